@@ -24,12 +24,56 @@ type WelcomeMessagePayload struct {
 type PromptRunPlaybookFromKeywordsPayload struct {
 	Keywords   []string `json:"keywords" mapstructure:"keywords"`
 	PlaybookID string   `json:"playbook_id" mapstructure:"playbook_id"`
+	// AutoStart starts a run from the matching post instead of suggesting the playbook.
+	AutoStart           bool `json:"auto_start,omitempty" mapstructure:"auto_start"`
+	RunAutoStartOptions `mapstructure:",squash"`
 }
 
 type CategorizeChannelPayload struct {
 	CategoryName string `json:"category_name" mapstructure:"category_name"`
 }
 
+// ChannelActionTriggerFilter narrows down when the run actions fire: Keywords for keywords,
+// UserIDs for message_from_user and EmojiNames for reaction_added.
+type ChannelActionTriggerFilter struct {
+	Keywords   []string `json:"keywords,omitempty" mapstructure:"keywords"`
+	UserIDs    []string `json:"user_ids,omitempty" mapstructure:"user_ids"`
+	EmojiNames []string `json:"emoji_names,omitempty" mapstructure:"emoji_names"`
+}
+
+// RunAutoStartOptions configures the runs that channel actions start without a prompt: the
+// cooldown during which a channel doesn't start another run of the playbook, and the property
+// values extracted from the triggering post. Runs started by members joining or leaving the
+// channel always wait at least a minute before the next one.
+type RunAutoStartOptions struct {
+	CooldownSeconds    int64               `json:"cooldown_seconds,omitempty" mapstructure:"cooldown_seconds"`
+	PropertyExtractors []PropertyExtractor `json:"property_extractors,omitempty" mapstructure:"property_extractors"`
+}
+
+// PropertyExtractor sets the playbook property field FieldID from the first match of Pattern in
+// the post, using its first capturing group if any.
+type PropertyExtractor struct {
+	FieldID string `json:"field_id" mapstructure:"field_id"`
+	Pattern string `json:"pattern" mapstructure:"pattern"`
+}
+
+type StartRunPayload struct {
+	ChannelActionTriggerFilter `mapstructure:",squash"`
+	PlaybookID                 string `json:"playbook_id" mapstructure:"playbook_id"`
+	RunAutoStartOptions        `mapstructure:",squash"`
+}
+
+type AddToRunTimelinePayload struct {
+	ChannelActionTriggerFilter `mapstructure:",squash"`
+	Summary                    string `json:"summary" mapstructure:"summary"`
+}
+
+type SetRunPropertyPayload struct {
+	ChannelActionTriggerFilter `mapstructure:",squash"`
+	FieldID                    string      `json:"field_id" mapstructure:"field_id"`
+	Value                      interface{} `json:"value" mapstructure:"value"`
+}
+
 type WelcomeMessageAction struct {
 	GenericChannelActionWithoutPayload
 	Payload WelcomeMessagePayload `json:"payload"`
@@ -40,10 +84,18 @@ const (
 	ActionTypeWelcomeMessage    = "send_welcome_message"
 	ActionTypePromptRunPlaybook = "prompt_run_playbook"
 	ActionTypeCategorizeChannel = "categorize_channel"
+	ActionTypeStartRun          = "start_run"
+	ActionTypeAddToRunTimeline  = "add_to_run_timeline"
+	ActionTypeSetRunProperty    = "set_run_property"
 
 	// Trigger types
-	TriggerTypeNewMemberJoins = "new_member_joins"
-	TriggerTypeKeywordsPosted = "keywords"
+	TriggerTypeNewMemberJoins       = "new_member_joins"
+	TriggerTypeKeywordsPosted       = "keywords"
+	TriggerTypeMemberLeaves         = "member_leaves"
+	TriggerTypeChannelHeaderChanged = "channel_header_changed"
+	TriggerTypeFileAttachment       = "file_attachment_posted"
+	TriggerTypeMessageFromUser      = "message_from_user"
+	TriggerTypeReactionAdded        = "reaction_added"
 )
 
 // ChannelActionListOptions specifies the optional parameters to the
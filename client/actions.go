@@ -76,3 +76,24 @@ func (s *ActionsService) Update(ctx context.Context, action GenericChannelAction
 
 	return nil
 }
+
+// CheckAndSendMessageOnJoin sends the welcome message of a channel to the current user, unless
+// they already saw it. It returns whether they had seen it before.
+func (s *ActionsService) CheckAndSendMessageOnJoin(ctx context.Context, channelID string) (bool, error) {
+	checkURL := fmt.Sprintf("actions/channels/%s/check-and-send-message-on-join", channelID)
+	req, err := s.client.newAPIRequest(http.MethodGet, checkURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var result struct {
+		Viewed bool `json:"viewed"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return result.Viewed, nil
+}
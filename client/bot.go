@@ -33,3 +33,23 @@ func (s *BotService) Connect(ctx context.Context) error {
 
 	return fmt.Errorf("unable to connect the bot: %d", resp.StatusCode)
 }
+
+// NotifyAdmins asks the system admins, through the Playbooks bot, to upgrade for the feature
+// described by messageType.
+func (s *BotService) NotifyAdmins(ctx context.Context, messageType string) error {
+	body := struct {
+		MessageType string `json:"message_type"`
+	}{messageType}
+	req, err := s.client.newAPIRequest(http.MethodPost, "bot/notify-admins", body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
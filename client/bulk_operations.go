@@ -0,0 +1,118 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Types of checklist item operations.
+const (
+	ChecklistItemOperationSetState    = "set_state"
+	ChecklistItemOperationSetAssignee = "set_assignee"
+	ChecklistItemOperationSetDueDate  = "set_due_date"
+	ChecklistItemOperationMove        = "move"
+)
+
+// Types of operations applied to many runs at once.
+const (
+	RunOperationChangeOwner     = "change_owner"
+	RunOperationAddParticipants = "add_participants"
+	RunOperationFinish          = "finish"
+)
+
+// ChecklistItemOperation is a change to a checklist item of a run, addressed by the item's ID.
+type ChecklistItemOperation struct {
+	Type   string `json:"type"`
+	ItemID string `json:"item_id"`
+
+	// State is the new state of the item for set_state.
+	State string `json:"state,omitempty"`
+
+	// AssigneeID is the user assigned to the item for set_assignee. Empty unassigns the item.
+	AssigneeID string `json:"assignee_id,omitempty"`
+
+	// DueDate is the new due date of the item for set_due_date, 0 to clear it.
+	DueDate int64 `json:"due_date,omitempty"`
+
+	// ChecklistID is the checklist the item is moved to for move, its current checklist when
+	// empty.
+	ChecklistID string `json:"checklist_id,omitempty"`
+
+	// Position is the index the item is moved to within the destination checklist for move. The
+	// item is moved to the end of the checklist when nil.
+	Position *int `json:"position,omitempty"`
+}
+
+// RunsOperation is an operation applied to many runs at once.
+type RunsOperation struct {
+	Type   string   `json:"type"`
+	RunIDs []string `json:"run_ids"`
+
+	// OwnerID is the new owner of the runs for change_owner.
+	OwnerID string `json:"owner_id,omitempty"`
+
+	// UserIDs are the users added to the runs for add_participants.
+	UserIDs           []string `json:"user_ids,omitempty"`
+	ForceAddToChannel bool     `json:"force_add_to_channel,omitempty"`
+
+	// ValidateFirst checks the operation against every run before applying it, rejecting the whole
+	// request when it can't be applied to one of them. The runs are still updated one at a time,
+	// so the results tell which runs it failed on if one changes in between.
+	ValidateFirst bool `json:"validate_first,omitempty"`
+}
+
+// BulkOperationResult is the outcome of an operation of a bulk request.
+type BulkOperationResult struct {
+	// Index is the position of the operation in the request.
+	Index int `json:"index"`
+
+	// ID is the checklist item or the run the operation addressed.
+	ID string `json:"id"`
+
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type bulkOperationResults struct {
+	Results []BulkOperationResult `json:"results"`
+}
+
+// ApplyChecklistItemOperations applies ops in order to the checklist items of a run, returning the
+// outcome of each. When atomic is true, the operations are applied together in a single update of
+// the run: nothing is applied if any operation is invalid or the run keeps being modified
+// concurrently, and the returned *ErrorResponse gives the reason in Details.
+func (s *PlaybookRunService) ApplyChecklistItemOperations(ctx context.Context, playbookRunID string, ops []ChecklistItemOperation, atomic bool) ([]BulkOperationResult, error) {
+	bulkURL := fmt.Sprintf("runs/%s/checklists/items/bulk", playbookRunID)
+	body := struct {
+		Operations []ChecklistItemOperation `json:"operations"`
+		Atomic     bool                     `json:"atomic"`
+	}{Operations: ops, Atomic: atomic}
+
+	return s.applyBulkOperation(ctx, bulkURL, body)
+}
+
+// ApplyRunsOperation changes the owner of, adds participants to or finishes many runs at once,
+// returning the outcome for each run.
+func (s *PlaybookRunService) ApplyRunsOperation(ctx context.Context, op RunsOperation) ([]BulkOperationResult, error) {
+	return s.applyBulkOperation(ctx, "runs/bulk", op)
+}
+
+func (s *PlaybookRunService) applyBulkOperation(ctx context.Context, bulkURL string, body interface{}) ([]BulkOperationResult, error) {
+	req, err := s.client.newAPIRequest(http.MethodPost, bulkURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result bulkOperationResults
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result.Results, nil
+}
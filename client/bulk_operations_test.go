@@ -0,0 +1,103 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyChecklistItemOperations(t *testing.T) {
+	t.Run("results", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs/run_id/checklists/items/bulk", r.URL.Path)
+
+			var body struct {
+				Operations []ChecklistItemOperation `json:"operations"`
+				Atomic     bool                     `json:"atomic"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.False(t, body.Atomic)
+			require.Len(t, body.Operations, 2)
+			assert.Equal(t, ChecklistItemOperationSetState, body.Operations[0].Type)
+			require.NotNil(t, body.Operations[1].Position)
+			assert.Equal(t, 0, *body.Operations[1].Position)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"results":[{"index":0,"id":"item_1","success":true},{"index":1,"id":"item_2","success":false,"error":"checklist \"missing\" not found"}]}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		position := 0
+		results, err := c.PlaybookRuns.ApplyChecklistItemOperations(context.Background(), "run_id", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationSetState, ItemID: "item_1", State: "closed"},
+			{Type: ChecklistItemOperationMove, ItemID: "item_2", ChecklistID: "missing", Position: &position},
+		}, false)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Success)
+		assert.False(t, results[1].Success)
+		assert.Equal(t, `checklist "missing" not found`, results[1].Error)
+	})
+
+	t.Run("atomic rejection", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error":"no operation was applied","details":"operation 1: invalid state \"done\""}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		_, err = c.PlaybookRuns.ApplyChecklistItemOperations(context.Background(), "run_id", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationSetState, ItemID: "item_1", State: "done"},
+		}, true)
+		var errResponse *ErrorResponse
+		require.ErrorAs(t, err, &errResponse)
+		assert.Equal(t, http.StatusBadRequest, errResponse.StatusCode)
+		assert.Equal(t, `operation 1: invalid state "done"`, errResponse.Details)
+	})
+}
+
+func TestApplyRunsOperation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/plugins/playbooks/api/v0/runs/bulk", r.URL.Path)
+
+		var op RunsOperation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&op))
+		assert.Equal(t, RunsOperation{Type: RunOperationChangeOwner, RunIDs: []string{"run_1", "run_2"}, OwnerID: "owner_id"}, op)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"results":[{"index":0,"id":"run_1","success":true},{"index":1,"id":"run_2","success":false,"error":"Not authorized"}]}`)
+	}))
+	defer ts.Close()
+
+	c, err := newClient(ts.URL, ts.Client())
+	require.NoError(t, err)
+
+	results, err := c.PlaybookRuns.ApplyRunsOperation(context.Background(), RunsOperation{
+		Type:    RunOperationChangeOwner,
+		RunIDs:  []string{"run_1", "run_2"},
+		OwnerID: "owner_id",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []BulkOperationResult{
+		{Index: 0, ID: "run_1", Success: true},
+		{Index: 1, ID: "run_2", Error: "Not authorized"},
+	}, results)
+}
@@ -9,10 +9,41 @@ import (
 	"net/http"
 )
 
+// CategoriesService handles communication with the sidebar category related
+// methods of the Playbooks API.
 type CategoriesService struct {
 	client *Client
 }
 
+// CategoryItemType is the type of an item of a sidebar category.
+type CategoryItemType string
+
+const (
+	PlaybookItemType CategoryItemType = "p"
+	RunItemType      CategoryItemType = "r"
+)
+
+// CategoryItem is a playbook or run in a sidebar category.
+type CategoryItem struct {
+	ItemID string           `json:"item_id"`
+	Type   CategoryItemType `json:"type"`
+	Name   string           `json:"name"`
+	Public bool             `json:"public"`
+}
+
+// Category is a sidebar category of the current user.
+type Category struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	TeamID    string         `json:"team_id"`
+	UserID    string         `json:"user_id"`
+	Collapsed bool           `json:"collapsed"`
+	CreateAt  int64          `json:"create_at"`
+	UpdateAt  int64          `json:"update_at"`
+	DeleteAt  int64          `json:"delete_at"`
+	Items     []CategoryItem `json:"items"`
+}
+
 // CategoriesIsFavoriteOptions specifies the optional parameters to the
 // CategoriesService.IsFavorite method
 type CategoriesIsFavoriteOptions struct {
@@ -46,3 +77,124 @@ func (s *CategoriesService) IsFavorite(ctx context.Context, opts CategoriesIsFav
 
 	return false, fmt.Errorf("unable to get favorite status: %d", resp.StatusCode)
 }
+
+// List the sidebar categories of the current user in a team, followed by the runs and playbooks
+// categories.
+func (s *CategoriesService) List(ctx context.Context, teamID string) ([]Category, error) {
+	listURL, err := addOption("my_categories", "team_id", teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newAPIRequest(http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	categories := []Category{}
+	resp, err := s.client.do(ctx, req, &categories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return categories, nil
+}
+
+// Create a sidebar category. Its UserID must be the current user.
+func (s *CategoriesService) Create(ctx context.Context, category Category) (*Category, error) {
+	req, err := s.client.newAPIRequest(http.MethodPost, "my_categories", category)
+	if err != nil {
+		return nil, err
+	}
+
+	created := new(Category)
+	resp, err := s.client.do(ctx, req, created)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return created, nil
+}
+
+// Update a sidebar category of the current user.
+func (s *CategoriesService) Update(ctx context.Context, category Category) error {
+	updateURL := fmt.Sprintf("my_categories/%s", category.ID)
+	req, err := s.client.newAPIRequest(http.MethodPut, updateURL, category)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Delete a sidebar category of the current user.
+func (s *CategoriesService) Delete(ctx context.Context, categoryID string) error {
+	deleteURL := fmt.Sprintf("my_categories/%s", categoryID)
+	req, err := s.client.newAPIRequest(http.MethodDelete, deleteURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Collapse collapses or expands a sidebar category of the current user.
+func (s *CategoriesService) Collapse(ctx context.Context, categoryID string, collapsed bool) error {
+	collapseURL := fmt.Sprintf("my_categories/%s/collapse", categoryID)
+	req, err := s.client.newAPIRequest(http.MethodPut, collapseURL, collapsed)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// SetFavorite adds a playbook or run to the favorites of the current user, or removes it. Setting
+// an item to what it already is, favorite or not, is not an error.
+func (s *CategoriesService) SetFavorite(ctx context.Context, itemType CategoryItemType, itemID string, favorite bool) error {
+	if itemType != PlaybookItemType && itemType != RunItemType {
+		return fmt.Errorf("unknown category item type %q", itemType)
+	}
+
+	favoriteURL, err := addOptions("my_categories/favorites", CategoriesIsFavoriteOptions{ItemId: itemID, ItemType: string(itemType)})
+	if err != nil {
+		return err
+	}
+
+	method := http.MethodPut
+	if !favorite {
+		method = http.MethodDelete
+	}
+	req, err := s.client.newAPIRequest(method, favoriteURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/my_categories", r.URL.Path)
+			assert.Equal(t, "team_id", r.URL.Query().Get("team_id"))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `[{"id":"category_id","name":"Favorite","items":[{"item_id":"run_id","type":"r","name":"Run"}]}]`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		categories, err := c.Categories.List(context.Background(), "team_id")
+		require.NoError(t, err)
+		require.Len(t, categories, 1)
+		assert.Equal(t, "Favorite", categories[0].Name)
+		assert.Equal(t, []CategoryItem{{ItemID: "run_id", Type: RunItemType, Name: "Run"}}, categories[0].Items)
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/my_categories/category_id/collapse", r.URL.Path)
+
+			var collapsed bool
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&collapsed))
+			assert.True(t, collapsed)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.Categories.Collapse(context.Background(), "category_id", true)
+		require.NoError(t, err)
+	})
+
+	t.Run("set run favorite", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/my_categories/favorites", r.URL.Path)
+			assert.Equal(t, "run_id", r.URL.Query().Get("item_id"))
+			assert.Equal(t, "r", r.URL.Query().Get("type"))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.Categories.SetFavorite(context.Background(), RunItemType, "run_id", true)
+		require.NoError(t, err)
+	})
+
+	t.Run("unset playbook favorite", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/my_categories/favorites", r.URL.Path)
+			assert.Equal(t, "playbook_id", r.URL.Query().Get("item_id"))
+			assert.Equal(t, "p", r.URL.Query().Get("type"))
+
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error":"Not authorized"}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.Categories.SetFavorite(context.Background(), PlaybookItemType, "playbook_id", false)
+		var errResp *ErrorResponse
+		require.ErrorAs(t, err, &errResp)
+		assert.Equal(t, http.StatusForbidden, errResp.StatusCode)
+	})
+
+	t.Run("set favorite of an unknown item type", func(t *testing.T) {
+		c, err := newClient("http://localhost", http.DefaultClient)
+		require.NoError(t, err)
+
+		err = c.Categories.SetFavorite(context.Background(), CategoryItemType("x"), "item_id", true)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ChecklistItemRef addresses a checklist item of a run by ID, which unlike its position is not
+// affected by other items being added, removed or moved.
+type ChecklistItemRef struct {
+	ItemID string
+
+	// UpdateAt is the UpdateAt of the version of the item the change is based on. When set, the
+	// change fails with a 409 *ErrorResponse if the item was modified since. Any change also fails
+	// with a 409 if the run is modified while it is being made.
+	UpdateAt int64
+}
+
+// Ref addresses this version of the item, so changes made through it fail once the item is
+// modified by someone else.
+func (item ChecklistItem) Ref() ChecklistItemRef {
+	return ChecklistItemRef{ItemID: item.ID, UpdateAt: item.UpdateAt}
+}
+
+// SetItemStateByID sets the state of a checklist item: "", "in_progress", "closed" or "skipped".
+func (s *PlaybookRunService) SetItemStateByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, newState string) error {
+	body := struct {
+		NewState string `json:"new_state"`
+	}{newState}
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/state", body)
+}
+
+// SetItemAssigneeByID assigns a checklist item to a user, or unassigns it when assigneeID is empty.
+func (s *PlaybookRunService) SetItemAssigneeByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, assigneeID string) error {
+	body := struct {
+		AssigneeID string `json:"assignee_id"`
+	}{assigneeID}
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/assignee", body)
+}
+
+// SetItemDueDateByID sets the due date of a checklist item, or clears it when duedate is 0.
+func (s *PlaybookRunService) SetItemDueDateByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, duedate int64) error {
+	body := struct {
+		DueDate int64 `json:"due_date"`
+	}{duedate}
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/duedate", body)
+}
+
+// SetItemCommandByID sets the slash command of a checklist item.
+func (s *PlaybookRunService) SetItemCommandByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, newCommand string) error {
+	body := struct {
+		Command string `json:"command"`
+	}{newCommand}
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/command", body)
+}
+
+// EditItemByID changes the title, command and description of a checklist item.
+func (s *PlaybookRunService) EditItemByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, title, command, description string) error {
+	body := struct {
+		Title       string `json:"title"`
+		Command     string `json:"command"`
+		Description string `json:"description"`
+	}{title, command, description}
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "", body)
+}
+
+// SetItemTaskActionsByID replaces the task actions of a checklist item.
+func (s *PlaybookRunService) SetItemTaskActionsByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, taskActions []TaskAction) error {
+	body := struct {
+		TaskActions []TaskAction `json:"task_actions"`
+	}{taskActions}
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/task-actions", body)
+}
+
+// RunItemCommandByID runs the slash command of a checklist item.
+func (s *PlaybookRunService) RunItemCommandByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef) error {
+	return s.checklistItemRequest(ctx, http.MethodPost, playbookRunID, ref, "/run", nil)
+}
+
+// SkipItemByID skips a checklist item.
+func (s *PlaybookRunService) SkipItemByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef) error {
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/skip", nil)
+}
+
+// RestoreItemByID restores a skipped checklist item.
+func (s *PlaybookRunService) RestoreItemByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef) error {
+	return s.checklistItemRequest(ctx, http.MethodPut, playbookRunID, ref, "/restore", nil)
+}
+
+// DuplicateItemByID adds a copy of a checklist item right after it.
+func (s *PlaybookRunService) DuplicateItemByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef) error {
+	return s.checklistItemRequest(ctx, http.MethodPost, playbookRunID, ref, "/duplicate", nil)
+}
+
+// RemoveItemByID removes a checklist item.
+func (s *PlaybookRunService) RemoveItemByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef) error {
+	return s.checklistItemRequest(ctx, http.MethodDelete, playbookRunID, ref, "", nil)
+}
+
+// MoveItemByID moves a checklist item to position in the checklist with ID checklistID, or in its
+// own checklist when checklistID is empty. The item is moved to the end of the checklist when
+// position is nil.
+func (s *PlaybookRunService) MoveItemByID(ctx context.Context, playbookRunID string, ref ChecklistItemRef, checklistID string, position *int) error {
+	body := struct {
+		ChecklistID string `json:"checklist_id,omitempty"`
+		Position    *int   `json:"position,omitempty"`
+	}{checklistID, position}
+	return s.checklistItemRequest(ctx, http.MethodPost, playbookRunID, ref, "/move", body)
+}
+
+func (s *PlaybookRunService) checklistItemRequest(ctx context.Context, method, playbookRunID string, ref ChecklistItemRef, action string, body interface{}) error {
+	itemURL := fmt.Sprintf("runs/%s/checklist-items/%s%s", playbookRunID, ref.ItemID, action)
+	if ref.UpdateAt != 0 {
+		var err error
+		itemURL, err = addOption(itemURL, "update_at", strconv.FormatInt(ref.UpdateAt, 10))
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := s.client.newAPIRequest(method, itemURL, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
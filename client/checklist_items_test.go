@@ -0,0 +1,78 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecklistItemsByID(t *testing.T) {
+	t.Run("set state of the latest version", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs/run_id/checklist-items/item_id/state", r.URL.Path)
+			assert.Equal(t, "1234", r.URL.Query().Get("update_at"))
+
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]string{"new_state": "closed"}, body)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.PlaybookRuns.SetItemStateByID(context.Background(), "run_id", ChecklistItemRef{ItemID: "item_id", UpdateAt: 1234}, "closed")
+		require.NoError(t, err)
+	})
+
+	t.Run("move without a version", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs/run_id/checklist-items/item_id/move", r.URL.Path)
+			assert.Empty(t, r.URL.RawQuery)
+
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]interface{}{"checklist_id": "checklist_id", "position": float64(0)}, body)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		position := 0
+		err = c.PlaybookRuns.MoveItemByID(context.Background(), "run_id", ChecklistItemRef{ItemID: "item_id"}, "checklist_id", &position)
+		require.NoError(t, err)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprint(w, `{"error":"checklist item was modified","item":{"id":"item_id","update_at":2000}}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.PlaybookRuns.RemoveItemByID(context.Background(), "run_id", ChecklistItemRef{ItemID: "item_id", UpdateAt: 1000})
+		var errResponse *ErrorResponse
+		require.ErrorAs(t, err, &errResponse)
+		assert.Equal(t, http.StatusConflict, errResponse.StatusCode)
+	})
+}
@@ -217,6 +217,30 @@ func (c *Client) DoGraphql(ctx context.Context, input *GraphQLInput, v interface
 	return nil
 }
 
+// graphqlMutation runs a GraphQL mutation whose result is not needed, failing with the first
+// error reported by the GraphQL API, which responds with 200 OK even when the mutation fails.
+func (c *Client) graphqlMutation(ctx context.Context, operationName, query string, variables map[string]interface{}) error {
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err := c.DoGraphql(ctx, &GraphQLInput{
+		Query:         query,
+		OperationName: operationName,
+		Variables:     variables,
+	}, &result)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%s failed: %s", operationName, result.Errors[0].Message)
+	}
+
+	return nil
+}
+
 // checkResponse checks the API response for an error.
 //
 // Any response with a status code outside 2xx is considered an error, and its body inspected for
@@ -241,9 +265,31 @@ func checkResponse(r *http.Response) error {
 		_ = json.Unmarshal(data, errorResponse)
 	}
 
+	if r.StatusCode == http.StatusPreconditionFailed {
+		var current struct {
+			PlaybookRun *PlaybookRun `json:"playbook_run"`
+			Playbook    *Playbook    `json:"playbook"`
+		}
+		_ = json.Unmarshal(data, &current)
+
+		return &StaleVersionError{
+			ErrorResponse: errorResponse,
+			PlaybookRun:   current.PlaybookRun,
+			Playbook:      current.Playbook,
+		}
+	}
+
 	return errorResponse
 }
 
+// setIfMatch makes req conditional on the run or playbook it updates having been last updated at
+// updateAt. It leaves req unconditional when updateAt is 0.
+func setIfMatch(req *http.Request, updateAt int64) {
+	if updateAt != 0 {
+		req.Header.Set("If-Match", strconv.Quote(strconv.FormatInt(updateAt, 10)))
+	}
+}
+
 // addOption adds the given parameter as an URL query parameters to s.
 func addOption(s string, name, value string) (string, error) {
 	u, err := url.Parse(s)
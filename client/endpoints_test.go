@@ -0,0 +1,352 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEndpoints checks the request each method sends, for the methods whose response carries
+// nothing beyond the status code.
+func TestEndpoints(t *testing.T) {
+	ctx := context.Background()
+	prefix := "plugins/playbooks/api/v0/"
+	runNumberPrefix := "INC"
+
+	testCases := []struct {
+		name         string
+		call         func(c *Client) error
+		expectedVerb string
+		expectedPath string
+		expectedBody string
+	}{
+		{
+			name:         "remove timeline event",
+			call:         func(c *Client) error { return c.PlaybookRuns.RemoveTimelineEvent(ctx, "run_id", "event_id") },
+			expectedVerb: http.MethodDelete,
+			expectedPath: "runs/run_id/timeline/event_id",
+		},
+		{
+			name:         "follow",
+			call:         func(c *Client) error { return c.PlaybookRuns.Follow(ctx, "run_id") },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/followers",
+		},
+		{
+			name:         "unfollow",
+			call:         func(c *Client) error { return c.PlaybookRuns.Unfollow(ctx, "run_id") },
+			expectedVerb: http.MethodDelete,
+			expectedPath: "runs/run_id/followers",
+		},
+		{
+			name:         "cancel retrospective",
+			call:         func(c *Client) error { return c.PlaybookRuns.CancelRetrospective(ctx, "run_id") },
+			expectedVerb: http.MethodPost,
+			expectedPath: "runs/run_id/no-retrospective-button",
+		},
+		{
+			name:         "disable status updates",
+			call:         func(c *Client) error { return c.PlaybookRuns.SetStatusUpdatesEnabled(ctx, "run_id", false) },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/status-update-enabled",
+			expectedBody: `{"status_enabled":false}`,
+		},
+		{
+			name:         "enable retrospective",
+			call:         func(c *Client) error { return c.PlaybookRuns.SetRetrospectiveEnabled(ctx, "run_id", true) },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/retrospective-enabled",
+			expectedBody: `{"retrospective_enabled":true}`,
+		},
+		{
+			name:         "request to join channel",
+			call:         func(c *Client) error { return c.PlaybookRuns.RequestJoinChannel(ctx, "run_id") },
+			expectedVerb: http.MethodPost,
+			expectedPath: "runs/run_id/request-join-channel",
+		},
+		{
+			name:         "skip checklist",
+			call:         func(c *Client) error { return c.PlaybookRuns.SkipChecklist(ctx, "run_id", 1) },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/skip",
+		},
+		{
+			name:         "restore checklist",
+			call:         func(c *Client) error { return c.PlaybookRuns.RestoreChecklist(ctx, "run_id", 1) },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/restore",
+		},
+		{
+			name:         "duplicate checklist",
+			call:         func(c *Client) error { return c.PlaybookRuns.DuplicateChecklist(ctx, "run_id", 1) },
+			expectedVerb: http.MethodPost,
+			expectedPath: "runs/run_id/checklists/1/duplicate",
+		},
+		{
+			name:         "set item state",
+			call:         func(c *Client) error { return c.PlaybookRuns.SetItemState(ctx, "run_id", 1, 2, "closed") },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/item/2/state",
+			expectedBody: `{"new_state":"closed"}`,
+		},
+		{
+			name: "edit item",
+			call: func(c *Client) error {
+				return c.PlaybookRuns.EditItem(ctx, "run_id", 1, 2, "title", "/echo", "description")
+			},
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/item/2",
+			expectedBody: `{"title":"title","command":"/echo","description":"description"}`,
+		},
+		{
+			name:         "remove item",
+			call:         func(c *Client) error { return c.PlaybookRuns.RemoveItem(ctx, "run_id", 1, 2) },
+			expectedVerb: http.MethodDelete,
+			expectedPath: "runs/run_id/checklists/1/item/2",
+		},
+		{
+			name:         "skip item",
+			call:         func(c *Client) error { return c.PlaybookRuns.SkipItem(ctx, "run_id", 1, 2) },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/item/2/skip",
+		},
+		{
+			name:         "restore item",
+			call:         func(c *Client) error { return c.PlaybookRuns.RestoreItem(ctx, "run_id", 1, 2) },
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/item/2/restore",
+		},
+		{
+			name:         "duplicate item",
+			call:         func(c *Client) error { return c.PlaybookRuns.DuplicateItem(ctx, "run_id", 1, 2) },
+			expectedVerb: http.MethodPost,
+			expectedPath: "runs/run_id/checklists/1/item/2/duplicate",
+		},
+		{
+			name:         "restore playbook",
+			call:         func(c *Client) error { return c.Playbooks.Restore(ctx, "playbook_id") },
+			expectedVerb: http.MethodPut,
+			expectedPath: "playbooks/playbook_id/restore",
+		},
+		{
+			name: "patch playbook",
+			call: func(c *Client) error {
+				return c.Playbooks.Patch(ctx, "playbook_id", PlaybookPatch{RunNumberPrefix: &runNumberPrefix})
+			},
+			expectedVerb: http.MethodPatch,
+			expectedPath: "playbooks/playbook_id",
+			expectedBody: `{"run_number_prefix":"INC"}`,
+		},
+		{
+			name: "patch playbook checklists",
+			call: func(c *Client) error {
+				title := "title"
+				checklists := []PlaybookChecklistUpdate{Checklist{
+					ID:    "checklist_id",
+					Title: "checklist",
+					Items: []ChecklistItem{{ID: "item_id", Title: "item", UpdateAt: 1}},
+				}.AsUpdate()}
+				return c.Playbooks.Patch(ctx, "playbook_id", PlaybookPatch{Title: &title, Checklists: &checklists})
+			},
+			expectedVerb: http.MethodPatch,
+			expectedPath: "playbooks/playbook_id",
+			expectedBody: `{"title":"title","checklists":[{"title":"checklist","items":[{"title":"item","state":"","state_modified":0,"assignee_id":"","assignee_modified":0,"command":"","command_last_run":0,"description":"","delete_at":0,"due_date":0,"task_actions":null,"condition_id":""}]}]}`,
+		},
+		{
+			name:         "add playbook member",
+			call:         func(c *Client) error { return c.Playbooks.AddMember(ctx, "playbook_id", "user_id") },
+			expectedVerb: http.MethodPut,
+			expectedPath: "playbooks/playbook_id/members/user_id",
+		},
+		{
+			name:         "remove playbook member",
+			call:         func(c *Client) error { return c.Playbooks.RemoveMember(ctx, "playbook_id", "user_id") },
+			expectedVerb: http.MethodDelete,
+			expectedPath: "playbooks/playbook_id/members/user_id",
+		},
+		{
+			name:         "delete metric",
+			call:         func(c *Client) error { return c.Playbooks.DeleteMetric(ctx, "playbook_id", "metric_id") },
+			expectedVerb: http.MethodDelete,
+			expectedPath: "playbooks/playbook_id/metrics/metric_id",
+		},
+		{
+			name: "set item task actions",
+			call: func(c *Client) error {
+				return c.PlaybookRuns.SetItemTaskActions(ctx, "run_id", 1, 2, []TaskAction{})
+			},
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklists/1/item/2/task-actions",
+			expectedBody: `{"task_actions":[]}`,
+		},
+		{
+			name: "set item task actions by ID",
+			call: func(c *Client) error {
+				return c.PlaybookRuns.SetItemTaskActionsByID(ctx, "run_id", ChecklistItemRef{ItemID: "item_id"}, nil)
+			},
+			expectedVerb: http.MethodPut,
+			expectedPath: "runs/run_id/checklist-items/item_id/task-actions",
+			expectedBody: `{"task_actions":null}`,
+		},
+		{
+			name: "create property field",
+			call: func(c *Client) error {
+				sortOrder := 2.0
+				parentID := "parent_id"
+				_, err := c.Playbooks.CreatePropertyField(ctx, "playbook_id", PropertyFieldRequest{
+					Name:  "name",
+					Type:  "text",
+					Attrs: &PropertyFieldAttrsInput{SortOrder: &sortOrder, ParentID: &parentID},
+				})
+				return err
+			},
+			expectedVerb: http.MethodPost,
+			expectedPath: "playbooks/playbook_id/property_fields",
+			expectedBody: `{"name":"name","type":"text","attrs":{"sort_order":2,"parent_id":"parent_id"}}`,
+		},
+		{
+			name: "update category",
+			call: func(c *Client) error {
+				return c.Categories.Update(ctx, Category{ID: "category_id", Name: "name", UserID: "user_id"})
+			},
+			expectedVerb: http.MethodPut,
+			expectedPath: "my_categories/category_id",
+			expectedBody: `{"id":"category_id","name":"name","team_id":"","user_id":"user_id","collapsed":false,"create_at":0,"update_at":0,"delete_at":0,"items":null}`,
+		},
+		{
+			name:         "delete category",
+			call:         func(c *Client) error { return c.Categories.Delete(ctx, "category_id") },
+			expectedVerb: http.MethodDelete,
+			expectedPath: "my_categories/category_id",
+		},
+		{
+			name:         "notify admins",
+			call:         func(c *Client) error { return c.Bot.NotifyAdmins(ctx, "start_trial_to_view_timeline") },
+			expectedVerb: http.MethodPost,
+			expectedPath: "bot/notify-admins",
+			expectedBody: `{"message_type":"start_trial_to_view_timeline"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedVerb, r.Method)
+				assert.Equal(t, "/"+prefix+tc.expectedPath, r.URL.Path)
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				if tc.expectedBody == "" {
+					assert.Empty(t, body)
+				} else {
+					assert.JSONEq(t, tc.expectedBody, string(body))
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer ts.Close()
+
+			c, err := newClient(ts.URL, ts.Client())
+			require.NoError(t, err)
+
+			require.NoError(t, tc.call(c))
+		})
+	}
+}
+
+func TestRunLookups(t *testing.T) {
+	ctx := context.Background()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+
+		var result interface{}
+		switch r.URL.Path {
+		case "/plugins/playbooks/api/v0/runs/run_id/followers":
+			result = []string{"user1", "user2"}
+		case "/plugins/playbooks/api/v0/runs/channel/channel_id/runs":
+			result = []map[string]string{{"id": "run_id"}}
+		case "/plugins/playbooks/api/v0/runs/channels":
+			assert.Equal(t, "team_id", r.URL.Query().Get("team_id"))
+			result = []string{"channel_id"}
+		case "/plugins/playbooks/api/v0/actions/channels/channel_id/check-and-send-message-on-join":
+			result = map[string]bool{"viewed": true}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(result))
+	}))
+	defer ts.Close()
+
+	c, err := newClient(ts.URL, ts.Client())
+	require.NoError(t, err)
+
+	followers, err := c.PlaybookRuns.GetFollowers(ctx, "run_id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, followers)
+
+	runs, err := c.PlaybookRuns.ListByChannel(ctx, "channel_id")
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "run_id", runs[0].ID)
+
+	channelIDs, err := c.PlaybookRuns.ListChannelIDs(ctx, PlaybookRunListOptions{TeamID: "team_id"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"channel_id"}, channelIDs)
+
+	viewed, err := c.Actions.CheckAndSendMessageOnJoin(ctx, "channel_id")
+	require.NoError(t, err)
+	assert.True(t, viewed)
+}
+
+func TestPlaybookMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			assert.Equal(t, "/plugins/playbooks/api/v0/playbooks/playbook_id/metrics", r.URL.Path)
+			assert.Equal(t, "Time to resolve", body["title"])
+			assert.Equal(t, MetricTypeDuration, body["type"])
+			assert.Nil(t, body["target"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"id":"metric_id","playbook_id":"playbook_id","title":"Time to resolve","description":"","type":"metric_duration","target":null}`)
+		case http.MethodPatch:
+			assert.Equal(t, "/plugins/playbooks/api/v0/playbooks/playbook_id/metrics/metric_id", r.URL.Path)
+			assert.Equal(t, map[string]interface{}{"target": float64(3600000)}, body)
+			_, _ = io.WriteString(w, `{"id":"metric_id","playbook_id":"playbook_id","title":"Time to resolve","description":"","type":"metric_duration","target":3600000}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := newClient(ts.URL, ts.Client())
+	require.NoError(t, err)
+
+	metric, err := c.Playbooks.AddMetric(ctx, "playbook_id", PlaybookMetricConfig{Title: "Time to resolve", Type: MetricTypeDuration})
+	require.NoError(t, err)
+	assert.Equal(t, "metric_id", metric.ID)
+	assert.False(t, metric.Target.Valid)
+
+	target := int64(3600000)
+	metric, err = c.Playbooks.UpdateMetric(ctx, "playbook_id", "metric_id", PlaybookMetricPatch{Target: &target})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600000), metric.Target.Int64)
+}
@@ -7,8 +7,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 )
 
+// Errors matched by an *ErrorResponse with the corresponding status code, so callers can check for
+// them with errors.Is instead of comparing ErrorResponse.StatusCode.
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:         ErrBadRequest,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrForbidden,
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusConflict:           ErrConflict,
+	http.StatusPreconditionFailed: ErrPreconditionFailed,
+}
+
 // ErrorResponse is an error from an API request.
 type ErrorResponse struct {
 	// Method is the HTTP verb used in the API request.
@@ -20,6 +41,14 @@ type ErrorResponse struct {
 
 	// Err is the error parsed from the API response.
 	Err error `json:"error"`
+
+	// MissingFields lists the required property fields without a value when a run could not be
+	// started or finished because of them.
+	MissingFields []MissingPropertyField `json:"missing_fields,omitempty"`
+
+	// Details gives the reason a request was rejected when the API provides one, such as the
+	// template error that prevented a run from being created.
+	Details string `json:"details,omitempty"`
 }
 
 func (e *ErrorResponse) UnmarshalJSON(data []byte) error {
@@ -46,7 +75,34 @@ func (e *ErrorResponse) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is the error for the status code of the response, such as ErrNotFound
+// for a 404.
+func (e *ErrorResponse) Is(target error) bool {
+	statusErr, ok := statusErrors[e.StatusCode]
+	return ok && statusErr == target
+}
+
 // Error describes the error from the API request.
 func (e *ErrorResponse) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s %s [%d]: %v: %s", e.Method, e.URL, e.StatusCode, e.Err, e.Details)
+	}
 	return fmt.Sprintf("%s %s [%d]: %v", e.Method, e.URL, e.StatusCode, e.Err)
 }
+
+// StaleVersionError is returned by a conditional update, such as
+// PlaybookRunService.UpdateIfUnmodified, rejected with 412 Precondition Failed because the run or
+// playbook was modified since the version the update was based on.
+type StaleVersionError struct {
+	*ErrorResponse
+
+	// PlaybookRun is the current version of the run, when a run was being updated.
+	PlaybookRun *PlaybookRun
+	// Playbook is the current version of the playbook, when a playbook was being updated.
+	Playbook *Playbook
+}
+
+// Unwrap exposes the ErrorResponse of a StaleVersionError.
+func (e *StaleVersionError) Unwrap() error {
+	return e.ErrorResponse
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponseIs(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		expected   error
+	}{
+		{statusCode: http.StatusBadRequest, expected: ErrBadRequest},
+		{statusCode: http.StatusUnauthorized, expected: ErrUnauthorized},
+		{statusCode: http.StatusForbidden, expected: ErrForbidden},
+		{statusCode: http.StatusNotFound, expected: ErrNotFound},
+		{statusCode: http.StatusConflict, expected: ErrConflict},
+		{statusCode: http.StatusPreconditionFailed, expected: ErrPreconditionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.statusCode), func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				_, _ = fmt.Fprint(w, `{"error":"failed"}`)
+			}))
+			defer ts.Close()
+
+			c, err := newClient(ts.URL, ts.Client())
+			require.NoError(t, err)
+
+			_, err = c.PlaybookRuns.Get(context.Background(), "run_id")
+			assert.ErrorIs(t, err, tc.expected)
+
+			var errResponse *ErrorResponse
+			require.ErrorAs(t, err, &errResponse)
+			assert.Equal(t, tc.statusCode, errResponse.StatusCode)
+			assert.EqualError(t, errResponse.Err, "failed")
+
+			for _, other := range testCases {
+				if other.statusCode != tc.statusCode {
+					assert.NotErrorIs(t, err, other.expected)
+				}
+			}
+		})
+	}
+
+	t.Run("unmapped status code", func(t *testing.T) {
+		err := &ErrorResponse{StatusCode: http.StatusInternalServerError}
+		assert.NotErrorIs(t, err, ErrBadRequest)
+	})
+}
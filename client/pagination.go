@@ -0,0 +1,32 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import "iter"
+
+// paginate iterates over the items of a paginated list, fetching each page as the previous one
+// is consumed. fetch returns the items of a page and whether there are more pages after it. The
+// iteration stops after yielding the first error returned by fetch.
+func paginate[T any](fetch func(page int) ([]T, bool, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := 0; ; page++ {
+			items, hasMore, err := fetch(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !hasMore || len(items) == 0 {
+				return
+			}
+		}
+	}
+}
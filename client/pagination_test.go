@@ -0,0 +1,98 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAll(t *testing.T) {
+	t.Run("runs across pages", func(t *testing.T) {
+		var pages []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs", r.URL.Path)
+			assert.Equal(t, "team_id", r.URL.Query().Get("team_id"))
+			assert.Equal(t, "2", r.URL.Query().Get("per_page"))
+			page := r.URL.Query().Get("page")
+			pages = append(pages, page)
+
+			w.Header().Set("Content-Type", "application/json")
+			switch page {
+			case "0":
+				_, _ = fmt.Fprint(w, `{"total_count":3,"has_more":true,"items":[{"id":"run1"},{"id":"run2"}]}`)
+			default:
+				_, _ = fmt.Fprint(w, `{"total_count":3,"has_more":false,"items":[{"id":"run3"}]}`)
+			}
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		var runIDs []string
+		for run, err := range c.PlaybookRuns.ListAll(context.Background(), 2, PlaybookRunListOptions{TeamID: "team_id"}) {
+			require.NoError(t, err)
+			runIDs = append(runIDs, run.ID)
+		}
+		assert.Equal(t, []string{"run1", "run2", "run3"}, runIDs)
+		assert.Equal(t, []string{"0", "1"}, pages)
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"has_more":true,"items":[{"id":"playbook1"},{"id":"playbook2"}]}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		for playbook, err := range c.Playbooks.ListAll(context.Background(), "team_id", 2, PlaybookListOptions{}) {
+			require.NoError(t, err)
+			assert.Equal(t, "playbook1", playbook.ID)
+			break
+		}
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("stop at the first error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs/run_id/conditions", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("page") == "0" {
+				_, _ = fmt.Fprint(w, `{"has_more":true,"items":[{"id":"condition1"}]}`)
+				return
+			}
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error":"no access"}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		var conditionIDs []string
+		var errs []error
+		for condition, err := range c.RunConditions.ListAll(context.Background(), "run_id", 1) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			conditionIDs = append(conditionIDs, condition.ID)
+		}
+		assert.Equal(t, []string{"condition1"}, conditionIDs)
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], ErrForbidden)
+	})
+}
@@ -4,6 +4,7 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"gopkg.in/guregu/null.v4"
@@ -18,6 +19,7 @@ type Playbook struct {
 	TeamID                                  string                 `json:"team_id"`
 	CreatePublicPlaybookRun                 bool                   `json:"create_public_playbook_run"`
 	CreateAt                                int64                  `json:"create_at"`
+	UpdateAt                                int64                  `json:"update_at"`
 	DeleteAt                                int64                  `json:"delete_at"`
 	NumStages                               int64                  `json:"num_stages"`
 	NumSteps                                int64                  `json:"num_steps"`
@@ -40,8 +42,12 @@ type Playbook struct {
 	ChannelID                               string                 `json:"channel_id" export:"channel_id"`
 	ChannelMode                             ChannelPlaybookMode    `json:"channel_mode" export:"channel_mode"`
 	RunNumberPrefix                         string                 `json:"run_number_prefix"`
+	ExportKey                               string                 `json:"export_key"`
+	Revision                                int64                  `json:"revision"`
+	PublishedRevision                       int64                  `json:"published_revision"`
 	AdminOnlyEdit                           bool                   `json:"admin_only_edit"`
 	OwnerGroupOnlyActions                   bool                   `json:"owner_group_only_actions"`
+	RunRoles                                []RunRole              `json:"run_roles"`
 	NewChannelOnly                          bool                   `json:"new_channel_only"`
 	AutoArchiveChannel                      bool                   `json:"auto_archive_channel"`
 }
@@ -52,6 +58,23 @@ type PlaybookMember struct {
 	SchemeRoles []string `json:"scheme_roles"`
 }
 
+// RunRole is a custom role, such as Incident Commander or Scribe, defined on a playbook and
+// assigned to a user in each of its runs.
+type RunRole struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	UserID      string   `json:"user_id"`
+}
+
+// Permissions a run role can grant.
+const (
+	RunRolePermissionPostStatusUpdates = "post_status_updates"
+	RunRolePermissionEditChecklists    = "edit_checklists"
+	RunRolePermissionFinishRun         = "finish_run"
+	RunRolePermissionManageProperties  = "manage_properties"
+)
+
 const (
 	MetricTypeDuration = "metric_duration"
 	MetricTypeCurrency = "metric_currency"
@@ -76,6 +99,7 @@ type ChecklistItem struct {
 	AssigneeModified        int64        `json:"assignee_modified"`
 	AssigneeType            string       `json:"assignee_type"`
 	AssigneePropertyFieldID string       `json:"assignee_property_field_id"`
+	AssigneeRoleID          string       `json:"assignee_role_id"`
 	Command                 string       `json:"command"`
 	CommandLastRun          int64        `json:"command_last_run"`
 	Description             string       `json:"description"`
@@ -127,6 +151,7 @@ type PlaybookCreateOptions struct {
 	RunNumberPrefix                         string                 `json:"run_number_prefix"`
 	AdminOnlyEdit                           bool                   `json:"admin_only_edit"`
 	OwnerGroupOnlyActions                   bool                   `json:"owner_group_only_actions"`
+	RunRoles                                []RunRole              `json:"run_roles"`
 	NewChannelOnly                          bool                   `json:"new_channel_only"`
 	AutoArchiveChannel                      bool                   `json:"auto_archive_channel"`
 }
@@ -156,6 +181,187 @@ type GetPlaybooksResults struct {
 	Items      []Playbook `json:"items"`
 }
 
+// PlaybookPatch specifies the fields of a playbook changed by PlaybooksService.Patch. Fields left
+// nil are not changed.
+type PlaybookPatch struct {
+	Title                                   *string                    `json:"title,omitempty"`
+	Description                             *string                    `json:"description,omitempty"`
+	Public                                  *bool                      `json:"public,omitempty"`
+	CreatePublicPlaybookRun                 *bool                      `json:"create_public_playbook_run,omitempty"`
+	ReminderMessageTemplate                 *string                    `json:"reminder_message_template,omitempty"`
+	ReminderTimerDefaultSeconds             *int64                     `json:"reminder_timer_default_seconds,omitempty"`
+	StatusUpdateEnabled                     *bool                      `json:"status_update_enabled,omitempty"`
+	InvitedUserIDs                          *[]string                  `json:"invited_user_ids,omitempty"`
+	InvitedGroupIDs                         *[]string                  `json:"invited_group_ids,omitempty"`
+	InviteUsersEnabled                      *bool                      `json:"invite_users_enabled,omitempty"`
+	DefaultOwnerID                          *string                    `json:"default_owner_id,omitempty"`
+	DefaultOwnerEnabled                     *bool                      `json:"default_owner_enabled,omitempty"`
+	BroadcastChannelIDs                     *[]string                  `json:"broadcast_channel_ids,omitempty"`
+	BroadcastEnabled                        *bool                      `json:"broadcast_enabled,omitempty"`
+	WebhookOnCreationURLs                   *[]string                  `json:"webhook_on_creation_urls,omitempty"`
+	WebhookOnCreationEnabled                *bool                      `json:"webhook_on_creation_enabled,omitempty"`
+	MessageOnJoin                           *string                    `json:"message_on_join,omitempty"`
+	MessageOnJoinEnabled                    *bool                      `json:"message_on_join_enabled,omitempty"`
+	RetrospectiveReminderIntervalSeconds    *int64                     `json:"retrospective_reminder_interval_seconds,omitempty"`
+	RetrospectiveTemplate                   *string                    `json:"retrospective_template,omitempty"`
+	RetrospectiveEnabled                    *bool                      `json:"retrospective_enabled,omitempty"`
+	WebhookOnStatusUpdateURLs               *[]string                  `json:"webhook_on_status_update_urls,omitempty"`
+	WebhookOnStatusUpdateEnabled            *bool                      `json:"webhook_on_status_update_enabled,omitempty"`
+	SignalAnyKeywords                       *[]string                  `json:"signal_any_keywords,omitempty"`
+	SignalAnyKeywordsEnabled                *bool                      `json:"signal_any_keywords_enabled,omitempty"`
+	CategorizeChannelEnabled                *bool                      `json:"categorize_channel_enabled,omitempty"`
+	CategoryName                            *string                    `json:"category_name,omitempty"`
+	RunSummaryTemplateEnabled               *bool                      `json:"run_summary_template_enabled,omitempty"`
+	RunSummaryTemplate                      *string                    `json:"run_summary_template,omitempty"`
+	RunNumberPrefix                         *string                    `json:"run_number_prefix,omitempty"`
+	ChannelNameTemplate                     *string                    `json:"channel_name_template,omitempty"`
+	Checklists                              *[]PlaybookChecklistUpdate `json:"checklists,omitempty"`
+	CreateChannelMemberOnNewParticipant     *bool                      `json:"create_channel_member_on_new_participant,omitempty"`
+	RemoveChannelMemberOnRemovedParticipant *bool                      `json:"remove_channel_member_on_removed_participant,omitempty"`
+	ChannelID                               *string                    `json:"channel_id,omitempty"`
+	ChannelMode                             *string                    `json:"channel_mode,omitempty"`
+}
+
+// PlaybookChecklistUpdate is a checklist as set by PlaybookPatch.Checklists.
+type PlaybookChecklistUpdate struct {
+	Title string                        `json:"title"`
+	Items []PlaybookChecklistItemUpdate `json:"items"`
+}
+
+// PlaybookChecklistItemUpdate is a checklist item as set by PlaybookPatch.Checklists.
+type PlaybookChecklistItemUpdate struct {
+	Title                   string       `json:"title"`
+	State                   string       `json:"state"`
+	StateModified           int64        `json:"state_modified"`
+	AssigneeID              string       `json:"assignee_id"`
+	AssigneeModified        int64        `json:"assignee_modified"`
+	AssigneeType            string       `json:"assignee_type,omitempty"`
+	AssigneePropertyFieldID string       `json:"assignee_property_field_id,omitempty"`
+	AssigneeRoleID          string       `json:"assignee_role_id,omitempty"`
+	Command                 string       `json:"command"`
+	CommandLastRun          int64        `json:"command_last_run"`
+	Description             string       `json:"description"`
+	LastSkipped             int64        `json:"delete_at"`
+	DueDate                 int64        `json:"due_date"`
+	TaskActions             []TaskAction `json:"task_actions"`
+	ConditionID             string       `json:"condition_id"`
+}
+
+// AsUpdate returns the checklist in the form set by PlaybookPatch.Checklists.
+func (c Checklist) AsUpdate() PlaybookChecklistUpdate {
+	items := make([]PlaybookChecklistItemUpdate, len(c.Items))
+	for i, item := range c.Items {
+		items[i] = PlaybookChecklistItemUpdate{
+			Title:                   item.Title,
+			State:                   item.State,
+			StateModified:           item.StateModified,
+			AssigneeID:              item.AssigneeID,
+			AssigneeModified:        item.AssigneeModified,
+			AssigneeType:            item.AssigneeType,
+			AssigneePropertyFieldID: item.AssigneePropertyFieldID,
+			AssigneeRoleID:          item.AssigneeRoleID,
+			Command:                 item.Command,
+			CommandLastRun:          item.CommandLastRun,
+			Description:             item.Description,
+			LastSkipped:             item.LastSkipped,
+			DueDate:                 item.DueDate,
+			TaskActions:             item.TaskActions,
+			ConditionID:             item.ConditionID,
+		}
+	}
+	return PlaybookChecklistUpdate{Title: c.Title, Items: items}
+}
+
+// PlaybookMetricPatch specifies the fields of a playbook metric changed by
+// PlaybooksService.UpdateMetric. Fields left nil are not changed.
+type PlaybookMetricPatch struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Target      *int64  `json:"target,omitempty"`
+}
+
+// PlaybookBundleImportItem describes what happens to one playbook of an imported bundle.
+type PlaybookBundleImportItem struct {
+	ExportKey         string `json:"export_key"`
+	Title             string `json:"title"`
+	Action            string `json:"action"`
+	PlaybookID        string `json:"playbook_id"`
+	NumChannelActions int    `json:"num_channel_actions"`
+
+	// Warnings lists the parts of the playbook that are not imported.
+	Warnings []string `json:"warnings"`
+}
+
+// PlaybookBundleImportResult is returned by PlaybooksService.ImportBundle.
+type PlaybookBundleImportResult struct {
+	DryRun    bool                       `json:"dry_run"`
+	Playbooks []PlaybookBundleImportItem `json:"playbooks"`
+}
+
+// PlaybookRevision is an immutable snapshot of a playbook, recorded every time it changes.
+// Snapshot is in playbook export format and is only set when fetching a single revision.
+type PlaybookRevision struct {
+	ID         string          `json:"id"`
+	PlaybookID string          `json:"playbook_id"`
+	Revision   int64           `json:"revision"`
+	UserID     string          `json:"user_id"`
+	CreateAt   int64           `json:"create_at"`
+	Snapshot   json.RawMessage `json:"snapshot,omitempty"`
+}
+
+type GetPlaybookRevisionsResults struct {
+	TotalCount int                `json:"total_count"`
+	PageCount  int                `json:"page_count"`
+	HasMore    bool               `json:"has_more"`
+	Items      []PlaybookRevision `json:"items"`
+}
+
+// PlaybookRevisionChange is a single value that differs between two revisions.
+type PlaybookRevisionChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// PlaybookRevisionDiff is returned by PlaybooksService.DiffRevisions.
+type PlaybookRevisionDiff struct {
+	PlaybookID   string                   `json:"playbook_id"`
+	FromRevision int64                    `json:"from_revision"`
+	ToRevision   int64                    `json:"to_revision"`
+	Changes      []PlaybookRevisionChange `json:"changes"`
+}
+
+// PropagatedChecklistChange describes a single change to the checklists of a run. ItemID and
+// ItemTitle are empty when the change concerns the checklist itself.
+type PropagatedChecklistChange struct {
+	ChecklistID    string   `json:"checklist_id"`
+	ChecklistTitle string   `json:"checklist_title"`
+	ItemID         string   `json:"item_id,omitempty"`
+	ItemTitle      string   `json:"item_title,omitempty"`
+	Fields         []string `json:"fields,omitempty"`
+	Reason         string   `json:"reason,omitempty"`
+}
+
+// RunChecklistPropagation reports what propagating a playbook's checklists did to a single run.
+type RunChecklistPropagation struct {
+	PlaybookRunID string                      `json:"playbook_run_id"`
+	Name          string                      `json:"name"`
+	FromRevision  int64                       `json:"from_revision"`
+	Added         []PropagatedChecklistChange `json:"added"`
+	Updated       []PropagatedChecklistChange `json:"updated"`
+	Skipped       []PropagatedChecklistChange `json:"skipped"`
+	Error         string                      `json:"error,omitempty"`
+}
+
+// ChecklistPropagationResult reports the outcome of propagating a playbook's checklists to its
+// active runs.
+type ChecklistPropagationResult struct {
+	PlaybookID string                    `json:"playbook_id"`
+	Revision   int64                     `json:"revision"`
+	DryRun     bool                      `json:"dry_run"`
+	Runs       []RunChecklistPropagation `json:"runs"`
+}
+
 type PlaybookStats struct {
 	RunsInProgress                int        `json:"runs_in_progress"`
 	ParticipantsActive            int        `json:"participants_active"`
@@ -173,6 +379,19 @@ type PlaybookStats struct {
 	MetricValueRange              [][]int64  `json:"metric_value_range"`
 	MetricRollingValues           [][]int64  `json:"metric_rolling_values"`
 	LastXRunNames                 []string   `json:"last_x_run_names"`
+
+	NumberPropertyStats []NumberPropertyStats `json:"number_property_stats"`
+}
+
+// NumberPropertyStats summarizes the values a number property of a playbook took across its runs.
+type NumberPropertyStats struct {
+	FieldID string  `json:"field_id"`
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Sum     float64 `json:"sum"`
+	Average float64 `json:"average"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
 }
 
 type ChannelPlaybookMode int
@@ -7,7 +7,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
+	"net/url"
 )
 
 // PlaybookConditionsService handles communication with the playbook condition related
@@ -34,6 +36,21 @@ type ConditionExprV1 struct {
 
 	Is    *ComparisonCondition `json:"is,omitempty"`
 	IsNot *ComparisonCondition `json:"isNot,omitempty"`
+
+	// GreaterThan and LessThan compare number and date fields.
+	GreaterThan *ComparisonCondition `json:"gt,omitempty"`
+	LessThan    *ComparisonCondition `json:"lt,omitempty"`
+}
+
+// EncodeValues encodes the expression as JSON when used in query options, such as the property
+// filter of PlaybookRunListOptions.
+func (c ConditionExprV1) EncodeValues(key string, v *url.Values) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	v.Set(key, string(data))
+	return nil
 }
 
 // ComparisonCondition represents a field comparison condition.
@@ -85,6 +102,17 @@ func (s *PlaybookConditionsService) List(ctx context.Context, playbookID string,
 	return result, nil
 }
 
+// ListAll iterates over all the conditions of a playbook, fetching them perPage at a time.
+func (s *PlaybookConditionsService) ListAll(ctx context.Context, playbookID string, perPage int) iter.Seq2[Condition, error] {
+	return paginate(func(page int) ([]Condition, bool, error) {
+		result, err := s.List(ctx, playbookID, page, perPage, PlaybookConditionListOptions{})
+		if err != nil {
+			return nil, false, err
+		}
+		return result.Items, result.HasMore, nil
+	})
+}
+
 // Create a playbook condition.
 func (s *PlaybookConditionsService) Create(ctx context.Context, playbookID string, condition Condition) (*Condition, error) {
 	conditionURL := fmt.Sprintf("playbooks/%s/conditions", playbookID)
@@ -32,6 +32,8 @@ type PlaybookRun struct {
 	ActiveStageTitle                        string          `json:"active_stage_title"`
 	PostID                                  string          `json:"post_id"`
 	PlaybookID                              string          `json:"playbook_id"`
+	PlaybookRevision                        int64           `json:"playbook_revision"`
+	RunRoles                                []RunRole       `json:"run_roles"`
 	Type                                    string          `json:"type"`
 	Checklists                              []Checklist     `json:"checklists"`
 	StatusPosts                             []StatusPost    `json:"status_posts"`
@@ -199,6 +201,9 @@ const (
 
 	// SortByRuns sorts by the number of times a playbook has been run.
 	SortByRuns Sort = "runs"
+
+	// SortByProperty sorts by the value of the property field given by SortPropertyFieldID.
+	SortByProperty Sort = "property"
 )
 
 // SortDirection determines whether results are sorted ascending or descending.
@@ -267,6 +272,28 @@ type PlaybookRunListOptions struct {
 	// A value of 0 (or negative, normalized to 0) means this filter is not applied.
 	// This is sent as the "since" URL parameter.
 	ActivitySince int64 `url:"since,omitempty"`
+
+	// SharedFieldID filters playbook runs having the team property field with this ID, across
+	// all the playbooks using it. Defaults to blank (no filter).
+	SharedFieldID string `url:"shared_field_id,omitempty"`
+
+	// SharedFieldValue, together with SharedFieldID, filters playbook runs whose value for the
+	// team property field is this text or option name.
+	SharedFieldValue string `url:"shared_field_value,omitempty"`
+
+	// PropertyFilter filters playbook runs by their property values. Comparisons reference
+	// playbook or team property fields; select values may be option IDs or names. For example,
+	// severity in [SEV1, SEV2] and service is payments:
+	//
+	//	&ConditionExprV1{And: []ConditionExprV1{
+	//		{Is: &ComparisonCondition{FieldID: severityID, Value: json.RawMessage(`["SEV1","SEV2"]`)}},
+	//		{Is: &ComparisonCondition{FieldID: serviceID, Value: json.RawMessage(`"payments"`)}},
+	//	}}
+	PropertyFilter *ConditionExprV1 `url:"property_filter,omitempty"`
+
+	// SortPropertyFieldID is the playbook or team property field to sort by when Sort is
+	// SortByProperty. Runs without a value come last.
+	SortPropertyFieldID string `url:"sort_property_field_id,omitempty"`
 }
 
 // PlaybookRunList contains the paginated result.
@@ -310,6 +337,32 @@ type RunMetricData struct {
 	Value          null.Int `json:"value"`
 }
 
+// RunLink holds the information needed to display and link to a run.
+type RunLink struct {
+	PlaybookRunID string `json:"playbook_run_id"`
+	Name          string `json:"name"`
+}
+
+// AssignedTask is a checklist item assigned to the user, with its containing checklist.
+type AssignedTask struct {
+	ChecklistID    string `json:"checklist_id"`
+	ChecklistTitle string `json:"checklist_title"`
+	ChecklistItem
+}
+
+// AssignedRun is a run with the tasks assigned to the user.
+type AssignedRun struct {
+	RunLink
+	Tasks []AssignedTask `json:"tasks"`
+}
+
+// TodoDigest holds the items of a user's todo digest.
+type TodoDigest struct {
+	OverdueRuns    []RunLink     `json:"overdue_runs"`
+	AssignedRuns   []AssignedRun `json:"assigned_runs"`
+	InProgressRuns []RunLink     `json:"in_progress_runs"`
+}
+
 // OwnerInfo holds the summary information of a owner.
 type OwnerInfo struct {
 	UserID    string `json:"user_id"`
@@ -6,6 +6,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"time"
 )
@@ -115,6 +116,17 @@ func (s *PlaybookRunService) List(ctx context.Context, page, perPage int, opts P
 	return result, nil
 }
 
+// ListAll iterates over all the playbook runs matching opts, fetching them perPage at a time.
+func (s *PlaybookRunService) ListAll(ctx context.Context, perPage int, opts PlaybookRunListOptions) iter.Seq2[PlaybookRun, error] {
+	return paginate(func(page int) ([]PlaybookRun, bool, error) {
+		result, err := s.List(ctx, page, perPage, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		return result.Items, result.HasMore, nil
+	})
+}
+
 // Create a playbook run.
 func (s *PlaybookRunService) Create(ctx context.Context, opts PlaybookRunCreateOptions) (*PlaybookRun, error) {
 	playbookRunURL := "runs"
@@ -162,11 +174,19 @@ func (s *PlaybookRunService) UpdateStatus(ctx context.Context, playbookRunID str
 
 // Update updates a playbook run.
 func (s *PlaybookRunService) Update(ctx context.Context, playbookRunID string, updates PlaybookRunUpdateOptions) (*PlaybookRun, error) {
+	return s.UpdateIfUnmodified(ctx, playbookRunID, 0, updates)
+}
+
+// UpdateIfUnmodified updates a playbook run unless it was modified since updateAt, the UpdateAt
+// of the version the updates are based on, failing with a *StaleVersionError holding the current
+// version otherwise. An updateAt of 0 updates the run unconditionally.
+func (s *PlaybookRunService) UpdateIfUnmodified(ctx context.Context, playbookRunID string, updateAt int64, updates PlaybookRunUpdateOptions) (*PlaybookRun, error) {
 	updateURL := fmt.Sprintf("runs/%s", playbookRunID)
 	req, err := s.client.newAPIRequest(http.MethodPatch, updateURL, updates)
 	if err != nil {
 		return nil, err
 	}
+	setIfMatch(req, updateAt)
 
 	playbookRun := new(PlaybookRun)
 	resp, err := s.client.do(ctx, req, playbookRun)
@@ -355,6 +375,13 @@ func (s *PlaybookRunService) SetItemPropertyUserAssignee(ctx context.Context, pl
 	}{propertyFieldID})
 }
 
+// SetItemRunRoleAssignee assigns the specified checklist item to whoever holds the given run role.
+func (s *PlaybookRunService) SetItemRunRoleAssignee(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int, roleID string) error {
+	return s.putItemAssignee(ctx, playbookRunID, checklistIdx, itemIdx, struct {
+		AssigneeRoleID string `json:"assignee_role_id"`
+	}{roleID})
+}
+
 func (s *PlaybookRunService) putItemAssignee(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int, body interface{}) error {
 	url := fmt.Sprintf("runs/%s/checklists/%d/item/%d/assignee", playbookRunID, checklistIdx, itemIdx)
 	req, err := s.client.newAPIRequest(http.MethodPut, url, body)
@@ -410,6 +437,99 @@ func (s *PlaybookRunService) SetItemDueDate(ctx context.Context, playbookRunID s
 	return err
 }
 
+// SetItemState sets the state of a checklist item: "", "in_progress", "closed" or "skipped".
+func (s *PlaybookRunService) SetItemState(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int, newState string) error {
+	body := struct {
+		NewState string `json:"new_state"`
+	}{newState}
+	return s.itemRequest(ctx, http.MethodPut, playbookRunID, checklistIdx, itemIdx, "/state", body)
+}
+
+// EditItem changes the title, command and description of a checklist item.
+func (s *PlaybookRunService) EditItem(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int, title, command, description string) error {
+	body := struct {
+		Title       string `json:"title"`
+		Command     string `json:"command"`
+		Description string `json:"description"`
+	}{title, command, description}
+	return s.itemRequest(ctx, http.MethodPut, playbookRunID, checklistIdx, itemIdx, "", body)
+}
+
+// RemoveItem removes a checklist item.
+func (s *PlaybookRunService) RemoveItem(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int) error {
+	return s.itemRequest(ctx, http.MethodDelete, playbookRunID, checklistIdx, itemIdx, "", nil)
+}
+
+// SkipItem skips a checklist item.
+func (s *PlaybookRunService) SkipItem(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int) error {
+	return s.itemRequest(ctx, http.MethodPut, playbookRunID, checklistIdx, itemIdx, "/skip", nil)
+}
+
+// RestoreItem restores a skipped checklist item.
+func (s *PlaybookRunService) RestoreItem(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int) error {
+	return s.itemRequest(ctx, http.MethodPut, playbookRunID, checklistIdx, itemIdx, "/restore", nil)
+}
+
+// DuplicateItem adds a copy of a checklist item right after it.
+func (s *PlaybookRunService) DuplicateItem(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int) error {
+	return s.itemRequest(ctx, http.MethodPost, playbookRunID, checklistIdx, itemIdx, "/duplicate", nil)
+}
+
+// SetItemTaskActions replaces the task actions of a checklist item.
+func (s *PlaybookRunService) SetItemTaskActions(ctx context.Context, playbookRunID string, checklistIdx int, itemIdx int, taskActions []TaskAction) error {
+	body := struct {
+		TaskActions []TaskAction `json:"task_actions"`
+	}{taskActions}
+	return s.itemRequest(ctx, http.MethodPut, playbookRunID, checklistIdx, itemIdx, "/task-actions", body)
+}
+
+func (s *PlaybookRunService) itemRequest(ctx context.Context, method, playbookRunID string, checklistIdx int, itemIdx int, action string, body interface{}) error {
+	itemURL := fmt.Sprintf("runs/%s/checklists/%d/item/%d%s", playbookRunID, checklistIdx, itemIdx, action)
+	req, err := s.client.newAPIRequest(method, itemURL, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// SkipChecklist skips a checklist and all of its items.
+func (s *PlaybookRunService) SkipChecklist(ctx context.Context, playbookRunID string, checklistNumber int) error {
+	return s.checklistRequest(ctx, http.MethodPut, playbookRunID, checklistNumber, "/skip")
+}
+
+// RestoreChecklist restores a skipped checklist and all of its items.
+func (s *PlaybookRunService) RestoreChecklist(ctx context.Context, playbookRunID string, checklistNumber int) error {
+	return s.checklistRequest(ctx, http.MethodPut, playbookRunID, checklistNumber, "/restore")
+}
+
+// DuplicateChecklist adds a copy of a checklist right after it.
+func (s *PlaybookRunService) DuplicateChecklist(ctx context.Context, playbookRunID string, checklistNumber int) error {
+	return s.checklistRequest(ctx, http.MethodPost, playbookRunID, checklistNumber, "/duplicate")
+}
+
+func (s *PlaybookRunService) checklistRequest(ctx context.Context, method, playbookRunID string, checklistNumber int, action string) error {
+	checklistURL := fmt.Sprintf("runs/%s/checklists/%d%s", playbookRunID, checklistNumber, action)
+	req, err := s.client.newAPIRequest(method, checklistURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
 // Get a playbook run.
 func (s *PlaybookRunService) GetOwners(ctx context.Context) ([]OwnerInfo, error) {
 	req, err := s.client.newAPIRequest(http.MethodGet, "runs/owners", nil)
@@ -427,6 +547,47 @@ func (s *PlaybookRunService) GetOwners(ctx context.Context) ([]OwnerInfo, error)
 	return owners, nil
 }
 
+// GetTodoDigest returns the current user's todo digest: the runs with overdue status updates,
+// the tasks assigned to them and the runs they take part in.
+func (s *PlaybookRunService) GetTodoDigest(ctx context.Context) (*TodoDigest, error) {
+	req, err := s.client.newAPIRequest(http.MethodGet, "runs/todo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := new(TodoDigest)
+	resp, err := s.client.do(ctx, req, digest)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return digest, nil
+}
+
+// GetSharedPropertyFieldStats counts the values of the team property field opts.SharedFieldID
+// across the runs of the team opts.TeamID matching opts.
+func (s *PlaybookRunService) GetSharedPropertyFieldStats(ctx context.Context, opts PlaybookRunListOptions) (*SharedPropertyFieldStats, error) {
+	statsURL, err := addOptions("runs/shared_property_stats", opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newAPIRequest(http.MethodGet, statsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := new(SharedPropertyFieldStats)
+	resp, err := s.client.do(ctx, req, stats)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return stats, nil
+}
+
 // ChangeOwner changes the owner of a playbook run.
 func (s *PlaybookRunService) ChangeOwner(ctx context.Context, playbookRunID, newOwnerID string) error {
 	ownerURL := fmt.Sprintf("runs/%s/owner", playbookRunID)
@@ -445,6 +606,242 @@ func (s *PlaybookRunService) ChangeOwner(ctx context.Context, playbookRunID, new
 	return nil
 }
 
+// AssignRole gives a run role to userID, or leaves it unassigned when userID is empty.
+func (s *PlaybookRunService) AssignRole(ctx context.Context, playbookRunID, roleID, userID string) error {
+	roleURL := fmt.Sprintf("runs/%s/roles/%s", playbookRunID, roleID)
+	body := struct {
+		UserID string `json:"user_id"`
+	}{UserID: userID}
+	req, err := s.client.newAPIRequest(http.MethodPut, roleURL, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// AddParticipants adds users to the participants of a playbook run, adding them to the run's
+// channel as well when forceAddToChannel is set.
+func (s *PlaybookRunService) AddParticipants(ctx context.Context, playbookRunID string, userIDs []string, forceAddToChannel bool) error {
+	participantsURL := fmt.Sprintf("runs/%s/participants", playbookRunID)
+	body := struct {
+		UserIDs           []string `json:"user_ids"`
+		ForceAddToChannel bool     `json:"force_add_to_channel"`
+	}{UserIDs: userIDs, ForceAddToChannel: forceAddToChannel}
+	req, err := s.client.newAPIRequest(http.MethodPost, participantsURL, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RemoveParticipant removes a user from the participants of a playbook run.
+func (s *PlaybookRunService) RemoveParticipant(ctx context.Context, playbookRunID, userID string) error {
+	participantURL := fmt.Sprintf("runs/%s/participants/%s", playbookRunID, userID)
+	req, err := s.client.newAPIRequest(http.MethodDelete, participantURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// AddPostToTimeline adds an existing post to the timeline of a playbook run.
+func (s *PlaybookRunService) AddPostToTimeline(ctx context.Context, playbookRunID, postID, summary string) error {
+	timelineURL := fmt.Sprintf("runs/%s/timeline", playbookRunID)
+	body := struct {
+		PostID  string `json:"post_id"`
+		Summary string `json:"summary"`
+	}{PostID: postID, Summary: summary}
+	req, err := s.client.newAPIRequest(http.MethodPost, timelineURL, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RemoveTimelineEvent removes an event from the timeline of a playbook run.
+func (s *PlaybookRunService) RemoveTimelineEvent(ctx context.Context, playbookRunID, eventID string) error {
+	eventURL := fmt.Sprintf("runs/%s/timeline/%s", playbookRunID, eventID)
+	req, err := s.client.newAPIRequest(http.MethodDelete, eventURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Follow makes the current user follow a playbook run.
+func (s *PlaybookRunService) Follow(ctx context.Context, playbookRunID string) error {
+	return s.followersRequest(ctx, http.MethodPut, playbookRunID)
+}
+
+// Unfollow makes the current user stop following a playbook run.
+func (s *PlaybookRunService) Unfollow(ctx context.Context, playbookRunID string) error {
+	return s.followersRequest(ctx, http.MethodDelete, playbookRunID)
+}
+
+func (s *PlaybookRunService) followersRequest(ctx context.Context, method, playbookRunID string) error {
+	followersURL := fmt.Sprintf("runs/%s/followers", playbookRunID)
+	req, err := s.client.newAPIRequest(method, followersURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetFollowers returns the IDs of the users following a playbook run.
+func (s *PlaybookRunService) GetFollowers(ctx context.Context, playbookRunID string) ([]string, error) {
+	followersURL := fmt.Sprintf("runs/%s/followers", playbookRunID)
+	req, err := s.client.newAPIRequest(http.MethodGet, followersURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	followers := []string{}
+	resp, err := s.client.do(ctx, req, &followers)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return followers, nil
+}
+
+// CancelRetrospective marks the retrospective of a playbook run as not needed.
+func (s *PlaybookRunService) CancelRetrospective(ctx context.Context, playbookRunID string) error {
+	cancelURL := fmt.Sprintf("runs/%s/no-retrospective-button", playbookRunID)
+	req, err := s.client.newAPIRequest(http.MethodPost, cancelURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SetStatusUpdatesEnabled enables or disables the status updates of a playbook run.
+func (s *PlaybookRunService) SetStatusUpdatesEnabled(ctx context.Context, playbookRunID string, enabled bool) error {
+	toggleURL := fmt.Sprintf("runs/%s/status-update-enabled", playbookRunID)
+	body := struct {
+		StatusEnabled bool `json:"status_enabled"`
+	}{enabled}
+	req, err := s.client.newAPIRequest(http.MethodPut, toggleURL, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SetRetrospectiveEnabled enables or disables the retrospective of a playbook run.
+func (s *PlaybookRunService) SetRetrospectiveEnabled(ctx context.Context, playbookRunID string, enabled bool) error {
+	toggleURL := fmt.Sprintf("runs/%s/retrospective-enabled", playbookRunID)
+	body := struct {
+		RetrospectiveEnabled bool `json:"retrospective_enabled"`
+	}{enabled}
+	req, err := s.client.newAPIRequest(http.MethodPut, toggleURL, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RequestJoinChannel asks the owner of a playbook run to add the current user, a participant,
+// to the run's channel.
+func (s *PlaybookRunService) RequestJoinChannel(ctx context.Context, playbookRunID string) error {
+	requestURL := fmt.Sprintf("runs/%s/request-join-channel", playbookRunID)
+	req, err := s.client.newAPIRequest(http.MethodPost, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ListByChannel lists the playbook runs of a channel the current user can see.
+func (s *PlaybookRunService) ListByChannel(ctx context.Context, channelID string) ([]PlaybookRun, error) {
+	channelURL := fmt.Sprintf("runs/channel/%s/runs", channelID)
+	req, err := s.client.newAPIRequest(http.MethodGet, channelURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	playbookRuns := []PlaybookRun{}
+	resp, err := s.client.do(ctx, req, &playbookRuns)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return playbookRuns, nil
+}
+
+// ListChannelIDs lists the IDs of the channels of the playbook runs matching opts.
+func (s *PlaybookRunService) ListChannelIDs(ctx context.Context, opts PlaybookRunListOptions) ([]string, error) {
+	channelsURL, err := addOptions("runs/channels", opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newAPIRequest(http.MethodGet, channelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	channelIDs := []string{}
+	resp, err := s.client.do(ctx, req, &channelIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return channelIDs, nil
+}
+
 // GetPropertyFields gets all property fields for a run. It is a wrapper around GetPropertyFieldsSince with updatedSince set to 0.
 func (s *PlaybookRunService) GetPropertyFields(ctx context.Context, playbookRunID string) ([]PropertyField, error) {
 	return s.GetPropertyFieldsSince(ctx, playbookRunID, 0)
@@ -499,6 +896,46 @@ func (s *PlaybookRunService) GetPropertyValuesSince(ctx context.Context, playboo
 	return values, nil
 }
 
+// GetPropertyValuesAsOf gets the property values a run had at the given timestamp in milliseconds.
+func (s *PlaybookRunService) GetPropertyValuesAsOf(ctx context.Context, playbookRunID string, asOf int64) ([]PropertyValue, error) {
+	propertyValuesURL := fmt.Sprintf("runs/%s/property_values?as_of=%d", playbookRunID, asOf)
+	req, err := s.client.newAPIRequest(http.MethodGet, propertyValuesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []PropertyValue
+	resp, err := s.client.do(ctx, req, &values)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return values, nil
+}
+
+// GetPropertyValueHistory gets the history of a run's property values, oldest first.
+// fieldID: optional - only return the history of this field (empty = all fields)
+func (s *PlaybookRunService) GetPropertyValueHistory(ctx context.Context, playbookRunID, fieldID string) ([]PropertyValueChange, error) {
+	historyURL := fmt.Sprintf("runs/%s/property_values/history", playbookRunID)
+	if fieldID != "" {
+		historyURL = fmt.Sprintf("%s?field_id=%s", historyURL, fieldID)
+	}
+	req, err := s.client.newAPIRequest(http.MethodGet, historyURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []PropertyValueChange
+	resp, err := s.client.do(ctx, req, &changes)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return changes, nil
+}
+
 // SetPropertyValue sets a property value for a run.
 func (s *PlaybookRunService) SetPropertyValue(ctx context.Context, playbookRunID, fieldID string, value PropertyValueRequest) (*PropertyValue, error) {
 	propertyValueURL := fmt.Sprintf("runs/%s/property_fields/%s/value", playbookRunID, fieldID)
@@ -76,3 +76,30 @@ func ExamplePlaybookRunService_List() {
 		fmt.Printf("Playbook Run Name: %s\n", playbookRun.Name)
 	}
 }
+
+func ExamplePlaybookRunService_ListAll() {
+	ctx := context.Background()
+
+	client4 := model.NewAPIv4Client("http://localhost:8065")
+	_, _, err := client4.Login(context.Background(), "test@example.com", "testtest")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	c, err := client.New(client4)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := client.PlaybookRunListOptions{
+		Sort:      client.SortByCreateAt,
+		Direction: client.SortDesc,
+	}
+	for playbookRun, err := range c.PlaybookRuns.ListAll(ctx, 100, opts) {
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("Playbook Run Name: %s\n", playbookRun.Name)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"iter"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -100,11 +101,22 @@ func (s *PlaybooksService) Create(ctx context.Context, opts PlaybookCreateOption
 }
 
 func (s *PlaybooksService) Update(ctx context.Context, playbook Playbook) error {
+	return s.update(ctx, playbook, 0)
+}
+
+// UpdateIfUnmodified updates a playbook unless it was modified since playbook.UpdateAt, failing
+// with a *StaleVersionError holding the current version otherwise.
+func (s *PlaybooksService) UpdateIfUnmodified(ctx context.Context, playbook Playbook) error {
+	return s.update(ctx, playbook, playbook.UpdateAt)
+}
+
+func (s *PlaybooksService) update(ctx context.Context, playbook Playbook, updateAt int64) error {
 	updateURL := fmt.Sprintf("playbooks/%s", playbook.ID)
 	req, err := s.client.newAPIRequest(http.MethodPut, updateURL, playbook)
 	if err != nil {
 		return err
 	}
+	setIfMatch(req, updateAt)
 
 	_, err = s.client.do(ctx, req, nil)
 	if err != nil {
@@ -129,6 +141,119 @@ func (s *PlaybooksService) Archive(ctx context.Context, playbookID string) error
 	return nil
 }
 
+// Restore an archived playbook.
+func (s *PlaybooksService) Restore(ctx context.Context, playbookID string) error {
+	restoreURL := fmt.Sprintf("playbooks/%s/restore", playbookID)
+	req, err := s.client.newAPIRequest(http.MethodPut, restoreURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Patch updates the fields of a playbook set in patch, leaving the others unchanged.
+func (s *PlaybooksService) Patch(ctx context.Context, playbookID string, patch PlaybookPatch) error {
+	patchURL := fmt.Sprintf("playbooks/%s", playbookID)
+	req, err := s.client.newAPIRequest(http.MethodPatch, patchURL, patch)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddMember adds a user to the members of a playbook. Adding a member twice is not an error.
+func (s *PlaybooksService) AddMember(ctx context.Context, playbookID, userID string) error {
+	return s.memberRequest(ctx, http.MethodPut, playbookID, userID)
+}
+
+// RemoveMember removes a user from the members of a playbook.
+func (s *PlaybooksService) RemoveMember(ctx context.Context, playbookID, userID string) error {
+	return s.memberRequest(ctx, http.MethodDelete, playbookID, userID)
+}
+
+func (s *PlaybooksService) memberRequest(ctx context.Context, method, playbookID, userID string) error {
+	memberURL := fmt.Sprintf("playbooks/%s/members/%s", playbookID, userID)
+	req, err := s.client.newAPIRequest(method, memberURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	return err
+}
+
+// AddMetric adds a metric to a playbook, returning it with its ID. The ID and PlaybookID of
+// metric are ignored.
+func (s *PlaybooksService) AddMetric(ctx context.Context, playbookID string, metric PlaybookMetricConfig) (*PlaybookMetricConfig, error) {
+	metricsURL := fmt.Sprintf("playbooks/%s/metrics", playbookID)
+	req, err := s.client.newAPIRequest(http.MethodPost, metricsURL, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(PlaybookMetricConfig)
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("expected status code %d", http.StatusCreated)
+	}
+
+	return result, nil
+}
+
+// UpdateMetric changes the fields of a playbook metric set in patch, returning the result.
+func (s *PlaybooksService) UpdateMetric(ctx context.Context, playbookID, metricID string, patch PlaybookMetricPatch) (*PlaybookMetricConfig, error) {
+	metricURL := fmt.Sprintf("playbooks/%s/metrics/%s", playbookID, metricID)
+	req, err := s.client.newAPIRequest(http.MethodPatch, metricURL, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(PlaybookMetricConfig)
+	if _, err := s.client.do(ctx, req, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteMetric removes a metric from a playbook.
+func (s *PlaybooksService) DeleteMetric(ctx context.Context, playbookID, metricID string) error {
+	metricURL := fmt.Sprintf("playbooks/%s/metrics/%s", playbookID, metricID)
+	req, err := s.client.newAPIRequest(http.MethodDelete, metricURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	return err
+}
+
+// ListAll iterates over all the playbooks of a team matching opts, fetching them perPage at a time.
+func (s *PlaybooksService) ListAll(ctx context.Context, teamID string, perPage int, opts PlaybookListOptions) iter.Seq2[Playbook, error] {
+	return paginate(func(page int) ([]Playbook, bool, error) {
+		result, err := s.List(ctx, teamID, page, perPage, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		return result.Items, result.HasMore, nil
+	})
+}
+
 func (s *PlaybooksService) Export(ctx context.Context, playbookID string) ([]byte, error) {
 	url := fmt.Sprintf("playbooks/%s/export", playbookID)
 	req, err := s.client.newAPIRequest(http.MethodGet, url, nil)
@@ -206,6 +331,61 @@ func (s *PlaybooksService) Import(ctx context.Context, toImport []byte, team str
 	return result.ID, nil
 }
 
+// ExportBundle exports the given playbooks as a single bundle.
+func (s *PlaybooksService) ExportBundle(ctx context.Context, playbookIDs []string) ([]byte, error) {
+	body := struct {
+		PlaybookIDs []string `json:"playbook_ids"`
+	}{playbookIDs}
+	req, err := s.client.newAPIRequest(http.MethodPost, "playbooks/bundle/export", body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	result, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("expected status code %d", http.StatusOK)
+	}
+
+	return result, nil
+}
+
+// ImportBundle imports a bundle of playbooks into the team, updating the playbooks previously
+// imported from the same bundle. With dryRun set, only reports what the import would do.
+func (s *PlaybooksService) ImportBundle(ctx context.Context, toImport []byte, team string, dryRun bool) (*PlaybookBundleImportResult, error) {
+	url := fmt.Sprintf("playbooks/bundle/import?team_id=%s&dry_run=%t", team, dryRun)
+	u, err := s.client.BaseURL.Parse(s.client.buildAPIURL(url))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid endpoint %s", url)
+	}
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(toImport))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create http request for bundle import")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	result := new(PlaybookBundleImportResult)
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("expected status code %d or %d", http.StatusOK, http.StatusCreated)
+	}
+
+	return result, nil
+}
+
 func (s *PlaybooksService) Stats(ctx context.Context, playbookID string) (*PlaybookStats, error) {
 	playbookStatsURL := fmt.Sprintf("stats/playbook?playbook_id=%s", playbookID)
 	req, err := s.client.newAPIRequest(http.MethodGet, playbookStatsURL, nil)
@@ -371,3 +551,232 @@ func (s *PlaybooksService) ReorderPropertyFields(ctx context.Context, playbookID
 
 	return fields, nil
 }
+
+// GetTeamPropertyFields gets the shared property fields of a team.
+func (s *PlaybooksService) GetTeamPropertyFields(ctx context.Context, teamID string) ([]PropertyField, error) {
+	propertyFieldsURL := fmt.Sprintf("teams/%s/property_fields", teamID)
+	req, err := s.client.newAPIRequest(http.MethodGet, propertyFieldsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []PropertyField
+	resp, err := s.client.do(ctx, req, &fields)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return fields, nil
+}
+
+// CreateTeamPropertyField creates a shared property field for a team. Playbooks use it by
+// creating a property field with the SharedFieldID attribute set to its ID.
+func (s *PlaybooksService) CreateTeamPropertyField(ctx context.Context, teamID string, field PropertyFieldRequest) (*PropertyField, error) {
+	propertyFieldsURL := fmt.Sprintf("teams/%s/property_fields", teamID)
+	req, err := s.client.newAPIRequest(http.MethodPost, propertyFieldsURL, field)
+	if err != nil {
+		return nil, err
+	}
+
+	propertyField := new(PropertyField)
+	resp, err := s.client.do(ctx, req, propertyField)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return propertyField, nil
+}
+
+// UpdateTeamPropertyField updates a shared property field of a team, along with the property
+// fields of the playbooks using it.
+func (s *PlaybooksService) UpdateTeamPropertyField(ctx context.Context, teamID, fieldID string, field PropertyFieldRequest) (*PropertyField, error) {
+	propertyFieldURL := fmt.Sprintf("teams/%s/property_fields/%s", teamID, fieldID)
+	req, err := s.client.newAPIRequest(http.MethodPut, propertyFieldURL, field)
+	if err != nil {
+		return nil, err
+	}
+
+	propertyField := new(PropertyField)
+	resp, err := s.client.do(ctx, req, propertyField)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return propertyField, nil
+}
+
+// DeleteTeamPropertyField deletes a shared property field of a team no playbook uses.
+func (s *PlaybooksService) DeleteTeamPropertyField(ctx context.Context, teamID, fieldID string) error {
+	propertyFieldURL := fmt.Sprintf("teams/%s/property_fields/%s", teamID, fieldID)
+	req, err := s.client.newAPIRequest(http.MethodDelete, propertyFieldURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// MergeTeamPropertyFields links the property fields named name of the playbooks of a team to a
+// shared property field of that name, creating it if needed.
+func (s *PlaybooksService) MergeTeamPropertyFields(ctx context.Context, teamID, name string) (*SharedPropertyMergeResult, error) {
+	mergeURL := fmt.Sprintf("teams/%s/property_fields/merge", teamID)
+	body := struct {
+		Name string `json:"name"`
+	}{Name: name}
+	req, err := s.client.newAPIRequest(http.MethodPost, mergeURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(SharedPropertyMergeResult)
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// GetRevisions lists the revisions of a playbook, newest first. Snapshots are not included.
+func (s *PlaybooksService) GetRevisions(ctx context.Context, playbookID string, page, perPage int) (*GetPlaybookRevisionsResults, error) {
+	revisionsURL, err := addPaginationOptions(fmt.Sprintf("playbooks/%s/revisions", playbookID), page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pagination options: %w", err)
+	}
+
+	req, err := s.client.newAPIRequest(http.MethodGet, revisionsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetPlaybookRevisionsResults{}
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// GetRevision gets a single revision of a playbook, including its snapshot.
+func (s *PlaybooksService) GetRevision(ctx context.Context, playbookID string, revision int64) (*PlaybookRevision, error) {
+	revisionURL := fmt.Sprintf("playbooks/%s/revisions/%d", playbookID, revision)
+	req, err := s.client.newAPIRequest(http.MethodGet, revisionURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlaybookRevision{}
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// DiffRevisions lists the changes between two revisions of a playbook.
+func (s *PlaybooksService) DiffRevisions(ctx context.Context, playbookID string, fromRevision, toRevision int64) (*PlaybookRevisionDiff, error) {
+	diffURL := fmt.Sprintf("playbooks/%s/revisions/diff?from=%d&to=%d", playbookID, fromRevision, toRevision)
+	req, err := s.client.newAPIRequest(http.MethodGet, diffURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlaybookRevisionDiff{}
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// RollbackRevision restores a playbook to the given revision. The rollback itself is recorded as
+// a new revision. Returns the updated playbook.
+func (s *PlaybooksService) RollbackRevision(ctx context.Context, playbookID string, revision int64) (*Playbook, error) {
+	rollbackURL := fmt.Sprintf("playbooks/%s/revisions/%d/rollback", playbookID, revision)
+	req, err := s.client.newAPIRequest(http.MethodPost, rollbackURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	playbook := new(Playbook)
+	resp, err := s.client.do(ctx, req, playbook)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return playbook, nil
+}
+
+// DiffDraft lists the changes made to a playbook since it was last published.
+func (s *PlaybooksService) DiffDraft(ctx context.Context, playbookID string) (*PlaybookRevisionDiff, error) {
+	diffURL := fmt.Sprintf("playbooks/%s/revisions/diff?from=published&to=draft", playbookID)
+	req, err := s.client.newAPIRequest(http.MethodGet, diffURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlaybookRevisionDiff{}
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// Publish makes the latest version of a playbook the one new runs are created from. Only
+// playbook admins can publish. Returns the updated playbook.
+func (s *PlaybooksService) Publish(ctx context.Context, playbookID string) (*Playbook, error) {
+	publishURL := fmt.Sprintf("playbooks/%s/publish", playbookID)
+	req, err := s.client.newAPIRequest(http.MethodPost, publishURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	playbook := new(Playbook)
+	resp, err := s.client.do(ctx, req, playbook)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return playbook, nil
+}
+
+// PropagateChecklists brings the checklists of the playbook's active runs up to date with its
+// published version, keeping the progress and edits made in each run. With dryRun set, only
+// reports what would change.
+func (s *PlaybooksService) PropagateChecklists(ctx context.Context, playbookID string, dryRun bool) (*ChecklistPropagationResult, error) {
+	propagateURL := fmt.Sprintf("playbooks/%s/propagate_checklists?dry_run=%t", playbookID, dryRun)
+	req, err := s.client.newAPIRequest(http.MethodPost, propagateURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(ChecklistPropagationResult)
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
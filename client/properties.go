@@ -29,6 +29,17 @@ type PropertyValue struct {
 	DeleteAt int64           `json:"delete_at"`
 }
 
+// PropertyValueChange represents a recorded write of a run's property value
+type PropertyValueChange struct {
+	ID            string          `json:"id"`
+	PlaybookRunID string          `json:"playbook_run_id"`
+	FieldID       string          `json:"field_id"`
+	OldValue      json.RawMessage `json:"old_value"`
+	NewValue      json.RawMessage `json:"new_value"`
+	UserID        string          `json:"user_id"`
+	CreateAt      int64           `json:"create_at"`
+}
+
 // PropertyFieldRequest represents a request to create or update a property field
 type PropertyFieldRequest struct {
 	Name  string                   `json:"name"`
@@ -36,14 +47,72 @@ type PropertyFieldRequest struct {
 	Attrs *PropertyFieldAttrsInput `json:"attrs,omitempty"`
 }
 
+// MissingPropertyField identifies a required property field that has no value.
+type MissingPropertyField struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // PropertyFieldAttrsInput represents property field attributes for input
 type PropertyFieldAttrsInput struct {
 	Visibility *string                `json:"visibility,omitempty"`
-	SortOrder  *float64               `json:"sortOrder,omitempty"`
+	SortOrder  *float64               `json:"sort_order,omitempty"`
 	Options    *[]PropertyOptionInput `json:"options,omitempty"`
-	ParentID   *string                `json:"parentID,omitempty"`
+	ParentID   *string                `json:"parent_id,omitempty"`
+
+	// ValueType refines text fields (PropertyValueTypeNumber, PropertyValueTypeURL,
+	// PropertyValueTypeEmail) and date fields (PropertyValueTypeDateTime).
+	ValueType *string `json:"value_type,omitempty"`
+
+	// Min, Max and Integer constrain the values of number fields.
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Integer bool     `json:"integer,omitempty"`
+
+	// RequiredOnCreate and RequiredOnFinish make a value mandatory when a run is started or
+	// finished, respectively.
+	RequiredOnCreate bool `json:"required_on_create,omitempty"`
+	RequiredOnFinish bool `json:"required_on_finish,omitempty"`
+
+	// Computed makes the field read-only, its value being derived from other data of the run.
+	Computed *PropertyFieldComputed `json:"computed,omitempty"`
+
+	// SharedFieldID, when creating a playbook property field, links it to the team property
+	// field with this ID instead of defining it in the playbook.
+	SharedFieldID string `json:"shared_field_id,omitempty"`
+}
+
+// Kinds of computed property fields.
+const (
+	ComputedKindFormula               = "formula"
+	ComputedKindDurationSinceCreation = "duration_since_creation"
+	ComputedKindOpenItemsCount        = "open_items_count"
+	ComputedKindLookup                = "lookup"
+)
+
+// PropertyFieldComputed describes how the value of a computed field is derived.
+type PropertyFieldComputed struct {
+	Kind string `json:"kind"`
+
+	// Expression is the formula of formula fields, referencing other fields by name in braces.
+	Expression string `json:"expression,omitempty"`
+
+	// Checklist is the title of the checklist counted by open_items_count fields.
+	Checklist string `json:"checklist,omitempty"`
+
+	// SourceField and Lookup map the values of another field to numbers for lookup fields.
+	SourceField string             `json:"source_field,omitempty"`
+	Lookup      map[string]float64 `json:"lookup,omitempty"`
 }
 
+// Value types of property fields.
+const (
+	PropertyValueTypeURL      = "url"
+	PropertyValueTypeEmail    = "email"
+	PropertyValueTypeNumber   = "number"
+	PropertyValueTypeDateTime = "datetime"
+)
+
 // PropertyOptionInput represents a property option for input
 type PropertyOptionInput struct {
 	ID    *string `json:"id,omitempty"`
@@ -51,6 +120,35 @@ type PropertyOptionInput struct {
 	Color *string `json:"color,omitempty"`
 }
 
+// SharedPropertyValueCount is the number of runs having a value of a team property field.
+type SharedPropertyValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SharedPropertyFieldStats summarizes the values of a team property field across the runs of
+// every playbook using it.
+type SharedPropertyFieldStats struct {
+	FieldID   string                     `json:"field_id"`
+	TotalRuns int                        `json:"total_runs"`
+	Values    []SharedPropertyValueCount `json:"values"`
+}
+
+// SharedPropertyMergeSkip is a playbook property field left out of a merge.
+type SharedPropertyMergeSkip struct {
+	PlaybookID string `json:"playbook_id"`
+	FieldID    string `json:"field_id"`
+	Reason     string `json:"reason"`
+}
+
+// SharedPropertyMergeResult is the outcome of merging the same-named playbook property fields
+// of a team into a team property field.
+type SharedPropertyMergeResult struct {
+	Field        PropertyField             `json:"field"`
+	LinkedFields []PropertyField           `json:"linked_fields"`
+	Skipped      []SharedPropertyMergeSkip `json:"skipped"`
+}
+
 // PropertyValueRequest represents a request to set a property value
 type PropertyValueRequest struct {
 	Value json.RawMessage `json:"value"`
@@ -0,0 +1,50 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyFilterListOptions(t *testing.T) {
+	t.Run("filter and sort are encoded", func(t *testing.T) {
+		opts := PlaybookRunListOptions{
+			Sort:                SortByProperty,
+			SortPropertyFieldID: "severityfieldid",
+			PropertyFilter: &ConditionExprV1{And: []ConditionExprV1{
+				{Is: &ComparisonCondition{FieldID: "severityfieldid", Value: json.RawMessage(`["SEV1","SEV2"]`)}},
+				{Is: &ComparisonCondition{FieldID: "servicefieldid", Value: json.RawMessage(`"payments"`)}},
+			}},
+		}
+
+		encoded, err := addOptions("runs", opts)
+		require.NoError(t, err)
+
+		u, err := url.Parse(encoded)
+		require.NoError(t, err)
+		query := u.Query()
+		assert.Equal(t, "property", query.Get("sort"))
+		assert.Equal(t, "severityfieldid", query.Get("sort_property_field_id"))
+
+		var filter ConditionExprV1
+		require.NoError(t, json.Unmarshal([]byte(query.Get("property_filter")), &filter))
+		require.Len(t, filter.And, 2)
+		assert.Equal(t, "servicefieldid", filter.And[1].Is.FieldID)
+		assert.JSONEq(t, `["SEV1","SEV2"]`, string(filter.And[0].Is.Value))
+	})
+
+	t.Run("no filter", func(t *testing.T) {
+		encoded, err := addOptions("runs", PlaybookRunListOptions{})
+		require.NoError(t, err)
+
+		u, err := url.Parse(encoded)
+		require.NoError(t, err)
+		assert.False(t, u.Query().Has("property_filter"))
+	})
+}
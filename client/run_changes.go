@@ -0,0 +1,260 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Types of the changes of the run change feed.
+const (
+	RunChangeCreated       = "run_created"
+	RunChangeUpdated       = "run_updated"
+	RunChangeFinished      = "run_finished"
+	RunChangeChecklistItem = "checklist_item_changed"
+	RunChangeProperty      = "property_changed"
+)
+
+// defaultRunChangesRetry is how long SubscribeChanges waits before reconnecting, unless the
+// server asks for another delay.
+const defaultRunChangesRetry = 5 * time.Second
+
+// maxRunChangeEventSize is the largest event SubscribeChanges accepts. run_created events hold
+// the whole run.
+const maxRunChangeEventSize = 16 * 1024 * 1024
+
+// RunChange is an entry of the run change feed.
+type RunChange struct {
+	// Cursor increases with every change. Pass it back to resume the feed after this change.
+	Cursor        int64  `json:"cursor"`
+	Type          string `json:"type"`
+	PlaybookRunID string `json:"playbook_run_id"`
+	TeamID        string `json:"team_id"`
+	ChannelID     string `json:"channel_id"`
+
+	// Payload depends on Type: the PlaybookRun for run_created, the incremental update of the run
+	// for run_updated and run_finished, a ChecklistItemChange or a PropertyChange for the others.
+	Payload json.RawMessage `json:"payload"`
+
+	CreateAt int64 `json:"create_at"`
+}
+
+// ChecklistItemChange is the payload of a checklist_item_changed change.
+type ChecklistItemChange struct {
+	PlaybookRunID string                `json:"playbook_run_id"`
+	ChecklistID   string                `json:"checklist_id"`
+	ItemUpdates   []ChecklistItemUpdate `json:"item_updates,omitempty"`
+	ItemInserts   []ChecklistItem       `json:"item_inserts,omitempty"`
+	ItemDeletes   []string              `json:"item_deletes,omitempty"`
+	ItemsOrder    []string              `json:"items_order,omitempty"`
+}
+
+// ChecklistItemUpdate holds the changed fields of a checklist item.
+type ChecklistItemUpdate struct {
+	ID                     string                 `json:"id"`
+	ChecklistItemUpdatedAt int64                  `json:"checklist_item_updated_at"`
+	Fields                 map[string]interface{} `json:"fields"`
+}
+
+// PropertyChange is the payload of a property_changed change, holding the run's property fields
+// and values after the change.
+type PropertyChange struct {
+	PlaybookRunID  string          `json:"playbook_run_id"`
+	PropertyFields []PropertyField `json:"property_fields,omitempty"`
+	PropertyValues []PropertyValue `json:"property_values,omitempty"`
+}
+
+// RunChangesOptions filters the run change feed.
+type RunChangesOptions struct {
+	// Cursor resumes the feed after the change with this cursor. 0 starts after the most recent
+	// change.
+	Cursor int64 `url:"cursor,omitempty"`
+
+	// TeamID restricts the feed to the runs of a team.
+	TeamID string `url:"team_id,omitempty"`
+
+	// PlaybookRunID restricts the feed to a single run.
+	PlaybookRunID string `url:"run_id,omitempty"`
+}
+
+// RunChangesResult is a page of the run change feed.
+type RunChangesResult struct {
+	Changes []RunChange `json:"changes"`
+
+	// Cursor resumes the feed after this page.
+	Cursor int64 `json:"cursor"`
+}
+
+// GetChanges returns the changes of the run change feed matching opts, leaving out the runs the
+// user cannot view. When there is none yet, it waits up to wait (at most 30 seconds) for one.
+func (s *PlaybookRunService) GetChanges(ctx context.Context, opts RunChangesOptions, wait time.Duration) (*RunChangesResult, error) {
+	changesURL, err := addOptions("runs/changes", opts)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		changesURL, err = addOption(changesURL, "wait", strconv.Itoa(int(wait.Seconds())))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := s.client.newAPIRequest(http.MethodGet, changesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(RunChangesResult)
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// runChangeHandlerError marks an error returned by the handler of SubscribeChanges.
+type runChangeHandlerError struct {
+	err error
+}
+
+func (e *runChangeHandlerError) Error() string {
+	return e.err.Error()
+}
+
+// runChangeStream is the state of a subscription to the change feed, kept across connections.
+type runChangeStream struct {
+	opts RunChangesOptions
+
+	// resumed is true once the server told where the feed starts, after which Last-Event-ID is
+	// sent even when the cursor is 0.
+	resumed bool
+
+	// retry is how long to wait before reconnecting.
+	retry time.Duration
+}
+
+// SubscribeChanges streams the run change feed matching opts, calling handler with each change in
+// order. When the connection drops, it reconnects and resumes after the last change handled. It
+// returns when ctx is done, when handler returns an error, or when the server rejects the
+// subscription, for instance because the feed is disabled.
+func (s *PlaybookRunService) SubscribeChanges(ctx context.Context, opts RunChangesOptions, handler func(RunChange) error) error {
+	stream := &runChangeStream{opts: opts, resumed: opts.Cursor > 0, retry: defaultRunChangesRetry}
+	for {
+		err := s.streamChanges(ctx, stream, handler)
+
+		var handlerErr *runChangeHandlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+		var errResponse *ErrorResponse
+		if errors.As(err, &errResponse) && errResponse.StatusCode >= 400 && errResponse.StatusCode < 500 {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(stream.retry):
+		}
+	}
+}
+
+// streamChanges reads a single connection to the change feed, moving the cursor of stream past
+// every change handled.
+func (s *PlaybookRunService) streamChanges(ctx context.Context, stream *runChangeStream, handler func(RunChange) error) error {
+	streamURL, err := addOptions("runs/changes/stream", stream.opts)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.client.newAPIRequest(http.MethodGet, streamURL, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	if stream.resumed {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(stream.opts.Cursor, 10))
+	}
+
+	resp, err := s.client.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err = checkResponse(resp); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRunChangeEventSize)
+
+	var id string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := stream.dispatch(id, data, handler); err != nil {
+				return err
+			}
+			id, data = "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if milliseconds, err := strconv.Atoi(value); err == nil && milliseconds >= 0 {
+				stream.retry = time.Duration(milliseconds) * time.Millisecond
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read run change stream")
+	}
+
+	return errors.New("run change stream closed")
+}
+
+// dispatch handles an event of the stream. Events without data only move the cursor.
+func (stream *runChangeStream) dispatch(id string, data []string, handler func(RunChange) error) error {
+	if len(data) == 0 {
+		if cursor, err := strconv.ParseInt(id, 10, 64); err == nil {
+			stream.opts.Cursor = cursor
+			stream.resumed = true
+		}
+		return nil
+	}
+
+	var change RunChange
+	if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &change); err != nil {
+		return errors.Wrap(err, "failed to decode run change")
+	}
+	if err := handler(change); err != nil {
+		return &runChangeHandlerError{err: err}
+	}
+	stream.opts.Cursor = change.Cursor
+	stream.resumed = true
+
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRunChangeEvent(t *testing.T, w http.ResponseWriter, change RunChange) {
+	data, err := json.Marshal(change)
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", change.Cursor, change.Type, data)
+	require.NoError(t, err)
+}
+
+func TestGetChanges(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/plugins/playbooks/api/v0/runs/changes", r.URL.Path)
+		assert.Equal(t, "12", r.URL.Query().Get("cursor"))
+		assert.Equal(t, "team_id", r.URL.Query().Get("team_id"))
+		assert.Equal(t, "10", r.URL.Query().Get("wait"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RunChangesResult{
+			Changes: []RunChange{{Cursor: 13, Type: RunChangeUpdated, PlaybookRunID: "run_id"}},
+			Cursor:  14,
+		})
+	}))
+	defer ts.Close()
+
+	c, err := newClient(ts.URL, ts.Client())
+	require.NoError(t, err)
+
+	result, err := c.PlaybookRuns.GetChanges(context.Background(), RunChangesOptions{Cursor: 12, TeamID: "team_id"}, 10*time.Second)
+	require.NoError(t, err)
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, int64(13), result.Changes[0].Cursor)
+	assert.Equal(t, int64(14), result.Cursor)
+}
+
+func TestSubscribeChanges(t *testing.T) {
+	t.Run("resumes after the last change handled", func(t *testing.T) {
+		var mu sync.Mutex
+		var lastEventIDs []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs/changes/stream", r.URL.Path)
+			assert.Equal(t, "run_id", r.URL.Query().Get("run_id"))
+
+			mu.Lock()
+			lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+			connection := len(lastEventIDs)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			switch connection {
+			case 1:
+				_, _ = fmt.Fprint(w, "retry: 10\nid: 0\n\n: heartbeat\n\n")
+				writeRunChangeEvent(t, w, RunChange{Cursor: 1, Type: RunChangeCreated, PlaybookRunID: "run_id"})
+				writeRunChangeEvent(t, w, RunChange{Cursor: 2, Type: RunChangeUpdated, PlaybookRunID: "run_id", Payload: json.RawMessage(`{"id":"run_id"}`)})
+			default:
+				_, _ = fmt.Fprint(w, "id: 2\n\n")
+				writeRunChangeEvent(t, w, RunChange{Cursor: 3, Type: RunChangeFinished, PlaybookRunID: "run_id"})
+			}
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		errDone := errors.New("done")
+		var received []RunChange
+		err = c.PlaybookRuns.SubscribeChanges(context.Background(), RunChangesOptions{PlaybookRunID: "run_id"}, func(change RunChange) error {
+			received = append(received, change)
+			if change.Type == RunChangeFinished {
+				return errDone
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errDone)
+
+		require.Len(t, received, 3)
+		assert.Equal(t, RunChangeCreated, received[0].Type)
+		assert.JSONEq(t, `{"id":"run_id"}`, string(received[1].Payload))
+		assert.Equal(t, int64(3), received[2].Cursor)
+		assert.Equal(t, []string{"", "2"}, lastEventIDs)
+	})
+
+	t.Run("resumes from the start of an empty feed", func(t *testing.T) {
+		var mu sync.Mutex
+		var lastEventIDs []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+			connection := len(lastEventIDs)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "retry: 10\nid: 0\n\n")
+			if connection > 1 {
+				writeRunChangeEvent(t, w, RunChange{Cursor: 1, Type: RunChangeCreated})
+			}
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		errDone := errors.New("done")
+		err = c.PlaybookRuns.SubscribeChanges(context.Background(), RunChangesOptions{}, func(RunChange) error {
+			return errDone
+		})
+		require.ErrorIs(t, err, errDone)
+		assert.Equal(t, []string{"", "0"}, lastEventIDs)
+	})
+
+	t.Run("stops when the server rejects the subscription", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error":"run change feed is disabled"}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.PlaybookRuns.SubscribeChanges(context.Background(), RunChangesOptions{}, func(RunChange) error {
+			return nil
+		})
+		var errResponse *ErrorResponse
+		require.ErrorAs(t, err, &errResponse)
+		assert.Equal(t, http.StatusForbidden, errResponse.StatusCode)
+		assert.EqualError(t, errResponse.Err, "run change feed is disabled")
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "retry: 10\nid: 5\n\n")
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err = c.PlaybookRuns.SubscribeChanges(ctx, RunChangesOptions{}, func(RunChange) error {
+			return nil
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
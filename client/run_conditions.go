@@ -6,6 +6,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -57,3 +58,14 @@ func (s *RunConditionsService) List(ctx context.Context, runID string, page, per
 
 	return result, nil
 }
+
+// ListAll iterates over all the conditions of a run, fetching them perPage at a time.
+func (s *RunConditionsService) ListAll(ctx context.Context, runID string, perPage int) iter.Seq2[Condition, error] {
+	return paginate(func(page int) ([]Condition, bool, error) {
+		result, err := s.List(ctx, runID, page, perPage, RunConditionListOptions{})
+		if err != nil {
+			return nil, false, err
+		}
+		return result.Items, result.HasMore, nil
+	})
+}
@@ -36,6 +36,9 @@ func (s *SettingsService) Get(ctx context.Context) (*GlobalSettings, error) {
 }
 
 // Update the configured settings.
+//
+// Deprecated: the server has no endpoint to update the settings, so this always fails. Change
+// the settings through the plugin configuration instead.
 func (s *SettingsService) Update(ctx context.Context, settings GlobalSettings) error {
 	settingsURL := "settings"
 	req, err := s.client.newAPIRequest(http.MethodPut, settingsURL, settings)
@@ -0,0 +1,107 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateIfUnmodified(t *testing.T) {
+	t.Run("update run based on the current version", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPatch, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/runs/run_id", r.URL.Path)
+			assert.Equal(t, `"1000"`, r.Header.Get("If-Match"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"2000"`)
+			_, _ = fmt.Fprint(w, `{"id":"run_id","name":"new name","update_at":2000}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		name := "new name"
+		run, err := c.PlaybookRuns.UpdateIfUnmodified(context.Background(), "run_id", 1000, PlaybookRunUpdateOptions{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2000), run.UpdateAt)
+	})
+
+	t.Run("update run unconditionally", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-Match"))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id":"run_id"}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		_, err = c.PlaybookRuns.Update(context.Background(), "run_id", PlaybookRunUpdateOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("update stale run", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"2000"`)
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = fmt.Fprint(w, `{"error":"playbook run was modified since the version in If-Match","playbook_run":{"id":"run_id","name":"their name","update_at":2000}}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		name := "new name"
+		_, err = c.PlaybookRuns.UpdateIfUnmodified(context.Background(), "run_id", 1000, PlaybookRunUpdateOptions{Name: &name})
+
+		var staleErr *StaleVersionError
+		require.ErrorAs(t, err, &staleErr)
+		require.NotNil(t, staleErr.PlaybookRun)
+		assert.Equal(t, "their name", staleErr.PlaybookRun.Name)
+		assert.Equal(t, int64(2000), staleErr.PlaybookRun.UpdateAt)
+		assert.Nil(t, staleErr.Playbook)
+
+		var errResponse *ErrorResponse
+		require.ErrorAs(t, err, &errResponse)
+		assert.Equal(t, http.StatusPreconditionFailed, errResponse.StatusCode)
+		assert.EqualError(t, errResponse.Err, "playbook run was modified since the version in If-Match")
+	})
+
+	t.Run("update stale playbook", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/plugins/playbooks/api/v0/playbooks/playbook_id", r.URL.Path)
+			assert.Equal(t, `"1000"`, r.Header.Get("If-Match"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = fmt.Fprint(w, `{"error":"playbook was modified since the version in If-Match","playbook":{"id":"playbook_id","title":"their title","update_at":2000}}`)
+		}))
+		defer ts.Close()
+
+		c, err := newClient(ts.URL, ts.Client())
+		require.NoError(t, err)
+
+		err = c.Playbooks.UpdateIfUnmodified(context.Background(), Playbook{ID: "playbook_id", Title: "my title", UpdateAt: 1000})
+
+		var staleErr *StaleVersionError
+		require.ErrorAs(t, err, &staleErr)
+		require.NotNil(t, staleErr.Playbook)
+		assert.Equal(t, "their title", staleErr.Playbook.Title)
+		assert.Equal(t, int64(2000), staleErr.Playbook.UpdateAt)
+		assert.Nil(t, staleErr.PlaybookRun)
+	})
+}
@@ -73,6 +73,20 @@ type MoveSectionArgs struct {
 	DestChecklistIdx   int    `json:"dest_checklist_idx" jsonschema:"The zero-based destination section index"`
 }
 
+type SetItemAssigneeArgs struct {
+	RunID           string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	ChecklistNumber int    `json:"checklist_number" jsonschema:"The zero-based index of the checklist"`
+	ItemNumber      int    `json:"item_number" jsonschema:"The zero-based index of the item within the checklist"`
+	AssigneeID      string `json:"assignee_id,omitempty" jsonschema:"The ID of the user to assign the item to. Empty unassigns the item."`
+}
+
+type SetItemDueDateArgs struct {
+	RunID           string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	ChecklistNumber int    `json:"checklist_number" jsonschema:"The zero-based index of the checklist"`
+	ItemNumber      int    `json:"item_number" jsonschema:"The zero-based index of the item within the checklist"`
+	DueDate         int64  `json:"due_date" jsonschema:"The due date as a Unix timestamp in milliseconds. 0 clears the due date."`
+}
+
 // --- Tool registration ---
 
 func (p *PlaybooksToolProvider) addMCPHelperChecklistTools(server *mcphelper.Server) {
@@ -111,6 +125,14 @@ func (p *PlaybooksToolProvider) addMCPHelperChecklistTools(server *mcphelper.Ser
 	addMCPHelperTool(server, p.clientFactory, "move_section",
 		"Move a section within a playbook run. Source and destination indexes are zero-based. Example: {\"run_id\": \"abc123...\", \"source_checklist_idx\": 1, \"dest_checklist_idx\": 0}",
 		toolMoveSection)
+
+	addMCPHelperTool(server, p.clientFactory, "set_item_assignee",
+		"Assign a checklist item in a playbook run to a user, or unassign it with an empty assignee_id. Example: {\"run_id\": \"abc123...\", \"checklist_number\": 0, \"item_number\": 2, \"assignee_id\": \"def456...\"}",
+		toolSetItemAssignee)
+
+	addMCPHelperTool(server, p.clientFactory, "set_item_due_date",
+		"Set the due date of a checklist item in a playbook run as a Unix timestamp in milliseconds, or clear it with 0. Example: {\"run_id\": \"abc123...\", \"checklist_number\": 0, \"item_number\": 2, \"due_date\": 1767225600000}",
+		toolSetItemDueDate)
 }
 
 // --- Tool implementations ---
@@ -381,3 +403,63 @@ func toolMoveSection(ctx context.Context, client APIClient, args MoveSectionArgs
 
 	return fmt.Sprintf("Moved section %d to %d in run %s.", args.SourceChecklistIdx, args.DestChecklistIdx, args.RunID), nil
 }
+
+func toolSetItemAssignee(ctx context.Context, client APIClient, args SetItemAssigneeArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if err := validateIndex(args.ChecklistNumber, "checklist_number"); err != nil {
+		return "", err
+	}
+	if err := validateIndex(args.ItemNumber, "item_number"); err != nil {
+		return "", err
+	}
+	if args.AssigneeID != "" {
+		if err := validateID(args.AssigneeID, "assignee_id"); err != nil {
+			return "", err
+		}
+	}
+
+	body := map[string]string{
+		"assignee_id": args.AssigneeID,
+	}
+
+	endpoint := fmt.Sprintf("runs/%s/checklists/%d/item/%d/assignee", args.RunID, args.ChecklistNumber, args.ItemNumber)
+	if err := client.Put(ctx, endpoint, body, nil); err != nil {
+		return "", fmt.Errorf("failed to set item assignee: %w", err)
+	}
+
+	if args.AssigneeID == "" {
+		return fmt.Sprintf("Checklist item [%d][%d] in run %s unassigned.", args.ChecklistNumber, args.ItemNumber, args.RunID), nil
+	}
+	return fmt.Sprintf("Checklist item [%d][%d] in run %s assigned to %s.", args.ChecklistNumber, args.ItemNumber, args.RunID, args.AssigneeID), nil
+}
+
+func toolSetItemDueDate(ctx context.Context, client APIClient, args SetItemDueDateArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if err := validateIndex(args.ChecklistNumber, "checklist_number"); err != nil {
+		return "", err
+	}
+	if err := validateIndex(args.ItemNumber, "item_number"); err != nil {
+		return "", err
+	}
+	if err := validateTimestamp(args.DueDate, "due_date"); err != nil {
+		return "", err
+	}
+
+	body := map[string]int64{
+		"due_date": args.DueDate,
+	}
+
+	endpoint := fmt.Sprintf("runs/%s/checklists/%d/item/%d/duedate", args.RunID, args.ChecklistNumber, args.ItemNumber)
+	if err := client.Put(ctx, endpoint, body, nil); err != nil {
+		return "", fmt.Errorf("failed to set item due date: %w", err)
+	}
+
+	if args.DueDate == 0 {
+		return fmt.Sprintf("Due date of checklist item [%d][%d] in run %s cleared.", args.ChecklistNumber, args.ItemNumber, args.RunID), nil
+	}
+	return fmt.Sprintf("Due date of checklist item [%d][%d] in run %s set.", args.ChecklistNumber, args.ItemNumber, args.RunID), nil
+}
@@ -5,18 +5,28 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 )
 
 type fakeAPIClient struct {
-	run      playbookRunDetail
-	listRuns listRunsResponse
-
-	getEndpoint string
-	getParams   url.Values
+	run            playbookRunDetail
+	listRuns       listRunsResponse
+	retrospective  runRetrospective
+	statusUpdates  []statusUpdate
+	propertyFields []runPropertyField
+	propertyValues []runPropertyValue
+	runDocument    runDocument
+	playbook       playbookDefinition
+	todoDigest     todoDigest
+
+	getEndpoint  string
+	getEndpoints []string
+	getParams    url.Values
 
 	postEndpoint string
 	postBody     any
@@ -30,12 +40,27 @@ type fakeAPIClient struct {
 
 func (f *fakeAPIClient) Get(_ context.Context, endpoint string, params url.Values, result any) error {
 	f.getEndpoint = endpoint
+	f.getEndpoints = append(f.getEndpoints, endpoint)
 	f.getParams = params
 	switch v := result.(type) {
 	case *playbookRunDetail:
 		*v = f.run
 	case *listRunsResponse:
 		*v = f.listRuns
+	case *runRetrospective:
+		*v = f.retrospective
+	case *[]statusUpdate:
+		*v = f.statusUpdates
+	case *[]runPropertyField:
+		*v = f.propertyFields
+	case *[]runPropertyValue:
+		*v = f.propertyValues
+	case *runDocument:
+		*v = f.runDocument
+	case *playbookDefinition:
+		*v = f.playbook
+	case *todoDigest:
+		*v = f.todoDigest
 	case *map[string]any:
 		*v = map[string]any{"id": "abcdefghijklmnopqrstuvwxyz", "title": "Created playbook"}
 	default:
@@ -271,6 +296,142 @@ func TestRunToolEndpointsAndBodies(t *testing.T) {
 		}
 	})
 
+	t.Run("status updates newest first", func(t *testing.T) {
+		client := &fakeAPIClient{statusUpdates: []statusUpdate{
+			{ID: "1", CreateAt: 1000, Message: "first", AuthorUserName: "alice"},
+			{ID: "2", CreateAt: 3000, Message: "deleted", AuthorUserName: "alice", DeleteAt: 4000},
+			{ID: "3", CreateAt: 2000, Message: "second", AuthorUserName: "bob"},
+		}}
+		result, err := toolGetStatusUpdates(context.Background(), client, GetStatusUpdatesArgs{RunID: runID, Limit: 1})
+		if err != nil {
+			t.Fatalf("toolGetStatusUpdates returned error: %v", err)
+		}
+		if client.getEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/status-updates" {
+			t.Fatalf("unexpected endpoint: %s", client.getEndpoint)
+		}
+		if !strings.Contains(result, "second") || strings.Contains(result, "first") || strings.Contains(result, "deleted") {
+			t.Fatalf("unexpected result: %s", result)
+		}
+	})
+
+	t.Run("add to timeline", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		args := AddToTimelineArgs{RunID: runID, PostID: ownerID, Summary: " Customer report "}
+		if _, err := toolAddToTimeline(context.Background(), client, args); err != nil {
+			t.Fatalf("toolAddToTimeline returned error: %v", err)
+		}
+		if client.postEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/timeline" {
+			t.Fatalf("unexpected endpoint: %s", client.postEndpoint)
+		}
+		body, ok := client.postBody.(map[string]string)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.postBody)
+		}
+		if body["post_id"] != ownerID || body["summary"] != "Customer report" {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("draft retrospective keeps metrics", func(t *testing.T) {
+		metrics := json.RawMessage(`[{"metric_config_id":"m1","value":5}]`)
+		client := &fakeAPIClient{retrospective: runRetrospective{MetricsData: metrics}}
+		if _, err := toolDraftRetrospective(context.Background(), client, DraftRetrospectiveArgs{RunID: runID, Text: "Draft"}); err != nil {
+			t.Fatalf("toolDraftRetrospective returned error: %v", err)
+		}
+		if client.postEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/retrospective" {
+			t.Fatalf("unexpected endpoint: %s", client.postEndpoint)
+		}
+		body, ok := client.postBody.(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.postBody)
+		}
+		if body["retrospective"] != "Draft" || string(body["metrics"].(json.RawMessage)) != string(metrics) {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("draft retrospective rejects published retrospective", func(t *testing.T) {
+		client := &fakeAPIClient{retrospective: runRetrospective{RetrospectivePublishedAt: 1}}
+		if _, err := toolDraftRetrospective(context.Background(), client, DraftRetrospectiveArgs{RunID: runID, Text: "Draft"}); err == nil {
+			t.Fatal("expected error")
+		}
+		if client.postEndpoint != "" {
+			t.Fatalf("unexpected post to %s", client.postEndpoint)
+		}
+	})
+
+	t.Run("publish retrospective uses current draft", func(t *testing.T) {
+		client := &fakeAPIClient{retrospective: runRetrospective{Retrospective: "Saved draft"}}
+		if _, err := toolPublishRetrospective(context.Background(), client, PublishRetrospectiveArgs{RunID: runID}); err != nil {
+			t.Fatalf("toolPublishRetrospective returned error: %v", err)
+		}
+		if client.postEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/retrospective/publish" {
+			t.Fatalf("unexpected endpoint: %s", client.postEndpoint)
+		}
+		body, ok := client.postBody.(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.postBody)
+		}
+		if body["retrospective"] != "Saved draft" || string(body["metrics"].(json.RawMessage)) != "[]" {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("publish retrospective rejects empty report", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		if _, err := toolPublishRetrospective(context.Background(), client, PublishRetrospectiveArgs{RunID: runID}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("add participants", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		args := AddRunParticipantsArgs{RunID: runID, UserIDs: []string{ownerID}, ForceAddToChannel: true}
+		if _, err := toolAddRunParticipants(context.Background(), client, args); err != nil {
+			t.Fatalf("toolAddRunParticipants returned error: %v", err)
+		}
+		if client.postEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/participants" {
+			t.Fatalf("unexpected endpoint: %s", client.postEndpoint)
+		}
+		body, ok := client.postBody.(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.postBody)
+		}
+		if ids, _ := body["user_ids"].([]string); len(ids) != 1 || ids[0] != ownerID || body["force_add_to_channel"] != true {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+
+		if _, err := toolAddRunParticipants(context.Background(), client, AddRunParticipantsArgs{RunID: runID, UserIDs: []string{"bad"}}); err == nil {
+			t.Fatal("expected error for invalid user ID")
+		}
+	})
+
+	t.Run("remove participant", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		if _, err := toolRemoveRunParticipant(context.Background(), client, RemoveRunParticipantArgs{RunID: runID, UserID: ownerID}); err != nil {
+			t.Fatalf("toolRemoveRunParticipant returned error: %v", err)
+		}
+		if client.deleteEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/participants/bcdefghijklmnopqrstuvwxyza" {
+			t.Fatalf("unexpected endpoint: %s", client.deleteEndpoint)
+		}
+	})
+
+	t.Run("follow and unfollow", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		if _, err := toolFollowRun(context.Background(), client, FollowRunArgs{RunID: runID, Follow: true}); err != nil {
+			t.Fatalf("toolFollowRun returned error: %v", err)
+		}
+		if client.putEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/followers" {
+			t.Fatalf("unexpected endpoint: %s", client.putEndpoint)
+		}
+		if _, err := toolFollowRun(context.Background(), client, FollowRunArgs{RunID: runID}); err != nil {
+			t.Fatalf("toolFollowRun returned error: %v", err)
+		}
+		if client.deleteEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/followers" {
+			t.Fatalf("unexpected endpoint: %s", client.deleteEndpoint)
+		}
+	})
+
 	t.Run("change owner", func(t *testing.T) {
 		client := &fakeAPIClient{}
 		if _, err := toolChangeRunOwner(context.Background(), client, ChangeRunOwnerArgs{RunID: runID, OwnerID: ownerID}); err != nil {
@@ -289,6 +450,100 @@ func TestRunToolEndpointsAndBodies(t *testing.T) {
 	})
 }
 
+func TestToolStartRun(t *testing.T) {
+	const playbookID = "abcdefghijklmnopqrstuvwxyz"
+	const teamID = "bcdefghijklmnopqrstuvwxyza"
+	const channelID = "cdefghijklmnopqrstuvwxyzab"
+
+	t.Run("maps property names to playbook field IDs", func(t *testing.T) {
+		client := &fakeAPIClient{
+			playbook:       playbookDefinition{ID: playbookID, TeamID: teamID, ChannelMode: "create_new_channel"},
+			propertyFields: testRunPropertyFields(),
+		}
+		args := StartRunArgs{
+			PlaybookID: playbookID,
+			Name:       " API outage ",
+			Summary:    "Requests fail.",
+			Properties: map[string]any{
+				"Severity":   "sev1",
+				"services":   []any{"API", "Database"},
+				"Root cause": "Unknown",
+			},
+		}
+		if _, err := toolStartRun(context.Background(), client, args); err != nil {
+			t.Fatalf("toolStartRun returned error: %v", err)
+		}
+		if client.getEndpoint != "playbooks/abcdefghijklmnopqrstuvwxyz/property_fields" {
+			t.Fatalf("unexpected endpoint: %s", client.getEndpoint)
+		}
+		if client.postEndpoint != "runs" {
+			t.Fatalf("unexpected endpoint: %s", client.postEndpoint)
+		}
+		body, ok := client.postBody.(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.postBody)
+		}
+		if body["name"] != "API outage" || body["playbook_id"] != playbookID || body["team_id"] != teamID || body["summary"] != "Requests fail." {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+		if _, ok := body["channel_id"]; ok {
+			t.Fatalf("expected no channel_id for a new channel: %#v", body)
+		}
+		if _, ok := body["owner_user_id"]; ok {
+			t.Fatalf("expected the owner to be left to the server: %#v", body)
+		}
+		wantValues := map[string]any{
+			"severityfieldidabcdefghijk": "sev1optionidabcdefghijklm",
+			"servicesfieldidabcdefghij":  []string{"apioptionidabcdefghijklmn", "dboptionidabcdefghijklmno"},
+			"rootcausefieldidabcdefghi":  "Unknown",
+		}
+		if !reflect.DeepEqual(body["property_values"], wantValues) {
+			t.Fatalf("unexpected property values: %#v", body["property_values"])
+		}
+	})
+
+	t.Run("links the playbook channel by default", func(t *testing.T) {
+		client := &fakeAPIClient{playbook: playbookDefinition{ID: playbookID, TeamID: teamID, ChannelMode: "link_existing_channel", ChannelID: channelID}}
+		if _, err := toolStartRun(context.Background(), client, StartRunArgs{PlaybookID: playbookID, Name: "Run"}); err != nil {
+			t.Fatalf("toolStartRun returned error: %v", err)
+		}
+		body := client.postBody.(map[string]any)
+		if body["channel_id"] != channelID {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+		if _, ok := body["property_values"]; ok {
+			t.Fatalf("expected no property values: %#v", body)
+		}
+	})
+
+	t.Run("rejected inputs", func(t *testing.T) {
+		testCases := map[string]struct {
+			playbook playbookDefinition
+			args     StartRunArgs
+		}{
+			"invalid playbook ID":           {args: StartRunArgs{PlaybookID: "bad"}},
+			"invalid channel mode":          {args: StartRunArgs{PlaybookID: playbookID, ChannelMode: "reuse"}},
+			"link without channel":          {args: StartRunArgs{PlaybookID: playbookID, ChannelMode: "link_existing_channel"}},
+			"channel with new channel mode": {args: StartRunArgs{PlaybookID: playbookID, ChannelMode: "create_new_channel", ChannelID: channelID}},
+			"unknown property":              {args: StartRunArgs{PlaybookID: playbookID, Properties: map[string]any{"Impact": "High"}}},
+			"computed property":             {args: StartRunArgs{PlaybookID: playbookID, Properties: map[string]any{"Duration": "1h"}}},
+			"unknown option":                {args: StartRunArgs{PlaybookID: playbookID, Properties: map[string]any{"Severity": "SEV9"}}},
+			"invalid value type":            {args: StartRunArgs{PlaybookID: playbookID, Properties: map[string]any{"Severity": true}}},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				client := &fakeAPIClient{playbook: tc.playbook, propertyFields: testRunPropertyFields()}
+				if _, err := toolStartRun(context.Background(), client, tc.args); err == nil {
+					t.Fatal("expected error")
+				}
+				if client.postEndpoint != "" {
+					t.Fatalf("unexpected post to %s", client.postEndpoint)
+				}
+			})
+		}
+	})
+}
+
 func TestChecklistStructureToolEndpointsAndBodies(t *testing.T) {
 	const runID = "abcdefghijklmnopqrstuvwxyz"
 	const assigneeID = "bcdefghijklmnopqrstuvwxyza"
@@ -418,6 +673,71 @@ func TestChecklistStructureToolEndpointsAndBodies(t *testing.T) {
 	})
 }
 
+func TestChecklistItemAssigneeAndDueDateTools(t *testing.T) {
+	const runID = "abcdefghijklmnopqrstuvwxyz"
+	const assigneeID = "bcdefghijklmnopqrstuvwxyza"
+
+	t.Run("set assignee", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		args := SetItemAssigneeArgs{RunID: runID, ChecklistNumber: 1, ItemNumber: 2, AssigneeID: assigneeID}
+		if _, err := toolSetItemAssignee(context.Background(), client, args); err != nil {
+			t.Fatalf("toolSetItemAssignee returned error: %v", err)
+		}
+		if client.putEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/checklists/1/item/2/assignee" {
+			t.Fatalf("unexpected endpoint: %s", client.putEndpoint)
+		}
+		body, ok := client.putBody.(map[string]string)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.putBody)
+		}
+		if body["assignee_id"] != assigneeID {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		if _, err := toolSetItemAssignee(context.Background(), client, SetItemAssigneeArgs{RunID: runID}); err != nil {
+			t.Fatalf("toolSetItemAssignee returned error: %v", err)
+		}
+		body, ok := client.putBody.(map[string]string)
+		if !ok || body["assignee_id"] != "" {
+			t.Fatalf("unexpected body: %#v", client.putBody)
+		}
+	})
+
+	t.Run("set due date", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		args := SetItemDueDateArgs{RunID: runID, ChecklistNumber: 0, ItemNumber: 3, DueDate: 1767225600000}
+		if _, err := toolSetItemDueDate(context.Background(), client, args); err != nil {
+			t.Fatalf("toolSetItemDueDate returned error: %v", err)
+		}
+		if client.putEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/checklists/0/item/3/duedate" {
+			t.Fatalf("unexpected endpoint: %s", client.putEndpoint)
+		}
+		body, ok := client.putBody.(map[string]int64)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.putBody)
+		}
+		if body["due_date"] != 1767225600000 {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		client := &fakeAPIClient{}
+		if _, err := toolSetItemAssignee(context.Background(), client, SetItemAssigneeArgs{RunID: runID, AssigneeID: "bad"}); err == nil {
+			t.Fatal("expected error for invalid assignee_id")
+		}
+		if _, err := toolSetItemDueDate(context.Background(), client, SetItemDueDateArgs{RunID: runID, DueDate: -1}); err == nil {
+			t.Fatal("expected error for negative due_date")
+		}
+		if client.putEndpoint != "" {
+			t.Fatalf("unexpected put to %s", client.putEndpoint)
+		}
+	})
+}
+
 func TestMoveChecklistToolsValidation(t *testing.T) {
 	const runID = "abcdefghijklmnopqrstuvwxyz"
 
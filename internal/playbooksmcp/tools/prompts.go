@@ -0,0 +1,83 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-agents/public/mcphelper"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// --- Prompt registration ---
+
+func (p *PlaybooksToolProvider) addMCPHelperPrompts(server *mcphelper.Server) {
+	addMCPHelperPrompt(server, p.clientFactory, &mcp.Prompt{
+		Name:        "draft_status_update",
+		Title:       "Draft a status update",
+		Description: "Draft the next status update of a playbook run from its checklists, timeline and previous updates.",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "run_id", Description: "The ID of the playbook run", Required: true},
+			{Name: "notes", Description: "Optional notes to include in the update"},
+		},
+	}, promptDraftStatusUpdate)
+
+	addMCPHelperPrompt(server, p.clientFactory, &mcp.Prompt{
+		Name:        "summarize_retrospective",
+		Title:       "Summarize a run into a retrospective",
+		Description: "Summarize the timeline and status updates of a playbook run into a retrospective report.",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "run_id", Description: "The ID of the playbook run", Required: true},
+		},
+	}, promptSummarizeRetrospective)
+}
+
+// --- Prompt implementations ---
+
+func promptDraftStatusUpdate(ctx context.Context, client APIClient, args map[string]string) (string, error) {
+	runID := args["run_id"]
+	if err := validateID(runID, "run_id"); err != nil {
+		return "", err
+	}
+
+	document, err := getRunDocument(ctx, client, runID)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Draft the next status update for the playbook run described below.\n\n")
+	sb.WriteString("- Say what changed since the last status update, based on the checklist progress and the timeline.\n")
+	sb.WriteString("- Call out blockers, overdue tasks and the next steps with their owners.\n")
+	sb.WriteString("- Keep it short and use Markdown. Follow the structure of previous updates if there are any.\n")
+	if notes := strings.TrimSpace(args["notes"]); notes != "" {
+		fmt.Fprintf(&sb, "- Include these notes from the user: %s\n", notes)
+	}
+	fmt.Fprintf(&sb, "\nShow the draft to the user. Once approved, post it with the update_run_status tool and run_id %s.\n", runID)
+	fmt.Fprintf(&sb, "\n---\n\n%s", document)
+	return sb.String(), nil
+}
+
+func promptSummarizeRetrospective(ctx context.Context, client APIClient, args map[string]string) (string, error) {
+	runID := args["run_id"]
+	if err := validateID(runID, "run_id"); err != nil {
+		return "", err
+	}
+
+	document, err := getRunDocument(ctx, client, runID)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Summarize the playbook run described below into a retrospective report.\n\n")
+	sb.WriteString("- If the run already has a retrospective, keep its structure and fill it in.\n")
+	sb.WriteString("- Otherwise use these sections: Summary, Impact, Timeline of key events, What went well, What could be improved, Action items.\n")
+	sb.WriteString("- Base the report on the timeline, the status updates and the checklists. Don't invent facts; mark unknowns as such.\n")
+	fmt.Fprintf(&sb, "\nShow the report to the user. Once approved, save it with the draft_retrospective tool and run_id %s.\n", runID)
+	fmt.Fprintf(&sb, "\n---\n\n%s", document)
+	return sb.String(), nil
+}
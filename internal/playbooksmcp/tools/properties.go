@@ -0,0 +1,298 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-agents/public/mcphelper"
+)
+
+// --- Argument structs ---
+
+type GetRunPropertiesArgs struct {
+	RunID string `json:"run_id" jsonschema:"The ID of the playbook run"`
+}
+
+type SetRunPropertyArgs struct {
+	RunID  string   `json:"run_id" jsonschema:"The ID of the playbook run"`
+	Field  string   `json:"field" jsonschema:"The ID or the name of the run property field"`
+	Value  string   `json:"value,omitempty" jsonschema:"The value for text, number, date, select and user fields. Select options may be given by name, dates as YYYY-MM-DD or RFC 3339. Empty clears the value."`
+	Values []string `json:"values,omitempty" jsonschema:"The values for multiselect and multiuser fields. Options may be given by name."`
+}
+
+// --- API response types (subset of fields for formatting) ---
+
+type propertyOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type propertyFieldAttrs struct {
+	Options   []propertyOption `json:"options"`
+	ValueType string           `json:"value_type"`
+	Computed  json.RawMessage  `json:"computed,omitempty"`
+}
+
+type runPropertyField struct {
+	ID    string             `json:"id"`
+	Name  string             `json:"name"`
+	Type  string             `json:"type"`
+	Attrs propertyFieldAttrs `json:"attrs"`
+}
+
+type runPropertyValue struct {
+	FieldID string          `json:"field_id"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// --- Tool registration ---
+
+func (p *PlaybooksToolProvider) addMCPHelperPropertyTools(server *mcphelper.Server) {
+	addMCPHelperTool(server, p.clientFactory, "get_run_properties",
+		"Get the property fields of a playbook run with their current values, such as severity, impacted service or root cause. Select values are shown by option name. Example: {\"run_id\": \"abc123...\"}",
+		toolGetRunProperties)
+
+	addMCPHelperTool(server, p.clientFactory, "set_run_property",
+		"Set the value of a run property field, given by ID or name. Use value for single-value fields and values for multiselect and multiuser fields. Select options may be given by name. Computed fields can't be set. Example: {\"run_id\": \"abc123...\", \"field\": \"Severity\", \"value\": \"SEV1\"}",
+		toolSetRunProperty)
+}
+
+// --- Tool implementations ---
+
+func toolGetRunProperties(ctx context.Context, client APIClient, args GetRunPropertiesArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+
+	fields, err := getRunPropertyFields(ctx, client, args.RunID)
+	if err != nil {
+		return "", err
+	}
+
+	var values []runPropertyValue
+	if err := client.Get(ctx, fmt.Sprintf("runs/%s/property_values", args.RunID), nil, &values); err != nil {
+		return "", fmt.Errorf("failed to get run property values: %w", err)
+	}
+
+	return formatRunProperties(fields, values), nil
+}
+
+func toolSetRunProperty(ctx context.Context, client APIClient, args SetRunPropertyArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.Field) == "" {
+		return "", fmt.Errorf("field is required")
+	}
+
+	fields, err := getRunPropertyFields(ctx, client, args.RunID)
+	if err != nil {
+		return "", err
+	}
+	field, err := findSettablePropertyField(fields, args.Field)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := propertyValueForField(field, args.Value, args.Values)
+	if err != nil {
+		return "", err
+	}
+
+	body := map[string]any{
+		"value": value,
+	}
+	endpoint := fmt.Sprintf("runs/%s/property_fields/%s/value", args.RunID, field.ID)
+	if err := client.Put(ctx, endpoint, body, nil); err != nil {
+		return "", fmt.Errorf("failed to set run property: %w", err)
+	}
+
+	return fmt.Sprintf("Property '%s' of run %s updated.", field.Name, args.RunID), nil
+}
+
+func getRunPropertyFields(ctx context.Context, client APIClient, runID string) ([]runPropertyField, error) {
+	var fields []runPropertyField
+	if err := client.Get(ctx, fmt.Sprintf("runs/%s/property_fields", runID), nil, &fields); err != nil {
+		return nil, fmt.Errorf("failed to get run property fields: %w", err)
+	}
+	return fields, nil
+}
+
+// findRunPropertyField returns the field whose ID is nameOrID or, failing that, the field whose
+// name matches it case-insensitively.
+func findRunPropertyField(fields []runPropertyField, nameOrID string) (runPropertyField, error) {
+	nameOrID = strings.TrimSpace(nameOrID)
+	for _, field := range fields {
+		if field.ID == nameOrID {
+			return field, nil
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(strings.TrimSpace(field.Name), nameOrID) {
+			return field, nil
+		}
+	}
+	return runPropertyField{}, fmt.Errorf("run has no property field %q", nameOrID)
+}
+
+// findSettablePropertyField is findRunPropertyField rejecting computed fields, whose values are
+// derived by the server.
+func findSettablePropertyField(fields []runPropertyField, nameOrID string) (runPropertyField, error) {
+	field, err := findRunPropertyField(fields, nameOrID)
+	if err != nil {
+		return runPropertyField{}, err
+	}
+	if len(field.Attrs.Computed) > 0 && string(field.Attrs.Computed) != "null" {
+		return runPropertyField{}, fmt.Errorf("field %q is computed and can't be set", field.Name)
+	}
+	return field, nil
+}
+
+// propertyValueFromArg converts a JSON tool argument, a string, number or list of strings, to the
+// value the REST API expects for field.
+func propertyValueFromArg(field runPropertyField, arg any) (any, error) {
+	switch v := arg.(type) {
+	case string:
+		if field.Type == "multiselect" || field.Type == "multiuser" {
+			return propertyValueForField(field, "", []string{v})
+		}
+		return propertyValueForField(field, v, nil)
+	case float64:
+		return propertyValueForField(field, strconv.FormatFloat(v, 'f', -1, 64), nil)
+	case []string:
+		return propertyValueForField(field, "", v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			value, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("field %q takes a list of strings", field.Name)
+			}
+			values = append(values, value)
+		}
+		return propertyValueForField(field, "", values)
+	default:
+		return nil, fmt.Errorf("field %q takes a string or a list of strings", field.Name)
+	}
+}
+
+// propertyValueForField converts the tool arguments to the value the REST API expects for field:
+// option IDs for select fields, user IDs for user fields and strings otherwise.
+func propertyValueForField(field runPropertyField, value string, values []string) (any, error) {
+	switch field.Type {
+	case "select":
+		if value == "" {
+			return "", nil
+		}
+		return findOptionID(field, value)
+	case "multiselect":
+		optionIDs := make([]string, 0, len(values))
+		for _, v := range values {
+			optionID, err := findOptionID(field, v)
+			if err != nil {
+				return nil, err
+			}
+			optionIDs = append(optionIDs, optionID)
+		}
+		return optionIDs, nil
+	case "user":
+		if value == "" {
+			return "", nil
+		}
+		if err := validateID(value, "value"); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "multiuser":
+		if len(values) == 0 {
+			return []string{}, nil
+		}
+		if err := validateIDs(values, "values"); err != nil {
+			return nil, err
+		}
+		return values, nil
+	default:
+		if len(values) > 0 {
+			return nil, fmt.Errorf("field %q takes a single value, use value instead of values", field.Name)
+		}
+		return value, nil
+	}
+}
+
+func findOptionID(field runPropertyField, nameOrID string) (string, error) {
+	nameOrID = strings.TrimSpace(nameOrID)
+	names := make([]string, 0, len(field.Attrs.Options))
+	for _, option := range field.Attrs.Options {
+		if option.ID == nameOrID || strings.EqualFold(strings.TrimSpace(option.Name), nameOrID) {
+			return option.ID, nil
+		}
+		names = append(names, option.Name)
+	}
+	return "", fmt.Errorf("field %q has no option %q; valid options are: %s", field.Name, nameOrID, strings.Join(names, ", "))
+}
+
+// --- Formatting helpers ---
+
+func formatRunProperties(fields []runPropertyField, values []runPropertyValue) string {
+	if len(fields) == 0 {
+		return "This run has no property fields."
+	}
+
+	valueByFieldID := make(map[string]json.RawMessage, len(values))
+	for _, value := range values {
+		valueByFieldID[value.FieldID] = value.Value
+	}
+
+	var sb strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&sb, "- **%s** (ID: %s, type: %s): %s\n", field.Name, field.ID, field.Type, formatPropertyValue(field, valueByFieldID[field.ID]))
+	}
+	return sb.String()
+}
+
+func formatPropertyValue(field runPropertyField, raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "(empty)"
+	}
+
+	optionName := func(optionID string) string {
+		for _, option := range field.Attrs.Options {
+			if option.ID == optionID {
+				return option.Name
+			}
+		}
+		return optionID
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		if single == "" {
+			return "(empty)"
+		}
+		if field.Type == "select" {
+			return optionName(single)
+		}
+		return single
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(raw, &multiple); err == nil {
+		if len(multiple) == 0 {
+			return "(empty)"
+		}
+		if field.Type == "multiselect" {
+			for i, optionID := range multiple {
+				multiple[i] = optionName(optionID)
+			}
+		}
+		return strings.Join(multiple, ", ")
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testRunPropertyFields() []runPropertyField {
+	return []runPropertyField{
+		{
+			ID:   "severityfieldidabcdefghijk",
+			Name: "Severity",
+			Type: "select",
+			Attrs: propertyFieldAttrs{Options: []propertyOption{
+				{ID: "sev1optionidabcdefghijklm", Name: "SEV1"},
+				{ID: "sev2optionidabcdefghijklm", Name: "SEV2"},
+			}},
+		},
+		{
+			ID:   "servicesfieldidabcdefghij",
+			Name: "Services",
+			Type: "multiselect",
+			Attrs: propertyFieldAttrs{Options: []propertyOption{
+				{ID: "apioptionidabcdefghijklmn", Name: "API"},
+				{ID: "dboptionidabcdefghijklmno", Name: "Database"},
+			}},
+		},
+		{ID: "rootcausefieldidabcdefghi", Name: "Root cause", Type: "text"},
+		{ID: "durationfieldidabcdefghij", Name: "Duration", Type: "text", Attrs: propertyFieldAttrs{Computed: json.RawMessage(`{"type":"duration"}`)}},
+	}
+}
+
+func TestToolGetRunPropertiesShowsOptionNames(t *testing.T) {
+	client := &fakeAPIClient{
+		propertyFields: testRunPropertyFields(),
+		propertyValues: []runPropertyValue{
+			{FieldID: "severityfieldidabcdefghijk", Value: json.RawMessage(`"sev1optionidabcdefghijklm"`)},
+			{FieldID: "servicesfieldidabcdefghij", Value: json.RawMessage(`["apioptionidabcdefghijklmn","dboptionidabcdefghijklmno"]`)},
+		},
+	}
+
+	result, err := toolGetRunProperties(context.Background(), client, GetRunPropertiesArgs{RunID: "abcdefghijklmnopqrstuvwxyz"})
+	if err != nil {
+		t.Fatalf("toolGetRunProperties returned error: %v", err)
+	}
+	if client.getEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/property_values" {
+		t.Fatalf("unexpected endpoint: %s", client.getEndpoint)
+	}
+	for _, want := range []string{"**Severity**", ": SEV1", ": API, Database", "**Root cause**", "(empty)"} {
+		if !strings.Contains(result, want) {
+			t.Fatalf("expected %q in result:\n%s", want, result)
+		}
+	}
+}
+
+func TestToolSetRunProperty(t *testing.T) {
+	const runID = "abcdefghijklmnopqrstuvwxyz"
+
+	t.Run("select option by name", func(t *testing.T) {
+		client := &fakeAPIClient{propertyFields: testRunPropertyFields()}
+		if _, err := toolSetRunProperty(context.Background(), client, SetRunPropertyArgs{RunID: runID, Field: "severity", Value: "sev2"}); err != nil {
+			t.Fatalf("toolSetRunProperty returned error: %v", err)
+		}
+		if client.putEndpoint != "runs/abcdefghijklmnopqrstuvwxyz/property_fields/severityfieldidabcdefghijk/value" {
+			t.Fatalf("unexpected endpoint: %s", client.putEndpoint)
+		}
+		body, ok := client.putBody.(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected body type %T", client.putBody)
+		}
+		if body["value"] != "sev2optionidabcdefghijklm" {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("multiselect options by name", func(t *testing.T) {
+		client := &fakeAPIClient{propertyFields: testRunPropertyFields()}
+		args := SetRunPropertyArgs{RunID: runID, Field: "servicesfieldidabcdefghij", Values: []string{"Database", "API"}}
+		if _, err := toolSetRunProperty(context.Background(), client, args); err != nil {
+			t.Fatalf("toolSetRunProperty returned error: %v", err)
+		}
+		body := client.putBody.(map[string]any)
+		want := []string{"dboptionidabcdefghijklmno", "apioptionidabcdefghijklmn"}
+		if !reflect.DeepEqual(body["value"], want) {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("text value", func(t *testing.T) {
+		client := &fakeAPIClient{propertyFields: testRunPropertyFields()}
+		if _, err := toolSetRunProperty(context.Background(), client, SetRunPropertyArgs{RunID: runID, Field: "Root cause", Value: "Expired certificate"}); err != nil {
+			t.Fatalf("toolSetRunProperty returned error: %v", err)
+		}
+		body := client.putBody.(map[string]any)
+		if body["value"] != "Expired certificate" {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+	})
+
+	t.Run("rejected inputs", func(t *testing.T) {
+		testCases := map[string]SetRunPropertyArgs{
+			"unknown field":         {RunID: runID, Field: "Impact", Value: "High"},
+			"unknown option":        {RunID: runID, Field: "Severity", Value: "SEV9"},
+			"computed field":        {RunID: runID, Field: "Duration", Value: "1h"},
+			"values for text field": {RunID: runID, Field: "Root cause", Values: []string{"a", "b"}},
+			"missing field":         {RunID: runID},
+		}
+		for name, args := range testCases {
+			t.Run(name, func(t *testing.T) {
+				client := &fakeAPIClient{propertyFields: testRunPropertyFields()}
+				if _, err := toolSetRunProperty(context.Background(), client, args); err == nil {
+					t.Fatal("expected error")
+				}
+				if client.putEndpoint != "" {
+					t.Fatalf("unexpected put to %s", client.putEndpoint)
+				}
+			})
+		}
+	})
+
+	t.Run("unknown option lists valid options", func(t *testing.T) {
+		client := &fakeAPIClient{propertyFields: testRunPropertyFields()}
+		_, err := toolSetRunProperty(context.Background(), client, SetRunPropertyArgs{RunID: runID, Field: "Severity", Value: "SEV9"})
+		if err == nil || !strings.Contains(err.Error(), "SEV1, SEV2") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -42,9 +42,17 @@ func NewPlaybooksToolProvider(clientFactory ClientFactory) (*PlaybooksToolProvid
 func (p *PlaybooksToolProvider) ProvideMCPHelperTools(mcpServer *mcphelper.Server) {
 	p.addMCPHelperRunTools(mcpServer)
 	p.addMCPHelperChecklistTools(mcpServer)
+	p.addMCPHelperPropertyTools(mcpServer)
 	p.addMCPHelperPlaybookTools(mcpServer)
 }
 
+// ProvideMCPHelperResources registers the read-only resources and prompt templates with the
+// Agents MCP helper server.
+func (p *PlaybooksToolProvider) ProvideMCPHelperResources(mcpServer *mcphelper.Server) {
+	p.addMCPHelperResources(mcpServer)
+	p.addMCPHelperPrompts(mcpServer)
+}
+
 func addMCPHelperTool[In any](server *mcphelper.Server, factory ClientFactory, name, description string, handler func(context.Context, APIClient, In) (string, error)) {
 	tool := &mcp.Tool{Name: name, Description: description}
 	mcphelper.AddTool(server, tool, makeToolHandler(factory, handler))
@@ -65,3 +73,48 @@ func makeToolHandler[In any](factory ClientFactory, handler func(context.Context
 		}, nil, nil
 	}
 }
+
+func addMCPHelperResource(server *mcphelper.Server, factory ClientFactory, resource *mcp.Resource, handler func(context.Context, APIClient, string) (string, error)) {
+	mcphelper.AddResource(server, resource, makeResourceHandler(factory, resource.MIMEType, handler))
+}
+
+func addMCPHelperResourceTemplate(server *mcphelper.Server, factory ClientFactory, template *mcp.ResourceTemplate, handler func(context.Context, APIClient, string) (string, error)) {
+	mcphelper.AddResourceTemplate(server, template, makeResourceHandler(factory, template.MIMEType, handler))
+}
+
+func makeResourceHandler(factory ClientFactory, mimeType string, handler func(context.Context, APIClient, string) (string, error)) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		client, err := factory(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, err := handler(ctx, client, req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: mimeType, Text: result}},
+		}, nil
+	}
+}
+
+func addMCPHelperPrompt(server *mcphelper.Server, factory ClientFactory, prompt *mcp.Prompt, handler func(context.Context, APIClient, map[string]string) (string, error)) {
+	mcphelper.AddPrompt(server, prompt, makePromptHandler(factory, prompt.Description, handler))
+}
+
+func makePromptHandler(factory ClientFactory, description string, handler func(context.Context, APIClient, map[string]string) (string, error)) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		client, err := factory(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, err := handler(ctx, client, req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: description,
+			Messages:    []*mcp.PromptMessage{{Role: "user", Content: &mcp.TextContent{Text: result}}},
+		}, nil
+	}
+}
@@ -3,7 +3,12 @@
 
 package tools
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
 
 func TestNewPlaybooksToolProviderRejectsNilClientFactory(t *testing.T) {
 	provider, err := NewPlaybooksToolProvider(nil)
@@ -14,3 +19,46 @@ func TestNewPlaybooksToolProviderRejectsNilClientFactory(t *testing.T) {
 		t.Fatalf("expected nil provider, got %v", provider)
 	}
 }
+
+func TestMakeResourceHandler(t *testing.T) {
+	factory := func(context.Context) (APIClient, error) {
+		return &fakeAPIClient{}, nil
+	}
+	handler := makeResourceHandler(factory, markdownMIMEType, func(_ context.Context, _ APIClient, uri string) (string, error) {
+		return "# " + uri, nil
+	})
+
+	result, err := handler(context.Background(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: todoResourceURI}})
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if len(result.Contents) != 1 {
+		t.Fatalf("expected one content, got %d", len(result.Contents))
+	}
+	content := result.Contents[0]
+	if content.URI != todoResourceURI || content.MIMEType != markdownMIMEType || content.Text != "# playbooks://todo" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+}
+
+func TestMakePromptHandler(t *testing.T) {
+	factory := func(context.Context) (APIClient, error) {
+		return &fakeAPIClient{}, nil
+	}
+	handler := makePromptHandler(factory, "Draft an update", func(_ context.Context, _ APIClient, args map[string]string) (string, error) {
+		return "Run " + args["run_id"], nil
+	})
+
+	result, err := handler(context.Background(), &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Arguments: map[string]string{"run_id": "abc"}}})
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if result.Description != "Draft an update" || len(result.Messages) != 1 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	message := result.Messages[0]
+	text, ok := message.Content.(*mcp.TextContent)
+	if message.Role != "user" || !ok || text.Text != "Run abc" {
+		t.Fatalf("unexpected message: %#v", message)
+	}
+}
@@ -0,0 +1,453 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-agents/public/mcphelper"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	runResourcePrefix      = "playbooks://runs/"
+	playbookResourcePrefix = "playbooks://playbooks/"
+	todoResourceURI        = "playbooks://todo"
+
+	markdownMIMEType = "text/markdown"
+
+	// runDocumentStatusUpdates is the number of most recent status updates included in a run document.
+	runDocumentStatusUpdates = 5
+)
+
+// --- API response types (subset of fields for formatting) ---
+
+type timelineEvent struct {
+	EventAt       int64  `json:"event_at"`
+	DeleteAt      int64  `json:"delete_at"`
+	EventType     string `json:"event_type"`
+	Summary       string `json:"summary"`
+	Details       string `json:"details"`
+	SubjectUserID string `json:"subject_user_id"`
+	CreatorUserID string `json:"creator_user_id"`
+}
+
+type runDocument struct {
+	playbookRunDetail
+	Retrospective  string          `json:"retrospective"`
+	TimelineEvents []timelineEvent `json:"timeline_events"`
+}
+
+type playbookChecklistItem struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Command     string `json:"command"`
+}
+
+type playbookChecklist struct {
+	Title string                  `json:"title"`
+	Items []playbookChecklistItem `json:"items"`
+}
+
+type playbookMetric struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Target      *int64 `json:"target"`
+}
+
+type playbookDefinition struct {
+	ID                          string              `json:"id"`
+	Title                       string              `json:"title"`
+	Description                 string              `json:"description"`
+	TeamID                      string              `json:"team_id"`
+	Public                      bool                `json:"public"`
+	DeleteAt                    int64               `json:"delete_at"`
+	NumRuns                     int64               `json:"num_runs"`
+	ChannelMode                 string              `json:"channel_mode"`
+	ChannelID                   string              `json:"channel_id"`
+	Checklists                  []playbookChecklist `json:"checklists"`
+	RunSummaryTemplate          string              `json:"run_summary_template"`
+	StatusUpdateEnabled         bool                `json:"status_update_enabled"`
+	ReminderTimerDefaultSeconds int64               `json:"reminder_timer_default_seconds"`
+	ReminderMessageTemplate     string              `json:"reminder_message_template"`
+	RetrospectiveEnabled        bool                `json:"retrospective_enabled"`
+	RetrospectiveTemplate       string              `json:"retrospective_template"`
+	Metrics                     []playbookMetric    `json:"metrics"`
+}
+
+type runLink struct {
+	PlaybookRunID string `json:"playbook_run_id"`
+	Name          string `json:"name"`
+}
+
+type assignedTask struct {
+	ChecklistTitle string `json:"checklist_title"`
+	Title          string `json:"title"`
+	State          string `json:"state"`
+	DueDate        int64  `json:"due_date"`
+}
+
+type assignedRun struct {
+	runLink
+	Tasks []assignedTask `json:"tasks"`
+}
+
+type todoDigest struct {
+	OverdueRuns    []runLink     `json:"overdue_runs"`
+	AssignedRuns   []assignedRun `json:"assigned_runs"`
+	InProgressRuns []runLink     `json:"in_progress_runs"`
+}
+
+// --- Resource registration ---
+
+func (p *PlaybooksToolProvider) addMCPHelperResources(server *mcphelper.Server) {
+	addMCPHelperResourceTemplate(server, p.clientFactory, &mcp.ResourceTemplate{
+		Name:        "run",
+		Title:       "Playbook run",
+		Description: "The full state of a playbook run as a Markdown document: overview, summary, properties, checklists with item states, recent status updates, timeline and retrospective.",
+		URITemplate: runResourcePrefix + "{run_id}",
+		MIMEType:    markdownMIMEType,
+	}, readRunResource)
+
+	addMCPHelperResourceTemplate(server, p.clientFactory, &mcp.ResourceTemplate{
+		Name:        "playbook",
+		Title:       "Playbook",
+		Description: "The definition of a playbook as a Markdown document: checklists, status update and retrospective settings, metrics and property fields.",
+		URITemplate: playbookResourcePrefix + "{playbook_id}",
+		MIMEType:    markdownMIMEType,
+	}, readPlaybookResource)
+
+	addMCPHelperResource(server, p.clientFactory, &mcp.Resource{
+		Name:        "todo",
+		Title:       "Todo digest",
+		Description: "The current user's todo digest: runs with overdue status updates, tasks assigned to them and the runs they take part in.",
+		URI:         todoResourceURI,
+		MIMEType:    markdownMIMEType,
+	}, readTodoResource)
+}
+
+// --- Resource implementations ---
+
+func readRunResource(ctx context.Context, client APIClient, uri string) (string, error) {
+	runID, err := resourceID(uri, runResourcePrefix, "run_id")
+	if err != nil {
+		return "", err
+	}
+	return getRunDocument(ctx, client, runID)
+}
+
+func readPlaybookResource(ctx context.Context, client APIClient, uri string) (string, error) {
+	playbookID, err := resourceID(uri, playbookResourcePrefix, "playbook_id")
+	if err != nil {
+		return "", err
+	}
+
+	var playbook playbookDefinition
+	if err := client.Get(ctx, fmt.Sprintf("playbooks/%s", playbookID), nil, &playbook); err != nil {
+		return "", fmt.Errorf("failed to get playbook: %w", err)
+	}
+
+	var fields []runPropertyField
+	if err := client.Get(ctx, fmt.Sprintf("playbooks/%s/property_fields", playbookID), nil, &fields); err != nil {
+		return "", fmt.Errorf("failed to get playbook property fields: %w", err)
+	}
+
+	return formatPlaybookDocument(playbook, fields, client.GetPlaybookURL(playbook.ID)), nil
+}
+
+func readTodoResource(ctx context.Context, client APIClient, _ string) (string, error) {
+	var digest todoDigest
+	if err := client.Get(ctx, "runs/todo", nil, &digest); err != nil {
+		return "", fmt.Errorf("failed to get todo digest: %w", err)
+	}
+	return formatTodoDigest(digest), nil
+}
+
+// getRunDocument gathers the state of a run, its status updates and its properties into a
+// Markdown document.
+func getRunDocument(ctx context.Context, client APIClient, runID string) (string, error) {
+	var run runDocument
+	if err := client.Get(ctx, fmt.Sprintf("runs/%s", runID), nil, &run); err != nil {
+		return "", fmt.Errorf("failed to get run: %w", err)
+	}
+
+	var updates []statusUpdate
+	if err := client.Get(ctx, fmt.Sprintf("runs/%s/status-updates", runID), nil, &updates); err != nil {
+		return "", fmt.Errorf("failed to get status updates: %w", err)
+	}
+
+	fields, err := getRunPropertyFields(ctx, client, runID)
+	if err != nil {
+		return "", err
+	}
+	var values []runPropertyValue
+	if len(fields) > 0 {
+		if err := client.Get(ctx, fmt.Sprintf("runs/%s/property_values", runID), nil, &values); err != nil {
+			return "", fmt.Errorf("failed to get run property values: %w", err)
+		}
+	}
+
+	return formatRunDocument(run, updates, fields, values), nil
+}
+
+// resourceID extracts and validates the ID following prefix in a resource URI.
+func resourceID(uri, prefix, name string) (string, error) {
+	id, ok := strings.CutPrefix(uri, prefix)
+	if !ok {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	if err := validateID(id, name); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// --- Formatting helpers ---
+
+func formatRunDocument(run runDocument, updates []statusUpdate, fields []runPropertyField, values []runPropertyValue) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", run.Name)
+	fmt.Fprintf(&sb, "- **ID:** %s\n", run.ID)
+	fmt.Fprintf(&sb, "- **Status:** %s\n", run.CurrentStatus)
+	if run.Type != "" {
+		fmt.Fprintf(&sb, "- **Type:** %s\n", run.Type)
+	}
+	if run.PlaybookID != "" {
+		fmt.Fprintf(&sb, "- **Playbook ID:** %s\n", run.PlaybookID)
+	}
+	fmt.Fprintf(&sb, "- **Owner ID:** %s\n", run.OwnerUserID)
+	if len(run.ParticipantIDs) > 0 {
+		fmt.Fprintf(&sb, "- **Participant IDs:** %s\n", strings.Join(run.ParticipantIDs, ", "))
+	}
+	fmt.Fprintf(&sb, "- **Channel ID:** %s\n", run.ChannelID)
+	fmt.Fprintf(&sb, "- **Started:** %s\n", formatTimestamp(run.CreateAt))
+	if run.EndAt > 0 {
+		fmt.Fprintf(&sb, "- **Finished:** %s\n", formatTimestamp(run.EndAt))
+	}
+	if run.LastStatusUpdateAt > 0 {
+		fmt.Fprintf(&sb, "- **Last status update:** %s\n", formatTimestamp(run.LastStatusUpdateAt))
+	}
+
+	if strings.TrimSpace(run.Summary) != "" {
+		fmt.Fprintf(&sb, "\n## Summary\n\n%s\n", strings.TrimSpace(run.Summary))
+	}
+
+	if len(fields) > 0 {
+		fmt.Fprintf(&sb, "\n## Properties\n\n%s", formatRunProperties(fields, values))
+	}
+
+	sb.WriteString("\n## Checklists\n")
+	if len(run.Checklists) == 0 {
+		sb.WriteString("\nThis run has no checklists.\n")
+	}
+	for i, cl := range run.Checklists {
+		fmt.Fprintf(&sb, "\n### [%d] %s\n\n", i, cl.Title)
+		if len(cl.Items) == 0 {
+			sb.WriteString("No items.\n")
+		}
+		for j, item := range cl.Items {
+			fmt.Fprintf(&sb, "- %s [%d] %s%s\n", checklistItemBox(item.State), j, item.Title, checklistItemDetails(item))
+		}
+	}
+
+	sb.WriteString("\n## Status updates\n\n")
+	sb.WriteString(formatStatusUpdates(updates, runDocumentStatusUpdates))
+	sb.WriteString("\n")
+
+	sb.WriteString("\n## Timeline\n\n")
+	sb.WriteString(formatTimeline(run.TimelineEvents))
+
+	if strings.TrimSpace(run.Retrospective) != "" {
+		fmt.Fprintf(&sb, "\n## Retrospective\n\n%s\n", strings.TrimSpace(run.Retrospective))
+	}
+
+	return sb.String()
+}
+
+func checklistItemBox(state string) string {
+	switch state {
+	case "closed":
+		return "[x]"
+	case "skipped":
+		return "[-]"
+	default:
+		return "[ ]"
+	}
+}
+
+func checklistItemDetails(item checklistItem) string {
+	var details []string
+	if item.State == "skipped" {
+		details = append(details, "skipped")
+	}
+	if item.AssigneeID != "" {
+		details = append(details, "assignee ID: "+item.AssigneeID)
+	}
+	if item.DueDate > 0 {
+		details = append(details, "due: "+formatTimestamp(item.DueDate))
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+func formatTimeline(events []timelineEvent) string {
+	var sb strings.Builder
+	for _, event := range events {
+		if event.DeleteAt != 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s — %s", formatTimestamp(event.EventAt), event.EventType)
+		if event.Summary != "" {
+			fmt.Fprintf(&sb, ": %s", event.Summary)
+		}
+		sb.WriteString("\n")
+	}
+	if sb.Len() == 0 {
+		return "No timeline events.\n"
+	}
+	return sb.String()
+}
+
+func formatPlaybookDocument(playbook playbookDefinition, fields []runPropertyField, url string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", playbook.Title)
+	fmt.Fprintf(&sb, "- **ID:** %s\n", playbook.ID)
+	fmt.Fprintf(&sb, "- **URL:** %s\n", url)
+	fmt.Fprintf(&sb, "- **Team ID:** %s\n", playbook.TeamID)
+	fmt.Fprintf(&sb, "- **Public:** %t\n", playbook.Public)
+	fmt.Fprintf(&sb, "- **Runs:** %d\n", playbook.NumRuns)
+	if playbook.ChannelMode != "" {
+		fmt.Fprintf(&sb, "- **Channel mode:** %s\n", playbook.ChannelMode)
+	}
+	if playbook.DeleteAt > 0 {
+		fmt.Fprintf(&sb, "- **Archived:** %s\n", formatTimestamp(playbook.DeleteAt))
+	}
+
+	if strings.TrimSpace(playbook.Description) != "" {
+		fmt.Fprintf(&sb, "\n## Description\n\n%s\n", strings.TrimSpace(playbook.Description))
+	}
+
+	sb.WriteString("\n## Checklists\n")
+	if len(playbook.Checklists) == 0 {
+		sb.WriteString("\nThis playbook has no checklists.\n")
+	}
+	for i, cl := range playbook.Checklists {
+		fmt.Fprintf(&sb, "\n### [%d] %s\n\n", i, cl.Title)
+		if len(cl.Items) == 0 {
+			sb.WriteString("No items.\n")
+		}
+		for j, item := range cl.Items {
+			fmt.Fprintf(&sb, "- [%d] %s\n", j, item.Title)
+			if item.Command != "" {
+				fmt.Fprintf(&sb, "  - Command: `%s`\n", item.Command)
+			}
+			if description := strings.TrimSpace(item.Description); description != "" {
+				fmt.Fprintf(&sb, "  - %s\n", strings.ReplaceAll(description, "\n", " "))
+			}
+		}
+	}
+
+	sb.WriteString("\n## Status updates\n\n")
+	if playbook.StatusUpdateEnabled {
+		fmt.Fprintf(&sb, "Enabled, every %s by default.\n", time.Duration(playbook.ReminderTimerDefaultSeconds)*time.Second)
+		if template := strings.TrimSpace(playbook.ReminderMessageTemplate); template != "" {
+			fmt.Fprintf(&sb, "\nTemplate:\n\n%s\n", template)
+		}
+	} else {
+		sb.WriteString("Disabled.\n")
+	}
+
+	sb.WriteString("\n## Retrospective\n\n")
+	if playbook.RetrospectiveEnabled {
+		sb.WriteString("Enabled.\n")
+		if template := strings.TrimSpace(playbook.RetrospectiveTemplate); template != "" {
+			fmt.Fprintf(&sb, "\nTemplate:\n\n%s\n", template)
+		}
+		for _, metric := range playbook.Metrics {
+			fmt.Fprintf(&sb, "\n- Metric **%s** (%s)", metric.Title, metric.Type)
+			if metric.Target != nil {
+				fmt.Fprintf(&sb, ", target %d", *metric.Target)
+			}
+			if metric.Description != "" {
+				fmt.Fprintf(&sb, ": %s", metric.Description)
+			}
+		}
+		if len(playbook.Metrics) > 0 {
+			sb.WriteString("\n")
+		}
+	} else {
+		sb.WriteString("Disabled.\n")
+	}
+
+	if template := strings.TrimSpace(playbook.RunSummaryTemplate); template != "" {
+		fmt.Fprintf(&sb, "\n## Run summary template\n\n%s\n", template)
+	}
+
+	if len(fields) > 0 {
+		sb.WriteString("\n## Property fields\n\n")
+		for _, field := range fields {
+			fmt.Fprintf(&sb, "- **%s** (ID: %s, type: %s)", field.Name, field.ID, field.Type)
+			if len(field.Attrs.Options) > 0 {
+				names := make([]string, 0, len(field.Attrs.Options))
+				for _, option := range field.Attrs.Options {
+					names = append(names, option.Name)
+				}
+				fmt.Fprintf(&sb, ": %s", strings.Join(names, ", "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func formatTodoDigest(digest todoDigest) string {
+	var sb strings.Builder
+	sb.WriteString("# Todo digest\n")
+
+	sb.WriteString("\n## Runs with overdue status updates\n\n")
+	if len(digest.OverdueRuns) == 0 {
+		sb.WriteString("None.\n")
+	}
+	for _, run := range digest.OverdueRuns {
+		fmt.Fprintf(&sb, "- %s (ID: %s)\n", run.Name, run.PlaybookRunID)
+	}
+
+	sb.WriteString("\n## Assigned tasks\n")
+	if len(digest.AssignedRuns) == 0 {
+		sb.WriteString("\nNone.\n")
+	}
+	for _, run := range digest.AssignedRuns {
+		fmt.Fprintf(&sb, "\n### %s (ID: %s)\n\n", run.Name, run.PlaybookRunID)
+		for _, task := range run.Tasks {
+			fmt.Fprintf(&sb, "- %s %s (checklist: %s", checklistItemBox(task.State), task.Title, task.ChecklistTitle)
+			if task.DueDate > 0 {
+				fmt.Fprintf(&sb, ", due: %s", formatTimestamp(task.DueDate))
+			}
+			sb.WriteString(")\n")
+		}
+	}
+
+	sb.WriteString("\n## Runs in progress\n\n")
+	if len(digest.InProgressRuns) == 0 {
+		sb.WriteString("None.\n")
+	}
+	for _, run := range digest.InProgressRuns {
+		fmt.Fprintf(&sb, "- %s (ID: %s)\n", run.Name, run.PlaybookRunID)
+	}
+
+	return sb.String()
+}
+
+func formatTimestamp(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
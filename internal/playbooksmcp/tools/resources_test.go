@@ -0,0 +1,199 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testRunDocument() runDocument {
+	return runDocument{
+		playbookRunDetail: playbookRunDetail{
+			ID:            "abcdefghijklmnopqrstuvwxyz",
+			Name:          "API outage",
+			Summary:       "Requests to the API fail.",
+			CurrentStatus: "InProgress",
+			OwnerUserID:   "bcdefghijklmnopqrstuvwxyza",
+			ChannelID:     "cdefghijklmnopqrstuvwxyzab",
+			CreateAt:      1767225600000,
+			Checklists: []checklist{{
+				Title: "Triage",
+				Items: []checklistItem{
+					{Title: "Page on-call", State: "closed"},
+					{Title: "Notify customers", State: "skipped"},
+					{Title: "Find root cause", AssigneeID: "bcdefghijklmnopqrstuvwxyza", DueDate: 1767229200000},
+				},
+			}},
+		},
+		Retrospective: "## Summary",
+		TimelineEvents: []timelineEvent{
+			{EventAt: 1767225600000, EventType: "incident_created", Summary: "Run started"},
+			{EventAt: 1767225700000, EventType: "event_from_post", Summary: "Removed", DeleteAt: 1767225800000},
+		},
+	}
+}
+
+func TestReadRunResource(t *testing.T) {
+	client := &fakeAPIClient{
+		runDocument:    testRunDocument(),
+		statusUpdates:  []statusUpdate{{CreateAt: 1767227400000, Message: "Mitigated.", AuthorUserName: "alice"}},
+		propertyFields: testRunPropertyFields(),
+		propertyValues: []runPropertyValue{{FieldID: "severityfieldidabcdefghijk", Value: json.RawMessage(`"sev1optionidabcdefghijklm"`)}},
+	}
+
+	document, err := readRunResource(context.Background(), client, "playbooks://runs/abcdefghijklmnopqrstuvwxyz")
+	if err != nil {
+		t.Fatalf("readRunResource returned error: %v", err)
+	}
+
+	wantEndpoints := []string{
+		"runs/abcdefghijklmnopqrstuvwxyz",
+		"runs/abcdefghijklmnopqrstuvwxyz/status-updates",
+		"runs/abcdefghijklmnopqrstuvwxyz/property_fields",
+		"runs/abcdefghijklmnopqrstuvwxyz/property_values",
+	}
+	if !reflect.DeepEqual(client.getEndpoints, wantEndpoints) {
+		t.Fatalf("unexpected endpoints: %v", client.getEndpoints)
+	}
+
+	for _, want := range []string{
+		"# API outage",
+		"Requests to the API fail.",
+		"**Severity** (ID: severityfieldidabcdefghijk, type: select): SEV1",
+		"- [x] [0] Page on-call",
+		"- [-] [1] Notify customers (skipped)",
+		"- [ ] [2] Find root cause (assignee ID: bcdefghijklmnopqrstuvwxyza, due: 2026-01-01T01:00:00Z)",
+		"by @alice\n\nMitigated.",
+		"- 2026-01-01T00:00:00Z — incident_created: Run started",
+		"## Retrospective\n\n## Summary",
+	} {
+		if !strings.Contains(document, want) {
+			t.Fatalf("expected %q in document:\n%s", want, document)
+		}
+	}
+	if strings.Contains(document, "Removed") {
+		t.Fatalf("expected deleted timeline events to be left out:\n%s", document)
+	}
+}
+
+func TestReadResourceRejectsInvalidURI(t *testing.T) {
+	client := &fakeAPIClient{}
+	if _, err := readRunResource(context.Background(), client, "playbooks://runs/invalid"); err == nil {
+		t.Fatal("expected error for invalid run ID")
+	}
+	if _, err := readPlaybookResource(context.Background(), client, "playbooks://runs/abcdefghijklmnopqrstuvwxyz"); err == nil {
+		t.Fatal("expected error for run URI passed as playbook")
+	}
+	if len(client.getEndpoints) != 0 {
+		t.Fatalf("unexpected requests: %v", client.getEndpoints)
+	}
+}
+
+func TestReadPlaybookResource(t *testing.T) {
+	target := int64(30)
+	client := &fakeAPIClient{
+		playbook: playbookDefinition{
+			ID:                          "abcdefghijklmnopqrstuvwxyz",
+			Title:                       "Incident response",
+			Description:                 "Handle production incidents.",
+			Checklists:                  []playbookChecklist{{Title: "Triage", Items: []playbookChecklistItem{{Title: "Page on-call", Command: "/page oncall"}}}},
+			StatusUpdateEnabled:         true,
+			ReminderTimerDefaultSeconds: 3600,
+			RetrospectiveEnabled:        true,
+			Metrics:                     []playbookMetric{{Title: "Time to resolve", Type: "metric_duration", Target: &target}},
+		},
+		propertyFields: testRunPropertyFields()[:1],
+	}
+
+	document, err := readPlaybookResource(context.Background(), client, "playbooks://playbooks/abcdefghijklmnopqrstuvwxyz")
+	if err != nil {
+		t.Fatalf("readPlaybookResource returned error: %v", err)
+	}
+	if client.getEndpoint != "playbooks/abcdefghijklmnopqrstuvwxyz/property_fields" {
+		t.Fatalf("unexpected endpoint: %s", client.getEndpoint)
+	}
+
+	for _, want := range []string{
+		"# Incident response",
+		"https://mattermost.example.com/playbooks/playbooks/abcdefghijklmnopqrstuvwxyz",
+		"- [0] Page on-call\n  - Command: `/page oncall`",
+		"Enabled, every 1h0m0s by default.",
+		"Metric **Time to resolve** (metric_duration), target 30",
+		"**Severity** (ID: severityfieldidabcdefghijk, type: select): SEV1, SEV2",
+	} {
+		if !strings.Contains(document, want) {
+			t.Fatalf("expected %q in document:\n%s", want, document)
+		}
+	}
+}
+
+func TestReadTodoResource(t *testing.T) {
+	client := &fakeAPIClient{
+		todoDigest: todoDigest{
+			AssignedRuns: []assignedRun{{
+				runLink: runLink{PlaybookRunID: "abcdefghijklmnopqrstuvwxyz", Name: "API outage"},
+				Tasks:   []assignedTask{{ChecklistTitle: "Triage", Title: "Find root cause", DueDate: 1767229200000}},
+			}},
+			InProgressRuns: []runLink{{PlaybookRunID: "abcdefghijklmnopqrstuvwxyz", Name: "API outage"}},
+		},
+	}
+
+	document, err := readTodoResource(context.Background(), client, todoResourceURI)
+	if err != nil {
+		t.Fatalf("readTodoResource returned error: %v", err)
+	}
+	if client.getEndpoint != "runs/todo" {
+		t.Fatalf("unexpected endpoint: %s", client.getEndpoint)
+	}
+
+	for _, want := range []string{
+		"## Runs with overdue status updates\n\nNone.",
+		"### API outage (ID: abcdefghijklmnopqrstuvwxyz)\n\n- [ ] Find root cause (checklist: Triage, due: 2026-01-01T01:00:00Z)",
+		"## Runs in progress\n\n- API outage (ID: abcdefghijklmnopqrstuvwxyz)",
+	} {
+		if !strings.Contains(document, want) {
+			t.Fatalf("expected %q in document:\n%s", want, document)
+		}
+	}
+}
+
+func TestPrompts(t *testing.T) {
+	newClient := func() *fakeAPIClient {
+		return &fakeAPIClient{runDocument: testRunDocument()}
+	}
+
+	t.Run("draft status update", func(t *testing.T) {
+		text, err := promptDraftStatusUpdate(context.Background(), newClient(), map[string]string{"run_id": "abcdefghijklmnopqrstuvwxyz", "notes": "Fix deployed"})
+		if err != nil {
+			t.Fatalf("promptDraftStatusUpdate returned error: %v", err)
+		}
+		for _, want := range []string{"update_run_status", "Fix deployed", "# API outage"} {
+			if !strings.Contains(text, want) {
+				t.Fatalf("expected %q in prompt:\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("summarize retrospective", func(t *testing.T) {
+		text, err := promptSummarizeRetrospective(context.Background(), newClient(), map[string]string{"run_id": "abcdefghijklmnopqrstuvwxyz"})
+		if err != nil {
+			t.Fatalf("promptSummarizeRetrospective returned error: %v", err)
+		}
+		for _, want := range []string{"draft_retrospective", "incident_created: Run started"} {
+			if !strings.Contains(text, want) {
+				t.Fatalf("expected %q in prompt:\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("missing run ID", func(t *testing.T) {
+		if _, err := promptDraftStatusUpdate(context.Background(), newClient(), nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
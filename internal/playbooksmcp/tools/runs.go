@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/mattermost/mattermost-plugin-agents/public/mcphelper"
@@ -23,6 +24,10 @@ type ListRunsArgs struct {
 	Types       []string `json:"types,omitempty" jsonschema:"Filter by run types. Valid values: playbook, channelChecklist"`
 	Page        int      `json:"page,omitempty" jsonschema:"Page number (0-indexed)"`
 	PerPage     int      `json:"per_page,omitempty" jsonschema:"Number of results per page (max 100)"`
+
+	PropertyFilter      string `json:"property_filter,omitempty" jsonschema:"Filter by property values with a JSON condition expression. Comparisons are is, isNot, gt and lt (gt and lt for number and date fields), combined with and/or. field_id is a playbook or team property field ID; select values may be option names. Example: {\"and\": [{\"is\": {\"field_id\": \"<severity field ID>\", \"value\": [\"SEV1\", \"SEV2\"]}}, {\"is\": {\"field_id\": \"<service field ID>\", \"value\": \"payments\"}}]}"`
+	SortPropertyFieldID string `json:"sort_property_field_id,omitempty" jsonschema:"Sort by the value of this playbook or team property field. Runs without a value come last."`
+	Direction           string `json:"direction,omitempty" jsonschema:"Sort direction: asc or desc (default: asc)"`
 }
 
 type CreateChecklistArgs struct {
@@ -66,6 +71,53 @@ type ChangeRunOwnerArgs struct {
 	OwnerID string `json:"owner_id" jsonschema:"The user ID of the new owner"`
 }
 
+type StartRunArgs struct {
+	PlaybookID  string         `json:"playbook_id" jsonschema:"The ID of the playbook to run"`
+	Name        string         `json:"name,omitempty" jsonschema:"Name of the run. Optional when the playbook generates run names from a template."`
+	OwnerID     string         `json:"owner_id,omitempty" jsonschema:"Optional user ID of the run owner. Defaults to the playbook's default owner or the current user."`
+	ChannelMode string         `json:"channel_mode,omitempty" jsonschema:"create_new_channel or link_existing_channel (default: the playbook's setting)"`
+	ChannelID   string         `json:"channel_id,omitempty" jsonschema:"The channel to link the run to with link_existing_channel (default: the playbook's channel)"`
+	Summary     string         `json:"summary,omitempty" jsonschema:"Optional summary of the run (supports Markdown)"`
+	Properties  map[string]any `json:"properties,omitempty" jsonschema:"Initial property values keyed by field name or ID. Use a string for single-value fields and a list of strings for multiselect and multiuser fields. Select options may be given by name."`
+}
+
+type GetStatusUpdatesArgs struct {
+	RunID string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	Limit int    `json:"limit,omitempty" jsonschema:"Maximum number of status updates to return, newest first (default: 10)"`
+}
+
+type AddToTimelineArgs struct {
+	RunID   string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	PostID  string `json:"post_id" jsonschema:"The ID of the post to add to the run timeline"`
+	Summary string `json:"summary,omitempty" jsonschema:"Optional title of the timeline event"`
+}
+
+type DraftRetrospectiveArgs struct {
+	RunID string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	Text  string `json:"text" jsonschema:"The retrospective report (supports Markdown)"`
+}
+
+type PublishRetrospectiveArgs struct {
+	RunID string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	Text  string `json:"text,omitempty" jsonschema:"Optional final retrospective report. If omitted, the current draft is published."`
+}
+
+type AddRunParticipantsArgs struct {
+	RunID             string   `json:"run_id" jsonschema:"The ID of the playbook run"`
+	UserIDs           []string `json:"user_ids" jsonschema:"The IDs of the users to add as participants"`
+	ForceAddToChannel bool     `json:"force_add_to_channel,omitempty" jsonschema:"If true the users are also added to the run channel"`
+}
+
+type RemoveRunParticipantArgs struct {
+	RunID  string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	UserID string `json:"user_id" jsonschema:"The ID of the participant to remove"`
+}
+
+type FollowRunArgs struct {
+	RunID  string `json:"run_id" jsonschema:"The ID of the playbook run"`
+	Follow bool   `json:"follow" jsonschema:"True to follow the run, false to stop following it"`
+}
+
 // --- API response types (subset of fields for formatting) ---
 
 type playbookRunSummary struct {
@@ -121,11 +173,25 @@ type playbookRunDetail struct {
 	Checklists         []checklist `json:"checklists"`
 }
 
+type statusUpdate struct {
+	ID             string `json:"id"`
+	CreateAt       int64  `json:"create_at"`
+	DeleteAt       int64  `json:"delete_at"`
+	Message        string `json:"message"`
+	AuthorUserName string `json:"author_user_name"`
+}
+
+type runRetrospective struct {
+	Retrospective            string          `json:"retrospective"`
+	RetrospectivePublishedAt int64           `json:"retrospective_published_at"`
+	MetricsData              json.RawMessage `json:"metrics_data"`
+}
+
 // --- Tool registration ---
 
 func (p *PlaybooksToolProvider) addMCPHelperRunTools(server *mcphelper.Server) {
 	addMCPHelperTool(server, p.clientFactory, "list_runs",
-		"List playbook runs and channel checklists with optional filters. Returns a paginated list showing ID, name, type, status, owner, and timestamps. Use status='InProgress' to see active runs and type='channelChecklist' to list checklists. Example: {\"status\": \"InProgress\", \"type\": \"channelChecklist\", \"per_page\": 5}",
+		"List playbook runs and channel checklists with optional filters. Returns a paginated list showing ID, name, type, status, owner, and timestamps. Use status='InProgress' to see active runs and type='channelChecklist' to list checklists. Use property_filter to filter by property values and sort_property_field_id to sort by one. Example: {\"status\": \"InProgress\", \"type\": \"channelChecklist\", \"per_page\": 5}",
 		toolListRuns)
 
 	addMCPHelperTool(server, p.clientFactory, "create_checklist",
@@ -137,16 +203,48 @@ func (p *PlaybooksToolProvider) addMCPHelperRunTools(server *mcphelper.Server) {
 		toolGetRun)
 
 	addMCPHelperTool(server, p.clientFactory, "update_run_status",
-		"Post a status update to a playbook run. The message supports Markdown. Optionally set a reminder interval or finish the run. You must be a participant to post updates. Example: {\"run_id\": \"abc123...\", \"message\": \"Investigation complete, root cause identified.\", \"reminder_seconds\": 1800}",
+		"Post a status update to a playbook run. The message supports Markdown. Optionally set a reminder interval or finish the run. You must be a participant to post updates. Finishing fails with the list of missing properties while properties required on finish are empty; nothing is posted in that case. Example: {\"run_id\": \"abc123...\", \"message\": \"Investigation complete, root cause identified.\", \"reminder_seconds\": 1800}",
 		toolUpdateRunStatus)
 
 	addMCPHelperTool(server, p.clientFactory, "finish_run",
-		"Finish (close) a playbook run. This marks the run as Finished. Example: {\"run_id\": \"abc123...\"}",
+		"Finish (close) a playbook run. This marks the run as Finished. If properties required on finish (such as root cause or customer impact) are empty, the call fails and the error lists the missing properties; fill them in and retry. Example: {\"run_id\": \"abc123...\"}",
 		toolFinishRun)
 
 	addMCPHelperTool(server, p.clientFactory, "change_run_owner",
 		"Change the owner of a playbook run. The new owner must be a valid Mattermost user. Example: {\"run_id\": \"abc123...\", \"owner_id\": \"def456...\"}",
 		toolChangeRunOwner)
+
+	addMCPHelperTool(server, p.clientFactory, "start_run",
+		"Start a playbook run from a playbook. The run gets the playbook's checklists and settings. Property values are keyed by field name; the call fails, listing the problem, when required properties are missing or the playbook's run name template can't be resolved. Example: {\"playbook_id\": \"abc123...\", \"name\": \"API outage\", \"summary\": \"Requests to the API fail.\", \"properties\": {\"Severity\": \"SEV1\", \"Services\": [\"API\"]}}",
+		toolStartRun)
+
+	addMCPHelperTool(server, p.clientFactory, "get_status_updates",
+		"Get the status updates posted to a playbook run, newest first, with their author and date. Example: {\"run_id\": \"abc123...\", \"limit\": 5}",
+		toolGetStatusUpdates)
+
+	addMCPHelperTool(server, p.clientFactory, "add_to_timeline",
+		"Add an existing post to the timeline of a playbook run, optionally with a summary used as the event title. You must be able to read the post's channel. Example: {\"run_id\": \"abc123...\", \"post_id\": \"def456...\", \"summary\": \"Customer report\"}",
+		toolAddToTimeline)
+
+	addMCPHelperTool(server, p.clientFactory, "draft_retrospective",
+		"Save a draft of the retrospective report of a playbook run without publishing it. Metrics already entered are kept. Example: {\"run_id\": \"abc123...\", \"text\": \"## Summary\\n...\"}",
+		toolDraftRetrospective)
+
+	addMCPHelperTool(server, p.clientFactory, "publish_retrospective",
+		"Publish the retrospective report of a playbook run to its channel. Publishes the current draft unless text is given. Example: {\"run_id\": \"abc123...\"}",
+		toolPublishRetrospective)
+
+	addMCPHelperTool(server, p.clientFactory, "add_run_participants",
+		"Add users as participants of a playbook run. Optionally also add them to the run channel. Example: {\"run_id\": \"abc123...\", \"user_ids\": [\"def456...\"]}",
+		toolAddRunParticipants)
+
+	addMCPHelperTool(server, p.clientFactory, "remove_run_participant",
+		"Remove a participant from a playbook run. The user also stops following the run. Example: {\"run_id\": \"abc123...\", \"user_id\": \"def456...\"}",
+		toolRemoveRunParticipant)
+
+	addMCPHelperTool(server, p.clientFactory, "follow_run",
+		"Follow or stop following a playbook run as the current user. Followers are notified of status updates. Example: {\"run_id\": \"abc123...\", \"follow\": true}",
+		toolFollowRun)
 }
 
 // --- Tool implementations ---
@@ -178,6 +276,27 @@ func toolListRuns(ctx context.Context, client APIClient, args ListRunsArgs) (str
 		params.Add("types", runType)
 	}
 
+	if args.PropertyFilter != "" {
+		if !json.Valid([]byte(args.PropertyFilter)) {
+			return "", fmt.Errorf("property_filter must be a JSON condition expression")
+		}
+		params.Set("property_filter", args.PropertyFilter)
+	}
+	if args.SortPropertyFieldID != "" {
+		if err := validateID(args.SortPropertyFieldID, "sort_property_field_id"); err != nil {
+			return "", err
+		}
+		params.Set("sort", "property")
+		params.Set("sort_property_field_id", args.SortPropertyFieldID)
+	}
+	if args.Direction != "" {
+		direction := strings.ToLower(args.Direction)
+		if direction != "asc" && direction != "desc" {
+			return "", fmt.Errorf("direction must be asc or desc")
+		}
+		params.Set("direction", direction)
+	}
+
 	perPage := args.PerPage
 	if perPage <= 0 {
 		perPage = 10
@@ -333,6 +452,262 @@ func toolChangeRunOwner(ctx context.Context, client APIClient, args ChangeRunOwn
 	return fmt.Sprintf("Owner of run %s changed to %s.", args.RunID, args.OwnerID), nil
 }
 
+func toolStartRun(ctx context.Context, client APIClient, args StartRunArgs) (string, error) {
+	if err := validateID(args.PlaybookID, "playbook_id"); err != nil {
+		return "", err
+	}
+	if args.OwnerID != "" {
+		if err := validateID(args.OwnerID, "owner_id"); err != nil {
+			return "", err
+		}
+	}
+	if args.ChannelID != "" {
+		if err := validateID(args.ChannelID, "channel_id"); err != nil {
+			return "", err
+		}
+	}
+	switch args.ChannelMode {
+	case "", "create_new_channel", "link_existing_channel":
+	default:
+		return "", fmt.Errorf("channel_mode must be create_new_channel or link_existing_channel")
+	}
+
+	var playbook playbookDefinition
+	if err := client.Get(ctx, fmt.Sprintf("playbooks/%s", args.PlaybookID), nil, &playbook); err != nil {
+		return "", fmt.Errorf("failed to get playbook: %w", err)
+	}
+
+	channelMode := args.ChannelMode
+	if channelMode == "" {
+		channelMode = playbook.ChannelMode
+	}
+	channelID := ""
+	if channelMode == "link_existing_channel" {
+		channelID = args.ChannelID
+		if channelID == "" {
+			channelID = playbook.ChannelID
+		}
+		if channelID == "" {
+			return "", fmt.Errorf("channel_id is required to link the run to an existing channel")
+		}
+	} else if args.ChannelID != "" {
+		return "", fmt.Errorf("channel_id can only be used with channel_mode link_existing_channel")
+	}
+
+	propertyValues, err := startRunPropertyValues(ctx, client, args.PlaybookID, args.Properties)
+	if err != nil {
+		return "", err
+	}
+
+	body := map[string]any{
+		"name":        strings.TrimSpace(args.Name),
+		"playbook_id": args.PlaybookID,
+		"team_id":     playbook.TeamID,
+	}
+	addNonEmpty(body, "owner_user_id", args.OwnerID)
+	addNonEmpty(body, "channel_id", channelID)
+	addNonEmpty(body, "summary", args.Summary)
+	if len(propertyValues) > 0 {
+		body["property_values"] = propertyValues
+	}
+
+	var run playbookRunDetail
+	if err := client.Post(ctx, "runs", body, &run); err != nil {
+		return "", fmt.Errorf("failed to start run: %w", err)
+	}
+
+	return formatRunDetail(run), nil
+}
+
+// startRunPropertyValues converts initial property values keyed by field name to values keyed by
+// the ID of the playbook's property fields, as the run creation API expects.
+func startRunPropertyValues(ctx context.Context, client APIClient, playbookID string, properties map[string]any) (map[string]any, error) {
+	if len(properties) == 0 {
+		return nil, nil
+	}
+
+	var fields []runPropertyField
+	if err := client.Get(ctx, fmt.Sprintf("playbooks/%s/property_fields", playbookID), nil, &fields); err != nil {
+		return nil, fmt.Errorf("failed to get playbook property fields: %w", err)
+	}
+
+	values := make(map[string]any, len(properties))
+	for nameOrID, arg := range properties {
+		field, err := findSettablePropertyField(fields, nameOrID)
+		if err != nil {
+			return nil, err
+		}
+		value, err := propertyValueFromArg(field, arg)
+		if err != nil {
+			return nil, err
+		}
+		values[field.ID] = value
+	}
+	return values, nil
+}
+
+func toolGetStatusUpdates(ctx context.Context, client APIClient, args GetStatusUpdatesArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if args.Limit < 0 {
+		return "", fmt.Errorf("limit must be >= 0")
+	}
+
+	var updates []statusUpdate
+	if err := client.Get(ctx, fmt.Sprintf("runs/%s/status-updates", args.RunID), nil, &updates); err != nil {
+		return "", fmt.Errorf("failed to get status updates: %w", err)
+	}
+
+	limit := args.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	return formatStatusUpdates(updates, limit), nil
+}
+
+func toolAddToTimeline(ctx context.Context, client APIClient, args AddToTimelineArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if err := validateID(args.PostID, "post_id"); err != nil {
+		return "", err
+	}
+
+	body := map[string]string{
+		"post_id": args.PostID,
+		"summary": strings.TrimSpace(args.Summary),
+	}
+
+	if err := client.Post(ctx, fmt.Sprintf("runs/%s/timeline", args.RunID), body, nil); err != nil {
+		return "", fmt.Errorf("failed to add post to timeline: %w", err)
+	}
+
+	return fmt.Sprintf("Post %s added to the timeline of run %s.", args.PostID, args.RunID), nil
+}
+
+func toolDraftRetrospective(ctx context.Context, client APIClient, args DraftRetrospectiveArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.Text) == "" {
+		return "", fmt.Errorf("text is required")
+	}
+
+	retro, err := getRunRetrospective(ctx, client, args.RunID)
+	if err != nil {
+		return "", err
+	}
+	if retro.RetrospectivePublishedAt > 0 {
+		return "", fmt.Errorf("the retrospective of run %s is already published", args.RunID)
+	}
+
+	if err := client.Post(ctx, fmt.Sprintf("runs/%s/retrospective", args.RunID), retrospectiveBody(args.Text, retro), nil); err != nil {
+		return "", fmt.Errorf("failed to save retrospective: %w", err)
+	}
+
+	return fmt.Sprintf("Retrospective draft of run %s saved.", args.RunID), nil
+}
+
+func toolPublishRetrospective(ctx context.Context, client APIClient, args PublishRetrospectiveArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+
+	retro, err := getRunRetrospective(ctx, client, args.RunID)
+	if err != nil {
+		return "", err
+	}
+	text := args.Text
+	if strings.TrimSpace(text) == "" {
+		text = retro.Retrospective
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("the retrospective of run %s is empty; provide text or draft it first", args.RunID)
+	}
+
+	if err := client.Post(ctx, fmt.Sprintf("runs/%s/retrospective/publish", args.RunID), retrospectiveBody(text, retro), nil); err != nil {
+		return "", fmt.Errorf("failed to publish retrospective: %w", err)
+	}
+
+	return fmt.Sprintf("Retrospective of run %s published.", args.RunID), nil
+}
+
+func toolAddRunParticipants(ctx context.Context, client APIClient, args AddRunParticipantsArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if err := validateIDs(args.UserIDs, "user_ids"); err != nil {
+		return "", err
+	}
+
+	body := map[string]any{
+		"user_ids":             args.UserIDs,
+		"force_add_to_channel": args.ForceAddToChannel,
+	}
+
+	if err := client.Post(ctx, fmt.Sprintf("runs/%s/participants", args.RunID), body, nil); err != nil {
+		return "", fmt.Errorf("failed to add participants: %w", err)
+	}
+
+	return fmt.Sprintf("Added %d participant(s) to run %s.", len(args.UserIDs), args.RunID), nil
+}
+
+func toolRemoveRunParticipant(ctx context.Context, client APIClient, args RemoveRunParticipantArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+	if err := validateID(args.UserID, "user_id"); err != nil {
+		return "", err
+	}
+
+	if err := client.Delete(ctx, fmt.Sprintf("runs/%s/participants/%s", args.RunID, args.UserID)); err != nil {
+		return "", fmt.Errorf("failed to remove participant: %w", err)
+	}
+
+	return fmt.Sprintf("Removed participant %s from run %s.", args.UserID, args.RunID), nil
+}
+
+func toolFollowRun(ctx context.Context, client APIClient, args FollowRunArgs) (string, error) {
+	if err := validateID(args.RunID, "run_id"); err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("runs/%s/followers", args.RunID)
+	if !args.Follow {
+		if err := client.Delete(ctx, endpoint); err != nil {
+			return "", fmt.Errorf("failed to unfollow run: %w", err)
+		}
+		return fmt.Sprintf("You no longer follow run %s.", args.RunID), nil
+	}
+
+	if err := client.Put(ctx, endpoint, nil, nil); err != nil {
+		return "", fmt.Errorf("failed to follow run: %w", err)
+	}
+	return fmt.Sprintf("You now follow run %s.", args.RunID), nil
+}
+
+func getRunRetrospective(ctx context.Context, client APIClient, runID string) (runRetrospective, error) {
+	var retro runRetrospective
+	if err := client.Get(ctx, fmt.Sprintf("runs/%s", runID), nil, &retro); err != nil {
+		return runRetrospective{}, fmt.Errorf("failed to get run: %w", err)
+	}
+	return retro, nil
+}
+
+// retrospectiveBody builds a retrospective update with text, keeping the metrics already entered
+// since the API replaces them along with the text.
+func retrospectiveBody(text string, retro runRetrospective) map[string]any {
+	metrics := retro.MetricsData
+	if len(metrics) == 0 || string(metrics) == "null" {
+		metrics = json.RawMessage("[]")
+	}
+	return map[string]any{
+		"retrospective": text,
+		"metrics":       metrics,
+	}
+}
+
 func validateRunType(runType string) error {
 	switch runType {
 	case "playbook", "channelChecklist":
@@ -383,6 +758,31 @@ func formatListRuns(resp listRunsResponse) string {
 	return sb.String()
 }
 
+func formatStatusUpdates(updates []statusUpdate, limit int) string {
+	visible := make([]statusUpdate, 0, len(updates))
+	for _, update := range updates {
+		if update.DeleteAt == 0 {
+			visible = append(visible, update)
+		}
+	}
+	if len(visible) == 0 {
+		return "No status updates have been posted to this run."
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].CreateAt > visible[j].CreateAt
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Found %d status updates (showing %d):\n\n", len(visible), min(limit, len(visible)))
+	for i, update := range visible {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(&sb, "### %s by @%s\n\n%s\n\n", formatTimestamp(update.CreateAt), update.AuthorUserName, update.Message)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
 func formatRunDetail(run playbookRunDetail) string {
 	data, err := json.MarshalIndent(run, "", "  ")
 	if err != nil {
@@ -27,3 +27,25 @@ func validateIndex(val int, name string) error {
 	}
 	return nil
 }
+
+// validateIDs checks that a list has at least one ID and that all of them match the Mattermost
+// ID format.
+func validateIDs(ids []string, name string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%s must contain at least one ID", name)
+	}
+	for i, id := range ids {
+		if err := validateID(id, fmt.Sprintf("%s[%d]", name, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTimestamp checks that a timestamp in milliseconds is non-negative.
+func validateTimestamp(val int64, name string) error {
+	if val < 0 {
+		return fmt.Errorf("%s must be a Unix timestamp in milliseconds, got %d", name, val)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
@@ -22,14 +23,18 @@ import (
 type ActionsHandler struct {
 	*ErrorHandler
 	channelActionsService app.ChannelActionService
+	playbookService       app.PlaybookService
+	propertyService       app.PropertyService
 	pluginAPI             *pluginapi.Client
 	permissions           *app.PermissionsService
 }
 
-func NewActionsHandler(router *mux.Router, channelActionsService app.ChannelActionService, pluginAPI *pluginapi.Client, permissions *app.PermissionsService) *ActionsHandler {
+func NewActionsHandler(router *mux.Router, channelActionsService app.ChannelActionService, playbookService app.PlaybookService, propertyService app.PropertyService, pluginAPI *pluginapi.Client, permissions *app.PermissionsService) *ActionsHandler {
 	handler := &ActionsHandler{
 		ErrorHandler:          &ErrorHandler{},
 		channelActionsService: channelActionsService,
+		playbookService:       playbookService,
+		propertyService:       propertyService,
 		pluginAPI:             pluginAPI,
 		permissions:           permissions,
 	}
@@ -95,23 +100,12 @@ func (a *ActionsHandler) createChannelAction(c *Context, w http.ResponseWriter,
 
 func (a *ActionsHandler) ValidateChannelAction(c *Context, w http.ResponseWriter, action *app.GenericChannelAction, userID string) error {
 	// Validate the trigger type and action types
-	switch action.TriggerType {
-	case app.TriggerTypeNewMemberJoins:
-		switch action.ActionType {
-		case app.ActionTypeWelcomeMessage:
-			break
-		case app.ActionTypeCategorizeChannel:
-			break
-		default:
-			return fmt.Errorf("action type %q is not valid for trigger type %q", action.ActionType, action.TriggerType)
-		}
-	case app.TriggerTypeKeywordsPosted:
-		if action.ActionType != app.ActionTypePromptRunPlaybook {
-			return fmt.Errorf("action type %q is not valid for trigger type %q", action.ActionType, action.TriggerType)
-		}
-	default:
+	if !isValidTrigger(string(action.TriggerType)) || action.TriggerType == "" {
 		return fmt.Errorf("trigger type %q not recognized", action.TriggerType)
 	}
+	if !app.IsValidChannelActionCombination(action.TriggerType, action.ActionType) {
+		return fmt.Errorf("action type %q is not valid for trigger type %q", action.ActionType, action.TriggerType)
+	}
 
 	// Validate the payload depending on the action type
 	switch action.ActionType {
@@ -144,6 +138,69 @@ func (a *ActionsHandler) ValidateChannelAction(c *Context, w http.ResponseWriter
 			return fmt.Errorf("unable to decode payload from action")
 		}
 
+		// Force the payload to only include the recognized decoded fields.
+		action.Payload = payload
+	case app.ActionTypeStartRun:
+		var payload app.StartRunPayload
+		if err := safemapstructure.Decode(action.Payload, &payload); err != nil {
+			return fmt.Errorf("unable to decode payload from action")
+		}
+		if err := checkValidChannelActionTriggerFilter(action.TriggerType, payload.ChannelActionTriggerFilter); err != nil {
+			return err
+		}
+		if !model.IsValidId(payload.PlaybookID) {
+			return fmt.Errorf("payload field 'playbook_id' must be a valid ID")
+		}
+		if err := checkValidRunAutoStartOptions(payload.RunAutoStartOptions); err != nil {
+			return err
+		}
+
+		if !a.PermissionsCheck(w, c.logger, a.permissions.PlaybookView(userID, payload.PlaybookID)) {
+			return fmt.Errorf("user does not have permissions to view playbook %s", payload.PlaybookID)
+		}
+
+		// The runs are started by whoever triggers the action, who is checked then too, but the
+		// user saving the action must be able to run the playbook in the channel's team.
+		playbook, err := a.playbookService.Get(payload.PlaybookID)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get playbook %s", payload.PlaybookID)
+		}
+		channel, err := a.pluginAPI.Channel.Get(action.ChannelID)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get channel %s", action.ChannelID)
+		}
+		if !a.PermissionsCheck(w, c.logger, a.permissions.RunCreate(userID, playbook, channel.TeamId)) {
+			return fmt.Errorf("user does not have permissions to run playbook %s", payload.PlaybookID)
+		}
+
+		// Force the payload to only include the recognized decoded fields.
+		action.Payload = payload
+	case app.ActionTypeAddToRunTimeline:
+		var payload app.AddToRunTimelinePayload
+		if err := safemapstructure.Decode(action.Payload, &payload); err != nil {
+			return fmt.Errorf("unable to decode payload from action")
+		}
+		if err := checkValidChannelActionTriggerFilter(action.TriggerType, payload.ChannelActionTriggerFilter); err != nil {
+			return err
+		}
+
+		// Force the payload to only include the recognized decoded fields.
+		action.Payload = payload
+	case app.ActionTypeSetRunProperty:
+		var payload app.SetRunPropertyPayload
+		if err := safemapstructure.Decode(action.Payload, &payload); err != nil {
+			return fmt.Errorf("unable to decode payload from action")
+		}
+		if err := checkValidChannelActionTriggerFilter(action.TriggerType, payload.ChannelActionTriggerFilter); err != nil {
+			return err
+		}
+		if !model.IsValidId(payload.FieldID) {
+			return fmt.Errorf("payload field 'field_id' must be a valid ID")
+		}
+		if err := a.checkValidRunPropertyValue(payload); err != nil {
+			return err
+		}
+
 		// Force the payload to only include the recognized decoded fields.
 		action.Payload = payload
 
@@ -154,6 +211,29 @@ func (a *ActionsHandler) ValidateChannelAction(c *Context, w http.ResponseWriter
 	return nil
 }
 
+// checkValidRunPropertyValue checks that the value set by a set_run_property action is one the run
+// property field accepts, so that a typo is reported when the action is saved rather than each time
+// it runs.
+func (a *ActionsHandler) checkValidRunPropertyValue(payload app.SetRunPropertyPayload) error {
+	field, err := a.propertyService.GetPropertyField(payload.FieldID)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get property field %s", payload.FieldID)
+	}
+	if field.IsComputed() {
+		return fmt.Errorf("the value of property field %s is computed", field.Name)
+	}
+
+	value, err := json.Marshal(payload.Value)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal payload field 'value'")
+	}
+	if _, err := a.propertyService.ValidatePropertyValue(field, value); err != nil {
+		return errors.Wrap(err, "payload field 'value' is not valid for the property field")
+	}
+
+	return nil
+}
+
 func checkValidPromptRunPlaybookFromKeywordsPayload(payload app.PromptRunPlaybookFromKeywordsPayload) error {
 	for _, keyword := range payload.Keywords {
 		if keyword == "" {
@@ -165,6 +245,60 @@ func checkValidPromptRunPlaybookFromKeywordsPayload(payload app.PromptRunPlayboo
 		return fmt.Errorf("payload field 'playbook_id' must be a valid ID")
 	}
 
+	if payload.AutoStart && payload.PlaybookID == "" {
+		return fmt.Errorf("payload field 'playbook_id' is required to auto start runs")
+	}
+
+	return checkValidRunAutoStartOptions(payload.RunAutoStartOptions)
+}
+
+// checkValidRunAutoStartOptions checks the cooldown and the property extractors of the runs
+// started by a channel action.
+func checkValidRunAutoStartOptions(options app.RunAutoStartOptions) error {
+	if options.CooldownSeconds < 0 {
+		return fmt.Errorf("payload field 'cooldown_seconds' must not be negative")
+	}
+
+	for _, extractor := range options.PropertyExtractors {
+		if !model.IsValidId(extractor.FieldID) {
+			return fmt.Errorf("payload field 'property_extractors' must contain only valid field IDs")
+		}
+		if extractor.Pattern == "" {
+			return fmt.Errorf("payload field 'property_extractors' must contain only non-empty patterns")
+		}
+		if _, err := regexp.Compile(extractor.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern in payload field 'property_extractors': %s", err)
+		}
+	}
+
+	return nil
+}
+
+// checkValidChannelActionTriggerFilter checks that the filter of a run action has what its trigger
+// type needs: keywords for keywords and user IDs for message_from_user.
+func checkValidChannelActionTriggerFilter(triggerType app.TriggerType, filter app.ChannelActionTriggerFilter) error {
+	for _, keyword := range filter.Keywords {
+		if keyword == "" {
+			return fmt.Errorf("payload field 'keywords' must contain only non-empty keywords")
+		}
+	}
+	for _, userID := range filter.UserIDs {
+		if !model.IsValidId(userID) {
+			return fmt.Errorf("payload field 'user_ids' must contain only valid IDs")
+		}
+	}
+
+	switch triggerType {
+	case app.TriggerTypeKeywordsPosted:
+		if len(filter.Keywords) == 0 {
+			return fmt.Errorf("payload field 'keywords' is required for trigger type %q", triggerType)
+		}
+	case app.TriggerTypeMessageFromUser:
+		if len(filter.UserIDs) == 0 {
+			return fmt.Errorf("payload field 'user_ids' is required for trigger type %q", triggerType)
+		}
+	}
+
 	return nil
 }
 
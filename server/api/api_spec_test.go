@@ -0,0 +1,851 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/client"
+	"github.com/mattermost/mattermost-plugin-playbooks/server/api/apispec"
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+	"github.com/mattermost/mattermost-plugin-playbooks/server/config"
+)
+
+func loadAPISpec(t *testing.T) *apispec.Spec {
+	t.Helper()
+
+	spec, err := apispec.Load("api.yaml")
+	require.NoError(t, err)
+
+	return spec
+}
+
+// requireResponseMatchesSpec checks a response recorded for a request to path against the schema
+// the spec gives for the operation and status code.
+func requireResponseMatchesSpec(t *testing.T, spec *apispec.Spec, method, path string, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	require.NoError(t, spec.CheckResponse(method, path, rec.Code, rec.Body.Bytes()))
+}
+
+// specTestConfig stands in for the configuration service when registering the routes.
+type specTestConfig struct {
+	config.Service
+}
+
+func (specTestConfig) IsConfiguredForDevelopmentAndTesting() bool {
+	return false
+}
+
+// registeredRoutes returns the HTTP methods of the routes registered by the plugin, keyed by their
+// path template as written in the spec. Handlers only record their dependencies when registering
+// their routes, so none are needed here.
+func registeredRoutes(t *testing.T) map[string]map[string][]string {
+	t.Helper()
+
+	handler := NewHandler(nil, specTestConfig{}, nil)
+	router := handler.APIRouter
+	NewGraphQLHandler(router, nil, nil, nil, nil, nil, specTestConfig{}, nil, nil, nil, nil)
+	NewPlaybookHandler(router, nil, nil, nil, nil, specTestConfig{}, nil, nil)
+	NewPlaybookRunHandler(router, nil, nil, nil, nil, nil, nil, nil, specTestConfig{})
+	NewStatsHandler(router, nil, nil, nil, nil, nil, nil)
+	NewBotHandler(router, nil, nil, specTestConfig{}, nil, nil)
+	NewSignalHandler(router, nil, nil, nil, nil, nil)
+	NewSettingsHandler(router, nil, specTestConfig{})
+	NewActionsHandler(router, nil, nil, nil, nil, nil)
+	NewCategoryHandler(router, nil, nil, nil, nil, nil)
+	NewConditionHandler(router, nil, nil, nil, nil, nil, nil)
+	NewPlaybookRevisionHandler(router, nil, nil, nil, nil)
+	NewTabAppHandler(handler, nil, nil, specTestConfig{}, nil)
+
+	routeVariableRegexp := regexp.MustCompile(`\{([^:}]+)(:[^}]+)?\}`)
+
+	// routes maps path templates to methods to the names of the path parameters.
+	routes := map[string]map[string][]string{}
+	err := handler.root.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Path prefixes of subrouters have no methods.
+			return nil
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+
+		var parameters []string
+		for _, match := range routeVariableRegexp.FindAllStringSubmatch(template, -1) {
+			parameters = append(parameters, match[1])
+		}
+		sort.Strings(parameters)
+
+		path := apispec.Prefix + routeVariableRegexp.ReplaceAllString(template, "{$1}")
+		for _, method := range methods {
+			// Preflight requests are answered for CORS and are not part of the API.
+			if method == http.MethodOptions {
+				continue
+			}
+			if routes[path] == nil {
+				routes[path] = map[string][]string{}
+			}
+			routes[path][method] = parameters
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	return routes
+}
+
+func TestAPISpecMatchesRouter(t *testing.T) {
+	spec := loadAPISpec(t)
+	routes := registeredRoutes(t)
+
+	t.Run("every route is in the spec", func(t *testing.T) {
+		for path, methods := range routes {
+			for method, parameters := range methods {
+				pathItem, ok := spec.Paths[path]
+				if !assert.True(t, ok, "%s %s is missing from api.yaml", method, path) {
+					continue
+				}
+				operation, ok := pathItem.Operations()[method]
+				if !assert.True(t, ok, "%s %s is missing from api.yaml", method, path) {
+					continue
+				}
+
+				specParameters, err := spec.PathParameters(pathItem, operation)
+				require.NoError(t, err)
+				assert.Equal(t, parameters, specParameters, "path parameters of %s %s", method, path)
+			}
+		}
+	})
+
+	t.Run("every operation in the spec is routed", func(t *testing.T) {
+		for path, pathItem := range spec.Paths {
+			for method := range pathItem.Operations() {
+				_, ok := routes[path][method]
+				assert.True(t, ok, "%s %s is in api.yaml but not routed", method, path)
+			}
+		}
+	})
+
+	t.Run("operations are complete", func(t *testing.T) {
+		operationIDs := map[string]string{}
+		for path, pathItem := range spec.Paths {
+			for method, operation := range pathItem.Operations() {
+				name := method + " " + path
+				if assert.NotEmpty(t, operation.OperationID, "%s has no operationId", name) {
+					other, duplicate := operationIDs[operation.OperationID]
+					assert.False(t, duplicate, "%s and %s share operationId %s", name, other, operation.OperationID)
+					operationIDs[operation.OperationID] = name
+				}
+
+				successful := false
+				for status, response := range operation.Responses {
+					if strings.HasPrefix(status, "2") {
+						successful = true
+					}
+					_, err := spec.ResolveResponse(response)
+					assert.NoError(t, err, "%s %s response", name, status)
+				}
+				assert.True(t, successful, "%s has no successful response", name)
+			}
+		}
+	})
+}
+
+func TestAPISpecReferences(t *testing.T) {
+	spec := loadAPISpec(t)
+
+	var checkSchema func(schema *apispec.Schema, at string)
+	checkSchema = func(schema *apispec.Schema, at string) {
+		if schema == nil {
+			return
+		}
+		if schema.Ref != "" {
+			_, err := spec.ResolveSchema(schema)
+			assert.NoError(t, err, at)
+			return
+		}
+		for name, property := range schema.Properties {
+			checkSchema(property, at+"."+name)
+		}
+		checkSchema(schema.Items, at+"[]")
+		for i, sub := range append(append([]*apispec.Schema{}, schema.AllOf...), schema.OneOf...) {
+			checkSchema(sub, fmt.Sprintf("%s/%d", at, i))
+		}
+	}
+
+	for name, schema := range spec.Components.Schemas {
+		checkSchema(schema, name)
+	}
+	for name, response := range spec.Components.Responses {
+		for _, content := range response.Content {
+			checkSchema(content.Schema, "response "+name)
+		}
+	}
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem.Operations() {
+			for _, parameter := range append(append([]*apispec.Parameter{}, pathItem.Parameters...), operation.Parameters...) {
+				resolved, err := spec.ResolveParameter(parameter)
+				if assert.NoError(t, err, "%s %s", method, path) {
+					checkSchema(resolved.Schema, method+" "+path+" "+resolved.Name)
+				}
+			}
+			if operation.RequestBody != nil {
+				for _, content := range operation.RequestBody.Content {
+					checkSchema(content.Schema, method+" "+path+" request body")
+				}
+			}
+			for status, response := range operation.Responses {
+				resolved, err := spec.ResolveResponse(response)
+				if !assert.NoError(t, err, "%s %s %s", method, path, status) {
+					continue
+				}
+				for _, content := range resolved.Content {
+					checkSchema(content.Schema, method+" "+path+" "+status)
+				}
+			}
+		}
+	}
+}
+
+func TestAPISpecResponses(t *testing.T) {
+	spec := loadAPISpec(t)
+	logger := logrus.New()
+	errorHandler := &ErrorHandler{}
+
+	item := app.ChecklistItem{ID: "item1", Title: "Check the logs", State: app.ChecklistItemStateSkipped, UpdateAt: 1000}
+	checklist := app.Checklist{ID: "checklist1", Title: "Triage", Items: []app.ChecklistItem{item}}
+	run := app.PlaybookRun{ID: "run1", Name: "Server down", TeamID: "team1", ChannelID: "channel1", Checklists: []app.Checklist{checklist}}
+	playbook := app.Playbook{ID: "playbook1", Title: "Incident", TeamID: "team1", Checklists: []app.Checklist{checklist}}
+	field := app.PropertyField{
+		PropertyField: model.PropertyField{ID: "field1", Name: "Priority", Type: model.PropertyFieldTypeSelect},
+		Attrs:         app.Attrs{Visibility: "when_set", SortOrder: 1},
+	}
+
+	testCases := []struct {
+		method string
+		path   string
+		write  func(w http.ResponseWriter)
+	}{
+		{http.MethodGet, "/runs/run1", func(w http.ResponseWriter) { ReturnJSON(w, &run, http.StatusOK) }},
+		{http.MethodGet, "/runs/run1", func(w http.ResponseWriter) { ReturnJSON(w, &app.PlaybookRun{}, http.StatusOK) }},
+		{http.MethodPatch, "/runs/run1", func(w http.ResponseWriter) { ReturnJSON(w, &run, http.StatusOK) }},
+		{http.MethodPatch, "/runs/run1", func(w http.ResponseWriter) { returnStaleRun(w, logger, &run) }},
+		{http.MethodGet, "/runs", func(w http.ResponseWriter) {
+			ReturnJSON(w, app.GetPlaybookRunsResults{Items: []app.PlaybookRun{run}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs", func(w http.ResponseWriter) { ReturnJSON(w, app.GetPlaybookRunsResults{}, http.StatusOK) }},
+		{http.MethodGet, "/runs/channel/channel1/runs", func(w http.ResponseWriter) {
+			ReturnJSON(w, []app.PlaybookRun(nil), http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/run1", func(w http.ResponseWriter) {
+			errorHandler.HandleErrorWithCode(w, logger, http.StatusForbidden, "not authorized", nil)
+		}},
+		{http.MethodGet, "/runs/run1/status-updates", func(w http.ResponseWriter) {
+			ReturnJSON(w, []*app.StatusPostComplete{{ID: "post1", Message: "Investigating"}}, http.StatusOK)
+		}},
+		{http.MethodPut, "/runs/run1/status-update-enabled", func(w http.ResponseWriter) {
+			ReturnJSON(w, map[string]interface{}{"success": true}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/todo", func(w http.ResponseWriter) {
+			ReturnJSON(w, app.TodoDigestMessageItems{
+				OverdueRuns:    []app.RunLink{{PlaybookRunID: "run1", Name: "Server down"}},
+				AssignedRuns:   []app.AssignedRun{{RunLink: app.RunLink{PlaybookRunID: "run1"}}},
+				InProgressRuns: []app.RunLink{},
+			}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/changes", func(w http.ResponseWriter) {
+			ReturnJSON(w, runChangesResult{Changes: []app.RunChange{{
+				Cursor:        7,
+				Type:          app.RunChangeUpdated,
+				PlaybookRunID: "run1",
+				Payload:       json.RawMessage(`{"name":"Server down"}`),
+			}}, Cursor: 7}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/changes", func(w http.ResponseWriter) { ReturnJSON(w, runChangesResult{Cursor: 7}, http.StatusOK) }},
+		{http.MethodPost, "/runs/bulk", func(w http.ResponseWriter) {
+			ReturnJSON(w, &bulkOperationResults{Results: []app.BulkOperationResult{
+				{Index: 0, ID: "run1", Success: true},
+				{Index: 1, ID: "run2", Error: "not authorized"},
+			}}, http.StatusOK)
+		}},
+		{http.MethodPost, "/runs/run1/checklists/items/bulk", func(w http.ResponseWriter) {
+			ReturnJSON(w, &bulkOperationRejectedResponse{Error: "no operation was applied", Details: "item not found"}, http.StatusBadRequest)
+		}},
+		{http.MethodPut, "/runs/run1/checklist-items/item1/state", func(w http.ResponseWriter) {
+			errorHandler.HandleChecklistItemConflict(w, logger, &app.ChecklistItemConflictError{Item: item})
+		}},
+		{http.MethodPost, "/runs/run1/checklists/0/item/0/run", func(w http.ResponseWriter) {
+			ReturnJSON(w, map[string]interface{}{"trigger_id": "trigger1"}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/run1/property_fields", func(w http.ResponseWriter) {
+			ReturnJSON(w, []app.PropertyField{field}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/run1/property_values", func(w http.ResponseWriter) {
+			ReturnJSON(w, []app.PropertyValue{{ID: "value1", FieldID: "field1", Value: json.RawMessage(`["option1"]`)}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/runs/run1/property_values/history", func(w http.ResponseWriter) {
+			ReturnJSON(w, []app.PropertyValueChange{{ID: "change1", FieldID: "field1", NewValue: json.RawMessage(`"option1"`)}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/playbooks/playbook1", func(w http.ResponseWriter) { ReturnJSON(w, &playbook, http.StatusOK) }},
+		{http.MethodGet, "/playbooks/playbook1", func(w http.ResponseWriter) { ReturnJSON(w, &app.Playbook{}, http.StatusOK) }},
+		{http.MethodPut, "/playbooks/playbook1", func(w http.ResponseWriter) { returnStalePlaybook(w, logger, &playbook) }},
+		{http.MethodPost, "/playbooks/playbook1/metrics", func(w http.ResponseWriter) {
+			ReturnJSON(w, &app.PlaybookMetricConfig{ID: "metric1", PlaybookID: "playbook1", Title: "Time to resolve", Type: app.MetricTypeDuration}, http.StatusCreated)
+		}},
+		{http.MethodPatch, "/playbooks/playbook1/metrics/metric1", func(w http.ResponseWriter) {
+			ReturnJSON(w, &app.PlaybookMetricConfig{ID: "metric1", PlaybookID: "playbook1", Title: "Time to resolve", Type: app.MetricTypeDuration, Target: null.IntFrom(3600000)}, http.StatusOK)
+		}},
+		{http.MethodGet, "/playbooks", func(w http.ResponseWriter) {
+			ReturnJSON(w, app.GetPlaybooksResults{Items: []app.Playbook{playbook}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/playbooks", func(w http.ResponseWriter) { ReturnJSON(w, app.GetPlaybooksResults{}, http.StatusOK) }},
+		{http.MethodGet, "/playbooks/playbook1/revisions", func(w http.ResponseWriter) {
+			ReturnJSON(w, app.GetPlaybookRevisionsResults{Items: []app.PlaybookRevision{{ID: "revision1", Revision: 1}}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/playbooks/playbook1/revisions/diff", func(w http.ResponseWriter) {
+			ReturnJSON(w, &app.PlaybookRevisionDiff{Changes: []app.PlaybookRevisionChange{{Path: "title", Old: "Incident", New: "Outage"}}}, http.StatusOK)
+		}},
+		{http.MethodPost, "/playbooks/playbook1/propagate_checklists", func(w http.ResponseWriter) {
+			ReturnJSON(w, &app.ChecklistPropagationResult{Runs: []app.RunChecklistPropagation{{PlaybookRunID: "run1"}}}, http.StatusOK)
+		}},
+		{http.MethodPost, "/playbooks/bundle/import", func(w http.ResponseWriter) {
+			ReturnJSON(w, &app.PlaybookBundleImportResult{Playbooks: []app.PlaybookBundleImportItem{{Action: app.BundleImportActionCreate}}}, http.StatusCreated)
+		}},
+		{http.MethodPost, "/playbooks/playbook1/duplicate", func(w http.ResponseWriter) {
+			ReturnJSON(w, &struct {
+				ID string `json:"id"`
+			}{ID: "playbook2"}, http.StatusCreated)
+		}},
+		{http.MethodPost, "/teams/team1/property_fields/merge", func(w http.ResponseWriter) {
+			ReturnJSON(w, &app.SharedPropertyMergeResult{Field: field, LinkedFields: []app.PropertyField{field}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/my_categories", func(w http.ResponseWriter) {
+			ReturnJSON(w, []app.Category{{ID: "category1", Items: []app.CategoryItem{{ItemID: "run1", Type: app.RunItemType}}}}, http.StatusOK)
+		}},
+		{http.MethodGet, "/my_categories/favorites", func(w http.ResponseWriter) { ReturnJSON(w, true, http.StatusOK) }},
+		{http.MethodGet, "/settings", func(w http.ResponseWriter) { ReturnJSON(w, &client.GlobalSettings{}, http.StatusOK) }},
+		{http.MethodGet, "/stats/site", func(w http.ResponseWriter) { ReturnJSON(w, &PlaybookSiteStats{}, http.StatusOK) }},
+	}
+
+	for _, tc := range testCases {
+		path := apispec.Prefix + "/api/v0" + tc.path
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tc.write(rec)
+			requireResponseMatchesSpec(t, spec, tc.method, path, rec)
+		})
+	}
+}
+
+func TestClientMatchesAPISpec(t *testing.T) {
+	spec := loadAPISpec(t)
+
+	type clientRequest struct {
+		method string
+		path   string
+	}
+	var requests []clientRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, clientRequest{r.Method, r.URL.Path})
+		ReturnJSON(w, map[string]string{"error": "not found"}, http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c, err := client.New(&model.Client4{URL: ts.URL})
+	require.NoError(t, err)
+
+	ref := client.ChecklistItemRef{ItemID: "item1", UpdateAt: 1000}
+	position := 0
+
+	// Every request the client can make answers 404, so each call returns after its first request.
+	testCases := map[string]func(ctx context.Context) error{
+		"Actions.Create": func(ctx context.Context) error {
+			_, err := c.Actions.Create(ctx, "channel1", client.ChannelActionCreateOptions{ChannelID: "channel1"})
+			return err
+		},
+		"Actions.List": func(ctx context.Context) error {
+			_, err := c.Actions.List(ctx, "channel1", client.ChannelActionListOptions{})
+			return err
+		},
+		"Actions.Update": func(ctx context.Context) error {
+			return c.Actions.Update(ctx, client.GenericChannelAction{GenericChannelActionWithoutPayload: client.GenericChannelActionWithoutPayload{ID: "action1", ChannelID: "channel1"}})
+		},
+		"Actions.CheckAndSendMessageOnJoin": func(ctx context.Context) error {
+			_, err := c.Actions.CheckAndSendMessageOnJoin(ctx, "channel1")
+			return err
+		},
+		"Bot.Connect":      func(ctx context.Context) error { return c.Bot.Connect(ctx) },
+		"Bot.NotifyAdmins": func(ctx context.Context) error { return c.Bot.NotifyAdmins(ctx, "start_trial") },
+		"Categories.IsFavorite": func(ctx context.Context) error {
+			_, err := c.Categories.IsFavorite(ctx, client.CategoriesIsFavoriteOptions{TeamId: "team1", ItemId: "run1", ItemType: string(client.RunItemType)})
+			return err
+		},
+		"Categories.List": func(ctx context.Context) error {
+			_, err := c.Categories.List(ctx, "team1")
+			return err
+		},
+		"Categories.Create": func(ctx context.Context) error {
+			_, err := c.Categories.Create(ctx, client.Category{Name: "Urgent", TeamID: "team1"})
+			return err
+		},
+		"Categories.Update":   func(ctx context.Context) error { return c.Categories.Update(ctx, client.Category{ID: "category1"}) },
+		"Categories.Delete":   func(ctx context.Context) error { return c.Categories.Delete(ctx, "category1") },
+		"Categories.Collapse": func(ctx context.Context) error { return c.Categories.Collapse(ctx, "category1", true) },
+		"Categories.SetFavorite": func(ctx context.Context) error {
+			return c.Categories.SetFavorite(ctx, client.RunItemType, "run1", true)
+		},
+		"Categories.SetFavorite unset": func(ctx context.Context) error {
+			return c.Categories.SetFavorite(ctx, client.PlaybookItemType, "playbook1", false)
+		},
+		"PlaybookConditions.List": func(ctx context.Context) error {
+			_, err := c.PlaybookConditions.List(ctx, "playbook1", 0, 10, client.PlaybookConditionListOptions{})
+			return err
+		},
+		"PlaybookConditions.ListAll": func(ctx context.Context) error {
+			for _, err := range c.PlaybookConditions.ListAll(ctx, "playbook1", 10) {
+				return err
+			}
+			return nil
+		},
+		"PlaybookConditions.Create": func(ctx context.Context) error {
+			_, err := c.PlaybookConditions.Create(ctx, "playbook1", client.Condition{})
+			return err
+		},
+		"PlaybookConditions.Update": func(ctx context.Context) error {
+			_, err := c.PlaybookConditions.Update(ctx, "playbook1", "condition1", client.Condition{})
+			return err
+		},
+		"PlaybookConditions.Delete": func(ctx context.Context) error {
+			return c.PlaybookConditions.Delete(ctx, "playbook1", "condition1")
+		},
+		"PlaybookRuns.ApplyChecklistItemOperations": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.ApplyChecklistItemOperations(ctx, "run1", []client.ChecklistItemOperation{{}}, true)
+			return err
+		},
+		"PlaybookRuns.ApplyRunsOperation": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.ApplyRunsOperation(ctx, client.RunsOperation{RunIDs: []string{"run1"}})
+			return err
+		},
+		"PlaybookRuns.SetItemStateByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemStateByID(ctx, "run1", ref, "closed")
+		},
+		"PlaybookRuns.SetItemAssigneeByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemAssigneeByID(ctx, "run1", ref, "user1")
+		},
+		"PlaybookRuns.SetItemDueDateByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemDueDateByID(ctx, "run1", ref, 1000)
+		},
+		"PlaybookRuns.SetItemTaskActionsByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemTaskActionsByID(ctx, "run1", ref, nil)
+		},
+		"PlaybookRuns.SetItemCommandByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemCommandByID(ctx, "run1", ref, "/echo")
+		},
+		"PlaybookRuns.EditItemByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.EditItemByID(ctx, "run1", ref, "title", "/echo", "description")
+		},
+		"PlaybookRuns.RunItemCommandByID": func(ctx context.Context) error { return c.PlaybookRuns.RunItemCommandByID(ctx, "run1", ref) },
+		"PlaybookRuns.SkipItemByID":       func(ctx context.Context) error { return c.PlaybookRuns.SkipItemByID(ctx, "run1", ref) },
+		"PlaybookRuns.RestoreItemByID":    func(ctx context.Context) error { return c.PlaybookRuns.RestoreItemByID(ctx, "run1", ref) },
+		"PlaybookRuns.DuplicateItemByID":  func(ctx context.Context) error { return c.PlaybookRuns.DuplicateItemByID(ctx, "run1", ref) },
+		"PlaybookRuns.RemoveItemByID":     func(ctx context.Context) error { return c.PlaybookRuns.RemoveItemByID(ctx, "run1", ref) },
+		"PlaybookRuns.MoveItemByID": func(ctx context.Context) error {
+			return c.PlaybookRuns.MoveItemByID(ctx, "run1", ref, "checklist1", &position)
+		},
+		"PlaybookRuns.Get": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.Get(ctx, "run1")
+			return err
+		},
+		"PlaybookRuns.GetByChannelID": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetByChannelID(ctx, "channel1")
+			return err
+		},
+		"PlaybookRuns.GetMetadata": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetMetadata(ctx, "run1")
+			return err
+		},
+		"PlaybookRuns.GetStatusUpdates": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetStatusUpdates(ctx, "run1")
+			return err
+		},
+		"PlaybookRuns.List": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.List(ctx, 0, 10, client.PlaybookRunListOptions{TeamID: "team1"})
+			return err
+		},
+		"PlaybookRuns.ListAll": func(ctx context.Context) error {
+			for _, err := range c.PlaybookRuns.ListAll(ctx, 10, client.PlaybookRunListOptions{TeamID: "team1"}) {
+				return err
+			}
+			return nil
+		},
+		"PlaybookRuns.Create": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.Create(ctx, client.PlaybookRunCreateOptions{Name: "Server down", OwnerUserID: "user1", TeamID: "team1", PlaybookID: "playbook1"})
+			return err
+		},
+		"PlaybookRuns.UpdateStatus": func(ctx context.Context) error {
+			return c.PlaybookRuns.UpdateStatus(ctx, "run1", "Investigating", 600)
+		},
+		"PlaybookRuns.Update": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.Update(ctx, "run1", client.PlaybookRunUpdateOptions{})
+			return err
+		},
+		"PlaybookRuns.UpdateIfUnmodified": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.UpdateIfUnmodified(ctx, "run1", 1000, client.PlaybookRunUpdateOptions{})
+			return err
+		},
+		"PlaybookRuns.RequestUpdate": func(ctx context.Context) error { return c.PlaybookRuns.RequestUpdate(ctx, "run1", "user1") },
+		"PlaybookRuns.Finish":        func(ctx context.Context) error { return c.PlaybookRuns.Finish(ctx, "run1") },
+		"PlaybookRuns.Restore":       func(ctx context.Context) error { return c.PlaybookRuns.Restore(ctx, "run1") },
+		"PlaybookRuns.CreateChecklist": func(ctx context.Context) error {
+			return c.PlaybookRuns.CreateChecklist(ctx, "run1", client.Checklist{Title: "Triage"})
+		},
+		"PlaybookRuns.RemoveChecklist": func(ctx context.Context) error { return c.PlaybookRuns.RemoveChecklist(ctx, "run1", 0) },
+		"PlaybookRuns.RenameChecklist": func(ctx context.Context) error {
+			return c.PlaybookRuns.RenameChecklist(ctx, "run1", 0, "Triage")
+		},
+		"PlaybookRuns.AddChecklistItem": func(ctx context.Context) error {
+			return c.PlaybookRuns.AddChecklistItem(ctx, "run1", 0, client.ChecklistItem{Title: "Check the logs"})
+		},
+		"PlaybookRuns.MoveChecklist": func(ctx context.Context) error { return c.PlaybookRuns.MoveChecklist(ctx, "run1", 0, 1) },
+		"PlaybookRuns.MoveChecklistItem": func(ctx context.Context) error {
+			return c.PlaybookRuns.MoveChecklistItem(ctx, "run1", 0, 0, 1, 0)
+		},
+		"PlaybookRuns.UpdateRetrospective": func(ctx context.Context) error {
+			return c.PlaybookRuns.UpdateRetrospective(ctx, "run1", "user1", client.RetrospectiveUpdate{})
+		},
+		"PlaybookRuns.PublishRetrospective": func(ctx context.Context) error {
+			return c.PlaybookRuns.PublishRetrospective(ctx, "run1", "user1", client.RetrospectiveUpdate{})
+		},
+		"PlaybookRuns.SetItemAssignee": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemAssignee(ctx, "run1", 0, 0, "user1")
+		},
+		"PlaybookRuns.SetItemRoleAssignee": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemRoleAssignee(ctx, "run1", 0, 0, "owner")
+		},
+		"PlaybookRuns.SetItemPropertyUserAssignee": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemPropertyUserAssignee(ctx, "run1", 0, 0, "field1")
+		},
+		"PlaybookRuns.SetItemRunRoleAssignee": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemRunRoleAssignee(ctx, "run1", 0, 0, "role1")
+		},
+		"PlaybookRuns.SetItemCommand": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemCommand(ctx, "run1", 0, 0, "/echo")
+		},
+		"PlaybookRuns.RunItemCommand": func(ctx context.Context) error { return c.PlaybookRuns.RunItemCommand(ctx, "run1", 0, 0) },
+		"PlaybookRuns.SetItemDueDate": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemDueDate(ctx, "run1", 0, 0, 1000)
+		},
+		"PlaybookRuns.SetItemTaskActions": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemTaskActions(ctx, "run1", 0, 0, nil)
+		},
+		"PlaybookRuns.SetItemState": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetItemState(ctx, "run1", 0, 0, "closed")
+		},
+		"PlaybookRuns.EditItem": func(ctx context.Context) error {
+			return c.PlaybookRuns.EditItem(ctx, "run1", 0, 0, "title", "/echo", "description")
+		},
+		"PlaybookRuns.RemoveItem":         func(ctx context.Context) error { return c.PlaybookRuns.RemoveItem(ctx, "run1", 0, 0) },
+		"PlaybookRuns.SkipItem":           func(ctx context.Context) error { return c.PlaybookRuns.SkipItem(ctx, "run1", 0, 0) },
+		"PlaybookRuns.RestoreItem":        func(ctx context.Context) error { return c.PlaybookRuns.RestoreItem(ctx, "run1", 0, 0) },
+		"PlaybookRuns.DuplicateItem":      func(ctx context.Context) error { return c.PlaybookRuns.DuplicateItem(ctx, "run1", 0, 0) },
+		"PlaybookRuns.SkipChecklist":      func(ctx context.Context) error { return c.PlaybookRuns.SkipChecklist(ctx, "run1", 0) },
+		"PlaybookRuns.RestoreChecklist":   func(ctx context.Context) error { return c.PlaybookRuns.RestoreChecklist(ctx, "run1", 0) },
+		"PlaybookRuns.DuplicateChecklist": func(ctx context.Context) error { return c.PlaybookRuns.DuplicateChecklist(ctx, "run1", 0) },
+		"PlaybookRuns.GetOwners": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetOwners(ctx)
+			return err
+		},
+		"PlaybookRuns.GetTodoDigest": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetTodoDigest(ctx)
+			return err
+		},
+		"PlaybookRuns.GetSharedPropertyFieldStats": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetSharedPropertyFieldStats(ctx, client.PlaybookRunListOptions{TeamID: "team1"})
+			return err
+		},
+		"PlaybookRuns.ChangeOwner": func(ctx context.Context) error { return c.PlaybookRuns.ChangeOwner(ctx, "run1", "user1") },
+		"PlaybookRuns.AssignRole":  func(ctx context.Context) error { return c.PlaybookRuns.AssignRole(ctx, "run1", "role1", "user1") },
+		"PlaybookRuns.AddParticipants": func(ctx context.Context) error {
+			return c.PlaybookRuns.AddParticipants(ctx, "run1", []string{"user1"}, false)
+		},
+		"PlaybookRuns.RemoveParticipant": func(ctx context.Context) error {
+			return c.PlaybookRuns.RemoveParticipant(ctx, "run1", "user1")
+		},
+		"PlaybookRuns.AddPostToTimeline": func(ctx context.Context) error {
+			return c.PlaybookRuns.AddPostToTimeline(ctx, "run1", "post1", "summary")
+		},
+		"PlaybookRuns.RemoveTimelineEvent": func(ctx context.Context) error {
+			return c.PlaybookRuns.RemoveTimelineEvent(ctx, "run1", "event1")
+		},
+		"PlaybookRuns.Follow":   func(ctx context.Context) error { return c.PlaybookRuns.Follow(ctx, "run1") },
+		"PlaybookRuns.Unfollow": func(ctx context.Context) error { return c.PlaybookRuns.Unfollow(ctx, "run1") },
+		"PlaybookRuns.GetFollowers": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetFollowers(ctx, "run1")
+			return err
+		},
+		"PlaybookRuns.CancelRetrospective": func(ctx context.Context) error { return c.PlaybookRuns.CancelRetrospective(ctx, "run1") },
+		"PlaybookRuns.SetStatusUpdatesEnabled": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetStatusUpdatesEnabled(ctx, "run1", true)
+		},
+		"PlaybookRuns.SetRetrospectiveEnabled": func(ctx context.Context) error {
+			return c.PlaybookRuns.SetRetrospectiveEnabled(ctx, "run1", true)
+		},
+		"PlaybookRuns.RequestJoinChannel": func(ctx context.Context) error { return c.PlaybookRuns.RequestJoinChannel(ctx, "run1") },
+		"PlaybookRuns.ListByChannel": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.ListByChannel(ctx, "channel1")
+			return err
+		},
+		"PlaybookRuns.ListChannelIDs": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.ListChannelIDs(ctx, client.PlaybookRunListOptions{TeamID: "team1"})
+			return err
+		},
+		"PlaybookRuns.GetPropertyFields": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetPropertyFields(ctx, "run1")
+			return err
+		},
+		"PlaybookRuns.GetPropertyFieldsSince": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetPropertyFieldsSince(ctx, "run1", 1000)
+			return err
+		},
+		"PlaybookRuns.GetPropertyValues": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetPropertyValues(ctx, "run1")
+			return err
+		},
+		"PlaybookRuns.GetPropertyValuesSince": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetPropertyValuesSince(ctx, "run1", 1000)
+			return err
+		},
+		"PlaybookRuns.GetPropertyValuesAsOf": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetPropertyValuesAsOf(ctx, "run1", 1000)
+			return err
+		},
+		"PlaybookRuns.GetPropertyValueHistory": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetPropertyValueHistory(ctx, "run1", "field1")
+			return err
+		},
+		"PlaybookRuns.SetPropertyValue": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.SetPropertyValue(ctx, "run1", "field1", client.PropertyValueRequest{})
+			return err
+		},
+		"PlaybookRuns.GetChanges": func(ctx context.Context) error {
+			_, err := c.PlaybookRuns.GetChanges(ctx, client.RunChangesOptions{TeamID: "team1"}, 0)
+			return err
+		},
+		"PlaybookRuns.SubscribeChanges": func(ctx context.Context) error {
+			return c.PlaybookRuns.SubscribeChanges(ctx, client.RunChangesOptions{TeamID: "team1"}, func(client.RunChange) error { return nil })
+		},
+		"Playbooks.Get": func(ctx context.Context) error {
+			_, err := c.Playbooks.Get(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.List": func(ctx context.Context) error {
+			_, err := c.Playbooks.List(ctx, "team1", 0, 10, client.PlaybookListOptions{})
+			return err
+		},
+		"Playbooks.ListAll": func(ctx context.Context) error {
+			for _, err := range c.Playbooks.ListAll(ctx, "team1", 10, client.PlaybookListOptions{}) {
+				return err
+			}
+			return nil
+		},
+		"Playbooks.Create": func(ctx context.Context) error {
+			_, err := c.Playbooks.Create(ctx, client.PlaybookCreateOptions{Title: "Incident", TeamID: "team1"})
+			return err
+		},
+		"Playbooks.Update": func(ctx context.Context) error { return c.Playbooks.Update(ctx, client.Playbook{ID: "playbook1"}) },
+		"Playbooks.UpdateIfUnmodified": func(ctx context.Context) error {
+			return c.Playbooks.UpdateIfUnmodified(ctx, client.Playbook{ID: "playbook1"})
+		},
+		"Playbooks.Archive": func(ctx context.Context) error { return c.Playbooks.Archive(ctx, "playbook1") },
+		"Playbooks.Restore": func(ctx context.Context) error { return c.Playbooks.Restore(ctx, "playbook1") },
+		"Playbooks.Patch": func(ctx context.Context) error {
+			return c.Playbooks.Patch(ctx, "playbook1", client.PlaybookPatch{})
+		},
+		"Playbooks.AddMember":    func(ctx context.Context) error { return c.Playbooks.AddMember(ctx, "playbook1", "user1") },
+		"Playbooks.RemoveMember": func(ctx context.Context) error { return c.Playbooks.RemoveMember(ctx, "playbook1", "user1") },
+		"Playbooks.AddMetric": func(ctx context.Context) error {
+			_, err := c.Playbooks.AddMetric(ctx, "playbook1", client.PlaybookMetricConfig{Title: "Time to resolve", Type: client.MetricTypeDuration})
+			return err
+		},
+		"Playbooks.UpdateMetric": func(ctx context.Context) error {
+			_, err := c.Playbooks.UpdateMetric(ctx, "playbook1", "metric1", client.PlaybookMetricPatch{})
+			return err
+		},
+		"Playbooks.DeleteMetric": func(ctx context.Context) error { return c.Playbooks.DeleteMetric(ctx, "playbook1", "metric1") },
+		"Playbooks.Export": func(ctx context.Context) error {
+			_, err := c.Playbooks.Export(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.Duplicate": func(ctx context.Context) error {
+			_, err := c.Playbooks.Duplicate(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.Import": func(ctx context.Context) error {
+			_, err := c.Playbooks.Import(ctx, []byte(`{}`), "team1")
+			return err
+		},
+		"Playbooks.ExportBundle": func(ctx context.Context) error {
+			_, err := c.Playbooks.ExportBundle(ctx, []string{"playbook1"})
+			return err
+		},
+		"Playbooks.ImportBundle": func(ctx context.Context) error {
+			_, err := c.Playbooks.ImportBundle(ctx, []byte(`{}`), "team1", true)
+			return err
+		},
+		"Playbooks.Stats": func(ctx context.Context) error {
+			_, err := c.Playbooks.Stats(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.AutoFollow":   func(ctx context.Context) error { return c.Playbooks.AutoFollow(ctx, "playbook1", "user1") },
+		"Playbooks.AutoUnfollow": func(ctx context.Context) error { return c.Playbooks.AutoUnfollow(ctx, "playbook1", "user1") },
+		"Playbooks.GetAutoFollows": func(ctx context.Context) error {
+			_, err := c.Playbooks.GetAutoFollows(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.GetPropertyFields": func(ctx context.Context) error {
+			_, err := c.Playbooks.GetPropertyFields(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.GetPropertyFieldsSince": func(ctx context.Context) error {
+			_, err := c.Playbooks.GetPropertyFieldsSince(ctx, "playbook1", 1000)
+			return err
+		},
+		"Playbooks.CreatePropertyField": func(ctx context.Context) error {
+			_, err := c.Playbooks.CreatePropertyField(ctx, "playbook1", client.PropertyFieldRequest{})
+			return err
+		},
+		"Playbooks.UpdatePropertyField": func(ctx context.Context) error {
+			_, err := c.Playbooks.UpdatePropertyField(ctx, "playbook1", "field1", client.PropertyFieldRequest{})
+			return err
+		},
+		"Playbooks.DeletePropertyField": func(ctx context.Context) error {
+			return c.Playbooks.DeletePropertyField(ctx, "playbook1", "field1")
+		},
+		"Playbooks.ReorderPropertyFields": func(ctx context.Context) error {
+			_, err := c.Playbooks.ReorderPropertyFields(ctx, "playbook1", client.ReorderPropertyFieldsRequest{})
+			return err
+		},
+		"Playbooks.GetTeamPropertyFields": func(ctx context.Context) error {
+			_, err := c.Playbooks.GetTeamPropertyFields(ctx, "team1")
+			return err
+		},
+		"Playbooks.CreateTeamPropertyField": func(ctx context.Context) error {
+			_, err := c.Playbooks.CreateTeamPropertyField(ctx, "team1", client.PropertyFieldRequest{})
+			return err
+		},
+		"Playbooks.UpdateTeamPropertyField": func(ctx context.Context) error {
+			_, err := c.Playbooks.UpdateTeamPropertyField(ctx, "team1", "field1", client.PropertyFieldRequest{})
+			return err
+		},
+		"Playbooks.DeleteTeamPropertyField": func(ctx context.Context) error {
+			return c.Playbooks.DeleteTeamPropertyField(ctx, "team1", "field1")
+		},
+		"Playbooks.MergeTeamPropertyFields": func(ctx context.Context) error {
+			_, err := c.Playbooks.MergeTeamPropertyFields(ctx, "team1", "Priority")
+			return err
+		},
+		"Playbooks.GetRevisions": func(ctx context.Context) error {
+			_, err := c.Playbooks.GetRevisions(ctx, "playbook1", 0, 10)
+			return err
+		},
+		"Playbooks.GetRevision": func(ctx context.Context) error {
+			_, err := c.Playbooks.GetRevision(ctx, "playbook1", 1)
+			return err
+		},
+		"Playbooks.DiffRevisions": func(ctx context.Context) error {
+			_, err := c.Playbooks.DiffRevisions(ctx, "playbook1", 1, 2)
+			return err
+		},
+		"Playbooks.RollbackRevision": func(ctx context.Context) error {
+			_, err := c.Playbooks.RollbackRevision(ctx, "playbook1", 1)
+			return err
+		},
+		"Playbooks.DiffDraft": func(ctx context.Context) error {
+			_, err := c.Playbooks.DiffDraft(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.Publish": func(ctx context.Context) error {
+			_, err := c.Playbooks.Publish(ctx, "playbook1")
+			return err
+		},
+		"Playbooks.PropagateChecklists": func(ctx context.Context) error {
+			_, err := c.Playbooks.PropagateChecklists(ctx, "playbook1", true)
+			return err
+		},
+		"Reminders.Reset": func(ctx context.Context) error {
+			return c.Reminders.Reset(ctx, "run1", client.ReminderResetPayload{NewReminderSeconds: 600})
+		},
+		"RunConditions.List": func(ctx context.Context) error {
+			_, err := c.RunConditions.List(ctx, "run1", 0, 10, client.RunConditionListOptions{})
+			return err
+		},
+		"RunConditions.ListAll": func(ctx context.Context) error {
+			for _, err := range c.RunConditions.ListAll(ctx, "run1", 10) {
+				return err
+			}
+			return nil
+		},
+		"Settings.Get": func(ctx context.Context) error {
+			_, err := c.Settings.Get(ctx)
+			return err
+		},
+		"Stats.GetSiteStats": func(ctx context.Context) error {
+			_, err := c.Stats.GetSiteStats(ctx)
+			return err
+		},
+		"TabApp.GetRuns": func(ctx context.Context) error {
+			_, err := c.TabApp.GetRuns(ctx, "token", client.TabAppGetRunsOptions{Page: 0, PerPage: 10})
+			return err
+		},
+	}
+
+	// Every method of the client services needs a test case, unless the server has no endpoint
+	// for it.
+	withoutEndpoint := map[string]bool{
+		"Settings.Update": true,
+	}
+	clientType := reflect.TypeOf(*c)
+	for i := 0; i < clientType.NumField(); i++ {
+		field := clientType.Field(i)
+		if field.Type.Kind() != reflect.Ptr || !strings.HasSuffix(field.Type.Elem().Name(), "Service") {
+			continue
+		}
+		for j := 0; j < field.Type.NumMethod(); j++ {
+			name := field.Name + "." + field.Type.Method(j).Name
+			_, tested := testCases[name]
+			assert.True(t, tested || withoutEndpoint[name], "client method %s has no test case", name)
+		}
+	}
+
+	for name, call := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requests = nil
+			assert.Error(t, call(context.Background()))
+			require.NotEmpty(t, requests, "the client made no request")
+			for _, request := range requests {
+				_, _, ok := spec.Operation(request.method, request.path)
+				assert.True(t, ok, "%s %s is not in the spec", request.method, request.path)
+			}
+		})
+	}
+}
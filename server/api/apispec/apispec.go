@@ -0,0 +1,336 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package apispec checks requests and responses of the plugin API against its OpenAPI spec in
+// api.yaml. It is used by the tests of the API and of the plugin.
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Prefix is the path prefix of the routes of the plugin, as written in the paths of the spec.
+const Prefix = "/plugins/playbooks"
+
+// Spec is the subset of the OpenAPI spec in api.yaml checked by the tests.
+type Spec struct {
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components struct {
+		Parameters map[string]*Parameter `yaml:"parameters"`
+		Responses  map[string]*Response  `yaml:"responses"`
+		Schemas    map[string]*Schema    `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// PathItem holds the operations of a path.
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Patch      *Operation   `yaml:"patch"`
+	Delete     *Operation   `yaml:"delete"`
+}
+
+// Operations returns the operations of the path by HTTP method.
+func (p *PathItem) Operations() map[string]*Operation {
+	operations := map[string]*Operation{}
+	for method, operation := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodPatch:  p.Patch,
+		http.MethodDelete: p.Delete,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+// Operation is an operation of a path, for a single HTTP method.
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *Response            `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a parameter of an operation, or a reference to one.
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// Response is a response or request body of an operation, or a reference to one.
+type Response struct {
+	Ref     string `yaml:"$ref"`
+	Content map[string]struct {
+		Schema *Schema `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+// Schema describes a JSON value, or references a schema that does.
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Nullable   bool               `yaml:"nullable"`
+	Enum       []interface{}      `yaml:"enum"`
+	Required   []string           `yaml:"required"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Items      *Schema            `yaml:"items"`
+	AllOf      []*Schema          `yaml:"allOf"`
+	OneOf      []*Schema          `yaml:"oneOf"`
+}
+
+// Load reads the spec from the api.yaml file at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// ResolveParameter returns the parameter a $ref points to, or the parameter itself.
+func (s *Spec) ResolveParameter(parameter *Parameter) (*Parameter, error) {
+	if parameter.Ref == "" {
+		return parameter, nil
+	}
+	resolved, ok := s.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+	if !ok {
+		return nil, fmt.Errorf("unresolved parameter %s", parameter.Ref)
+	}
+	return resolved, nil
+}
+
+// ResolveResponse returns the response a $ref points to, or the response itself.
+func (s *Spec) ResolveResponse(response *Response) (*Response, error) {
+	if response.Ref == "" {
+		return response, nil
+	}
+	resolved, ok := s.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	if !ok {
+		return nil, fmt.Errorf("unresolved response %s", response.Ref)
+	}
+	return resolved, nil
+}
+
+// ResolveSchema returns the schema a $ref points to, or the schema itself.
+func (s *Spec) ResolveSchema(schema *Schema) (*Schema, error) {
+	for schema.Ref != "" {
+		resolved, ok := s.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			return nil, fmt.Errorf("unresolved schema %s", schema.Ref)
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+// PathParameters returns the names of the path parameters declared for an operation, either on
+// the operation or on its path.
+func (s *Spec) PathParameters(pathItem *PathItem, operation *Operation) ([]string, error) {
+	var names []string
+	for _, parameter := range append(append([]*Parameter{}, pathItem.Parameters...), operation.Parameters...) {
+		resolved, err := s.ResolveParameter(parameter)
+		if err != nil {
+			return nil, err
+		}
+		if resolved.In == "path" {
+			names = append(names, resolved.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Validate checks value, decoded from JSON, against schema. It covers the keywords used in
+// api.yaml: types, nullable, enums, required properties, items and allOf/oneOf compositions.
+// Properties absent from the schema are allowed, as are values for schemas without a type.
+func (s *Spec) Validate(schema *Schema, value interface{}, path string) error {
+	schema, err := s.ResolveSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range schema.AllOf {
+		if err := s.Validate(sub, value, path); err != nil {
+			return err
+		}
+	}
+
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, sub := range schema.OneOf {
+			if s.Validate(sub, value, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: matches %d of the oneOf schemas instead of 1", path, matches)
+		}
+	}
+
+	if value == nil {
+		if schema.Type == "" || schema.Nullable {
+			return nil
+		}
+		return fmt.Errorf("%s: null for a non-nullable %s", path, schema.Type)
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, allowed := range schema.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, schema.Enum)
+		}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %T", path, value)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required property %s", path, name)
+			}
+		}
+		for name, propertySchema := range schema.Properties {
+			propertyValue, ok := object[name]
+			if !ok {
+				continue
+			}
+			if err := s.Validate(propertySchema, propertyValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array, got %T", path, value)
+		}
+		if schema.Items != nil {
+			for i, item := range array {
+				if err := s.Validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected a string, got %T", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected a boolean, got %T", path, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected a number, got %T", path, value)
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return fmt.Errorf("%s: expected an integer, got %v", path, value)
+		}
+	}
+
+	return nil
+}
+
+// Operation finds the operation of the spec serving a request, matching path against the path
+// templates of the spec. As in the router, literal segments win over parameters, so
+// /runs/owners is not taken for /runs/{id}.
+func (s *Spec) Operation(method, path string) (string, *Operation, bool) {
+	var (
+		found      string
+		foundCount int
+	)
+	for template, pathItem := range s.Paths {
+		if _, ok := pathItem.Operations()[method]; !ok || !specPathRegexp(template).MatchString(path) {
+			continue
+		}
+		count := strings.Count(template, "{")
+		if found == "" || count < foundCount || (count == foundCount && template < found) {
+			found, foundCount = template, count
+		}
+	}
+	if found == "" {
+		return "", nil, false
+	}
+	return found, s.Paths[found].Operations()[method], true
+}
+
+var specPathParameterRegexp = regexp.MustCompile(`\\\{[^}]+\}`)
+
+// specPathRegexp returns a regexp matching the paths of requests to a path template of the spec.
+func specPathRegexp(template string) *regexp.Regexp {
+	return regexp.MustCompile("^" + specPathParameterRegexp.ReplaceAllString(regexp.QuoteMeta(template), `[^/]+`) + "$")
+}
+
+// CheckResponse checks the status and body of a response to a request to path against the
+// schema the spec gives for the operation and status code.
+func (s *Spec) CheckResponse(method, path string, status int, body []byte) error {
+	template, operation, ok := s.Operation(method, path)
+	if !ok {
+		return fmt.Errorf("no operation for %s %s in api.yaml", method, path)
+	}
+
+	response, ok := operation.Responses[fmt.Sprint(status)]
+	if !ok {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("no %d response for %s %s in api.yaml", status, method, template)
+	}
+
+	response, err := s.ResolveResponse(response)
+	if err != nil {
+		return err
+	}
+
+	content, ok := response.Content["application/json"]
+	if !ok || content.Schema == nil {
+		if len(bytes.TrimSpace(body)) > 0 {
+			return fmt.Errorf("%d response for %s %s has no body in api.yaml", status, method, template)
+		}
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("%d response for %s %s is not JSON: %w", status, method, template, err)
+	}
+	if err := s.Validate(content.Schema, value, "body"); err != nil {
+		return fmt.Errorf("%d response for %s %s: %w", status, method, template, err)
+	}
+	return nil
+}
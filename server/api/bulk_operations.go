@@ -0,0 +1,263 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// bulkOperationResults is the response of the bulk endpoints.
+type bulkOperationResults struct {
+	Results []app.BulkOperationResult `json:"results"`
+}
+
+// bulkOperationRejectedResponse is the body of the response to a bulk request that was rejected
+// without applying any operation.
+type bulkOperationRejectedResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details"`
+}
+
+// checklistItemOperationsParams is the body of POST /runs/{id}/checklists/items/bulk.
+type checklistItemOperationsParams struct {
+	Operations []app.ChecklistItemOperation `json:"operations"`
+
+	// Atomic applies the operations together in a single update of the run, applying nothing when
+	// any operation is invalid.
+	Atomic bool `json:"atomic"`
+}
+
+// bulkRunsParams is the body of POST /runs/bulk.
+type bulkRunsParams struct {
+	Type   string   `json:"type"`
+	RunIDs []string `json:"run_ids"`
+
+	// OwnerID is the new owner of the runs for change_owner.
+	OwnerID string `json:"owner_id"`
+
+	// UserIDs are the users added to the runs for add_participants.
+	UserIDs           []string `json:"user_ids"`
+	ForceAddToChannel bool     `json:"force_add_to_channel"`
+
+	// ValidateFirst checks the operation against every run before applying it, rejecting the whole
+	// request when it can't be applied to one of them. The runs are still updated one at a time,
+	// so the results tell which runs it failed on if one changes in between.
+	ValidateFirst bool `json:"validate_first"`
+}
+
+// applyChecklistItemOperations handles POST /runs/{id}/checklists/items/bulk, applying in order a
+// list of operations addressed by checklist item ID.
+func (h *PlaybookRunHandler) applyChecklistItemOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookRunID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params checklistItemOperationsParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode payload", err)
+		return
+	}
+	if len(params.Operations) == 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "operations must not be empty", nil)
+		return
+	}
+	if len(params.Operations) > app.MaxBulkOperations {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, fmt.Sprintf("at most %d operations are allowed", app.MaxBulkOperations), nil)
+		return
+	}
+
+	for _, op := range params.Operations {
+		if op.Type == app.ChecklistItemOperationSetDueDate && !h.licenseChecker.ChecklistItemDueDateAllowed() {
+			h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "checklist item due date feature is not covered by current server license", nil)
+			return
+		}
+	}
+
+	results, err := h.playbookRunService.ApplyChecklistItemOperations(playbookRunID, userID, params.Operations, params.Atomic)
+	if err != nil {
+		var bulkErr *app.BulkOperationError
+		if errors.As(err, &bulkErr) {
+			c.logger.WithError(err).Warn("rejected checklist item operations")
+			ReturnJSON(w, &bulkOperationRejectedResponse{
+				Error:   "no operation was applied",
+				Details: bulkErr.PublicMessage(),
+			}, http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, app.ErrStaleVersion) {
+			c.logger.WithError(err).Warn("run kept changing while applying checklist item operations")
+			ReturnJSON(w, &bulkOperationRejectedResponse{
+				Error:   "no operation was applied",
+				Details: "the run was modified concurrently",
+			}, http.StatusConflict)
+			return
+		}
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, &bulkOperationResults{Results: results}, http.StatusOK)
+}
+
+// applyBulkRunsOperation handles POST /runs/bulk, changing the owner of, adding participants to or
+// finishing many runs at once. Permissions are checked for each run. The runs are updated one at a
+// time, so the operation can be applied to some of them only, as the results report.
+func (h *PlaybookRunHandler) applyBulkRunsOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params bulkRunsParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode payload", err)
+		return
+	}
+	if len(params.RunIDs) == 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "run_ids must not be empty", nil)
+		return
+	}
+	if len(params.RunIDs) > app.MaxBulkOperations {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, fmt.Sprintf("at most %d runs are allowed", app.MaxBulkOperations), nil)
+		return
+	}
+	for _, runID := range params.RunIDs {
+		if !model.IsValidId(runID) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "run_ids must contain only valid IDs", nil)
+			return
+		}
+	}
+
+	switch params.Type {
+	case app.RunOperationChangeOwner:
+		if !model.IsValidId(params.OwnerID) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "owner_id must be a valid ID", nil)
+			return
+		}
+	case app.RunOperationAddParticipants:
+		if len(params.UserIDs) == 0 {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "user_ids must not be empty", nil)
+			return
+		}
+		for _, participantID := range params.UserIDs {
+			if !model.IsValidId(participantID) {
+				h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "user_ids must contain only valid IDs", nil)
+				return
+			}
+		}
+	case app.RunOperationFinish:
+	default:
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, fmt.Sprintf("unknown operation type %q", params.Type), nil)
+		return
+	}
+
+	if params.ValidateFirst {
+		for i, runID := range params.RunIDs {
+			if publicMsg, err := h.checkRunOperation(userID, runID, params); err != nil {
+				c.logger.WithError(err).WithField("playbook_run_id", runID).Warn("rejected bulk run operation")
+				ReturnJSON(w, &bulkOperationRejectedResponse{
+					Error:   "no operation was applied",
+					Details: fmt.Sprintf("operation %d: %s", i, publicMsg),
+				}, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	results := make([]app.BulkOperationResult, 0, len(params.RunIDs))
+	for i, runID := range params.RunIDs {
+		result := app.BulkOperationResult{Index: i, ID: runID, Success: true}
+		if publicMsg, err := h.applyRunOperation(userID, runID, params); err != nil {
+			c.logger.WithError(err).WithField("playbook_run_id", runID).Warn("failed to apply bulk run operation")
+			result.Success = false
+			result.Error = publicMsg
+		}
+		results = append(results, result)
+	}
+
+	ReturnJSON(w, &bulkOperationResults{Results: results}, http.StatusOK)
+}
+
+// checkRunOperation checks that userID can apply the operation to the run, as the endpoint for a
+// single run would. It returns a public message and an internal error otherwise.
+func (h *PlaybookRunHandler) checkRunOperation(userID, playbookRunID string, params bulkRunsParams) (string, error) {
+	switch params.Type {
+	case app.RunOperationChangeOwner:
+		if err := h.permissions.RunChangeOwner(userID, playbookRunID); err != nil {
+			return "Not authorized", err
+		}
+	case app.RunOperationAddParticipants:
+		if updatesOnlyRequesterMembership(userID, params.UserIDs) {
+			if err := h.permissions.RunView(userID, playbookRunID); err != nil {
+				return "Not authorized", err
+			}
+			return "", nil
+		}
+		if err := h.permissions.RunManageProperties(userID, playbookRunID); err != nil {
+			return "Not authorized", err
+		}
+	case app.RunOperationFinish:
+		if err := h.permissions.RunFinish(userID, playbookRunID); err != nil {
+			return "Not authorized", err
+		}
+	}
+
+	playbookRun, err := h.playbookRunService.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		return "An internal error has occurred. Check app server logs for details.", err
+	}
+
+	switch params.Type {
+	case app.RunOperationFinish:
+		if playbookRun.CurrentStatus != app.StatusFinished {
+			var missingErr *app.MissingRequiredPropertiesError
+			if err = playbookRun.CheckRequiredOnFinish(); errors.As(err, &missingErr) {
+				return missingErr.PublicMessage(), err
+			}
+		}
+	default:
+		if err := app.EnsureRunIsActive(playbookRun); err != nil {
+			return "cannot modify a finished run", err
+		}
+	}
+
+	return "", nil
+}
+
+// applyRunOperation applies the operation to a single run. It returns a public message and an
+// internal error when the operation failed.
+func (h *PlaybookRunHandler) applyRunOperation(userID, playbookRunID string, params bulkRunsParams) (string, error) {
+	if publicMsg, err := h.checkRunOperation(userID, playbookRunID, params); err != nil {
+		return publicMsg, err
+	}
+
+	switch params.Type {
+	case app.RunOperationChangeOwner:
+		if err := h.playbookRunService.ChangeOwner(playbookRunID, userID, params.OwnerID); err != nil {
+			if errors.Is(err, app.ErrInvalidOwner) {
+				return err.Error(), err
+			}
+			return "An internal error has occurred. Check app server logs for details.", err
+		}
+	case app.RunOperationAddParticipants:
+		if err := h.playbookRunService.AddParticipants(playbookRunID, params.UserIDs, userID, params.ForceAddToChannel, false); err != nil {
+			return "An internal error has occurred. Check app server logs for details.", err
+		}
+	case app.RunOperationFinish:
+		if err := h.playbookRunService.FinishPlaybookRun(playbookRunID, userID); err != nil {
+			var missingErr *app.MissingRequiredPropertiesError
+			if errors.As(err, &missingErr) {
+				return missingErr.PublicMessage(), err
+			}
+			return "An internal error has occurred. Check app server logs for details.", err
+		}
+	}
+
+	return "", nil
+}
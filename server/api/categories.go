@@ -42,6 +42,8 @@ func NewCategoryHandler(router *mux.Router, api *pluginapi.Client, categoryServi
 	categoriesRouter.HandleFunc("", withContext(handler.getMyCategories)).Methods(http.MethodGet)
 	categoriesRouter.HandleFunc("", withContext(handler.createMyCategory)).Methods(http.MethodPost)
 	categoriesRouter.HandleFunc("/favorites", withContext(handler.isFavorite)).Methods(http.MethodGet)
+	categoriesRouter.HandleFunc("/favorites", withContext(handler.addFavorite)).Methods(http.MethodPut)
+	categoriesRouter.HandleFunc("/favorites", withContext(handler.removeFavorite)).Methods(http.MethodDelete)
 
 	categoryRouter := categoriesRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
 	categoryRouter.HandleFunc("", withContext(handler.updateMyCategory)).Methods(http.MethodPut)
@@ -252,6 +254,74 @@ func (h *CategoryHandler) isFavorite(c *Context, w http.ResponseWriter, r *http.
 	ReturnJSON(w, isFavorite, http.StatusOK)
 }
 
+func (h *CategoryHandler) addFavorite(c *Context, w http.ResponseWriter, r *http.Request) {
+	h.setFavorite(c, w, r, true)
+}
+
+func (h *CategoryHandler) removeFavorite(c *Context, w http.ResponseWriter, r *http.Request) {
+	h.setFavorite(c, w, r, false)
+}
+
+// setFavorite adds the playbook or run given by the item_id and type parameters to the favorites
+// of the user, or removes it. The favorites are those of the team of the item.
+func (h *CategoryHandler) setFavorite(c *Context, w http.ResponseWriter, r *http.Request, favorite bool) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	params := r.URL.Query()
+	itemID := params.Get("item_id")
+	itemType, err := app.StringToItemType(params.Get("type"))
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'type'", err)
+		return
+	}
+
+	var teamID string
+	switch itemType {
+	case app.PlaybookItemType:
+		if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookView(userID, itemID)) {
+			return
+		}
+		playbook, err := h.playbookService.Get(itemID)
+		if err != nil {
+			h.HandleError(w, c.logger, err)
+			return
+		}
+		if playbook.DeleteAt != 0 {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", itemID))
+			return
+		}
+		teamID = playbook.TeamID
+	case app.RunItemType:
+		if !h.PermissionsCheck(w, c.logger, h.permissions.RunView(userID, itemID)) {
+			return
+		}
+		playbookRun, err := h.playbookRunService.GetPlaybookRun(itemID)
+		if err != nil {
+			h.HandleError(w, c.logger, err)
+			return
+		}
+		teamID = playbookRun.TeamID
+	}
+
+	item := app.CategoryItem{ItemID: itemID, Type: itemType}
+	isFavorite, err := h.categoryService.IsItemFavorite(item, teamID, userID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	if favorite && !isFavorite {
+		err = h.categoryService.AddFavorite(item, teamID, userID)
+	} else if !favorite && isFavorite {
+		err = h.categoryService.DeleteFavorite(item, teamID, userID)
+	}
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *CategoryHandler) getRunsCategory(teamID, userID string) (app.Category, error) {
 	runs, err := h.playbookRunService.GetPlaybookRuns(
 		app.RequesterInfo{
@@ -162,6 +162,9 @@ func (h *ConditionHandler) createPlaybookCondition(c *Context, w http.ResponseWr
 		}
 		return
 	}
+	if !h.recordRevision(w, c, playbookID, userID) {
+		return
+	}
 
 	w.Header().Add("Location", makeAPIURL(h.pluginAPI, "playbooks/%s/conditions/%s", playbookID, createdCondition.ID))
 	ReturnJSON(w, createdCondition, http.StatusCreated)
@@ -225,6 +228,9 @@ func (h *ConditionHandler) updatePlaybookCondition(c *Context, w http.ResponseWr
 		}
 		return
 	}
+	if !h.recordRevision(w, c, playbookID, userID) {
+		return
+	}
 
 	ReturnJSON(w, updatedCondition, http.StatusOK)
 }
@@ -271,10 +277,23 @@ func (h *ConditionHandler) deletePlaybookCondition(c *Context, w http.ResponseWr
 		h.HandleError(w, c.logger, err)
 		return
 	}
+	if !h.recordRevision(w, c, playbookID, userID) {
+		return
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// recordRevision records a playbook revision after its conditions changed, writing an error
+// response and returning false if it could not be recorded.
+func (h *ConditionHandler) recordRevision(w http.ResponseWriter, c *Context, playbookID, userID string) bool {
+	if err := h.playbookService.RecordRevision(playbookID, userID); err != nil {
+		h.HandleError(w, c.logger, errors.Wrap(err, "failed to record playbook revision"))
+		return false
+	}
+	return true
+}
+
 // parsePaginationParams parses page and per_page query parameters from url.Values
 func parsePaginationParams(query url.Values) (page, perPage int) {
 	perPage = DefaultPerPage
@@ -5,15 +5,24 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
 )
 
 type ErrorHandler struct {
 }
 
-// HandleError logs the internal error and sends a generic error as JSON in a 500 response.
+// HandleError logs the internal error and sends a generic error as JSON in a 500 response. A
+// change to a checklist item addressed by ID that conflicts with a concurrent change is answered
+// with a 409 instead, as HandleChecklistItemConflict does.
 func (h *ErrorHandler) HandleError(w http.ResponseWriter, logger logrus.FieldLogger, internalErr error) {
+	if h.HandleChecklistItemConflict(w, logger, internalErr) {
+		return
+	}
 	h.HandleErrorWithCode(w, logger, http.StatusInternalServerError, "An internal error has occurred. Check app server logs for details.", internalErr)
 }
 
@@ -34,3 +43,75 @@ func (h *ErrorHandler) PermissionsCheck(w http.ResponseWriter, logger logrus.Fie
 
 	return true
 }
+
+// missingRequiredPropertiesResponse is the body of a 400 response for a run that is missing
+// required property values.
+type missingRequiredPropertiesResponse struct {
+	Error         string                     `json:"error"`
+	Requirement   string                     `json:"requirement"`
+	MissingFields []app.MissingPropertyField `json:"missing_fields"`
+}
+
+// HandleMissingRequiredProperties responds with a 400 listing the missing fields when err is an
+// app.MissingRequiredPropertiesError. It returns false, without writing a response, for any other error.
+func (h *ErrorHandler) HandleMissingRequiredProperties(w http.ResponseWriter, logger logrus.FieldLogger, err error) bool {
+	var missingErr *app.MissingRequiredPropertiesError
+	if !errors.As(err, &missingErr) {
+		return false
+	}
+
+	logger.WithError(err).Warn("required property values are missing")
+	ReturnJSON(w, &missingRequiredPropertiesResponse{
+		Error:         missingErr.PublicMessage(),
+		Requirement:   missingErr.Requirement,
+		MissingFields: missingErr.Fields,
+	}, http.StatusBadRequest)
+	return true
+}
+
+// malformedRunResponse is the body of a 400 response for a run that was rejected as malformed.
+type malformedRunResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details"`
+}
+
+// HandleMalformedRun responds with a 400 giving the reason a run was rejected, such as a channel
+// name template referencing empty fields, when err wraps app.ErrMalformedPlaybookRun. It returns
+// false, without writing a response, for any other error.
+func (h *ErrorHandler) HandleMalformedRun(w http.ResponseWriter, logger logrus.FieldLogger, err error) bool {
+	if !errors.Is(err, app.ErrMalformedPlaybookRun) {
+		return false
+	}
+
+	logger.WithError(err).Warn("unable to create playbook run")
+	ReturnJSON(w, &malformedRunResponse{
+		Error:   "unable to create playbook run",
+		Details: strings.TrimSuffix(err.Error(), ": "+app.ErrMalformedPlaybookRun.Error()),
+	}, http.StatusBadRequest)
+	return true
+}
+
+// checklistItemConflictResponse is the body of a 409 response for a change based on an outdated
+// version of a checklist item.
+type checklistItemConflictResponse struct {
+	Error string            `json:"error"`
+	Item  app.ChecklistItem `json:"item"`
+}
+
+// HandleChecklistItemConflict responds with a 409 holding the current version of the item when err
+// is an app.ChecklistItemConflictError, returned when the item was modified since the version the
+// change was based on or while the change was being made. It returns false, without writing a
+// response, for any other error.
+func (h *ErrorHandler) HandleChecklistItemConflict(w http.ResponseWriter, logger logrus.FieldLogger, err error) bool {
+	var conflictErr *app.ChecklistItemConflictError
+	if !errors.As(err, &conflictErr) {
+		return false
+	}
+
+	logger.WithError(err).Warn("checklist item was modified concurrently")
+	ReturnJSON(w, &checklistItemConflictResponse{
+		Error: "checklist item was modified",
+		Item:  conflictErr.Item,
+	}, http.StatusConflict)
+	return true
+}
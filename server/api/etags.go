@@ -0,0 +1,80 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// versionETag returns the entity tag of the version of a run or playbook last updated at updateAt.
+func versionETag(updateAt int64) string {
+	return strconv.Quote(strconv.FormatInt(updateAt, 10))
+}
+
+// setVersionETag sets the ETag header of a response holding the version of a run or playbook last
+// updated at updateAt.
+func setVersionETag(w http.ResponseWriter, updateAt int64) {
+	w.Header().Set("ETag", versionETag(updateAt))
+}
+
+// hasIfMatch reports whether the request makes an update conditional with an If-Match header.
+func hasIfMatch(r *http.Request) bool {
+	return strings.TrimSpace(r.Header.Get("If-Match")) != ""
+}
+
+// ifMatchVersion checks the If-Match header of the request against the current version of a run or
+// playbook, last updated at updateAt. It returns the UpdateAt the update must be conditional on, or
+// 0 when the header is absent or "*", and false when none of the listed entity tags match. Weak
+// entity tags never match, as If-Match uses the strong comparison.
+func ifMatchVersion(r *http.Request, updateAt int64) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	current := versionETag(updateAt)
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == current {
+			return updateAt, true
+		}
+	}
+
+	return 0, false
+}
+
+// staleVersionResponse is the body of a 412 response for a conditional update based on an outdated
+// version of a run or playbook. It holds the current version of the one being updated.
+type staleVersionResponse struct {
+	Error       string           `json:"error"`
+	PlaybookRun *app.PlaybookRun `json:"playbook_run,omitempty"`
+	Playbook    *app.Playbook    `json:"playbook,omitempty"`
+}
+
+// returnStaleRun responds with a 412 holding the current version of the run, which doesn't match
+// the If-Match header of the request.
+func returnStaleRun(w http.ResponseWriter, logger logrus.FieldLogger, playbookRun *app.PlaybookRun) {
+	logger.WithField("update_at", playbookRun.UpdateAt).Warn("playbook run was modified since the version in If-Match")
+	setVersionETag(w, playbookRun.UpdateAt)
+	ReturnJSON(w, &staleVersionResponse{
+		Error:       "playbook run was modified since the version in If-Match",
+		PlaybookRun: playbookRun,
+	}, http.StatusPreconditionFailed)
+}
+
+// returnStalePlaybook responds with a 412 holding the current version of the playbook, which
+// doesn't match the If-Match header of the request.
+func returnStalePlaybook(w http.ResponseWriter, logger logrus.FieldLogger, playbook *app.Playbook) {
+	logger.WithField("update_at", playbook.UpdateAt).Warn("playbook was modified since the version in If-Match")
+	setVersionETag(w, playbook.UpdateAt)
+	ReturnJSON(w, &staleVersionResponse{
+		Error:    "playbook was modified since the version in If-Match",
+		Playbook: playbook,
+	}, http.StatusPreconditionFailed)
+}
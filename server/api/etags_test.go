@@ -0,0 +1,42 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	testCases := []struct {
+		name             string
+		ifMatch          string
+		expectedUpdateAt int64
+		expectedMatches  bool
+	}{
+		{name: "no header", ifMatch: "", expectedUpdateAt: 0, expectedMatches: true},
+		{name: "any version", ifMatch: "*", expectedUpdateAt: 0, expectedMatches: true},
+		{name: "current version", ifMatch: `"1000"`, expectedUpdateAt: 1000, expectedMatches: true},
+		{name: "current version in a list", ifMatch: `"900", "1000"`, expectedUpdateAt: 1000, expectedMatches: true},
+		{name: "stale version", ifMatch: `"900"`, expectedUpdateAt: 0, expectedMatches: false},
+		{name: "weak tag", ifMatch: `W/"1000"`, expectedUpdateAt: 0, expectedMatches: false},
+		{name: "unquoted tag", ifMatch: `1000`, expectedUpdateAt: 0, expectedMatches: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/playbooks/id", nil)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			updateAt, matches := ifMatchVersion(r, 1000)
+			assert.Equal(t, tc.expectedUpdateAt, updateAt)
+			assert.Equal(t, tc.expectedMatches, matches)
+		})
+	}
+}
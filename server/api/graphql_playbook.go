@@ -235,6 +235,7 @@ type UpdateChecklistItem struct {
 	AssigneeModified        float64           `json:"assignee_modified"`
 	AssigneeType            *string           `json:"assignee_type"`
 	AssigneePropertyFieldID *string           `json:"assignee_property_field_id"`
+	AssigneeRoleID          *string           `json:"assignee_role_id"`
 	Command                 string            `json:"command"`
 	CommandLastRun          float64           `json:"command_last_run"`
 	Description             string            `json:"description"`
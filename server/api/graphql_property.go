@@ -17,6 +17,29 @@ type PropertyFieldAttrsGraphQLInput struct {
 	Options    *[]PropertyOptionGraphQLInput `json:"options"`
 	ParentID   *string                       `json:"parentID"`
 	ValueType  *string                       `json:"valueType"`
+	Min        *float64                      `json:"min"`
+	Max        *float64                      `json:"max"`
+	Integer    *bool                         `json:"integer"`
+
+	RequiredOnCreate *bool `json:"requiredOnCreate"`
+	RequiredOnFinish *bool `json:"requiredOnFinish"`
+
+	Computed *PropertyFieldComputedGraphQLInput `json:"computed"`
+
+	SharedFieldID *string `json:"sharedFieldID"`
+}
+
+type PropertyLookupEntryGraphQLInput struct {
+	Key   string  `json:"key"`
+	Value float64 `json:"value"`
+}
+
+type PropertyFieldComputedGraphQLInput struct {
+	Kind        string                             `json:"kind"`
+	Expression  *string                            `json:"expression"`
+	Checklist   *string                            `json:"checklist"`
+	SourceField *string                            `json:"sourceField"`
+	Lookup      *[]PropertyLookupEntryGraphQLInput `json:"lookup"`
 }
 
 type PropertyFieldGraphQLInput struct {
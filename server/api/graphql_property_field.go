@@ -5,6 +5,7 @@ package api
 
 import (
 	"context"
+	"sort"
 
 	"github.com/mattermost/mattermost/server/public/model"
 
@@ -23,6 +24,15 @@ type PropertyFieldAttrsResolver struct {
 	attrs app.Attrs
 }
 
+type PropertyFieldComputedResolver struct {
+	computed app.ComputedAttrs
+}
+
+type PropertyLookupEntryResolver struct {
+	key   string
+	value float64
+}
+
 func (r *PropertyFieldResolver) ID(ctx context.Context) string {
 	return r.propertyField.ID
 }
@@ -70,6 +80,13 @@ func (r *PropertyFieldAttrsResolver) ParentID(ctx context.Context) *string {
 	return &r.attrs.ParentID
 }
 
+func (r *PropertyFieldAttrsResolver) SharedFieldID(ctx context.Context) *string {
+	if r.attrs.SharedFieldID == "" {
+		return nil
+	}
+	return &r.attrs.SharedFieldID
+}
+
 func (r *PropertyFieldAttrsResolver) Options(ctx context.Context) *[]*PropertyOptionResolver {
 	if len(r.attrs.Options) == 0 {
 		return nil
@@ -89,6 +106,84 @@ func (r *PropertyFieldAttrsResolver) ValueType(ctx context.Context) *string {
 	return &r.attrs.ValueType
 }
 
+func (r *PropertyFieldAttrsResolver) Min(ctx context.Context) *float64 {
+	return r.attrs.Min
+}
+
+func (r *PropertyFieldAttrsResolver) Max(ctx context.Context) *float64 {
+	return r.attrs.Max
+}
+
+func (r *PropertyFieldAttrsResolver) Integer(ctx context.Context) bool {
+	return r.attrs.Integer
+}
+
+func (r *PropertyFieldAttrsResolver) RequiredOnCreate(ctx context.Context) bool {
+	return r.attrs.RequiredOnCreate
+}
+
+func (r *PropertyFieldAttrsResolver) RequiredOnFinish(ctx context.Context) bool {
+	return r.attrs.RequiredOnFinish
+}
+
+func (r *PropertyFieldAttrsResolver) Computed(ctx context.Context) *PropertyFieldComputedResolver {
+	if r.attrs.Computed == nil {
+		return nil
+	}
+	return &PropertyFieldComputedResolver{computed: *r.attrs.Computed}
+}
+
+func (r *PropertyFieldComputedResolver) Kind(ctx context.Context) string {
+	return r.computed.Kind
+}
+
+func (r *PropertyFieldComputedResolver) Expression(ctx context.Context) *string {
+	if r.computed.Expression == "" {
+		return nil
+	}
+	return &r.computed.Expression
+}
+
+func (r *PropertyFieldComputedResolver) Checklist(ctx context.Context) *string {
+	if r.computed.Checklist == "" {
+		return nil
+	}
+	return &r.computed.Checklist
+}
+
+func (r *PropertyFieldComputedResolver) SourceField(ctx context.Context) *string {
+	if r.computed.SourceField == "" {
+		return nil
+	}
+	return &r.computed.SourceField
+}
+
+func (r *PropertyFieldComputedResolver) Lookup(ctx context.Context) *[]*PropertyLookupEntryResolver {
+	if len(r.computed.Lookup) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(r.computed.Lookup))
+	for key := range r.computed.Lookup {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	resolvers := make([]*PropertyLookupEntryResolver, len(keys))
+	for i, key := range keys {
+		resolvers[i] = &PropertyLookupEntryResolver{key: key, value: r.computed.Lookup[key]}
+	}
+	return &resolvers
+}
+
+func (r *PropertyLookupEntryResolver) Key(ctx context.Context) string {
+	return r.key
+}
+
+func (r *PropertyLookupEntryResolver) Value(ctx context.Context) float64 {
+	return r.value
+}
+
 func (r *PropertyOptionResolver) ID(ctx context.Context) string {
 	return r.option.GetID()
 }
@@ -5,13 +5,10 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/pkg/errors"
 	"gopkg.in/guregu/null.v4"
 
-	"github.com/mattermost/mattermost/server/public/model"
-
 	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
 )
 
@@ -154,43 +151,7 @@ func (r *RunRootResolver) UpdatePlaybookFavorite(ctx context.Context, args struc
 
 func (r *PlaybookRootResolver) UpdatePlaybook(ctx context.Context, args struct {
 	ID      string
-	Updates struct {
-		Title                                   *string
-		Description                             *string
-		Public                                  *bool
-		CreatePublicPlaybookRun                 *bool
-		ReminderMessageTemplate                 *string
-		ReminderTimerDefaultSeconds             *float64
-		StatusUpdateEnabled                     *bool
-		InvitedUserIDs                          *[]string
-		InvitedGroupIDs                         *[]string
-		InviteUsersEnabled                      *bool
-		DefaultOwnerID                          *string
-		DefaultOwnerEnabled                     *bool
-		BroadcastChannelIDs                     *[]string
-		BroadcastEnabled                        *bool
-		WebhookOnCreationURLs                   *[]string
-		WebhookOnCreationEnabled                *bool
-		MessageOnJoin                           *string
-		MessageOnJoinEnabled                    *bool
-		RetrospectiveReminderIntervalSeconds    *float64
-		RetrospectiveTemplate                   *string
-		RetrospectiveEnabled                    *bool
-		WebhookOnStatusUpdateURLs               *[]string
-		WebhookOnStatusUpdateEnabled            *bool
-		SignalAnyKeywords                       *[]string
-		SignalAnyKeywordsEnabled                *bool
-		CategorizeChannelEnabled                *bool
-		CategoryName                            *string
-		RunSummaryTemplateEnabled               *bool
-		RunSummaryTemplate                      *string
-		ChannelNameTemplate                     *string
-		Checklists                              *[]UpdateChecklist
-		CreateChannelMemberOnNewParticipant     *bool
-		RemoveChannelMemberOnRemovedParticipant *bool
-		ChannelID                               *string
-		ChannelMode                             *string
-	}
+	Updates PlaybookUpdates
 }) (string, error) {
 	c, err := getContext(ctx)
 	if err != nil {
@@ -211,118 +172,13 @@ func (r *PlaybookRootResolver) UpdatePlaybook(ctx context.Context, args struct {
 		return "", errors.New("archived playbooks can not be modified")
 	}
 
-	setmap := map[string]interface{}{}
-	addToSetmap(setmap, "Title", args.Updates.Title)
-	addToSetmap(setmap, "Description", args.Updates.Description)
-	if args.Updates.Public != nil {
-		if *args.Updates.Public {
-			if err := c.permissions.PlaybookMakePublic(userID, currentPlaybook); err != nil {
-				return "", err
-			}
-		} else {
-			if err := c.permissions.PlaybookMakePrivate(userID, currentPlaybook); err != nil {
-				return "", err
-			}
-		}
-		if !c.licenceChecker.PlaybookAllowed(*args.Updates.Public) {
-			return "", errors.Wrapf(app.ErrLicensedFeature, "the playbook is not valid with the current license")
-		}
-		addToSetmap(setmap, "Public", args.Updates.Public)
-	}
-	addToSetmap(setmap, "CreatePublicIncident", args.Updates.CreatePublicPlaybookRun)
-	addToSetmap(setmap, "ReminderMessageTemplate", args.Updates.ReminderMessageTemplate)
-	addToSetmap(setmap, "ReminderTimerDefaultSeconds", args.Updates.ReminderTimerDefaultSeconds)
-	addToSetmap(setmap, "StatusUpdateEnabled", args.Updates.StatusUpdateEnabled)
-	addToSetmap(setmap, "CreateChannelMemberOnNewParticipant", args.Updates.CreateChannelMemberOnNewParticipant)
-	addToSetmap(setmap, "RemoveChannelMemberOnRemovedParticipant", args.Updates.RemoveChannelMemberOnRemovedParticipant)
-
-	if args.Updates.InvitedUserIDs != nil {
-		filteredInvitedUserIDs := c.permissions.FilterInvitedUserIDs(*args.Updates.InvitedUserIDs, currentPlaybook.TeamID)
-		addConcatToSetmap(setmap, "ConcatenatedInvitedUserIDs", &filteredInvitedUserIDs)
-	}
-
-	if args.Updates.InvitedGroupIDs != nil {
-		filteredInvitedGroupIDs := c.permissions.FilterInvitedGroupIDs(*args.Updates.InvitedGroupIDs)
-		addConcatToSetmap(setmap, "ConcatenatedInvitedGroupIDs", &filteredInvitedGroupIDs)
-	}
-
-	addToSetmap(setmap, "InviteUsersEnabled", args.Updates.InviteUsersEnabled)
-	if args.Updates.DefaultOwnerID != nil {
-		if !c.pluginAPI.User.HasPermissionToTeam(*args.Updates.DefaultOwnerID, currentPlaybook.TeamID, model.PermissionViewTeam) {
-			return "", errors.Wrap(app.ErrNoPermissions, "default owner can't view team")
-		}
-		addToSetmap(setmap, "DefaultCommanderID", args.Updates.DefaultOwnerID)
-	}
-	addToSetmap(setmap, "DefaultCommanderEnabled", args.Updates.DefaultOwnerEnabled)
-
-	if args.Updates.BroadcastChannelIDs != nil {
-		if err := c.permissions.NoAddedBroadcastChannelsWithoutPermission(userID, *args.Updates.BroadcastChannelIDs, currentPlaybook.BroadcastChannelIDs); err != nil {
-			return "", err
-		}
-		addConcatToSetmap(setmap, "ConcatenatedBroadcastChannelIDs", args.Updates.BroadcastChannelIDs)
-	}
-
-	addToSetmap(setmap, "BroadcastEnabled", args.Updates.BroadcastEnabled)
-	if args.Updates.WebhookOnCreationURLs != nil {
-		if err := app.ValidateWebhookURLs(*args.Updates.WebhookOnCreationURLs); err != nil {
-			return "", err
-		}
-		addConcatToSetmap(setmap, "ConcatenatedWebhookOnCreationURLs", args.Updates.WebhookOnCreationURLs)
-	}
-	addToSetmap(setmap, "WebhookOnCreationEnabled", args.Updates.WebhookOnCreationEnabled)
-	addToSetmap(setmap, "MessageOnJoin", args.Updates.MessageOnJoin)
-	addToSetmap(setmap, "MessageOnJoinEnabled", args.Updates.MessageOnJoinEnabled)
-	addToSetmap(setmap, "RetrospectiveReminderIntervalSeconds", args.Updates.RetrospectiveReminderIntervalSeconds)
-	addToSetmap(setmap, "RetrospectiveTemplate", args.Updates.RetrospectiveTemplate)
-	addToSetmap(setmap, "RetrospectiveEnabled", args.Updates.RetrospectiveEnabled)
-	if args.Updates.WebhookOnStatusUpdateURLs != nil {
-		if err := app.ValidateWebhookURLs(*args.Updates.WebhookOnStatusUpdateURLs); err != nil {
-			return "", err
-		}
-		addConcatToSetmap(setmap, "ConcatenatedWebhookOnStatusUpdateURLs", args.Updates.WebhookOnStatusUpdateURLs)
-	}
-	addToSetmap(setmap, "WebhookOnStatusUpdateEnabled", args.Updates.WebhookOnStatusUpdateEnabled)
-	if args.Updates.SignalAnyKeywords != nil {
-		validSignalAnyKeywords := app.ProcessSignalAnyKeywords(*args.Updates.SignalAnyKeywords)
-		addConcatToSetmap(setmap, "ConcatenatedSignalAnyKeywords", &validSignalAnyKeywords)
-	}
-	addToSetmap(setmap, "SignalAnyKeywordsEnabled", args.Updates.SignalAnyKeywordsEnabled)
-	addToSetmap(setmap, "CategorizeChannelEnabled", args.Updates.CategorizeChannelEnabled)
-	if args.Updates.CategoryName != nil {
-		if err := app.ValidateCategoryName(*args.Updates.CategoryName); err != nil {
-			return "", err
-		}
-		addToSetmap(setmap, "CategoryName", args.Updates.CategoryName)
-	}
-	addToSetmap(setmap, "RunSummaryTemplateEnabled", args.Updates.RunSummaryTemplateEnabled)
-	addToSetmap(setmap, "RunSummaryTemplate", args.Updates.RunSummaryTemplate)
-	addToSetmap(setmap, "ChannelNameTemplate", args.Updates.ChannelNameTemplate)
-	addToSetmap(setmap, "ChannelID", args.Updates.ChannelID)
-	addToSetmap(setmap, "ChannelMode", args.Updates.ChannelMode)
-
-	// Not optimal graphql. Stopgap measure. Should be updated separately.
-	if args.Updates.Checklists != nil {
-		app.CleanUpChecklists(*args.Updates.Checklists)
-		if err := validateUpdateTaskActions(*args.Updates.Checklists); err != nil {
-			return "", errors.Wrapf(err, "failed to validate task actions in graphql json for playbook id: '%s'", args.ID)
-		}
-		checklistsJSON, err := json.Marshal(args.Updates.Checklists)
-		if err != nil {
-			return "", errors.Wrapf(err, "failed to marshal checklist in graphql json for playbook id: '%s'", args.ID)
-		}
-		setmap["ChecklistsJSON"] = checklistsJSON
-	}
-
-	if args.Updates.Checklists != nil || args.Updates.InvitedUserIDs != nil || args.Updates.InviteUsersEnabled != nil {
-		if err := validatePreAssignmentUpdate(currentPlaybook, args.Updates.Checklists, args.Updates.InvitedUserIDs, args.Updates.InviteUsersEnabled); err != nil {
-			return "", errors.Wrapf(err, "invalid user pre-assignment for playbook id: '%s'", args.ID)
-		}
+	setmap, err := playbookUpdatesSetmap(c.permissions, c.licenceChecker, c.pluginAPI, userID, currentPlaybook, args.Updates)
+	if err != nil {
+		return "", err
 	}
 
-	if len(setmap) > 0 {
-		if err := c.playbookStore.GraphqlUpdate(args.ID, setmap); err != nil {
-			return "", err
-		}
+	if err := c.playbookService.GraphqlUpdate(args.ID, setmap, userID); err != nil {
+		return "", err
 	}
 
 	return args.ID, nil
@@ -351,8 +207,8 @@ func (r *PlaybookRootResolver) AddPlaybookMember(ctx context.Context, args struc
 		return "", errors.New("archived playbooks can not be modified")
 	}
 
-	if err := c.playbookStore.AddPlaybookMember(args.PlaybookID, args.UserID); err != nil {
-		return "", errors.Wrap(err, "unable to add playbook member")
+	if err := c.playbookService.AddMember(args.PlaybookID, args.UserID, userID); err != nil {
+		return "", err
 	}
 
 	return "", nil
@@ -384,8 +240,8 @@ func (r *PlaybookRootResolver) RemovePlaybookMember(ctx context.Context, args st
 		}
 	}
 
-	if err := c.playbookStore.RemovePlaybookMember(args.PlaybookID, args.UserID); err != nil {
-		return "", errors.Wrap(err, "unable to remove playbook member")
+	if err := c.playbookService.RemoveMember(args.PlaybookID, args.UserID, userID); err != nil {
+		return "", err
 	}
 
 	return "", nil
@@ -417,19 +273,16 @@ func (r *PlaybookRootResolver) AddMetric(ctx context.Context, args struct {
 		return "", err
 	}
 
-	var target null.Int
-	if args.Target == nil {
-		target = null.NewInt(0, false)
-	} else {
-		target = null.IntFrom(int64(*args.Target))
-	}
-
-	if err := c.playbookStore.AddMetric(args.PlaybookID, app.PlaybookMetricConfig{
+	metric := app.PlaybookMetricConfig{
 		Title:       args.Title,
 		Description: args.Description,
 		Type:        args.Type,
-		Target:      target,
-	}); err != nil {
+	}
+	if args.Target != nil {
+		metric.Target = null.IntFrom(int64(*args.Target))
+	}
+
+	if _, err := c.playbookService.AddMetric(args.PlaybookID, metric, userID); err != nil {
 		return "", err
 	}
 
@@ -448,7 +301,7 @@ func (r *PlaybookRootResolver) UpdateMetric(ctx context.Context, args struct {
 	}
 	userID := c.r.Header.Get("Mattermost-User-ID")
 
-	currentMetric, err := c.playbookStore.GetMetric(args.ID)
+	currentMetric, err := c.playbookService.GetMetric(args.ID)
 	if err != nil {
 		return "", err
 	}
@@ -466,16 +319,17 @@ func (r *PlaybookRootResolver) UpdateMetric(ctx context.Context, args struct {
 		return "", err
 	}
 
-	setmap := map[string]interface{}{}
-	addToSetmap(setmap, "Title", args.Title)
-	addToSetmap(setmap, "Description", args.Description)
+	patch := app.PlaybookMetricPatch{
+		Title:       args.Title,
+		Description: args.Description,
+	}
 	if args.Target != nil {
-		setmap["Target"] = null.IntFrom(int64(*args.Target))
+		target := int64(*args.Target)
+		patch.Target = &target
 	}
-	if len(setmap) > 0 {
-		if err := c.playbookStore.UpdateMetric(args.ID, setmap); err != nil {
-			return "", err
-		}
+
+	if _, err := c.playbookService.UpdateMetric(args.ID, patch, userID); err != nil {
+		return "", err
 	}
 
 	return args.ID, nil
@@ -490,7 +344,7 @@ func (r *PlaybookRootResolver) DeleteMetric(ctx context.Context, args struct {
 	}
 	userID := c.r.Header.Get("Mattermost-User-ID")
 
-	currentMetric, err := c.playbookStore.GetMetric(args.ID)
+	currentMetric, err := c.playbookService.GetMetric(args.ID)
 	if err != nil {
 		return "", err
 	}
@@ -504,7 +358,7 @@ func (r *PlaybookRootResolver) DeleteMetric(ctx context.Context, args struct {
 		return "", err
 	}
 
-	if err := c.playbookStore.DeleteMetric(args.ID); err != nil {
+	if err := c.playbookService.DeleteMetric(args.ID, userID); err != nil {
 		return "", err
 	}
 
@@ -81,7 +81,7 @@ func (r *PropertyRootResolver) AddPlaybookPropertyField(ctx context.Context, arg
 	propertyField := convertPropertyFieldGraphQLInputToPropertyField(args.PropertyField)
 
 	// Create the property field using the playbook service
-	createdField, err := c.playbookService.CreatePropertyField(args.PlaybookID, *propertyField)
+	createdField, err := c.playbookService.CreatePropertyField(args.PlaybookID, *propertyField, userID)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create property field")
 	}
@@ -134,7 +134,7 @@ func (r *PropertyRootResolver) UpdatePlaybookPropertyField(ctx context.Context,
 	propertyField.ID = args.PropertyFieldID
 
 	// Update the property field using the playbook service
-	updatedField, err := c.playbookService.UpdatePropertyField(args.PlaybookID, *propertyField)
+	updatedField, err := c.playbookService.UpdatePropertyField(args.PlaybookID, *propertyField, userID)
 	if err != nil {
 		if errors.Is(err, app.ErrPropertyOptionsInUse) {
 			return "", newGraphQLError(err)
@@ -188,7 +188,7 @@ func (r *PropertyRootResolver) DeletePlaybookPropertyField(ctx context.Context,
 	}
 
 	// Delete the property field using the playbook service
-	err = c.playbookService.DeletePropertyField(args.PlaybookID, args.PropertyFieldID)
+	err = c.playbookService.DeletePropertyField(args.PlaybookID, args.PropertyFieldID, userID)
 	if err != nil {
 		if errors.Is(err, app.ErrPropertyFieldInUse) {
 			return "", newGraphQLError(err)
@@ -229,8 +229,8 @@ func (r *PropertyRootResolver) SetRunPropertyValue(ctx context.Context, args str
 		return "", errors.Wrap(err, "failed to get playbook run")
 	}
 
-	// Check permissions to modify the run
-	if err := c.permissions.RunManageProperties(userID, playbookRun.ID); err != nil {
+	// Check permissions to set the run's property values
+	if err := c.permissions.RunSetPropertyValues(userID, playbookRun.ID); err != nil {
 		return "", err
 	}
 
@@ -300,10 +300,48 @@ func convertPropertyFieldGraphQLInputToPropertyField(input PropertyFieldGraphQLI
 			attrs.ParentID = *input.Attrs.ParentID
 		}
 
+		if input.Attrs.SharedFieldID != nil {
+			attrs.SharedFieldID = *input.Attrs.SharedFieldID
+		}
+
 		if input.Attrs.ValueType != nil {
 			attrs.ValueType = *input.Attrs.ValueType
 		}
 
+		attrs.Min = input.Attrs.Min
+		attrs.Max = input.Attrs.Max
+
+		if input.Attrs.Integer != nil {
+			attrs.Integer = *input.Attrs.Integer
+		}
+
+		if input.Attrs.RequiredOnCreate != nil {
+			attrs.RequiredOnCreate = *input.Attrs.RequiredOnCreate
+		}
+
+		if input.Attrs.RequiredOnFinish != nil {
+			attrs.RequiredOnFinish = *input.Attrs.RequiredOnFinish
+		}
+
+		if computed := input.Attrs.Computed; computed != nil {
+			attrs.Computed = &app.ComputedAttrs{Kind: computed.Kind}
+			if computed.Expression != nil {
+				attrs.Computed.Expression = *computed.Expression
+			}
+			if computed.Checklist != nil {
+				attrs.Computed.Checklist = *computed.Checklist
+			}
+			if computed.SourceField != nil {
+				attrs.Computed.SourceField = *computed.SourceField
+			}
+			if computed.Lookup != nil {
+				attrs.Computed.Lookup = make(map[string]float64, len(*computed.Lookup))
+				for _, entry := range *computed.Lookup {
+					attrs.Computed.Lookup[entry.Key] = entry.Value
+				}
+			}
+		}
+
 		propertyField.Attrs = attrs
 	} else {
 		propertyField.Attrs = app.Attrs{
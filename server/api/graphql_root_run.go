@@ -392,7 +392,7 @@ func (r *RunRootResolver) UpdateRunTaskActions(ctx context.Context, args struct
 	}
 	userID := c.r.Header.Get("Mattermost-User-ID")
 
-	if err = c.permissions.RunManageProperties(userID, args.RunID); err != nil {
+	if err = c.permissions.RunEditChecklists(userID, args.RunID); err != nil {
 		return "", err
 	}
 
@@ -27,6 +27,11 @@ func (r *statusRecorder) WriteHeader(code int) {
 	r.statusCode = code
 }
 
+// Unwrap returns the wrapped http.ResponseWriter, letting http.ResponseController flush it.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // LogRequest logs each request, attaching a unique request_id to the request context to trace
 // logs throughout the request lifecycle.
 func LogRequest(next http.Handler) http.Handler {
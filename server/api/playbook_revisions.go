@@ -0,0 +1,266 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// PlaybookRevisionHandler handles the playbook revision history and publishing endpoints
+type PlaybookRevisionHandler struct {
+	*ErrorHandler
+	revisionService    app.PlaybookRevisionService
+	playbookService    app.PlaybookService
+	playbookRunService app.PlaybookRunService
+	permissions        *app.PermissionsService
+}
+
+// NewPlaybookRevisionHandler creates the playbook revision API handler and sets up routes
+func NewPlaybookRevisionHandler(router *mux.Router, revisionService app.PlaybookRevisionService, playbookService app.PlaybookService, playbookRunService app.PlaybookRunService, permissions *app.PermissionsService) *PlaybookRevisionHandler {
+	handler := &PlaybookRevisionHandler{
+		ErrorHandler:       &ErrorHandler{},
+		revisionService:    revisionService,
+		playbookService:    playbookService,
+		playbookRunService: playbookRunService,
+		permissions:        permissions,
+	}
+
+	// Playbook revisions: /playbooks/{id}/revisions
+	playbooksRouter := router.PathPrefix("/playbooks").Subrouter()
+	playbookRouter := playbooksRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
+	revisionsRouter := playbookRouter.PathPrefix("/revisions").Subrouter()
+	revisionsRouter.HandleFunc("", withContext(handler.getRevisions)).Methods(http.MethodGet)
+	revisionsRouter.HandleFunc("/diff", withContext(handler.diffRevisions)).Methods(http.MethodGet)
+
+	playbookRouter.HandleFunc("/publish", withContext(handler.publish)).Methods(http.MethodPost)
+	playbookRouter.HandleFunc("/propagate_checklists", withContext(handler.propagateChecklists)).Methods(http.MethodPost)
+
+	revisionRouter := revisionsRouter.PathPrefix("/{revision:[0-9]+}").Subrouter()
+	revisionRouter.HandleFunc("", withContext(handler.getRevision)).Methods(http.MethodGet)
+	revisionRouter.HandleFunc("/rollback", withContext(handler.rollback)).Methods(http.MethodPost)
+
+	return handler
+}
+
+// getRevisions handles GET /api/v0/playbooks/{id}/revisions
+func (h *PlaybookRevisionHandler) getRevisions(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookView(userID, playbookID)) {
+		return
+	}
+
+	page, perPage := parsePaginationParams(r.URL.Query())
+
+	results, err := h.revisionService.GetRevisions(playbookID, page, perPage)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, results, http.StatusOK)
+}
+
+// getRevision handles GET /api/v0/playbooks/{id}/revisions/{revision}
+func (h *PlaybookRevisionHandler) getRevision(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookView(userID, playbookID)) {
+		return
+	}
+
+	revision, err := strconv.ParseInt(vars["revision"], 10, 64)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid revision", err)
+		return
+	}
+
+	playbookRevision, err := h.revisionService.GetRevision(playbookID, revision)
+	if err != nil {
+		h.handleRevisionError(w, c, err)
+		return
+	}
+
+	ReturnJSON(w, playbookRevision, http.StatusOK)
+}
+
+// diffRevisions handles GET /api/v0/playbooks/{id}/revisions/diff?from={revision}&to={revision}
+// Besides revision numbers, "published" and "draft" name the published and latest revisions.
+func (h *PlaybookRevisionHandler) diffRevisions(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookViewWithPlaybook(userID, playbook)) {
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseRevisionParam(query.Get("from"), playbook)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'from': it should be a revision number, 'published' or 'draft'", err)
+		return
+	}
+	to, err := parseRevisionParam(query.Get("to"), playbook)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'to': it should be a revision number, 'published' or 'draft'", err)
+		return
+	}
+
+	diff, err := h.revisionService.Diff(playbookID, from, to)
+	if err != nil {
+		h.handleRevisionError(w, c, err)
+		return
+	}
+
+	ReturnJSON(w, diff, http.StatusOK)
+}
+
+// rollback handles POST /api/v0/playbooks/{id}/revisions/{revision}/rollback
+func (h *PlaybookRevisionHandler) rollback(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookEdit(userID, playbook)) {
+		return
+	}
+
+	if playbook.DeleteAt != 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", playbookID))
+		return
+	}
+
+	revision, err := strconv.ParseInt(vars["revision"], 10, 64)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid revision", err)
+		return
+	}
+
+	if err := h.revisionService.Rollback(playbookID, revision, userID); err != nil {
+		h.handleRevisionError(w, c, err)
+		return
+	}
+
+	playbook, err = h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, &playbook, http.StatusOK)
+}
+
+// publish handles POST /api/v0/playbooks/{id}/publish
+func (h *PlaybookRevisionHandler) publish(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookPublish(userID, playbook)) {
+		return
+	}
+
+	if playbook.DeleteAt != 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", playbookID))
+		return
+	}
+
+	if _, err := h.revisionService.Publish(playbookID, userID); err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	playbook, err = h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, &playbook, http.StatusOK)
+}
+
+// propagateChecklists handles POST /api/v0/playbooks/{id}/propagate_checklists?dry_run={bool}
+// It brings the checklists of the playbook's active runs up to date with its published version.
+func (h *PlaybookRevisionHandler) propagateChecklists(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	// Changing the checklists of every active run takes the same authority as publishing.
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookPublish(userID, playbook)) {
+		return
+	}
+
+	if playbook.DeleteAt != 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", playbookID))
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	result, err := h.playbookRunService.PropagatePlaybookChecklists(playbookID, userID, dryRun)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, result, http.StatusOK)
+}
+
+// parseRevisionParam parses a revision number, or the "published" and "draft" aliases.
+func parseRevisionParam(value string, playbook app.Playbook) (int64, error) {
+	switch value {
+	case "published":
+		if playbook.PublishedRevision == 0 {
+			return 0, errors.Errorf("playbook '%s' has never been published", playbook.ID)
+		}
+		return playbook.PublishedRevision, nil
+	case "draft":
+		return playbook.Revision, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// handleRevisionError responds with 404 when the requested revision does not exist.
+func (h *PlaybookRevisionHandler) handleRevisionError(w http.ResponseWriter, c *Context, err error) {
+	if errors.Is(err, app.ErrNotFound) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusNotFound, "revision not found", err)
+		return
+	}
+
+	h.HandleError(w, c.logger, err)
+}
@@ -4,6 +4,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
@@ -71,9 +73,14 @@ func NewPlaybookRunHandler(
 	playbookRunsRouter.HandleFunc("/add-to-timeline-dialog", withContext(handler.addToTimelineDialog)).Methods(http.MethodPost)
 	playbookRunsRouter.HandleFunc("/owners", withContext(handler.getOwners)).Methods(http.MethodGet)
 	playbookRunsRouter.HandleFunc("/channels", withContext(handler.getChannels)).Methods(http.MethodGet)
+	playbookRunsRouter.HandleFunc("/shared_property_stats", withContext(handler.getSharedPropertyFieldStats)).Methods(http.MethodGet)
 	playbookRunsRouter.HandleFunc("/checklist-autocomplete", withContext(handler.getChecklistAutocomplete)).Methods(http.MethodGet)
 	playbookRunsRouter.HandleFunc("/checklist-autocomplete-item", withContext(handler.getChecklistAutocompleteItem)).Methods(http.MethodGet)
 	playbookRunsRouter.HandleFunc("/runs-autocomplete", withContext(handler.getChannelRunsAutocomplete)).Methods(http.MethodGet)
+	playbookRunsRouter.HandleFunc("/todo", withContext(handler.getTodoDigest)).Methods(http.MethodGet)
+	playbookRunsRouter.HandleFunc("/changes", withContext(handler.getRunChanges)).Methods(http.MethodGet)
+	playbookRunsRouter.HandleFunc("/changes/stream", withContext(handler.streamRunChanges)).Methods(http.MethodGet)
+	playbookRunsRouter.HandleFunc("/bulk", withContext(handler.applyBulkRunsOperation)).Methods(http.MethodPost)
 
 	playbookRunRouter := playbookRunsRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
 	playbookRunRouter.HandleFunc("", withContext(handler.getPlaybookRun)).Methods(http.MethodGet)
@@ -82,11 +89,12 @@ func NewPlaybookRunHandler(
 	playbookRunRouter.HandleFunc("/request-update", withContext(handler.requestUpdate)).Methods(http.MethodPost)
 	playbookRunRouter.HandleFunc("/request-join-channel", withContext(handler.requestJoinChannel)).Methods(http.MethodPost)
 
-	// These routes perform their own per-handler permission check (RunFinish / RunChangeOwner)
+	// These routes perform their own per-handler permission check (RunFinish / RunChangeOwner / RunManageRoles)
 	// which is stricter than the checkEditPermissions middleware (RunManageProperties).
 	playbookRunRouter.HandleFunc("/owner", withContext(handler.changeOwner)).Methods(http.MethodPost)
 	playbookRunRouter.HandleFunc("/finish", withContext(handler.finish)).Methods(http.MethodPut)
 	playbookRunRouter.HandleFunc("/restore", withContext(handler.restore)).Methods(http.MethodPut)
+	playbookRunRouter.HandleFunc("/roles/{roleID:[A-Za-z0-9]+}", withContext(handler.assignRunRole)).Methods(http.MethodPut)
 
 	playbookRunRouter.HandleFunc("/finish-dialog", withContext(handler.finishDialog)).Methods(http.MethodPost)
 
@@ -98,6 +106,7 @@ func NewPlaybookRunHandler(
 	playbookRunRouterAuthorized.HandleFunc("/reminder/button-update", withContext(handler.reminderButtonUpdate)).Methods(http.MethodPost)
 	playbookRunRouterAuthorized.HandleFunc("/reminder", withContext(handler.reminderReset)).Methods(http.MethodPost)
 	playbookRunRouterAuthorized.HandleFunc("/no-retrospective-button", withContext(handler.noRetrospectiveButton)).Methods(http.MethodPost)
+	playbookRunRouterAuthorized.HandleFunc("/timeline", withContext(handler.addPostToTimeline)).Methods(http.MethodPost)
 	playbookRunRouterAuthorized.HandleFunc("/timeline/{eventID:[A-Za-z0-9]+}", withContext(handler.removeTimelineEvent)).Methods(http.MethodDelete)
 	playbookRunRouterAuthorized.HandleFunc("/status-update-enabled", withContext(handler.toggleStatusUpdates)).Methods(http.MethodPut)
 	playbookRunRouterAuthorized.HandleFunc("/retrospective-enabled", withContext(handler.toggleRetrospective)).Methods(http.MethodPut)
@@ -107,9 +116,11 @@ func NewPlaybookRunHandler(
 	channelRouter.HandleFunc("/runs", withContext(handler.getPlaybookRunsForChannelByUser)).Methods(http.MethodGet)
 
 	checklistsRouter := playbookRunRouterAuthorized.PathPrefix("/checklists").Subrouter()
+	checklistsRouter.Use(handler.checkChecklistEditPermissions)
 	checklistsRouter.HandleFunc("", withContext(handler.addChecklist)).Methods(http.MethodPost)
 	checklistsRouter.HandleFunc("/move", withContext(handler.moveChecklist)).Methods(http.MethodPost)
 	checklistsRouter.HandleFunc("/move-item", withContext(handler.moveChecklistItem)).Methods(http.MethodPost)
+	checklistsRouter.HandleFunc("/items/bulk", withContext(handler.applyChecklistItemOperations)).Methods(http.MethodPost)
 
 	checklistRouter := checklistsRouter.PathPrefix("/{checklist:[0-9]+}").Subrouter()
 	checklistRouter.HandleFunc("", withContext(handler.removeChecklist)).Methods(http.MethodDelete)
@@ -131,11 +142,19 @@ func NewPlaybookRunHandler(
 		checklistItem.HandleFunc("/run", withContext(handler.itemRun)).Methods(http.MethodPost)
 		checklistItem.HandleFunc("/duplicate", withContext(handler.itemDuplicate)).Methods(http.MethodPost)
 		checklistItem.HandleFunc("/duedate", withContext(handler.itemSetDueDate)).Methods(http.MethodPut)
+		checklistItem.HandleFunc("/task-actions", withContext(handler.itemSetTaskActions)).Methods(http.MethodPut)
 	}
 
 	registerChecklistItemRoutes(checklistRouter.PathPrefix("/item/{item:[0-9]+}").Subrouter())
 	registerChecklistItemRoutes(checklistRouter.PathPrefix("/items/{item:[0-9]+}").Subrouter())
 
+	// Checklist items addressed by ID are served by the same handlers once resolved to their position.
+	checklistItemByIDRouter := playbookRunRouterAuthorized.PathPrefix("/checklist-items/{itemID:[A-Za-z0-9]+}").Subrouter()
+	checklistItemByIDRouter.Use(handler.checkChecklistEditPermissions)
+	checklistItemByIDRouter.Use(handler.resolveChecklistItem)
+	registerChecklistItemRoutes(checklistItemByIDRouter)
+	checklistItemByIDRouter.HandleFunc("/move", withContext(handler.moveChecklistItemByID)).Methods(http.MethodPost)
+
 	retrospectiveRouter := playbookRunRouterAuthorized.PathPrefix("/retrospective").Subrouter()
 	retrospectiveRouter.HandleFunc("", withContext(handler.updateRetrospective)).Methods(http.MethodPost)
 	retrospectiveRouter.HandleFunc("/publish", withContext(handler.publishRetrospective)).Methods(http.MethodPost)
@@ -145,12 +164,17 @@ func NewPlaybookRunHandler(
 	followersRouter.HandleFunc("", withContext(handler.unfollow)).Methods(http.MethodDelete)
 	followersRouter.HandleFunc("", withContext(handler.getFollowers)).Methods(http.MethodGet)
 
+	participantsRouter := playbookRunRouter.PathPrefix("/participants").Subrouter()
+	participantsRouter.HandleFunc("", withContext(handler.addParticipants)).Methods(http.MethodPost)
+	participantsRouter.HandleFunc("/{userID:[A-Za-z0-9]+}", withContext(handler.removeParticipant)).Methods(http.MethodDelete)
+
 	propertyFieldsRouter := playbookRunRouter.PathPrefix("/property_fields").Subrouter()
 	propertyFieldsRouter.HandleFunc("", withContext(handler.getRunPropertyFields)).Methods(http.MethodGet)
 	propertyFieldsRouter.HandleFunc("/{fieldID:[A-Za-z0-9]+}/value", withContext(handler.setRunPropertyValue)).Methods(http.MethodPut)
 
 	propertyValuesRouter := playbookRunRouter.PathPrefix("/property_values").Subrouter()
 	propertyValuesRouter.HandleFunc("", withContext(handler.getRunPropertyValues)).Methods(http.MethodGet)
+	propertyValuesRouter.HandleFunc("/history", withContext(handler.getRunPropertyValueHistory)).Methods(http.MethodGet)
 
 	return handler
 }
@@ -206,6 +230,87 @@ func (h *PlaybookRunHandler) checkEditPermissions(next http.Handler) http.Handle
 	})
 }
 
+// checkChecklistEditPermissions runs after checkEditPermissions and additionally requires the
+// edit_checklists run role permission when the run restricts it.
+func (h *PlaybookRunHandler) checkChecklistEditPermissions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get("Mattermost-User-ID")
+		if !h.PermissionsCheck(w, getLogger(r), h.permissions.RunEditChecklists(userID, mux.Vars(r)["id"])) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// runServiceContextKey is the key of the service resolveChecklistItem passes the handlers of the
+// routes addressing a checklist item by ID.
+type runServiceContextKey struct{}
+
+// runService returns the service the handlers of the checklist item routes change the item
+// through: the one set by resolveChecklistItem when the item is addressed by ID.
+func (h *PlaybookRunHandler) runService(r *http.Request) app.PlaybookRunService {
+	if service, ok := r.Context().Value(runServiceContextKey{}).(app.PlaybookRunService); ok {
+		return service
+	}
+
+	return h.playbookRunService
+}
+
+// resolveChecklistItem serves the routes addressing a checklist item by ID, setting the checklist
+// and item numbers the handlers of the routes addressing it by position expect. The handlers change
+// the item through a service that rejects the change with a 409 if the item was modified since
+// update_at, or if the run is modified before the change is written.
+func (h *PlaybookRunHandler) resolveChecklistItem(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := getLogger(r)
+		vars := mux.Vars(r)
+
+		ref, err := parseChecklistItemRef(r)
+		if err != nil {
+			h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "bad parameter 'update_at'", err)
+			return
+		}
+
+		err = h.playbookRunService.WithChecklistItem(vars["id"], ref, func(service app.PlaybookRunService, checklistNumber, itemNumber int) error {
+			vars["checklist"] = strconv.Itoa(checklistNumber)
+			vars["item"] = strconv.Itoa(itemNumber)
+			ctx := context.WithValue(r.Context(), runServiceContextKey{}, service)
+			next.ServeHTTP(w, mux.SetURLVars(r.WithContext(ctx), vars))
+			return nil
+		})
+		if err != nil {
+			if h.HandleChecklistItemConflict(w, logger, err) {
+				return
+			}
+			if errors.Is(err, app.ErrNotFound) {
+				h.HandleErrorWithCode(w, logger, http.StatusNotFound, "checklist item not found", err)
+				return
+			}
+			h.HandleError(w, logger, err)
+		}
+	})
+}
+
+// parseChecklistItemRef returns the checklist item addressed by the itemID route variable and the
+// optional update_at query parameter.
+func parseChecklistItemRef(r *http.Request) (app.ChecklistItemRef, error) {
+	ref := app.ChecklistItemRef{ItemID: mux.Vars(r)["itemID"]}
+
+	if updateAtParam := r.URL.Query().Get("update_at"); updateAtParam != "" {
+		updateAt, err := strconv.ParseInt(updateAtParam, 10, 64)
+		if err != nil {
+			return ref, err
+		}
+		if updateAt < 0 {
+			return ref, errors.New("update_at must not be negative")
+		}
+		ref.UpdateAt = updateAt
+	}
+
+	return ref, nil
+}
+
 // createPlaybookRunFromPost handles the POST /runs endpoint
 func (h *PlaybookRunHandler) createPlaybookRunFromPost(c *Context, w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("Mattermost-User-ID")
@@ -243,8 +348,11 @@ func (h *PlaybookRunHandler) createPlaybookRunFromPost(c *Context, w http.Respon
 		return
 	}
 
-	if errors.Is(err, app.ErrMalformedPlaybookRun) {
-		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to create playbook run", err)
+	if h.HandleMissingRequiredProperties(w, c.logger, err) {
+		return
+	}
+
+	if h.HandleMalformedRun(w, c.logger, err) {
 		return
 	}
 
@@ -291,8 +399,28 @@ func (h *PlaybookRunHandler) updatePlaybookRun(c *Context, w http.ResponseWriter
 		fieldsToUpdate["SummaryModifiedAt"] = model.GetMillis()
 	}
 
+	// With If-Match, only update the run if it wasn't modified since the version it names
+	var updateAt int64
+	if hasIfMatch(r) {
+		currentRun, err := h.playbookRunService.GetPlaybookRun(playbookRunID)
+		if err != nil {
+			h.HandleError(w, c.logger, err)
+			return
+		}
+
+		var matches bool
+		if updateAt, matches = ifMatchVersion(r, currentRun.UpdateAt); !matches {
+			returnStaleRun(w, c.logger, currentRun)
+			return
+		}
+	}
+
 	// Update using GraphqlUpdate
-	if err := h.playbookRunService.GraphqlUpdate(playbookRunID, fieldsToUpdate); err != nil {
+	if err := h.playbookRunService.GraphqlUpdateIfUnmodified(playbookRunID, fieldsToUpdate, updateAt); err != nil {
+		if errors.Is(err, app.ErrStaleVersion) {
+			h.returnCurrentStaleRun(w, c.logger, playbookRunID)
+			return
+		}
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -304,9 +432,22 @@ func (h *PlaybookRunHandler) updatePlaybookRun(c *Context, w http.ResponseWriter
 		return
 	}
 
+	setVersionETag(w, updatedPlaybookRun.UpdateAt)
 	ReturnJSON(w, updatedPlaybookRun, http.StatusOK)
 }
 
+// returnCurrentStaleRun responds with a 412 holding the current version of a run that was modified
+// between checking If-Match and updating it.
+func (h *PlaybookRunHandler) returnCurrentStaleRun(w http.ResponseWriter, logger logrus.FieldLogger, playbookRunID string) {
+	currentRun, err := h.playbookRunService.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		h.HandleError(w, logger, err)
+		return
+	}
+
+	returnStaleRun(w, logger, currentRun)
+}
+
 // createPlaybookRunFromDialog handles the interactive dialog submission when a user presses confirm on
 // the create playbook run dialog.
 func (h *PlaybookRunHandler) createPlaybookRunFromDialog(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -344,7 +485,7 @@ func (h *PlaybookRunHandler) createPlaybookRunFromDialog(c *Context, w http.Resp
 
 	// if a playbook ID exists, link the run to the channel and set the right type
 	if playbookID != "" {
-		playbook, err := h.playbookService.Get(playbookID)
+		playbook, err := h.playbookService.GetPublished(playbookID)
 		if err != nil {
 			h.HandleErrorWithCode(w, c.logger, http.StatusInternalServerError, "unable to get playbook", err)
 			return
@@ -368,8 +509,15 @@ func (h *PlaybookRunHandler) createPlaybookRunFromDialog(c *Context, w http.Resp
 		nil,
 	)
 	if err != nil {
-		if errors.Is(err, app.ErrMalformedPlaybookRun) {
-			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to create playbook run", err)
+		var missingErr *app.MissingRequiredPropertiesError
+		if errors.As(err, &missingErr) {
+			ReturnJSON(w, &model.SubmitDialogResponse{
+				Error: missingErr.PublicMessage(),
+			}, http.StatusOK)
+			return
+		}
+
+		if h.HandleMalformedRun(w, c.logger, err) {
 			return
 		}
 
@@ -548,7 +696,7 @@ func (h *PlaybookRunHandler) createPlaybookRun(playbookRun app.PlaybookRun, user
 	// for this playbook (via playbook membership).
 	if playbookRun.PlaybookID != "" {
 		var pb app.Playbook
-		pb, err = h.playbookService.Get(playbookRun.PlaybookID)
+		pb, err = h.playbookService.GetPublished(playbookRun.PlaybookID)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get playbook")
 		}
@@ -684,7 +832,13 @@ func (h *PlaybookRunHandler) getPlaybookRuns(c *Context, w http.ResponseWriter,
 	}
 
 	results, err := h.playbookRunService.GetPlaybookRuns(requesterInfo, *filterOptions)
-	if err != nil {
+	if errors.Is(err, app.ErrMalformedCondition) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+		return
+	} else if errors.Is(err, app.ErrLicensedFeature) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, err.Error(), err)
+		return
+	} else if err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -708,6 +862,7 @@ func (h *PlaybookRunHandler) getPlaybookRun(c *Context, w http.ResponseWriter, r
 		return
 	}
 
+	setVersionETag(w, playbookRunToGet.UpdateAt)
 	ReturnJSON(w, playbookRunToGet, http.StatusOK)
 }
 
@@ -815,6 +970,70 @@ func (h *PlaybookRunHandler) getOwners(c *Context, w http.ResponseWriter, r *htt
 	ReturnJSON(w, owners, http.StatusOK)
 }
 
+// getTodoDigest returns the items of the requester's todo digest: the runs with overdue status
+// updates, the tasks assigned to them and the runs they take part in.
+func (h *PlaybookRunHandler) getTodoDigest(c *Context, w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	items, err := h.playbookRunService.GetTodoDigestMessageItems(userID)
+	if err != nil {
+		h.HandleError(w, c.logger, errors.Wrap(err, "failed to get todo digest"))
+		return
+	}
+
+	if items.OverdueRuns == nil {
+		items.OverdueRuns = []app.RunLink{}
+	}
+	if items.AssignedRuns == nil {
+		items.AssignedRuns = []app.AssignedRun{}
+	}
+	if items.InProgressRuns == nil {
+		items.InProgressRuns = []app.RunLink{}
+	}
+
+	ReturnJSON(w, items, http.StatusOK)
+}
+
+// getSharedPropertyFieldStats counts the values of a team property field across the runs of every
+// playbook of the team using it, restricted to the runs the user can see.
+func (h *PlaybookRunHandler) getSharedPropertyFieldStats(c *Context, w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "playbook attributes feature is not covered by current server license", app.ErrLicensedFeature)
+		return
+	}
+
+	filterOptions, err := parsePlaybookRunsFilterOptions(r.URL, userID)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Bad parameter", err)
+		return
+	}
+
+	if filterOptions.TeamID == "" || filterOptions.SharedFieldID == "" {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "team_id and shared_field_id are required", nil)
+		return
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookList(userID, filterOptions.TeamID)) {
+		return
+	}
+
+	requesterInfo, err := h.getRequesterInfo(userID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	stats, err := h.playbookRunService.GetSharedPropertyFieldStats(requesterInfo, *filterOptions)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, stats, http.StatusOK)
+}
+
 func (h *PlaybookRunHandler) getChannels(c *Context, w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("Mattermost-User-ID")
 
@@ -883,6 +1102,41 @@ func (h *PlaybookRunHandler) changeOwner(c *Context, w http.ResponseWriter, r *h
 	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
 }
 
+// assignRunRole handles the PUT /runs/{id}/roles/{roleID} endpoint. An empty user_id leaves the
+// role unassigned.
+func (h *PlaybookRunHandler) assignRunRole(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.RunManageRoles(userID, vars["id"])) {
+		return
+	}
+
+	var params struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "could not decode request body", err)
+		return
+	}
+	params.UserID = strings.TrimSpace(params.UserID)
+	if params.UserID != "" && !model.IsValidId(params.UserID) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid user_id", errors.New("invalid id format"))
+		return
+	}
+
+	if err := h.playbookRunService.AssignRunRole(vars["id"], userID, vars["roleID"], params.UserID); err != nil {
+		if errors.Is(err, app.ErrMalformedPlaybookRun) || errors.Is(err, app.ErrPlaybookRunNotActive) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
 // updateStatusD handles the POST /runs/{id}/status endpoint, user has edit permissions
 func (h *PlaybookRunHandler) status(c *Context, w http.ResponseWriter, r *http.Request) {
 	playbookRunID := mux.Vars(r)["id"]
@@ -897,7 +1151,7 @@ func (h *PlaybookRunHandler) status(c *Context, w http.ResponseWriter, r *http.R
 	if publicMsg, internalErr := h.updateStatus(playbookRunID, userID, options); internalErr != nil {
 		if errors.Is(internalErr, app.ErrNoPermissions) {
 			h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, publicMsg, internalErr)
-		} else {
+		} else if !h.HandleMissingRequiredProperties(w, c.logger, internalErr) {
 			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, publicMsg, internalErr)
 		}
 		return
@@ -914,8 +1168,23 @@ func (h *PlaybookRunHandler) updateStatus(playbookRunID, userID string, options
 		if err := h.permissions.RunFinish(userID, playbookRunID); err != nil {
 			return "Not authorized to finish this run", err
 		}
-	} else if err := h.permissions.RunManageProperties(userID, playbookRunID); err != nil {
-		return "Not authorized", err
+	}
+	if err := h.permissions.RunUpdateStatus(userID, playbookRunID); err != nil {
+		return "Not authorized to post status updates to this run", err
+	}
+
+	// Check required fields before posting so a rejected finish doesn't leave a stray update.
+	if options.FinishRun {
+		playbookRun, err := h.playbookRunService.GetPlaybookRun(playbookRunID)
+		if err != nil {
+			return "An internal error has occurred. Check app server logs for details.", err
+		}
+		if playbookRun.CurrentStatus != app.StatusFinished {
+			var missingErr *app.MissingRequiredPropertiesError
+			if err = playbookRun.CheckRequiredOnFinish(); errors.As(err, &missingErr) {
+				return missingErr.PublicMessage(), err
+			}
+		}
 	}
 
 	options.Message = strings.TrimSpace(options.Message)
@@ -954,6 +1223,9 @@ func (h *PlaybookRunHandler) finish(c *Context, w http.ResponseWriter, r *http.R
 	}
 
 	if err := h.playbookRunService.FinishPlaybookRun(playbookRunID, userID); err != nil {
+		if h.HandleMissingRequiredProperties(w, c.logger, err) {
+			return
+		}
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1086,6 +1358,13 @@ func (h *PlaybookRunHandler) finishDialog(c *Context, w http.ResponseWriter, r *
 	}
 
 	if err := h.playbookRunService.FinishPlaybookRun(playbookRunID, userID); err != nil {
+		var missingErr *app.MissingRequiredPropertiesError
+		if errors.As(err, &missingErr) {
+			ReturnJSON(w, &model.SubmitDialogResponse{
+				Error: missingErr.PublicMessage(),
+			}, http.StatusOK)
+			return
+		}
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1197,7 +1476,7 @@ func (h *PlaybookRunHandler) updateStatusDialog(c *Context, w http.ResponseWrite
 			// FinishRun permission failures must return 403 so clients can enforce OwnerGroupOnlyActions.
 			// ErrNotFound also returns 403 to avoid disclosing run existence to unauthorized callers.
 			h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, publicMsg, internalErr)
-		} else if errors.Is(internalErr, app.ErrNoPermissions) || errors.Is(internalErr, app.ErrNotFound) {
+		} else if errors.Is(internalErr, app.ErrNoPermissions) || errors.Is(internalErr, app.ErrNotFound) || errors.As(internalErr, new(*app.MissingRequiredPropertiesError)) {
 			// Dialog handlers return HTTP 200 with SubmitDialogResponse so the dialog can show errors.
 			ReturnJSON(w, &model.SubmitDialogResponse{
 				Error: publicMsg,
@@ -1310,6 +1589,53 @@ func (h *PlaybookRunHandler) removeTimelineEvent(c *Context, w http.ResponseWrit
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// addPostToTimeline adds an existing post to the run's timeline as an event titled with summary.
+func (h *PlaybookRunHandler) addPostToTimeline(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !h.licenseChecker.TimelineAllowed() {
+		h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "timeline feature is not covered by current server license", nil)
+		return
+	}
+
+	playbookRunID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		PostID  string `json:"post_id"`
+		Summary string `json:"summary"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode payload", err)
+		return
+	}
+	if !model.IsValidId(params.PostID) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "post_id must be a valid ID", nil)
+		return
+	}
+
+	playbookRun, err := h.playbookRunService.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	post, err := h.pluginAPI.Post.GetPost(params.PostID)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusNotFound, "post not found", err)
+		return
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.ChannelView(userID, post.ChannelId)) {
+		return
+	}
+
+	if err := h.playbookRunService.AddPostToTimeline(playbookRun, userID, post, params.Summary); err != nil {
+		h.HandleError(w, c.logger, errors.Wrap(err, "failed to add post to timeline"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *PlaybookRunHandler) getChecklistAutocompleteItem(c *Context, w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	channelID := query.Get("channel_id")
@@ -1474,7 +1800,7 @@ func (h *PlaybookRunHandler) itemSetState(c *Context, w http.ResponseWriter, r *
 		return
 	}
 
-	if err := h.playbookRunService.ModifyCheckedState(id, userID, params.NewState, checklistNum, itemNum); err != nil {
+	if err := h.runService(r).ModifyCheckedState(id, userID, params.NewState, checklistNum, itemNum); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1501,6 +1827,7 @@ func (h *PlaybookRunHandler) itemSetAssignee(c *Context, w http.ResponseWriter,
 		AssigneeID              string `json:"assignee_id"`
 		AssigneeType            string `json:"assignee_type"`
 		AssigneePropertyFieldID string `json:"assignee_property_field_id"`
+		AssigneeRoleID          string `json:"assignee_role_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
 		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "failed to unmarshal", err)
@@ -1510,8 +1837,14 @@ func (h *PlaybookRunHandler) itemSetAssignee(c *Context, w http.ResponseWriter,
 	params.AssigneeID = strings.TrimSpace(params.AssigneeID)
 	params.AssigneeType = strings.TrimSpace(params.AssigneeType)
 	params.AssigneePropertyFieldID = strings.TrimSpace(params.AssigneePropertyFieldID)
+	params.AssigneeRoleID = strings.TrimSpace(params.AssigneeRoleID)
 
-	// At most one of the three assignment modes may be set in a single request.
+	// A run role is given either on its own or with the matching assignee_type.
+	if params.AssigneeRoleID != "" && params.AssigneeType == app.AssigneeTypeRunRole {
+		params.AssigneeType = ""
+	}
+
+	// At most one of the assignment modes may be set in a single request.
 	modes := 0
 	if params.AssigneeID != "" {
 		modes++
@@ -1522,18 +1855,30 @@ func (h *PlaybookRunHandler) itemSetAssignee(c *Context, w http.ResponseWriter,
 	if params.AssigneePropertyFieldID != "" {
 		modes++
 	}
+	if params.AssigneeRoleID != "" {
+		modes++
+	}
 	if modes > 1 {
-		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "assignee_id, assignee_type, and assignee_property_field_id are mutually exclusive", errors.New("multiple assignee fields set"))
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "assignee_id, assignee_type, assignee_property_field_id, and assignee_role_id are mutually exclusive", errors.New("multiple assignee fields set"))
 		return
 	}
 
 	switch {
+	case params.AssigneeRoleID != "":
+		if err := h.runService(r).SetRunRoleAssignee(id, userID, params.AssigneeRoleID, checklistNum, itemNum); err != nil {
+			if errors.Is(err, app.ErrMalformedPlaybookRun) {
+				h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+			} else {
+				h.HandleError(w, c.logger, err)
+			}
+			return
+		}
 	case params.AssigneePropertyFieldID != "":
 		if !model.IsValidId(params.AssigneePropertyFieldID) {
 			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid assignee_property_field_id", errors.New("invalid id format"))
 			return
 		}
-		if err := h.playbookRunService.SetPropertyUserAssignee(id, userID, checklistNum, itemNum, params.AssigneePropertyFieldID); err != nil {
+		if err := h.runService(r).SetPropertyUserAssignee(id, userID, checklistNum, itemNum, params.AssigneePropertyFieldID); err != nil {
 			if errors.Is(err, app.ErrMalformedPlaybookRun) || errors.Is(err, app.ErrPropertyFieldNotOnRun) {
 				h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
 			} else {
@@ -1542,7 +1887,7 @@ func (h *PlaybookRunHandler) itemSetAssignee(c *Context, w http.ResponseWriter,
 			return
 		}
 	case params.AssigneeType != "":
-		if err := h.playbookRunService.SetRoleAssignee(id, userID, params.AssigneeType, checklistNum, itemNum); err != nil {
+		if err := h.runService(r).SetRoleAssignee(id, userID, params.AssigneeType, checklistNum, itemNum); err != nil {
 			if errors.Is(err, app.ErrMalformedPlaybookRun) {
 				h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
 			} else {
@@ -1552,7 +1897,7 @@ func (h *PlaybookRunHandler) itemSetAssignee(c *Context, w http.ResponseWriter,
 		}
 	default:
 		// Empty body / empty assignee_id keeps the existing "clear assignee" semantics.
-		if err := h.playbookRunService.SetAssignee(id, userID, params.AssigneeID, checklistNum, itemNum); err != nil {
+		if err := h.runService(r).SetAssignee(id, userID, params.AssigneeID, checklistNum, itemNum); err != nil {
 			h.HandleError(w, c.logger, err)
 			return
 		}
@@ -1589,7 +1934,7 @@ func (h *PlaybookRunHandler) itemSetDueDate(c *Context, w http.ResponseWriter, r
 		return
 	}
 
-	if err := h.playbookRunService.SetDueDate(id, userID, params.DueDate, checklistNum, itemNum); err != nil {
+	if err := h.runService(r).SetDueDate(id, userID, params.DueDate, checklistNum, itemNum); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1620,7 +1965,43 @@ func (h *PlaybookRunHandler) itemSetCommand(c *Context, w http.ResponseWriter, r
 		return
 	}
 
-	if err := h.playbookRunService.SetCommandToChecklistItem(id, userID, checklistNum, itemNum, params.Command); err != nil {
+	if err := h.runService(r).SetCommandToChecklistItem(id, userID, checklistNum, itemNum, params.Command); err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
+func (h *PlaybookRunHandler) itemSetTaskActions(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	checklistNum, err := strconv.Atoi(vars["checklist"])
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "failed to parse checklist", err)
+		return
+	}
+	itemNum, err := strconv.Atoi(vars["item"])
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "failed to parse item", err)
+		return
+	}
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		TaskActions []app.TaskAction `json:"task_actions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "failed to unmarshal", err)
+		return
+	}
+
+	if err := validateTaskActions(params.TaskActions); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid task actions", err)
+		return
+	}
+
+	if err := h.runService(r).SetTaskActionsToChecklistItem(id, userID, checklistNum, itemNum, params.TaskActions); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1649,7 +2030,7 @@ func (h *PlaybookRunHandler) itemRun(c *Context, w http.ResponseWriter, r *http.
 		return
 	}
 
-	triggerID, err := h.playbookRunService.RunChecklistItemSlashCommand(playbookRunID, userID, checklistNum, itemNum)
+	triggerID, err := h.runService(r).RunChecklistItemSlashCommand(playbookRunID, userID, checklistNum, itemNum)
 	if err != nil {
 		h.HandleError(w, c.logger, err)
 		return
@@ -1679,7 +2060,7 @@ func (h *PlaybookRunHandler) itemDuplicate(c *Context, w http.ResponseWriter, r
 		return
 	}
 
-	if err := h.playbookRunService.DuplicateChecklistItem(playbookRunID, userID, checklistNum, itemNum); err != nil {
+	if err := h.runService(r).DuplicateChecklistItem(playbookRunID, userID, checklistNum, itemNum); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1847,7 +2228,7 @@ func (h *PlaybookRunHandler) itemDelete(c *Context, w http.ResponseWriter, r *ht
 	}
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	if err := h.playbookRunService.RemoveChecklistItem(id, userID, checklistNum, itemNum); err != nil {
+	if err := h.runService(r).RemoveChecklistItem(id, userID, checklistNum, itemNum); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1906,7 +2287,7 @@ func (h *PlaybookRunHandler) itemSkip(c *Context, w http.ResponseWriter, r *http
 	}
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	if err := h.playbookRunService.SkipChecklistItem(id, userID, checklistNum, itemNum); err != nil {
+	if err := h.runService(r).SkipChecklistItem(id, userID, checklistNum, itemNum); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1929,7 +2310,7 @@ func (h *PlaybookRunHandler) itemRestore(c *Context, w http.ResponseWriter, r *h
 	}
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	if err := h.playbookRunService.RestoreChecklistItem(id, userID, checklistNum, itemNum); err != nil {
+	if err := h.runService(r).RestoreChecklistItem(id, userID, checklistNum, itemNum); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -1962,7 +2343,7 @@ func (h *PlaybookRunHandler) itemEdit(c *Context, w http.ResponseWriter, r *http
 		return
 	}
 
-	if err := h.playbookRunService.EditChecklistItem(id, userID, checklistNum, itemNum, params.Title, params.Command, params.Description); err != nil {
+	if err := h.runService(r).EditChecklistItem(id, userID, checklistNum, itemNum, params.Title, params.Command, params.Description); err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -2052,6 +2433,42 @@ func (h *PlaybookRunHandler) moveChecklistItem(c *Context, w http.ResponseWriter
 	w.WriteHeader(http.StatusOK)
 }
 
+// moveChecklistItemByID handles the POST /runs/{id}/checklist-items/{itemID}/move endpoint,
+// moving the item to a position of a checklist addressed by ID.
+func (h *PlaybookRunHandler) moveChecklistItemByID(c *Context, w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	ref, err := parseChecklistItemRef(r)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'update_at'", err)
+		return
+	}
+
+	var params struct {
+		ChecklistID string `json:"checklist_id"`
+		Position    *int   `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "failed to unmarshal move params", err)
+		return
+	}
+
+	if err := h.playbookRunService.MoveChecklistItemByID(id, userID, ref, params.ChecklistID, params.Position); err != nil {
+		if h.HandleChecklistItemConflict(w, c.logger, err) {
+			return
+		}
+		if errors.Is(err, app.ErrInvalidBulkOperation) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid destination", err)
+			return
+		}
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func (h *PlaybookRunHandler) postPlaybookRunCreatedMessage(playbookRun *app.PlaybookRun, channelID string) error {
 	channel, err := h.pluginAPI.Channel.Get(playbookRun.ChannelID)
 	if err != nil {
@@ -2156,6 +2573,88 @@ func (h *PlaybookRunHandler) getFollowers(c *Context, w http.ResponseWriter, r *
 	ReturnJSON(w, followers, http.StatusOK)
 }
 
+func (h *PlaybookRunHandler) addParticipants(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookRunID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		UserIDs           []string `json:"user_ids"`
+		ForceAddToChannel bool     `json:"force_add_to_channel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode payload", err)
+		return
+	}
+	if len(params.UserIDs) == 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "user_ids must not be empty", nil)
+		return
+	}
+	for _, participantID := range params.UserIDs {
+		if !model.IsValidId(participantID) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "user_ids must contain only valid IDs", nil)
+			return
+		}
+	}
+
+	if !h.checkParticipantsPermissions(c, w, userID, playbookRunID, params.UserIDs) {
+		return
+	}
+
+	if err := h.playbookRunService.AddParticipants(playbookRunID, params.UserIDs, userID, params.ForceAddToChannel, false); err != nil {
+		h.HandleError(w, c.logger, errors.Wrap(err, "failed to add participants to run"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *PlaybookRunHandler) removeParticipant(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookRunID := vars["id"]
+	participantID := vars["userID"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.checkParticipantsPermissions(c, w, userID, playbookRunID, []string{participantID}) {
+		return
+	}
+
+	if err := h.playbookRunService.RemoveParticipants(playbookRunID, []string{participantID}, userID); err != nil {
+		h.HandleError(w, c.logger, errors.Wrap(err, "failed to remove participant from run"))
+		return
+	}
+	if err := h.playbookRunService.Unfollow(playbookRunID, participantID); err != nil {
+		h.HandleError(w, c.logger, errors.Wrap(err, "failed to make participant unfollow run"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkParticipantsPermissions checks that userID can change the participation of userIDs in the
+// run: viewing the run is enough to join or leave it, changing others requires managing an active
+// run. It writes the error response and returns false otherwise.
+func (h *PlaybookRunHandler) checkParticipantsPermissions(c *Context, w http.ResponseWriter, userID, playbookRunID string, userIDs []string) bool {
+	if updatesOnlyRequesterMembership(userID, userIDs) {
+		return h.PermissionsCheck(w, c.logger, h.permissions.RunView(userID, playbookRunID))
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.RunManageProperties(userID, playbookRunID)) {
+		return false
+	}
+
+	playbookRun, err := h.playbookRunService.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return false
+	}
+	if err := app.EnsureRunIsActive(playbookRun); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "cannot modify a finished run", err)
+		return false
+	}
+
+	return true
+}
+
 // parsePlaybookRunsFilterOptions is only for parsing. Put validation logic in app.validateOptions.
 func parsePlaybookRunsFilterOptions(u *url.URL, currentUserID string) (*app.PlaybookRunFilterOptions, error) {
 	teamID := u.Query().Get("team_id")
@@ -2247,6 +2746,20 @@ func parsePlaybookRunsFilterOptions(u *url.URL, currentUserID string) (*app.Play
 	omitEndedParam := u.Query().Get("omit_ended")
 	omitEnded := omitEndedParam == "true" // Default to false if not specified or invalid
 
+	// Parse shared_field_id and shared_field_value params to filter by a team property field
+	sharedFieldID := u.Query().Get("shared_field_id")
+	sharedFieldValue := u.Query().Get("shared_field_value")
+
+	// Parse property_filter param, a condition expression over property values in JSON
+	var propertyFilter *app.ConditionExprV1
+	if propertyFilterParam := u.Query().Get("property_filter"); propertyFilterParam != "" {
+		propertyFilter = &app.ConditionExprV1{}
+		if err = json.Unmarshal([]byte(propertyFilterParam), propertyFilter); err != nil {
+			return nil, errors.Wrapf(err, "bad parameter 'property_filter'")
+		}
+	}
+	sortPropertyFieldID := u.Query().Get("sort_property_field_id")
+
 	options := app.PlaybookRunFilterOptions{
 		TeamID:                  teamID,
 		Page:                    page,
@@ -2267,6 +2780,10 @@ func parsePlaybookRunsFilterOptions(u *url.URL, currentUserID string) (*app.Play
 		Types:                   types,
 		ActivitySince:           activitySince,
 		OmitEnded:               omitEnded,
+		SharedFieldID:           sharedFieldID,
+		SharedFieldValue:        sharedFieldValue,
+		PropertyFilter:          propertyFilter,
+		SortPropertyFieldID:     sortPropertyFieldID,
 	}
 
 	options, err = options.Validate()
@@ -2317,6 +2834,24 @@ func (h *PlaybookRunHandler) getRunPropertyValues(c *Context, w http.ResponseWri
 		return
 	}
 
+	// Parse optional as_of query parameter, reconstructing the values from their history
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		asOf, err := strconv.ParseInt(asOfStr, 10, 64)
+		if err != nil || asOf <= 0 {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid as_of parameter", err)
+			return
+		}
+
+		propertyValues, err := h.playbookRunService.GetPropertyValuesAsOf(playbookRunID, asOf)
+		if err != nil {
+			h.HandleError(w, c.logger, err)
+			return
+		}
+
+		ReturnJSON(w, propertyValues, http.StatusOK)
+		return
+	}
+
 	// Parse optional updated_since query parameter
 	var updatedSince int64 = 0
 	if updatedSinceStr := r.URL.Query().Get("updated_since"); updatedSinceStr != "" {
@@ -2337,13 +2872,40 @@ func (h *PlaybookRunHandler) getRunPropertyValues(c *Context, w http.ResponseWri
 	ReturnJSON(w, propertyValues, http.StatusOK)
 }
 
+// getRunPropertyValueHistory returns every write of the run's property values, oldest first,
+// optionally restricted to a single field with the field_id query parameter.
+func (h *PlaybookRunHandler) getRunPropertyValueHistory(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookRunID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if err := h.permissions.RunView(userID, playbookRunID); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "Not authorized", err)
+		return
+	}
+
+	fieldID := r.URL.Query().Get("field_id")
+	if fieldID != "" && !model.IsValidId(fieldID) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid field_id parameter", nil)
+		return
+	}
+
+	changes, err := h.playbookRunService.GetPropertyValueHistory(playbookRunID, fieldID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, changes, http.StatusOK)
+}
+
 func (h *PlaybookRunHandler) setRunPropertyValue(c *Context, w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	playbookRunID := vars["id"]
 	fieldID := vars["fieldID"]
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	if err := h.permissions.RunManageProperties(userID, playbookRunID); err != nil {
+	if err := h.permissions.RunSetPropertyValues(userID, playbookRunID); err != nil {
 		h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "Not authorized", err)
 		return
 	}
@@ -2367,7 +2929,10 @@ func (h *PlaybookRunHandler) setRunPropertyValue(c *Context, w http.ResponseWrit
 	}
 
 	propertyValue, err := h.playbookRunService.SetRunPropertyValue(userID, playbookRunID, fieldID, valueRequest.Value)
-	if err != nil {
+	if errors.Is(err, app.ErrPropertyFieldReadOnly) {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "property field is computed and cannot be set", err)
+		return
+	} else if err != nil {
 		h.HandleError(w, c.logger, err)
 		return
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v4"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
@@ -28,6 +29,7 @@ import (
 type PlaybookHandler struct {
 	*ErrorHandler
 	playbookService app.PlaybookService
+	bundleService   app.PlaybookBundleService
 	propertyService app.PropertyServiceReader
 	pluginAPI       *pluginapi.Client
 	config          config.Service
@@ -50,6 +52,17 @@ type PropertyFieldAttrsInput struct {
 	Options    []PropertyOptionInput `json:"options"`
 	ParentID   string                `json:"parent_id"`
 	ValueType  string                `json:"value_type"`
+	Min        *float64              `json:"min"`
+	Max        *float64              `json:"max"`
+	Integer    bool                  `json:"integer"`
+
+	// SharedFieldID links a new playbook property field to the team property field with this ID.
+	SharedFieldID string `json:"shared_field_id"`
+
+	RequiredOnCreate bool `json:"required_on_create"`
+	RequiredOnFinish bool `json:"required_on_finish"`
+
+	Computed *app.ComputedAttrs `json:"computed"`
 }
 
 type PropertyFieldRequest struct {
@@ -59,10 +72,11 @@ type PropertyFieldRequest struct {
 }
 
 // NewPlaybookHandler returns a new playbook api handler
-func NewPlaybookHandler(router *mux.Router, playbookService app.PlaybookService, propertyService app.PropertyServiceReader, api *pluginapi.Client, configService config.Service, permissions *app.PermissionsService, licenseChecker app.LicenseChecker) *PlaybookHandler {
+func NewPlaybookHandler(router *mux.Router, playbookService app.PlaybookService, bundleService app.PlaybookBundleService, propertyService app.PropertyServiceReader, api *pluginapi.Client, configService config.Service, permissions *app.PermissionsService, licenseChecker app.LicenseChecker) *PlaybookHandler {
 	handler := &PlaybookHandler{
 		ErrorHandler:    &ErrorHandler{},
 		playbookService: playbookService,
+		bundleService:   bundleService,
 		propertyService: propertyService,
 		pluginAPI:       api,
 		config:          configService,
@@ -78,6 +92,11 @@ func NewPlaybookHandler(router *mux.Router, playbookService app.PlaybookService,
 	playbooksRouter.HandleFunc("/autocomplete", withContext(handler.getPlaybooksAutoComplete)).Methods(http.MethodGet)
 	playbooksRouter.HandleFunc("/import", withContext(handler.importPlaybook)).Methods(http.MethodPost)
 
+	// Registered before the playbook routes, since "bundle" would also match a playbook ID.
+	bundleRouter := playbooksRouter.PathPrefix("/bundle").Subrouter()
+	bundleRouter.HandleFunc("/export", withContext(handler.exportPlaybookBundle)).Methods(http.MethodPost)
+	bundleRouter.HandleFunc("/import", withContext(handler.importPlaybookBundle)).Methods(http.MethodPost)
+
 	playbookRouter := playbooksRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
 	playbookRouter.HandleFunc("", withContext(handler.getPlaybook)).Methods(http.MethodGet)
 	playbookRouter.HandleFunc("", withContext(handler.updatePlaybook)).Methods(http.MethodPut)
@@ -87,6 +106,17 @@ func NewPlaybookHandler(router *mux.Router, playbookService app.PlaybookService,
 	playbookRouter.HandleFunc("/export", withContext(handler.exportPlaybook)).Methods(http.MethodGet)
 	playbookRouter.HandleFunc("/duplicate", withContext(handler.duplicatePlaybook)).Methods(http.MethodPost)
 
+	membersRouter := playbookRouter.PathPrefix("/members").Subrouter()
+	memberRouter := membersRouter.PathPrefix("/{userID:[A-Za-z0-9]+}").Subrouter()
+	memberRouter.HandleFunc("", withContext(handler.addPlaybookMember)).Methods(http.MethodPut)
+	memberRouter.HandleFunc("", withContext(handler.removePlaybookMember)).Methods(http.MethodDelete)
+
+	metricsRouter := playbookRouter.PathPrefix("/metrics").Subrouter()
+	metricsRouter.HandleFunc("", withContext(handler.addMetric)).Methods(http.MethodPost)
+	metricRouter := metricsRouter.PathPrefix("/{metricID:[A-Za-z0-9]+}").Subrouter()
+	metricRouter.HandleFunc("", withContext(handler.updateMetric)).Methods(http.MethodPatch)
+	metricRouter.HandleFunc("", withContext(handler.deleteMetric)).Methods(http.MethodDelete)
+
 	propertyFieldsRouter := playbookRouter.PathPrefix("/property_fields").Subrouter()
 	propertyFieldsRouter.HandleFunc("", withContext(handler.getPlaybookPropertyFields)).Methods(http.MethodGet)
 	propertyFieldsRouter.HandleFunc("", withContext(handler.createPlaybookPropertyField)).Methods(http.MethodPost)
@@ -95,6 +125,14 @@ func NewPlaybookHandler(router *mux.Router, playbookService app.PlaybookService,
 	propertyFieldRouter.HandleFunc("", withContext(handler.updatePlaybookPropertyField)).Methods(http.MethodPut)
 	propertyFieldRouter.HandleFunc("", withContext(handler.deletePlaybookPropertyField)).Methods(http.MethodDelete)
 
+	teamPropertyFieldsRouter := router.PathPrefix("/teams/{teamID:[A-Za-z0-9]+}/property_fields").Subrouter()
+	teamPropertyFieldsRouter.HandleFunc("", withContext(handler.getTeamPropertyFields)).Methods(http.MethodGet)
+	teamPropertyFieldsRouter.HandleFunc("", withContext(handler.createTeamPropertyField)).Methods(http.MethodPost)
+	teamPropertyFieldsRouter.HandleFunc("/merge", withContext(handler.mergeTeamPropertyFields)).Methods(http.MethodPost)
+	teamPropertyFieldRouter := teamPropertyFieldsRouter.PathPrefix("/{fieldID:[A-Za-z0-9]+}").Subrouter()
+	teamPropertyFieldRouter.HandleFunc("", withContext(handler.updateTeamPropertyField)).Methods(http.MethodPut)
+	teamPropertyFieldRouter.HandleFunc("", withContext(handler.deleteTeamPropertyField)).Methods(http.MethodDelete)
+
 	autoFollowsRouter := playbookRouter.PathPrefix("/autofollows").Subrouter()
 	autoFollowsRouter.HandleFunc("", withContext(handler.getAutoFollows)).Methods(http.MethodGet)
 	autoFollowRouter := autoFollowsRouter.PathPrefix("/{userID:[A-Za-z0-9]+}").Subrouter()
@@ -164,6 +202,11 @@ func (h *PlaybookHandler) validPlaybook(w http.ResponseWriter, logger logrus.Fie
 		return false
 	}
 
+	if err := app.ValidatePlaybookRunRoles(*playbook); err != nil {
+		h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "invalid run roles", err)
+		return false
+	}
+
 	for listIndex := range playbook.Checklists {
 		for itemIndex := range playbook.Checklists[listIndex].Items {
 			if err := validateTaskActions(playbook.Checklists[listIndex].Items[itemIndex].TaskActions); err != nil {
@@ -262,6 +305,7 @@ func (h *PlaybookHandler) getPlaybook(c *Context, w http.ResponseWriter, r *http
 		return
 	}
 
+	setVersionETag(w, playbook.UpdateAt)
 	ReturnJSON(w, &playbook, http.StatusOK)
 }
 
@@ -312,6 +356,13 @@ func (h *PlaybookHandler) updatePlaybook(c *Context, w http.ResponseWriter, r *h
 		return
 	}
 
+	// With If-Match, only update the playbook if it wasn't modified since the version it names
+	updateAt, matches := ifMatchVersion(r, oldPlaybook.UpdateAt)
+	if !matches {
+		returnStalePlaybook(w, c.logger, &oldPlaybook)
+		return
+	}
+
 	// Preserve server-managed counters that must not be set via REST PUT.
 	playbook.NextRunNumber = oldPlaybook.NextRunNumber
 
@@ -346,8 +397,16 @@ func (h *PlaybookHandler) updatePlaybook(c *Context, w http.ResponseWriter, r *h
 		return
 	}
 
-	err = h.playbookService.Update(playbook, userID)
-	if err != nil {
+	err = h.playbookService.UpdateIfUnmodified(playbook, userID, updateAt)
+	if errors.Is(err, app.ErrStaleVersion) {
+		currentPlaybook, getErr := h.playbookService.Get(playbook.ID)
+		if getErr != nil {
+			h.HandleError(w, c.logger, getErr)
+			return
+		}
+		returnStalePlaybook(w, c.logger, &currentPlaybook)
+		return
+	} else if err != nil {
 		h.handlePlaybookWriteError(w, c.logger, err)
 		return
 	}
@@ -378,8 +437,8 @@ func (h *PlaybookHandler) patchPlaybook(c *Context, w http.ResponseWriter, r *ht
 	r.Body = http.MaxBytesReader(w, r.Body, 2<<20)
 
 	var body struct {
-		RunNumberPrefix     *string `json:"run_number_prefix"`
-		ChannelNameTemplate *string `json:"channel_name_template"`
+		PlaybookUpdates
+		RunNumberPrefix *string `json:"run_number_prefix"`
 	}
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
@@ -388,7 +447,38 @@ func (h *PlaybookHandler) patchPlaybook(c *Context, w http.ResponseWriter, r *ht
 		return
 	}
 
-	// These two updates are not atomic: if the prefix succeeds but the template fails,
+	// The channel name template is validated against the playbook's property fields on its own.
+	channelNameTemplate := body.ChannelNameTemplate
+	body.ChannelNameTemplate = nil
+
+	// Any other field requires the permission to edit the playbook.
+	var setmap map[string]interface{}
+	if body.PlaybookUpdates != (PlaybookUpdates{}) {
+		if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookEdit(userID, playbook)) {
+			return
+		}
+
+		setmap, err = playbookUpdatesSetmap(h.permissions, h.licenseChecker, h.pluginAPI, userID, playbook, body.PlaybookUpdates)
+		if errors.Is(err, app.ErrNoPermissions) || errors.Is(err, app.ErrLicensedFeature) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "Not authorized", err)
+			return
+		} else if err != nil {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+
+		// Validate the playbook as it will be stored, the same way a PUT does.
+		updated, err := applyPlaybookUpdates(playbook, body.PlaybookUpdates)
+		if err != nil {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		if !h.validPlaybook(w, c.logger, &updated) {
+			return
+		}
+	}
+
+	// These updates are not atomic: if the prefix succeeds but the template fails,
 	// the prefix change is persisted. Clients that need consistency should send them
 	// in separate requests.
 	if body.RunNumberPrefix != nil {
@@ -398,16 +488,202 @@ func (h *PlaybookHandler) patchPlaybook(c *Context, w http.ResponseWriter, r *ht
 		}
 	}
 
-	if body.ChannelNameTemplate != nil {
-		if err = h.playbookService.UpdateChannelNameTemplate(playbookID, *body.ChannelNameTemplate, userID); err != nil {
+	if channelNameTemplate != nil {
+		if err = h.playbookService.UpdateChannelNameTemplate(playbookID, *channelNameTemplate, userID); err != nil {
 			h.handlePlaybookWriteError(w, c.logger, err)
 			return
 		}
 	}
 
+	if err = h.playbookService.GraphqlUpdate(playbookID, setmap, userID); err != nil {
+		h.handlePlaybookWriteError(w, c.logger, err)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PlaybookUpdates is a partial update of a playbook: only the fields that are set are changed.
+type PlaybookUpdates struct {
+	Title                                   *string            `json:"title"`
+	Description                             *string            `json:"description"`
+	Public                                  *bool              `json:"public"`
+	CreatePublicPlaybookRun                 *bool              `json:"create_public_playbook_run"`
+	ReminderMessageTemplate                 *string            `json:"reminder_message_template"`
+	ReminderTimerDefaultSeconds             *float64           `json:"reminder_timer_default_seconds"`
+	StatusUpdateEnabled                     *bool              `json:"status_update_enabled"`
+	InvitedUserIDs                          *[]string          `json:"invited_user_ids"`
+	InvitedGroupIDs                         *[]string          `json:"invited_group_ids"`
+	InviteUsersEnabled                      *bool              `json:"invite_users_enabled"`
+	DefaultOwnerID                          *string            `json:"default_owner_id"`
+	DefaultOwnerEnabled                     *bool              `json:"default_owner_enabled"`
+	BroadcastChannelIDs                     *[]string          `json:"broadcast_channel_ids"`
+	BroadcastEnabled                        *bool              `json:"broadcast_enabled"`
+	WebhookOnCreationURLs                   *[]string          `json:"webhook_on_creation_urls"`
+	WebhookOnCreationEnabled                *bool              `json:"webhook_on_creation_enabled"`
+	MessageOnJoin                           *string            `json:"message_on_join"`
+	MessageOnJoinEnabled                    *bool              `json:"message_on_join_enabled"`
+	RetrospectiveReminderIntervalSeconds    *float64           `json:"retrospective_reminder_interval_seconds"`
+	RetrospectiveTemplate                   *string            `json:"retrospective_template"`
+	RetrospectiveEnabled                    *bool              `json:"retrospective_enabled"`
+	WebhookOnStatusUpdateURLs               *[]string          `json:"webhook_on_status_update_urls"`
+	WebhookOnStatusUpdateEnabled            *bool              `json:"webhook_on_status_update_enabled"`
+	SignalAnyKeywords                       *[]string          `json:"signal_any_keywords"`
+	SignalAnyKeywordsEnabled                *bool              `json:"signal_any_keywords_enabled"`
+	CategorizeChannelEnabled                *bool              `json:"categorize_channel_enabled"`
+	CategoryName                            *string            `json:"category_name"`
+	RunSummaryTemplateEnabled               *bool              `json:"run_summary_template_enabled"`
+	RunSummaryTemplate                      *string            `json:"run_summary_template"`
+	ChannelNameTemplate                     *string            `json:"channel_name_template"`
+	Checklists                              *[]UpdateChecklist `json:"checklists"`
+	CreateChannelMemberOnNewParticipant     *bool              `json:"create_channel_member_on_new_participant"`
+	RemoveChannelMemberOnRemovedParticipant *bool              `json:"remove_channel_member_on_removed_participant"`
+	ChannelID                               *string            `json:"channel_id"`
+	ChannelMode                             *string            `json:"channel_mode"`
+}
+
+// playbookUpdatesSetmap checks updates against the playbook they change and the permissions of
+// userID, and returns the columns to set. Permission errors wrap app.ErrNoPermissions or
+// app.ErrLicensedFeature; any other error means the updates are invalid.
+func playbookUpdatesSetmap(permissions *app.PermissionsService, licenseChecker app.LicenseChecker, pluginAPI *pluginapi.Client, userID string, playbook app.Playbook, updates PlaybookUpdates) (map[string]interface{}, error) {
+	setmap := map[string]interface{}{}
+	addToSetmap(setmap, "Title", updates.Title)
+	addToSetmap(setmap, "Description", updates.Description)
+	if updates.Public != nil {
+		if *updates.Public {
+			if err := permissions.PlaybookMakePublic(userID, playbook); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := permissions.PlaybookMakePrivate(userID, playbook); err != nil {
+				return nil, err
+			}
+		}
+		if !licenseChecker.PlaybookAllowed(*updates.Public) {
+			return nil, errors.Wrapf(app.ErrLicensedFeature, "the playbook is not valid with the current license")
+		}
+		addToSetmap(setmap, "Public", updates.Public)
+	}
+	addToSetmap(setmap, "CreatePublicIncident", updates.CreatePublicPlaybookRun)
+	addToSetmap(setmap, "ReminderMessageTemplate", updates.ReminderMessageTemplate)
+	addToSetmap(setmap, "ReminderTimerDefaultSeconds", updates.ReminderTimerDefaultSeconds)
+	addToSetmap(setmap, "StatusUpdateEnabled", updates.StatusUpdateEnabled)
+	addToSetmap(setmap, "CreateChannelMemberOnNewParticipant", updates.CreateChannelMemberOnNewParticipant)
+	addToSetmap(setmap, "RemoveChannelMemberOnRemovedParticipant", updates.RemoveChannelMemberOnRemovedParticipant)
+
+	if updates.InvitedUserIDs != nil {
+		filteredInvitedUserIDs := permissions.FilterInvitedUserIDs(*updates.InvitedUserIDs, playbook.TeamID)
+		addConcatToSetmap(setmap, "ConcatenatedInvitedUserIDs", &filteredInvitedUserIDs)
+	}
+
+	if updates.InvitedGroupIDs != nil {
+		filteredInvitedGroupIDs := permissions.FilterInvitedGroupIDs(*updates.InvitedGroupIDs)
+		addConcatToSetmap(setmap, "ConcatenatedInvitedGroupIDs", &filteredInvitedGroupIDs)
+	}
+
+	addToSetmap(setmap, "InviteUsersEnabled", updates.InviteUsersEnabled)
+	if updates.DefaultOwnerID != nil {
+		if !pluginAPI.User.HasPermissionToTeam(*updates.DefaultOwnerID, playbook.TeamID, model.PermissionViewTeam) {
+			return nil, errors.Wrap(app.ErrNoPermissions, "default owner can't view team")
+		}
+		addToSetmap(setmap, "DefaultCommanderID", updates.DefaultOwnerID)
+	}
+	addToSetmap(setmap, "DefaultCommanderEnabled", updates.DefaultOwnerEnabled)
+
+	if updates.BroadcastChannelIDs != nil {
+		if err := permissions.NoAddedBroadcastChannelsWithoutPermission(userID, *updates.BroadcastChannelIDs, playbook.BroadcastChannelIDs); err != nil {
+			return nil, err
+		}
+		addConcatToSetmap(setmap, "ConcatenatedBroadcastChannelIDs", updates.BroadcastChannelIDs)
+	}
+
+	addToSetmap(setmap, "BroadcastEnabled", updates.BroadcastEnabled)
+	if updates.WebhookOnCreationURLs != nil {
+		if err := app.ValidateWebhookURLs(*updates.WebhookOnCreationURLs); err != nil {
+			return nil, err
+		}
+		addConcatToSetmap(setmap, "ConcatenatedWebhookOnCreationURLs", updates.WebhookOnCreationURLs)
+	}
+	addToSetmap(setmap, "WebhookOnCreationEnabled", updates.WebhookOnCreationEnabled)
+	addToSetmap(setmap, "MessageOnJoin", updates.MessageOnJoin)
+	addToSetmap(setmap, "MessageOnJoinEnabled", updates.MessageOnJoinEnabled)
+	addToSetmap(setmap, "RetrospectiveReminderIntervalSeconds", updates.RetrospectiveReminderIntervalSeconds)
+	addToSetmap(setmap, "RetrospectiveTemplate", updates.RetrospectiveTemplate)
+	addToSetmap(setmap, "RetrospectiveEnabled", updates.RetrospectiveEnabled)
+	if updates.WebhookOnStatusUpdateURLs != nil {
+		if err := app.ValidateWebhookURLs(*updates.WebhookOnStatusUpdateURLs); err != nil {
+			return nil, err
+		}
+		addConcatToSetmap(setmap, "ConcatenatedWebhookOnStatusUpdateURLs", updates.WebhookOnStatusUpdateURLs)
+	}
+	addToSetmap(setmap, "WebhookOnStatusUpdateEnabled", updates.WebhookOnStatusUpdateEnabled)
+	if updates.SignalAnyKeywords != nil {
+		validSignalAnyKeywords := app.ProcessSignalAnyKeywords(*updates.SignalAnyKeywords)
+		addConcatToSetmap(setmap, "ConcatenatedSignalAnyKeywords", &validSignalAnyKeywords)
+	}
+	addToSetmap(setmap, "SignalAnyKeywordsEnabled", updates.SignalAnyKeywordsEnabled)
+	addToSetmap(setmap, "CategorizeChannelEnabled", updates.CategorizeChannelEnabled)
+	if updates.CategoryName != nil {
+		if err := app.ValidateCategoryName(*updates.CategoryName); err != nil {
+			return nil, err
+		}
+		addToSetmap(setmap, "CategoryName", updates.CategoryName)
+	}
+	addToSetmap(setmap, "RunSummaryTemplateEnabled", updates.RunSummaryTemplateEnabled)
+	addToSetmap(setmap, "RunSummaryTemplate", updates.RunSummaryTemplate)
+	addToSetmap(setmap, "ChannelNameTemplate", updates.ChannelNameTemplate)
+	addToSetmap(setmap, "ChannelID", updates.ChannelID)
+	addToSetmap(setmap, "ChannelMode", updates.ChannelMode)
+
+	// Checklists are replaced as a whole.
+	if updates.Checklists != nil {
+		app.CleanUpChecklists(*updates.Checklists)
+		if err := validateUpdateTaskActions(*updates.Checklists); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate task actions for playbook id: '%s'", playbook.ID)
+		}
+		checklistsJSON, err := json.Marshal(updates.Checklists)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal checklists for playbook id: '%s'", playbook.ID)
+		}
+		setmap["ChecklistsJSON"] = checklistsJSON
+	}
+
+	if updates.Checklists != nil || updates.InvitedUserIDs != nil || updates.InviteUsersEnabled != nil {
+		if err := validatePreAssignmentUpdate(playbook, updates.Checklists, updates.InvitedUserIDs, updates.InviteUsersEnabled); err != nil {
+			return nil, errors.Wrapf(err, "invalid user pre-assignment for playbook id: '%s'", playbook.ID)
+		}
+	}
+
+	return setmap, nil
+}
+
+// applyPlaybookUpdates returns a copy of playbook with the updates that validPlaybook checks
+// applied. Checklists are left out: updated checklists are validated by playbookUpdatesSetmap.
+func applyPlaybookUpdates(playbook app.Playbook, updates PlaybookUpdates) (app.Playbook, error) {
+	setIfNotNil(&playbook.BroadcastChannelIDs, updates.BroadcastChannelIDs)
+	setIfNotNil(&playbook.BroadcastEnabled, updates.BroadcastEnabled)
+	setIfNotNil(&playbook.WebhookOnCreationURLs, updates.WebhookOnCreationURLs)
+	setIfNotNil(&playbook.WebhookOnCreationEnabled, updates.WebhookOnCreationEnabled)
+	setIfNotNil(&playbook.WebhookOnStatusUpdateURLs, updates.WebhookOnStatusUpdateURLs)
+	setIfNotNil(&playbook.WebhookOnStatusUpdateEnabled, updates.WebhookOnStatusUpdateEnabled)
+	setIfNotNil(&playbook.SignalAnyKeywords, updates.SignalAnyKeywords)
+	setIfNotNil(&playbook.CategorizeChannelEnabled, updates.CategorizeChannelEnabled)
+	setIfNotNil(&playbook.CategoryName, updates.CategoryName)
+	setIfNotNil(&playbook.ChannelID, updates.ChannelID)
+	if updates.ChannelMode != nil {
+		if err := playbook.ChannelMode.UnmarshalText([]byte(*updates.ChannelMode)); err != nil {
+			return app.Playbook{}, err
+		}
+	}
+	return playbook, nil
+}
+
+func setIfNotNil[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
+
 func validatePreAssignment(pb app.Playbook) error {
 	assignees := app.GetDistinctAssignees(pb.Checklists)
 	return app.ValidatePreAssignment(assignees, pb.InvitedUserIDs, pb.InviteUsersEnabled)
@@ -645,6 +921,229 @@ func parseGetPlaybooksOptions(u *url.URL) (app.PlaybookFilterOptions, error) {
 	}, nil
 }
 
+func (h *PlaybookHandler) addPlaybookMember(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookID := vars["id"]
+	memberID := vars["userID"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookManageMembers(userID, playbook)) {
+		return
+	}
+
+	if playbook.DeleteAt != 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", playbookID))
+		return
+	}
+
+	if err := h.playbookService.AddMember(playbookID, memberID, userID); err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlaybookHandler) removePlaybookMember(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookID := vars["id"]
+	memberID := vars["userID"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	// Leaving a playbook does not require the permission to manage its members.
+	if userID != memberID {
+		if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookManageMembers(userID, playbook)) {
+			return
+		}
+	}
+
+	if playbook.DeleteAt != 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", playbookID))
+		return
+	}
+
+	if err := h.playbookService.RemoveMember(playbookID, memberID, userID); err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// editablePlaybook gets a playbook userID is about to change the metrics of, writing an error
+// response and returning false when it can't be changed.
+func (h *PlaybookHandler) editablePlaybook(w http.ResponseWriter, logger logrus.FieldLogger, playbookID, userID string) (app.Playbook, bool) {
+	playbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, logger, err)
+		return app.Playbook{}, false
+	}
+
+	if !h.PermissionsCheck(w, logger, h.permissions.PlaybookEdit(userID, playbook)) {
+		return app.Playbook{}, false
+	}
+
+	if playbook.DeleteAt != 0 {
+		h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "Playbook cannot be modified", fmt.Errorf("playbook with id '%s' is archived", playbookID))
+		return app.Playbook{}, false
+	}
+
+	return playbook, true
+}
+
+// playbookMetric gets a metric of a playbook, writing a 404 when the metric does not exist or
+// belongs to another playbook.
+func (h *PlaybookHandler) playbookMetric(w http.ResponseWriter, logger logrus.FieldLogger, playbookID, metricID string) (*app.PlaybookMetricConfig, bool) {
+	metric, err := h.playbookService.GetMetric(metricID)
+	if errors.Is(err, app.ErrNotFound) || (err == nil && metric.PlaybookID != playbookID) {
+		h.HandleErrorWithCode(w, logger, http.StatusNotFound, "metric not found", err)
+		return nil, false
+	} else if err != nil {
+		h.HandleError(w, logger, err)
+		return nil, false
+	}
+
+	return metric, true
+}
+
+func (h *PlaybookHandler) addMetric(c *Context, w http.ResponseWriter, r *http.Request) {
+	playbookID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, ok := h.editablePlaybook(w, c.logger, playbookID, userID)
+	if !ok {
+		return
+	}
+
+	var params struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+		Target      *int64 `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode metric", err)
+		return
+	}
+
+	metric := app.PlaybookMetricConfig{
+		Title:       strings.TrimSpace(params.Title),
+		Description: params.Description,
+		Type:        params.Type,
+	}
+	if params.Target != nil {
+		metric.Target = null.IntFrom(*params.Target)
+	}
+
+	if metric.Title == "" {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "metric title must not be empty", nil)
+		return
+	}
+	if err := app.ValidateMetricType(metric.Type); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	playbook.Metrics = append(playbook.Metrics, metric)
+	if err := h.validateMetrics(playbook); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid metrics configs", err)
+		return
+	}
+
+	created, err := h.playbookService.AddMetric(playbookID, metric, userID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.Header().Add("Location", makeAPIURL(h.pluginAPI, "playbooks/%s/metrics/%s", playbookID, created.ID))
+	ReturnJSON(w, created, http.StatusCreated)
+}
+
+func (h *PlaybookHandler) updateMetric(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	playbook, ok := h.editablePlaybook(w, c.logger, playbookID, userID)
+	if !ok {
+		return
+	}
+
+	metric, ok := h.playbookMetric(w, c.logger, playbookID, vars["metricID"])
+	if !ok {
+		return
+	}
+
+	var patch app.PlaybookMetricPatch
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patch); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode metric", err)
+		return
+	}
+
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if title == "" {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "metric title must not be empty", nil)
+			return
+		}
+		patch.Title = &title
+
+		for i := range playbook.Metrics {
+			if playbook.Metrics[i].ID == metric.ID {
+				playbook.Metrics[i].Title = title
+			}
+		}
+		if err := h.validateMetrics(playbook); err != nil {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "invalid metrics configs", err)
+			return
+		}
+	}
+
+	updated, err := h.playbookService.UpdateMetric(metric.ID, patch, userID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	ReturnJSON(w, updated, http.StatusOK)
+}
+
+func (h *PlaybookHandler) deleteMetric(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playbookID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if _, ok := h.editablePlaybook(w, c.logger, playbookID, userID); !ok {
+		return
+	}
+
+	metric, ok := h.playbookMetric(w, c.logger, playbookID, vars["metricID"])
+	if !ok {
+		return
+	}
+
+	if err := h.playbookService.DeleteMetric(metric.ID, userID); err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *PlaybookHandler) autoFollow(c *Context, w http.ResponseWriter, r *http.Request) {
 	playbookID := mux.Vars(r)["id"]
 	currentUserID := r.Header.Get("Mattermost-User-ID")
@@ -799,12 +1298,16 @@ func (h *PlaybookHandler) importPlaybook(c *Context, w http.ResponseWriter, r *h
 
 	playbook.NextRunNumber = 0
 
+	// A single import always creates a new playbook, so it must not claim the export key of the
+	// original: only bundle imports match playbooks by export key.
+	playbook.ExportKey = ""
+
 	if playbook.ID != "" {
 		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "playbook import should not have ID field", nil)
 		return
 	}
 
-	if importBlock.Version != app.CurrentPlaybookExportVersion {
+	if !app.IsSupportedPlaybookExportVersion(importBlock.Version) {
 		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "Unsupported import version", nil)
 		return
 	}
@@ -863,6 +1366,164 @@ func (h *PlaybookHandler) importPlaybook(c *Context, w http.ResponseWriter, r *h
 	ReturnJSON(w, &result, http.StatusCreated)
 }
 
+// maxBundleSize is the maximum size of a playbook bundle accepted on import.
+const maxBundleSize = 50 * 1024 * 1024
+
+func (h *PlaybookHandler) exportPlaybookBundle(c *Context, w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		PlaybookIDs []string `json:"playbook_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to decode playbook ids", err)
+		return
+	}
+
+	if len(params.PlaybookIDs) == 0 {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "playbook_ids must not be empty", nil)
+		return
+	}
+	if len(params.PlaybookIDs) > app.MaxPlaybooksPerBundle {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, fmt.Sprintf("cannot export more than %d playbooks at once", app.MaxPlaybooksPerBundle), nil)
+		return
+	}
+
+	for _, playbookID := range params.PlaybookIDs {
+		if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookView(userID, playbookID)) {
+			return
+		}
+	}
+
+	export, err := h.bundleService.Export(params.PlaybookIDs)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(export) // #nosec G705 -- export is JSON generated with json.MarshalIndent and served as application/json.
+}
+
+// importPlaybookBundle creates or updates the playbooks of a bundle in the given team. With
+// dry_run=true, it only returns which playbooks would be created and which would be updated.
+func (h *PlaybookHandler) importPlaybookBundle(c *Context, w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	teamID := params.Get("team_id")
+	dryRun, _ := strconv.ParseBool(params.Get("dry_run"))
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if teamID == "" {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "team_id is required", nil)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBundleSize))
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "unable to read playbook bundle", err)
+		return
+	}
+
+	bundle, err := app.ParsePlaybookBundle(data)
+	if err != nil {
+		h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// Channel IDs come from the exporting server: actions of a channel that is not an active
+	// channel of the team are skipped rather than written to an unrelated channel.
+	warnings := make(map[int][]string)
+	for i := range bundle.Playbooks {
+		entry := &bundle.Playbooks[i]
+		if len(entry.ChannelActions) == 0 {
+			continue
+		}
+		if entry.ChannelID != "" {
+			channel, err := h.pluginAPI.Channel.Get(entry.ChannelID)
+			if err == nil && channel.TeamId == teamID && channel.DeleteAt == 0 {
+				continue
+			}
+		}
+		warnings[i] = append(warnings[i], fmt.Sprintf("%d channel actions skipped: channel %q is not a channel of the team", len(entry.ChannelActions), entry.ChannelID))
+		entry.ChannelActions = nil
+	}
+
+	plan, err := h.bundleService.Plan(bundle, teamID)
+	if err != nil {
+		h.HandleError(w, c.logger, err)
+		return
+	}
+	addBundleImportWarnings(plan, warnings)
+
+	for i := range bundle.Playbooks {
+		entry := &bundle.Playbooks[i]
+		entry.TeamID = teamID
+		entry.Public = true
+
+		switch plan.Playbooks[i].Action {
+		case app.BundleImportActionUpdate:
+			existing, err := h.playbookService.Get(plan.Playbooks[i].PlaybookID)
+			if err != nil {
+				h.HandleError(w, c.logger, err)
+				return
+			}
+			if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookEdit(userID, existing)) {
+				return
+			}
+		default:
+			if !h.PermissionsCheck(w, c.logger, h.permissions.PlaybookCreate(userID, entry.Playbook)) {
+				return
+			}
+		}
+
+		if entry.ChannelID != "" && len(entry.ChannelActions) > 0 {
+			if !h.PermissionsCheck(w, c.logger, h.permissions.ChannelActionUpdate(userID, entry.ChannelID)) {
+				return
+			}
+		}
+
+		if !h.validPlaybook(w, c.logger, &entry.Playbook) {
+			return
+		}
+
+		importFields := make([]app.PropertyField, 0, len(entry.Properties))
+		for _, epf := range entry.Properties {
+			var pf app.PropertyField
+			pf.Name = epf.Name
+			importFields = append(importFields, pf)
+		}
+		if !h.validateTemplateWithFields(w, c.logger, entry.ChannelNameTemplate, importFields) {
+			return
+		}
+	}
+
+	if dryRun {
+		ReturnJSON(w, plan, http.StatusOK)
+		return
+	}
+
+	result, err := h.bundleService.Import(bundle, teamID, userID)
+	if err != nil {
+		h.handlePlaybookWriteError(w, c.logger, err)
+		return
+	}
+	addBundleImportWarnings(result, warnings)
+
+	status := http.StatusOK
+	if result.NumCreates() > 0 {
+		status = http.StatusCreated
+	}
+	ReturnJSON(w, result, status)
+}
+
+// addBundleImportWarnings adds warnings, by index of the bundle entry, to the items of result.
+func addBundleImportWarnings(result *app.PlaybookBundleImportResult, warnings map[int][]string) {
+	for i, entryWarnings := range warnings {
+		result.Playbooks[i].Warnings = append(result.Playbooks[i].Warnings, entryWarnings...)
+	}
+}
+
 func (h *PlaybookHandler) validateMetrics(pb app.Playbook) error {
 	if len(pb.Metrics) > app.MaxMetricsPerPlaybook {
 		return errors.Errorf("playbook cannot have more than %d key metrics", app.MaxMetricsPerPlaybook)
@@ -1105,7 +1766,7 @@ func (h *PlaybookHandler) createPlaybookPropertyField(c *Context, w http.Respons
 
 	propertyField := convertRequestToPropertyField(request)
 
-	createdField, err := h.playbookService.CreatePropertyField(playbookID, *propertyField)
+	createdField, err := h.playbookService.CreatePropertyField(playbookID, *propertyField, userID)
 	if err != nil {
 		h.handlePlaybookWriteError(w, logger, err)
 		return
@@ -1183,7 +1844,7 @@ func (h *PlaybookHandler) updatePlaybookPropertyField(c *Context, w http.Respons
 		}
 	}
 
-	updatedField, err := h.playbookService.UpdatePropertyField(playbookID, *propertyField)
+	updatedField, err := h.playbookService.UpdatePropertyField(playbookID, *propertyField, userID)
 	if err != nil {
 		// Best-effort revert: if the template was updated but the field rename failed, try to
 		// roll the template back so we don't leave ChannelNameTemplate referencing a field name
@@ -1201,6 +1862,10 @@ func (h *PlaybookHandler) updatePlaybookPropertyField(c *Context, w http.Respons
 			h.HandleErrorWithCode(w, logger, http.StatusConflict, err.Error(), err)
 			return
 		}
+		if errors.Is(err, app.ErrPropertyFieldShared) {
+			h.HandleErrorWithCode(w, logger, http.StatusConflict, err.Error(), err)
+			return
+		}
 		h.handlePlaybookWriteError(w, logger, err)
 		return
 	}
@@ -1262,7 +1927,7 @@ func (h *PlaybookHandler) deletePlaybookPropertyField(c *Context, w http.Respons
 		return
 	}
 
-	if err := h.playbookService.DeletePropertyField(playbookID, fieldID); err != nil {
+	if err := h.playbookService.DeletePropertyField(playbookID, fieldID, userID); err != nil {
 		if errors.Is(err, app.ErrPropertyFieldInUse) {
 			h.HandleErrorWithCode(w, logger, http.StatusConflict, err.Error(), err)
 			return
@@ -1274,6 +1939,176 @@ func (h *PlaybookHandler) deletePlaybookPropertyField(c *Context, w http.Respons
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *PlaybookHandler) getTeamPropertyFields(c *Context, w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamID"]
+	logger := c.logger.WithField("team_id", teamID)
+
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		h.HandleErrorWithCode(w, logger, http.StatusForbidden, "playbook attributes feature is not covered by current server license", app.ErrLicensedFeature)
+		return
+	}
+
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, logger, h.permissions.PlaybookList(userID, teamID)) {
+		return
+	}
+
+	propertyFields, err := h.propertyService.GetTeamPropertyFields(teamID)
+	if err != nil {
+		h.HandleError(w, logger, err)
+		return
+	}
+
+	ReturnJSON(w, propertyFields, http.StatusOK)
+}
+
+func (h *PlaybookHandler) createTeamPropertyField(c *Context, w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamID"]
+	logger := c.logger.WithField("team_id", teamID)
+
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		h.HandleErrorWithCode(w, logger, http.StatusForbidden, "playbook attributes feature is not covered by current server license", app.ErrLicensedFeature)
+		return
+	}
+
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, logger, h.permissions.TeamManagePropertyFields(userID, teamID)) {
+		return
+	}
+
+	var request PropertyFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "unable to decode request body", err)
+		return
+	}
+
+	propertyField := convertRequestToPropertyField(request)
+
+	createdField, err := h.playbookService.CreateTeamPropertyField(teamID, *propertyField)
+	if err != nil {
+		h.handlePlaybookWriteError(w, logger, err)
+		return
+	}
+
+	ReturnJSON(w, createdField, http.StatusCreated)
+}
+
+func (h *PlaybookHandler) updateTeamPropertyField(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	fieldID := vars["fieldID"]
+	logger := c.logger.WithFields(logrus.Fields{"team_id": teamID, "field_id": fieldID})
+
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		h.HandleErrorWithCode(w, logger, http.StatusForbidden, "playbook attributes feature is not covered by current server license", app.ErrLicensedFeature)
+		return
+	}
+
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, logger, h.permissions.TeamManagePropertyFields(userID, teamID)) {
+		return
+	}
+
+	var request PropertyFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "unable to decode request body", err)
+		return
+	}
+
+	propertyField := convertRequestToPropertyField(request)
+	propertyField.ID = fieldID
+
+	updatedField, err := h.playbookService.UpdateTeamPropertyField(teamID, *propertyField, userID)
+	if err != nil {
+		h.handleTeamPropertyFieldError(w, logger, err)
+		return
+	}
+
+	ReturnJSON(w, updatedField, http.StatusOK)
+}
+
+func (h *PlaybookHandler) deleteTeamPropertyField(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	fieldID := vars["fieldID"]
+	logger := c.logger.WithFields(logrus.Fields{"team_id": teamID, "field_id": fieldID})
+
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		h.HandleErrorWithCode(w, logger, http.StatusForbidden, "playbook attributes feature is not covered by current server license", app.ErrLicensedFeature)
+		return
+	}
+
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, logger, h.permissions.TeamManagePropertyFields(userID, teamID)) {
+		return
+	}
+
+	if err := h.playbookService.DeleteTeamPropertyField(teamID, fieldID); err != nil {
+		h.handleTeamPropertyFieldError(w, logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type MergeTeamPropertyFieldsRequest struct {
+	Name string `json:"name"`
+}
+
+// mergeTeamPropertyFields replaces the playbook property fields of the team named like the
+// request by a single team property field, linking each of them to it.
+func (h *PlaybookHandler) mergeTeamPropertyFields(c *Context, w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamID"]
+	logger := c.logger.WithField("team_id", teamID)
+
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		h.HandleErrorWithCode(w, logger, http.StatusForbidden, "playbook attributes feature is not covered by current server license", app.ErrLicensedFeature)
+		return
+	}
+
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.PermissionsCheck(w, logger, h.permissions.TeamManagePropertyFields(userID, teamID)) {
+		return
+	}
+
+	var request MergeTeamPropertyFieldsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "unable to decode request body", err)
+		return
+	}
+
+	if strings.TrimSpace(request.Name) == "" {
+		h.HandleErrorWithCode(w, logger, http.StatusBadRequest, "name is required", nil)
+		return
+	}
+
+	result, err := h.playbookService.MergeTeamPropertyFields(teamID, strings.TrimSpace(request.Name), userID)
+	if err != nil {
+		h.handleTeamPropertyFieldError(w, logger, err)
+		return
+	}
+
+	ReturnJSON(w, result, http.StatusOK)
+}
+
+func (h *PlaybookHandler) handleTeamPropertyFieldError(w http.ResponseWriter, logger logrus.FieldLogger, err error) {
+	switch {
+	case errors.Is(err, app.ErrNotFound):
+		h.HandleErrorWithCode(w, logger, http.StatusNotFound, err.Error(), err)
+	case errors.Is(err, app.ErrPropertyOptionsInUse),
+		errors.Is(err, app.ErrPropertyFieldTypeChangeNotAllowed),
+		errors.Is(err, app.ErrPropertyFieldInUse):
+		h.HandleErrorWithCode(w, logger, http.StatusConflict, err.Error(), err)
+	default:
+		h.handlePlaybookWriteError(w, logger, err)
+	}
+}
+
 type ReorderPropertyFieldsRequest struct {
 	FieldID        string `json:"field_id"`
 	TargetPosition int    `json:"target_position"`
@@ -1322,7 +2157,7 @@ func (h *PlaybookHandler) reorderPlaybookPropertyFields(c *Context, w http.Respo
 		return
 	}
 
-	reorderedFields, err := h.playbookService.ReorderPropertyFields(playbookID, request.FieldID, request.TargetPosition)
+	reorderedFields, err := h.playbookService.ReorderPropertyFields(playbookID, request.FieldID, request.TargetPosition, userID)
 	if err != nil {
 		h.HandleError(w, logger, err)
 		return
@@ -1345,6 +2180,16 @@ func convertRequestToPropertyField(request PropertyFieldRequest) *app.PropertyFi
 			SortOrder:  request.Attrs.SortOrder,
 			ParentID:   request.Attrs.ParentID,
 			ValueType:  request.Attrs.ValueType,
+			Min:        request.Attrs.Min,
+			Max:        request.Attrs.Max,
+			Integer:    request.Attrs.Integer,
+
+			SharedFieldID: request.Attrs.SharedFieldID,
+
+			RequiredOnCreate: request.Attrs.RequiredOnCreate,
+			RequiredOnFinish: request.Attrs.RequiredOnFinish,
+
+			Computed: request.Attrs.Computed,
 		}
 
 		if request.Attrs.Visibility == "" {
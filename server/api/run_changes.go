@@ -0,0 +1,272 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+const (
+	// runChangesPageSize is the maximum number of changes read from the feed at once.
+	runChangesPageSize = 200
+
+	// runChangesMaxWait caps how long GET /runs/changes waits for a change.
+	runChangesMaxWait = 30 * time.Second
+
+	// runChangesPollInterval is how often waiting requests and streams check for new changes.
+	runChangesPollInterval = time.Second
+
+	// runChangesHeartbeatInterval is how often streams send a comment to keep idle connections open.
+	runChangesHeartbeatInterval = 30 * time.Second
+
+	// runChangesPermissionTTL is how long a stream trusts a run view permission check.
+	runChangesPermissionTTL = time.Minute
+)
+
+// runChangesResult is the response of GET /runs/changes.
+type runChangesResult struct {
+	Changes []app.RunChange `json:"changes"`
+
+	// Cursor is the cursor to resume the feed from. It can be past the last change returned when
+	// changes of runs the user cannot view were skipped.
+	Cursor int64 `json:"cursor"`
+}
+
+type runViewCheck struct {
+	allowed   bool
+	checkedAt time.Time
+}
+
+// runChangeFeed reads the run change feed on behalf of a user, leaving out the changes of the
+// runs the user cannot view.
+type runChangeFeed struct {
+	handler *PlaybookRunHandler
+	logger  logrus.FieldLogger
+	userID  string
+	options app.RunChangeOptions
+	checks  map[string]runViewCheck
+}
+
+// newRunChangeFeed validates the filters and the cursor of a change feed request. Without a
+// cursor, the feed starts after the most recent change. It writes the error response and returns
+// false when the request can't be served.
+func (h *PlaybookRunHandler) newRunChangeFeed(c *Context, w http.ResponseWriter, r *http.Request) (*runChangeFeed, bool) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.config.IsRunChangeFeedEnabled() {
+		h.HandleErrorWithCode(w, c.logger, http.StatusForbidden, "run change feed is disabled", errors.New("run change feed is disabled"))
+		return nil, false
+	}
+
+	query := r.URL.Query()
+	options := app.RunChangeOptions{
+		TeamID:        query.Get("team_id"),
+		PlaybookRunID: query.Get("run_id"),
+		Limit:         runChangesPageSize,
+	}
+
+	if options.TeamID != "" {
+		if !model.IsValidId(options.TeamID) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'team_id'", nil)
+			return nil, false
+		}
+		if !h.PermissionsCheck(w, c.logger, h.permissions.TeamView(userID, options.TeamID)) {
+			return nil, false
+		}
+	}
+	if options.PlaybookRunID != "" {
+		if !model.IsValidId(options.PlaybookRunID) {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'run_id'", nil)
+			return nil, false
+		}
+		if !h.PermissionsCheck(w, c.logger, h.permissions.RunView(userID, options.PlaybookRunID)) {
+			return nil, false
+		}
+	}
+
+	// EventSource sends the ID of the last event it received when it reconnects.
+	cursorParam := r.Header.Get("Last-Event-ID")
+	if cursorParam == "" {
+		cursorParam = query.Get("cursor")
+	}
+	if cursorParam != "" {
+		cursor, err := strconv.ParseInt(cursorParam, 10, 64)
+		if err != nil || cursor < 0 {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'cursor'", err)
+			return nil, false
+		}
+		options.After = cursor
+	} else {
+		cursor, err := h.playbookRunService.GetLatestRunChangeCursor()
+		if err != nil {
+			h.HandleError(w, c.logger, err)
+			return nil, false
+		}
+		options.After = cursor
+	}
+
+	return &runChangeFeed{
+		handler: h,
+		logger:  c.logger,
+		userID:  userID,
+		options: options,
+		checks:  make(map[string]runViewCheck),
+	}, true
+}
+
+// next returns the next changes the user can view and advances the cursor past every change read.
+// more is true when the page was full and further changes may be waiting.
+func (f *runChangeFeed) next() (changes []app.RunChange, more bool, err error) {
+	page, err := f.handler.playbookRunService.GetRunChanges(f.options)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get run changes")
+	}
+
+	changes = []app.RunChange{}
+	for _, change := range page {
+		f.options.After = change.Cursor
+		if f.canView(change.PlaybookRunID) {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, len(page) == f.options.Limit, nil
+}
+
+func (f *runChangeFeed) canView(runID string) bool {
+	if check, ok := f.checks[runID]; ok && time.Since(check.checkedAt) < runChangesPermissionTTL {
+		return check.allowed
+	}
+
+	// A run that can't be read, including a deleted run, is left out of the feed.
+	allowed := f.handler.permissions.RunView(f.userID, runID) == nil
+	f.checks[runID] = runViewCheck{allowed: allowed, checkedAt: time.Now()}
+	return allowed
+}
+
+// getRunChanges handles GET /runs/changes, returning the changes after the given cursor. With
+// wait, in seconds, it waits until a change arrives or the time is up.
+func (h *PlaybookRunHandler) getRunChanges(c *Context, w http.ResponseWriter, r *http.Request) {
+	var wait time.Duration
+	if waitParam := r.URL.Query().Get("wait"); waitParam != "" {
+		seconds, err := strconv.Atoi(waitParam)
+		if err != nil || seconds < 0 {
+			h.HandleErrorWithCode(w, c.logger, http.StatusBadRequest, "bad parameter 'wait'", err)
+			return
+		}
+		wait = min(time.Duration(seconds)*time.Second, runChangesMaxWait)
+	}
+
+	feed, ok := h.newRunChangeFeed(c, w, r)
+	if !ok {
+		return
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		changes, more, err := feed.next()
+		if err != nil {
+			h.HandleError(w, c.logger, err)
+			return
+		}
+		if len(changes) > 0 || (!more && !time.Now().Before(deadline)) {
+			ReturnJSON(w, runChangesResult{Changes: changes, Cursor: feed.options.After}, http.StatusOK)
+			return
+		}
+		if more {
+			continue
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(min(runChangesPollInterval, time.Until(deadline))):
+		}
+	}
+}
+
+// streamRunChanges handles GET /runs/changes/stream, streaming the change feed as server-sent
+// events. Each event has the change's cursor as ID and its type as name, so EventSource clients
+// resume where they left off when they reconnect.
+func (h *PlaybookRunHandler) streamRunChanges(c *Context, w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.newRunChangeFeed(c, w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	flush := func() bool {
+		if err := controller.Flush(); err != nil {
+			c.logger.WithError(err).Debug("failed to flush run change stream")
+			return false
+		}
+		return true
+	}
+
+	// Tell the client where the stream starts, so it can resume from there even before the
+	// first change arrives.
+	if _, err := fmt.Fprintf(w, "retry: %d\nid: %d\n\n", (5 * time.Second).Milliseconds(), feed.options.After); err != nil || !flush() {
+		return
+	}
+
+	poll := time.NewTicker(runChangesPollInterval)
+	defer poll.Stop()
+	lastWrite := time.Now()
+	for {
+		changes, more, err := feed.next()
+		if err != nil {
+			c.logger.WithError(err).Warn("failed to stream run changes")
+			return
+		}
+
+		for _, change := range changes {
+			data, err := json.Marshal(change)
+			if err != nil {
+				c.logger.WithError(err).Warn("failed to marshal run change")
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", change.Cursor, change.Type, data); err != nil {
+				return
+			}
+		}
+		if len(changes) > 0 {
+			if !flush() {
+				return
+			}
+			lastWrite = time.Now()
+		}
+		if more {
+			continue
+		}
+
+		if time.Since(lastWrite) >= runChangesHeartbeatInterval {
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil || !flush() {
+				return
+			}
+			lastWrite = time.Now()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-poll.C:
+		}
+	}
+}
@@ -24,16 +24,18 @@ type StatsHandler struct {
 	pluginAPI       *pluginapi.Client
 	statsStore      *sqlstore.StatsStore
 	playbookService app.PlaybookService
+	propertyService app.PropertyService
 	permissions     *app.PermissionsService
 	licenseChecker  app.LicenseChecker
 }
 
-func NewStatsHandler(router *mux.Router, api *pluginapi.Client, statsStore *sqlstore.StatsStore, playbookService app.PlaybookService, permissions *app.PermissionsService, licenseChecker app.LicenseChecker) *StatsHandler {
+func NewStatsHandler(router *mux.Router, api *pluginapi.Client, statsStore *sqlstore.StatsStore, playbookService app.PlaybookService, propertyService app.PropertyService, permissions *app.PermissionsService, licenseChecker app.LicenseChecker) *StatsHandler {
 	handler := &StatsHandler{
 		ErrorHandler:    &ErrorHandler{},
 		pluginAPI:       api,
 		statsStore:      statsStore,
 		playbookService: playbookService,
+		propertyService: propertyService,
 		permissions:     permissions,
 		licenseChecker:  licenseChecker,
 	}
@@ -62,11 +64,16 @@ type PlaybookStats struct {
 	MetricValueRange              [][]int64  `json:"metric_value_range"`
 	MetricRollingValues           [][]int64  `json:"metric_rolling_values"`
 	LastXRunNames                 []string   `json:"last_x_run_names"`
+
+	NumberPropertyStats []app.NumberPropertyStats `json:"number_property_stats"`
 }
 
 const (
 	MetricChartPeriod          = 10
 	MetricRollingAveragePeriod = 10
+
+	// NumberPropertyStatsPeriod is the number of most recent runs summarized for number properties.
+	NumberPropertyStatsPeriod = 1000
 )
 
 func parsePlaybookStatsFilters(u *url.URL) (*sqlstore.StatsFilters, error) {
@@ -115,6 +122,7 @@ func (h *StatsHandler) playbookStats(c *Context, w http.ResponseWriter, r *http.
 	metricRollingValues, lastXRunNames := h.statsStore.MetricRollingValuesLastXRuns(MetricChartPeriod, 0, *filters)
 	metricRollingAverage, metricRollingAverageChange := h.statsStore.MetricRollingAverageAndChange(MetricRollingAveragePeriod, *filters)
 	metricValueRange := h.statsStore.MetricValueRange(*filters)
+	numberPropertyStats := h.numberPropertyStats(c, filters)
 
 	ReturnJSON(w, &PlaybookStats{
 		RunsInProgress:                h.statsStore.TotalInProgressPlaybookRuns(filters),
@@ -133,9 +141,45 @@ func (h *StatsHandler) playbookStats(c *Context, w http.ResponseWriter, r *http.
 		MetricRollingAverage:          metricRollingAverage,
 		MetricRollingAverageChange:    metricRollingAverageChange,
 		LastXRunNames:                 lastXRunNames,
+		NumberPropertyStats:           numberPropertyStats,
 	}, http.StatusOK)
 }
 
+// numberPropertyStats summarizes the number properties of the playbook over its most recent runs.
+// Failures are logged and yield no stats, like the other playbook stats.
+func (h *StatsHandler) numberPropertyStats(c *Context, filters *sqlstore.StatsFilters) []app.NumberPropertyStats {
+	stats := []app.NumberPropertyStats{}
+	if !h.licenseChecker.PlaybookAttributesAllowed() {
+		return stats
+	}
+
+	playbookFields, err := h.propertyService.GetPropertyFields(filters.PlaybookID)
+	if err != nil {
+		c.logger.WithError(err).Warn("failed to get property fields for playbook stats")
+		return stats
+	}
+
+	runIDs, err := h.statsStore.LastXRunIDs(NumberPropertyStatsPeriod, filters)
+	if err != nil {
+		c.logger.WithError(err).Warn("failed to get runs for playbook property stats")
+		return stats
+	}
+
+	runFields, err := h.propertyService.GetRunsPropertyFields(runIDs)
+	if err != nil {
+		c.logger.WithError(err).Warn("failed to get run property fields for playbook stats")
+		return stats
+	}
+
+	runValues, err := h.propertyService.GetRunsPropertyValues(runIDs)
+	if err != nil {
+		c.logger.WithError(err).Warn("failed to get run property values for playbook stats")
+		return stats
+	}
+
+	return app.ComputeNumberPropertyStats(playbookFields, runFields, runValues)
+}
+
 type PlaybookSiteStats struct {
 	TotalPlaybooks    int `json:"total_playbooks"`
 	TotalPlaybookRuns int `json:"total_playbook_runs"`
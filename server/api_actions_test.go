@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
 
@@ -96,6 +97,31 @@ func TestActionCreation(t *testing.T) {
 		requireErrorWithStatusCode(t, err, http.StatusForbidden)
 	})
 
+	t.Run("create set run property action with a value the field rejects", func(t *testing.T) {
+		channel := createNewChannel(t, "create-set-run-property-action-invalid-value")
+
+		field, err := e.PlaybooksClient.Playbooks.CreatePropertyField(context.Background(), e.BasicPlaybook.ID, client.PropertyFieldRequest{
+			Name:  "Impact",
+			Type:  "text",
+			Attrs: &client.PropertyFieldAttrsInput{ValueType: model.NewPointer(client.PropertyValueTypeNumber)},
+		})
+		require.NoError(t, err)
+
+		createAction := func(value interface{}) error {
+			_, err := e.PlaybooksClient.Actions.Create(context.Background(), channel.Id, client.ChannelActionCreateOptions{
+				ChannelID:   channel.Id,
+				Enabled:     true,
+				ActionType:  client.ActionTypeSetRunProperty,
+				TriggerType: client.TriggerTypeChannelHeaderChanged,
+				Payload:     client.SetRunPropertyPayload{FieldID: field.ID, Value: value},
+			})
+			return err
+		}
+
+		requireErrorWithStatusCode(t, createAction("high"), http.StatusBadRequest)
+		assert.NoError(t, createAction(3))
+	})
+
 	t.Run("create invalid action - duplicate action and trigger types", func(t *testing.T) {
 		// Create a brand new channel
 		channel := createNewChannel(t, "create-invalid-action-duplicate")
@@ -506,6 +506,83 @@ func TestPlaybookUpdate(t *testing.T) {
 	})
 }
 
+func TestPlaybookUpdateIfUnmodified(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	t.Run("update based on the current version", func(t *testing.T) {
+		playbook, err := e.PlaybooksClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+
+		playbook.Description = "my description"
+		err = e.PlaybooksClient.Playbooks.UpdateIfUnmodified(context.Background(), *playbook)
+		require.NoError(t, err)
+
+		updated, err := e.PlaybooksClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "my description", updated.Description)
+		assert.Greater(t, updated.UpdateAt, playbook.UpdateAt)
+	})
+
+	t.Run("update based on a stale version", func(t *testing.T) {
+		playbook, err := e.PlaybooksClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+
+		theirs := *playbook
+		theirs.Description = "their description"
+		err = e.PlaybooksAdminClient.Playbooks.Update(context.Background(), theirs)
+		require.NoError(t, err)
+
+		playbook.Description = "my stale description"
+		err = e.PlaybooksClient.Playbooks.UpdateIfUnmodified(context.Background(), *playbook)
+		requireErrorWithStatusCode(t, err, http.StatusPreconditionFailed)
+
+		var staleErr *client.StaleVersionError
+		require.ErrorAs(t, err, &staleErr)
+		require.NotNil(t, staleErr.Playbook)
+		assert.Equal(t, "their description", staleErr.Playbook.Description)
+		assert.Greater(t, staleErr.Playbook.UpdateAt, playbook.UpdateAt)
+
+		current, err := e.PlaybooksClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "their description", current.Description)
+	})
+
+	// Edits made through the other playbook endpoints move UpdateAt too.
+	staleAfter := func(t *testing.T, name string, edit func(t *testing.T)) {
+		t.Run("update based on a version stale after "+name, func(t *testing.T) {
+			playbook, err := e.PlaybooksClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+			require.NoError(t, err)
+
+			edit(t)
+
+			playbook.Description = "stale after " + name
+			err = e.PlaybooksClient.Playbooks.UpdateIfUnmodified(context.Background(), *playbook)
+			requireErrorWithStatusCode(t, err, http.StatusPreconditionFailed)
+		})
+	}
+
+	staleAfter(t, "a patch", func(t *testing.T) {
+		err := e.PlaybooksAdminClient.Playbooks.Patch(context.Background(), e.BasicPlaybook.ID, client.PlaybookPatch{
+			Title: model.NewPointer("Patched title"),
+		})
+		require.NoError(t, err)
+	})
+
+	staleAfter(t, "a new metric", func(t *testing.T) {
+		_, err := e.PlaybooksAdminClient.Playbooks.AddMetric(context.Background(), e.BasicPlaybook.ID, client.PlaybookMetricConfig{
+			Title: "Time to resolve",
+			Type:  client.MetricTypeDuration,
+		})
+		require.NoError(t, err)
+	})
+
+	staleAfter(t, "a new member", func(t *testing.T) {
+		err := e.PlaybooksAdminClient.Playbooks.AddMember(context.Background(), e.BasicPlaybook.ID, e.RegularUser2.Id)
+		require.NoError(t, err)
+	})
+}
+
 func TestPlaybookUpdateCrossTeam(t *testing.T) {
 	e := Setup(t)
 	e.CreateBasic()
@@ -1616,6 +1693,41 @@ func TestPlaybooksImportExport(t *testing.T) {
 		assert.Equal(t, newFields[0].ID, importedConditions.Items[0].ConditionExpr.Is.FieldID,
 			"imported condition should reference the new property field ID")
 	})
+
+	t.Run("Bundle import skips channel actions of channels outside the team", func(t *testing.T) {
+		bundleWithActions := func(t *testing.T, exportKey, channelID string) []byte {
+			bundle := map[string]any{
+				"version": app.CurrentPlaybookExportVersion,
+				"playbooks": []map[string]any{{
+					"title":      "Bundled playbook " + exportKey,
+					"export_key": exportKey,
+					"version":    app.CurrentPlaybookExportVersion,
+					"channel_id": channelID,
+					"channel_actions": []map[string]any{{
+						"action_type":  app.ActionTypeWelcomeMessage,
+						"trigger_type": app.TriggerTypeNewMemberJoins,
+						"enabled":      true,
+						"payload":      map[string]any{"message": "Welcome"},
+					}},
+				}},
+			}
+			data, err := json.Marshal(bundle)
+			require.NoError(t, err)
+			return data
+		}
+
+		result, err := e.PlaybooksAdminClient.Playbooks.ImportBundle(context.Background(), bundleWithActions(t, model.NewId(), model.NewId()), e.BasicTeam.Id, false)
+		require.NoError(t, err)
+		require.Len(t, result.Playbooks, 1)
+		assert.Zero(t, result.Playbooks[0].NumChannelActions)
+		assert.Len(t, result.Playbooks[0].Warnings, 1)
+
+		result, err = e.PlaybooksAdminClient.Playbooks.ImportBundle(context.Background(), bundleWithActions(t, model.NewId(), e.BasicPublicChannel.Id), e.BasicTeam.Id, false)
+		require.NoError(t, err)
+		require.Len(t, result.Playbooks, 1)
+		assert.Equal(t, 1, result.Playbooks[0].NumChannelActions)
+		assert.Empty(t, result.Playbooks[0].Warnings)
+	})
 }
 
 func TestPlaybooksDuplicate(t *testing.T) {
@@ -2696,6 +2808,56 @@ func TestPatchPlaybook(t *testing.T) {
 		require.Equal(t, http.StatusConflict, resp2.StatusCode)
 	})
 
+	t.Run("playbook settings set and persisted", func(t *testing.T) {
+		id, err := e.PlaybooksClient.Playbooks.Create(context.Background(), client.PlaybookCreateOptions{
+			Title:  "Patch Settings Test",
+			TeamID: e.BasicTeam.Id,
+			Public: true,
+		})
+		require.NoError(t, err)
+
+		resp := patchPlaybook(e.ServerClient, id, map[string]any{
+			"title":                     "Patched Title",
+			"reminder_message_template": "Status: ",
+			"checklists": []map[string]any{
+				{"title": "Triage", "items": []map[string]any{{"title": "Check the logs"}}},
+			},
+		})
+		resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		pb, err := e.PlaybooksClient.Playbooks.Get(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, "Patched Title", pb.Title)
+		assert.Equal(t, "Status: ", pb.ReminderMessageTemplate)
+		require.Len(t, pb.Checklists, 1)
+		assert.Equal(t, "Triage", pb.Checklists[0].Title)
+		require.Len(t, pb.Checklists[0].Items, 1)
+		assert.Equal(t, "Check the logs", pb.Checklists[0].Items[0].Title)
+	})
+
+	t.Run("playbook settings through the client", func(t *testing.T) {
+		id, err := e.PlaybooksClient.Playbooks.Create(context.Background(), client.PlaybookCreateOptions{
+			Title:  "Patch Client Test",
+			TeamID: e.BasicTeam.Id,
+			Public: true,
+		})
+		require.NoError(t, err)
+
+		description := "Patched description"
+		prefix := "PCT"
+		err = e.PlaybooksClient.Playbooks.Patch(context.Background(), id, client.PlaybookPatch{
+			Description:     &description,
+			RunNumberPrefix: &prefix,
+		})
+		require.NoError(t, err)
+
+		pb, err := e.PlaybooksClient.Playbooks.Get(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, "Patched description", pb.Description)
+		assert.Equal(t, "PCT", pb.RunNumberPrefix)
+	})
+
 	t.Run("invalid prefix format returns 400", func(t *testing.T) {
 		id, err := e.PlaybooksClient.Playbooks.Create(context.Background(), client.PlaybookCreateOptions{
 			Title:  "Prefix Invalid Test",
@@ -2709,6 +2871,52 @@ func TestPatchPlaybook(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
+	t.Run("linking an existing channel on a new-channel-only playbook returns 400", func(t *testing.T) {
+		id, err := e.PlaybooksClient.Playbooks.Create(context.Background(), client.PlaybookCreateOptions{
+			Title:          "New Channel Only PATCH Test",
+			TeamID:         e.BasicTeam.Id,
+			Public:         true,
+			NewChannelOnly: true,
+		})
+		require.NoError(t, err)
+
+		resp := patchPlaybook(e.ServerClient, id, map[string]any{
+			"channel_mode": "link_existing_channel",
+			"channel_id":   e.BasicPublicChannel.Id,
+		})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		pb, err := e.PlaybooksClient.Playbooks.Get(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, client.PlaybookRunCreateNewChannel, pb.ChannelMode)
+	})
+
+	t.Run("unknown channel mode returns 400", func(t *testing.T) {
+		resp := patchPlaybook(e.ServerClient, e.BasicPlaybook.ID, map[string]any{"channel_mode": "no_channel"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("broadcasting to an archived channel returns 400", func(t *testing.T) {
+		channel, _, err := e.ServerAdminClient.CreateChannel(context.Background(), &model.Channel{
+			DisplayName: "Archived broadcast",
+			Name:        "archived-broadcast-patch",
+			Type:        model.ChannelTypeOpen,
+			TeamId:      e.BasicTeam.Id,
+		})
+		require.NoError(t, err)
+		_, err = e.ServerAdminClient.DeleteChannel(context.Background(), channel.Id)
+		require.NoError(t, err)
+
+		resp := patchPlaybook(e.ServerAdminClient, e.BasicPlaybook.ID, map[string]any{
+			"broadcast_channel_ids": []string{channel.Id},
+			"broadcast_enabled":     true,
+		})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
 	t.Run("unknown field in body returns 400", func(t *testing.T) {
 		resp := patchPlaybook(e.ServerClient, e.BasicPlaybook.ID, map[string]any{"unknown_field": "value"})
 		defer resp.Body.Close()
@@ -2900,6 +3108,221 @@ func stringPtr(s string) *string {
 	return &s
 }
 
+func TestPlaybookMetrics(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	var metricID string
+
+	t.Run("add metric", func(t *testing.T) {
+		metric, err := e.PlaybooksClient.Playbooks.AddMetric(context.Background(), e.BasicPlaybook.ID, client.PlaybookMetricConfig{
+			Title:       "New Metric",
+			Description: "the description",
+			Type:        app.MetricTypeDuration,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, metric.ID)
+		assert.Equal(t, e.BasicPlaybook.ID, metric.PlaybookID)
+		assert.False(t, metric.Target.Valid)
+		metricID = metric.ID
+
+		updatedPlaybook, err := e.PlaybooksAdminClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+		require.Len(t, updatedPlaybook.Metrics, 2)
+		assert.Equal(t, metric.ID, updatedPlaybook.Metrics[1].ID)
+		assert.Equal(t, "New Metric", updatedPlaybook.Metrics[1].Title)
+	})
+
+	t.Run("add metric with a duplicate title", func(t *testing.T) {
+		_, err := e.PlaybooksClient.Playbooks.AddMetric(context.Background(), e.BasicPlaybook.ID, client.PlaybookMetricConfig{
+			Title: "New Metric",
+			Type:  app.MetricTypeInteger,
+		})
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("add metric with an unknown type", func(t *testing.T) {
+		_, err := e.PlaybooksClient.Playbooks.AddMetric(context.Background(), e.BasicPlaybook.ID, client.PlaybookMetricConfig{
+			Title: "Unknown",
+			Type:  "metric_unknown",
+		})
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("update metric", func(t *testing.T) {
+		title := "Updated Title"
+		target := int64(3600000)
+		metric, err := e.PlaybooksClient.Playbooks.UpdateMetric(context.Background(), e.BasicPlaybook.ID, metricID, client.PlaybookMetricPatch{
+			Title:  &title,
+			Target: &target,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Title", metric.Title)
+		assert.Equal(t, "the description", metric.Description)
+		assert.Equal(t, target, metric.Target.Int64)
+
+		updatedPlaybook, err := e.PlaybooksAdminClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+		require.Len(t, updatedPlaybook.Metrics, 2)
+		assert.Equal(t, "Updated Title", updatedPlaybook.Metrics[1].Title)
+	})
+
+	t.Run("update metric of another playbook", func(t *testing.T) {
+		title := "Moved"
+		_, err := e.PlaybooksClient.Playbooks.UpdateMetric(context.Background(), e.BasicPrivatePlaybook.ID, metricID, client.PlaybookMetricPatch{Title: &title})
+		requireErrorWithStatusCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("add metric without access", func(t *testing.T) {
+		_, err := e.PlaybooksClient.Playbooks.AddMetric(context.Background(), e.PrivatePlaybookNoMembers.ID, client.PlaybookMetricConfig{
+			Title: "No Access",
+			Type:  app.MetricTypeInteger,
+		})
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("delete metric", func(t *testing.T) {
+		err := e.PlaybooksClient.Playbooks.DeleteMetric(context.Background(), e.BasicPlaybook.ID, metricID)
+		require.NoError(t, err)
+
+		updatedPlaybook, err := e.PlaybooksAdminClient.Playbooks.Get(context.Background(), e.BasicPlaybook.ID)
+		require.NoError(t, err)
+		require.Len(t, updatedPlaybook.Metrics, 1)
+
+		err = e.PlaybooksClient.Playbooks.DeleteMetric(context.Background(), e.BasicPlaybook.ID, metricID)
+		requireErrorWithStatusCode(t, err, http.StatusNotFound)
+	})
+}
+
+func TestPlaybookMembers(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	isMember := func(t *testing.T, playbookID, userID string) bool {
+		playbook, err := e.PlaybooksAdminClient.Playbooks.Get(context.Background(), playbookID)
+		require.NoError(t, err)
+		for _, member := range playbook.Members {
+			if member.UserID == userID {
+				return true
+			}
+		}
+		return false
+	}
+
+	revisionCount := func(t *testing.T, playbookID string) int {
+		revisions, err := e.PlaybooksAdminClient.Playbooks.GetRevisions(context.Background(), playbookID, 0, 1)
+		require.NoError(t, err)
+		return revisions.TotalCount
+	}
+
+	t.Run("add member", func(t *testing.T) {
+		before := revisionCount(t, e.BasicPlaybook.ID)
+		err := e.PlaybooksAdminClient.Playbooks.AddMember(context.Background(), e.BasicPlaybook.ID, e.RegularUser2.Id)
+		require.NoError(t, err)
+		require.True(t, isMember(t, e.BasicPlaybook.ID, e.RegularUser2.Id))
+		require.Equal(t, before+1, revisionCount(t, e.BasicPlaybook.ID))
+
+		// Adding a member again changes nothing.
+		err = e.PlaybooksAdminClient.Playbooks.AddMember(context.Background(), e.BasicPlaybook.ID, e.RegularUser2.Id)
+		require.NoError(t, err)
+	})
+
+	t.Run("leave playbook", func(t *testing.T) {
+		before := revisionCount(t, e.BasicPlaybook.ID)
+		err := e.PlaybooksClient2.Playbooks.RemoveMember(context.Background(), e.BasicPlaybook.ID, e.RegularUser2.Id)
+		require.NoError(t, err)
+		require.False(t, isMember(t, e.BasicPlaybook.ID, e.RegularUser2.Id))
+		require.Equal(t, before+1, revisionCount(t, e.BasicPlaybook.ID))
+	})
+
+	t.Run("add member without access", func(t *testing.T) {
+		err := e.PlaybooksClient.Playbooks.AddMember(context.Background(), e.PrivatePlaybookNoMembers.ID, e.RegularUser.Id)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("add member to archived playbook", func(t *testing.T) {
+		err := e.PlaybooksAdminClient.Playbooks.AddMember(context.Background(), e.ArchivedPlaybook.ID, e.RegularUser2.Id)
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+}
+
 func float64Ptr(f float64) *float64 {
 	return &f
 }
+
+func TestPublishedPlaybookPropertiesReachRuns(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+	e.SetEnterpriseLicence()
+
+	pbID, err := e.PlaybooksAdminClient.Playbooks.Create(context.Background(), client.PlaybookCreateOptions{
+		Title:  "Published Properties Playbook",
+		TeamID: e.BasicTeam.Id,
+		Public: true,
+	})
+	require.NoError(t, err)
+
+	field, err := e.PlaybooksAdminClient.Playbooks.CreatePropertyField(context.Background(), pbID, client.PropertyFieldRequest{Name: "Priority", Type: "text"})
+	require.NoError(t, err)
+
+	_, err = e.PlaybooksAdminClient.Playbooks.Publish(context.Background(), pbID)
+	require.NoError(t, err)
+
+	// Renaming the field only changes the draft.
+	_, err = e.PlaybooksAdminClient.Playbooks.UpdatePropertyField(context.Background(), pbID, field.ID, client.PropertyFieldRequest{Name: "Urgency", Type: "text"})
+	require.NoError(t, err)
+
+	run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Run of the published playbook",
+		OwnerUserID: e.RegularUser.Id,
+		TeamID:      e.BasicTeam.Id,
+		PlaybookID:  pbID,
+	})
+	require.NoError(t, err)
+
+	runFields, err := e.PlaybooksClient.PlaybookRuns.GetPropertyFields(context.Background(), run.ID)
+	require.NoError(t, err)
+	names := make([]string, 0, len(runFields))
+	for _, runField := range runFields {
+		names = append(names, runField.Name)
+	}
+	assert.Contains(t, names, "Priority")
+	assert.NotContains(t, names, "Urgency")
+}
+
+func TestPublishedPlaybookRunSettingsReachRuns(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+	e.SetEnterpriseLicence()
+
+	pbID, err := e.PlaybooksAdminClient.Playbooks.Create(context.Background(), client.PlaybookCreateOptions{
+		Title:  "Published Run Settings Playbook",
+		TeamID: e.BasicTeam.Id,
+		Public: true,
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksAdminClient.Playbooks.Patch(context.Background(), pbID, client.PlaybookPatch{
+		WebhookOnCreationURLs:    &[]string{"https://example.com/published"},
+		WebhookOnCreationEnabled: model.NewPointer(true),
+	})
+	require.NoError(t, err)
+
+	_, err = e.PlaybooksAdminClient.Playbooks.Publish(context.Background(), pbID)
+	require.NoError(t, err)
+
+	// Changing the webhook only changes the draft.
+	err = e.PlaybooksAdminClient.Playbooks.Patch(context.Background(), pbID, client.PlaybookPatch{
+		WebhookOnCreationURLs: &[]string{"https://example.com/draft"},
+	})
+	require.NoError(t, err)
+
+	run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Run of the published playbook",
+		OwnerUserID: e.RegularUser.Id,
+		TeamID:      e.BasicTeam.Id,
+		PlaybookID:  pbID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/published"}, run.WebhookOnCreationURLs)
+}
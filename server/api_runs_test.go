@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -3135,6 +3136,62 @@ func TestUpdatePlaybookRun(t *testing.T) {
 
 }
 
+func TestUpdatePlaybookRunIfUnmodified(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	newRun := func(t *testing.T) *client.PlaybookRun {
+		run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+			Name:        "Versioned Run",
+			OwnerUserID: e.RegularUser.Id,
+			TeamID:      e.BasicTeam.Id,
+			PlaybookID:  e.BasicPlaybook.ID,
+		})
+		require.NoError(t, err)
+		return run
+	}
+
+	t.Run("update based on the current version", func(t *testing.T) {
+		run := newRun(t)
+
+		name := "Renamed Run"
+		updatedRun, err := e.PlaybooksClient.PlaybookRuns.UpdateIfUnmodified(context.Background(), run.ID, run.UpdateAt, client.PlaybookRunUpdateOptions{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, name, updatedRun.Name)
+		assert.Greater(t, updatedRun.UpdateAt, run.UpdateAt)
+	})
+
+	t.Run("update based on a stale version", func(t *testing.T) {
+		run := newRun(t)
+
+		theirName := "Their Name"
+		theirRun, err := e.PlaybooksClient.PlaybookRuns.Update(context.Background(), run.ID, client.PlaybookRunUpdateOptions{Name: &theirName})
+		require.NoError(t, err)
+
+		myName := "My Name"
+		_, err = e.PlaybooksClient.PlaybookRuns.UpdateIfUnmodified(context.Background(), run.ID, run.UpdateAt, client.PlaybookRunUpdateOptions{Name: &myName})
+		requireErrorWithStatusCode(t, err, http.StatusPreconditionFailed)
+
+		var staleErr *client.StaleVersionError
+		require.ErrorAs(t, err, &staleErr)
+		require.NotNil(t, staleErr.PlaybookRun)
+		assert.Equal(t, theirName, staleErr.PlaybookRun.Name)
+		assert.Equal(t, theirRun.UpdateAt, staleErr.PlaybookRun.UpdateAt)
+
+		current, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		assert.Equal(t, theirName, current.Name)
+	})
+
+	t.Run("stale version is not disclosed without permission", func(t *testing.T) {
+		run := newRun(t)
+
+		name := "Intruder Name"
+		_, err := e.PlaybooksClientNotInTeam.PlaybookRuns.UpdateIfUnmodified(context.Background(), run.ID, run.UpdateAt-1, client.PlaybookRunUpdateOptions{Name: &name})
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+}
+
 func TestRunGetMetadata(t *testing.T) {
 	e := Setup(t)
 	e.CreateBasic()
@@ -5803,3 +5860,586 @@ func TestSetRunPropertyValue_UserField(t *testing.T) {
 			"run owner must be able to add a new member to the run via property assignment")
 	})
 }
+
+func TestRunParticipantsAndTimelineREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	t.Run("add and remove a participant", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.AddParticipants(context.Background(), e.BasicRun.ID, []string{e.RegularUser2.Id}, false)
+		require.NoError(t, err)
+
+		run, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), e.BasicRun.ID)
+		require.NoError(t, err)
+		assert.Contains(t, run.ParticipantIDs, e.RegularUser2.Id)
+
+		err = e.PlaybooksClient.PlaybookRuns.RemoveParticipant(context.Background(), e.BasicRun.ID, e.RegularUser2.Id)
+		require.NoError(t, err)
+
+		run, err = e.PlaybooksClient.PlaybookRuns.Get(context.Background(), e.BasicRun.ID)
+		require.NoError(t, err)
+		assert.NotContains(t, run.ParticipantIDs, e.RegularUser2.Id)
+	})
+
+	t.Run("users outside the run cannot add others", func(t *testing.T) {
+		err := e.PlaybooksClientNotInTeam.PlaybookRuns.AddParticipants(context.Background(), e.BasicRun.ID, []string{e.RegularUser2.Id}, false)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("add a post to the timeline", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.AddPostToTimeline(context.Background(), e.BasicRun.ID, e.BasicPublicChannelPost.Id, "Customer report")
+		require.NoError(t, err)
+
+		run, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), e.BasicRun.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, run.TimelineEvents)
+		lastEvent := run.TimelineEvents[len(run.TimelineEvents)-1]
+		assert.Equal(t, client.EventFromPost, lastEvent.EventType)
+		assert.Equal(t, "Customer report", lastEvent.Summary)
+		assert.Equal(t, e.BasicPublicChannelPost.Id, lastEvent.PostID)
+	})
+
+	t.Run("timeline rejects an invalid post ID", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.AddPostToTimeline(context.Background(), e.BasicRun.ID, "invalid", "Summary")
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+}
+
+func TestRunTodoDigestREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Todo run",
+		OwnerUserID: e.RegularUser.Id,
+		TeamID:      e.BasicTeam.Id,
+		PlaybookID:  e.BasicPlaybook.ID,
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.CreateChecklist(context.Background(), run.ID, client.Checklist{
+		Title: "Todo checklist",
+		Items: []client.ChecklistItem{{Title: "Todo item"}},
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.SetItemAssignee(context.Background(), run.ID, 0, 0, e.RegularUser.Id)
+	require.NoError(t, err)
+
+	t.Run("lists assigned tasks and runs in progress", func(t *testing.T) {
+		digest, err := e.PlaybooksClient.PlaybookRuns.GetTodoDigest(context.Background())
+		require.NoError(t, err)
+
+		var assigned *client.AssignedRun
+		for i := range digest.AssignedRuns {
+			if digest.AssignedRuns[i].PlaybookRunID == run.ID {
+				assigned = &digest.AssignedRuns[i]
+			}
+		}
+		require.NotNil(t, assigned)
+		require.Len(t, assigned.Tasks, 1)
+		assert.Equal(t, "Todo item", assigned.Tasks[0].Title)
+		assert.Equal(t, "Todo checklist", assigned.Tasks[0].ChecklistTitle)
+
+		assert.Contains(t, digest.InProgressRuns, client.RunLink{PlaybookRunID: run.ID, Name: "Todo run"})
+	})
+
+	t.Run("other users do not see the tasks", func(t *testing.T) {
+		digest, err := e.PlaybooksClient2.PlaybookRuns.GetTodoDigest(context.Background())
+		require.NoError(t, err)
+		for _, assigned := range digest.AssignedRuns {
+			assert.NotEqual(t, run.ID, assigned.PlaybookRunID)
+		}
+		assert.NotNil(t, digest.OverdueRuns)
+	})
+}
+
+func TestCreateRunMalformedDetails(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	_, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Run without team",
+		OwnerUserID: e.RegularUser.Id,
+		PlaybookID:  e.BasicPlaybook.ID,
+	})
+	requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+
+	var errResponse *client.ErrorResponse
+	require.True(t, errors.As(err, &errResponse))
+	assert.Equal(t, "unable to create playbook run", errResponse.Err.Error())
+	assert.Equal(t, "must provide team or channel to create playbook run", errResponse.Details)
+}
+
+func TestRunChangeFeedREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	setChangeFeed := func(t *testing.T, enable bool) {
+		cfg := e.Srv.Config()
+		cfg.PluginSettings.Plugins["playbooks"]["EnableRunChangeFeed"] = enable
+
+		// Patching only the plugin config doesn't trigger an OnConfigurationChange, so change an
+		// unrelated setting as well.
+		var patchedConfig model.Config
+		patchedConfig.ServiceSettings.GiphySdkKey = testPtr(model.NewRandomString(6))
+		patchedConfig.PluginSettings.Plugins = map[string]map[string]any{
+			"playbooks": cfg.PluginSettings.Plugins["playbooks"],
+		}
+		_, _, err := e.ServerAdminClient.PatchConfig(context.Background(), &patchedConfig)
+		require.NoError(t, err)
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		_, err := e.PlaybooksClient.PlaybookRuns.GetChanges(context.Background(), client.RunChangesOptions{}, 0)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	setChangeFeed(t, true)
+	defer setChangeFeed(t, false)
+
+	start, err := e.PlaybooksClient.PlaybookRuns.GetChanges(context.Background(), client.RunChangesOptions{TeamID: e.BasicTeam.Id}, 0)
+	require.NoError(t, err)
+	require.Empty(t, start.Changes)
+
+	run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Change feed run",
+		OwnerUserID: e.RegularUser.Id,
+		TeamID:      e.BasicTeam.Id,
+		PlaybookID:  e.BasicPlaybook.ID,
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.CreateChecklist(context.Background(), run.ID, client.Checklist{
+		Title: "Feed checklist",
+		Items: []client.ChecklistItem{{Title: "Feed item"}},
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.SetItemAssignee(context.Background(), run.ID, 0, 0, e.RegularUser.Id)
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.Finish(context.Background(), run.ID)
+	require.NoError(t, err)
+
+	t.Run("long polls the changes of the run", func(t *testing.T) {
+		var changes []client.RunChange
+		cursor := start.Cursor
+		require.Eventually(t, func() bool {
+			result, err := e.PlaybooksClient.PlaybookRuns.GetChanges(context.Background(), client.RunChangesOptions{Cursor: cursor, PlaybookRunID: run.ID}, 2*time.Second)
+			require.NoError(t, err)
+			changes = append(changes, result.Changes...)
+			cursor = result.Cursor
+			return len(changes) > 0 && changes[len(changes)-1].Type == client.RunChangeFinished
+		}, 20*time.Second, 100*time.Millisecond)
+
+		types := make(map[string]bool)
+		for _, change := range changes {
+			assert.Equal(t, run.ID, change.PlaybookRunID)
+			types[change.Type] = true
+		}
+		assert.True(t, types[client.RunChangeCreated])
+		assert.True(t, types[client.RunChangeUpdated])
+		assert.True(t, types[client.RunChangeChecklistItem])
+
+		var created client.PlaybookRun
+		require.NoError(t, json.Unmarshal(changes[0].Payload, &created))
+		assert.Equal(t, client.RunChangeCreated, changes[0].Type)
+		assert.Equal(t, "Change feed run", created.Name)
+	})
+
+	t.Run("streams the changes of the run", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		errFinished := errors.New("finished")
+		var received []client.RunChange
+		err := e.PlaybooksClient.PlaybookRuns.SubscribeChanges(ctx, client.RunChangesOptions{Cursor: start.Cursor, TeamID: e.BasicTeam.Id}, func(change client.RunChange) error {
+			received = append(received, change)
+			if change.PlaybookRunID == run.ID && change.Type == client.RunChangeFinished {
+				return errFinished
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errFinished)
+		require.NotEmpty(t, received)
+		assert.Equal(t, client.RunChangeCreated, received[0].Type)
+	})
+
+	t.Run("rejects runs the user cannot view", func(t *testing.T) {
+		_, err := e.PlaybooksClientNotInTeam.PlaybookRuns.GetChanges(context.Background(), client.RunChangesOptions{PlaybookRunID: run.ID}, 0)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+}
+
+func TestBulkOperationsREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	createRun := func(t *testing.T) *client.PlaybookRun {
+		run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+			Name:        "Bulk run",
+			OwnerUserID: e.RegularUser.Id,
+			TeamID:      e.BasicTeam.Id,
+			PlaybookID:  e.BasicPlaybook.ID,
+		})
+		require.NoError(t, err)
+
+		err = e.PlaybooksClient.PlaybookRuns.CreateChecklist(context.Background(), run.ID, client.Checklist{
+			Title: "First",
+			Items: []client.ChecklistItem{{Title: "One"}, {Title: "Two"}},
+		})
+		require.NoError(t, err)
+		err = e.PlaybooksClient.PlaybookRuns.CreateChecklist(context.Background(), run.ID, client.Checklist{
+			Title: "Second",
+			Items: []client.ChecklistItem{{Title: "Three"}},
+		})
+		require.NoError(t, err)
+
+		run, err = e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		require.Len(t, run.Checklists, 2)
+		return run
+	}
+
+	t.Run("checklist item operations report per-operation results", func(t *testing.T) {
+		run := createRun(t)
+		one := run.Checklists[0].Items[0].ID
+		two := run.Checklists[0].Items[1].ID
+
+		results, err := e.PlaybooksClient.PlaybookRuns.ApplyChecklistItemOperations(context.Background(), run.ID, []client.ChecklistItemOperation{
+			{Type: client.ChecklistItemOperationSetState, ItemID: one, State: "closed"},
+			{Type: client.ChecklistItemOperationSetAssignee, ItemID: two, AssigneeID: e.RegularUser2.Id},
+			{Type: client.ChecklistItemOperationMove, ItemID: one, ChecklistID: run.Checklists[1].ID},
+			{Type: client.ChecklistItemOperationSetState, ItemID: "missing", State: "closed"},
+		}, false)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.True(t, results[0].Success)
+		assert.True(t, results[1].Success)
+		assert.True(t, results[2].Success)
+		assert.False(t, results[3].Success)
+		assert.Equal(t, `checklist item "missing" not found`, results[3].Error)
+
+		run, err = e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		require.Len(t, run.Checklists[0].Items, 1)
+		assert.Equal(t, e.RegularUser2.Id, run.Checklists[0].Items[0].AssigneeID)
+		require.Len(t, run.Checklists[1].Items, 2)
+		assert.Equal(t, one, run.Checklists[1].Items[1].ID)
+		assert.Equal(t, "closed", run.Checklists[1].Items[1].State)
+	})
+
+	t.Run("atomic checklist item operations apply nothing when one is invalid", func(t *testing.T) {
+		run := createRun(t)
+		one := run.Checklists[0].Items[0].ID
+
+		_, err := e.PlaybooksClient.PlaybookRuns.ApplyChecklistItemOperations(context.Background(), run.ID, []client.ChecklistItemOperation{
+			{Type: client.ChecklistItemOperationSetState, ItemID: one, State: "closed"},
+			{Type: client.ChecklistItemOperationSetState, ItemID: one, State: "done"},
+		}, true)
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+
+		var errResponse *client.ErrorResponse
+		require.True(t, errors.As(err, &errResponse))
+		assert.Equal(t, `operation 1: invalid state "done"`, errResponse.Details)
+
+		run, err = e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		assert.Empty(t, run.Checklists[0].Items[0].State)
+	})
+
+	t.Run("checklist item operations require edit permissions", func(t *testing.T) {
+		run := createRun(t)
+
+		_, err := e.PlaybooksClientNotInTeam.PlaybookRuns.ApplyChecklistItemOperations(context.Background(), run.ID, []client.ChecklistItemOperation{
+			{Type: client.ChecklistItemOperationSetState, ItemID: run.Checklists[0].Items[0].ID, State: "closed"},
+		}, false)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("add participants and change owner of many runs", func(t *testing.T) {
+		first := createRun(t)
+		second := createRun(t)
+
+		results, err := e.PlaybooksClient.PlaybookRuns.ApplyRunsOperation(context.Background(), client.RunsOperation{
+			Type:    client.RunOperationAddParticipants,
+			RunIDs:  []string{first.ID, second.ID},
+			UserIDs: []string{e.RegularUser2.Id},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []client.BulkOperationResult{
+			{Index: 0, ID: first.ID, Success: true},
+			{Index: 1, ID: second.ID, Success: true},
+		}, results)
+
+		results, err = e.PlaybooksClient.PlaybookRuns.ApplyRunsOperation(context.Background(), client.RunsOperation{
+			Type:    client.RunOperationChangeOwner,
+			RunIDs:  []string{first.ID, second.ID},
+			OwnerID: e.RegularUser2.Id,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Success)
+		assert.True(t, results[1].Success)
+
+		for _, runID := range []string{first.ID, second.ID} {
+			run, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), runID)
+			require.NoError(t, err)
+			assert.Contains(t, run.ParticipantIDs, e.RegularUser2.Id)
+			assert.Equal(t, e.RegularUser2.Id, run.OwnerUserID)
+		}
+	})
+
+	t.Run("finish many runs reports the runs the user cannot finish", func(t *testing.T) {
+		first := createRun(t)
+		second := createRun(t)
+
+		results, err := e.PlaybooksClientNotInTeam.PlaybookRuns.ApplyRunsOperation(context.Background(), client.RunsOperation{
+			Type:   client.RunOperationFinish,
+			RunIDs: []string{first.ID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []client.BulkOperationResult{{Index: 0, ID: first.ID, Error: "Not authorized"}}, results)
+
+		_, err = e.PlaybooksClientNotInTeam.PlaybookRuns.ApplyRunsOperation(context.Background(), client.RunsOperation{
+			Type:          client.RunOperationFinish,
+			RunIDs:        []string{first.ID},
+			ValidateFirst: true,
+		})
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+
+		results, err = e.PlaybooksClient.PlaybookRuns.ApplyRunsOperation(context.Background(), client.RunsOperation{
+			Type:   client.RunOperationFinish,
+			RunIDs: []string{first.ID, second.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Success)
+		assert.True(t, results[1].Success)
+
+		run, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, client.StatusFinished, run.CurrentStatus)
+	})
+
+	t.Run("rejects an unknown operation", func(t *testing.T) {
+		_, err := e.PlaybooksClient.PlaybookRuns.ApplyRunsOperation(context.Background(), client.RunsOperation{
+			Type:   "delete",
+			RunIDs: []string{e.BasicRun.ID},
+		})
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+}
+
+func TestChecklistItemsByIDREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Items by ID",
+		OwnerUserID: e.RegularUser.Id,
+		TeamID:      e.BasicTeam.Id,
+		PlaybookID:  e.BasicPlaybook.ID,
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.CreateChecklist(context.Background(), run.ID, client.Checklist{
+		Title: "Checklist",
+		Items: []client.ChecklistItem{{Title: "One"}, {Title: "Two"}, {Title: "Three"}},
+	})
+	require.NoError(t, err)
+
+	getItem := func(t *testing.T, itemID string) client.ChecklistItem {
+		t.Helper()
+		run, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		for _, checklist := range run.Checklists {
+			for _, item := range checklist.Items {
+				if item.ID == itemID {
+					return item
+				}
+			}
+		}
+		require.Failf(t, "checklist item not found", "item %s", itemID)
+		return client.ChecklistItem{}
+	}
+
+	run, err = e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+	require.NoError(t, err)
+	one := run.Checklists[0].Items[0]
+	three := run.Checklists[0].Items[2]
+
+	t.Run("changes are unaffected by moves", func(t *testing.T) {
+		position := 0
+		err := e.PlaybooksClient.PlaybookRuns.MoveItemByID(context.Background(), run.ID, client.ChecklistItemRef{ItemID: three.ID}, "", &position)
+		require.NoError(t, err)
+
+		err = e.PlaybooksClient.PlaybookRuns.SetItemStateByID(context.Background(), run.ID, client.ChecklistItemRef{ItemID: one.ID}, "closed")
+		require.NoError(t, err)
+
+		updated, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		assert.Equal(t, three.ID, updated.Checklists[0].Items[0].ID)
+		assert.Equal(t, one.ID, updated.Checklists[0].Items[1].ID)
+		assert.Equal(t, "closed", updated.Checklists[0].Items[1].State)
+	})
+
+	t.Run("stale writes are rejected", func(t *testing.T) {
+		current := getItem(t, three.ID)
+
+		err := e.PlaybooksClient.PlaybookRuns.SetItemAssigneeByID(context.Background(), run.ID, current.Ref(), e.RegularUser2.Id)
+		require.NoError(t, err)
+
+		err = e.PlaybooksClient.PlaybookRuns.SetItemAssigneeByID(context.Background(), run.ID, current.Ref(), e.RegularUser.Id)
+		requireErrorWithStatusCode(t, err, http.StatusConflict)
+		assert.Equal(t, e.RegularUser2.Id, getItem(t, three.ID).AssigneeID)
+
+		err = e.PlaybooksClient.PlaybookRuns.SetItemAssigneeByID(context.Background(), run.ID, getItem(t, three.ID).Ref(), e.RegularUser.Id)
+		require.NoError(t, err)
+		assert.Equal(t, e.RegularUser.Id, getItem(t, three.ID).AssigneeID)
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.SkipItemByID(context.Background(), run.ID, client.ChecklistItemRef{ItemID: model.NewId()})
+		requireErrorWithStatusCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("requires edit permissions", func(t *testing.T) {
+		err := e.PlaybooksClientNotInTeam.PlaybookRuns.SkipItemByID(context.Background(), run.ID, client.ChecklistItemRef{ItemID: one.ID})
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.RemoveItemByID(context.Background(), run.ID, client.ChecklistItemRef{ItemID: one.ID})
+		require.NoError(t, err)
+
+		updated, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		require.Len(t, updated.Checklists[0].Items, 2)
+		for _, item := range updated.Checklists[0].Items {
+			assert.NotEqual(t, one.ID, item.ID)
+		}
+	})
+}
+
+func TestRunItemTaskActionsREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	run, err := e.PlaybooksClient.PlaybookRuns.Create(context.Background(), client.PlaybookRunCreateOptions{
+		Name:        "Task actions",
+		OwnerUserID: e.RegularUser.Id,
+		TeamID:      e.BasicTeam.Id,
+		PlaybookID:  e.BasicPlaybook.ID,
+	})
+	require.NoError(t, err)
+
+	err = e.PlaybooksClient.PlaybookRuns.CreateChecklist(context.Background(), run.ID, client.Checklist{
+		Title: "Checklist",
+		Items: []client.ChecklistItem{{Title: "One"}, {Title: "Two"}},
+	})
+	require.NoError(t, err)
+
+	run, err = e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+	require.NoError(t, err)
+	checklistIdx := len(run.Checklists) - 1
+	two := run.Checklists[checklistIdx].Items[1]
+
+	taskAction := client.TaskAction{
+		Trigger: client.TriggerAction{
+			Type:    string(app.KeywordsByUsersTriggerType),
+			Payload: `{"keywords":["one","two"],"user_ids":["abc"]}`,
+		},
+		Actions: []client.TriggerAction{{
+			Type:    string(app.MarkItemAsDoneActionType),
+			Payload: `{"enabled":false}`,
+		}},
+	}
+
+	t.Run("set by position", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.SetItemTaskActions(context.Background(), run.ID, checklistIdx, 0, []client.TaskAction{taskAction})
+		require.NoError(t, err)
+
+		updated, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		taskActions := updated.Checklists[checklistIdx].Items[0].TaskActions
+		require.Len(t, taskActions, 1)
+		assert.Equal(t, taskAction.Trigger.Payload, taskActions[0].Trigger.Payload)
+		assert.Equal(t, taskAction.Actions[0].Type, taskActions[0].Actions[0].Type)
+	})
+
+	t.Run("set by ID", func(t *testing.T) {
+		err := e.PlaybooksClient.PlaybookRuns.SetItemTaskActionsByID(context.Background(), run.ID, two.Ref(), []client.TaskAction{taskAction})
+		require.NoError(t, err)
+
+		updated, err := e.PlaybooksClient.PlaybookRuns.Get(context.Background(), run.ID)
+		require.NoError(t, err)
+		require.Len(t, updated.Checklists[checklistIdx].Items[1].TaskActions, 1)
+
+		// The item changed since two was read.
+		err = e.PlaybooksClient.PlaybookRuns.SetItemTaskActionsByID(context.Background(), run.ID, two.Ref(), nil)
+		requireErrorWithStatusCode(t, err, http.StatusConflict)
+	})
+
+	t.Run("rejects an invalid trigger", func(t *testing.T) {
+		invalid := taskAction
+		invalid.Trigger.Type = "unknown"
+		err := e.PlaybooksClient.PlaybookRuns.SetItemTaskActions(context.Background(), run.ID, checklistIdx, 0, []client.TaskAction{invalid})
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("requires edit permissions", func(t *testing.T) {
+		err := e.PlaybooksClientNotInTeam.PlaybookRuns.SetItemTaskActions(context.Background(), run.ID, checklistIdx, 0, nil)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+}
+
+func TestFavoritesREST(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	isFavorite := func(t *testing.T, c *client.Client, itemType client.CategoryItemType, itemID string) bool {
+		t.Helper()
+		favorite, err := c.Categories.IsFavorite(context.Background(), client.CategoriesIsFavoriteOptions{
+			TeamId:   e.BasicTeam.Id,
+			ItemId:   itemID,
+			ItemType: string(itemType),
+		})
+		require.NoError(t, err)
+		return favorite
+	}
+
+	t.Run("favorite and unfavorite a run", func(t *testing.T) {
+		err := e.PlaybooksClient.Categories.SetFavorite(context.Background(), client.RunItemType, e.BasicRun.ID, true)
+		require.NoError(t, err)
+		require.True(t, isFavorite(t, e.PlaybooksClient, client.RunItemType, e.BasicRun.ID))
+
+		// Favoriting again changes nothing.
+		err = e.PlaybooksClient.Categories.SetFavorite(context.Background(), client.RunItemType, e.BasicRun.ID, true)
+		require.NoError(t, err)
+
+		err = e.PlaybooksClient.Categories.SetFavorite(context.Background(), client.RunItemType, e.BasicRun.ID, false)
+		require.NoError(t, err)
+		require.False(t, isFavorite(t, e.PlaybooksClient, client.RunItemType, e.BasicRun.ID))
+
+		err = e.PlaybooksClient.Categories.SetFavorite(context.Background(), client.RunItemType, e.BasicRun.ID, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("favorite a playbook with read access", func(t *testing.T) {
+		err := e.PlaybooksClient2.Categories.SetFavorite(context.Background(), client.PlaybookItemType, e.BasicPlaybook.ID, true)
+		require.NoError(t, err)
+		require.True(t, isFavorite(t, e.PlaybooksClient2, client.PlaybookItemType, e.BasicPlaybook.ID))
+	})
+
+	t.Run("favorite a private playbook without access", func(t *testing.T) {
+		err := e.PlaybooksClient.Categories.SetFavorite(context.Background(), client.PlaybookItemType, e.PrivatePlaybookNoMembers.ID, true)
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("favorite an archived playbook", func(t *testing.T) {
+		err := e.PlaybooksAdminClient.Categories.SetFavorite(context.Background(), client.PlaybookItemType, e.ArchivedPlaybook.ID, true)
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+}
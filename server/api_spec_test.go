@@ -0,0 +1,90 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/api/apispec"
+)
+
+// TestAPISpecServerResponses checks the responses of the handlers, as served by the plugin,
+// against the schemas of api.yaml.
+func TestAPISpecServerResponses(t *testing.T) {
+	e := Setup(t)
+	e.CreateBasic()
+
+	spec, err := apispec.Load("api/api.yaml")
+	require.NoError(t, err)
+
+	request := func(t *testing.T, method, path string, body any) {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, e.ServerAdminClient.URL+apispec.Prefix+"/api/v0"+path, reader)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+e.ServerAdminClient.AuthToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		require.Less(t, resp.StatusCode, http.StatusBadRequest, "%s", data)
+		require.NoError(t, spec.CheckResponse(method, req.URL.Path, resp.StatusCode, data))
+	}
+
+	runID := e.BasicRun.ID
+	playbookID := e.BasicPlaybook.ID
+	teamID := e.BasicTeam.Id
+
+	testCases := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, "/runs/" + runID, nil},
+		{http.MethodGet, "/runs/" + runID + "/metadata", nil},
+		{http.MethodGet, "/runs/" + runID + "/status-updates", nil},
+		{http.MethodGet, "/runs/" + runID + "/property_fields", nil},
+		{http.MethodGet, "/runs/" + runID + "/property_values", nil},
+		{http.MethodGet, "/runs/" + runID + "/property_values/history", nil},
+		{http.MethodGet, "/runs?team_id=" + teamID, nil},
+		{http.MethodGet, "/runs/owners?team_id=" + teamID, nil},
+		{http.MethodGet, "/runs/channel/" + e.BasicRun.ChannelID + "/runs", nil},
+		{http.MethodGet, "/runs/todo", nil},
+		{http.MethodGet, "/playbooks/" + playbookID, nil},
+		{http.MethodGet, "/playbooks?team_id=" + teamID, nil},
+		{http.MethodGet, "/playbooks/" + playbookID + "/property_fields", nil},
+		{http.MethodGet, "/playbooks/" + playbookID + "/revisions?page=0&per_page=10", nil},
+		{http.MethodPost, "/playbooks/" + playbookID + "/publish", nil},
+		{http.MethodPost, "/playbooks/" + playbookID + "/duplicate", nil},
+		{http.MethodPost, "/playbooks/bundle/import?dry_run=true&team_id=" + teamID, map[string]any{
+			"version":   1,
+			"playbooks": []map[string]any{{"title": "Bundled playbook", "version": 1}},
+		}},
+		{http.MethodGet, "/my_categories?team_id=" + teamID, nil},
+		{http.MethodGet, fmt.Sprintf("/my_categories/favorites?team_id=%s&item_id=%s&type=r", teamID, runID), nil},
+		{http.MethodGet, "/settings", nil},
+		{http.MethodGet, "/stats/site", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			request(t, tc.method, tc.path, tc.body)
+		})
+	}
+}
@@ -3,7 +3,11 @@
 
 package app
 
-import "github.com/mattermost/mattermost/server/public/model"
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
 
 type GenericChannelActionWithoutPayload struct {
 	ID          string      `json:"id"`
@@ -26,12 +30,63 @@ type WelcomeMessagePayload struct {
 type PromptRunPlaybookFromKeywordsPayload struct {
 	Keywords   []string `json:"keywords" mapstructure:"keywords"`
 	PlaybookID string   `json:"playbook_id" mapstructure:"playbook_id"`
+	// AutoStart starts a run from the matching post instead of suggesting the playbook.
+	AutoStart           bool `json:"auto_start,omitempty" mapstructure:"auto_start"`
+	RunAutoStartOptions `mapstructure:",squash"`
 }
 
 type CategorizeChannelPayload struct {
 	CategoryName string `json:"category_name" mapstructure:"category_name"`
 }
 
+// ChannelActionTriggerFilter narrows down when the run actions fire. Only the field matching the
+// action's trigger type is used: Keywords for keywords, UserIDs for message_from_user and
+// EmojiNames for reaction_added.
+type ChannelActionTriggerFilter struct {
+	Keywords   []string `json:"keywords,omitempty" mapstructure:"keywords"`
+	UserIDs    []string `json:"user_ids,omitempty" mapstructure:"user_ids"`
+	EmojiNames []string `json:"emoji_names,omitempty" mapstructure:"emoji_names"`
+}
+
+// RunAutoStartOptions configures the runs that channel actions start without a prompt.
+type RunAutoStartOptions struct {
+	// CooldownSeconds is how long after starting a run of the playbook the action ignores its
+	// trigger in the channel, so that a flapping alert starts a single run. Zero disables it,
+	// except for the membership triggers, which always wait at least a minute.
+	CooldownSeconds int64 `json:"cooldown_seconds,omitempty" mapstructure:"cooldown_seconds"`
+	// PropertyExtractors set property values of the started run from the triggering post.
+	PropertyExtractors []PropertyExtractor `json:"property_extractors,omitempty" mapstructure:"property_extractors"`
+}
+
+// PropertyExtractor sets the playbook property field FieldID from the first match of Pattern in
+// the post message, or else in its attachments. The value is the first capturing group of the
+// pattern if it has one, the whole match otherwise. Select options are matched by name.
+type PropertyExtractor struct {
+	FieldID string `json:"field_id" mapstructure:"field_id"`
+	Pattern string `json:"pattern" mapstructure:"pattern"`
+}
+
+// StartRunPayload starts a run of PlaybookID without prompting. Keywords are watched per channel,
+// through keywords channel actions: the SignalAnyKeywords of playbooks are a legacy setting that
+// the server no longer acts on, so they cannot auto-start runs.
+type StartRunPayload struct {
+	ChannelActionTriggerFilter `mapstructure:",squash"`
+	PlaybookID                 string `json:"playbook_id" mapstructure:"playbook_id"`
+	RunAutoStartOptions        `mapstructure:",squash"`
+}
+
+type AddToRunTimelinePayload struct {
+	ChannelActionTriggerFilter `mapstructure:",squash"`
+	// Summary is the title of the timeline event. Empty uses a description of the trigger.
+	Summary string `json:"summary" mapstructure:"summary"`
+}
+
+type SetRunPropertyPayload struct {
+	ChannelActionTriggerFilter `mapstructure:",squash"`
+	FieldID                    string      `json:"field_id" mapstructure:"field_id"`
+	Value                      interface{} `json:"value" mapstructure:"value"`
+}
+
 type ActionType string
 type TriggerType string
 
@@ -40,21 +95,59 @@ const (
 	ActionTypeWelcomeMessage    ActionType = "send_welcome_message"
 	ActionTypePromptRunPlaybook ActionType = "prompt_run_playbook"
 	ActionTypeCategorizeChannel ActionType = "categorize_channel"
+	ActionTypeStartRun          ActionType = "start_run"
+	ActionTypeAddToRunTimeline  ActionType = "add_to_run_timeline"
+	ActionTypeSetRunProperty    ActionType = "set_run_property"
 
 	// Trigger types: add new types to the ValidTriggerTypes array below
-	TriggerTypeNewMemberJoins TriggerType = "new_member_joins"
-	TriggerTypeKeywordsPosted TriggerType = "keywords"
+	TriggerTypeNewMemberJoins       TriggerType = "new_member_joins"
+	TriggerTypeKeywordsPosted       TriggerType = "keywords"
+	TriggerTypeMemberLeaves         TriggerType = "member_leaves"
+	TriggerTypeChannelHeaderChanged TriggerType = "channel_header_changed"
+	TriggerTypeFileAttachment       TriggerType = "file_attachment_posted"
+	TriggerTypeMessageFromUser      TriggerType = "message_from_user"
+	TriggerTypeReactionAdded        TriggerType = "reaction_added"
 )
 
 var ValidActionTypes = []ActionType{
 	ActionTypeWelcomeMessage,
 	ActionTypePromptRunPlaybook,
 	ActionTypeCategorizeChannel,
+	ActionTypeStartRun,
+	ActionTypeAddToRunTimeline,
+	ActionTypeSetRunProperty,
 }
 
 var ValidTriggerTypes = []TriggerType{
 	TriggerTypeNewMemberJoins,
 	TriggerTypeKeywordsPosted,
+	TriggerTypeMemberLeaves,
+	TriggerTypeChannelHeaderChanged,
+	TriggerTypeFileAttachment,
+	TriggerTypeMessageFromUser,
+	TriggerTypeReactionAdded,
+}
+
+// IsValidChannelActionCombination returns whether actionType can be attached to triggerType.
+// The run actions need a post for the timeline, so they can't follow membership triggers.
+func IsValidChannelActionCombination(triggerType TriggerType, actionType ActionType) bool {
+	switch triggerType {
+	case TriggerTypeNewMemberJoins:
+		return actionType == ActionTypeWelcomeMessage || actionType == ActionTypeCategorizeChannel ||
+			actionType == ActionTypeStartRun || actionType == ActionTypeSetRunProperty
+	case TriggerTypeMemberLeaves:
+		return actionType == ActionTypeStartRun || actionType == ActionTypeSetRunProperty
+	case TriggerTypeKeywordsPosted:
+		return actionType == ActionTypePromptRunPlaybook || isRunChannelAction(actionType)
+	case TriggerTypeChannelHeaderChanged, TriggerTypeFileAttachment, TriggerTypeMessageFromUser, TriggerTypeReactionAdded:
+		return isRunChannelAction(actionType)
+	default:
+		return false
+	}
+}
+
+func isRunChannelAction(actionType ActionType) bool {
+	return actionType == ActionTypeStartRun || actionType == ActionTypeAddToRunTimeline || actionType == ActionTypeSetRunProperty
 }
 
 type GetChannelActionOptions struct {
@@ -62,7 +155,42 @@ type GetChannelActionOptions struct {
 	TriggerType TriggerType
 }
 
+// ChannelActionRunService is the part of the playbook run service the run actions rely on. The
+// playbook run service depends on the channel action service, so it is provided after both are
+// created through ChannelActionService.SetRunService.
+type ChannelActionRunService interface {
+	// StartRunFromChannelAction creates a run of playbookID reported by userID, linked to post if
+	// not nil. propertyValues are the text values of playbook property fields, keyed by field ID.
+	StartRunFromChannelAction(playbookID, channelID, userID string, post *model.Post, propertyValues map[string]string) (*PlaybookRun, error)
+
+	// GetInProgressRunsForChannel returns the in-progress runs linked to channelID.
+	GetInProgressRunsForChannel(channelID string) ([]PlaybookRun, error)
+
+	// AddPostToTimeline adds an event based on post to the run's timeline.
+	AddPostToTimeline(playbookRun *PlaybookRun, userID string, post *model.Post, summary string) error
+
+	// SetRunPropertyValue sets a property value on a run.
+	SetRunPropertyValue(userID, playbookRunID, propertyFieldID string, value json.RawMessage) (*PropertyValue, error)
+}
+
+// ChannelActionPermissions are the permission checks the run actions make for the user whose
+// message or membership change triggered them. The permissions service depends on the playbook
+// run service, so it is provided through ChannelActionService.SetPermissions.
+type ChannelActionPermissions interface {
+	// RunCreate checks whether userID can run playbook in targetTeamID.
+	RunCreate(userID string, playbook Playbook, targetTeamID string) error
+
+	// RunSetPropertyValues checks whether userID can set the property values of runID.
+	RunSetPropertyValues(userID, runID string) error
+}
+
 type ChannelActionService interface {
+	// SetRunService provides the run service used by the run actions.
+	SetRunService(runService ChannelActionRunService)
+
+	// SetPermissions provides the permission checks made by the run actions.
+	SetPermissions(permissions ChannelActionPermissions)
+
 	// Create creates a new action
 	Create(action GenericChannelAction) (string, error)
 
@@ -80,11 +208,19 @@ type ChannelActionService interface {
 	// was invited by actorID.
 	UserHasJoinedChannel(userID, channelID, actorID string)
 
+	// UserHasLeftChannel is called when userID has left channelID. If actorID is not blank, userID
+	// was removed by actorID.
+	UserHasLeftChannel(userID, channelID, actorID string)
+
+	// ReactionHasBeenAdded runs the actions triggered by reaction.
+	ReactionHasBeenAdded(reaction *model.Reaction)
+
 	// CheckAndSendMessageOnJoin checks if userID has viewed channelID and sends
 	// the registered welcome message action. Returns true if the message was sent.
 	CheckAndSendMessageOnJoin(userID, channelID string) bool
 
-	// MessageHasBeenPosted suggests playbooks to the user if triggered
+	// MessageHasBeenPosted suggests playbooks to the user if triggered, and runs the actions
+	// triggered by post.
 	MessageHasBeenPosted(post *model.Post)
 }
 
@@ -4,7 +4,9 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +33,9 @@ type channelActionServiceImpl struct {
 	api                   *pluginapi.Client
 	playbookGetter        PlaybookGetter
 	keywordsThreadIgnorer KeywordsThreadIgnorer
+	runService            ChannelActionRunService
+	permissions           ChannelActionPermissions
+	runStartCooldown      runStartCooldown
 }
 
 func NewChannelActionsService(api *pluginapi.Client, poster bot.Poster, configService config.Service, store ChannelActionStore, playbookGetter PlaybookGetter, keywordsThreadIgnorer KeywordsThreadIgnorer) ChannelActionService {
@@ -41,9 +46,22 @@ func NewChannelActionsService(api *pluginapi.Client, poster bot.Poster, configSe
 		api:                   api,
 		playbookGetter:        playbookGetter,
 		keywordsThreadIgnorer: keywordsThreadIgnorer,
+		runStartCooldown:      &kvRunStartCooldown{api: api},
 	}
 }
 
+// SetRunService provides the run service used by the run actions. Until it is set, the run
+// actions are ignored.
+func (a *channelActionServiceImpl) SetRunService(runService ChannelActionRunService) {
+	a.runService = runService
+}
+
+// SetPermissions provides the permission checks made by the run actions. Until it is set, the
+// run actions are ignored.
+func (a *channelActionServiceImpl) SetPermissions(permissions ChannelActionPermissions) {
+	a.permissions = permissions
+}
+
 // setViewedChannelForEveryMember mark channelID as viewed for all its existing members
 func (a *channelActionServiceImpl) setViewedChannelForEveryMember(channelID string) error {
 	// TODO: this is a magic number, we should load test this function to find a
@@ -156,6 +174,8 @@ func (a *channelActionServiceImpl) UserHasJoinedChannel(userID, channelID, actor
 		return
 	}
 
+	a.runTriggeredActions(channelActionEvent{channelID: channelID, userID: userID}, TriggerTypeNewMemberJoins)
+
 	actions, err := a.GetChannelActions(channelID, GetChannelActionOptions{
 		ActionType:  ActionTypeCategorizeChannel,
 		TriggerType: TriggerTypeNewMemberJoins,
@@ -209,6 +229,38 @@ func (a *channelActionServiceImpl) UserHasJoinedChannel(userID, channelID, actor
 	}
 }
 
+// UserHasLeftChannel is called when userID has left channelID. If actorID is not blank, userID
+// was removed by actorID.
+func (a *channelActionServiceImpl) UserHasLeftChannel(userID, channelID, actorID string) {
+	user, err := a.api.User.Get(userID)
+	if err != nil {
+		logrus.WithError(err).WithField("user_id", userID).Error("failed to resolve user")
+		return
+	}
+
+	if user.IsBot {
+		return
+	}
+
+	a.runTriggeredActions(channelActionEvent{channelID: channelID, userID: userID}, TriggerTypeMemberLeaves)
+}
+
+// ReactionHasBeenAdded runs the actions triggered by reaction, acting on the post it was added to.
+func (a *channelActionServiceImpl) ReactionHasBeenAdded(reaction *model.Reaction) {
+	post, err := a.api.Post.GetPost(reaction.PostId)
+	if err != nil {
+		logrus.WithError(err).WithField("post_id", reaction.PostId).Error("failed to resolve reacted post")
+		return
+	}
+
+	a.runTriggeredActions(channelActionEvent{
+		channelID: post.ChannelId,
+		userID:    reaction.UserId,
+		post:      post,
+		emojiName: reaction.EmojiName,
+	}, TriggerTypeReactionAdded)
+}
+
 // createOrUpdatePlaybookRunSidebarCategory creates or updates a "Playbook Runs" sidebar category if
 // it does not already exist and adds the channel within the sidebar category
 func (a *channelActionServiceImpl) createOrUpdatePlaybookRunSidebarCategory(userID, channelID, teamID, categoryName string) error {
@@ -320,6 +372,10 @@ func (a *channelActionServiceImpl) CheckAndSendMessageOnJoin(userID, channelID s
 }
 
 func (a *channelActionServiceImpl) MessageHasBeenPosted(post *model.Post) {
+	if !a.poster.IsFromPoster(post) {
+		a.runPostTriggeredActions(post)
+	}
+
 	if post.IsSystemMessage() || a.keywordsThreadIgnorer.IsIgnored(post.RootId, post.UserId) || a.poster.IsFromPoster(post) {
 		return
 	}
@@ -362,6 +418,21 @@ func (a *channelActionServiceImpl) MessageHasBeenPosted(post *model.Post) {
 			continue
 		}
 
+		if payload.AutoStart {
+			filter := ChannelActionTriggerFilter{Keywords: payload.Keywords}
+			event := channelActionEvent{channelID: post.ChannelId, userID: post.UserId, post: post}
+			if filter.matches(TriggerTypeKeywordsPosted, event) {
+				if err := a.autoStartRun(payload.PlaybookID, payload.RunAutoStartOptions, TriggerTypeKeywordsPosted, event); err != nil {
+					logrus.WithError(err).WithFields(logrus.Fields{
+						"channel_id":  post.ChannelId,
+						"action_id":   action.ID,
+						"playbook_id": payload.PlaybookID,
+					}).Error("unable to start run from keywords")
+				}
+			}
+			continue
+		}
+
 		suggestedPlaybook, err := a.playbookGetter.Get(payload.PlaybookID)
 		if err != nil {
 			logrus.WithError(err).WithField("playbook_id", payload.PlaybookID).Error("unable to get playbook to run action")
@@ -505,3 +576,319 @@ func containsAttachments(attachments []*model.MessageAttachment, trigger string)
 	}
 	return false
 }
+
+// channelActionEvent describes what fired the triggers of the run actions. post is nil for the
+// membership triggers.
+type channelActionEvent struct {
+	channelID string
+	userID    string
+	post      *model.Post
+	emojiName string
+}
+
+// runPostTriggeredActions runs the run actions whose triggers match post.
+func (a *channelActionServiceImpl) runPostTriggeredActions(post *model.Post) {
+	event := channelActionEvent{channelID: post.ChannelId, userID: post.UserId, post: post}
+
+	if post.Type == model.PostTypeHeaderChange {
+		a.runTriggeredActions(event, TriggerTypeChannelHeaderChanged)
+		return
+	}
+	if post.IsSystemMessage() {
+		return
+	}
+
+	triggerTypes := []TriggerType{TriggerTypeMessageFromUser}
+	if !a.keywordsThreadIgnorer.IsIgnored(post.RootId, post.UserId) {
+		triggerTypes = append(triggerTypes, TriggerTypeKeywordsPosted)
+	}
+	if len(post.FileIds) > 0 {
+		triggerTypes = append(triggerTypes, TriggerTypeFileAttachment)
+	}
+	a.runTriggeredActions(event, triggerTypes...)
+}
+
+// runTriggeredActions runs the enabled run actions of the event's channel that have one of
+// triggerTypes and whose trigger filter matches the event.
+func (a *channelActionServiceImpl) runTriggeredActions(event channelActionEvent, triggerTypes ...TriggerType) {
+	if a.runService == nil || a.permissions == nil {
+		return
+	}
+
+	actions, err := a.GetChannelActions(event.channelID, GetChannelActionOptions{})
+	if err != nil {
+		logrus.WithError(err).WithField("channel_id", event.channelID).Error("unable to retrieve channel actions")
+		return
+	}
+
+	for _, action := range actions {
+		if !action.Enabled || !isRunChannelAction(action.ActionType) || !containsTriggerType(triggerTypes, action.TriggerType) {
+			continue
+		}
+
+		logger := logrus.WithFields(logrus.Fields{
+			"channel_id":   event.channelID,
+			"action_id":    action.ID,
+			"action_type":  action.ActionType,
+			"trigger_type": action.TriggerType,
+		})
+
+		payload, err := DecodeChannelActionPayload(action.ActionType, action.Payload)
+		if err != nil {
+			logger.WithError(err).Error("unable to decode payload from action")
+			continue
+		}
+
+		if !channelActionTriggerFilterOf(payload).matches(action.TriggerType, event) {
+			continue
+		}
+
+		if err := a.runChannelAction(action, payload, event); err != nil {
+			logger.WithError(err).Error("unable to run channel action")
+		}
+	}
+}
+
+// runChannelAction performs a run action. Actions on existing runs apply to every in-progress
+// run linked to the channel.
+func (a *channelActionServiceImpl) runChannelAction(action GenericChannelAction, payload interface{}, event channelActionEvent) error {
+	switch typed := payload.(type) {
+	case StartRunPayload:
+		return a.autoStartRun(typed.PlaybookID, typed.RunAutoStartOptions, action.TriggerType, event)
+
+	case AddToRunTimelinePayload:
+		if event.post == nil {
+			return nil
+		}
+		runs, err := a.runService.GetInProgressRunsForChannel(event.channelID)
+		if err != nil {
+			return err
+		}
+		summary := typed.Summary
+		if summary == "" {
+			summary = defaultChannelActionTimelineSummary(action.TriggerType, event)
+		}
+		for i := range runs {
+			if err := a.runService.AddPostToTimeline(&runs[i], event.userID, event.post, summary); err != nil {
+				return errors.Wrapf(err, "failed to add post to the timeline of run %s", runs[i].ID)
+			}
+		}
+		return nil
+
+	case SetRunPropertyPayload:
+		value, err := json.Marshal(typed.Value)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal property value")
+		}
+		runs, err := a.runService.GetInProgressRunsForChannel(event.channelID)
+		if err != nil {
+			return err
+		}
+		for _, run := range runs {
+			fieldID := runPropertyFieldID(run, typed.FieldID)
+			if fieldID == "" {
+				continue
+			}
+			if err := a.permissions.RunSetPropertyValues(event.userID, run.ID); err != nil {
+				logrus.WithError(err).WithFields(logrus.Fields{"playbook_run_id": run.ID, "user_id": event.userID}).Warn("skipping property value the user may not set")
+				continue
+			}
+			if _, err := a.runService.SetRunPropertyValue(event.userID, run.ID, fieldID, value); err != nil {
+				return errors.Wrapf(err, "failed to set property value of run %s", run.ID)
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("action type %q is not a run action", action.ActionType)
+	}
+}
+
+// membershipRunStartCooldown is the least cooldown of runs started by members joining or leaving a
+// channel, so that adding or removing many users at once starts a single run.
+const membershipRunStartCooldown = time.Minute
+
+// autoStartRun starts a run of playbookID from the event, unless the user who triggered it may not
+// run the playbook or the cooldown of options has not elapsed since the last run of the playbook
+// started from the channel.
+func (a *channelActionServiceImpl) autoStartRun(playbookID string, options RunAutoStartOptions, triggerType TriggerType, event channelActionEvent) error {
+	if a.runService == nil || a.permissions == nil {
+		return errors.New("run service is not available")
+	}
+
+	playbook, err := a.playbookGetter.Get(playbookID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get playbook %s", playbookID)
+	}
+	if err = a.permissions.RunCreate(event.userID, playbook, playbook.TeamID); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"channel_id":  event.channelID,
+			"playbook_id": playbookID,
+			"user_id":     event.userID,
+		}).Warn("skipping run start the user may not create")
+		return nil
+	}
+
+	cooldown := time.Duration(options.CooldownSeconds) * time.Second
+	if (triggerType == TriggerTypeNewMemberJoins || triggerType == TriggerTypeMemberLeaves) && cooldown < membershipRunStartCooldown {
+		cooldown = membershipRunStartCooldown
+	}
+
+	cooldownKey := ""
+	if cooldown > 0 {
+		cooldownKey = runStartCooldownKey(event.channelID, playbookID)
+		started, err := a.runStartCooldown.start(cooldownKey, cooldown)
+		if err != nil {
+			return errors.Wrap(err, "failed to start run cooldown")
+		}
+		if !started {
+			logrus.WithFields(logrus.Fields{
+				"channel_id":  event.channelID,
+				"playbook_id": playbookID,
+			}).Debug("skipping run start during cooldown")
+			return nil
+		}
+	}
+
+	_, err = a.runService.StartRunFromChannelAction(playbookID, event.channelID, event.userID, event.post, extractPropertyValues(options.PropertyExtractors, event.post))
+	if err != nil && cooldownKey != "" {
+		// Let the next trigger retry rather than waiting for the cooldown of a run that doesn't exist.
+		if cancelErr := a.runStartCooldown.cancel(cooldownKey); cancelErr != nil {
+			logrus.WithError(cancelErr).WithField("key", cooldownKey).Warn("failed to cancel run cooldown")
+		}
+	}
+	return err
+}
+
+// extractPropertyValues returns the values that extractors find in post, keyed by field ID.
+func extractPropertyValues(extractors []PropertyExtractor, post *model.Post) map[string]string {
+	if post == nil || len(extractors) == 0 {
+		return nil
+	}
+
+	texts := []string{post.Message}
+	for _, attachment := range post.Attachments() {
+		texts = append(texts, attachment.Pretext, attachment.Title, attachment.Text, attachment.Footer)
+	}
+
+	values := make(map[string]string, len(extractors))
+	for _, extractor := range extractors {
+		pattern, err := regexp.Compile(extractor.Pattern)
+		if err != nil {
+			logrus.WithError(err).WithField("field_id", extractor.FieldID).Warn("invalid property extractor pattern")
+			continue
+		}
+		for _, text := range texts {
+			match := pattern.FindStringSubmatch(text)
+			if match == nil {
+				continue
+			}
+			value := match[0]
+			if len(match) > 1 {
+				value = match[1]
+			}
+			values[extractor.FieldID] = value
+			break
+		}
+	}
+	return values
+}
+
+// runStartCooldown keeps channel actions from starting runs too often.
+type runStartCooldown interface {
+	// start reserves key for window and returns whether it wasn't already reserved.
+	start(key string, window time.Duration) (bool, error)
+	// cancel releases key.
+	cancel(key string) error
+}
+
+// kvRunStartCooldown keeps the cooldowns in the KV store so that they hold across the cluster.
+type kvRunStartCooldown struct {
+	api *pluginapi.Client
+}
+
+func (c *kvRunStartCooldown) start(key string, window time.Duration) (bool, error) {
+	if window < time.Second {
+		window = time.Second
+	}
+	return c.api.KV.Set(key, []byte{1}, pluginapi.SetAtomic(nil), pluginapi.SetExpiry(window))
+}
+
+func (c *kvRunStartCooldown) cancel(key string) error {
+	return c.api.KV.Delete(key)
+}
+
+func runStartCooldownKey(channelID, playbookID string) string {
+	return fmt.Sprintf("channel_action_run_cooldown_%s_%s", channelID, playbookID)
+}
+
+// runPropertyFieldID returns the ID of the run's field that is fieldID or was copied from it,
+// or "" if the run has no such field.
+func runPropertyFieldID(run PlaybookRun, fieldID string) string {
+	for _, field := range run.PropertyFields {
+		if field.ID == fieldID || field.Attrs.ParentID == fieldID {
+			return field.ID
+		}
+	}
+	return ""
+}
+
+func defaultChannelActionTimelineSummary(triggerType TriggerType, event channelActionEvent) string {
+	switch triggerType {
+	case TriggerTypeKeywordsPosted:
+		return "Keywords posted"
+	case TriggerTypeChannelHeaderChanged:
+		return "Channel header changed"
+	case TriggerTypeFileAttachment:
+		return "File attached"
+	case TriggerTypeReactionAdded:
+		return fmt.Sprintf("Reaction :%s: added", event.emojiName)
+	default:
+		return "Message posted"
+	}
+}
+
+func channelActionTriggerFilterOf(payload interface{}) ChannelActionTriggerFilter {
+	switch typed := payload.(type) {
+	case StartRunPayload:
+		return typed.ChannelActionTriggerFilter
+	case AddToRunTimelinePayload:
+		return typed.ChannelActionTriggerFilter
+	case SetRunPropertyPayload:
+		return typed.ChannelActionTriggerFilter
+	default:
+		return ChannelActionTriggerFilter{}
+	}
+}
+
+// matches returns whether the event passes the filter of an action with triggerType. An empty
+// list of emoji names matches every reaction.
+func (f ChannelActionTriggerFilter) matches(triggerType TriggerType, event channelActionEvent) bool {
+	switch triggerType {
+	case TriggerTypeKeywordsPosted:
+		if event.post == nil {
+			return false
+		}
+		for _, keyword := range f.Keywords {
+			if keyword != "" && (strings.Contains(event.post.Message, keyword) || containsAttachments(event.post.Attachments(), keyword)) {
+				return true
+			}
+		}
+		return false
+	case TriggerTypeMessageFromUser:
+		return sliceContains(f.UserIDs, event.userID)
+	case TriggerTypeReactionAdded:
+		return len(f.EmojiNames) == 0 || sliceContains(f.EmojiNames, event.emojiName)
+	default:
+		return true
+	}
+}
+
+func containsTriggerType(triggerTypes []TriggerType, target TriggerType) bool {
+	for _, triggerType := range triggerTypes {
+		if triggerType == target {
+			return true
+		}
+	}
+	return false
+}
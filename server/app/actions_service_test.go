@@ -0,0 +1,377 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock_bot "github.com/mattermost/mattermost-plugin-playbooks/server/bot/mocks"
+)
+
+type stubChannelActionStore struct {
+	ChannelActionStore
+	actions []GenericChannelAction
+}
+
+func (s *stubChannelActionStore) GetChannelActions(channelID string, options GetChannelActionOptions) ([]GenericChannelAction, error) {
+	var actions []GenericChannelAction
+	for _, action := range s.actions {
+		if action.ChannelID != channelID {
+			continue
+		}
+		if options.TriggerType != "" && action.TriggerType != options.TriggerType {
+			continue
+		}
+		if options.ActionType != "" && action.ActionType != options.ActionType {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+type setPropertyCall struct {
+	userID, runID, fieldID string
+	value                  string
+}
+
+type stubChannelActionRunService struct {
+	runs          []PlaybookRun
+	startedRuns   []string
+	startedPosts  []*model.Post
+	startedValues []map[string]string
+	startErr      error
+	timelinePosts map[string]string
+	setCalls      []setPropertyCall
+}
+
+func (s *stubChannelActionRunService) StartRunFromChannelAction(playbookID, channelID, userID string, post *model.Post, propertyValues map[string]string) (*PlaybookRun, error) {
+	if s.startErr != nil {
+		return nil, s.startErr
+	}
+	s.startedRuns = append(s.startedRuns, playbookID)
+	s.startedPosts = append(s.startedPosts, post)
+	s.startedValues = append(s.startedValues, propertyValues)
+	return &PlaybookRun{PlaybookID: playbookID, ChannelID: channelID}, nil
+}
+
+func (s *stubChannelActionRunService) GetInProgressRunsForChannel(channelID string) ([]PlaybookRun, error) {
+	return s.runs, nil
+}
+
+func (s *stubChannelActionRunService) AddPostToTimeline(playbookRun *PlaybookRun, userID string, post *model.Post, summary string) error {
+	if s.timelinePosts == nil {
+		s.timelinePosts = map[string]string{}
+	}
+	s.timelinePosts[playbookRun.ID] = summary
+	return nil
+}
+
+func (s *stubChannelActionRunService) SetRunPropertyValue(userID, playbookRunID, propertyFieldID string, value json.RawMessage) (*PropertyValue, error) {
+	s.setCalls = append(s.setCalls, setPropertyCall{userID: userID, runID: playbookRunID, fieldID: propertyFieldID, value: string(value)})
+	return &PropertyValue{}, nil
+}
+
+type stubPlaybookGetter struct{}
+
+func (stubPlaybookGetter) Get(id string) (Playbook, error) {
+	return Playbook{ID: id, TeamID: "team1"}, nil
+}
+
+// stubChannelActionPermissions denies everything to the users in denied.
+type stubChannelActionPermissions struct {
+	denied map[string]bool
+}
+
+func (p stubChannelActionPermissions) RunCreate(userID string, playbook Playbook, targetTeamID string) error {
+	if p.denied[userID] {
+		return ErrNoPermissions
+	}
+	return nil
+}
+
+func (p stubChannelActionPermissions) RunSetPropertyValues(userID, runID string) error {
+	if p.denied[userID] {
+		return ErrNoPermissions
+	}
+	return nil
+}
+
+type stubRunStartCooldown struct {
+	reserved map[string]time.Duration
+}
+
+func (c *stubRunStartCooldown) start(key string, window time.Duration) (bool, error) {
+	if _, ok := c.reserved[key]; ok {
+		return false, nil
+	}
+	c.reserved[key] = window
+	return true, nil
+}
+
+func (c *stubRunStartCooldown) cancel(key string) error {
+	delete(c.reserved, key)
+	return nil
+}
+
+func TestIsValidChannelActionCombination(t *testing.T) {
+	assert.True(t, IsValidChannelActionCombination(TriggerTypeNewMemberJoins, ActionTypeWelcomeMessage))
+	assert.True(t, IsValidChannelActionCombination(TriggerTypeKeywordsPosted, ActionTypePromptRunPlaybook))
+	assert.True(t, IsValidChannelActionCombination(TriggerTypeKeywordsPosted, ActionTypeStartRun))
+	assert.True(t, IsValidChannelActionCombination(TriggerTypeReactionAdded, ActionTypeAddToRunTimeline))
+	assert.True(t, IsValidChannelActionCombination(TriggerTypeMemberLeaves, ActionTypeSetRunProperty))
+
+	assert.False(t, IsValidChannelActionCombination(TriggerTypeMemberLeaves, ActionTypeAddToRunTimeline))
+	assert.False(t, IsValidChannelActionCombination(TriggerTypeFileAttachment, ActionTypePromptRunPlaybook))
+	assert.False(t, IsValidChannelActionCombination(TriggerTypeChannelHeaderChanged, ActionTypeWelcomeMessage))
+	assert.False(t, IsValidChannelActionCombination("unknown", ActionTypeStartRun))
+}
+
+func TestChannelActionRunActions(t *testing.T) {
+	channelID := model.NewId()
+	playbookID := model.NewId()
+	botUserID := model.NewId()
+	alertUserID := model.NewId()
+	guestUserID := model.NewId()
+
+	action := func(triggerType TriggerType, actionType ActionType, payload interface{}) GenericChannelAction {
+		return GenericChannelAction{
+			GenericChannelActionWithoutPayload: GenericChannelActionWithoutPayload{
+				ID:          model.NewId(),
+				ChannelID:   channelID,
+				Enabled:     true,
+				ActionType:  actionType,
+				TriggerType: triggerType,
+			},
+			Payload: payload,
+		}
+	}
+
+	setup := func(actions ...GenericChannelAction) (*channelActionServiceImpl, *stubChannelActionRunService) {
+		runService := &stubChannelActionRunService{
+			runs: []PlaybookRun{{
+				ID: "run1",
+				PropertyFields: []PropertyField{{
+					PropertyField: model.PropertyField{ID: "run_field"},
+					Attrs:         Attrs{ParentID: "playbook_field"},
+				}},
+			}},
+		}
+		poster := mock_bot.NewMockPoster(gomock.NewController(t))
+		poster.EXPECT().IsFromPoster(gomock.Any()).DoAndReturn(func(post *model.Post) bool {
+			return post.UserId == botUserID
+		}).AnyTimes()
+		service := &channelActionServiceImpl{
+			store:                 &stubChannelActionStore{actions: actions},
+			poster:                poster,
+			playbookGetter:        stubPlaybookGetter{},
+			keywordsThreadIgnorer: NewKeywordsThreadIgnorer(),
+			runStartCooldown:      &stubRunStartCooldown{reserved: map[string]time.Duration{}},
+		}
+		service.SetRunService(runService)
+		service.SetPermissions(stubChannelActionPermissions{denied: map[string]bool{guestUserID: true}})
+		return service, runService
+	}
+
+	t.Run("message from user starts a run linked to the post", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeMessageFromUser, ActionTypeStartRun, StartRunPayload{
+			ChannelActionTriggerFilter: ChannelActionTriggerFilter{UserIDs: []string{alertUserID}},
+			PlaybookID:                 playbookID,
+		}))
+
+		service.runPostTriggeredActions(&model.Post{Id: "other", ChannelId: channelID, UserId: model.NewId(), Message: "hello"})
+		assert.Empty(t, runService.startedRuns)
+
+		post := &model.Post{Id: "alert", ChannelId: channelID, UserId: alertUserID, Message: "disk full"}
+		service.runPostTriggeredActions(post)
+		assert.Equal(t, []string{playbookID}, runService.startedRuns)
+		assert.Same(t, post, runService.startedPosts[0])
+	})
+
+	t.Run("keywords start a run from a map payload", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeKeywordsPosted, ActionTypeStartRun, map[string]interface{}{
+			"keywords":    []interface{}{"outage"},
+			"playbook_id": playbookID,
+		}))
+
+		service.runPostTriggeredActions(&model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID, Message: "all good"})
+		service.runPostTriggeredActions(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, Message: "major outage"})
+		assert.Equal(t, []string{playbookID}, runService.startedRuns)
+	})
+
+	t.Run("file attachment is added to the timeline", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeFileAttachment, ActionTypeAddToRunTimeline, AddToRunTimelinePayload{}))
+
+		service.runPostTriggeredActions(&model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID, Message: "no file"})
+		assert.Empty(t, runService.timelinePosts)
+
+		service.runPostTriggeredActions(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, FileIds: []string{model.NewId()}})
+		assert.Equal(t, map[string]string{"run1": "File attached"}, runService.timelinePosts)
+	})
+
+	t.Run("header change sets a run property through its playbook field", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeChannelHeaderChanged, ActionTypeSetRunProperty, SetRunPropertyPayload{
+			FieldID: "playbook_field",
+			Value:   "changed",
+		}))
+
+		service.runPostTriggeredActions(&model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID, Type: model.PostTypeJoinChannel})
+		assert.Empty(t, runService.setCalls)
+
+		service.runPostTriggeredActions(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, Type: model.PostTypeHeaderChange})
+		require.Len(t, runService.setCalls, 1)
+		assert.Equal(t, setPropertyCall{userID: alertUserID, runID: "run1", fieldID: "run_field", value: `"changed"`}, runService.setCalls[0])
+	})
+
+	t.Run("posts from the bot and disabled actions are ignored", func(t *testing.T) {
+		disabled := action(TriggerTypeMessageFromUser, ActionTypeStartRun, StartRunPayload{
+			ChannelActionTriggerFilter: ChannelActionTriggerFilter{UserIDs: []string{alertUserID}},
+			PlaybookID:                 playbookID,
+		})
+		disabled.Enabled = false
+		service, runService := setup(disabled, action(TriggerTypeMessageFromUser, ActionTypeAddToRunTimeline, AddToRunTimelinePayload{
+			ChannelActionTriggerFilter: ChannelActionTriggerFilter{UserIDs: []string{botUserID}},
+		}))
+
+		service.MessageHasBeenPosted(&model.Post{Id: "p1", ChannelId: channelID, UserId: botUserID, Message: "status"})
+		service.runPostTriggeredActions(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, Message: "alert"})
+		assert.Empty(t, runService.startedRuns)
+		assert.Empty(t, runService.timelinePosts)
+	})
+
+	t.Run("reaction filter on emoji names", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeReactionAdded, ActionTypeAddToRunTimeline, AddToRunTimelinePayload{
+			ChannelActionTriggerFilter: ChannelActionTriggerFilter{EmojiNames: []string{"fire"}},
+		}))
+		post := &model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID}
+
+		service.runTriggeredActions(channelActionEvent{channelID: channelID, userID: alertUserID, post: post, emojiName: "smile"}, TriggerTypeReactionAdded)
+		assert.Empty(t, runService.timelinePosts)
+
+		service.runTriggeredActions(channelActionEvent{channelID: channelID, userID: alertUserID, post: post, emojiName: "fire"}, TriggerTypeReactionAdded)
+		assert.Equal(t, map[string]string{"run1": "Reaction :fire: added"}, runService.timelinePosts)
+	})
+	t.Run("keywords auto-start a run once per cooldown", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeKeywordsPosted, ActionTypePromptRunPlaybook, PromptRunPlaybookFromKeywordsPayload{
+			Keywords:            []string{"FIRING"},
+			PlaybookID:          playbookID,
+			AutoStart:           true,
+			RunAutoStartOptions: RunAutoStartOptions{CooldownSeconds: 600},
+		}))
+
+		first := &model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID, Message: "FIRING: disk full"}
+		service.MessageHasBeenPosted(first)
+		service.MessageHasBeenPosted(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, Message: "FIRING: disk full"})
+		assert.Equal(t, []string{playbookID}, runService.startedRuns)
+		assert.Same(t, first, runService.startedPosts[0])
+	})
+
+	t.Run("a failed start does not hold the cooldown", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeKeywordsPosted, ActionTypeStartRun, StartRunPayload{
+			ChannelActionTriggerFilter: ChannelActionTriggerFilter{Keywords: []string{"FIRING"}},
+			PlaybookID:                 playbookID,
+			RunAutoStartOptions:        RunAutoStartOptions{CooldownSeconds: 600},
+		}))
+
+		runService.startErr = errors.New("playbook is archived")
+		service.runPostTriggeredActions(&model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID, Message: "FIRING"})
+		runService.startErr = nil
+		service.runPostTriggeredActions(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, Message: "FIRING"})
+		service.runPostTriggeredActions(&model.Post{Id: "p3", ChannelId: channelID, UserId: alertUserID, Message: "FIRING"})
+		assert.Equal(t, []string{playbookID}, runService.startedRuns)
+		assert.Equal(t, "p2", runService.startedPosts[0].Id)
+	})
+
+	t.Run("users who may not run the playbook do not start runs", func(t *testing.T) {
+		service, runService := setup(
+			action(TriggerTypeKeywordsPosted, ActionTypeStartRun, StartRunPayload{
+				ChannelActionTriggerFilter: ChannelActionTriggerFilter{Keywords: []string{"FIRING"}},
+				PlaybookID:                 playbookID,
+				RunAutoStartOptions:        RunAutoStartOptions{CooldownSeconds: 600},
+			}),
+			action(TriggerTypeMessageFromUser, ActionTypeSetRunProperty, SetRunPropertyPayload{
+				ChannelActionTriggerFilter: ChannelActionTriggerFilter{UserIDs: []string{guestUserID, alertUserID}},
+				FieldID:                    "playbook_field",
+				Value:                      "changed",
+			}),
+		)
+
+		service.runPostTriggeredActions(&model.Post{Id: "p1", ChannelId: channelID, UserId: guestUserID, Message: "FIRING"})
+		assert.Empty(t, runService.startedRuns)
+		assert.Empty(t, runService.setCalls)
+
+		// The denied start did not hold the cooldown either.
+		service.runPostTriggeredActions(&model.Post{Id: "p2", ChannelId: channelID, UserId: alertUserID, Message: "FIRING"})
+		assert.Equal(t, []string{playbookID}, runService.startedRuns)
+		assert.Len(t, runService.setCalls, 1)
+	})
+
+	t.Run("members joining start a single run", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeNewMemberJoins, ActionTypeStartRun, StartRunPayload{
+			PlaybookID: playbookID,
+		}))
+
+		service.runTriggeredActions(channelActionEvent{channelID: channelID, userID: alertUserID}, TriggerTypeNewMemberJoins)
+		service.runTriggeredActions(channelActionEvent{channelID: channelID, userID: model.NewId()}, TriggerTypeNewMemberJoins)
+		assert.Equal(t, []string{playbookID}, runService.startedRuns)
+		assert.Equal(t, membershipRunStartCooldown, service.runStartCooldown.(*stubRunStartCooldown).reserved[runStartCooldownKey(channelID, playbookID)])
+	})
+
+	t.Run("property values are extracted from the post", func(t *testing.T) {
+		service, runService := setup(action(TriggerTypeMessageFromUser, ActionTypeStartRun, map[string]interface{}{
+			"user_ids":    []interface{}{alertUserID},
+			"playbook_id": playbookID,
+			"property_extractors": []interface{}{
+				map[string]interface{}{"field_id": "severity", "pattern": `severity=(\w+)`},
+				map[string]interface{}{"field_id": "host", "pattern": `host-\d+`},
+				map[string]interface{}{"field_id": "missing", "pattern": `region=(\w+)`},
+			},
+		}))
+
+		post := &model.Post{Id: "p1", ChannelId: channelID, UserId: alertUserID, Message: "severity=high"}
+		model.ParseMessageAttachment(post, []*model.MessageAttachment{{Text: "on host-42"}})
+		service.runPostTriggeredActions(post)
+		require.Len(t, runService.startedValues, 1)
+		assert.Equal(t, map[string]string{"severity": "high", "host": "host-42"}, runService.startedValues[0])
+	})
+}
+
+func TestTextToPropertyValue(t *testing.T) {
+	options := model.PropertyOptions[*model.PluginPropertyOption]{
+		model.NewPluginPropertyOption("opt_high", "High"),
+		model.NewPluginPropertyOption("opt_low", "Low"),
+	}
+	selectField := &PropertyField{PropertyField: model.PropertyField{Type: model.PropertyFieldTypeSelect}, Attrs: Attrs{Options: options}}
+	multiselectField := &PropertyField{PropertyField: model.PropertyField{Type: model.PropertyFieldTypeMultiselect}, Attrs: Attrs{Options: options}}
+
+	value, err := textToPropertyValue(selectField, "high")
+	require.NoError(t, err)
+	assert.JSONEq(t, `"opt_high"`, string(value))
+
+	value, err = textToPropertyValue(multiselectField, "Low, High")
+	require.NoError(t, err)
+	assert.JSONEq(t, `["opt_low", "opt_high"]`, string(value))
+
+	value, err = textToPropertyValue(multiselectField, " Low ,, High, ")
+	require.NoError(t, err)
+	assert.JSONEq(t, `["opt_low", "opt_high"]`, string(value))
+
+	_, err = textToPropertyValue(selectField, "critical")
+	assert.Error(t, err)
+
+	_, err = textToPropertyValue(&PropertyField{PropertyField: model.PropertyField{Type: model.PropertyFieldTypeUser}}, "@alice")
+	assert.Error(t, err)
+
+	value, err = textToPropertyValue(&PropertyField{PropertyField: model.PropertyField{Type: model.PropertyFieldTypeText}}, "disk full")
+	require.NoError(t, err)
+	assert.JSONEq(t, `"disk full"`, string(value))
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+// Types of the operations applied by ApplyChecklistItemOperations.
+const (
+	ChecklistItemOperationSetState    = "set_state"
+	ChecklistItemOperationSetAssignee = "set_assignee"
+	ChecklistItemOperationSetDueDate  = "set_due_date"
+	ChecklistItemOperationMove        = "move"
+)
+
+// Types of the operations applied to many runs at once.
+const (
+	RunOperationChangeOwner     = "change_owner"
+	RunOperationAddParticipants = "add_participants"
+	RunOperationFinish          = "finish"
+)
+
+// MaxBulkOperations is the largest number of operations accepted in a single bulk request.
+const MaxBulkOperations = 200
+
+// ErrInvalidBulkOperation occurs when an operation of a bulk request can't be applied, such as
+// one addressing a checklist item the run doesn't have.
+var ErrInvalidBulkOperation = errors.New("invalid operation")
+
+// ChecklistItemOperation is a change to a checklist item of a run, addressed by the item's ID so it
+// is unaffected by other items being added, removed or moved.
+type ChecklistItemOperation struct {
+	Type   string `json:"type"`
+	ItemID string `json:"item_id"`
+
+	// State is the new state of the item for set_state.
+	State string `json:"state,omitempty"`
+
+	// AssigneeID is the user assigned to the item for set_assignee. Empty unassigns the item.
+	AssigneeID string `json:"assignee_id,omitempty"`
+
+	// DueDate is the new due date of the item for set_due_date, 0 to clear it.
+	DueDate int64 `json:"due_date,omitempty"`
+
+	// ChecklistID is the checklist the item is moved to for move, its current checklist when
+	// empty.
+	ChecklistID string `json:"checklist_id,omitempty"`
+
+	// Position is the index the item is moved to within the destination checklist for move. The
+	// item is moved to the end of the checklist when nil.
+	Position *int `json:"position,omitempty"`
+}
+
+// BulkOperationResult is the outcome of an operation of a bulk request.
+type BulkOperationResult struct {
+	// Index is the position of the operation in the request.
+	Index int `json:"index"`
+
+	// ID is the checklist item or the run the operation addressed.
+	ID string `json:"id"`
+
+	Success bool `json:"success"`
+
+	// Error is why the operation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// BulkOperationError is returned when an all-or-nothing bulk request is rejected because one of
+// its operations is invalid. None of the operations were applied.
+type BulkOperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *BulkOperationError) Error() string {
+	return fmt.Sprintf("operation %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *BulkOperationError) Unwrap() error {
+	return e.Err
+}
+
+// PublicMessage describes the invalid operation for the user.
+func (e *BulkOperationError) PublicMessage() string {
+	return fmt.Sprintf("operation %d: %s", e.Index, invalidBulkOperationMessage(e.Err))
+}
+
+// invalidBulkOperationMessage returns why an operation is invalid, without the generic suffix.
+func invalidBulkOperationMessage(err error) string {
+	return strings.TrimSuffix(err.Error(), ": "+ErrInvalidBulkOperation.Error())
+}
+
+// resolvedChecklistItemOperation holds the indices an operation addresses in the current state of
+// the run's checklists.
+type resolvedChecklistItemOperation struct {
+	checklistIdx     int
+	itemIdx          int
+	destChecklistIdx int
+	destItemIdx      int
+}
+
+// findChecklistItemByID returns the indices of the checklist and the item with the given ID, or
+// false when no checklist has it.
+func findChecklistItemByID(checklists []Checklist, itemID string) (int, int, bool) {
+	for i := range checklists {
+		if j := findItemByID(checklists[i].Items, itemID); j >= 0 {
+			return i, j, true
+		}
+	}
+
+	return -1, -1, false
+}
+
+// resolveChecklistItemOperation checks op against the run's checklists and returns the indices it
+// addresses.
+func resolveChecklistItemOperation(checklists []Checklist, op ChecklistItemOperation) (resolvedChecklistItemOperation, error) {
+	checklistIdx, itemIdx, found := findChecklistItemByID(checklists, op.ItemID)
+	if !found {
+		return resolvedChecklistItemOperation{}, errors.Wrapf(ErrInvalidBulkOperation, "checklist item %q not found", op.ItemID)
+	}
+	resolved := resolvedChecklistItemOperation{checklistIdx: checklistIdx, itemIdx: itemIdx}
+
+	switch op.Type {
+	case ChecklistItemOperationSetState:
+		if !IsValidChecklistItemState(op.State) {
+			return resolved, errors.Wrapf(ErrInvalidBulkOperation, "invalid state %q", op.State)
+		}
+	case ChecklistItemOperationSetAssignee:
+		if op.AssigneeID != "" && !model.IsValidId(op.AssigneeID) {
+			return resolved, errors.Wrapf(ErrInvalidBulkOperation, "invalid assignee_id %q", op.AssigneeID)
+		}
+	case ChecklistItemOperationSetDueDate:
+		if op.DueDate < 0 {
+			return resolved, errors.Wrap(ErrInvalidBulkOperation, "invalid due_date")
+		}
+	case ChecklistItemOperationMove:
+		resolved.destChecklistIdx = checklistIdx
+		if op.ChecklistID != "" {
+			resolved.destChecklistIdx = findChecklistByID(checklists, op.ChecklistID)
+			if resolved.destChecklistIdx < 0 {
+				return resolved, errors.Wrapf(ErrInvalidBulkOperation, "checklist %q not found", op.ChecklistID)
+			}
+		}
+
+		// The item leaves its checklist before being inserted, so moving it within its own
+		// checklist leaves one position less.
+		lastPosition := len(checklists[resolved.destChecklistIdx].Items)
+		if resolved.destChecklistIdx == checklistIdx {
+			lastPosition--
+		}
+		resolved.destItemIdx = lastPosition
+		if op.Position != nil {
+			if *op.Position < 0 || *op.Position > lastPosition {
+				return resolved, errors.Wrapf(ErrInvalidBulkOperation, "invalid position %d", *op.Position)
+			}
+			resolved.destItemIdx = *op.Position
+		}
+	default:
+		return resolved, errors.Wrapf(ErrInvalidBulkOperation, "unknown operation type %q", op.Type)
+	}
+
+	return resolved, nil
+}
+
+// maxBulkOperationAttempts is how many times atomic operations are applied to a run that keeps
+// being modified concurrently before the request fails.
+const maxBulkOperationAttempts = 3
+
+// ApplyChecklistItemOperations applies ops in order to the checklist items of a run, with the same
+// effects as applying them one at a time. Each operation addresses its item as the run stands once
+// the previous operations are applied. When atomic is true, the operations are applied to the run
+// together and saved in a single update: none is applied if any is invalid, returning a
+// *BulkOperationError, or if the run keeps being modified concurrently, returning
+// ErrStaleVersion. Otherwise, every operation is attempted and the results tell which ones failed.
+func (s *PlaybookRunServiceImpl) ApplyChecklistItemOperations(playbookRunID, userID string, ops []ChecklistItemOperation, atomic bool) ([]BulkOperationResult, error) {
+	results := make([]BulkOperationResult, 0, len(ops))
+	if atomic {
+		if err := s.applyChecklistItemOperationsAtomically(playbookRunID, userID, ops); err != nil {
+			return nil, err
+		}
+		for i, op := range ops {
+			results = append(results, BulkOperationResult{Index: i, ID: op.ItemID, Success: true})
+		}
+		return results, nil
+	}
+
+	logger := logrus.WithFields(logrus.Fields{"playbook_run_id": playbookRunID, "user_id": userID})
+	for i, op := range ops {
+		result := BulkOperationResult{Index: i, ID: op.ItemID}
+		if err := s.applyChecklistItemOperation(playbookRunID, userID, op); err != nil {
+			if errors.Is(err, ErrInvalidBulkOperation) {
+				result.Error = invalidBulkOperationMessage(err)
+			} else {
+				logger.WithError(err).WithField("item_id", op.ItemID).Warn("failed to apply checklist item operation")
+				result.Error = "failed to apply operation"
+			}
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// applyChecklistItemOperationsAtomically applies ops to the run in a single update, applying
+// them again to the latest version of the run when it was modified since it was read.
+func (s *PlaybookRunServiceImpl) applyChecklistItemOperationsAtomically(playbookRunID, userID string, ops []ChecklistItemOperation) error {
+	auditRec := plugin.MakeAuditRecord("applyChecklistItemOperations", model.AuditStatusFail)
+	defer s.api.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookRunID", playbookRunID)
+	model.AddEventParameterToAuditRec(auditRec, "operationsCount", len(ops))
+
+	for attempt := 1; ; attempt++ {
+		playbookRun, err := s.applyChecklistItemOperationsOnce(playbookRunID, userID, ops)
+		if err == nil {
+			auditRec.Success()
+			auditRec.AddEventResultState(*playbookRun)
+			return nil
+		}
+		if !errors.Is(err, ErrStaleVersion) || attempt == maxBulkOperationAttempts {
+			return err
+		}
+	}
+}
+
+// checklistItemAssignment is an item assigned by an atomic bulk request, whose assignee is
+// notified once the run is saved.
+type checklistItemAssignment struct {
+	item                     ChecklistItem
+	oldAssigneeUserAtMention string
+}
+
+// applyChecklistItemOperationsOnce makes a single attempt at applyChecklistItemOperationsAtomically,
+// returning the updated run, or ErrStaleVersion when the run was modified since it was read.
+func (s *PlaybookRunServiceImpl) applyChecklistItemOperationsOnce(playbookRunID, userID string, ops []ChecklistItemOperation) (*PlaybookRun, error) {
+	playbookRun, err := s.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve playbook run")
+	}
+
+	var originalRun *PlaybookRun
+	if s.shouldDiffRunUpdates() {
+		originalRun = playbookRun.Clone()
+	}
+
+	readAt := playbookRun.UpdateAt
+	timestamp := model.GetMillis()
+	var events []*TimelineEvent
+	var assignments []checklistItemAssignment
+	refreshComputedValues := false
+	for i, op := range ops {
+		resolved, err := resolveChecklistItemOperation(playbookRun.Checklists, op)
+		if err != nil {
+			return nil, &BulkOperationError{Index: i, Err: err}
+		}
+		checklist := &playbookRun.Checklists[resolved.checklistIdx]
+		item := &checklist.Items[resolved.itemIdx]
+
+		switch op.Type {
+		case ChecklistItemOperationSetState:
+			if item.State == op.State {
+				continue
+			}
+			oldState := item.State
+			item.State = op.State
+			item.StateModified = timestamp
+			updateChecklistAndItemTimestamp(checklist, item, timestamp)
+
+			event, _, err := taskStateModifiedEvent(playbookRunID, userID, *item, oldState)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+			refreshComputedValues = true
+		case ChecklistItemOperationSetAssignee:
+			newAssigneeUserAtMention, err := s.assigneeMention(op.AssigneeID)
+			if err != nil {
+				return nil, &BulkOperationError{Index: i, Err: errors.Wrapf(ErrInvalidBulkOperation, "assignee %q not found", op.AssigneeID)}
+			}
+			if applyAssigneeUpdate(item, op.AssigneeID) {
+				continue
+			}
+			oldAssigneeUserAtMention, err := s.assigneeMention(item.AssigneeID)
+			if err != nil {
+				return nil, err
+			}
+			item.AssigneeID = op.AssigneeID
+			item.AssigneeModified = timestamp
+			updateChecklistAndItemTimestamp(checklist, item, timestamp)
+
+			events = append(events, assigneeChangedEvent(playbookRunID, userID, *item, oldAssigneeUserAtMention, newAssigneeUserAtMention))
+			assignments = append(assignments, checklistItemAssignment{item: *item, oldAssigneeUserAtMention: oldAssigneeUserAtMention})
+		case ChecklistItemOperationSetDueDate:
+			setChecklistItemDueDate(checklist, item, op.DueDate, timestamp)
+		case ChecklistItemOperationMove:
+			moveChecklistItem(playbookRun.Checklists, resolved.checklistIdx, resolved.itemIdx, resolved.destChecklistIdx, resolved.destItemIdx, timestamp)
+			refreshComputedValues = true
+		}
+	}
+
+	playbookRun, err = s.store.UpdatePlaybookRunIfUnmodified(playbookRun, readAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update playbook run")
+	}
+
+	// The run is saved, so what follows is logged rather than failing the request.
+	logger := logrus.WithFields(logrus.Fields{"playbook_run_id": playbookRunID, "user_id": userID})
+	for _, event := range events {
+		if _, err := s.store.CreateTimelineEvent(event); err != nil {
+			logger.WithError(err).Warn("failed to create timeline event")
+		}
+	}
+
+	participantIDs := playbookRun.ParticipantIDs
+	for _, assignment := range assignments {
+		s.notifyAssignee(playbookRun, participantIDs, userID, assignment.item, assignment.oldAssigneeUserAtMention)
+
+		// Notifying an assignee can add them as participant, which must not be repeated for the
+		// other items assigned to them.
+		if !slices.Contains(participantIDs, assignment.item.AssigneeID) {
+			if latest, err := s.store.GetPlaybookRun(playbookRunID); err == nil {
+				participantIDs = latest.ParticipantIDs
+			}
+		}
+	}
+
+	if refreshComputedValues {
+		s.refreshComputedPropertyValues(playbookRun)
+	}
+	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRun)
+
+	return playbookRun, nil
+}
+
+// applyChecklistItemOperation resolves op against the current state of the run and applies it
+// through the index based methods.
+func (s *PlaybookRunServiceImpl) applyChecklistItemOperation(playbookRunID, userID string, op ChecklistItemOperation) error {
+	playbookRun, err := s.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve playbook run")
+	}
+
+	resolved, err := resolveChecklistItemOperation(playbookRun.Checklists, op)
+	if err != nil {
+		return err
+	}
+
+	switch op.Type {
+	case ChecklistItemOperationSetState:
+		return s.ModifyCheckedState(playbookRunID, userID, op.State, resolved.checklistIdx, resolved.itemIdx)
+	case ChecklistItemOperationSetAssignee:
+		return s.SetAssignee(playbookRunID, userID, op.AssigneeID, resolved.checklistIdx, resolved.itemIdx)
+	case ChecklistItemOperationSetDueDate:
+		return s.SetDueDate(playbookRunID, userID, op.DueDate, resolved.checklistIdx, resolved.itemIdx)
+	default:
+		return s.MoveChecklistItem(playbookRunID, userID, resolved.checklistIdx, resolved.itemIdx, resolved.destChecklistIdx, resolved.destItemIdx)
+	}
+}
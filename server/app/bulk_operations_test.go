@@ -0,0 +1,243 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mock_bot "github.com/mattermost/mattermost-plugin-playbooks/server/bot/mocks"
+)
+
+func TestResolveChecklistItemOperation(t *testing.T) {
+	checklists := []Checklist{
+		{ID: "checklist_1", Items: []ChecklistItem{{ID: "item_1"}, {ID: "item_2"}, {ID: "item_3"}}},
+		{ID: "checklist_2", Items: []ChecklistItem{{ID: "item_4"}}},
+	}
+	position := func(p int) *int { return &p }
+
+	testCases := []struct {
+		name     string
+		op       ChecklistItemOperation
+		expected resolvedChecklistItemOperation
+		errMsg   string
+	}{
+		{
+			name:     "set state",
+			op:       ChecklistItemOperation{Type: ChecklistItemOperationSetState, ItemID: "item_4", State: ChecklistItemStateClosed},
+			expected: resolvedChecklistItemOperation{checklistIdx: 1, itemIdx: 0},
+		},
+		{
+			name:   "unknown item",
+			op:     ChecklistItemOperation{Type: ChecklistItemOperationSetState, ItemID: "missing", State: ChecklistItemStateClosed},
+			errMsg: `checklist item "missing" not found`,
+		},
+		{
+			name:   "invalid state",
+			op:     ChecklistItemOperation{Type: ChecklistItemOperationSetState, ItemID: "item_1", State: "done"},
+			errMsg: `invalid state "done"`,
+		},
+		{
+			name:   "invalid assignee",
+			op:     ChecklistItemOperation{Type: ChecklistItemOperationSetAssignee, ItemID: "item_1", AssigneeID: "nobody"},
+			errMsg: `invalid assignee_id "nobody"`,
+		},
+		{
+			name:   "unknown type",
+			op:     ChecklistItemOperation{Type: "delete", ItemID: "item_1"},
+			errMsg: `unknown operation type "delete"`,
+		},
+		{
+			name:     "move to the end of its checklist",
+			op:       ChecklistItemOperation{Type: ChecklistItemOperationMove, ItemID: "item_1"},
+			expected: resolvedChecklistItemOperation{checklistIdx: 0, itemIdx: 0, destChecklistIdx: 0, destItemIdx: 2},
+		},
+		{
+			name:     "move to the end of another checklist",
+			op:       ChecklistItemOperation{Type: ChecklistItemOperationMove, ItemID: "item_1", ChecklistID: "checklist_2"},
+			expected: resolvedChecklistItemOperation{checklistIdx: 0, itemIdx: 0, destChecklistIdx: 1, destItemIdx: 1},
+		},
+		{
+			name:     "move to a position",
+			op:       ChecklistItemOperation{Type: ChecklistItemOperationMove, ItemID: "item_3", ChecklistID: "checklist_1", Position: position(0)},
+			expected: resolvedChecklistItemOperation{checklistIdx: 0, itemIdx: 2, destChecklistIdx: 0, destItemIdx: 0},
+		},
+		{
+			name:   "move past the end of its checklist",
+			op:     ChecklistItemOperation{Type: ChecklistItemOperationMove, ItemID: "item_1", Position: position(3)},
+			errMsg: "invalid position 3",
+		},
+		{
+			name:   "move to an unknown checklist",
+			op:     ChecklistItemOperation{Type: ChecklistItemOperationMove, ItemID: "item_1", ChecklistID: "missing"},
+			errMsg: `checklist "missing" not found`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := resolveChecklistItemOperation(checklists, tc.op)
+			if tc.errMsg != "" {
+				require.ErrorIs(t, err, ErrInvalidBulkOperation)
+				assert.Equal(t, tc.errMsg, invalidBulkOperationMessage(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, resolved)
+		})
+	}
+}
+
+// timelineRunStore is a concurrentRunStore that also records the timeline events created.
+type timelineRunStore struct {
+	*concurrentRunStore
+	events []TimelineEvent
+}
+
+func (s *timelineRunStore) CreateTimelineEvent(event *TimelineEvent) (*TimelineEvent, error) {
+	s.events = append(s.events, *event)
+	return event, nil
+}
+
+// noAttributesLicenseChecker disables playbook attributes, so runs are read without properties.
+type noAttributesLicenseChecker struct {
+	LicenseChecker
+}
+
+func (noAttributesLicenseChecker) PlaybookAttributesAllowed() bool { return false }
+
+func TestApplyChecklistItemOperations_Atomic(t *testing.T) {
+	newRun := func() PlaybookRun {
+		return PlaybookRun{
+			ID:        "run1",
+			ChannelID: "channel1",
+			UpdateAt:  1000,
+			Checklists: []Checklist{
+				{ID: "checklist_1", Items: []ChecklistItem{{ID: "item_1", Title: "Check logs"}, {ID: "item_2"}}},
+				{ID: "checklist_2", Items: []ChecklistItem{{ID: "item_3"}}},
+			},
+		}
+	}
+	newService := func(t *testing.T, store PlaybookRunStore) *PlaybookRunServiceImpl {
+		api := &plugintest.API{}
+		api.On("LogAuditRec", mock.Anything).Return()
+		api.On("GetChannelMembersByIds", mock.Anything, mock.Anything).Return(model.ChannelMembers{}, nil)
+		poster := mock_bot.NewMockPoster(gomock.NewController(t))
+		poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), "channel1").AnyTimes()
+
+		return &PlaybookRunServiceImpl{
+			store:          store,
+			configService:  staticConfigService{},
+			poster:         poster,
+			api:            api,
+			pluginAPI:      pluginapi.NewClient(api, &plugintest.Driver{}),
+			licenseChecker: noAttributesLicenseChecker{},
+		}
+	}
+
+	t.Run("operations are saved in a single update", func(t *testing.T) {
+		store := &timelineRunStore{concurrentRunStore: &concurrentRunStore{run: newRun()}}
+		last := 1
+
+		results, err := newService(t, store).ApplyChecklistItemOperations("run1", "user1", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationSetState, ItemID: "item_1", State: ChecklistItemStateClosed},
+			{Type: ChecklistItemOperationMove, ItemID: "item_1", ChecklistID: "checklist_2"},
+			{Type: ChecklistItemOperationMove, ItemID: "item_2", ChecklistID: "checklist_2", Position: &last},
+			{Type: ChecklistItemOperationSetDueDate, ItemID: "item_2", DueDate: 5000},
+		}, true)
+		require.NoError(t, err)
+
+		require.Len(t, results, 4)
+		for _, result := range results {
+			assert.True(t, result.Success)
+		}
+		assert.Equal(t, 1, store.updates)
+
+		checklists := store.run.Checklists
+		assert.Empty(t, checklists[0].Items)
+		require.Len(t, checklists[1].Items, 3)
+		assert.Equal(t, "item_3", checklists[1].Items[0].ID)
+		assert.Equal(t, "item_2", checklists[1].Items[1].ID)
+		assert.Equal(t, int64(5000), checklists[1].Items[1].DueDate)
+		assert.Equal(t, "item_1", checklists[1].Items[2].ID)
+		assert.Equal(t, ChecklistItemStateClosed, checklists[1].Items[2].State)
+
+		require.Len(t, store.events, 1)
+		assert.Equal(t, TaskStateModified, store.events[0].EventType)
+		assert.Equal(t, "checked off checklist item **Check logs**", store.events[0].Summary)
+	})
+
+	t.Run("due date is cleared", func(t *testing.T) {
+		run := newRun()
+		run.Checklists[0].Items[0].DueDate = 5000
+		store := &timelineRunStore{concurrentRunStore: &concurrentRunStore{run: run}}
+
+		_, err := newService(t, store).ApplyChecklistItemOperations("run1", "user1", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationSetDueDate, ItemID: "item_1", DueDate: 0},
+		}, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, store.updates)
+		assert.Zero(t, store.run.Checklists[0].Items[0].DueDate)
+		assert.NotZero(t, store.run.Checklists[0].Items[0].UpdateAt)
+	})
+
+	t.Run("invalid operation applies nothing", func(t *testing.T) {
+		store := &timelineRunStore{concurrentRunStore: &concurrentRunStore{run: newRun()}}
+		position := 1
+
+		_, err := newService(t, store).ApplyChecklistItemOperations("run1", "user1", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationMove, ItemID: "item_1", ChecklistID: "checklist_2"},
+			{Type: ChecklistItemOperationMove, ItemID: "item_2", Position: &position},
+		}, true)
+
+		var bulkErr *BulkOperationError
+		require.ErrorAs(t, err, &bulkErr)
+		assert.Equal(t, 1, bulkErr.Index)
+		assert.Equal(t, "operation 1: invalid position 1", bulkErr.PublicMessage())
+		assert.Zero(t, store.updates)
+		assert.Equal(t, newRun(), store.run)
+	})
+
+	t.Run("operations are applied again to the run changed since it was read", func(t *testing.T) {
+		reads := 0
+		store := &timelineRunStore{concurrentRunStore: &concurrentRunStore{run: newRun(), modify: func(run *PlaybookRun) bool {
+			// Another item is closed once, between the first read and write.
+			reads++
+			if reads > 1 {
+				return false
+			}
+			run.Checklists[1].Items[0].State = ChecklistItemStateClosed
+			return true
+		}}}
+
+		_, err := newService(t, store).ApplyChecklistItemOperations("run1", "user1", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationSetDueDate, ItemID: "item_1", DueDate: 5000},
+		}, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, store.updates)
+		assert.Equal(t, int64(5000), store.run.Checklists[0].Items[0].DueDate)
+		assert.Equal(t, ChecklistItemStateClosed, store.run.Checklists[1].Items[0].State)
+	})
+
+	t.Run("run that keeps changing is left untouched", func(t *testing.T) {
+		store := &timelineRunStore{concurrentRunStore: &concurrentRunStore{run: newRun(), modify: func(*PlaybookRun) bool { return true }}}
+
+		_, err := newService(t, store).ApplyChecklistItemOperations("run1", "user1", []ChecklistItemOperation{
+			{Type: ChecklistItemOperationSetDueDate, ItemID: "item_1", DueDate: 5000},
+		}, true)
+
+		require.ErrorIs(t, err, ErrStaleVersion)
+		assert.Equal(t, maxBulkOperationAttempts, store.updates)
+		assert.Zero(t, store.run.Checklists[0].Items[0].DueDate)
+	})
+}
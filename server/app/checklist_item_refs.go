@@ -0,0 +1,215 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrChecklistItemModified occurs when a change to a checklist item is based on a version of the
+// item that has since been modified.
+var ErrChecklistItemModified = errors.New("checklist item was modified")
+
+// ChecklistItemRef addresses a checklist item of a run by ID, which unlike its position is not
+// affected by other items being added, removed or moved.
+type ChecklistItemRef struct {
+	ItemID string
+
+	// UpdateAt is the UpdateAt of the version of the item the change is based on. When set, the
+	// change is rejected with a *ChecklistItemConflictError if the item was modified since.
+	UpdateAt int64
+}
+
+// ChecklistItemConflictError is returned when ChecklistItemRef.UpdateAt doesn't match the item, or
+// when the run is modified while a change to the item is being made.
+type ChecklistItemConflictError struct {
+	// Item is the current version of the item.
+	Item ChecklistItem
+}
+
+func (e *ChecklistItemConflictError) Error() string {
+	return fmt.Sprintf("checklist item %s was modified at %d", e.Item.ID, e.Item.UpdateAt)
+}
+
+func (e *ChecklistItemConflictError) Unwrap() error {
+	return ErrChecklistItemModified
+}
+
+// resolveChecklistItemRef returns the indices of the item ref addresses, checking that the item
+// wasn't modified since ref.UpdateAt.
+func resolveChecklistItemRef(checklists []Checklist, ref ChecklistItemRef) (int, int, error) {
+	checklistNumber, itemNumber, found := findChecklistItemByID(checklists, ref.ItemID)
+	if !found {
+		return -1, -1, errors.Wrapf(ErrNotFound, "checklist item %s", ref.ItemID)
+	}
+
+	item := checklists[checklistNumber].Items[itemNumber]
+	if ref.UpdateAt != 0 && item.UpdateAt != ref.UpdateAt {
+		return -1, -1, &ChecklistItemConflictError{Item: item}
+	}
+
+	return checklistNumber, itemNumber, nil
+}
+
+// checklistItemConflict returns the error for a change to the item with the given ID that was
+// modified, or removed, while the change was being made.
+func checklistItemConflict(checklists []Checklist, itemID string) error {
+	checklistNumber, itemNumber, found := findChecklistItemByID(checklists, itemID)
+	if !found {
+		return errors.Wrapf(ErrNotFound, "checklist item %s", itemID)
+	}
+
+	return &ChecklistItemConflictError{Item: checklists[checklistNumber].Items[itemNumber]}
+}
+
+// checklistItemRunStore is the store through which the index based methods change the item a
+// ChecklistItemRef was resolved to. Until the run is first written, every read of it must still
+// have the item, as it was resolved, at the same position, and every write is conditional on the
+// run being unmodified since it was last read. The item therefore can't change between being
+// resolved and being written, which fails with a *ChecklistItemConflictError instead.
+type checklistItemRunStore struct {
+	PlaybookRunStore
+	item            ChecklistItem
+	checklistNumber int
+	itemNumber      int
+	readAt          int64
+	written         bool
+}
+
+func (s *checklistItemRunStore) GetPlaybookRun(id string) (*PlaybookRun, error) {
+	playbookRun, err := s.PlaybookRunStore.GetPlaybookRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.written && !s.hasResolvedItem(playbookRun.Checklists) {
+		return nil, checklistItemConflict(playbookRun.Checklists, s.item.ID)
+	}
+	s.readAt = playbookRun.UpdateAt
+
+	return playbookRun, nil
+}
+
+// hasResolvedItem tells whether checklists have the item, as it was resolved, at its position.
+func (s *checklistItemRunStore) hasResolvedItem(checklists []Checklist) bool {
+	if !IsValidChecklistItemIndex(checklists, s.checklistNumber, s.itemNumber) {
+		return false
+	}
+
+	item := checklists[s.checklistNumber].Items[s.itemNumber]
+	return item.ID == s.item.ID && item.UpdateAt == s.item.UpdateAt
+}
+
+func (s *checklistItemRunStore) UpdatePlaybookRun(playbookRun *PlaybookRun) (*PlaybookRun, error) {
+	updated, err := s.PlaybookRunStore.UpdatePlaybookRunIfUnmodified(playbookRun, s.readAt)
+	if errors.Is(err, ErrStaleVersion) {
+		current, getErr := s.PlaybookRunStore.GetPlaybookRun(playbookRun.ID)
+		if getErr != nil {
+			return nil, errors.Wrap(getErr, "failed to retrieve modified playbook run")
+		}
+		return nil, checklistItemConflict(current.Checklists, s.item.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.readAt = updated.UpdateAt
+	s.written = true
+
+	return updated, nil
+}
+
+// WithChecklistItem resolves the checklist item ref addresses and calls apply with the checklist
+// and item numbers it is at, and a service through which the index based methods change it only
+// if it is still the same: the change fails with a *ChecklistItemConflictError when the item, or
+// any other part of the run, is modified between being resolved and being written.
+func (s *PlaybookRunServiceImpl) WithChecklistItem(playbookRunID string, ref ChecklistItemRef, apply func(service PlaybookRunService, checklistNumber, itemNumber int) error) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error {
+		return apply(service, checklistNumber, itemNumber)
+	})
+}
+
+// withChecklistItem is WithChecklistItem for the methods of the service itself.
+func (s *PlaybookRunServiceImpl) withChecklistItem(playbookRunID string, ref ChecklistItemRef, apply func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error) error {
+	playbookRun, err := s.store.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve playbook run")
+	}
+
+	checklistNumber, itemNumber, err := resolveChecklistItemRef(playbookRun.Checklists, ref)
+	if err != nil {
+		return err
+	}
+
+	service := *s
+	service.store = &checklistItemRunStore{
+		PlaybookRunStore: s.store,
+		item:             playbookRun.Checklists[checklistNumber].Items[itemNumber],
+		checklistNumber:  checklistNumber,
+		itemNumber:       itemNumber,
+		readAt:           playbookRun.UpdateAt,
+	}
+
+	return apply(&service, checklistNumber, itemNumber)
+}
+
+// ModifyCheckedStateByID modifies the state of the checklist item ref addresses.
+func (s *PlaybookRunServiceImpl) ModifyCheckedStateByID(playbookRunID, userID, newState string, ref ChecklistItemRef) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error {
+		return service.ModifyCheckedState(playbookRunID, userID, newState, checklistNumber, itemNumber)
+	})
+}
+
+// ToggleCheckedStateByID checks or unchecks the checklist item ref addresses.
+func (s *PlaybookRunServiceImpl) ToggleCheckedStateByID(playbookRunID, userID string, ref ChecklistItemRef) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error {
+		return service.ToggleCheckedState(playbookRunID, userID, checklistNumber, itemNumber)
+	})
+}
+
+// SetAssigneeByID sets the assignee of the checklist item ref addresses.
+func (s *PlaybookRunServiceImpl) SetAssigneeByID(playbookRunID, userID, assigneeID string, ref ChecklistItemRef) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error {
+		return service.SetAssignee(playbookRunID, userID, assigneeID, checklistNumber, itemNumber)
+	})
+}
+
+// SetDueDateByID sets the due date of the checklist item ref addresses.
+func (s *PlaybookRunServiceImpl) SetDueDateByID(playbookRunID, userID string, duedate int64, ref ChecklistItemRef) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error {
+		return service.SetDueDate(playbookRunID, userID, duedate, checklistNumber, itemNumber)
+	})
+}
+
+// RemoveChecklistItemByID removes the checklist item ref addresses.
+func (s *PlaybookRunServiceImpl) RemoveChecklistItemByID(playbookRunID, userID string, ref ChecklistItemRef) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, checklistNumber, itemNumber int) error {
+		return service.RemoveChecklistItem(playbookRunID, userID, checklistNumber, itemNumber)
+	})
+}
+
+// MoveChecklistItemByID moves the checklist item ref addresses to position in the checklist with
+// ID destChecklistID, or in its own checklist when destChecklistID is empty. The item is moved to
+// the end of the checklist when position is nil.
+func (s *PlaybookRunServiceImpl) MoveChecklistItemByID(playbookRunID, userID string, ref ChecklistItemRef, destChecklistID string, position *int) error {
+	return s.withChecklistItem(playbookRunID, ref, func(service *PlaybookRunServiceImpl, _, _ int) error {
+		playbookRun, err := service.store.GetPlaybookRun(playbookRunID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve playbook run")
+		}
+
+		resolved, err := resolveChecklistItemOperation(playbookRun.Checklists, ChecklistItemOperation{
+			Type:        ChecklistItemOperationMove,
+			ItemID:      ref.ItemID,
+			ChecklistID: destChecklistID,
+			Position:    position,
+		})
+		if err != nil {
+			return err
+		}
+
+		return service.MoveChecklistItem(playbookRunID, userID, resolved.checklistIdx, resolved.itemIdx, resolved.destChecklistIdx, resolved.destItemIdx)
+	})
+}
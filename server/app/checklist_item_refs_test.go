@@ -0,0 +1,152 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mock_bot "github.com/mattermost/mattermost-plugin-playbooks/server/bot/mocks"
+)
+
+func TestResolveChecklistItemRef(t *testing.T) {
+	checklists := []Checklist{
+		{ID: "checklist_1", Items: []ChecklistItem{{ID: "item_1", UpdateAt: 100}}},
+		{ID: "checklist_2", Items: []ChecklistItem{{ID: "item_2", UpdateAt: 200}, {ID: "item_3", UpdateAt: 300}}},
+	}
+
+	t.Run("by ID", func(t *testing.T) {
+		checklistNumber, itemNumber, err := resolveChecklistItemRef(checklists, ChecklistItemRef{ItemID: "item_3"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, checklistNumber)
+		assert.Equal(t, 1, itemNumber)
+	})
+
+	t.Run("matching version", func(t *testing.T) {
+		checklistNumber, itemNumber, err := resolveChecklistItemRef(checklists, ChecklistItemRef{ItemID: "item_1", UpdateAt: 100})
+		require.NoError(t, err)
+		assert.Equal(t, 0, checklistNumber)
+		assert.Equal(t, 0, itemNumber)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		_, _, err := resolveChecklistItemRef(checklists, ChecklistItemRef{ItemID: "item_2", UpdateAt: 150})
+		require.ErrorIs(t, err, ErrChecklistItemModified)
+
+		var conflictErr *ChecklistItemConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, "item_2", conflictErr.Item.ID)
+		assert.Equal(t, int64(200), conflictErr.Item.UpdateAt)
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		_, _, err := resolveChecklistItemRef(checklists, ChecklistItemRef{ItemID: "missing"})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSetDueDateByID_ConcurrentRunChange(t *testing.T) {
+	newRun := func() PlaybookRun {
+		return PlaybookRun{
+			ID:        "run1",
+			ChannelID: "channel1",
+			UpdateAt:  1000,
+			Checklists: []Checklist{{
+				ID:    "checklist_1",
+				Items: []ChecklistItem{{ID: "item_1", UpdateAt: 100}, {ID: "item_2", UpdateAt: 100}},
+			}},
+		}
+	}
+	newService := func(t *testing.T, store PlaybookRunStore) *PlaybookRunServiceImpl {
+		api := &plugintest.API{}
+		api.On("LogAuditRec", mock.Anything).Return()
+		api.On("GetChannelMembersByIds", mock.Anything, mock.Anything).Return(model.ChannelMembers{}, nil)
+		poster := mock_bot.NewMockPoster(gomock.NewController(t))
+		poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), "channel1").AnyTimes()
+
+		return &PlaybookRunServiceImpl{
+			store:          store,
+			configService:  staticConfigService{},
+			poster:         poster,
+			api:            api,
+			pluginAPI:      pluginapi.NewClient(api, &plugintest.Driver{}),
+			licenseChecker: noAttributesLicenseChecker{},
+		}
+	}
+	// modifyOnRead returns a modify func for concurrentRunStore applying change once, right after
+	// the given read of the run.
+	modifyOnRead := func(read int, change func(run *PlaybookRun)) func(run *PlaybookRun) bool {
+		reads := 0
+		return func(run *PlaybookRun) bool {
+			reads++
+			if reads != read {
+				return false
+			}
+			change(run)
+			return true
+		}
+	}
+
+	t.Run("unchanged run is written", func(t *testing.T) {
+		store := &concurrentRunStore{run: newRun()}
+
+		err := newService(t, store).SetDueDateByID("run1", "user1", 5000, ChecklistItemRef{ItemID: "item_2", UpdateAt: 100})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, store.updates)
+		assert.Equal(t, int64(5000), store.run.Checklists[0].Items[1].DueDate)
+	})
+
+	t.Run("item modified between the lookup and the change is a conflict", func(t *testing.T) {
+		store := &concurrentRunStore{run: newRun(), modify: modifyOnRead(1, func(run *PlaybookRun) {
+			run.Checklists[0].Items[1].State = ChecklistItemStateClosed
+			run.Checklists[0].Items[1].UpdateAt = 200
+		})}
+
+		err := newService(t, store).SetDueDateByID("run1", "user1", 5000, ChecklistItemRef{ItemID: "item_2", UpdateAt: 100})
+
+		var conflictErr *ChecklistItemConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, int64(200), conflictErr.Item.UpdateAt)
+		assert.Zero(t, store.updates)
+		assert.Zero(t, store.run.Checklists[0].Items[1].DueDate)
+	})
+
+	t.Run("item moved between the lookup and the change is a conflict", func(t *testing.T) {
+		store := &concurrentRunStore{run: newRun(), modify: modifyOnRead(1, func(run *PlaybookRun) {
+			items := run.Checklists[0].Items
+			items[0], items[1] = items[1], items[0]
+		})}
+
+		err := newService(t, store).SetDueDateByID("run1", "user1", 5000, ChecklistItemRef{ItemID: "item_2"})
+
+		var conflictErr *ChecklistItemConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, "item_2", conflictErr.Item.ID)
+		assert.Zero(t, store.updates)
+		assert.Zero(t, store.run.Checklists[0].Items[0].DueDate)
+		assert.Zero(t, store.run.Checklists[0].Items[1].DueDate)
+	})
+
+	t.Run("run modified between the read and the write of the change is a conflict", func(t *testing.T) {
+		store := &concurrentRunStore{run: newRun(), modify: modifyOnRead(2, func(run *PlaybookRun) {
+			run.Checklists[0].Items[0].State = ChecklistItemStateClosed
+		})}
+
+		err := newService(t, store).SetDueDateByID("run1", "user1", 5000, ChecklistItemRef{ItemID: "item_2"})
+
+		var conflictErr *ChecklistItemConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, 1, store.updates)
+		assert.Equal(t, ChecklistItemStateClosed, store.run.Checklists[0].Items[0].State)
+		assert.Zero(t, store.run.Checklists[0].Items[1].DueDate)
+	})
+}
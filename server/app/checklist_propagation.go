@@ -0,0 +1,440 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"reflect"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// PropagationSkipEditedInRun means the run changed the value the playbook now changes.
+	PropagationSkipEditedInRun = "edited_in_run"
+
+	// PropagationSkipNotInRun means the run no longer has the item or checklist, or renamed it.
+	PropagationSkipNotInRun = "not_in_run"
+
+	// PropagationSkipConditional means the new item is shown by a playbook condition, which
+	// cannot be evaluated for a run that is already under way.
+	PropagationSkipConditional = "conditional_item"
+)
+
+// propagatedItemFields are the checklist item fields a playbook defines for its runs. Everything
+// else (state, assignees, due dates and condition links) belongs to the run once it starts.
+var propagatedItemFields = []string{"title", "description", "command", "task_actions"}
+
+// PropagatedChecklistChange describes a single change to the checklists of a run. ItemID and
+// ItemTitle are empty when the change concerns the checklist itself.
+type PropagatedChecklistChange struct {
+	ChecklistID    string   `json:"checklist_id"`
+	ChecklistTitle string   `json:"checklist_title"`
+	ItemID         string   `json:"item_id,omitempty"`
+	ItemTitle      string   `json:"item_title,omitempty"`
+	Fields         []string `json:"fields,omitempty"`
+	Reason         string   `json:"reason,omitempty"`
+}
+
+// RunChecklistPropagation reports what propagating a playbook's checklists did to a single run.
+type RunChecklistPropagation struct {
+	PlaybookRunID string `json:"playbook_run_id"`
+	Name          string `json:"name"`
+
+	// FromRevision is the playbook revision the run was created from.
+	FromRevision int64 `json:"from_revision"`
+
+	Added   []PropagatedChecklistChange `json:"added"`
+	Updated []PropagatedChecklistChange `json:"updated"`
+	Skipped []PropagatedChecklistChange `json:"skipped"`
+
+	// Error is set when the run could not be updated at all.
+	Error string `json:"error,omitempty"`
+}
+
+// HasChanges returns true if the run's checklists were, or in a dry run would be, modified.
+func (r RunChecklistPropagation) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Updated) > 0
+}
+
+// ChecklistPropagationResult reports the outcome of propagating a playbook's checklists to its
+// active runs.
+type ChecklistPropagationResult struct {
+	PlaybookID string `json:"playbook_id"`
+
+	// Revision is the playbook revision that was propagated.
+	Revision int64                     `json:"revision"`
+	DryRun   bool                      `json:"dry_run"`
+	Runs     []RunChecklistPropagation `json:"runs"`
+}
+
+// MergePlaybookChecklists brings the checklists of a run created from the base checklists of a
+// playbook up to date with its target checklists. New checklists and items are added, and the
+// playbook-defined fields of existing ones are updated where the run still has the base value.
+// Nothing is removed from the run, and state, assignees and anything edited in the run are left
+// as they are. runStart converts the relative due dates of new items into absolute ones.
+//
+// Playbook checklists do not always carry IDs, so checklists and items are matched by ID and then
+// by title before being diffed. Checklists are also matched by position, as are the items of the
+// two playbook versions, so that an item renamed in the playbook is updated rather than added.
+func MergePlaybookChecklists(base, target, run []Checklist, runStart int64) ([]Checklist, RunChecklistPropagation) {
+	var report RunChecklistPropagation
+
+	merged := make([]Checklist, len(run))
+	for i := range run {
+		merged[i] = run[i].Clone()
+	}
+
+	keyedBase, keyedTarget := keyPlaybookChecklists(base, target, merged)
+	updates, _ := GetChecklistUpdates(keyedBase, keyedTarget)
+
+	baseChecklists := make(map[string]Checklist, len(keyedBase))
+	for _, checklist := range keyedBase {
+		baseChecklists[checklist.ID] = checklist
+	}
+	targetChecklists := make(map[string]Checklist, len(keyedTarget))
+	for _, checklist := range keyedTarget {
+		targetChecklists[checklist.ID] = checklist
+	}
+
+	now := model.GetMillis()
+	for _, update := range updates {
+		targetChecklist := targetChecklists[update.ID]
+		baseChecklist, inBase := baseChecklists[update.ID]
+
+		checklistIdx := findChecklistByID(merged, update.ID)
+		if checklistIdx < 0 {
+			if !inBase {
+				// The whole checklist is new to the playbook.
+				checklist := Checklist{ID: update.ID, Title: targetChecklist.Title, UpdateAt: now}
+				for _, item := range targetChecklist.Items {
+					if item.ConditionID != "" {
+						report.Skipped = append(report.Skipped, itemChange(checklist, item, nil, PropagationSkipConditional))
+						continue
+					}
+					checklist.Items = append(checklist.Items, newPropagatedItem(item, runStart, now))
+				}
+				merged = append(merged, checklist)
+				report.Added = append(report.Added, itemChange(checklist, ChecklistItem{}, nil, ""))
+				continue
+			}
+
+			if hasPropagatedChanges(update) {
+				report.Skipped = append(report.Skipped, itemChange(baseChecklist, ChecklistItem{}, nil, PropagationSkipNotInRun))
+			}
+			continue
+		}
+
+		checklist := &merged[checklistIdx]
+
+		if title, ok := update.Fields["title"].(string); ok && inBase {
+			if checklist.Title == baseChecklist.Title {
+				checklist.Title = title
+				checklist.UpdateAt = now
+				report.Updated = append(report.Updated, itemChange(*checklist, ChecklistItem{}, []string{"title"}, ""))
+			} else {
+				report.Skipped = append(report.Skipped, itemChange(*checklist, ChecklistItem{}, []string{"title"}, PropagationSkipEditedInRun))
+			}
+		}
+
+		baseItems := make(map[string]ChecklistItem, len(baseChecklist.Items))
+		for _, item := range baseChecklist.Items {
+			baseItems[item.ID] = item
+		}
+
+		for _, itemUpdate := range update.ItemUpdates {
+			fields := propagatedFields(itemUpdate)
+			if len(fields) == 0 {
+				continue
+			}
+
+			baseItem := baseItems[itemUpdate.ID]
+			itemIdx := findItemByID(checklist.Items, itemUpdate.ID)
+			if itemIdx < 0 {
+				report.Skipped = append(report.Skipped, itemChange(*checklist, baseItem, fields, PropagationSkipNotInRun))
+				continue
+			}
+
+			item := &checklist.Items[itemIdx]
+			targetItem := targetChecklist.Items[findItemByID(targetChecklist.Items, itemUpdate.ID)]
+			var updated, skipped []string
+			for _, field := range fields {
+				if !propagatedFieldEqual(field, *item, baseItem) {
+					skipped = append(skipped, field)
+					continue
+				}
+				setPropagatedField(field, item, targetItem)
+				updated = append(updated, field)
+			}
+
+			if len(updated) > 0 {
+				updateChecklistAndItemTimestamp(checklist, item, now)
+				report.Updated = append(report.Updated, itemChange(*checklist, *item, updated, ""))
+			}
+			if len(skipped) > 0 {
+				report.Skipped = append(report.Skipped, itemChange(*checklist, *item, skipped, PropagationSkipEditedInRun))
+			}
+		}
+
+		for _, insert := range update.ItemInserts {
+			// The item was already added to the run by an earlier propagation.
+			if hasItemWithID(merged, insert.ID) {
+				continue
+			}
+			if insert.ConditionID != "" {
+				report.Skipped = append(report.Skipped, itemChange(*checklist, insert, nil, PropagationSkipConditional))
+				continue
+			}
+
+			item := newPropagatedItem(insert, runStart, now)
+			position := insertPosition(checklist.Items, targetChecklist.Items, insert.ID)
+			checklist.Items = append(checklist.Items, ChecklistItem{})
+			copy(checklist.Items[position+1:], checklist.Items[position:])
+			checklist.Items[position] = item
+			checklist.UpdateAt = now
+			report.Added = append(report.Added, itemChange(*checklist, item, nil, ""))
+		}
+	}
+
+	return merged, report
+}
+
+// keyPlaybookChecklists returns copies of the base and target playbook checklists whose IDs are
+// those of the matching run checklists and items. Unmatched checklists and items keep their
+// playbook ID, or get a new one, so that GetChecklistUpdates reports them as added.
+func keyPlaybookChecklists(base, target, run []Checklist) ([]Checklist, []Checklist) {
+	keyedBase := make([]Checklist, len(base))
+	keyedTarget := make([]Checklist, len(target))
+
+	baseToRun := matchChecklists(base, run)
+	for i := range base {
+		keyedBase[i] = base[i].Clone()
+		var runItems []ChecklistItem
+		if j := baseToRun[i]; j >= 0 {
+			keyedBase[i].ID = run[j].ID
+			runItems = run[j].Items
+		} else {
+			keyedBase[i].ID = model.NewId()
+		}
+
+		itemToRun := matchItems(base[i].Items, runItems, false)
+		for k := range keyedBase[i].Items {
+			if j := itemToRun[k]; j >= 0 {
+				keyedBase[i].Items[k].ID = runItems[j].ID
+			} else {
+				keyedBase[i].Items[k].ID = model.NewId()
+			}
+		}
+	}
+
+	targetToBase := matchChecklists(target, base)
+	for i := range target {
+		keyedTarget[i] = target[i].Clone()
+		var baseItems, keyedBaseItems []ChecklistItem
+		if j := targetToBase[i]; j >= 0 {
+			keyedTarget[i].ID = keyedBase[j].ID
+			baseItems = base[j].Items
+			keyedBaseItems = keyedBase[j].Items
+		} else {
+			keyedTarget[i].ID = idOrNew(target[i].ID)
+		}
+
+		itemToBase := matchItems(target[i].Items, baseItems, true)
+		for k := range keyedTarget[i].Items {
+			if j := itemToBase[k]; j >= 0 {
+				keyedTarget[i].Items[k].ID = keyedBaseItems[j].ID
+			} else {
+				keyedTarget[i].Items[k].ID = idOrNew(target[i].Items[k].ID)
+			}
+		}
+	}
+
+	return keyedBase, keyedTarget
+}
+
+// idOrNew returns id, or a new ID if it is empty.
+func idOrNew(id string) string {
+	if id == "" {
+		return model.NewId()
+	}
+
+	return id
+}
+
+// matchChecklists pairs each checklist in left with one in right, by ID, then by title and
+// finally by position. Unpaired checklists map to -1.
+func matchChecklists(left, right []Checklist) []int {
+	return matchIndexes(len(left), len(right), []func(i, j int) bool{
+		func(i, j int) bool { return left[i].ID != "" && left[i].ID == right[j].ID },
+		func(i, j int) bool { return left[i].Title == right[j].Title },
+		func(i, j int) bool { return i == j },
+	})
+}
+
+// matchItems pairs each checklist item in left with one in right, by ID and then by title. With
+// byPosition, items still unpaired are matched to the one at the same index. Unpaired items map
+// to -1.
+func matchItems(left, right []ChecklistItem, byPosition bool) []int {
+	passes := []func(i, j int) bool{
+		func(i, j int) bool { return left[i].ID != "" && left[i].ID == right[j].ID },
+		func(i, j int) bool { return left[i].Title == right[j].Title },
+	}
+	if byPosition {
+		passes = append(passes, func(i, j int) bool { return i == j })
+	}
+
+	return matchIndexes(len(left), len(right), passes)
+}
+
+// matchIndexes pairs indexes of two lists using each pass in turn on the indexes still unpaired.
+func matchIndexes(leftLen, rightLen int, passes []func(i, j int) bool) []int {
+	result := make([]int, leftLen)
+	for i := range result {
+		result[i] = -1
+	}
+	taken := make([]bool, rightLen)
+
+	for _, pass := range passes {
+		for i := 0; i < leftLen; i++ {
+			if result[i] >= 0 {
+				continue
+			}
+			for j := 0; j < rightLen; j++ {
+				if !taken[j] && pass(i, j) {
+					result[i] = j
+					taken[j] = true
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// propagatedFields returns the playbook-defined fields changed by an item update.
+func propagatedFields(update ChecklistItemUpdate) []string {
+	var fields []string
+	for _, field := range propagatedItemFields {
+		if _, ok := update.Fields[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// hasPropagatedChanges returns true if a checklist update changes anything a playbook defines.
+func hasPropagatedChanges(update ChecklistUpdate) bool {
+	if _, ok := update.Fields["title"]; ok || len(update.ItemInserts) > 0 {
+		return true
+	}
+	for _, itemUpdate := range update.ItemUpdates {
+		if len(propagatedFields(itemUpdate)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newPropagatedItem prepares a playbook item to be added to a run that is already under way.
+func newPropagatedItem(item ChecklistItem, runStart, now int64) ChecklistItem {
+	item.State = ChecklistItemStateOpen
+	item.StateModified = 0
+	// Pre-assigned users are only invited when a run starts, so they may not be participants.
+	item.AssigneeID = ""
+	item.AssigneeModified = 0
+	item.CommandLastRun = 0
+	item.LastSkipped = 0
+	item.ConditionAction = ConditionActionNone
+	item.ConditionReason = ""
+	if item.DueDate > 0 {
+		item.DueDate += runStart
+	}
+	item.UpdateAt = now
+
+	return item
+}
+
+// insertPosition returns where an item new to the target checklist goes in the run checklist:
+// right after the item preceding it in the target checklist, or first if there is none.
+func insertPosition(runItems, targetItems []ChecklistItem, itemID string) int {
+	targetIdx := findItemByID(targetItems, itemID)
+	for i := targetIdx - 1; i >= 0; i-- {
+		if runIdx := findItemByID(runItems, targetItems[i].ID); runIdx >= 0 {
+			return runIdx + 1
+		}
+	}
+
+	return 0
+}
+
+func propagatedFieldEqual(field string, a, b ChecklistItem) bool {
+	switch field {
+	case "title":
+		return a.Title == b.Title
+	case "description":
+		return a.Description == b.Description
+	case "command":
+		return a.Command == b.Command
+	case "task_actions":
+		return (len(a.TaskActions) == 0 && len(b.TaskActions) == 0) || reflect.DeepEqual(a.TaskActions, b.TaskActions)
+	}
+
+	return false
+}
+
+func setPropagatedField(field string, item *ChecklistItem, from ChecklistItem) {
+	switch field {
+	case "title":
+		item.Title = from.Title
+	case "description":
+		item.Description = from.Description
+	case "command":
+		item.Command = from.Command
+	case "task_actions":
+		item.TaskActions = from.TaskActions
+	}
+}
+
+func itemChange(checklist Checklist, item ChecklistItem, fields []string, reason string) PropagatedChecklistChange {
+	return PropagatedChecklistChange{
+		ChecklistID:    checklist.ID,
+		ChecklistTitle: checklist.Title,
+		ItemID:         item.ID,
+		ItemTitle:      item.Title,
+		Fields:         fields,
+		Reason:         reason,
+	}
+}
+
+func hasItemWithID(checklists []Checklist, id string) bool {
+	for _, checklist := range checklists {
+		if findItemByID(checklist.Items, id) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findChecklistByID(checklists []Checklist, id string) int {
+	for i := range checklists {
+		if checklists[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findItemByID(items []ChecklistItem, id string) int {
+	for i := range items {
+		if items[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
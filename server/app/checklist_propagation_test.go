@@ -0,0 +1,313 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mock_bot "github.com/mattermost/mattermost-plugin-playbooks/server/bot/mocks"
+	"github.com/mattermost/mattermost-plugin-playbooks/server/config"
+)
+
+func TestMergePlaybookChecklists(t *testing.T) {
+	// Playbooks edited through the webapp lose their checklist IDs, while runs get new ones.
+	base := []Checklist{{
+		Title: "Triage",
+		Items: []ChecklistItem{
+			{Title: "Acknowledge alert"},
+			{Title: "Cehck logs", Description: "Look at the logs"},
+			{Title: "Page on-call", Command: "/page"},
+		},
+	}}
+	newRun := func() []Checklist {
+		return []Checklist{{
+			ID:    "run-checklist",
+			Title: "Triage",
+			Items: []ChecklistItem{
+				{ID: "run-item-1", Title: "Acknowledge alert", State: ChecklistItemStateClosed, AssigneeID: "user-1"},
+				{ID: "run-item-2", Title: "Cehck logs", Description: "Look at the logs", AssigneeID: "user-2"},
+				{ID: "run-item-3", Title: "Page on-call", Command: "/page"},
+			},
+		}}
+	}
+
+	t.Run("no playbook changes", func(t *testing.T) {
+		run := newRun()
+		merged, report := MergePlaybookChecklists(base, base, run, 1000)
+
+		assert.False(t, report.HasChanges())
+		assert.Empty(t, report.Skipped)
+		assert.Equal(t, run, merged)
+	})
+
+	t.Run("fixed typo is propagated without touching state or assignees", func(t *testing.T) {
+		target := []Checklist{base[0].Clone()}
+		target[0].Items[1].Title = "Check logs"
+
+		merged, report := MergePlaybookChecklists(base, target, newRun(), 1000)
+
+		require.Len(t, report.Updated, 1)
+		assert.Equal(t, "run-item-2", report.Updated[0].ItemID)
+		assert.Equal(t, []string{"title"}, report.Updated[0].Fields)
+		assert.Empty(t, report.Added)
+
+		item := merged[0].Items[1]
+		assert.Equal(t, "run-item-2", item.ID)
+		assert.Equal(t, "Check logs", item.Title)
+		assert.Equal(t, "user-2", item.AssigneeID)
+		assert.Equal(t, ChecklistItemStateClosed, merged[0].Items[0].State)
+		assert.Equal(t, "user-1", merged[0].Items[0].AssigneeID)
+	})
+
+	t.Run("missing step is added after its predecessor", func(t *testing.T) {
+		target := []Checklist{base[0].Clone()}
+		target[0].Items = []ChecklistItem{
+			target[0].Items[0],
+			target[0].Items[1],
+			{Title: "Open incident doc", State: ChecklistItemStateClosed, AssigneeID: "user-3", DueDate: 60000},
+			target[0].Items[2],
+		}
+
+		merged, report := MergePlaybookChecklists(base, target, newRun(), 1000)
+
+		require.Len(t, report.Added, 1)
+		assert.Equal(t, "Open incident doc", report.Added[0].ItemTitle)
+		require.Len(t, merged[0].Items, 4)
+
+		added := merged[0].Items[2]
+		assert.Equal(t, "Open incident doc", added.Title)
+		assert.NotEmpty(t, added.ID)
+		assert.Equal(t, ChecklistItemStateOpen, added.State)
+		assert.Empty(t, added.AssigneeID)
+		assert.Equal(t, int64(61000), added.DueDate)
+		assert.Equal(t, "run-item-3", merged[0].Items[3].ID)
+	})
+
+	t.Run("fields edited in the run are kept", func(t *testing.T) {
+		target := []Checklist{base[0].Clone()}
+		target[0].Items[1].Description = "Look at the dashboards"
+		target[0].Items[2].Command = "/page oncall"
+
+		run := newRun()
+		run[0].Items[1].Description = "Look at the logs in the EU region"
+
+		merged, report := MergePlaybookChecklists(base, target, run, 1000)
+
+		require.Len(t, report.Updated, 1)
+		assert.Equal(t, "run-item-3", report.Updated[0].ItemID)
+		require.Len(t, report.Skipped, 1)
+		assert.Equal(t, "run-item-2", report.Skipped[0].ItemID)
+		assert.Equal(t, []string{"description"}, report.Skipped[0].Fields)
+		assert.Equal(t, PropagationSkipEditedInRun, report.Skipped[0].Reason)
+
+		assert.Equal(t, "Look at the logs in the EU region", merged[0].Items[1].Description)
+		assert.Equal(t, "/page oncall", merged[0].Items[2].Command)
+	})
+
+	t.Run("items removed from the playbook or the run are left alone", func(t *testing.T) {
+		target := []Checklist{base[0].Clone()}
+		target[0].Items = target[0].Items[:2]
+		target[0].Items[0].Title = "Acknowledge the alert"
+
+		run := newRun()
+		run[0].Items = run[0].Items[1:]
+
+		merged, report := MergePlaybookChecklists(base, target, run, 1000)
+
+		assert.False(t, report.HasChanges())
+		require.Len(t, report.Skipped, 1)
+		assert.Equal(t, PropagationSkipNotInRun, report.Skipped[0].Reason)
+		assert.Equal(t, run, merged)
+	})
+
+	t.Run("new checklist is appended", func(t *testing.T) {
+		target := []Checklist{
+			base[0].Clone(),
+			{Title: "Follow up", Items: []ChecklistItem{
+				{Title: "Write retrospective"},
+				{Title: "Notify customers", ConditionID: "condition-1", ConditionAction: ConditionActionHidden},
+			}},
+		}
+
+		merged, report := MergePlaybookChecklists(base, target, newRun(), 1000)
+
+		require.Len(t, merged, 2)
+		assert.Equal(t, "Follow up", merged[1].Title)
+		assert.NotEmpty(t, merged[1].ID)
+		require.Len(t, merged[1].Items, 1)
+		assert.Equal(t, "Write retrospective", merged[1].Items[0].Title)
+
+		require.Len(t, report.Added, 1)
+		assert.Equal(t, "Follow up", report.Added[0].ChecklistTitle)
+		assert.Empty(t, report.Added[0].ItemID)
+		require.Len(t, report.Skipped, 1)
+		assert.Equal(t, PropagationSkipConditional, report.Skipped[0].Reason)
+	})
+
+	t.Run("renamed checklist", func(t *testing.T) {
+		target := []Checklist{base[0].Clone()}
+		target[0].Title = "Triage and diagnose"
+
+		merged, report := MergePlaybookChecklists(base, target, newRun(), 1000)
+
+		require.Len(t, report.Updated, 1)
+		assert.Empty(t, report.Updated[0].ItemID)
+		assert.Equal(t, "Triage and diagnose", merged[0].Title)
+		assert.Equal(t, "run-checklist", merged[0].ID)
+
+		run := newRun()
+		run[0].Title = "Triage (EU)"
+		merged, report = MergePlaybookChecklists(base, target, run, 1000)
+
+		assert.False(t, report.HasChanges())
+		require.Len(t, report.Skipped, 1)
+		assert.Equal(t, PropagationSkipEditedInRun, report.Skipped[0].Reason)
+		assert.Equal(t, "Triage (EU)", merged[0].Title)
+	})
+
+	t.Run("the run's checklists are not modified", func(t *testing.T) {
+		target := []Checklist{base[0].Clone()}
+		target[0].Items[1].Title = "Check logs"
+
+		run := newRun()
+		_, _ = MergePlaybookChecklists(base, target, run, 1000)
+
+		assert.Equal(t, newRun(), run)
+	})
+}
+
+// concurrentRunStore stores a single run, which modify may change each time it is read to simulate
+// an update made between a read and the following write.
+type concurrentRunStore struct {
+	PlaybookRunStore
+	run     PlaybookRun
+	modify  func(run *PlaybookRun) bool
+	updates int
+}
+
+func (s *concurrentRunStore) GetPlaybookRun(string) (*PlaybookRun, error) {
+	read := s.run.Clone()
+	if s.modify != nil && s.modify(&s.run) {
+		s.run.UpdateAt++
+	}
+	return read, nil
+}
+
+func (s *concurrentRunStore) UpdatePlaybookRunIfUnmodified(run *PlaybookRun, updateAt int64) (*PlaybookRun, error) {
+	s.updates++
+	if updateAt != s.run.UpdateAt {
+		return nil, ErrStaleVersion
+	}
+	s.run = *run.Clone()
+	return run, nil
+}
+
+// staticConfigService disables incremental updates and the change feed.
+type staticConfigService struct {
+	config.Service
+}
+
+func (staticConfigService) IsIncrementalUpdatesEnabled() bool { return false }
+func (staticConfigService) IsRunChangeFeedEnabled() bool      { return false }
+
+func TestPropagateChecklistsToRun_ConcurrentRunChange(t *testing.T) {
+	base := []Checklist{{
+		Title: "Triage",
+		Items: []ChecklistItem{{Title: "Cehck logs"}, {Title: "Page on-call"}},
+	}}
+	target := Playbook{ID: "playbook1", Revision: 2, Checklists: []Checklist{base[0].Clone()}}
+	target.Checklists[0].Items[0].Title = "Check logs"
+
+	newRun := func() PlaybookRun {
+		return PlaybookRun{
+			ID:               "run1",
+			ChannelID:        "channel1",
+			PlaybookRevision: 1,
+			UpdateAt:         1000,
+			Checklists: []Checklist{{
+				ID:    "run-checklist",
+				Title: "Triage",
+				Items: []ChecklistItem{
+					{ID: "run-item-1", Title: "Cehck logs"},
+					{ID: "run-item-2", Title: "Page on-call"},
+				},
+			}},
+		}
+	}
+	newService := func(t *testing.T, store PlaybookRunStore) *PlaybookRunServiceImpl {
+		api := &plugintest.API{}
+		api.On("GetChannelMembersByIds", mock.Anything, mock.Anything).Return(model.ChannelMembers{}, nil)
+		poster := mock_bot.NewMockPoster(gomock.NewController(t))
+		poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), "channel1").AnyTimes()
+
+		return &PlaybookRunServiceImpl{
+			store:         store,
+			configService: staticConfigService{},
+			poster:        poster,
+			pluginAPI:     pluginapi.NewClient(api, &plugintest.Driver{}),
+		}
+	}
+
+	t.Run("merge is retried on the run changed since it was read", func(t *testing.T) {
+		reads := 0
+		store := &concurrentRunStore{run: newRun(), modify: func(run *PlaybookRun) bool {
+			// The step is closed once, between the first read and write.
+			reads++
+			if reads > 1 {
+				return false
+			}
+			run.Checklists[0].Items[1].State = ChecklistItemStateClosed
+			return true
+		}}
+
+		report := newService(t, store).propagateChecklistsToRun("run1", target, map[int64][]Checklist{1: base}, false)
+
+		assert.Empty(t, report.Error)
+		require.Len(t, report.Updated, 1)
+		assert.Equal(t, 2, store.updates)
+		assert.Equal(t, "Check logs", store.run.Checklists[0].Items[0].Title)
+		assert.Equal(t, ChecklistItemStateClosed, store.run.Checklists[0].Items[1].State)
+	})
+
+	t.Run("run that keeps changing is skipped", func(t *testing.T) {
+		store := &concurrentRunStore{run: newRun(), modify: func(*PlaybookRun) bool { return true }}
+
+		report := newService(t, store).propagateChecklistsToRun("run1", target, map[int64][]Checklist{1: base}, false)
+
+		assert.Equal(t, "run was modified concurrently, skipped", report.Error)
+		assert.Equal(t, maxChecklistPropagationAttempts, store.updates)
+		assert.Equal(t, "Cehck logs", store.run.Checklists[0].Items[0].Title)
+	})
+
+	t.Run("propagating twice adds new items once", func(t *testing.T) {
+		withNewItem := Playbook{ID: "playbook1", Revision: 2, Checklists: []Checklist{base[0].Clone()}}
+		withNewItem.Checklists[0].Items = append(withNewItem.Checklists[0].Items, ChecklistItem{ID: "playbook-item-3", Title: "Open incident doc"})
+		store := &concurrentRunStore{run: newRun()}
+		service := newService(t, store)
+
+		report := service.propagateChecklistsToRun("run1", withNewItem, map[int64][]Checklist{1: base, 2: withNewItem.Checklists}, false)
+		require.Empty(t, report.Error)
+		require.Len(t, report.Added, 1)
+		assert.Equal(t, int64(2), store.run.PlaybookRevision)
+
+		report = service.propagateChecklistsToRun("run1", withNewItem, map[int64][]Checklist{1: base, 2: withNewItem.Checklists}, false)
+		require.Empty(t, report.Error)
+		assert.False(t, report.HasChanges())
+		assert.Equal(t, 1, store.updates)
+		assert.Len(t, store.run.Checklists[0].Items, 3)
+
+		// Even merged from the revision the run was created from, the added item is not added again.
+		merged, merge := MergePlaybookChecklists(base, withNewItem.Checklists, store.run.Checklists, 0)
+		assert.Empty(t, merge.Added)
+		assert.Len(t, merged[0].Items, 3)
+	})
+}
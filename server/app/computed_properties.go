@@ -0,0 +1,490 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Kinds of computed property fields.
+const (
+	// ComputedKindFormula evaluates an arithmetic expression over other fields.
+	ComputedKindFormula = "formula"
+	// ComputedKindDurationSinceCreation counts the whole minutes since the run was created,
+	// stopping when the run finishes.
+	ComputedKindDurationSinceCreation = "duration_since_creation"
+	// ComputedKindOpenItemsCount counts the open and in progress items of a checklist.
+	ComputedKindOpenItemsCount = "open_items_count"
+	// ComputedKindLookup maps the value of another field to a number.
+	ComputedKindLookup = "lookup"
+)
+
+const (
+	// MaxComputedExpressionLength bounds the length of a formula.
+	MaxComputedExpressionLength = 1024
+	// MaxComputedLookupEntries bounds the number of entries of a lookup table.
+	MaxComputedLookupEntries = 100
+)
+
+// ComputedAttrs describes how the value of a computed field is derived. Computed fields are
+// number fields whose value is maintained by the property service and cannot be set directly.
+type ComputedAttrs struct {
+	Kind string `json:"kind"`
+
+	// Expression is the formula of formula fields. It supports numbers, + - * /, parentheses and
+	// references to number, date and other computed fields by name in braces, such as
+	// "{Users affected} * {Minutes down}". Dates are read as milliseconds since the epoch.
+	Expression string `json:"expression,omitempty"`
+
+	// Checklist is the title of the checklist counted by open_items_count fields. All
+	// checklists are counted when it is empty.
+	Checklist string `json:"checklist,omitempty"`
+
+	// SourceField is the name of the field read by lookup fields, and Lookup maps its values
+	// (option names for select fields) to numbers.
+	SourceField string             `json:"source_field,omitempty"`
+	Lookup      map[string]float64 `json:"lookup,omitempty"`
+}
+
+// IsComputed returns true for fields whose value is derived rather than set by users.
+func (p *PropertyField) IsComputed() bool {
+	return p.Attrs.Computed != nil
+}
+
+// sanitizeAndValidate normalizes the spec of a computed field, clearing the parameters the kind
+// does not use.
+func (c *ComputedAttrs) sanitizeAndValidate() error {
+	c.Kind = strings.TrimSpace(c.Kind)
+	c.Expression = strings.TrimSpace(c.Expression)
+	c.Checklist = strings.TrimSpace(c.Checklist)
+	c.SourceField = strings.TrimSpace(c.SourceField)
+
+	switch c.Kind {
+	case ComputedKindFormula:
+		if c.Expression == "" {
+			return errors.New("formula fields require an expression")
+		}
+		if len(c.Expression) > MaxComputedExpressionLength {
+			return errors.Errorf("formula must be at most %d characters", MaxComputedExpressionLength)
+		}
+		if _, err := parseFormula(c.Expression); err != nil {
+			return errors.Wrap(err, "invalid formula")
+		}
+		c.Checklist, c.SourceField, c.Lookup = "", "", nil
+	case ComputedKindDurationSinceCreation:
+		c.Expression, c.Checklist, c.SourceField, c.Lookup = "", "", "", nil
+	case ComputedKindOpenItemsCount:
+		c.Expression, c.SourceField, c.Lookup = "", "", nil
+	case ComputedKindLookup:
+		if c.SourceField == "" {
+			return errors.New("lookup fields require a source field")
+		}
+		if len(c.Lookup) == 0 {
+			return errors.New("lookup fields require at least one entry")
+		}
+		if len(c.Lookup) > MaxComputedLookupEntries {
+			return errors.Errorf("lookup fields support at most %d entries", MaxComputedLookupEntries)
+		}
+		for key, value := range c.Lookup {
+			if strings.TrimSpace(key) == "" {
+				return errors.New("lookup keys must not be empty")
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return errors.Errorf("lookup value for %q must be finite", key)
+			}
+		}
+		c.Expression, c.Checklist = "", ""
+	default:
+		return errors.Errorf("unknown computed field kind %q", c.Kind)
+	}
+
+	return nil
+}
+
+// ComputePropertyValues evaluates the computed fields among fields. values holds the current
+// values keyed by field ID, and checklists, createAt and endAt describe the run; now is used for
+// durations of runs that have not ended. The result is keyed by field ID and holds a JSON number,
+// or null when the inputs of a field are missing or invalid.
+func ComputePropertyValues(fields []PropertyField, values map[string]json.RawMessage, checklists []Checklist, createAt, endAt, now int64) map[string]json.RawMessage {
+	ctx := &computeContext{
+		fieldsByName: make(map[string]*PropertyField, len(fields)),
+		values:       values,
+		checklists:   checklists,
+		createAt:     createAt,
+		endAt:        endAt,
+		now:          now,
+		results:      make(map[string]computedResult),
+		visiting:     make(map[string]bool),
+	}
+	for i := range fields {
+		if fields[i].DeleteAt != 0 {
+			continue
+		}
+		name := strings.ToLower(fields[i].Name)
+		if _, exists := ctx.fieldsByName[name]; !exists {
+			ctx.fieldsByName[name] = &fields[i]
+		}
+	}
+
+	computed := make(map[string]json.RawMessage)
+	for i := range fields {
+		if fields[i].DeleteAt != 0 || !fields[i].IsComputed() {
+			continue
+		}
+		number, ok := ctx.compute(&fields[i])
+		if !ok {
+			computed[fields[i].ID] = json.RawMessage("null")
+			continue
+		}
+		raw, err := json.Marshal(number)
+		if err != nil {
+			computed[fields[i].ID] = json.RawMessage("null")
+			continue
+		}
+		computed[fields[i].ID] = raw
+	}
+	return computed
+}
+
+// ComputePropertyValues evaluates the run's computed fields. The run must have been loaded with
+// its property fields and values.
+func (r *PlaybookRun) ComputePropertyValues(now int64) map[string]json.RawMessage {
+	values := make(map[string]json.RawMessage, len(r.PropertyValues))
+	for _, value := range r.PropertyValues {
+		values[value.FieldID] = value.Value
+	}
+	return ComputePropertyValues(r.PropertyFields, values, r.Checklists, r.CreateAt, r.EndAt, now)
+}
+
+type computedResult struct {
+	value float64
+	ok    bool
+}
+
+type computeContext struct {
+	fieldsByName map[string]*PropertyField
+	values       map[string]json.RawMessage
+	checklists   []Checklist
+	createAt     int64
+	endAt        int64
+	now          int64
+
+	results  map[string]computedResult
+	visiting map[string]bool
+}
+
+// number reads the numeric value of a field, computing it first for computed fields.
+func (c *computeContext) number(field *PropertyField) (float64, bool) {
+	if field.IsComputed() {
+		return c.compute(field)
+	}
+	return orderedValue(*field, c.values[field.ID])
+}
+
+// compute evaluates a computed field once, treating fields that depend on themselves as having
+// no value.
+func (c *computeContext) compute(field *PropertyField) (float64, bool) {
+	if result, ok := c.results[field.ID]; ok {
+		return result.value, result.ok
+	}
+	if c.visiting[field.ID] {
+		return 0, false
+	}
+	c.visiting[field.ID] = true
+	defer delete(c.visiting, field.ID)
+
+	value, ok := c.evaluate(field)
+	if ok && (math.IsNaN(value) || math.IsInf(value, 0)) {
+		ok = false
+	}
+	c.results[field.ID] = computedResult{value: value, ok: ok}
+	return value, ok
+}
+
+func (c *computeContext) evaluate(field *PropertyField) (float64, bool) {
+	spec := field.Attrs.Computed
+	switch spec.Kind {
+	case ComputedKindFormula:
+		f, err := parseFormula(spec.Expression)
+		if err != nil {
+			return 0, false
+		}
+		return f.eval(func(name string) (float64, bool) {
+			ref, ok := c.fieldsByName[strings.ToLower(name)]
+			if !ok {
+				return 0, false
+			}
+			return c.number(ref)
+		})
+	case ComputedKindDurationSinceCreation:
+		end := c.endAt
+		if end == 0 {
+			end = c.now
+		}
+		if c.createAt == 0 || end < c.createAt {
+			return 0, true
+		}
+		return math.Floor(float64(end-c.createAt) / 60000), true
+	case ComputedKindOpenItemsCount:
+		count := 0
+		for _, checklist := range c.checklists {
+			if spec.Checklist != "" && !strings.EqualFold(strings.TrimSpace(checklist.Title), spec.Checklist) {
+				continue
+			}
+			for _, item := range checklist.Items {
+				if item.ConditionAction == ConditionActionHidden {
+					continue
+				}
+				if item.State == ChecklistItemStateOpen || item.State == ChecklistItemStateInProgress {
+					count++
+				}
+			}
+		}
+		return float64(count), true
+	case ComputedKindLookup:
+		source, ok := c.fieldsByName[strings.ToLower(spec.SourceField)]
+		if !ok {
+			return 0, false
+		}
+		key, ok := c.lookupKey(source)
+		if !ok {
+			return 0, false
+		}
+		if value, ok := spec.Lookup[key]; ok {
+			return value, true
+		}
+		for k, value := range spec.Lookup {
+			if strings.EqualFold(k, key) {
+				return value, true
+			}
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+// lookupKey returns the value of a lookup source field as a string: the option name for select
+// fields, the text for text fields and the formatted number for numeric fields.
+func (c *computeContext) lookupKey(source *PropertyField) (string, bool) {
+	if source.IsComputed() || source.IsNumber() {
+		number, ok := c.number(source)
+		if !ok {
+			return "", false
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), true
+	}
+
+	var str string
+	if err := json.Unmarshal(c.values[source.ID], &str); err != nil {
+		return "", false
+	}
+	if source.SupportsOptions() {
+		for _, option := range source.Attrs.Options {
+			if option.GetID() == str {
+				return option.GetName(), true
+			}
+		}
+		return "", false
+	}
+	str = strings.TrimSpace(str)
+	return str, str != ""
+}
+
+// formulaNode is a node of a parsed formula.
+type formulaNode interface {
+	eval(resolve func(name string) (float64, bool)) (float64, bool)
+}
+
+type formulaNumber float64
+
+func (n formulaNumber) eval(func(string) (float64, bool)) (float64, bool) {
+	return float64(n), true
+}
+
+type formulaReference string
+
+func (r formulaReference) eval(resolve func(string) (float64, bool)) (float64, bool) {
+	return resolve(string(r))
+}
+
+type formulaNegation struct {
+	operand formulaNode
+}
+
+func (n formulaNegation) eval(resolve func(string) (float64, bool)) (float64, bool) {
+	value, ok := n.operand.eval(resolve)
+	return -value, ok
+}
+
+type formulaBinary struct {
+	operator    byte
+	left, right formulaNode
+}
+
+func (b formulaBinary) eval(resolve func(string) (float64, bool)) (float64, bool) {
+	left, ok := b.left.eval(resolve)
+	if !ok {
+		return 0, false
+	}
+	right, ok := b.right.eval(resolve)
+	if !ok {
+		return 0, false
+	}
+
+	switch b.operator {
+	case '+':
+		return left + right, true
+	case '-':
+		return left - right, true
+	case '*':
+		return left * right, true
+	case '/':
+		if right == 0 {
+			return 0, false
+		}
+		return left / right, true
+	default:
+		return 0, false
+	}
+}
+
+// parseFormula parses an arithmetic expression with the usual precedence:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = "-" factor | number | "{" name "}" | "(" expr ")"
+func parseFormula(expression string) (formulaNode, error) {
+	p := &formulaParser{input: expression}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, errors.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	return node, nil
+}
+
+// maxFormulaDepth bounds the nesting of parentheses and negations.
+const maxFormulaDepth = 64
+
+type formulaParser struct {
+	input string
+	pos   int
+	depth int
+}
+
+func (p *formulaParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *formulaParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *formulaParser) parseExpr() (formulaNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := p.peek()
+		if operator != '+' && operator != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = formulaBinary{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *formulaParser) parseTerm() (formulaNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := p.peek()
+		if operator != '*' && operator != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = formulaBinary{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *formulaParser) parseFactor() (formulaNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxFormulaDepth {
+		return nil, errors.New("formula is nested too deeply")
+	}
+
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, errors.New("unexpected end of formula")
+	case c == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return formulaNegation{operand: operand}, nil
+	case c == '(':
+		p.pos++
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, errors.Errorf("missing closing parenthesis at position %d", p.pos)
+		}
+		p.pos++
+		return node, nil
+	case c == '{':
+		end := strings.IndexByte(p.input[p.pos:], '}')
+		if end < 0 {
+			return nil, errors.Errorf("unterminated field reference at position %d", p.pos)
+		}
+		name := strings.TrimSpace(p.input[p.pos+1 : p.pos+end])
+		if name == "" {
+			return nil, errors.Errorf("empty field reference at position %d", p.pos)
+		}
+		p.pos += end + 1
+		return formulaReference(name), nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q", p.input[start:p.pos])
+		}
+		return formulaNumber(number), nil
+	default:
+		return nil, errors.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+}
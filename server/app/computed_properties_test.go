@@ -0,0 +1,206 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormula(t *testing.T) {
+	values := map[string]float64{"Users affected": 120, "Minutes down": 15, "Zero": 0}
+	resolve := func(name string) (float64, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+
+	testCases := []struct {
+		expression string
+		expected   float64
+		ok         bool
+	}{
+		{"1 + 2 * 3", 7, true},
+		{"(1 + 2) * 3", 9, true},
+		{"10 / 4 - 1", 1.5, true},
+		{"-3 + -(2 * -2)", 1, true},
+		{".5 * 4", 2, true},
+		{"{Users affected} * {Minutes down}", 1800, true},
+		{"{ Users affected } / 60", 2, true},
+		{"{Users affected} / {Zero}", 0, false},
+		{"{Unknown} + 1", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			formula, err := parseFormula(tc.expression)
+			require.NoError(t, err)
+
+			value, ok := formula.eval(resolve)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.InDelta(t, tc.expected, value, 1e-9)
+			}
+		})
+	}
+
+	t.Run("rejects invalid formulas", func(t *testing.T) {
+		for _, expression := range []string{"", "1 +", "(1 + 2", "1 2", "{}", "{Unterminated", "1.2.3", "a + 1", "2 ^ 3"} {
+			_, err := parseFormula(expression)
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestComputePropertyValues(t *testing.T) {
+	severity := PropertyField{
+		PropertyField: model.PropertyField{ID: "severity", Name: "Severity", Type: model.PropertyFieldTypeSelect},
+		Attrs: Attrs{Options: model.PropertyOptions[*model.PluginPropertyOption]{
+			model.NewPluginPropertyOption("opt_high", "High"),
+			model.NewPluginPropertyOption("opt_low", "Low"),
+		}},
+	}
+	users := PropertyField{
+		PropertyField: model.PropertyField{ID: "users", Name: "Users affected", Type: model.PropertyFieldTypeText},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+	}
+	computedField := func(id, name string, computed ComputedAttrs) PropertyField {
+		return PropertyField{
+			PropertyField: model.PropertyField{ID: id, Name: name, Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Computed: &computed},
+		}
+	}
+
+	fields := []PropertyField{
+		severity,
+		users,
+		computedField("sla", "SLA minutes", ComputedAttrs{Kind: ComputedKindLookup, SourceField: "severity", Lookup: map[string]float64{"high": 30, "Low": 240}}),
+		computedField("budget", "Budget", ComputedAttrs{Kind: ComputedKindFormula, Expression: "{SLA minutes} * {Users affected}"}),
+		computedField("age", "Age", ComputedAttrs{Kind: ComputedKindDurationSinceCreation}),
+		computedField("open", "Open follow-ups", ComputedAttrs{Kind: ComputedKindOpenItemsCount, Checklist: "follow-ups"}),
+		computedField("all_open", "All open", ComputedAttrs{Kind: ComputedKindOpenItemsCount}),
+		computedField("loop_a", "Loop A", ComputedAttrs{Kind: ComputedKindFormula, Expression: "{Loop B} + 1"}),
+		computedField("loop_b", "Loop B", ComputedAttrs{Kind: ComputedKindFormula, Expression: "{Loop A} + 1"}),
+	}
+	checklists := []Checklist{
+		{Title: "Triage", Items: []ChecklistItem{{State: ChecklistItemStateOpen}, {State: ChecklistItemStateClosed}}},
+		{Title: "Follow-ups", Items: []ChecklistItem{
+			{State: ChecklistItemStateOpen},
+			{State: ChecklistItemStateInProgress},
+			{State: ChecklistItemStateSkipped},
+			{State: ChecklistItemStateOpen, ConditionAction: ConditionActionHidden},
+		}},
+	}
+	values := map[string]json.RawMessage{
+		"severity": json.RawMessage(`"opt_high"`),
+		"users":    json.RawMessage(`"12"`),
+	}
+
+	createAt := int64(1_700_000_000_000)
+	computed := ComputePropertyValues(fields, values, checklists, createAt, 0, createAt+95*60*1000+59*1000)
+
+	assert.JSONEq(t, `30`, string(computed["sla"]))
+	assert.JSONEq(t, `360`, string(computed["budget"]))
+	assert.JSONEq(t, `95`, string(computed["age"]))
+	assert.JSONEq(t, `2`, string(computed["open"]))
+	assert.JSONEq(t, `3`, string(computed["all_open"]))
+	assert.JSONEq(t, `null`, string(computed["loop_a"]))
+	assert.JSONEq(t, `null`, string(computed["loop_b"]))
+	assert.NotContains(t, computed, "severity")
+	assert.NotContains(t, computed, "users")
+
+	t.Run("duration stops when the run ends", func(t *testing.T) {
+		computed := ComputePropertyValues(fields, values, checklists, createAt, createAt+10*60*1000, createAt+500*60*1000)
+		assert.JSONEq(t, `10`, string(computed["age"]))
+	})
+
+	t.Run("missing inputs produce null", func(t *testing.T) {
+		computed := ComputePropertyValues(fields, map[string]json.RawMessage{"severity": json.RawMessage(`"opt_unknown"`)}, nil, createAt, 0, createAt)
+		assert.JSONEq(t, `null`, string(computed["sla"]))
+		assert.JSONEq(t, `null`, string(computed["budget"]))
+		assert.JSONEq(t, `0`, string(computed["open"]))
+	})
+}
+
+func TestPropertyField_SanitizeAndValidate_Computed(t *testing.T) {
+	newField := func(fieldType model.PropertyFieldType, computed ComputedAttrs) PropertyField {
+		minimum := 1.0
+		return PropertyField{
+			PropertyField: model.PropertyField{Name: "Computed", Type: fieldType},
+			Attrs:         Attrs{Min: &minimum, Integer: true, RequiredOnCreate: true, RequiredOnFinish: true, Computed: &computed},
+		}
+	}
+
+	t.Run("normalizes computed fields to read-only numbers", func(t *testing.T) {
+		field := newField(model.PropertyFieldTypeText, ComputedAttrs{Kind: ComputedKindFormula, Expression: " {A} + 1 ", SourceField: "B", Checklist: "C"})
+		require.NoError(t, field.SanitizeAndValidate())
+
+		assert.True(t, field.IsComputed())
+		assert.True(t, field.IsNumber())
+		assert.Nil(t, field.Attrs.Min)
+		assert.False(t, field.Attrs.Integer)
+		assert.False(t, field.Attrs.RequiredOnCreate)
+		assert.False(t, field.Attrs.RequiredOnFinish)
+		assert.Equal(t, &ComputedAttrs{Kind: ComputedKindFormula, Expression: "{A} + 1"}, field.Attrs.Computed)
+	})
+
+	t.Run("rejects invalid specs", func(t *testing.T) {
+		for name, field := range map[string]PropertyField{
+			"non-text field":       newField(model.PropertyFieldTypeSelect, ComputedAttrs{Kind: ComputedKindDurationSinceCreation}),
+			"unknown kind":         newField(model.PropertyFieldTypeText, ComputedAttrs{Kind: "average"}),
+			"invalid formula":      newField(model.PropertyFieldTypeText, ComputedAttrs{Kind: ComputedKindFormula, Expression: "{A} +"}),
+			"lookup without field": newField(model.PropertyFieldTypeText, ComputedAttrs{Kind: ComputedKindLookup, Lookup: map[string]float64{"High": 1}}),
+			"empty lookup":         newField(model.PropertyFieldTypeText, ComputedAttrs{Kind: ComputedKindLookup, SourceField: "Severity"}),
+		} {
+			assert.Error(t, field.SanitizeAndValidate(), name)
+		}
+	})
+
+	t.Run("computed spec survives the round trip through the plugin API", func(t *testing.T) {
+		field := newField(model.PropertyFieldTypeText, ComputedAttrs{Kind: ComputedKindLookup, SourceField: "Severity", Lookup: map[string]float64{"High": 30}})
+		require.NoError(t, field.SanitizeAndValidate())
+
+		converted, err := NewPropertyFieldFromMattermostPropertyField(field.ToMattermostPropertyField())
+		require.NoError(t, err)
+		assert.Equal(t, field.Attrs.Computed, converted.Attrs.Computed)
+	})
+}
+
+// stubComputedRunPropertyService serves the property fields of a run for GetPlaybookRun.
+type stubComputedRunPropertyService struct {
+	stubUpsertPropertyService
+	fields []PropertyField
+}
+
+func (s *stubComputedRunPropertyService) GetRunPropertyFields(string) ([]PropertyField, error) {
+	return s.fields, nil
+}
+
+func (s *stubComputedRunPropertyService) GetRunPropertyValues(string) ([]PropertyValue, error) {
+	return nil, nil
+}
+
+func TestSetRunPropertyValue_ComputedFieldIsReadOnly(t *testing.T) {
+	runID := model.NewId()
+	fieldID := model.NewId()
+	propertyService := &stubComputedRunPropertyService{fields: []PropertyField{{
+		PropertyField: model.PropertyField{ID: fieldID, Name: "Age", Type: model.PropertyFieldTypeText, TargetType: PropertyTargetTypeRun, TargetID: runID},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Computed: &ComputedAttrs{Kind: ComputedKindDurationSinceCreation}},
+	}}}
+	svc := &PlaybookRunServiceImpl{
+		store:           &stubRunStoreGetOnly{run: &PlaybookRun{ID: runID}},
+		licenseChecker:  &allocLicenseCheckerWithAttributes{},
+		propertyService: propertyService,
+	}
+
+	_, err := svc.SetRunPropertyValue("user1", runID, fieldID, json.RawMessage(`12`))
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrPropertyFieldReadOnly))
+	assert.Zero(t, propertyService.upsertCalled)
+}
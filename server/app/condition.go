@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
@@ -37,6 +39,10 @@ type ConditionExprV1 struct {
 
 	Is    *ComparisonCondition `json:"is,omitempty"`
 	IsNot *ComparisonCondition `json:"isNot,omitempty"`
+
+	// GreaterThan and LessThan compare number and date fields.
+	GreaterThan *ComparisonCondition `json:"gt,omitempty"`
+	LessThan    *ComparisonCondition `json:"lt,omitempty"`
 }
 
 type ComparisonCondition struct {
@@ -87,6 +93,14 @@ func (c *ConditionExprV1) Sanitize() {
 	if c.IsNot != nil {
 		c.IsNot.Sanitize()
 	}
+
+	if c.GreaterThan != nil {
+		c.GreaterThan.Sanitize()
+	}
+
+	if c.LessThan != nil {
+		c.LessThan.Sanitize()
+	}
 }
 
 func (c *ConditionExprV1) evaluate(fieldMap map[string]PropertyField, valueMap map[string]PropertyValue) bool {
@@ -130,6 +144,22 @@ func (c *ConditionExprV1) evaluate(fieldMap map[string]PropertyField, valueMap m
 		return isNot(field, value, c.IsNot.Value)
 	}
 
+	if c.GreaterThan != nil {
+		field, fieldExists := fieldMap[c.GreaterThan.FieldID]
+		if !fieldExists {
+			return false
+		}
+		return compare(field, valueMap[c.GreaterThan.FieldID], c.GreaterThan.Value) == 1
+	}
+
+	if c.LessThan != nil {
+		field, fieldExists := fieldMap[c.LessThan.FieldID]
+		if !fieldExists {
+			return false
+		}
+		return compare(field, valueMap[c.LessThan.FieldID], c.LessThan.Value) == -1
+	}
+
 	return true
 }
 
@@ -180,12 +210,26 @@ func (c *ConditionExprV1) validate(currentDepth int, propertyFields []PropertyFi
 		}
 	}
 
+	if c.GreaterThan != nil {
+		conditionCount++
+		if err := c.GreaterThan.validateOrdered(propertyFields); err != nil {
+			return err
+		}
+	}
+
+	if c.LessThan != nil {
+		conditionCount++
+		if err := c.LessThan.validateOrdered(propertyFields); err != nil {
+			return err
+		}
+	}
+
 	if conditionCount == 0 {
-		return errors.New("condition must have at least one operation (and, or, is, isNot)")
+		return errors.New("condition must have at least one operation (and, or, is, isNot, gt, lt)")
 	}
 
 	if conditionCount > 1 {
-		return errors.New("condition can only have one operation (and, or, is, isNot)")
+		return errors.New("condition can only have one operation (and, or, is, isNot, gt, lt)")
 	}
 
 	return nil
@@ -207,6 +251,32 @@ func (cc *ComparisonCondition) Validate(propertyFields []PropertyField) error {
 	return nil
 }
 
+// validateOrdered ensures a gt/lt comparison references a number or date field and compares it
+// against a value of the matching kind
+func (cc *ComparisonCondition) validateOrdered(propertyFields []PropertyField) error {
+	if cc.FieldID == "" {
+		return errors.New("field_id cannot be empty")
+	}
+
+	for _, field := range propertyFields {
+		if field.ID != cc.FieldID {
+			continue
+		}
+		if !field.IsNumber() && field.Type != model.PropertyFieldTypeDate {
+			return errors.New("gt and lt conditions require a number or date field")
+		}
+		if _, ok := orderedValue(field, cc.Value); !ok {
+			if field.IsNumber() {
+				return errors.New("number field condition value must be a number")
+			}
+			return errors.New("date field condition value must be a date or a millisecond timestamp")
+		}
+		return nil
+	}
+
+	return nil
+}
+
 // Sanitize trims whitespace from the comparison value
 func (cc *ComparisonCondition) Sanitize() {
 	var stringValue string
@@ -228,6 +298,13 @@ func (cc *ComparisonCondition) Auditable() map[string]any {
 func (cc *ComparisonCondition) validateValueForFieldType(field PropertyField) error {
 	switch field.Type {
 	case model.PropertyFieldTypeText:
+		if field.IsNumber() {
+			var number float64
+			if err := json.Unmarshal(cc.Value, &number); err != nil {
+				return errors.New("number field condition value must be a number")
+			}
+			return nil
+		}
 		var stringValue string
 		if err := json.Unmarshal(cc.Value, &stringValue); err != nil {
 			return errors.New("text field condition value must be a string")
@@ -293,11 +370,16 @@ func (cc *ComparisonCondition) validateValueForFieldType(field PropertyField) er
 
 // is checks if a property value matches the condition value based on the field type.
 // For text fields: condition value is a string, performs case-insensitive comparison using strings.EqualFold.
+// For number fields: condition value is a number, compared numerically.
 // For select fields: condition value is an array, checks if the property value is any of the condition values.
 // For multiselect fields: condition value is an array, checks if any condition value is in the property array.
 func is(propertyField PropertyField, propertyValue PropertyValue, conditionValue json.RawMessage) bool {
 	switch propertyField.Type {
 	case model.PropertyFieldTypeText:
+		if propertyField.IsNumber() {
+			return compare(propertyField, propertyValue, conditionValue) == 0
+		}
+
 		var conditionString string
 		if err := json.Unmarshal(conditionValue, &conditionString); err != nil {
 			return false
@@ -354,6 +436,73 @@ func isNot(propertyField PropertyField, propertyValue PropertyValue, conditionVa
 	return !is(propertyField, propertyValue, conditionValue)
 }
 
+// compare orders the value of a number or date field against the condition value, returning -1, 0
+// or 1. Missing or unparsable values return compareUnordered, which never matches.
+func compare(propertyField PropertyField, propertyValue PropertyValue, conditionValue json.RawMessage) int {
+	value, ok := orderedValue(propertyField, propertyValue.Value)
+	if !ok {
+		return compareUnordered
+	}
+	target, ok := orderedValue(propertyField, conditionValue)
+	if !ok {
+		return compareUnordered
+	}
+	switch {
+	case value < target:
+		return -1
+	case value > target:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareUnordered is returned by compare when either side cannot be ordered.
+const compareUnordered = 2
+
+// orderedValue converts a raw number or date value to a float64 so it can be compared. Dates are
+// converted to milliseconds since the epoch. Numeric strings stored before a field became a number
+// field are accepted.
+func orderedValue(field PropertyField, raw json.RawMessage) (float64, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, false
+	}
+
+	if field.Type == model.PropertyFieldTypeDate {
+		normalized, err := normalizeDateValue(raw)
+		if err != nil {
+			return 0, false
+		}
+		var dateString string
+		if err := json.Unmarshal(normalized, &dateString); err != nil {
+			return 0, false
+		}
+		t, err := time.Parse(time.RFC3339, dateString)
+		if err != nil {
+			return 0, false
+		}
+		return float64(t.UnixMilli()), true
+	}
+
+	if !field.IsNumber() {
+		return 0, false
+	}
+
+	var number float64
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number, true
+	}
+	var numberString string
+	if err := json.Unmarshal(raw, &numberString); err != nil {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(numberString), 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
 // ToString returns a human-readable string representation of the condition
 func (c *ConditionExprV1) ToString(propertyFields []PropertyField) string {
 	fieldMap := make(map[string]PropertyField)
@@ -410,6 +559,14 @@ func (c *ConditionExprV1) Auditable() map[string]any {
 		result["isNot"] = c.IsNot.Auditable()
 	}
 
+	if c.GreaterThan != nil {
+		result["gt"] = c.GreaterThan.Auditable()
+	}
+
+	if c.LessThan != nil {
+		result["lt"] = c.LessThan.Auditable()
+	}
+
 	return result
 }
 
@@ -443,6 +600,16 @@ func (c *ConditionExprV1) SwapPropertyIDs(propertyMappings *PropertyCopyResult)
 		}
 	}
 
+	// Handle ordered comparisons
+	for _, cc := range []*ComparisonCondition{c.GreaterThan, c.LessThan} {
+		if cc == nil {
+			continue
+		}
+		if err := cc.SwapPropertyIDs(propertyMappings); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -469,6 +636,14 @@ func (c *ConditionExprV1) extractIDs(fieldIDSet map[string]struct{}, optionsIDSe
 		fieldIDSet[c.IsNot.FieldID] = struct{}{}
 		c.IsNot.extractOptionsIDs(optionsIDSet)
 	}
+
+	if c.GreaterThan != nil {
+		fieldIDSet[c.GreaterThan.FieldID] = struct{}{}
+	}
+
+	if c.LessThan != nil {
+		fieldIDSet[c.LessThan.FieldID] = struct{}{}
+	}
 }
 
 // SwapPropertyIDs translates field and option IDs in the comparison condition
@@ -578,10 +753,27 @@ func (c *ConditionExprV1) toString(fieldMap map[string]PropertyField, needsParen
 		return c.IsNot.toString(fieldMap, true)
 	}
 
+	if c.GreaterThan != nil {
+		return c.GreaterThan.toStringWithOperator(fieldMap, ">")
+	}
+
+	if c.LessThan != nil {
+		return c.LessThan.toStringWithOperator(fieldMap, "<")
+	}
+
 	return ""
 }
 
 func (cc *ComparisonCondition) toString(fieldMap map[string]PropertyField, isNot bool) string {
+	operator := "is"
+	if isNot {
+		operator = "is not"
+	}
+
+	return cc.toStringWithOperator(fieldMap, operator)
+}
+
+func (cc *ComparisonCondition) toStringWithOperator(fieldMap map[string]PropertyField, operator string) string {
 	field, exists := fieldMap[cc.FieldID]
 	var fieldName string
 	if exists && field.Name != "" {
@@ -590,11 +782,6 @@ func (cc *ComparisonCondition) toString(fieldMap map[string]PropertyField, isNot
 		fieldName = cc.FieldID
 	}
 
-	operator := "is"
-	if isNot {
-		operator = "is not"
-	}
-
 	valueStr := cc.formatValue(field, exists)
 	return fmt.Sprintf(`"%s" %s %s`, fieldName, operator, valueStr)
 }
@@ -606,7 +793,13 @@ func (cc *ComparisonCondition) formatValue(field PropertyField, fieldExists bool
 
 	switch field.Type {
 	case model.PropertyFieldTypeText:
+		if field.IsNumber() {
+			return string(cc.Value)
+		}
 		return cc.formatTextValue()
+	case model.PropertyFieldTypeDate:
+		formatted, _ := DefaultFormatPropertyValue(&field, cc.Value)
+		return formatted
 	case model.PropertyFieldTypeSelect:
 		return cc.formatSelectValue(field)
 	case model.PropertyFieldTypeMultiselect:
@@ -783,7 +976,7 @@ type ConditionService interface {
 	GetRunConditions(userID, playbookID, runID string, page, perPage int) (*GetConditionsResults, error)
 
 	// Copy conditions from playbook to run with field ID mappings, returns old condition ID to new condition mapping
-	CopyPlaybookConditionsToRun(playbookID, runID string, propertyMappings *PropertyCopyResult) (map[string]*Condition, error)
+	CopyPlaybookConditionsToRun(playbookID, runID string, playbookConditions []Condition, propertyMappings *PropertyCopyResult) (map[string]*Condition, error)
 
 	// Copy conditions from playbook to playbook with field ID mappings, returns old condition ID to new condition mapping
 	CopyPlaybookConditionsToPlaybook(sourcePlaybookID, targetPlaybookID string, propertyMappings *PropertyCopyResult) (map[string]*Condition, error)
@@ -35,14 +35,9 @@ func NewConditionService(store ConditionStore, propertyService PropertyService,
 	}
 }
 
-// CopyPlaybookConditionsToRun copies conditions from a playbook to a run, translating field IDs
-func (s *conditionService) CopyPlaybookConditionsToRun(playbookID, runID string, propertyMappings *PropertyCopyResult) (map[string]*Condition, error) {
-	// Get all conditions for the playbook
-	playbookConditions, err := s.store.GetPlaybookConditions(playbookID, 0, MaxConditionsPerPlaybook)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get playbook conditions")
-	}
-
+// CopyPlaybookConditionsToRun copies the given conditions of a playbook to a run, translating field
+// IDs. The conditions may be those of a revision rather than the playbook's current ones.
+func (s *conditionService) CopyPlaybookConditionsToRun(playbookID, runID string, playbookConditions []Condition, propertyMappings *PropertyCopyResult) (map[string]*Condition, error) {
 	// Map from old condition ID to new copied condition
 	conditionMapping := make(map[string]*Condition)
 	if len(playbookConditions) == 0 {
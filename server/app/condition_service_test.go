@@ -418,10 +418,6 @@ func TestConditionService_CopyPlaybookConditionsToRun(t *testing.T) {
 	}
 
 	t.Run("success copy conditions", func(t *testing.T) {
-		mockStore.EXPECT().
-			GetPlaybookConditions(playbookID, 0, app.MaxConditionsPerPlaybook).
-			Return(playbookConditions, nil)
-
 		newConditionID1 := model.NewId()
 		newConditionID2 := model.NewId()
 
@@ -441,7 +437,7 @@ func TestConditionService_CopyPlaybookConditionsToRun(t *testing.T) {
 			}).
 			Times(2)
 
-		result, err := service.CopyPlaybookConditionsToRun(playbookID, runID, propertyMappings)
+		result, err := service.CopyPlaybookConditionsToRun(playbookID, runID, playbookConditions, propertyMappings)
 		require.NoError(t, err)
 		require.Len(t, result, 2)
 		require.Contains(t, result, conditionID1)
@@ -453,25 +449,10 @@ func TestConditionService_CopyPlaybookConditionsToRun(t *testing.T) {
 	})
 
 	t.Run("success with no playbook conditions", func(t *testing.T) {
-		mockStore.EXPECT().
-			GetPlaybookConditions(playbookID, 0, app.MaxConditionsPerPlaybook).
-			Return([]app.Condition{}, nil)
-
-		result, err := service.CopyPlaybookConditionsToRun(playbookID, runID, propertyMappings)
+		result, err := service.CopyPlaybookConditionsToRun(playbookID, runID, []app.Condition{}, propertyMappings)
 		require.NoError(t, err)
 		require.Empty(t, result)
 	})
-
-	t.Run("error getting playbook conditions", func(t *testing.T) {
-		mockStore.EXPECT().
-			GetPlaybookConditions(playbookID, 0, app.MaxConditionsPerPlaybook).
-			Return(nil, errors.New("database error"))
-
-		result, err := service.CopyPlaybookConditionsToRun(playbookID, runID, propertyMappings)
-		require.Error(t, err)
-		require.Nil(t, result)
-		require.Contains(t, err.Error(), "failed to get playbook conditions")
-	})
 }
 
 func TestConditionService_EvaluateConditionsOnValueChanged(t *testing.T) {
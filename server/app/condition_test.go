@@ -1755,3 +1755,105 @@ func TestConditionEvaluationResult_AnythingAdded(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionExprV1_NumberAndDateComparisons(t *testing.T) {
+	numberField := PropertyField{
+		PropertyField: model.PropertyField{ID: "customers", Name: "Customers affected", Type: model.PropertyFieldTypeText},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+	}
+	dateField := PropertyField{
+		PropertyField: model.PropertyField{ID: "detected", Name: "Detected", Type: model.PropertyFieldTypeDate},
+	}
+	textField := PropertyField{
+		PropertyField: model.PropertyField{ID: "summary", Name: "Summary", Type: model.PropertyFieldTypeText},
+	}
+	fields := []PropertyField{numberField, dateField, textField}
+
+	values := []PropertyValue{
+		{FieldID: "customers", Value: json.RawMessage(`150`)},
+		{FieldID: "detected", Value: json.RawMessage(`"2024-03-15T12:00:00Z"`)},
+	}
+
+	t.Run("evaluate", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			expr     ConditionExprV1
+			expected bool
+		}{
+			{"number gt matches", ConditionExprV1{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`100`)}}, true},
+			{"number gt does not match equal", ConditionExprV1{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`150`)}}, false},
+			{"number lt matches", ConditionExprV1{LessThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`1000`)}}, true},
+			{"number is compares numerically", ConditionExprV1{Is: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`150.0`)}}, true},
+			{"number isNot compares numerically", ConditionExprV1{IsNot: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`151`)}}, true},
+			{"date gt matches", ConditionExprV1{GreaterThan: &ComparisonCondition{FieldID: "detected", Value: json.RawMessage(`"2024-03-01"`)}}, true},
+			{"date lt with millis", ConditionExprV1{LessThan: &ComparisonCondition{FieldID: "detected", Value: json.RawMessage(`1710000000000`)}}, false},
+			{"missing value never matches", ConditionExprV1{GreaterThan: &ComparisonCondition{FieldID: "summary", Value: json.RawMessage(`1`)}}, false},
+			{"unknown field never matches", ConditionExprV1{LessThan: &ComparisonCondition{FieldID: "unknown", Value: json.RawMessage(`1`)}}, false},
+			{"combined with and", ConditionExprV1{And: []ConditionExprV1{
+				{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`100`)}},
+				{LessThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`200`)}},
+			}}, true},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expected, tc.expr.Evaluate(fields, values))
+			})
+		}
+	})
+
+	t.Run("legacy string values of number fields are compared as numbers", func(t *testing.T) {
+		expr := ConditionExprV1{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`9`)}}
+		require.True(t, expr.Evaluate(fields, []PropertyValue{{FieldID: "customers", Value: json.RawMessage(`"10"`)}}))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		valid := []ConditionExprV1{
+			{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`10`)}},
+			{LessThan: &ComparisonCondition{FieldID: "detected", Value: json.RawMessage(`"2024-03-01"`)}},
+			{Is: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`10`)}},
+		}
+		for _, expr := range valid {
+			require.NoError(t, expr.Validate(fields))
+		}
+
+		invalid := []ConditionExprV1{
+			{GreaterThan: &ComparisonCondition{FieldID: "summary", Value: json.RawMessage(`10`)}},
+			{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`"ten"`)}},
+			{LessThan: &ComparisonCondition{FieldID: "detected", Value: json.RawMessage(`"yesterday"`)}},
+			{Is: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`"10"`)}},
+			{
+				GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`1`)},
+				LessThan:    &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`2`)},
+			},
+		}
+		for _, expr := range invalid {
+			require.Error(t, expr.Validate(fields))
+		}
+	})
+
+	t.Run("to string", func(t *testing.T) {
+		expr := ConditionExprV1{And: []ConditionExprV1{
+			{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`100`)}},
+			{LessThan: &ComparisonCondition{FieldID: "detected", Value: json.RawMessage(`"2024-03-01T00:00:00Z"`)}},
+		}}
+		require.Equal(t, `"Customers affected" > 100 AND "Detected" < 2024-03-01`, expr.ToString(fields))
+	})
+
+	t.Run("extracts field IDs", func(t *testing.T) {
+		expr := ConditionExprV1{Or: []ConditionExprV1{
+			{GreaterThan: &ComparisonCondition{FieldID: "customers", Value: json.RawMessage(`100`)}},
+			{LessThan: &ComparisonCondition{FieldID: "detected", Value: json.RawMessage(`1710000000000`)}},
+		}}
+		fieldIDs, optionIDs := expr.ExtractPropertyIDs()
+		require.ElementsMatch(t, []string{"customers", "detected"}, fieldIDs)
+		require.Empty(t, optionIDs)
+	})
+
+	t.Run("json round trip", func(t *testing.T) {
+		var expr ConditionExprV1
+		require.NoError(t, json.Unmarshal([]byte(`{"gt":{"field_id":"customers","value":5}}`), &expr))
+		require.NotNil(t, expr.GreaterThan)
+		require.Equal(t, "customers", expr.GreaterThan.FieldID)
+	})
+}
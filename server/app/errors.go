@@ -55,8 +55,19 @@ var ErrInternalPrecondition = errors.New("internal precondition violated")
 // ErrPropertyFieldNotOnRun occurs when a property field does not belong to the specified run.
 var ErrPropertyFieldNotOnRun = errors.New("property field does not belong to run")
 
+// ErrPropertyFieldReadOnly occurs when trying to set the value of a computed property field.
+var ErrPropertyFieldReadOnly = errors.New("property field is read-only")
+
+// ErrPropertyFieldShared occurs when trying to change the definition of a playbook property field
+// that is linked to a team property field, or when a team property field is not usable as such.
+var ErrPropertyFieldShared = errors.New("property field is shared by the team")
+
 // ErrInvalidOwner occurs when the proposed new owner is not a member of the run's team or channel.
 var ErrInvalidOwner = errors.New("invalid owner")
 
 // ErrChannelArchived occurs when trying to modify a run whose linked channel has been archived.
 var ErrChannelArchived = errors.New("channel is archived")
+
+// ErrStaleVersion occurs when a conditional update is based on a version of a run or playbook that
+// has since been modified.
+var ErrStaleVersion = errors.New("modified since the expected version")
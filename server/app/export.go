@@ -10,7 +10,17 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 )
 
-const CurrentPlaybookExportVersion = 1
+// CurrentPlaybookExportVersion is the version written by GeneratePlaybookExport and
+// GeneratePlaybookBundleExport. Version 2 added export keys and playbook bundles.
+const CurrentPlaybookExportVersion = 2
+
+// MinPlaybookExportVersion is the oldest export version that can still be imported.
+const MinPlaybookExportVersion = 1
+
+// IsSupportedPlaybookExportVersion returns true if an export with the given version can be imported.
+func IsSupportedPlaybookExportVersion(version int) bool {
+	return version >= MinPlaybookExportVersion && version <= CurrentPlaybookExportVersion
+}
 
 func getFieldsForExport(in interface{}) map[string]interface{} {
 	out := map[string]interface{}{}
@@ -60,6 +70,15 @@ func generateMetricsExport(metrics []PlaybookMetricConfig) []interface{} {
 	return exported
 }
 
+func generateRunRolesExport(roles []RunRole) []interface{} {
+	exported := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		exported = append(exported, getFieldsForExport(role))
+	}
+
+	return exported
+}
+
 // ExportPropertyField represents a property field in export format
 type ExportPropertyField struct {
 	ID    string                  `json:"id"`
@@ -155,13 +174,21 @@ type PlaybookImportData struct {
 	Conditions []ExportCondition     `json:"conditions,omitempty"`
 }
 
-// GeneratePlaybookExport returns a playbook in export format.
-// Fields marked with the stuct tag "export" are included using the given string.
-func GeneratePlaybookExport(playbook Playbook, properties []PropertyField, conditions []Condition) ([]byte, error) {
+// GetExportKey returns the key identifying the playbook across servers, defaulting to its ID.
+func (p Playbook) GetExportKey() string {
+	if p.ExportKey != "" {
+		return p.ExportKey
+	}
+	return p.ID
+}
+
+func generatePlaybookExport(playbook Playbook, properties []PropertyField, conditions []Condition) map[string]interface{} {
 	export := getFieldsForExport(playbook)
 	export["version"] = CurrentPlaybookExportVersion
+	export["export_key"] = playbook.GetExportKey()
 	export["checklists"] = generateChecklistExport(playbook.Checklists)
 	export["metrics"] = generateMetricsExport(playbook.Metrics)
+	export["run_roles"] = generateRunRolesExport(playbook.RunRoles)
 
 	// Add properties and conditions if provided
 	if len(properties) > 0 {
@@ -171,6 +198,14 @@ func GeneratePlaybookExport(playbook Playbook, properties []PropertyField, condi
 		export["conditions"] = generateConditionsExport(conditions)
 	}
 
+	return export
+}
+
+// GeneratePlaybookExport returns a playbook in export format.
+// Fields marked with the stuct tag "export" are included using the given string.
+func GeneratePlaybookExport(playbook Playbook, properties []PropertyField, conditions []Condition) ([]byte, error) {
+	export := generatePlaybookExport(playbook, properties, conditions)
+
 	result, err := json.MarshalIndent(export, "", "    ")
 	if err != nil {
 		return nil, err
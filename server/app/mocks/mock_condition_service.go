@@ -50,18 +50,18 @@ func (mr *MockConditionServiceMockRecorder) CopyPlaybookConditionsToPlaybook(arg
 }
 
 // CopyPlaybookConditionsToRun mocks base method.
-func (m *MockConditionService) CopyPlaybookConditionsToRun(arg0, arg1 string, arg2 *app.PropertyCopyResult) (map[string]*app.Condition, error) {
+func (m *MockConditionService) CopyPlaybookConditionsToRun(arg0, arg1 string, arg2 []app.Condition, arg3 *app.PropertyCopyResult) (map[string]*app.Condition, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyPlaybookConditionsToRun", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "CopyPlaybookConditionsToRun", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(map[string]*app.Condition)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CopyPlaybookConditionsToRun indicates an expected call of CopyPlaybookConditionsToRun.
-func (mr *MockConditionServiceMockRecorder) CopyPlaybookConditionsToRun(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockConditionServiceMockRecorder) CopyPlaybookConditionsToRun(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyPlaybookConditionsToRun", reflect.TypeOf((*MockConditionService)(nil).CopyPlaybookConditionsToRun), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyPlaybookConditionsToRun", reflect.TypeOf((*MockConditionService)(nil).CopyPlaybookConditionsToRun), arg0, arg1, arg2, arg3)
 }
 
 // CreateConditionsFromExport mocks base method.
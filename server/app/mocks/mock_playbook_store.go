@@ -133,6 +133,36 @@ func (mr *MockPlaybookStoreMockRecorder) Create(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPlaybookStore)(nil).Create), arg0)
 }
 
+// CreatePlaybookRevision mocks base method.
+func (m *MockPlaybookStore) CreatePlaybookRevision(arg0 app.PlaybookRevision) (app.PlaybookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePlaybookRevision", arg0)
+	ret0, _ := ret[0].(app.PlaybookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePlaybookRevision indicates an expected call of CreatePlaybookRevision.
+func (mr *MockPlaybookStoreMockRecorder) CreatePlaybookRevision(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePlaybookRevision", reflect.TypeOf((*MockPlaybookStore)(nil).CreatePlaybookRevision), arg0)
+}
+
+// CreateWithRevision mocks base method.
+func (m *MockPlaybookStore) CreateWithRevision(arg0 app.Playbook, arg1 app.PlaybookRevision) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithRevision", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithRevision indicates an expected call of CreateWithRevision.
+func (mr *MockPlaybookStoreMockRecorder) CreateWithRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithRevision", reflect.TypeOf((*MockPlaybookStore)(nil).CreateWithRevision), arg0, arg1)
+}
+
 // DeleteMetric mocks base method.
 func (m *MockPlaybookStore) DeleteMetric(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -207,6 +237,36 @@ func (mr *MockPlaybookStoreMockRecorder) GetMetric(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetric", reflect.TypeOf((*MockPlaybookStore)(nil).GetMetric), arg0)
 }
 
+// GetPlaybookIDsByExportKeys mocks base method.
+func (m *MockPlaybookStore) GetPlaybookIDsByExportKeys(arg0 string, arg1 []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaybookIDsByExportKeys", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaybookIDsByExportKeys indicates an expected call of GetPlaybookIDsByExportKeys.
+func (mr *MockPlaybookStoreMockRecorder) GetPlaybookIDsByExportKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybookIDsByExportKeys", reflect.TypeOf((*MockPlaybookStore)(nil).GetPlaybookIDsByExportKeys), arg0, arg1)
+}
+
+// GetPlaybookIDsForTeam mocks base method.
+func (m *MockPlaybookStore) GetPlaybookIDsForTeam(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaybookIDsForTeam", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaybookIDsForTeam indicates an expected call of GetPlaybookIDsForTeam.
+func (mr *MockPlaybookStoreMockRecorder) GetPlaybookIDsForTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybookIDsForTeam", reflect.TypeOf((*MockPlaybookStore)(nil).GetPlaybookIDsForTeam), arg0)
+}
+
 // GetPlaybookIDsForUser mocks base method.
 func (m *MockPlaybookStore) GetPlaybookIDsForUser(arg0, arg1 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -222,6 +282,36 @@ func (mr *MockPlaybookStoreMockRecorder) GetPlaybookIDsForUser(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybookIDsForUser", reflect.TypeOf((*MockPlaybookStore)(nil).GetPlaybookIDsForUser), arg0, arg1)
 }
 
+// GetPlaybookRevision mocks base method.
+func (m *MockPlaybookStore) GetPlaybookRevision(arg0 string, arg1 int64) (app.PlaybookRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaybookRevision", arg0, arg1)
+	ret0, _ := ret[0].(app.PlaybookRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaybookRevision indicates an expected call of GetPlaybookRevision.
+func (mr *MockPlaybookStoreMockRecorder) GetPlaybookRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybookRevision", reflect.TypeOf((*MockPlaybookStore)(nil).GetPlaybookRevision), arg0, arg1)
+}
+
+// GetPlaybookRevisions mocks base method.
+func (m *MockPlaybookStore) GetPlaybookRevisions(arg0 string, arg1, arg2 int) (app.GetPlaybookRevisionsResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaybookRevisions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(app.GetPlaybookRevisionsResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaybookRevisions indicates an expected call of GetPlaybookRevisions.
+func (mr *MockPlaybookStoreMockRecorder) GetPlaybookRevisions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybookRevisions", reflect.TypeOf((*MockPlaybookStore)(nil).GetPlaybookRevisions), arg0, arg1, arg2)
+}
+
 // GetPlaybooks mocks base method.
 func (m *MockPlaybookStore) GetPlaybooks() ([]app.Playbook, error) {
 	m.ctrl.T.Helper()
@@ -341,34 +431,34 @@ func (mr *MockPlaybookStoreMockRecorder) GraphqlUpdate(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GraphqlUpdate", reflect.TypeOf((*MockPlaybookStore)(nil).GraphqlUpdate), arg0, arg1)
 }
 
-// IsRunNumberPrefixUsed mocks base method.
-func (m *MockPlaybookStore) IsRunNumberPrefixUsed(arg0, arg1, arg2 string) (bool, error) {
+// IncrementRunNumber mocks base method.
+func (m *MockPlaybookStore) IncrementRunNumber(arg0 string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsRunNumberPrefixUsed", arg0, arg1, arg2)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "IncrementRunNumber", arg0)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsRunNumberPrefixUsed indicates an expected call of IsRunNumberPrefixUsed.
-func (mr *MockPlaybookStoreMockRecorder) IsRunNumberPrefixUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
+// IncrementRunNumber indicates an expected call of IncrementRunNumber.
+func (mr *MockPlaybookStoreMockRecorder) IncrementRunNumber(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunNumberPrefixUsed", reflect.TypeOf((*MockPlaybookStore)(nil).IsRunNumberPrefixUsed), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRunNumber", reflect.TypeOf((*MockPlaybookStore)(nil).IncrementRunNumber), arg0)
 }
 
-// IncrementRunNumber mocks base method.
-func (m *MockPlaybookStore) IncrementRunNumber(arg0 string) (int64, error) {
+// IsRunNumberPrefixUsed mocks base method.
+func (m *MockPlaybookStore) IsRunNumberPrefixUsed(arg0, arg1, arg2 string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IncrementRunNumber", arg0)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "IsRunNumberPrefixUsed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IncrementRunNumber indicates an expected call of IncrementRunNumber.
-func (mr *MockPlaybookStoreMockRecorder) IncrementRunNumber(arg0 interface{}) *gomock.Call {
+// IsRunNumberPrefixUsed indicates an expected call of IsRunNumberPrefixUsed.
+func (mr *MockPlaybookStoreMockRecorder) IsRunNumberPrefixUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRunNumber", reflect.TypeOf((*MockPlaybookStore)(nil).IncrementRunNumber), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunNumberPrefixUsed", reflect.TypeOf((*MockPlaybookStore)(nil).IsRunNumberPrefixUsed), arg0, arg1, arg2)
 }
 
 // RemovePlaybookMember mocks base method.
@@ -399,32 +489,32 @@ func (mr *MockPlaybookStoreMockRecorder) Restore(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockPlaybookStore)(nil).Restore), arg0)
 }
 
-// Update mocks base method.
-func (m *MockPlaybookStore) Update(arg0 app.Playbook) error {
+// SetPublishedRevision mocks base method.
+func (m *MockPlaybookStore) SetPublishedRevision(arg0 string, arg1 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "SetPublishedRevision", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockPlaybookStoreMockRecorder) Update(arg0 interface{}) *gomock.Call {
+// SetPublishedRevision indicates an expected call of SetPublishedRevision.
+func (mr *MockPlaybookStoreMockRecorder) SetPublishedRevision(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPlaybookStore)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPublishedRevision", reflect.TypeOf((*MockPlaybookStore)(nil).SetPublishedRevision), arg0, arg1)
 }
 
-// UpdateRunNumberPrefix mocks base method.
-func (m *MockPlaybookStore) UpdateRunNumberPrefix(arg0, arg1 string) error {
+// Update mocks base method.
+func (m *MockPlaybookStore) Update(arg0 app.Playbook) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateRunNumberPrefix", arg0, arg1)
+	ret := m.ctrl.Call(m, "Update", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateRunNumberPrefix indicates an expected call of UpdateRunNumberPrefix.
-func (mr *MockPlaybookStoreMockRecorder) UpdateRunNumberPrefix(arg0, arg1 interface{}) *gomock.Call {
+// Update indicates an expected call of Update.
+func (mr *MockPlaybookStoreMockRecorder) Update(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRunNumberPrefix", reflect.TypeOf((*MockPlaybookStore)(nil).UpdateRunNumberPrefix), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPlaybookStore)(nil).Update), arg0)
 }
 
 // UpdateChannelNameTemplate mocks base method.
@@ -456,6 +546,20 @@ func (mr *MockPlaybookStoreMockRecorder) UpdateChannelNameTemplateIfUnchanged(ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannelNameTemplateIfUnchanged", reflect.TypeOf((*MockPlaybookStore)(nil).UpdateChannelNameTemplateIfUnchanged), arg0, arg1, arg2)
 }
 
+// UpdateIfUnmodified mocks base method.
+func (m *MockPlaybookStore) UpdateIfUnmodified(arg0 app.Playbook, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIfUnmodified", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIfUnmodified indicates an expected call of UpdateIfUnmodified.
+func (mr *MockPlaybookStoreMockRecorder) UpdateIfUnmodified(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIfUnmodified", reflect.TypeOf((*MockPlaybookStore)(nil).UpdateIfUnmodified), arg0, arg1)
+}
+
 // UpdateMetric mocks base method.
 func (m *MockPlaybookStore) UpdateMetric(arg0 string, arg1 map[string]interface{}) error {
 	m.ctrl.T.Helper()
@@ -469,3 +573,31 @@ func (mr *MockPlaybookStoreMockRecorder) UpdateMetric(arg0, arg1 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetric", reflect.TypeOf((*MockPlaybookStore)(nil).UpdateMetric), arg0, arg1)
 }
+
+// UpdateRunNumberPrefix mocks base method.
+func (m *MockPlaybookStore) UpdateRunNumberPrefix(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRunNumberPrefix", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRunNumberPrefix indicates an expected call of UpdateRunNumberPrefix.
+func (mr *MockPlaybookStoreMockRecorder) UpdateRunNumberPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRunNumberPrefix", reflect.TypeOf((*MockPlaybookStore)(nil).UpdateRunNumberPrefix), arg0, arg1)
+}
+
+// UpdateWithRevision mocks base method.
+func (m *MockPlaybookStore) UpdateWithRevision(arg0 app.Playbook, arg1 int64, arg2 app.PlaybookRevision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWithRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWithRevision indicates an expected call of UpdateWithRevision.
+func (mr *MockPlaybookStoreMockRecorder) UpdateWithRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithRevision", reflect.TypeOf((*MockPlaybookStore)(nil).UpdateWithRevision), arg0, arg1, arg2)
+}
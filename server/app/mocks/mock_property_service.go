@@ -10,7 +10,6 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	app "github.com/mattermost/mattermost-plugin-playbooks/server/app"
-	model "github.com/mattermost/mattermost/server/public/model"
 )
 
 // MockPropertyService is a mock of PropertyService interface.
@@ -52,18 +51,18 @@ func (mr *MockPropertyServiceMockRecorder) CopyPlaybookPropertiesToPlaybook(arg0
 }
 
 // CopyPlaybookPropertiesToRun mocks base method.
-func (m *MockPropertyService) CopyPlaybookPropertiesToRun(arg0, arg1 string) (*app.PropertyCopyResult, error) {
+func (m *MockPropertyService) CopyPlaybookPropertiesToRun(arg0, arg1 string, arg2 []app.PropertyField) (*app.PropertyCopyResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CopyPlaybookPropertiesToRun", arg0, arg1)
+	ret := m.ctrl.Call(m, "CopyPlaybookPropertiesToRun", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*app.PropertyCopyResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CopyPlaybookPropertiesToRun indicates an expected call of CopyPlaybookPropertiesToRun.
-func (mr *MockPropertyServiceMockRecorder) CopyPlaybookPropertiesToRun(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockPropertyServiceMockRecorder) CopyPlaybookPropertiesToRun(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyPlaybookPropertiesToRun", reflect.TypeOf((*MockPropertyService)(nil).CopyPlaybookPropertiesToRun), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyPlaybookPropertiesToRun", reflect.TypeOf((*MockPropertyService)(nil).CopyPlaybookPropertiesToRun), arg0, arg1, arg2)
 }
 
 // CreatePropertyField mocks base method.
@@ -81,6 +80,21 @@ func (mr *MockPropertyServiceMockRecorder) CreatePropertyField(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePropertyField", reflect.TypeOf((*MockPropertyService)(nil).CreatePropertyField), arg0, arg1)
 }
 
+// CreateTeamPropertyField mocks base method.
+func (m *MockPropertyService) CreateTeamPropertyField(arg0 string, arg1 app.PropertyField) (*app.PropertyField, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTeamPropertyField", arg0, arg1)
+	ret0, _ := ret[0].(*app.PropertyField)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTeamPropertyField indicates an expected call of CreateTeamPropertyField.
+func (mr *MockPropertyServiceMockRecorder) CreateTeamPropertyField(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeamPropertyField", reflect.TypeOf((*MockPropertyService)(nil).CreateTeamPropertyField), arg0, arg1)
+}
+
 // DeletePropertyField mocks base method.
 func (m *MockPropertyService) DeletePropertyField(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -95,6 +109,20 @@ func (mr *MockPropertyServiceMockRecorder) DeletePropertyField(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePropertyField", reflect.TypeOf((*MockPropertyService)(nil).DeletePropertyField), arg0, arg1)
 }
 
+// DeleteTeamPropertyField mocks base method.
+func (m *MockPropertyService) DeleteTeamPropertyField(arg0, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeamPropertyField", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTeamPropertyField indicates an expected call of DeleteTeamPropertyField.
+func (mr *MockPropertyServiceMockRecorder) DeleteTeamPropertyField(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeamPropertyField", reflect.TypeOf((*MockPropertyService)(nil).DeleteTeamPropertyField), arg0, arg1, arg2)
+}
+
 // GetPropertyField mocks base method.
 func (m *MockPropertyService) GetPropertyField(arg0 string) (*app.PropertyField, error) {
 	m.ctrl.T.Helper()
@@ -260,6 +288,66 @@ func (mr *MockPropertyServiceMockRecorder) GetRunsPropertyValues(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunsPropertyValues", reflect.TypeOf((*MockPropertyService)(nil).GetRunsPropertyValues), arg0)
 }
 
+// GetTeamPropertyFields mocks base method.
+func (m *MockPropertyService) GetTeamPropertyFields(arg0 string) ([]app.PropertyField, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamPropertyFields", arg0)
+	ret0, _ := ret[0].([]app.PropertyField)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamPropertyFields indicates an expected call of GetTeamPropertyFields.
+func (mr *MockPropertyServiceMockRecorder) GetTeamPropertyFields(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamPropertyFields", reflect.TypeOf((*MockPropertyService)(nil).GetTeamPropertyFields), arg0)
+}
+
+// LinkTeamPropertyField mocks base method.
+func (m *MockPropertyService) LinkTeamPropertyField(arg0, arg1 string, arg2 app.PropertyField) (*app.PropertyField, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkTeamPropertyField", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*app.PropertyField)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkTeamPropertyField indicates an expected call of LinkTeamPropertyField.
+func (mr *MockPropertyServiceMockRecorder) LinkTeamPropertyField(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkTeamPropertyField", reflect.TypeOf((*MockPropertyService)(nil).LinkTeamPropertyField), arg0, arg1, arg2)
+}
+
+// MergePropertyFieldsIntoTeamField mocks base method.
+func (m *MockPropertyService) MergePropertyFieldsIntoTeamField(arg0, arg1 string, arg2 []string) (*app.SharedPropertyMergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergePropertyFieldsIntoTeamField", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*app.SharedPropertyMergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergePropertyFieldsIntoTeamField indicates an expected call of MergePropertyFieldsIntoTeamField.
+func (mr *MockPropertyServiceMockRecorder) MergePropertyFieldsIntoTeamField(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePropertyFieldsIntoTeamField", reflect.TypeOf((*MockPropertyService)(nil).MergePropertyFieldsIntoTeamField), arg0, arg1, arg2)
+}
+
+// RecomputeRunPropertyValues mocks base method.
+func (m *MockPropertyService) RecomputeRunPropertyValues(arg0 *app.PlaybookRun) ([]app.PropertyValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecomputeRunPropertyValues", arg0)
+	ret0, _ := ret[0].([]app.PropertyValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecomputeRunPropertyValues indicates an expected call of RecomputeRunPropertyValues.
+func (mr *MockPropertyServiceMockRecorder) RecomputeRunPropertyValues(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecomputeRunPropertyValues", reflect.TypeOf((*MockPropertyService)(nil).RecomputeRunPropertyValues), arg0)
+}
+
 // ReorderPropertyFields mocks base method.
 func (m *MockPropertyService) ReorderPropertyFields(arg0, arg1 string, arg2 int) ([]app.PropertyField, error) {
 	m.ctrl.T.Helper()
@@ -275,6 +363,21 @@ func (mr *MockPropertyServiceMockRecorder) ReorderPropertyFields(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderPropertyFields", reflect.TypeOf((*MockPropertyService)(nil).ReorderPropertyFields), arg0, arg1, arg2)
 }
 
+// SanitizePropertyValue mocks base method.
+func (m *MockPropertyService) SanitizePropertyValue(arg0 *app.PropertyField, arg1 json.RawMessage) (json.RawMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SanitizePropertyValue", arg0, arg1)
+	ret0, _ := ret[0].(json.RawMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SanitizePropertyValue indicates an expected call of SanitizePropertyValue.
+func (mr *MockPropertyServiceMockRecorder) SanitizePropertyValue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SanitizePropertyValue", reflect.TypeOf((*MockPropertyService)(nil).SanitizePropertyValue), arg0, arg1)
+}
+
 // UpdatePropertyField mocks base method.
 func (m *MockPropertyService) UpdatePropertyField(arg0 string, arg1 app.PropertyField) (*app.PropertyField, error) {
 	m.ctrl.T.Helper()
@@ -290,19 +393,20 @@ func (mr *MockPropertyServiceMockRecorder) UpdatePropertyField(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePropertyField", reflect.TypeOf((*MockPropertyService)(nil).UpdatePropertyField), arg0, arg1)
 }
 
-// SanitizePropertyValue mocks base method.
-func (m *MockPropertyService) SanitizePropertyValue(arg0 model.PropertyFieldType, arg1 json.RawMessage) (json.RawMessage, error) {
+// UpdateTeamPropertyField mocks base method.
+func (m *MockPropertyService) UpdateTeamPropertyField(arg0 string, arg1 app.PropertyField, arg2 []string) (*app.PropertyField, []string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SanitizePropertyValue", arg0, arg1)
-	ret0, _ := ret[0].(json.RawMessage)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateTeamPropertyField", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*app.PropertyField)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// SanitizePropertyValue indicates an expected call of SanitizePropertyValue.
-func (mr *MockPropertyServiceMockRecorder) SanitizePropertyValue(arg0, arg1 interface{}) *gomock.Call {
+// UpdateTeamPropertyField indicates an expected call of UpdateTeamPropertyField.
+func (mr *MockPropertyServiceMockRecorder) UpdateTeamPropertyField(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SanitizePropertyValue", reflect.TypeOf((*MockPropertyService)(nil).SanitizePropertyValue), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeamPropertyField", reflect.TypeOf((*MockPropertyService)(nil).UpdateTeamPropertyField), arg0, arg1, arg2)
 }
 
 // UpsertRunPropertyValue mocks base method.
@@ -334,3 +438,18 @@ func (mr *MockPropertyServiceMockRecorder) UpsertRunPropertyValueWithField(arg0,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRunPropertyValueWithField", reflect.TypeOf((*MockPropertyService)(nil).UpsertRunPropertyValueWithField), arg0, arg1, arg2)
 }
+
+// ValidatePropertyValue mocks base method.
+func (m *MockPropertyService) ValidatePropertyValue(arg0 *app.PropertyField, arg1 json.RawMessage) (json.RawMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePropertyValue", arg0, arg1)
+	ret0, _ := ret[0].(json.RawMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatePropertyValue indicates an expected call of ValidatePropertyValue.
+func (mr *MockPropertyServiceMockRecorder) ValidatePropertyValue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePropertyValue", reflect.TypeOf((*MockPropertyService)(nil).ValidatePropertyValue), arg0, arg1)
+}
@@ -105,4 +105,22 @@ func TestValidateNewChannelOnlyMode(t *testing.T) {
 		err := ValidateNewChannelOnlyMode(false, PlaybookRunCreateNewChannel)
 		require.NoError(t, err)
 	})
+
+	t.Run("draft version of a published playbook is rejected", func(t *testing.T) {
+		err := normalizeAndValidateRunCreationParams(
+			&PlaybookRun{},
+			&Playbook{ID: "pb-A", Revision: 3, PublishedRevision: 2},
+		)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrMalformedPlaybookRun),
+			"expected ErrMalformedPlaybookRun; got: %v", err)
+	})
+
+	t.Run("published version is allowed", func(t *testing.T) {
+		err := normalizeAndValidateRunCreationParams(
+			&PlaybookRun{},
+			&Playbook{ID: "pb-A", Revision: 2, PublishedRevision: 2},
+		)
+		require.NoError(t, err)
+	})
 }
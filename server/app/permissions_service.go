@@ -179,6 +179,22 @@ func (p *PermissionsService) PlaybookEdit(userID string, playbook Playbook) erro
 	return errors.Wrapf(ErrNoPermissions, "user %s cannot edit playbook %s (admin-only)", userID, playbook.ID)
 }
 
+// PlaybookPublish checks whether the user can publish a playbook's draft, making it the version
+// new runs are created from. Only system admins and playbook admins can, regardless of
+// AdminOnlyEdit.
+func (p *PermissionsService) PlaybookPublish(userID string, playbook Playbook) error {
+	if IsSystemAdmin(userID, p.pluginAPI) {
+		return nil
+	}
+	if !p.canViewTeam(userID, playbook.TeamID) {
+		return errors.Wrapf(ErrNoPermissions, "user %s cannot publish playbook %s: no team access", userID, playbook.ID)
+	}
+	if p.IsPlaybookAdmin(userID, playbook) {
+		return nil
+	}
+	return errors.Wrapf(ErrNoPermissions, "user %s cannot publish playbook %s (admin-only)", userID, playbook.ID)
+}
+
 // IsPlaybookAdmin reports whether the user holds the playbook's admin role.
 // Uses DefaultPlaybookAdminRole when set, falling back to PlaybookRoleAdmin.
 // Returns false if the user is not an active member of the playbook's team,
@@ -429,6 +445,25 @@ func (p *PermissionsService) PlaybookList(userID, teamID string) error {
 	return errors.Wrapf(ErrNoPermissions, "user `%s` does not have permission to list playbooks for team `%s`", userID, teamID)
 }
 
+// TeamView checks that the user can view the team.
+func (p *PermissionsService) TeamView(userID, teamID string) error {
+	if p.canViewTeam(userID, teamID) {
+		return nil
+	}
+
+	return errors.Wrapf(ErrNoPermissions, "user `%s` does not have permission to view team `%s`", userID, teamID)
+}
+
+// TeamManagePropertyFields checks that the user can manage the shared property fields of the
+// team. Changing them changes every playbook using them, so it takes team admin rights.
+func (p *PermissionsService) TeamManagePropertyFields(userID, teamID string) error {
+	if p.pluginAPI.User.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		return nil
+	}
+
+	return errors.Wrapf(ErrNoPermissions, "user `%s` does not have permission to manage property fields for team `%s`", userID, teamID)
+}
+
 func (p *PermissionsService) PlaybookViewWithPlaybook(userID string, playbook Playbook) error {
 	noAccessErr := errors.Wrapf(
 		ErrNoPermissions,
@@ -798,18 +833,95 @@ func (p *PermissionsService) runRequiresOwnerOrAdmin(userID, runID, actionName s
 }
 
 // RunFinish checks whether userID can finish runID.
-// When Playbook.OwnerGroupOnlyActions is set, only the run owner or a system admin can finish the run.
+// When Playbook.OwnerGroupOnlyActions is set, only the run owner, a system admin or the holder of
+// a run role granting finish_run can finish the run.
 func (p *PermissionsService) RunFinish(userID, runID string) error {
-	_, _, err := p.runRequiresOwnerOrAdmin(userID, runID, "finish")
-	return err
+	return p.runRequiresFinishPermission(userID, runID, "finish")
 }
 
 // RunRestore checks whether userID can restore runID.
-// Applies the same OwnerGroupOnlyActions gate as RunFinish — only the run owner
-// or a system admin can restore when the flag is set.
+// Applies the same gates as RunFinish.
 func (p *PermissionsService) RunRestore(userID, runID string) error {
-	_, _, err := p.runRequiresOwnerOrAdmin(userID, runID, "restore")
-	return err
+	return p.runRequiresFinishPermission(userID, runID, "restore")
+}
+
+// runRequiresFinishPermission applies runRequiresOwnerOrAdmin, letting holders of a run role
+// granting finish_run through the OwnerGroupOnlyActions gate, and then restricts the action to
+// those holders when such a role exists.
+func (p *PermissionsService) runRequiresFinishPermission(userID, runID, actionName string) error {
+	run, _, err := p.runRequiresOwnerOrAdmin(userID, runID, actionName)
+	if run == nil {
+		return err
+	}
+	if err != nil {
+		if errors.Is(err, ErrOwnerGroupOnlyAction) && run.HasRunRolePermission(userID, RunRolePermissionFinishRun) {
+			return nil
+		}
+		return err
+	}
+	return p.checkRunRolePermission(userID, run, RunRolePermissionFinishRun, actionName)
+}
+
+// RunUpdateStatus checks whether userID can post status updates to runID.
+func (p *PermissionsService) RunUpdateStatus(userID, runID string) error {
+	return p.runRequiresRolePermission(userID, runID, RunRolePermissionPostStatusUpdates, "post status updates to")
+}
+
+// RunEditChecklists checks whether userID can modify the checklists of runID.
+func (p *PermissionsService) RunEditChecklists(userID, runID string) error {
+	return p.runRequiresRolePermission(userID, runID, RunRolePermissionEditChecklists, "edit the checklists of")
+}
+
+// RunSetPropertyValues checks whether userID can set the property values of runID.
+func (p *PermissionsService) RunSetPropertyValues(userID, runID string) error {
+	return p.runRequiresRolePermission(userID, runID, RunRolePermissionManageProperties, "set the properties of")
+}
+
+// RunManageRoles checks whether userID can assign the run roles of runID. Only the run owner, a
+// system admin or an admin of the run's playbook can do so.
+func (p *PermissionsService) RunManageRoles(userID, runID string) error {
+	run, playbook, err := p.loadRunAndPlaybook(runID)
+	if run == nil {
+		return err
+	}
+	// A deleted playbook leaves the decision to the run owner and system admins.
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if run.TeamID != "" && !p.canViewTeam(userID, run.TeamID) {
+		return errors.Wrapf(ErrNoPermissions, "no run access; no team view permission for team `%s`", run.TeamID)
+	}
+	if run.OwnerUserID == userID || IsSystemAdmin(userID, p.pluginAPI) {
+		return nil
+	}
+	if playbook != nil && p.IsPlaybookAdmin(userID, *playbook) {
+		return nil
+	}
+	return errors.Wrapf(ErrNoPermissions, "only the run owner or a playbook admin can assign the roles of run %s", runID)
+}
+
+// runRequiresRolePermission enforces base participant access, then checkRunRolePermission.
+func (p *PermissionsService) runRequiresRolePermission(userID, runID, permission, actionName string) error {
+	run, err := p.runService.GetPlaybookRun(runID)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to get run to determine permissions, run id `%s`", runID)
+	}
+	if err := p.runManagePropertiesWithPlaybookRun(userID, run); err != nil {
+		return err
+	}
+	return p.checkRunRolePermission(userID, run, permission, actionName)
+}
+
+// checkRunRolePermission restricts an action to the run owner, system admins and the holders of a
+// run role granting permission, once any role of the run grants it.
+func (p *PermissionsService) checkRunRolePermission(userID string, run *PlaybookRun, permission, actionName string) error {
+	if !run.RunRolesRestrict(permission) || run.OwnerUserID == userID || run.HasRunRolePermission(userID, permission) {
+		return nil
+	}
+	if IsSystemAdmin(userID, p.pluginAPI) {
+		return nil
+	}
+	return errors.Wrapf(ErrNoPermissions, "user `%s` needs a run role granting %s to %s run %s", userID, permission, actionName, run.ID)
 }
 
 // RunChangeOwner checks if the user can change the owner of a run.
@@ -388,6 +388,59 @@ func TestPlaybookModifyWithFixes_AdminOnlyEditFlip(t *testing.T) {
 	})
 }
 
+func TestPlaybookPublish(t *testing.T) {
+	const (
+		teamID     = "team-1"
+		sysadminID = "u-sysadmin"
+		adminID    = "u-pb-admin"
+		memberID   = "u-pb-member"
+	)
+
+	playbook := Playbook{
+		ID:                       "pb-1",
+		TeamID:                   teamID,
+		Public:                   true,
+		DefaultPlaybookAdminRole: PlaybookRoleAdmin,
+		Members: []PlaybookMember{
+			{UserID: adminID, SchemeRoles: []string{PlaybookRoleAdmin, PlaybookRoleMember}},
+			{UserID: memberID, SchemeRoles: []string{PlaybookRoleMember}},
+		},
+	}
+
+	t.Run("system admin can publish", func(t *testing.T) {
+		f := newPermissionsFixture(t)
+		f.allowSysadmin(sysadminID)
+
+		assert.NoError(t, f.svc.PlaybookPublish(sysadminID, playbook))
+	})
+
+	t.Run("playbook admin can publish", func(t *testing.T) {
+		f := newPermissionsFixture(t)
+		f.denySysadmin(adminID)
+		f.allowTeamView(adminID, teamID)
+
+		assert.NoError(t, f.svc.PlaybookPublish(adminID, playbook))
+	})
+
+	t.Run("playbook member who can edit cannot publish", func(t *testing.T) {
+		f := newPermissionsFixture(t)
+		f.denySysadmin(memberID)
+		f.allowTeamView(memberID, teamID)
+
+		err := f.svc.PlaybookPublish(memberID, playbook)
+		assert.ErrorIs(t, err, ErrNoPermissions)
+	})
+
+	t.Run("playbook admin without team access cannot publish", func(t *testing.T) {
+		f := newPermissionsFixture(t)
+		f.denySysadmin(adminID)
+		f.denyTeamView(adminID, teamID)
+
+		err := f.svc.PlaybookPublish(adminID, playbook)
+		assert.ErrorIs(t, err, ErrNoPermissions)
+	})
+}
+
 // ---------------------------------------------------------------------------
 // stubRunService — minimal implementation of PlaybookRunService.
 // Only GetPlaybookRun is exercised by the permission helpers.
@@ -402,6 +455,9 @@ type stubRunService struct {
 func (s *stubRunService) GetPlaybookRun(playbookRunID string) (*PlaybookRun, error) {
 	return s.run, s.err
 }
+func (s *stubRunService) PropagatePlaybookChecklists(string, string, bool) (*ChecklistPropagationResult, error) {
+	panic("stubRunService: PropagatePlaybookChecklists not implemented")
+}
 
 func (s *stubRunService) GetPlaybookRuns(RequesterInfo, PlaybookRunFilterOptions) (*GetPlaybookRunsResults, error) {
 	panic("stubRunService: GetPlaybookRuns not implemented")
@@ -409,6 +465,12 @@ func (s *stubRunService) GetPlaybookRuns(RequesterInfo, PlaybookRunFilterOptions
 func (s *stubRunService) CreatePlaybookRun(*PlaybookRun, *Playbook, string, bool, string, map[string]json.RawMessage) (*PlaybookRun, error) {
 	panic("stubRunService: CreatePlaybookRun not implemented")
 }
+func (s *stubRunService) StartRunFromChannelAction(string, string, string, *model.Post, map[string]string) (*PlaybookRun, error) {
+	panic("stubRunService: StartRunFromChannelAction not implemented")
+}
+func (s *stubRunService) GetInProgressRunsForChannel(string) ([]PlaybookRun, error) {
+	panic("stubRunService: GetInProgressRunsForChannel not implemented")
+}
 func (s *stubRunService) OpenCreatePlaybookRunDialog(string, string, string, string, string, []Playbook) error {
 	panic("stubRunService: OpenCreatePlaybookRunDialog not implemented")
 }
@@ -442,6 +504,18 @@ func (s *stubRunService) ToggleStatusUpdates(string, string, bool) error {
 func (s *stubRunService) SetRunPropertyValue(string, string, string, json.RawMessage) (*PropertyValue, error) {
 	panic("stubRunService: SetRunPropertyValue not implemented")
 }
+func (s *stubRunService) GetPropertyValueHistory(string, string) ([]PropertyValueChange, error) {
+	panic("stubRunService: GetPropertyValueHistory not implemented")
+}
+func (s *stubRunService) GetPropertyValuesAsOf(string, int64) ([]PropertyValue, error) {
+	panic("stubRunService: GetPropertyValuesAsOf not implemented")
+}
+func (s *stubRunService) GetRunChanges(RunChangeOptions) ([]RunChange, error) {
+	panic("stubRunService: GetRunChanges not implemented")
+}
+func (s *stubRunService) GetLatestRunChangeCursor() (int64, error) {
+	panic("stubRunService: GetLatestRunChangeCursor not implemented")
+}
 func (s *stubRunService) GetPlaybookRunMetadata(string, bool) (*Metadata, error) {
 	panic("stubRunService: GetPlaybookRunMetadata not implemented")
 }
@@ -451,6 +525,9 @@ func (s *stubRunService) GetPlaybookRunsForChannelByUser(string, string) ([]Play
 func (s *stubRunService) GetOwners(RequesterInfo, PlaybookRunFilterOptions) ([]OwnerInfo, error) {
 	panic("stubRunService: GetOwners not implemented")
 }
+func (s *stubRunService) GetSharedPropertyFieldStats(RequesterInfo, PlaybookRunFilterOptions) (*SharedPropertyFieldStats, error) {
+	panic("stubRunService: GetSharedPropertyFieldStats not implemented")
+}
 func (s *stubRunService) IsOwner(string, string) bool {
 	panic("stubRunService: IsOwner not implemented")
 }
@@ -472,6 +549,12 @@ func (s *stubRunService) SetGroupAssignee(string, string, string, int, int) erro
 func (s *stubRunService) SetRoleAssignee(string, string, string, int, int) error {
 	panic("stubRunService: SetRoleAssignee not implemented")
 }
+func (s *stubRunService) SetRunRoleAssignee(string, string, string, int, int) error {
+	panic("stubRunService: SetRunRoleAssignee not implemented")
+}
+func (s *stubRunService) AssignRunRole(string, string, string, string) error {
+	panic("stubRunService: AssignRunRole not implemented")
+}
 func (s *stubRunService) SetPropertyUserAssignee(string, string, int, int, string) error {
 	panic("stubRunService: SetPropertyUserAssignee not implemented")
 }
@@ -520,6 +603,30 @@ func (s *stubRunService) MoveChecklist(string, string, int, int) error {
 func (s *stubRunService) MoveChecklistItem(string, string, int, int, int, int) error {
 	panic("stubRunService: MoveChecklistItem not implemented")
 }
+func (s *stubRunService) WithChecklistItem(string, ChecklistItemRef, func(PlaybookRunService, int, int) error) error {
+	panic("stubRunService: WithChecklistItem not implemented")
+}
+func (s *stubRunService) ModifyCheckedStateByID(string, string, string, ChecklistItemRef) error {
+	panic("stubRunService: ModifyCheckedStateByID not implemented")
+}
+func (s *stubRunService) ToggleCheckedStateByID(string, string, ChecklistItemRef) error {
+	panic("stubRunService: ToggleCheckedStateByID not implemented")
+}
+func (s *stubRunService) SetAssigneeByID(string, string, string, ChecklistItemRef) error {
+	panic("stubRunService: SetAssigneeByID not implemented")
+}
+func (s *stubRunService) SetDueDateByID(string, string, int64, ChecklistItemRef) error {
+	panic("stubRunService: SetDueDateByID not implemented")
+}
+func (s *stubRunService) RemoveChecklistItemByID(string, string, ChecklistItemRef) error {
+	panic("stubRunService: RemoveChecklistItemByID not implemented")
+}
+func (s *stubRunService) MoveChecklistItemByID(string, string, ChecklistItemRef, string, *int) error {
+	panic("stubRunService: MoveChecklistItemByID not implemented")
+}
+func (s *stubRunService) ApplyChecklistItemOperations(string, string, []ChecklistItemOperation, bool) ([]BulkOperationResult, error) {
+	panic("stubRunService: ApplyChecklistItemOperations not implemented")
+}
 func (s *stubRunService) GetChecklistItemAutocomplete([]PlaybookRun) ([]model.AutocompleteListItem, error) {
 	panic("stubRunService: GetChecklistItemAutocomplete not implemented")
 }
@@ -574,6 +681,9 @@ func (s *stubRunService) EphemeralPostTodoDigestToUser(string, string, bool, boo
 func (s *stubRunService) DMTodoDigestToUser(string, bool, bool) error {
 	panic("stubRunService: DMTodoDigestToUser not implemented")
 }
+func (s *stubRunService) GetTodoDigestMessageItems(string) (*TodoDigestMessageItems, error) {
+	panic("stubRunService: GetTodoDigestMessageItems not implemented")
+}
 func (s *stubRunService) GetRunsWithAssignedTasks(string) ([]AssignedRun, error) {
 	panic("stubRunService: GetRunsWithAssignedTasks not implemented")
 }
@@ -616,6 +726,9 @@ func (s *stubRunService) GetPlaybookRunIDsForUser(string) ([]string, error) {
 func (s *stubRunService) GraphqlUpdate(string, map[string]interface{}) error {
 	panic("stubRunService: GraphqlUpdate not implemented")
 }
+func (s *stubRunService) GraphqlUpdateIfUnmodified(string, map[string]interface{}, int64) error {
+	panic("stubRunService: GraphqlUpdateIfUnmodified not implemented")
+}
 func (s *stubRunService) MessageHasBeenPosted(*model.Post) {
 	panic("stubRunService: MessageHasBeenPosted not implemented")
 }
@@ -658,6 +771,9 @@ func (s *stubPlaybookService) GetPlaybooksForTeam(RequesterInfo, string, Playboo
 func (s *stubPlaybookService) Update(Playbook, string) error {
 	panic("stubPlaybookService: Update not implemented")
 }
+func (s *stubPlaybookService) UpdateIfUnmodified(Playbook, string, int64) error {
+	panic("stubPlaybookService: UpdateIfUnmodified not implemented")
+}
 func (s *stubPlaybookService) Archive(Playbook, string) error {
 	panic("stubPlaybookService: Archive not implemented")
 }
@@ -682,22 +798,34 @@ func (s *stubPlaybookService) GetTopPlaybooksForTeam(string, string, *InsightsOp
 func (s *stubPlaybookService) GetTopPlaybooksForUser(string, string, *InsightsOpts) (*PlaybooksInsightsList, error) {
 	panic("stubPlaybookService: GetTopPlaybooksForUser not implemented")
 }
-func (s *stubPlaybookService) CreatePropertyField(string, PropertyField) (*PropertyField, error) {
+func (s *stubPlaybookService) CreatePropertyField(string, PropertyField, string) (*PropertyField, error) {
 	panic("stubPlaybookService: CreatePropertyField not implemented")
 }
-func (s *stubPlaybookService) UpdatePropertyField(string, PropertyField) (*PropertyField, error) {
+func (s *stubPlaybookService) UpdatePropertyField(string, PropertyField, string) (*PropertyField, error) {
 	panic("stubPlaybookService: UpdatePropertyField not implemented")
 }
-func (s *stubPlaybookService) DeletePropertyField(string, string) error {
+func (s *stubPlaybookService) DeletePropertyField(string, string, string) error {
 	panic("stubPlaybookService: DeletePropertyField not implemented")
 }
-func (s *stubPlaybookService) ReorderPropertyFields(string, string, int) ([]PropertyField, error) {
+func (s *stubPlaybookService) ReorderPropertyFields(string, string, int, string) ([]PropertyField, error) {
 	panic("stubPlaybookService: ReorderPropertyFields not implemented")
 }
+func (s *stubPlaybookService) CreateTeamPropertyField(string, PropertyField) (*PropertyField, error) {
+	panic("stubPlaybookService: CreateTeamPropertyField not implemented")
+}
+func (s *stubPlaybookService) UpdateTeamPropertyField(string, PropertyField, string) (*PropertyField, error) {
+	panic("stubPlaybookService: UpdateTeamPropertyField not implemented")
+}
+func (s *stubPlaybookService) DeleteTeamPropertyField(string, string) error {
+	panic("stubPlaybookService: DeleteTeamPropertyField not implemented")
+}
+func (s *stubPlaybookService) MergeTeamPropertyFields(string, string, string) (*SharedPropertyMergeResult, error) {
+	panic("stubPlaybookService: MergeTeamPropertyFields not implemented")
+}
 func (s *stubPlaybookService) IncrementRunNumber(string) (int64, error) {
 	panic("stubPlaybookService: IncrementRunNumber not implemented")
 }
-func (s *stubPlaybookService) GraphqlUpdate(string, map[string]interface{}) error {
+func (s *stubPlaybookService) GraphqlUpdate(string, map[string]interface{}, string) error {
 	panic("stubPlaybookService: GraphqlUpdate not implemented")
 }
 func (s *stubPlaybookService) AddPlaybookMember(string, string) error {
@@ -706,16 +834,22 @@ func (s *stubPlaybookService) AddPlaybookMember(string, string) error {
 func (s *stubPlaybookService) RemovePlaybookMember(string, string) error {
 	panic("stubPlaybookService: RemovePlaybookMember not implemented")
 }
-func (s *stubPlaybookService) AddMetric(string, PlaybookMetricConfig) error {
+func (s *stubPlaybookService) AddMember(string, string, string) error {
+	panic("stubPlaybookService: AddMember not implemented")
+}
+func (s *stubPlaybookService) RemoveMember(string, string, string) error {
+	panic("stubPlaybookService: RemoveMember not implemented")
+}
+func (s *stubPlaybookService) AddMetric(string, PlaybookMetricConfig, string) (*PlaybookMetricConfig, error) {
 	panic("stubPlaybookService: AddMetric not implemented")
 }
 func (s *stubPlaybookService) GetMetric(string) (*PlaybookMetricConfig, error) {
 	panic("stubPlaybookService: GetMetric not implemented")
 }
-func (s *stubPlaybookService) UpdateMetric(string, map[string]interface{}) error {
+func (s *stubPlaybookService) UpdateMetric(string, PlaybookMetricPatch, string) (*PlaybookMetricConfig, error) {
 	panic("stubPlaybookService: UpdateMetric not implemented")
 }
-func (s *stubPlaybookService) DeleteMetric(string) error {
+func (s *stubPlaybookService) DeleteMetric(string, string) error {
 	panic("stubPlaybookService: DeleteMetric not implemented")
 }
 
@@ -734,6 +868,18 @@ func (s *stubPlaybookService) UpdateChannelNameTemplateAtomically(string, func(s
 func (s *stubPlaybookService) GetPlaybookConditionsForExport(string) ([]Condition, error) {
 	panic("stubPlaybookService: GetPlaybookConditionsForExport not implemented")
 }
+func (s *stubPlaybookService) RecordRevision(string, string) error {
+	panic("stubPlaybookService: RecordRevision not implemented")
+}
+func (s *stubPlaybookService) GetPublished(string) (Playbook, error) {
+	panic("stubPlaybookService: GetPublished not implemented")
+}
+func (s *stubPlaybookService) GetAtRevision(string, int64) (Playbook, error) {
+	panic("stubPlaybookService: GetAtRevision not implemented")
+}
+func (s *stubPlaybookService) GetPropertiesAndConditions(Playbook) ([]PropertyField, []Condition, error) {
+	panic("stubPlaybookService: GetPropertiesAndConditions not implemented")
+}
 
 // ---------------------------------------------------------------------------
 // Helpers
@@ -1733,3 +1879,133 @@ func TestRunToggleRetrospective(t *testing.T) {
 		require.ErrorIs(t, svc.RunToggleRetrospective(outsider, run.ID), ErrNoPermissions)
 	})
 }
+
+// ---------------------------------------------------------------------------
+// TestRunRolePermissions
+// ---------------------------------------------------------------------------
+
+func TestRunRolePermissions(t *testing.T) {
+	const (
+		runID     = "run-roles-id"
+		pbID      = "playbook-id-roles"
+		ownerID   = "owner-user-id"
+		holderID  = "role-holder-user-id"
+		memberID  = "member-non-owner-id"
+		adminID   = "system-admin-user-id"
+		pbAdminID = "playbook-admin-user-id"
+	)
+
+	playbook := Playbook{
+		ID:     pbID,
+		TeamID: "team-1",
+		Members: []PlaybookMember{
+			{UserID: pbAdminID, SchemeRoles: []string{PlaybookRoleAdmin, PlaybookRoleMember}},
+			{UserID: memberID, SchemeRoles: []string{PlaybookRoleMember}},
+		},
+	}
+
+	makeRun := func(roles ...RunRole) *PlaybookRun {
+		return &PlaybookRun{
+			ID:             runID,
+			PlaybookID:     pbID,
+			TeamID:         "team-1",
+			OwnerUserID:    ownerID,
+			ParticipantIDs: []string{ownerID, holderID, memberID, pbAdminID},
+			Type:           RunTypePlaybook,
+			RunRoles:       roles,
+		}
+	}
+
+	scribe := RunRole{ID: "scribe", Name: "Scribe", UserID: holderID, Permissions: []string{
+		RunRolePermissionPostStatusUpdates,
+		RunRolePermissionEditChecklists,
+	}}
+
+	check := func(t *testing.T, err error, shouldSucceed bool) {
+		t.Helper()
+		if shouldSucceed {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrNoPermissions), "got: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		run           *PlaybookRun
+		userID        string
+		isAdmin       bool
+		shouldSucceed bool
+	}{
+		{"no roles allows any participant", makeRun(), memberID, false, true},
+		{"unrestricting role allows any participant", makeRun(RunRole{ID: "r", Name: "Observer"}), memberID, false, true},
+		{"restricting role allows holder", makeRun(scribe), holderID, false, true},
+		{"restricting role allows owner", makeRun(scribe), ownerID, false, true},
+		{"restricting role allows system admin", makeRun(scribe), adminID, true, true},
+		{"restricting role rejects other participant", makeRun(scribe), memberID, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminIDs := []string{}
+			if tt.isAdmin {
+				adminIDs = append(adminIDs, tt.userID)
+			}
+			svc := newPermissionsServiceForTest(
+				&stubRunService{run: tt.run, err: nil},
+				&stubPlaybookService{playbook: playbook, err: nil},
+				newPluginAPIAllowingAdmins(t, adminIDs...),
+			)
+
+			check(t, svc.RunUpdateStatus(tt.userID, runID), tt.shouldSucceed)
+			check(t, svc.RunEditChecklists(tt.userID, runID), tt.shouldSucceed)
+
+			// The scribe role does not grant managing properties, so that stays open.
+			require.NoError(t, svc.RunSetPropertyValues(tt.userID, runID))
+		})
+	}
+
+	t.Run("finish_run role lets holder finish an owner-only run", func(t *testing.T) {
+		ownerOnly := playbook
+		ownerOnly.OwnerGroupOnlyActions = true
+		run := makeRun(RunRole{ID: "lead", Name: "Lead", UserID: holderID, Permissions: []string{RunRolePermissionFinishRun}})
+		svc := newPermissionsServiceForTest(
+			&stubRunService{run: run, err: nil},
+			&stubPlaybookService{playbook: ownerOnly, err: nil},
+			newPluginAPIAllowingAdmins(t),
+		)
+
+		check(t, svc.RunFinish(holderID, runID), true)
+		check(t, svc.RunFinish(memberID, runID), false)
+	})
+
+	t.Run("manage roles", func(t *testing.T) {
+		manageTests := []struct {
+			name          string
+			userID        string
+			isAdmin       bool
+			shouldSucceed bool
+		}{
+			{"owner can assign roles", ownerID, false, true},
+			{"playbook admin can assign roles", pbAdminID, false, true},
+			{"system admin can assign roles", adminID, true, true},
+			{"role holder cannot assign roles", holderID, false, false},
+			{"member cannot assign roles", memberID, false, false},
+		}
+		for _, tt := range manageTests {
+			t.Run(tt.name, func(t *testing.T) {
+				adminIDs := []string{}
+				if tt.isAdmin {
+					adminIDs = append(adminIDs, tt.userID)
+				}
+				svc := newPermissionsServiceForTest(
+					&stubRunService{run: makeRun(scribe), err: nil},
+					&stubPlaybookService{playbook: playbook, err: nil},
+					newPluginAPIAllowingAdmins(t, adminIDs...),
+				)
+				check(t, svc.RunManageRoles(tt.userID, runID), tt.shouldSucceed)
+			})
+		}
+	})
+}
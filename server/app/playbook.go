@@ -80,12 +80,26 @@ type Playbook struct {
 	ChannelMode ChannelPlaybookMode `json:"channel_mode" export:"channel_mode"`
 
 	RunNumberPrefix string `json:"run_number_prefix" export:"run_number_prefix"`
+	// ExportKey identifies the playbook across servers when importing bundles. It is set once on
+	// creation and falls back to the playbook ID when exporting a playbook that has none.
+	ExportKey string `json:"export_key" export:"export_key"`
 	// NextRunNumber is a server-managed counter. It is write-ignored on API input and must never be set by callers.
 	NextRunNumber int64 `json:"-" export:"-"`
-	AdminOnlyEdit bool  `json:"admin_only_edit" export:"-"`
+	// Revision is the number of the latest revision recorded for the playbook. It is server-managed
+	// and ignored on API input.
+	Revision int64 `json:"revision" export:"-"`
+	// PublishedRevision is the revision new runs are created from. Changes made after it are a
+	// draft until a playbook admin publishes them. 0 means the playbook was never published and
+	// runs use its latest version. It is server-managed and ignored on API input.
+	PublishedRevision int64 `json:"published_revision" export:"-"`
+	AdminOnlyEdit     bool  `json:"admin_only_edit" export:"-"`
 
 	OwnerGroupOnlyActions bool `json:"owner_group_only_actions" export:"owner_group_only_actions"`
 
+	// RunRoles are the custom roles, such as Incident Commander or Scribe, copied to each run of
+	// the playbook and assigned per run.
+	RunRoles []RunRole `json:"run_roles" export:"run_roles"`
+
 	NewChannelOnly bool `json:"new_channel_only" export:"new_channel_only"`
 
 	AutoArchiveChannel bool `json:"auto_archive_channel" export:"auto_archive_channel"`
@@ -123,6 +137,23 @@ type PlaybookMetricConfig struct {
 	Target      null.Int `json:"target" export:"target"`
 }
 
+// PlaybookMetricPatch is a partial update of a metric: only the fields that are set are changed.
+type PlaybookMetricPatch struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Target      *int64  `json:"target"`
+}
+
+// ValidateMetricType returns an error unless metricType is one of the metric types.
+func ValidateMetricType(metricType string) error {
+	switch metricType {
+	case MetricTypeDuration, MetricTypeCurrency, MetricTypeInteger:
+		return nil
+	default:
+		return errors.Errorf("unknown metric type %q", metricType)
+	}
+}
+
 func (pm PlaybookMember) Clone() PlaybookMember {
 	newPlaybookMember := pm
 	if len(pm.Roles) != 0 {
@@ -142,6 +173,7 @@ func (p Playbook) Clone() Playbook {
 	}
 	newPlaybook.Checklists = newChecklists
 	newPlaybook.Metrics = append([]PlaybookMetricConfig(nil), p.Metrics...)
+	newPlaybook.RunRoles = cloneRunRoles(p.RunRoles)
 	var newMembers []PlaybookMember
 	for _, m := range p.Members {
 		newMembers = append(newMembers, m.Clone())
@@ -203,6 +235,9 @@ func (p Playbook) MarshalJSON() ([]byte, error) {
 	if old.Metrics == nil {
 		old.Metrics = []PlaybookMetricConfig{}
 	}
+	if old.RunRoles == nil {
+		old.RunRoles = []RunRole{}
+	}
 	if old.InvitedUserIDs == nil {
 		old.InvitedUserIDs = []string{}
 	}
@@ -318,6 +353,8 @@ type ChecklistItem struct {
 	AssigneeType string `json:"assignee_type" export:"assignee_type"`
 	// AssigneePropertyFieldID is the property field whose value is used when AssigneeType == AssigneeTypePropertyUser.
 	AssigneePropertyFieldID string `json:"assignee_property_field_id" export:"assignee_property_field_id"`
+	// AssigneeRoleID is the run role whose holder is assigned when AssigneeType == AssigneeTypeRunRole.
+	AssigneeRoleID string `json:"assignee_role_id" export:"assignee_role_id"`
 
 	// Command, if not empty, is the slash command that can be run as part of this item.
 	Command string `json:"command" export:"command"`
@@ -430,6 +467,10 @@ type PlaybookService interface {
 	// Update updates a playbook
 	Update(playbook Playbook, userID string) error
 
+	// UpdateIfUnmodified updates a playbook, failing with ErrStaleVersion unless it was last
+	// updated at updateAt. An updateAt of 0 updates the playbook unconditionally.
+	UpdateIfUnmodified(playbook Playbook, userID string, updateAt int64) error
+
 	// Archive archives a playbook
 	Archive(playbook Playbook, userID string) error
 
@@ -455,16 +496,30 @@ type PlaybookService interface {
 	GetTopPlaybooksForUser(teamID, userID string, opts *InsightsOpts) (*PlaybooksInsightsList, error)
 
 	// CreatePropertyField creates a property field for a playbook and bumps the playbook's updated_at
-	CreatePropertyField(playbookID string, propertyField PropertyField) (*PropertyField, error)
+	CreatePropertyField(playbookID string, propertyField PropertyField, userID string) (*PropertyField, error)
 
 	// UpdatePropertyField updates a property field for a playbook and bumps the playbook's updated_at
-	UpdatePropertyField(playbookID string, propertyField PropertyField) (*PropertyField, error)
+	UpdatePropertyField(playbookID string, propertyField PropertyField, userID string) (*PropertyField, error)
 
 	// DeletePropertyField deletes a property field for a playbook and bumps the playbook's updated_at
-	DeletePropertyField(playbookID, propertyID string) error
+	DeletePropertyField(playbookID, propertyID, userID string) error
 
 	// ReorderPropertyFields reorders property fields for a playbook and bumps the playbook's updated_at
-	ReorderPropertyFields(playbookID, fieldID string, targetPosition int) ([]PropertyField, error)
+	ReorderPropertyFields(playbookID, fieldID string, targetPosition int, userID string) ([]PropertyField, error)
+
+	// CreateTeamPropertyField creates a property field shared by the playbooks of a team
+	CreateTeamPropertyField(teamID string, propertyField PropertyField) (*PropertyField, error)
+
+	// UpdateTeamPropertyField updates a team property field and the playbook fields linked to it,
+	// bumping the updated_at of their playbooks
+	UpdateTeamPropertyField(teamID string, propertyField PropertyField, userID string) (*PropertyField, error)
+
+	// DeleteTeamPropertyField deletes a team property field no playbook uses anymore
+	DeleteTeamPropertyField(teamID, propertyID string) error
+
+	// MergeTeamPropertyFields links the same-named property fields of the team's playbooks to a
+	// team property field, creating it from them if needed
+	MergeTeamPropertyFields(teamID, name, userID string) (*SharedPropertyMergeResult, error)
 
 	// IncrementRunNumber atomically increments NextRunNumber on the playbook and returns the allocated number.
 	IncrementRunNumber(playbookID string) (int64, error)
@@ -478,6 +533,43 @@ type PlaybookService interface {
 
 	// UpdateChannelNameTemplate updates only the channel name template for a playbook.
 	UpdateChannelNameTemplate(playbookID, template, userID string) error
+
+	// RecordRevision stores an immutable snapshot of the playbook as it currently is. Changes made
+	// through the service are recorded automatically; this is for changes made elsewhere.
+	RecordRevision(playbookID, userID string) error
+
+	// GetPublished retrieves a playbook as it was when last published, which is the version new
+	// runs must be created from. Unpublished playbooks are returned as they currently are.
+	GetPublished(id string) (Playbook, error)
+
+	// GetAtRevision retrieves a playbook as it was at the given revision.
+	GetAtRevision(id string, revision int64) (Playbook, error)
+
+	// GetPropertiesAndConditions retrieves the property fields and conditions of the given version
+	// of a playbook, as recorded in its revision, so they match its checklists. Playbooks without
+	// revisions return their current ones.
+	GetPropertiesAndConditions(playbook Playbook) ([]PropertyField, []Condition, error)
+
+	// GraphqlUpdate updates the columns of a playbook set in setmap. An empty setmap changes nothing.
+	GraphqlUpdate(id string, setmap map[string]interface{}, userID string) error
+
+	// AddMember adds a user to the members of a playbook. Existing members are left unchanged.
+	AddMember(playbookID, memberID, userID string) error
+
+	// RemoveMember removes a user from the members of a playbook.
+	RemoveMember(playbookID, memberID, userID string) error
+
+	// GetMetric retrieves a metric by ID.
+	GetMetric(id string) (*PlaybookMetricConfig, error)
+
+	// AddMetric adds a metric to a playbook and returns it with its new ID.
+	AddMetric(playbookID string, metric PlaybookMetricConfig, userID string) (*PlaybookMetricConfig, error)
+
+	// UpdateMetric updates the fields of a metric set in patch and returns the updated metric.
+	UpdateMetric(id string, patch PlaybookMetricPatch, userID string) (*PlaybookMetricConfig, error)
+
+	// DeleteMetric deletes a metric.
+	DeleteMetric(id, userID string) error
 }
 
 // PlaybookStore is an interface for storing playbooks
@@ -488,6 +580,10 @@ type PlaybookStore interface {
 	// Create creates a new playbook
 	Create(playbook Playbook) (string, error)
 
+	// CreateWithRevision creates a new playbook and stores revision as its first revision in the
+	// same transaction.
+	CreateWithRevision(playbook Playbook, revision PlaybookRevision) (string, error)
+
 	// GetPlaybooks retrieves all playbooks
 	GetPlaybooks() ([]Playbook, error)
 
@@ -508,9 +604,20 @@ type PlaybookStore interface {
 	// GetPlaybookIDsForUser retrieves playbooks user can access
 	GetPlaybookIDsForUser(userID, teamID string) ([]string, error)
 
+	// GetPlaybookIDsForTeam retrieves the IDs of all playbooks of a team, archived ones included
+	GetPlaybookIDsForTeam(teamID string) ([]string, error)
+
 	// Update updates a playbook
 	Update(playbook Playbook) error
 
+	// UpdateIfUnmodified updates a playbook, failing with ErrStaleVersion unless its UpdateAt is
+	// updateAt. An updateAt of 0 updates the playbook unconditionally.
+	UpdateIfUnmodified(playbook Playbook, updateAt int64) error
+
+	// UpdateWithRevision updates a playbook like UpdateIfUnmodified and stores revision in the same
+	// transaction, so the update is never saved without its revision.
+	UpdateWithRevision(playbook Playbook, updateAt int64, revision PlaybookRevision) error
+
 	// GraphqlUpdate taking a setmap for graphql
 	GraphqlUpdate(id string, setmap map[string]interface{}) error
 
@@ -532,10 +639,10 @@ type PlaybookStore interface {
 	// GetPlaybooksActiveTotal returns number of active playbooks
 	GetPlaybooksActiveTotal() (int64, error)
 
-	// GetMetric retrieves a metric by ID
+	// GetMetric retrieves a metric by ID, failing with ErrNotFound for deleted metrics
 	GetMetric(id string) (*PlaybookMetricConfig, error)
 
-	// AddMetric adds a metric
+	// AddMetric adds a metric with the ID of config
 	AddMetric(playbookID string, config PlaybookMetricConfig) error
 
 	// UpdateMetric updates a metric
@@ -575,6 +682,23 @@ type PlaybookStore interface {
 
 	// UpdateChannelNameTemplate updates only the ChannelNameTemplate column for the given playbook.
 	UpdateChannelNameTemplate(id, template string) error
+
+	// GetPlaybookIDsByExportKeys returns the IDs of the active playbooks in teamID matching the
+	// given export keys, keyed by export key.
+	GetPlaybookIDsByExportKeys(teamID string, exportKeys []string) (map[string]string, error)
+
+	// CreatePlaybookRevision stores a new revision of a playbook, numbering it after the latest one
+	// and updating the playbook's current revision.
+	CreatePlaybookRevision(revision PlaybookRevision) (PlaybookRevision, error)
+
+	// GetPlaybookRevisions returns the revisions of a playbook, newest first, without their snapshots.
+	GetPlaybookRevisions(playbookID string, page, perPage int) (GetPlaybookRevisionsResults, error)
+
+	// GetPlaybookRevision returns a single revision of a playbook. Returns ErrNotFound if not found.
+	GetPlaybookRevision(playbookID string, revision int64) (PlaybookRevision, error)
+
+	// SetPublishedRevision marks a revision of a playbook as the published one.
+	SetPublishedRevision(playbookID string, revision int64) error
 }
 
 const (
@@ -633,15 +757,18 @@ const (
 	AssigneeTypeOwner        = "owner"         // resolved to the run owner at assignment time
 	AssigneeTypeCreator      = "creator"       // resolved to the run creator at assignment time
 	AssigneeTypePropertyUser = "property_user" // resolved via a User-type property field
+	AssigneeTypeRunRole      = "run_role"      // resolved to the holder of a custom run role
 )
 
 // IsValidAssigneeType returns true for all recognised AssigneeType values:
-// AssigneeTypeSpecificUser, AssigneeTypeOwner, AssigneeTypeCreator, and AssigneeTypePropertyUser.
+// AssigneeTypeSpecificUser, AssigneeTypeOwner, AssigneeTypeCreator, AssigneeTypePropertyUser,
+// and AssigneeTypeRunRole.
 func IsValidAssigneeType(assigneeType string) bool {
 	return assigneeType == AssigneeTypeSpecificUser ||
 		assigneeType == AssigneeTypeOwner ||
 		assigneeType == AssigneeTypeCreator ||
-		assigneeType == AssigneeTypePropertyUser
+		assigneeType == AssigneeTypePropertyUser ||
+		assigneeType == AssigneeTypeRunRole
 }
 
 func IsValidChecklistItemState(state string) bool {
@@ -0,0 +1,408 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/safemapstructure"
+)
+
+const (
+	BundleImportActionCreate = "create"
+	BundleImportActionUpdate = "update"
+)
+
+// MaxPlaybooksPerBundle limits the number of playbooks exported or imported in a single bundle.
+const MaxPlaybooksPerBundle = 100
+
+// ExportChannelAction represents a channel action in export format.
+type ExportChannelAction struct {
+	ActionType  ActionType  `json:"action_type"`
+	TriggerType TriggerType `json:"trigger_type"`
+	Enabled     bool        `json:"enabled"`
+	Payload     interface{} `json:"payload"`
+}
+
+// PlaybookBundle is a set of playbooks exported together so they can be moved between servers.
+type PlaybookBundle struct {
+	Version   int                   `json:"version"`
+	Playbooks []PlaybookBundleEntry `json:"playbooks"`
+}
+
+// PlaybookBundleEntry is a single playbook inside a bundle. It uses the same format as a single
+// playbook export, plus the actions of the channel linked to the playbook. Entries are only meant
+// to be decoded: the embedded Playbook's MarshalJSON would hide the other fields when encoding.
+type PlaybookBundleEntry struct {
+	Playbook
+	Version        int                   `json:"version"`
+	Properties     []ExportPropertyField `json:"properties,omitempty"`
+	Conditions     []ExportCondition     `json:"conditions,omitempty"`
+	ChannelActions []ExportChannelAction `json:"channel_actions,omitempty"`
+}
+
+// PlaybookExportSource holds everything exported for a single playbook of a bundle.
+type PlaybookExportSource struct {
+	Playbook       Playbook
+	Properties     []PropertyField
+	Conditions     []Condition
+	ChannelActions []GenericChannelAction
+}
+
+// PlaybookBundleImportItem describes what importing a bundle entry does, or did.
+type PlaybookBundleImportItem struct {
+	ExportKey         string `json:"export_key"`
+	Title             string `json:"title"`
+	Action            string `json:"action"`
+	PlaybookID        string `json:"playbook_id,omitempty"`
+	NumChannelActions int    `json:"num_channel_actions"`
+
+	// Warnings lists the parts of the entry that are not imported.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PlaybookBundleImportResult is returned both when previewing and when importing a bundle.
+type PlaybookBundleImportResult struct {
+	DryRun    bool                       `json:"dry_run"`
+	Playbooks []PlaybookBundleImportItem `json:"playbooks"`
+}
+
+// NumCreates returns the number of playbooks the import creates.
+func (r *PlaybookBundleImportResult) NumCreates() int {
+	count := 0
+	for _, item := range r.Playbooks {
+		if item.Action == BundleImportActionCreate {
+			count++
+		}
+	}
+	return count
+}
+
+// Auditable returns the IDs of the created and updated playbooks for the audit log.
+func (r *PlaybookBundleImportResult) Auditable() map[string]any {
+	created := []string{}
+	updated := []string{}
+	for _, item := range r.Playbooks {
+		if item.Action == BundleImportActionUpdate {
+			updated = append(updated, item.PlaybookID)
+		} else {
+			created = append(created, item.PlaybookID)
+		}
+	}
+	return map[string]any{
+		"created_playbook_ids": created,
+		"updated_playbook_ids": updated,
+	}
+}
+
+// PlaybookBundleService exports and imports several playbooks at once.
+type PlaybookBundleService interface {
+	// Export generates a bundle containing the given playbooks.
+	Export(playbookIDs []string) ([]byte, error)
+
+	// Plan computes which bundle entries would create a new playbook and which would update an
+	// existing one in teamID, without writing anything.
+	Plan(bundle PlaybookBundle, teamID string) (*PlaybookBundleImportResult, error)
+
+	// Import creates or updates the bundled playbooks in teamID, matching existing playbooks by
+	// export key. Importing the same bundle twice leaves the playbooks unchanged. When a step
+	// fails, the steps already done are reverted: created playbooks are archived, and updated
+	// playbooks and channel actions get their previous settings back.
+	Import(bundle PlaybookBundle, teamID, userID string) (*PlaybookBundleImportResult, error)
+}
+
+// GeneratePlaybookBundleExport returns several playbooks in bundle export format. References to
+// other playbooks of the bundle, such as the playbook suggested by a keywords channel action, are
+// replaced by their export keys so they can be resolved on import.
+func GeneratePlaybookBundleExport(sources []PlaybookExportSource) ([]byte, error) {
+	exportKeys := make(map[string]string, len(sources))
+	for _, source := range sources {
+		exportKeys[source.Playbook.ID] = source.Playbook.GetExportKey()
+	}
+
+	playbooks := make([]interface{}, 0, len(sources))
+	for _, source := range sources {
+		export := generatePlaybookExport(source.Playbook, source.Properties, source.Conditions)
+
+		channelActions, err := generateChannelActionsExport(source.ChannelActions, exportKeys)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to export channel actions for playbook %s", source.Playbook.ID)
+		}
+		if len(channelActions) > 0 {
+			export["channel_actions"] = channelActions
+		}
+
+		playbooks = append(playbooks, export)
+	}
+
+	bundle := map[string]interface{}{
+		"version":   CurrentPlaybookExportVersion,
+		"playbooks": playbooks,
+	}
+
+	return json.MarshalIndent(bundle, "", "    ")
+}
+
+func generateChannelActionsExport(actions []GenericChannelAction, exportKeys map[string]string) ([]ExportChannelAction, error) {
+	exported := make([]ExportChannelAction, 0, len(actions))
+	for _, action := range actions {
+		if action.DeleteAt != 0 {
+			continue
+		}
+
+		payload, err := DecodeChannelActionPayload(action.ActionType, action.Payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = resolveChannelActionPlaybookIDs(payload, exportKeys)
+
+		exported = append(exported, ExportChannelAction{
+			ActionType:  action.ActionType,
+			TriggerType: action.TriggerType,
+			Enabled:     action.Enabled,
+			Payload:     payload,
+		})
+	}
+
+	return exported, nil
+}
+
+// DecodeChannelActionPayload converts a channel action payload, either typed or freshly decoded
+// from JSON, into the payload struct matching the action type.
+func DecodeChannelActionPayload(actionType ActionType, payload interface{}) (interface{}, error) {
+	switch actionType {
+	case ActionTypeWelcomeMessage:
+		if typed, ok := payload.(WelcomeMessagePayload); ok {
+			return typed, nil
+		}
+		var typed WelcomeMessagePayload
+		if err := safemapstructure.Decode(payload, &typed); err != nil {
+			return nil, errors.Wrap(err, "unable to decode welcome message payload")
+		}
+		return typed, nil
+	case ActionTypePromptRunPlaybook:
+		if typed, ok := payload.(PromptRunPlaybookFromKeywordsPayload); ok {
+			return typed, nil
+		}
+		var typed PromptRunPlaybookFromKeywordsPayload
+		if err := safemapstructure.Decode(payload, &typed); err != nil {
+			return nil, errors.Wrap(err, "unable to decode prompt run playbook payload")
+		}
+		return typed, nil
+	case ActionTypeCategorizeChannel:
+		if typed, ok := payload.(CategorizeChannelPayload); ok {
+			return typed, nil
+		}
+		var typed CategorizeChannelPayload
+		if err := safemapstructure.Decode(payload, &typed); err != nil {
+			return nil, errors.Wrap(err, "unable to decode categorize channel payload")
+		}
+		return typed, nil
+	case ActionTypeStartRun:
+		if typed, ok := payload.(StartRunPayload); ok {
+			return typed, nil
+		}
+		var typed StartRunPayload
+		if err := safemapstructure.Decode(payload, &typed); err != nil {
+			return nil, errors.Wrap(err, "unable to decode start run payload")
+		}
+		return typed, nil
+	case ActionTypeAddToRunTimeline:
+		if typed, ok := payload.(AddToRunTimelinePayload); ok {
+			return typed, nil
+		}
+		var typed AddToRunTimelinePayload
+		if err := safemapstructure.Decode(payload, &typed); err != nil {
+			return nil, errors.Wrap(err, "unable to decode add to run timeline payload")
+		}
+		return typed, nil
+	case ActionTypeSetRunProperty:
+		if typed, ok := payload.(SetRunPropertyPayload); ok {
+			return typed, nil
+		}
+		var typed SetRunPropertyPayload
+		if err := safemapstructure.Decode(payload, &typed); err != nil {
+			return nil, errors.Wrap(err, "unable to decode set run property payload")
+		}
+		return typed, nil
+	default:
+		return nil, errors.Errorf("action type %q not recognized", actionType)
+	}
+}
+
+// ParsePlaybookBundle decodes a bundle. Besides the bundle format it accepts a bare JSON array of
+// playbook exports and a single playbook export, including version 1 exports without export keys.
+func ParsePlaybookBundle(data []byte) (PlaybookBundle, error) {
+	var bundle PlaybookBundle
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return bundle, errors.New("bundle is empty")
+	}
+
+	switch trimmed[0] {
+	case '[':
+		if err := json.Unmarshal(trimmed, &bundle.Playbooks); err != nil {
+			return bundle, errors.Wrap(err, "unable to decode playbook bundle")
+		}
+	case '{':
+		var probe struct {
+			Playbooks json.RawMessage `json:"playbooks"`
+		}
+		if err := json.Unmarshal(trimmed, &probe); err != nil {
+			return bundle, errors.Wrap(err, "unable to decode playbook bundle")
+		}
+
+		if probe.Playbooks != nil {
+			if err := json.Unmarshal(trimmed, &bundle); err != nil {
+				return bundle, errors.Wrap(err, "unable to decode playbook bundle")
+			}
+		} else {
+			var entry PlaybookBundleEntry
+			if err := json.Unmarshal(trimmed, &entry); err != nil {
+				return bundle, errors.Wrap(err, "unable to decode playbook export")
+			}
+			bundle.Version = entry.Version
+			bundle.Playbooks = []PlaybookBundleEntry{entry}
+		}
+	default:
+		return bundle, errors.New("bundle must be a JSON object or array")
+	}
+
+	if err := bundle.Validate(); err != nil {
+		return bundle, err
+	}
+
+	return bundle, nil
+}
+
+// Validate checks the bundle and entry versions and that export keys are unique. Entries without
+// a version inherit the bundle version.
+func (b *PlaybookBundle) Validate() error {
+	if b.Version != 0 && !IsSupportedPlaybookExportVersion(b.Version) {
+		return errors.Errorf("unsupported bundle version %d", b.Version)
+	}
+
+	if len(b.Playbooks) == 0 {
+		return errors.New("bundle does not contain any playbooks")
+	}
+	if len(b.Playbooks) > MaxPlaybooksPerBundle {
+		return errors.Errorf("bundle cannot contain more than %d playbooks", MaxPlaybooksPerBundle)
+	}
+
+	seenKeys := make(map[string]bool, len(b.Playbooks))
+	for i := range b.Playbooks {
+		entry := &b.Playbooks[i]
+		if entry.Version == 0 {
+			entry.Version = b.Version
+		}
+		if !IsSupportedPlaybookExportVersion(entry.Version) {
+			return errors.Errorf("playbook %q has unsupported export version %d", entry.Title, entry.Version)
+		}
+
+		if entry.ID != "" {
+			return errors.Errorf("playbook %q should not have ID field", entry.Title)
+		}
+
+		if entry.ExportKey == "" {
+			continue
+		}
+		if seenKeys[entry.ExportKey] {
+			return errors.Errorf("export key %q is used by more than one playbook", entry.ExportKey)
+		}
+		seenKeys[entry.ExportKey] = true
+	}
+
+	return nil
+}
+
+func (b *PlaybookBundle) exportKeys() []string {
+	keys := make([]string, 0, len(b.Playbooks))
+	for _, entry := range b.Playbooks {
+		if entry.ExportKey != "" {
+			keys = append(keys, entry.ExportKey)
+		}
+	}
+	return keys
+}
+
+// planBundleImport pairs every entry of the bundle with the existing playbook sharing its export
+// key, if any. Entries without an export key always create a new playbook.
+func planBundleImport(bundle PlaybookBundle, existingIDs map[string]string) *PlaybookBundleImportResult {
+	result := &PlaybookBundleImportResult{
+		Playbooks: make([]PlaybookBundleImportItem, 0, len(bundle.Playbooks)),
+	}
+
+	for _, entry := range bundle.Playbooks {
+		item := PlaybookBundleImportItem{
+			ExportKey:         entry.ExportKey,
+			Title:             entry.Title,
+			Action:            BundleImportActionCreate,
+			NumChannelActions: len(entry.ChannelActions),
+		}
+		if id, ok := existingIDs[entry.ExportKey]; ok && entry.ExportKey != "" {
+			item.Action = BundleImportActionUpdate
+			item.PlaybookID = id
+		}
+		result.Playbooks = append(result.Playbooks, item)
+	}
+
+	return result
+}
+
+// applyExportedFields copies every field of src tagged for export onto dst, leaving server
+// specific fields such as the ID, team, members and counters untouched.
+func applyExportedFields(dst *Playbook, src Playbook) {
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src)
+	srcType := srcValue.Type()
+	for i := 0; i < srcType.NumField(); i++ {
+		tag := srcType.Field(i).Tag.Get("export")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		dstValue.Field(i).Set(srcValue.Field(i))
+	}
+}
+
+// matchMetricIDs reuses the IDs of existing metrics with the same title, so that updating a
+// playbook from a bundle keeps the metric data already recorded by its runs.
+func matchMetricIDs(metrics []PlaybookMetricConfig, existing []PlaybookMetricConfig) []PlaybookMetricConfig {
+	existingByTitle := make(map[string]string, len(existing))
+	for _, metric := range existing {
+		existingByTitle[metric.Title] = metric.ID
+	}
+
+	matched := make([]PlaybookMetricConfig, 0, len(metrics))
+	for _, metric := range metrics {
+		metric.ID = existingByTitle[metric.Title]
+		metric.PlaybookID = ""
+		matched = append(matched, metric)
+	}
+	return matched
+}
+
+// resolveChannelActionPlaybookIDs replaces the playbook referenced by prompt and start run payloads
+// using playbookIDs: IDs by export keys on export, and export keys by the IDs of the playbooks
+// created or updated on import.
+func resolveChannelActionPlaybookIDs(payload interface{}, playbookIDs map[string]string) interface{} {
+	switch typed := payload.(type) {
+	case PromptRunPlaybookFromKeywordsPayload:
+		if id, ok := playbookIDs[typed.PlaybookID]; ok {
+			typed.PlaybookID = id
+		}
+		return typed
+	case StartRunPayload:
+		if id, ok := playbookIDs[typed.PlaybookID]; ok {
+			typed.PlaybookID = id
+		}
+		return typed
+	default:
+		return payload
+	}
+}
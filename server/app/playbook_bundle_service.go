@@ -0,0 +1,291 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+type playbookBundleService struct {
+	store                PlaybookStore
+	playbookService      PlaybookService
+	propertyService      PropertyService
+	conditionService     ConditionService
+	channelActionService ChannelActionService
+	auditor              Auditor
+	applier              playbookExportApplier
+}
+
+// NewPlaybookBundleService returns a new playbook bundle service
+func NewPlaybookBundleService(store PlaybookStore, playbookService PlaybookService, propertyService PropertyService, conditionService ConditionService, channelActionService ChannelActionService, auditor Auditor) PlaybookBundleService {
+	return &playbookBundleService{
+		store:                store,
+		playbookService:      playbookService,
+		propertyService:      propertyService,
+		conditionService:     conditionService,
+		channelActionService: channelActionService,
+		auditor:              auditor,
+		applier: playbookExportApplier{
+			playbookService:  playbookService,
+			propertyService:  propertyService,
+			conditionService: conditionService,
+		},
+	}
+}
+
+func (s *playbookBundleService) Export(playbookIDs []string) ([]byte, error) {
+	if len(playbookIDs) > MaxPlaybooksPerBundle {
+		return nil, errors.Errorf("cannot export more than %d playbooks at once", MaxPlaybooksPerBundle)
+	}
+
+	sources := make([]PlaybookExportSource, 0, len(playbookIDs))
+	for _, playbookID := range playbookIDs {
+		playbook, err := s.playbookService.Get(playbookID)
+		if err != nil {
+			return nil, err
+		}
+
+		properties, err := s.propertyService.GetPropertyFields(playbookID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get property fields for playbook %s", playbookID)
+		}
+
+		conditions, err := s.playbookService.GetPlaybookConditionsForExport(playbookID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get conditions for playbook %s", playbookID)
+		}
+
+		var channelActions []GenericChannelAction
+		if playbook.ChannelID != "" {
+			channelActions, err = s.channelActionService.GetChannelActions(playbook.ChannelID, GetChannelActionOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get channel actions for playbook %s", playbookID)
+			}
+		}
+
+		sources = append(sources, PlaybookExportSource{
+			Playbook:       playbook,
+			Properties:     properties,
+			Conditions:     conditions,
+			ChannelActions: channelActions,
+		})
+	}
+
+	return GeneratePlaybookBundleExport(sources)
+}
+
+func (s *playbookBundleService) Plan(bundle PlaybookBundle, teamID string) (*PlaybookBundleImportResult, error) {
+	existingIDs, err := s.store.GetPlaybookIDsByExportKeys(teamID, bundle.exportKeys())
+	if err != nil {
+		return nil, err
+	}
+
+	result := planBundleImport(bundle, existingIDs)
+	result.DryRun = true
+
+	return result, nil
+}
+
+func (s *playbookBundleService) Import(bundle PlaybookBundle, teamID, userID string) (*PlaybookBundleImportResult, error) {
+	auditRec := s.auditor.MakeAuditRecord("importPlaybookBundle", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "teamID", teamID)
+	model.AddEventParameterToAuditRec(auditRec, "numPlaybooks", len(bundle.Playbooks))
+
+	result, err := s.Plan(bundle, teamID)
+	if err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return nil, err
+	}
+	result.DryRun = false
+
+	// Nothing spans all the services involved in an import, so a failed import is undone
+	// instead: created playbooks are archived, updated ones and their channel actions restored.
+	var undo bundleImportUndo
+	fail := func(err error) (*PlaybookBundleImportResult, error) {
+		auditRec.AddErrorDesc(err.Error())
+		undo.run()
+		return nil, err
+	}
+
+	// Import every playbook before the channel actions, so that actions may reference any
+	// playbook of the bundle regardless of the order of the entries.
+	playbookIDs := make(map[string]string, len(bundle.Playbooks))
+	for i, entry := range bundle.Playbooks {
+		item := &result.Playbooks[i]
+
+		switch item.Action {
+		case BundleImportActionUpdate:
+			err = s.updateFromEntry(item.PlaybookID, entry, userID, &undo)
+		default:
+			item.PlaybookID, err = s.createFromEntry(entry, teamID, userID)
+			if err == nil {
+				undo.add(s.archiveStep(item.PlaybookID, userID))
+			}
+		}
+		if err != nil {
+			return fail(errors.Wrapf(err, "failed to import playbook %q", entry.Title))
+		}
+
+		if entry.ExportKey != "" {
+			playbookIDs[entry.ExportKey] = item.PlaybookID
+		}
+	}
+
+	for _, entry := range bundle.Playbooks {
+		if err := s.importChannelActions(entry, playbookIDs, userID, &undo); err != nil {
+			return fail(errors.Wrapf(err, "failed to import channel actions for playbook %q", entry.Title))
+		}
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(result)
+
+	return result, nil
+}
+
+// bundleImportUndo collects the steps reverting what an import did so far.
+type bundleImportUndo struct {
+	steps []func() error
+}
+
+func (u *bundleImportUndo) add(step func() error) {
+	u.steps = append(u.steps, step)
+}
+
+// run reverts the import, last change first. Steps that fail are logged and skipped, so that
+// as much as possible is reverted.
+func (u *bundleImportUndo) run() {
+	for i := len(u.steps) - 1; i >= 0; i-- {
+		if err := u.steps[i](); err != nil {
+			logrus.WithError(err).Warn("failed to revert part of a failed playbook bundle import")
+		}
+	}
+}
+
+func (s *playbookBundleService) archiveStep(playbookID, userID string) func() error {
+	return func() error {
+		playbook, err := s.playbookService.Get(playbookID)
+		if err != nil {
+			return err
+		}
+		return s.playbookService.Archive(playbook, userID)
+	}
+}
+
+// updateFromEntry updates an existing playbook from a bundle entry, after snapshotting it so that
+// undo can restore it.
+func (s *playbookBundleService) updateFromEntry(playbookID string, entry PlaybookBundleEntry, userID string, undo *bundleImportUndo) error {
+	playbook, err := s.playbookService.Get(playbookID)
+	if err != nil {
+		return err
+	}
+	properties, err := s.propertyService.GetPropertyFields(playbookID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get property fields for playbook %s", playbookID)
+	}
+	conditions, err := s.playbookService.GetPlaybookConditionsForExport(playbookID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get conditions for playbook %s", playbookID)
+	}
+	snapshot, err := NewPlaybookRevisionSnapshot(playbook, properties, conditions)
+	if err != nil {
+		return err
+	}
+	previous, err := PlaybookRevision{PlaybookID: playbookID, Snapshot: snapshot}.Entry()
+	if err != nil {
+		return err
+	}
+
+	if err := s.applier.apply(playbookID, entry, nil, userID); err != nil {
+		return err
+	}
+	undo.add(func() error {
+		return s.applier.apply(playbookID, previous.PlaybookBundleEntry, previous.RunSettings, userID)
+	})
+
+	return nil
+}
+
+func (s *playbookBundleService) createFromEntry(entry PlaybookBundleEntry, teamID, userID string) (string, error) {
+	playbook := entry.Playbook
+	playbook.TeamID = teamID
+	playbook.NextRunNumber = 0
+
+	// Make the importer the sole admin of the playbook.
+	playbook.Members = []PlaybookMember{
+		{
+			UserID: userID,
+			Roles:  []string{PlaybookRoleMember, PlaybookRoleAdmin},
+		},
+	}
+
+	// Force imported playbooks to be public to avoid licencing issues
+	playbook.Public = true
+
+	return s.playbookService.Import(PlaybookImportData{
+		Playbook:   playbook,
+		Version:    entry.Version,
+		Properties: entry.Properties,
+		Conditions: entry.Conditions,
+	}, userID)
+}
+
+// importChannelActions creates or updates the actions of the channel linked to the playbook. A
+// channel holds at most one action per action and trigger type, which is what makes the import
+// idempotent.
+func (s *playbookBundleService) importChannelActions(entry PlaybookBundleEntry, playbookIDs map[string]string, userID string, undo *bundleImportUndo) error {
+	if entry.ChannelID == "" || len(entry.ChannelActions) == 0 {
+		return nil
+	}
+
+	for _, exported := range entry.ChannelActions {
+		payload, err := DecodeChannelActionPayload(exported.ActionType, exported.Payload)
+		if err != nil {
+			return err
+		}
+
+		action := GenericChannelAction{
+			GenericChannelActionWithoutPayload: GenericChannelActionWithoutPayload{
+				ChannelID:   entry.ChannelID,
+				Enabled:     exported.Enabled,
+				ActionType:  exported.ActionType,
+				TriggerType: exported.TriggerType,
+			},
+			Payload: resolveChannelActionPlaybookIDs(payload, playbookIDs),
+		}
+
+		existing, err := s.channelActionService.GetChannelActions(entry.ChannelID, GetChannelActionOptions{
+			ActionType:  exported.ActionType,
+			TriggerType: exported.TriggerType,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Channel actions cannot be deleted, so undoing a created action disables it.
+		if len(existing) > 0 {
+			previous := existing[0]
+			action.ID = previous.ID
+			if err = s.channelActionService.Update(action, userID); err != nil {
+				return err
+			}
+			undo.add(func() error { return s.channelActionService.Update(previous, userID) })
+		} else {
+			if action.ID, err = s.channelActionService.Create(action); err != nil {
+				return err
+			}
+			created := action
+			created.Enabled = false
+			undo.add(func() error { return s.channelActionService.Update(created, userID) })
+		}
+	}
+
+	return nil
+}
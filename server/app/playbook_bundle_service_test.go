@@ -0,0 +1,45 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+	mock_app "github.com/mattermost/mattermost-plugin-playbooks/server/app/mocks"
+)
+
+func TestPlaybookBundleServicePlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mock_app.NewMockPlaybookStore(ctrl)
+	service := app.NewPlaybookBundleService(store, nil, nil, nil, nil, nil)
+
+	bundle := app.PlaybookBundle{
+		Version: app.CurrentPlaybookExportVersion,
+		Playbooks: []app.PlaybookBundleEntry{
+			{Playbook: app.Playbook{Title: "Existing", ExportKey: "existing-key"}},
+			{Playbook: app.Playbook{Title: "New", ExportKey: "new-key"}, ChannelActions: []app.ExportChannelAction{{}}},
+			{Playbook: app.Playbook{Title: "Legacy"}},
+		},
+	}
+
+	store.EXPECT().
+		GetPlaybookIDsByExportKeys("team1", []string{"existing-key", "new-key"}).
+		Return(map[string]string{"existing-key": "playbook1"}, nil)
+
+	result, err := service.Plan(bundle, "team1")
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []app.PlaybookBundleImportItem{
+		{ExportKey: "existing-key", Title: "Existing", Action: app.BundleImportActionUpdate, PlaybookID: "playbook1"},
+		{ExportKey: "new-key", Title: "New", Action: app.BundleImportActionCreate, NumChannelActions: 1},
+		{Title: "Legacy", Action: app.BundleImportActionCreate},
+	}, result.Playbooks)
+	assert.Equal(t, 2, result.NumCreates())
+}
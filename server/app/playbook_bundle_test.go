@@ -0,0 +1,275 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestGeneratePlaybookBundleExport(t *testing.T) {
+	incident := Playbook{
+		ID:        "playbook1",
+		Title:     "Incident",
+		ChannelID: "channel1",
+		Checklists: []Checklist{
+			{Title: "Triage", Items: []ChecklistItem{{Title: "Page on-call"}}},
+		},
+	}
+	security := Playbook{
+		ID:        "playbook2",
+		Title:     "Security",
+		ExportKey: "security-key",
+	}
+
+	output, err := GeneratePlaybookBundleExport([]PlaybookExportSource{
+		{
+			Playbook: incident,
+			ChannelActions: []GenericChannelAction{
+				{
+					GenericChannelActionWithoutPayload: GenericChannelActionWithoutPayload{
+						ID:          "action1",
+						ChannelID:   "channel1",
+						Enabled:     true,
+						ActionType:  ActionTypePromptRunPlaybook,
+						TriggerType: TriggerTypeKeywordsPosted,
+					},
+					Payload: PromptRunPlaybookFromKeywordsPayload{
+						Keywords:   []string{"breach"},
+						PlaybookID: "playbook2",
+					},
+				},
+				{
+					GenericChannelActionWithoutPayload: GenericChannelActionWithoutPayload{
+						ID:          "action2",
+						ChannelID:   "channel1",
+						DeleteAt:    1,
+						ActionType:  ActionTypeWelcomeMessage,
+						TriggerType: TriggerTypeNewMemberJoins,
+					},
+					Payload: WelcomeMessagePayload{Message: "deleted"},
+				},
+			},
+		},
+		{Playbook: security},
+	})
+	require.NoError(t, err)
+
+	bundle, err := ParsePlaybookBundle(output)
+	require.NoError(t, err)
+
+	assert.Equal(t, CurrentPlaybookExportVersion, bundle.Version)
+	require.Len(t, bundle.Playbooks, 2)
+
+	// Playbooks without an export key are keyed by their ID
+	assert.Equal(t, "playbook1", bundle.Playbooks[0].ExportKey)
+	assert.Equal(t, "security-key", bundle.Playbooks[1].ExportKey)
+	assert.Empty(t, bundle.Playbooks[0].ID)
+	assert.Equal(t, "Page on-call", bundle.Playbooks[0].Checklists[0].Items[0].Title)
+
+	// Deleted actions are skipped and references to bundled playbooks use their export key
+	require.Len(t, bundle.Playbooks[0].ChannelActions, 1)
+	action := bundle.Playbooks[0].ChannelActions[0]
+	assert.Empty(t, bundle.Playbooks[1].ChannelActions)
+
+	payload, err := DecodeChannelActionPayload(action.ActionType, action.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, PromptRunPlaybookFromKeywordsPayload{
+		Keywords:   []string{"breach"},
+		PlaybookID: "security-key",
+	}, payload)
+}
+
+func TestParsePlaybookBundle(t *testing.T) {
+	t.Run("version 1 single playbook export", func(t *testing.T) {
+		bundle, err := ParsePlaybookBundle([]byte(`{"title": "Legacy", "version": 1, "checklists": []}`))
+		require.NoError(t, err)
+		require.Len(t, bundle.Playbooks, 1)
+		assert.Equal(t, "Legacy", bundle.Playbooks[0].Title)
+		assert.Equal(t, 1, bundle.Playbooks[0].Version)
+		assert.Empty(t, bundle.Playbooks[0].ExportKey)
+	})
+
+	t.Run("bare array of playbook exports", func(t *testing.T) {
+		bundle, err := ParsePlaybookBundle([]byte(`[
+			{"title": "One", "version": 1},
+			{"title": "Two", "version": 2, "export_key": "two"}
+		]`))
+		require.NoError(t, err)
+		require.Len(t, bundle.Playbooks, 2)
+		assert.Equal(t, "two", bundle.Playbooks[1].ExportKey)
+	})
+
+	t.Run("entries inherit the bundle version", func(t *testing.T) {
+		bundle, err := ParsePlaybookBundle([]byte(`{"version": 2, "playbooks": [{"title": "One"}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, 2, bundle.Playbooks[0].Version)
+	})
+
+	t.Run("invalid bundles", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"empty":               ``,
+			"not an object":       `"playbook"`,
+			"no playbooks":        `{"version": 2, "playbooks": []}`,
+			"unsupported version": `{"version": 99, "playbooks": [{"title": "One"}]}`,
+			"missing version":     `[{"title": "One"}]`,
+			"playbook with ID":    `{"version": 2, "playbooks": [{"id": "abc", "title": "One"}]}`,
+			"duplicate keys":      `{"version": 2, "playbooks": [{"title": "One", "export_key": "k"}, {"title": "Two", "export_key": "k"}]}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := ParsePlaybookBundle([]byte(data))
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestApplyExportedFields(t *testing.T) {
+	existing := Playbook{
+		ID:             "playbook1",
+		TeamID:         "team1",
+		Title:          "Old title",
+		Public:         false,
+		InvitedUserIDs: []string{"user1"},
+		ExportKey:      "key",
+		Members:        []PlaybookMember{{UserID: "user1"}},
+	}
+	imported := Playbook{
+		Title:                "New title",
+		Public:               true,
+		ExportKey:            "key",
+		StatusUpdateEnabled:  true,
+		RetrospectiveEnabled: true,
+	}
+
+	applyExportedFields(&existing, imported)
+
+	assert.Equal(t, "New title", existing.Title)
+	assert.True(t, existing.StatusUpdateEnabled)
+	assert.True(t, existing.RetrospectiveEnabled)
+
+	// Fields not exported stay untouched
+	assert.Equal(t, "playbook1", existing.ID)
+	assert.Equal(t, "team1", existing.TeamID)
+	assert.False(t, existing.Public)
+	assert.Equal(t, []string{"user1"}, existing.InvitedUserIDs)
+	assert.Len(t, existing.Members, 1)
+}
+
+func TestMatchMetricIDs(t *testing.T) {
+	existing := []PlaybookMetricConfig{
+		{ID: "metric1", PlaybookID: "playbook1", Title: "Time to resolve"},
+		{ID: "metric2", PlaybookID: "playbook1", Title: "Cost"},
+	}
+	imported := []PlaybookMetricConfig{
+		{Title: "Cost", Target: null.IntFrom(10)},
+		{Title: "Customers affected"},
+	}
+
+	matched := matchMetricIDs(imported, existing)
+	require.Len(t, matched, 2)
+	assert.Equal(t, "metric2", matched[0].ID)
+	assert.Equal(t, null.IntFrom(10), matched[0].Target)
+	assert.Empty(t, matched[1].ID)
+}
+
+func TestMatchOptionIDs(t *testing.T) {
+	existing := model.PropertyOptions[*model.PluginPropertyOption]{
+		model.NewPluginPropertyOption("existinghigh", "High"),
+	}
+	exported := model.PropertyOptions[*model.PluginPropertyOption]{
+		model.NewPluginPropertyOption("exportedhigh", "High"),
+		model.NewPluginPropertyOption("exportedlow", "Low"),
+	}
+
+	matched := matchOptionIDs(exported, existing)
+	require.Len(t, matched, 2)
+	assert.Equal(t, "existinghigh", matched[0].GetID())
+	assert.Empty(t, matched[1].GetID())
+	assert.Equal(t, "Low", matched[1].GetName())
+
+	// The exported options are left untouched so they can still be mapped
+	assert.Equal(t, "exportedhigh", exported[0].GetID())
+}
+
+func TestResolveChannelActionPlaybookIDs(t *testing.T) {
+	var raw interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"keywords": ["sev1"], "playbook_id": "incident-key"}`), &raw))
+
+	payload, err := DecodeChannelActionPayload(ActionTypePromptRunPlaybook, raw)
+	require.NoError(t, err)
+
+	resolved := resolveChannelActionPlaybookIDs(payload, map[string]string{"incident-key": "playbook1"})
+	assert.Equal(t, PromptRunPlaybookFromKeywordsPayload{Keywords: []string{"sev1"}, PlaybookID: "playbook1"}, resolved)
+
+	welcome := WelcomeMessagePayload{Message: "hello"}
+	assert.Equal(t, welcome, resolveChannelActionPlaybookIDs(welcome, map[string]string{}))
+}
+
+type bundleImportStore struct {
+	PlaybookStore
+}
+
+func (s bundleImportStore) GetPlaybookIDsByExportKeys(string, []string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+type bundleImportPlaybookService struct {
+	PlaybookService
+	created  []string
+	archived []string
+}
+
+func (s *bundleImportPlaybookService) Import(data PlaybookImportData, _ string) (string, error) {
+	if data.Playbook.Title == "Broken" {
+		return "", errors.New("failed to create playbook")
+	}
+	id := model.NewId()
+	s.created = append(s.created, id)
+	return id, nil
+}
+
+func (s *bundleImportPlaybookService) Get(id string) (Playbook, error) {
+	return Playbook{ID: id}, nil
+}
+
+func (s *bundleImportPlaybookService) Archive(playbook Playbook, _ string) error {
+	s.archived = append(s.archived, playbook.ID)
+	return nil
+}
+
+type bundleImportAuditor struct{}
+
+func (bundleImportAuditor) MakeAuditRecord(string, string) *model.AuditRecord {
+	return &model.AuditRecord{}
+}
+
+func (bundleImportAuditor) LogAuditRec(*model.AuditRecord) {}
+
+func TestPlaybookBundleServiceImportUndo(t *testing.T) {
+	playbookService := &bundleImportPlaybookService{}
+	service := NewPlaybookBundleService(bundleImportStore{}, playbookService, nil, nil, nil, bundleImportAuditor{})
+
+	bundle := PlaybookBundle{
+		Version: CurrentPlaybookExportVersion,
+		Playbooks: []PlaybookBundleEntry{
+			{Playbook: Playbook{Title: "Incident", ExportKey: "incident"}},
+			{Playbook: Playbook{Title: "Broken", ExportKey: "broken"}},
+		},
+	}
+
+	_, err := service.Import(bundle, "team1", "user1")
+	require.Error(t, err)
+
+	// The playbook created before the failure is archived again.
+	require.Len(t, playbookService.created, 1)
+	assert.Equal(t, playbookService.created, playbookService.archived)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// playbookExportApplier updates existing playbooks from data in export format. It backs both
+// bundle imports and revision rollbacks.
+type playbookExportApplier struct {
+	playbookService  PlaybookService
+	propertyService  PropertyService
+	conditionService ConditionService
+}
+
+// apply overwrites the exported fields of an existing playbook with the given entry, and its run
+// settings with runSettings when not nil. Property fields are matched by name so their IDs, and
+// the run values referencing them, survive the update; conditions are recreated from the entry.
+func (s playbookExportApplier) apply(playbookID string, entry PlaybookBundleEntry, runSettings *PlaybookRunSettings, userID string) error {
+	existing, err := s.playbookService.Get(playbookID)
+	if err != nil {
+		return err
+	}
+
+	playbook := existing.Clone()
+	applyExportedFields(&playbook, entry.Playbook)
+	if runSettings != nil {
+		runSettings.ApplyTo(&playbook)
+	}
+	playbook.Checklists = entry.Checklists
+	playbook.Metrics = matchMetricIDs(entry.Metrics, existing.Metrics)
+
+	condRefs := saveAndClearConditionRefs(&playbook)
+
+	existingConditions, err := s.playbookService.GetPlaybookConditionsForExport(playbookID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get existing conditions")
+	}
+	for _, condition := range existingConditions {
+		if err := s.conditionService.DeletePlaybookCondition(userID, playbookID, condition.ID, existing.TeamID); err != nil {
+			return errors.Wrapf(err, "failed to delete condition %s", condition.ID)
+		}
+	}
+
+	propertyMappings, err := s.syncPropertiesFromExport(playbookID, entry.Properties)
+	if err != nil {
+		return err
+	}
+
+	if len(entry.Conditions) > 0 && len(propertyMappings.FieldMappings) > 0 {
+		conditionMapping, err := s.conditionService.CreateConditionsFromExport(playbookID, entry.Conditions, propertyMappings)
+		if err != nil {
+			return errors.Wrap(err, "failed to create conditions from export")
+		}
+		remapChecklistConditions(&playbook, conditionMapping, condRefs)
+	}
+
+	remapAssigneePropertyFieldIDs(playbook.Checklists, propertyMappings.FieldMappings)
+
+	return s.playbookService.Update(playbook, userID)
+}
+
+// syncPropertiesFromExport updates the property fields of a playbook sharing a name with an
+// exported field, creates the missing ones and returns the mapping from exported to local IDs.
+// Local fields absent from the export are kept, since run values may still reference them.
+func (s playbookExportApplier) syncPropertiesFromExport(playbookID string, properties []ExportPropertyField) (*PropertyCopyResult, error) {
+	result := &PropertyCopyResult{
+		FieldMappings:  make(map[string]string),
+		OptionMappings: make(map[string]string),
+	}
+
+	existingFields, err := s.propertyService.GetPropertyFields(playbookID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get existing property fields")
+	}
+	existingByName := make(map[string]PropertyField, len(existingFields))
+	for _, field := range existingFields {
+		existingByName[field.Name] = field
+	}
+
+	for _, exportProp := range properties {
+		field := PropertyField{
+			PropertyField: model.PropertyField{
+				Name: exportProp.Name,
+				Type: exportProp.Type,
+			},
+			Attrs: exportProp.Attrs,
+		}
+		field.Attrs.ParentID = ""
+
+		existingField, exists := existingByName[exportProp.Name]
+		field.Attrs.Options = matchOptionIDs(exportProp.Attrs.Options, existingField.Attrs.Options)
+
+		var saved *PropertyField
+		if exists {
+			field.ID = existingField.ID
+			saved, err = s.propertyService.UpdatePropertyField(playbookID, field)
+		} else {
+			saved, err = s.propertyService.CreatePropertyField(playbookID, field)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to import property field %q", exportProp.Name)
+		}
+
+		result.FieldMappings[exportProp.ID] = saved.ID
+		result.CopiedFields = append(result.CopiedFields, *saved)
+		if saved.SupportsOptions() {
+			for j, oldOption := range exportProp.Attrs.Options {
+				if j < len(saved.Attrs.Options) {
+					result.OptionMappings[oldOption.GetID()] = saved.Attrs.Options[j].GetID()
+				}
+			}
+		}
+	}
+
+	for i, exportProp := range properties {
+		if exportProp.Attrs.ParentID == "" {
+			continue
+		}
+		newParentID, ok := result.FieldMappings[exportProp.Attrs.ParentID]
+		if !ok {
+			continue
+		}
+		field := result.CopiedFields[i]
+		field.Attrs.ParentID = newParentID
+		if _, err := s.propertyService.UpdatePropertyField(playbookID, field); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"playbook_id":   playbookID,
+				"field_id":      field.ID,
+				"new_parent_id": newParentID,
+			}).Warn("failed to remap ParentID on imported property field")
+		}
+	}
+
+	return result, nil
+}
+
+// matchOptionIDs copies the exported options, reusing the ID of the existing option with the same
+// name and leaving new options without ID so that one is generated.
+func matchOptionIDs(exported, existing model.PropertyOptions[*model.PluginPropertyOption]) model.PropertyOptions[*model.PluginPropertyOption] {
+	if exported == nil {
+		return nil
+	}
+
+	existingByName := make(map[string]string, len(existing))
+	for _, option := range existing {
+		existingByName[option.GetName()] = option.GetID()
+	}
+
+	matched := make(model.PropertyOptions[*model.PluginPropertyOption], 0, len(exported))
+	for _, option := range exported {
+		data := make(map[string]string, len(option.Data))
+		for k, v := range option.Data {
+			data[k] = v
+		}
+		copied := &model.PluginPropertyOption{Data: data}
+		copied.SetID(existingByName[option.GetName()])
+		matched = append(matched, copied)
+	}
+	return matched
+}
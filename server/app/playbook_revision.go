@@ -0,0 +1,258 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// PlaybookRevision is an immutable snapshot of a playbook, recorded every time it changes.
+type PlaybookRevision struct {
+	ID         string `json:"id"`
+	PlaybookID string `json:"playbook_id"`
+
+	// Revision numbers the snapshots of a playbook, starting at 1.
+	Revision int64  `json:"revision"`
+	UserID   string `json:"user_id"`
+	CreateAt int64  `json:"create_at"`
+
+	// Snapshot holds the playbook in export format, including its property fields and conditions,
+	// and its run settings. It is left out when listing revisions.
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+}
+
+// PlaybookRunSettings are the settings runs are created with that are specific to this server, and
+// so are left out of exports but kept in revisions.
+type PlaybookRunSettings struct {
+	CreatePublicPlaybookRun      bool     `json:"create_public_playbook_run"`
+	InvitedUserIDs               []string `json:"invited_user_ids"`
+	InvitedGroupIDs              []string `json:"invited_group_ids"`
+	InviteUsersEnabled           bool     `json:"invite_users_enabled"`
+	DefaultOwnerID               string   `json:"default_owner_id"`
+	DefaultOwnerEnabled          bool     `json:"default_owner_enabled"`
+	BroadcastChannelIDs          []string `json:"broadcast_channel_ids"`
+	BroadcastEnabled             bool     `json:"broadcast_enabled"`
+	WebhookOnCreationURLs        []string `json:"webhook_on_creation_urls"`
+	WebhookOnCreationEnabled     bool     `json:"webhook_on_creation_enabled"`
+	WebhookOnStatusUpdateURLs    []string `json:"webhook_on_status_update_urls"`
+	WebhookOnStatusUpdateEnabled bool     `json:"webhook_on_status_update_enabled"`
+}
+
+// NewPlaybookRunSettings returns the run settings of playbook.
+func NewPlaybookRunSettings(playbook Playbook) PlaybookRunSettings {
+	return PlaybookRunSettings{
+		CreatePublicPlaybookRun:      playbook.CreatePublicPlaybookRun,
+		InvitedUserIDs:               playbook.InvitedUserIDs,
+		InvitedGroupIDs:              playbook.InvitedGroupIDs,
+		InviteUsersEnabled:           playbook.InviteUsersEnabled,
+		DefaultOwnerID:               playbook.DefaultOwnerID,
+		DefaultOwnerEnabled:          playbook.DefaultOwnerEnabled,
+		BroadcastChannelIDs:          playbook.BroadcastChannelIDs,
+		BroadcastEnabled:             playbook.BroadcastEnabled,
+		WebhookOnCreationURLs:        playbook.WebhookOnCreationURLs,
+		WebhookOnCreationEnabled:     playbook.WebhookOnCreationEnabled,
+		WebhookOnStatusUpdateURLs:    playbook.WebhookOnStatusUpdateURLs,
+		WebhookOnStatusUpdateEnabled: playbook.WebhookOnStatusUpdateEnabled,
+	}
+}
+
+// ApplyTo sets the run settings of playbook.
+func (s PlaybookRunSettings) ApplyTo(playbook *Playbook) {
+	playbook.CreatePublicPlaybookRun = s.CreatePublicPlaybookRun
+	playbook.InvitedUserIDs = s.InvitedUserIDs
+	playbook.InvitedGroupIDs = s.InvitedGroupIDs
+	playbook.InviteUsersEnabled = s.InviteUsersEnabled
+	playbook.DefaultOwnerID = s.DefaultOwnerID
+	playbook.DefaultOwnerEnabled = s.DefaultOwnerEnabled
+	playbook.BroadcastChannelIDs = s.BroadcastChannelIDs
+	playbook.BroadcastEnabled = s.BroadcastEnabled
+	playbook.WebhookOnCreationURLs = s.WebhookOnCreationURLs
+	playbook.WebhookOnCreationEnabled = s.WebhookOnCreationEnabled
+	playbook.WebhookOnStatusUpdateURLs = s.WebhookOnStatusUpdateURLs
+	playbook.WebhookOnStatusUpdateEnabled = s.WebhookOnStatusUpdateEnabled
+}
+
+// PlaybookRevisionEntry is the decoded snapshot of a revision.
+type PlaybookRevisionEntry struct {
+	PlaybookBundleEntry
+
+	// RunSettings is nil in revisions recorded before run settings were kept.
+	RunSettings *PlaybookRunSettings `json:"run_settings,omitempty"`
+}
+
+type GetPlaybookRevisionsResults struct {
+	TotalCount int                `json:"total_count"`
+	PageCount  int                `json:"page_count"`
+	HasMore    bool               `json:"has_more"`
+	Items      []PlaybookRevision `json:"items"`
+}
+
+// MarshalJSON customizes the JSON marshalling for GetPlaybookRevisionsResults by rendering a nil
+// Items as an empty slice instead.
+func (r GetPlaybookRevisionsResults) MarshalJSON() ([]byte, error) {
+	type Alias GetPlaybookRevisionsResults
+
+	if r.Items == nil {
+		r.Items = []PlaybookRevision{}
+	}
+
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(&r),
+	}
+
+	return json.Marshal(aux)
+}
+
+// PlaybookRevisionChange is a single difference between two revisions. Path points to the changed
+// value inside the snapshot, e.g. "checklists[0].items[2].title". Old is nil when the value was
+// added and New is nil when it was removed.
+type PlaybookRevisionChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// PlaybookRevisionDiff lists the changes needed to go from one revision to another.
+type PlaybookRevisionDiff struct {
+	PlaybookID   string                   `json:"playbook_id"`
+	FromRevision int64                    `json:"from_revision"`
+	ToRevision   int64                    `json:"to_revision"`
+	Changes      []PlaybookRevisionChange `json:"changes"`
+}
+
+// PlaybookRevisionService lists, compares and restores the revisions of playbooks. Revisions are
+// recorded by PlaybookService whenever a playbook changes.
+type PlaybookRevisionService interface {
+	// GetRevisions returns the revisions of a playbook, newest first, without their snapshots.
+	GetRevisions(playbookID string, page, perPage int) (GetPlaybookRevisionsResults, error)
+
+	// GetRevision returns a single revision of a playbook. Returns ErrNotFound if not found.
+	GetRevision(playbookID string, revision int64) (PlaybookRevision, error)
+
+	// Diff compares two revisions of a playbook.
+	Diff(playbookID string, fromRevision, toRevision int64) (*PlaybookRevisionDiff, error)
+
+	// Rollback restores the checklists, properties, conditions, settings and run settings of a
+	// playbook to the given revision. Its visibility, members, admin-only edit lock and default
+	// roles are left unchanged, as are run settings missing from revisions recorded before they
+	// were snapshotted. The rollback itself is recorded as a new revision.
+	Rollback(playbookID string, revision int64, userID string) error
+
+	// Publish makes the latest revision of a playbook the one new runs are created from, and
+	// returns its number. Callers must check the user is allowed to publish the playbook.
+	Publish(playbookID, userID string) (int64, error)
+}
+
+// NewPlaybookRevisionSnapshot returns the snapshot stored with a revision of the given playbook.
+func NewPlaybookRevisionSnapshot(playbook Playbook, properties []PropertyField, conditions []Condition) ([]byte, error) {
+	export := generatePlaybookExport(playbook, properties, conditions)
+	// Unlike exports, snapshots stay on this server, so checklists keep their item IDs and
+	// pre-assigned users, and the run settings specific to this server are kept too. Runs created
+	// from a published revision depend on them.
+	export["checklists"] = playbook.Checklists
+	export["run_settings"] = NewPlaybookRunSettings(playbook)
+
+	snapshot, err := json.Marshal(export)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal playbook snapshot")
+	}
+
+	return snapshot, nil
+}
+
+// Entry decodes the snapshot of the revision.
+func (r PlaybookRevision) Entry() (PlaybookRevisionEntry, error) {
+	var entry PlaybookRevisionEntry
+	if err := json.Unmarshal(r.Snapshot, &entry); err != nil {
+		return PlaybookRevisionEntry{}, errors.Wrapf(err, "failed to decode snapshot of revision %d", r.Revision)
+	}
+
+	return entry, nil
+}
+
+// DiffPlaybookRevisions compares the snapshots of two revisions of the same playbook.
+func DiffPlaybookRevisions(from, to PlaybookRevision) (*PlaybookRevisionDiff, error) {
+	var fromSnapshot, toSnapshot map[string]interface{}
+	if err := json.Unmarshal(from.Snapshot, &fromSnapshot); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode snapshot of revision %d", from.Revision)
+	}
+	if err := json.Unmarshal(to.Snapshot, &toSnapshot); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode snapshot of revision %d", to.Revision)
+	}
+
+	// The export format version is not part of the playbook.
+	delete(fromSnapshot, "version")
+	delete(toSnapshot, "version")
+
+	diff := &PlaybookRevisionDiff{
+		PlaybookID:   to.PlaybookID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Changes:      []PlaybookRevisionChange{},
+	}
+	diffSnapshotValues("", fromSnapshot, toSnapshot, &diff.Changes)
+
+	return diff, nil
+}
+
+// diffSnapshotValues recursively compares two decoded JSON values. Objects are compared key by
+// key and arrays element by element; anything else is reported as a whole.
+func diffSnapshotValues(path string, from, to interface{}, changes *[]PlaybookRevisionChange) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(fromValue)+len(toValue))
+		for key := range fromValue {
+			keys = append(keys, key)
+		}
+		for key := range toValue {
+			if _, ok := fromValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			diffSnapshotValues(keyPath, fromValue[key], toValue[key], changes)
+		}
+		return
+
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+			var fromElem, toElem interface{}
+			if i < len(fromValue) {
+				fromElem = fromValue[i]
+			}
+			if i < len(toValue) {
+				toElem = toValue[i]
+			}
+			diffSnapshotValues(fmt.Sprintf("%s[%d]", path, i), fromElem, toElem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, PlaybookRevisionChange{Path: path, Old: from, New: to})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+type playbookRevisionService struct {
+	store           PlaybookStore
+	playbookService PlaybookService
+	auditor         Auditor
+	applier         playbookExportApplier
+}
+
+// NewPlaybookRevisionService returns a new playbook revision service
+func NewPlaybookRevisionService(store PlaybookStore, playbookService PlaybookService, propertyService PropertyService, conditionService ConditionService, auditor Auditor) PlaybookRevisionService {
+	return &playbookRevisionService{
+		store:           store,
+		playbookService: playbookService,
+		auditor:         auditor,
+		applier: playbookExportApplier{
+			playbookService:  playbookService,
+			propertyService:  propertyService,
+			conditionService: conditionService,
+		},
+	}
+}
+
+func (s *playbookRevisionService) GetRevisions(playbookID string, page, perPage int) (GetPlaybookRevisionsResults, error) {
+	return s.store.GetPlaybookRevisions(playbookID, page, perPage)
+}
+
+func (s *playbookRevisionService) GetRevision(playbookID string, revision int64) (PlaybookRevision, error) {
+	return s.store.GetPlaybookRevision(playbookID, revision)
+}
+
+func (s *playbookRevisionService) Diff(playbookID string, fromRevision, toRevision int64) (*PlaybookRevisionDiff, error) {
+	from, err := s.store.GetPlaybookRevision(playbookID, fromRevision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get revision %d", fromRevision)
+	}
+
+	to, err := s.store.GetPlaybookRevision(playbookID, toRevision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get revision %d", toRevision)
+	}
+
+	return DiffPlaybookRevisions(from, to)
+}
+
+func (s *playbookRevisionService) Rollback(playbookID string, revision int64, userID string) error {
+	auditRec := s.auditor.MakeAuditRecord("rollbackPlaybook", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", playbookID)
+	model.AddEventParameterToAuditRec(auditRec, "revision", revision)
+
+	target, err := s.store.GetPlaybookRevision(playbookID, revision)
+	if err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return errors.Wrapf(err, "failed to get revision %d", revision)
+	}
+
+	entry, err := target.Entry()
+	if err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+
+	// Updating the playbook records the rollback as a new revision.
+	if err := s.applier.apply(playbookID, entry.PlaybookBundleEntry, entry.RunSettings, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return errors.Wrapf(err, "failed to roll back to revision %d", revision)
+	}
+
+	auditRec.Success()
+
+	return nil
+}
+
+func (s *playbookRevisionService) Publish(playbookID, userID string) (int64, error) {
+	auditRec := s.auditor.MakeAuditRecord("publishPlaybook", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", playbookID)
+
+	playbook, err := s.store.Get(playbookID)
+	if err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return 0, err
+	}
+
+	// Playbooks last changed before revisions were recorded have none to publish yet.
+	if playbook.Revision == 0 {
+		if err = s.playbookService.RecordRevision(playbookID, userID); err != nil {
+			auditRec.AddErrorDesc(err.Error())
+			return 0, errors.Wrap(err, "failed to record revision to publish")
+		}
+		if playbook, err = s.store.Get(playbookID); err != nil {
+			auditRec.AddErrorDesc(err.Error())
+			return 0, err
+		}
+	}
+
+	if err := s.store.SetPublishedRevision(playbookID, playbook.Revision); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return 0, errors.Wrapf(err, "failed to publish revision %d", playbook.Revision)
+	}
+
+	model.AddEventParameterToAuditRec(auditRec, "revision", playbook.Revision)
+	auditRec.Success()
+
+	return playbook.Revision, nil
+}
@@ -0,0 +1,497 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+	mock_app "github.com/mattermost/mattermost-plugin-playbooks/server/app/mocks"
+	mock_bot "github.com/mattermost/mattermost-plugin-playbooks/server/bot/mocks"
+	"github.com/mattermost/mattermost-plugin-playbooks/server/metrics"
+)
+
+// expectRevisionSnapshot expects the lookups made by the playbook service to snapshot a playbook
+// it is about to save.
+func expectRevisionSnapshot(mockStore *mock_app.MockPlaybookStore, mockPropertyService *mock_app.MockPropertyService, mockConditionService *mock_app.MockConditionService, playbookID interface{}) {
+	mockPropertyService.EXPECT().GetPropertyFields(playbookID).Return(nil, nil)
+	mockConditionService.EXPECT().
+		GetPlaybookConditions("", playbookID, 0, app.MaxConditionsPerPlaybook).
+		Return(&app.GetConditionsResults{}, nil)
+}
+
+// expectRevisionRecorded expects the calls made by the playbook service to record a revision of a
+// playbook it has to fetch first.
+func expectRevisionRecorded(mockStore *mock_app.MockPlaybookStore, mockPropertyService *mock_app.MockPropertyService, mockConditionService *mock_app.MockConditionService, playbookID string) {
+	mockStore.EXPECT().Get(playbookID).Return(app.Playbook{ID: playbookID}, nil)
+	expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, playbookID)
+	mockStore.EXPECT().CreatePlaybookRevision(gomock.Any()).Return(app.PlaybookRevision{}, nil)
+}
+
+func TestPlaybookService_RecordRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_app.NewMockPlaybookStore(ctrl)
+	mockPropertyService := mock_app.NewMockPropertyService(ctrl)
+	mockConditionService := mock_app.NewMockConditionService(ctrl)
+
+	service := app.NewPlaybookService(
+		mockStore,
+		mock_bot.NewMockPoster(ctrl),
+		nil,
+		nil,
+		&metrics.Metrics{},
+		mockPropertyService,
+		mockConditionService,
+	)
+
+	playbook := app.Playbook{
+		ID:    "playbook1",
+		Title: "Incident",
+		Checklists: []app.Checklist{
+			{Title: "Triage", Items: []app.ChecklistItem{{Title: "Page on-call"}}},
+		},
+	}
+	field := app.PropertyField{
+		PropertyField: model.PropertyField{ID: "field1", Name: "Severity", Type: model.PropertyFieldTypeText},
+	}
+
+	mockStore.EXPECT().Get("playbook1").Return(playbook, nil)
+	mockPropertyService.EXPECT().GetPropertyFields("playbook1").Return([]app.PropertyField{field}, nil)
+	mockConditionService.EXPECT().
+		GetPlaybookConditions("", "playbook1", 0, app.MaxConditionsPerPlaybook).
+		Return(&app.GetConditionsResults{}, nil)
+
+	var stored app.PlaybookRevision
+	mockStore.EXPECT().
+		CreatePlaybookRevision(gomock.Any()).
+		DoAndReturn(func(revision app.PlaybookRevision) (app.PlaybookRevision, error) {
+			stored = revision
+			revision.Revision = 3
+			return revision, nil
+		})
+
+	err := service.RecordRevision("playbook1", "user1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "playbook1", stored.PlaybookID)
+	assert.Equal(t, "user1", stored.UserID)
+	assert.NotZero(t, stored.CreateAt)
+
+	entry, err := stored.Entry()
+	require.NoError(t, err)
+	assert.Equal(t, "Incident", entry.Title)
+	assert.Equal(t, "Page on-call", entry.Checklists[0].Items[0].Title)
+	require.Len(t, entry.Properties, 1)
+	assert.Equal(t, "Severity", entry.Properties[0].Name)
+}
+
+func TestDiffPlaybookRevisions(t *testing.T) {
+	makeRevision := func(t *testing.T, revision int64, playbook app.Playbook) app.PlaybookRevision {
+		snapshot, err := app.NewPlaybookRevisionSnapshot(playbook, nil, nil)
+		require.NoError(t, err)
+		return app.PlaybookRevision{PlaybookID: "playbook1", Revision: revision, Snapshot: snapshot}
+	}
+
+	from := makeRevision(t, 1, app.Playbook{
+		ID:                  "playbook1",
+		Title:               "Incident",
+		StatusUpdateEnabled: true,
+		Checklists: []app.Checklist{
+			{Title: "Triage", Items: []app.ChecklistItem{{Title: "Page on-call"}}},
+		},
+	})
+	to := makeRevision(t, 2, app.Playbook{
+		ID:    "playbook1",
+		Title: "Major incident",
+		Checklists: []app.Checklist{
+			{Title: "Triage", Items: []app.ChecklistItem{{Title: "Page on-call"}, {Title: "Open bridge"}}},
+		},
+	})
+
+	diff, err := app.DiffPlaybookRevisions(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), diff.FromRevision)
+	assert.Equal(t, int64(2), diff.ToRevision)
+
+	changes := map[string]app.PlaybookRevisionChange{}
+	for _, change := range diff.Changes {
+		changes[change.Path] = change
+	}
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, "Incident", changes["title"].Old)
+	assert.Equal(t, "Major incident", changes["title"].New)
+
+	assert.Equal(t, true, changes["status_update_enabled"].Old)
+	assert.Nil(t, changes["status_update_enabled"].New)
+
+	added, ok := changes["checklists[0].items[1]"]
+	require.True(t, ok)
+	assert.Nil(t, added.Old)
+	assert.Equal(t, "Open bridge", added.New.(map[string]interface{})["title"])
+
+	t.Run("identical revisions", func(t *testing.T) {
+		diff, err := app.DiffPlaybookRevisions(from, from)
+		require.NoError(t, err)
+		assert.Empty(t, diff.Changes)
+
+		data, err := json.Marshal(diff)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"changes":[]`)
+	})
+}
+
+func TestPlaybookRevisionService_Rollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_app.NewMockPlaybookStore(ctrl)
+	mockPropertyService := mock_app.NewMockPropertyService(ctrl)
+	mockConditionService := mock_app.NewMockConditionService(ctrl)
+	mockAuditor := mock_app.NewMockAuditor(ctrl)
+	mockAuditor.EXPECT().MakeAuditRecord(gomock.Any(), gomock.Any()).Return(&model.AuditRecord{}).AnyTimes()
+	mockAuditor.EXPECT().LogAuditRec(gomock.Any()).AnyTimes()
+
+	playbookService := app.NewPlaybookService(
+		mockStore,
+		mock_bot.NewMockPoster(ctrl),
+		nil,
+		mockAuditor,
+		&metrics.Metrics{},
+		mockPropertyService,
+		mockConditionService,
+	)
+	service := app.NewPlaybookRevisionService(mockStore, playbookService, mockPropertyService, mockConditionService, mockAuditor)
+
+	oldVersion := app.Playbook{
+		ID:    "playbook1",
+		Title: "Incident",
+		Checklists: []app.Checklist{
+			{Title: "Triage", Items: []app.ChecklistItem{{Title: "Page on-call"}}},
+		},
+		WebhookOnCreationURLs:    []string{"https://example.com/old"},
+		WebhookOnCreationEnabled: true,
+	}
+	snapshot, err := app.NewPlaybookRevisionSnapshot(oldVersion, nil, nil)
+	require.NoError(t, err)
+
+	current := app.Playbook{
+		ID:                    "playbook1",
+		TeamID:                "team1",
+		Title:                 "Major incident",
+		Members:               []app.PlaybookMember{{UserID: "user1"}},
+		Public:                true,
+		WebhookOnCreationURLs: []string{"https://example.com/new"},
+	}
+
+	t.Run("restores the snapshot", func(t *testing.T) {
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(1)).
+			Return(app.PlaybookRevision{PlaybookID: "playbook1", Revision: 1, Snapshot: snapshot}, nil)
+		mockStore.EXPECT().Get("playbook1").Return(current, nil)
+		mockConditionService.EXPECT().
+			GetPlaybookConditions("", "playbook1", 0, app.MaxConditionsPerPlaybook).
+			Return(&app.GetConditionsResults{}, nil)
+		mockPropertyService.EXPECT().GetPropertyFields("playbook1").Return(nil, nil)
+
+		mockStore.EXPECT().
+			UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).
+			DoAndReturn(func(playbook app.Playbook, _ int64, _ app.PlaybookRevision) error {
+				assert.Equal(t, "Incident", playbook.Title)
+				assert.Equal(t, "team1", playbook.TeamID)
+				assert.Len(t, playbook.Members, 1)
+				assert.True(t, playbook.Public)
+				assert.Equal(t, []string{"https://example.com/old"}, playbook.WebhookOnCreationURLs)
+				assert.True(t, playbook.WebhookOnCreationEnabled)
+				require.Len(t, playbook.Checklists, 1)
+				assert.Equal(t, "Page on-call", playbook.Checklists[0].Items[0].Title)
+				return nil
+			})
+
+		// The rollback is recorded as a new revision
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, "playbook1")
+
+		err := service.Rollback("playbook1", 1, "user2")
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps the run settings missing from older snapshots", func(t *testing.T) {
+		var legacy map[string]interface{}
+		require.NoError(t, json.Unmarshal(snapshot, &legacy))
+		delete(legacy, "run_settings")
+		legacySnapshot, err := json.Marshal(legacy)
+		require.NoError(t, err)
+
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(1)).
+			Return(app.PlaybookRevision{PlaybookID: "playbook1", Revision: 1, Snapshot: legacySnapshot}, nil)
+		mockStore.EXPECT().Get("playbook1").Return(current, nil)
+		mockConditionService.EXPECT().
+			GetPlaybookConditions("", "playbook1", 0, app.MaxConditionsPerPlaybook).
+			Return(&app.GetConditionsResults{}, nil)
+		mockPropertyService.EXPECT().GetPropertyFields("playbook1").Return(nil, nil)
+
+		mockStore.EXPECT().
+			UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).
+			DoAndReturn(func(playbook app.Playbook, _ int64, _ app.PlaybookRevision) error {
+				assert.Equal(t, "Incident", playbook.Title)
+				assert.Equal(t, []string{"https://example.com/new"}, playbook.WebhookOnCreationURLs)
+				return nil
+			})
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, "playbook1")
+
+		err = service.Rollback("playbook1", 1, "user2")
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(7)).
+			Return(app.PlaybookRevision{}, app.ErrNotFound)
+
+		err := service.Rollback("playbook1", 7, "user2")
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
+
+func TestPlaybookService_GetPublished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_app.NewMockPlaybookStore(ctrl)
+	service := app.NewPlaybookService(mockStore, mock_bot.NewMockPoster(ctrl), nil, nil, &metrics.Metrics{}, nil, nil)
+
+	published := app.Playbook{
+		ID:    "playbook1",
+		Title: "Incident",
+		Checklists: []app.Checklist{
+			{Title: "Triage", Items: []app.ChecklistItem{{Title: "Page on-call", AssigneeID: "user1"}}},
+		},
+		Metrics:                  []app.PlaybookMetricConfig{{Title: "Time to detect"}, {Title: "Removed since"}},
+		WebhookOnCreationURLs:    []string{"https://example.com/published"},
+		WebhookOnCreationEnabled: true,
+	}
+	snapshot, err := app.NewPlaybookRevisionSnapshot(published, nil, nil)
+	require.NoError(t, err)
+
+	draft := app.Playbook{
+		ID:                "playbook1",
+		TeamID:            "team1",
+		Title:             "Major incident",
+		Revision:          3,
+		PublishedRevision: 2,
+		Members:           []app.PlaybookMember{{UserID: "user1"}},
+		Checklists: []app.Checklist{
+			{Title: "Triage", Items: []app.ChecklistItem{{Title: "Page on-call"}, {Title: "Open bridge"}}},
+		},
+		Metrics:                  []app.PlaybookMetricConfig{{ID: "metric1", Title: "Time to detect"}},
+		WebhookOnCreationURLs:    []string{"https://example.com/draft"},
+		WebhookOnCreationEnabled: true,
+		BroadcastChannelIDs:      []string{"channel1"},
+	}
+
+	t.Run("draft is replaced by the published revision", func(t *testing.T) {
+		mockStore.EXPECT().Get("playbook1").Return(draft, nil)
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(2)).
+			Return(app.PlaybookRevision{PlaybookID: "playbook1", Revision: 2, Snapshot: snapshot}, nil)
+
+		playbook, err := service.GetPublished("playbook1")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Incident", playbook.Title)
+		assert.Equal(t, int64(2), playbook.Revision)
+		assert.Equal(t, "team1", playbook.TeamID)
+		assert.Len(t, playbook.Members, 1)
+		require.Len(t, playbook.Checklists[0].Items, 1)
+		assert.Equal(t, "user1", playbook.Checklists[0].Items[0].AssigneeID)
+		require.Len(t, playbook.Metrics, 1)
+		assert.Equal(t, "metric1", playbook.Metrics[0].ID)
+		assert.Equal(t, []string{"https://example.com/published"}, playbook.WebhookOnCreationURLs)
+		assert.Empty(t, playbook.BroadcastChannelIDs)
+	})
+
+	t.Run("revision recorded without run settings keeps the current ones", func(t *testing.T) {
+		var legacy map[string]interface{}
+		require.NoError(t, json.Unmarshal(snapshot, &legacy))
+		delete(legacy, "run_settings")
+		legacySnapshot, err := json.Marshal(legacy)
+		require.NoError(t, err)
+
+		mockStore.EXPECT().Get("playbook1").Return(draft, nil)
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(2)).
+			Return(app.PlaybookRevision{PlaybookID: "playbook1", Revision: 2, Snapshot: legacySnapshot}, nil)
+
+		playbook, err := service.GetPublished("playbook1")
+		require.NoError(t, err)
+		assert.Equal(t, "Incident", playbook.Title)
+		assert.Equal(t, []string{"https://example.com/draft"}, playbook.WebhookOnCreationURLs)
+	})
+
+	t.Run("published playbook without draft changes", func(t *testing.T) {
+		current := draft
+		current.PublishedRevision = 3
+		mockStore.EXPECT().Get("playbook1").Return(current, nil)
+
+		playbook, err := service.GetPublished("playbook1")
+		require.NoError(t, err)
+		assert.Equal(t, "Major incident", playbook.Title)
+	})
+
+	t.Run("never published playbook", func(t *testing.T) {
+		current := draft
+		current.PublishedRevision = 0
+		mockStore.EXPECT().Get("playbook1").Return(current, nil)
+
+		playbook, err := service.GetPublished("playbook1")
+		require.NoError(t, err)
+		assert.Equal(t, "Major incident", playbook.Title)
+	})
+
+	t.Run("playbook at an earlier revision", func(t *testing.T) {
+		current := draft
+		current.PublishedRevision = 3
+		mockStore.EXPECT().Get("playbook1").Return(current, nil)
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(2)).
+			Return(app.PlaybookRevision{PlaybookID: "playbook1", Revision: 2, Snapshot: snapshot}, nil)
+
+		playbook, err := service.GetAtRevision("playbook1", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Incident", playbook.Title)
+		assert.Equal(t, int64(2), playbook.Revision)
+		require.Len(t, playbook.Checklists[0].Items, 1)
+	})
+
+	t.Run("playbook at its latest revision", func(t *testing.T) {
+		mockStore.EXPECT().Get("playbook1").Return(draft, nil)
+
+		playbook, err := service.GetAtRevision("playbook1", 3)
+		require.NoError(t, err)
+		assert.Equal(t, "Major incident", playbook.Title)
+	})
+}
+
+func TestPlaybookService_GetPropertiesAndConditions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_app.NewMockPlaybookStore(ctrl)
+	mockPropertyService := mock_app.NewMockPropertyService(ctrl)
+	mockConditionService := mock_app.NewMockConditionService(ctrl)
+	service := app.NewPlaybookService(mockStore, mock_bot.NewMockPoster(ctrl), nil, nil, &metrics.Metrics{}, mockPropertyService, mockConditionService)
+
+	publishedFields := []app.PropertyField{
+		{PropertyField: model.PropertyField{ID: "field1", Name: "Priority", Type: model.PropertyFieldTypeText}},
+	}
+	publishedConditions := []app.Condition{{
+		ID:      "condition1",
+		Version: 1,
+		ConditionExpr: &app.ConditionExprV1{
+			Is: &app.ComparisonCondition{FieldID: "field1", Value: json.RawMessage(`"high"`)},
+		},
+	}}
+	snapshot, err := app.NewPlaybookRevisionSnapshot(app.Playbook{ID: "playbook1"}, publishedFields, publishedConditions)
+	require.NoError(t, err)
+
+	t.Run("draft property changes are left out of the published revision", func(t *testing.T) {
+		// Only the revision is read: the draft, where the field was since renamed, is not.
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(2)).
+			Return(app.PlaybookRevision{PlaybookID: "playbook1", Revision: 2, Snapshot: snapshot}, nil)
+
+		fields, conditions, err := service.GetPropertiesAndConditions(app.Playbook{ID: "playbook1", Revision: 2})
+		require.NoError(t, err)
+
+		require.Len(t, fields, 1)
+		assert.Equal(t, "field1", fields[0].ID)
+		assert.Equal(t, "Priority", fields[0].Name)
+		assert.Equal(t, "playbook1", fields[0].TargetID)
+		require.Len(t, conditions, 1)
+		assert.Equal(t, "condition1", conditions[0].ID)
+		assert.Equal(t, "playbook1", conditions[0].PlaybookID)
+		assert.Equal(t, "field1", conditions[0].ConditionExpr.(*app.ConditionExprV1).Is.FieldID)
+	})
+
+	t.Run("playbook without revisions", func(t *testing.T) {
+		current := []app.PropertyField{
+			{PropertyField: model.PropertyField{ID: "field1", Name: "Urgency", Type: model.PropertyFieldTypeText}},
+		}
+		mockPropertyService.EXPECT().GetPropertyFields("playbook1").Return(current, nil)
+		mockConditionService.EXPECT().
+			GetPlaybookConditions("", "playbook1", 0, app.MaxConditionsPerPlaybook).
+			Return(&app.GetConditionsResults{}, nil)
+
+		fields, conditions, err := service.GetPropertiesAndConditions(app.Playbook{ID: "playbook1"})
+		require.NoError(t, err)
+		assert.Equal(t, current, fields)
+		assert.Empty(t, conditions)
+	})
+
+	t.Run("missing revision", func(t *testing.T) {
+		mockStore.EXPECT().
+			GetPlaybookRevision("playbook1", int64(5)).
+			Return(app.PlaybookRevision{}, app.ErrNotFound)
+
+		_, _, err := service.GetPropertiesAndConditions(app.Playbook{ID: "playbook1", Revision: 5})
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
+
+func TestPlaybookRevisionService_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_app.NewMockPlaybookStore(ctrl)
+	mockPropertyService := mock_app.NewMockPropertyService(ctrl)
+	mockConditionService := mock_app.NewMockConditionService(ctrl)
+	mockAuditor := mock_app.NewMockAuditor(ctrl)
+	mockAuditor.EXPECT().MakeAuditRecord(gomock.Any(), gomock.Any()).Return(&model.AuditRecord{}).AnyTimes()
+	mockAuditor.EXPECT().LogAuditRec(gomock.Any()).AnyTimes()
+
+	playbookService := app.NewPlaybookService(
+		mockStore,
+		mock_bot.NewMockPoster(ctrl),
+		nil,
+		mockAuditor,
+		&metrics.Metrics{},
+		mockPropertyService,
+		mockConditionService,
+	)
+	service := app.NewPlaybookRevisionService(mockStore, playbookService, mockPropertyService, mockConditionService, mockAuditor)
+
+	t.Run("publishes the latest revision", func(t *testing.T) {
+		mockStore.EXPECT().Get("playbook1").Return(app.Playbook{ID: "playbook1", Revision: 4, PublishedRevision: 2}, nil)
+		mockStore.EXPECT().SetPublishedRevision("playbook1", int64(4)).Return(nil)
+
+		revision, err := service.Publish("playbook1", "user1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), revision)
+	})
+
+	t.Run("records a revision for playbooks that have none", func(t *testing.T) {
+		gomock.InOrder(
+			mockStore.EXPECT().Get("playbook1").Return(app.Playbook{ID: "playbook1"}, nil),
+			mockStore.EXPECT().Get("playbook1").Return(app.Playbook{ID: "playbook1"}, nil),
+			mockStore.EXPECT().Get("playbook1").Return(app.Playbook{ID: "playbook1", Revision: 1}, nil),
+		)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, "playbook1")
+		mockStore.EXPECT().CreatePlaybookRevision(gomock.Any()).Return(app.PlaybookRevision{}, nil)
+		mockStore.EXPECT().SetPublishedRevision("playbook1", int64(1)).Return(nil)
+
+		revision, err := service.Publish("playbook1", "user1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), revision)
+	})
+}
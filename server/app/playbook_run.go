@@ -42,6 +42,9 @@ const (
 	RunSourcePost    = "post"
 	RunSourceDialog  = "dialog"
 	RunSourceCommand = "command"
+
+	// RunSourceChannelAction is used for runs started automatically by a channel action.
+	RunSourceChannelAction = "channel_action"
 )
 
 const (
@@ -244,6 +247,13 @@ type PlaybookRun struct {
 	// SequentialID is the human-readable sequential identifier (e.g., "INC-00042").
 	SequentialID string `json:"sequential_id"`
 
+	// PlaybookRevision is the revision of the playbook the run was created from.
+	// 0 means the run predates playbook revisions or was not created from a playbook.
+	PlaybookRevision int64 `json:"playbook_revision"`
+
+	// RunRoles are the custom roles copied from the playbook, with the users holding them.
+	RunRoles []RunRole `json:"run_roles"`
+
 	ChannelCreatedByRun bool `json:"-"`
 
 	// AutoArchivedChannel tracks whether this run auto-archived its channel; checked independently
@@ -396,6 +406,7 @@ func DetectChangedFields(previous, current *PlaybookRun) map[string]interface{}
 	detectStatusPostChanges(previous, current, changes)
 	detectTimelineEventChanges(previous, current, changes)
 	detectMetricsDataChanges(previous, current, changes)
+	detectRunRoleChanges(previous, current, changes)
 	detectChecklistChanges(previous, current, changes)
 	detectPropertyChanges(previous, current, changes)
 
@@ -629,6 +640,13 @@ func detectMetricsDataChanges(previous, current *PlaybookRun, changes map[string
 	}
 }
 
+// detectRunRoleChanges compares run roles and their holders between two PlaybookRun objects
+func detectRunRoleChanges(previous, current *PlaybookRun, changes map[string]interface{}) {
+	if !reflect.DeepEqual(previous.RunRoles, current.RunRoles) {
+		changes["run_roles"] = current.RunRoles
+	}
+}
+
 // detectChecklistChanges compares checklists and handles both updates and deletions
 func detectChecklistChanges(previous, current *PlaybookRun, changes map[string]interface{}) {
 	checklistUpdates, checklistDeletes := GetChecklistUpdates(previous.Checklists, current.Checklists)
@@ -780,6 +798,9 @@ func GetChecklistItemUpdates(previous, current []ChecklistItem) ItemChanges {
 			if prev.AssigneePropertyFieldID != item.AssigneePropertyFieldID {
 				fields["assignee_property_field_id"] = item.AssigneePropertyFieldID
 			}
+			if prev.AssigneeRoleID != item.AssigneeRoleID {
+				fields["assignee_role_id"] = item.AssigneeRoleID
+			}
 			if prev.Command != item.Command {
 				fields["command"] = item.Command
 			}
@@ -873,6 +894,7 @@ func (r *PlaybookRun) Clone() *PlaybookRun {
 	newPlaybookRun.WebhookOnStatusUpdateURLs = append([]string(nil), r.WebhookOnStatusUpdateURLs...)
 	newPlaybookRun.MetricsData = append([]RunMetricData(nil), r.MetricsData...)
 	newPlaybookRun.BroadcastChannelIDs = append([]string(nil), r.BroadcastChannelIDs...)
+	newPlaybookRun.RunRoles = cloneRunRoles(r.RunRoles)
 
 	// Clear ItemsOrder to prevent data inconsistency, same as Checklist.Clone()
 	newPlaybookRun.ItemsOrder = nil
@@ -925,6 +947,9 @@ func (r PlaybookRun) MarshalJSON() ([]byte, error) {
 	if old.MetricsData == nil {
 		old.MetricsData = []RunMetricData{}
 	}
+	if old.RunRoles == nil {
+		old.RunRoles = []RunRole{}
+	}
 	// Always compute ItemsOrder fresh to prevent data inconsistency
 	old.ItemsOrder = r.GetItemsOrder()
 
@@ -958,9 +983,9 @@ func (r *PlaybookRun) SetChecklistFromPlaybook(playbook Playbook) {
 // SetConfigurationFromPlaybook overwrites this run's configuration with the data from the provided playbook,
 // effectively snapshoting the playbook's configuration in this moment of time.
 func (r *PlaybookRun) SetConfigurationFromPlaybook(playbook Playbook, source string) {
-	// Runs created through managed dialog or slash command lack summary, and we should use the template (if enabled)
+	// Runs created through managed dialog, slash command or channel action lack summary, and we should use the template (if enabled)
 	// Runs created through new modal would have filled the summary in the webapp
-	if playbook.RunSummaryTemplateEnabled && (source == RunSourceDialog || source == RunSourceCommand) {
+	if playbook.RunSummaryTemplateEnabled && (source == RunSourceDialog || source == RunSourceCommand || source == RunSourceChannelAction) {
 		r.Summary = playbook.RunSummaryTemplate
 	}
 	r.ReminderMessageTemplate = playbook.ReminderMessageTemplate
@@ -998,6 +1023,8 @@ func (r *PlaybookRun) SetConfigurationFromPlaybook(playbook Playbook, source str
 		r.Retrospective = playbook.RetrospectiveTemplate
 	}
 
+	r.RunRoles = cloneRunRoles(playbook.RunRoles)
+
 	r.CreateChannelMemberOnNewParticipant = playbook.CreateChannelMemberOnNewParticipant
 	r.RemoveChannelMemberOnRemovedParticipant = playbook.RemoveChannelMemberOnRemovedParticipant
 
@@ -1098,6 +1125,7 @@ const (
 	RetrospectiveEnabled   timelineEventType = "retrospective_enabled"
 	RetrospectiveDisabled  timelineEventType = "retrospective_disabled"
 	PropertyChanged        timelineEventType = "property_changed"
+	RunRoleChanged         timelineEventType = "run_role_changed"
 )
 
 type TimelineEvent struct {
@@ -1120,7 +1148,8 @@ type TimelineEvent struct {
 
 	// EventType is the type of this event. It can be "incident_created", "task_state_modified",
 	// "status_updated", "owner_changed", "assignee_changed", "ran_slash_command",
-	// "event_from_post", "user_joined_left", "published_retrospective", "canceled_retrospective" or "status_update_snoozed".
+	// "event_from_post", "user_joined_left", "published_retrospective", "canceled_retrospective",
+	// "status_update_snoozed" or "run_role_changed".
 	EventType timelineEventType `json:"event_type"`
 
 	// Summary is a short description of the event.
@@ -1214,23 +1243,23 @@ type DialogStateAddToTimeline struct {
 
 // RunLink represents the info needed to display and link to a run
 type RunLink struct {
-	PlaybookRunID string
-	Name          string
+	PlaybookRunID string `json:"playbook_run_id"`
+	Name          string `json:"name"`
 }
 
 // AssignedRun represents all the info needed to display a Run & ChecklistItem to a user
 type AssignedRun struct {
 	RunLink
-	Tasks []AssignedTask
+	Tasks []AssignedTask `json:"tasks"`
 }
 
 // AssignedTask represents a ChecklistItem + extra info needed to display to a user
 type AssignedTask struct {
 	// ID is the identifier of the containing checklist.
-	ChecklistID string
+	ChecklistID string `json:"checklist_id"`
 
 	// Title is the name of the containing checklist.
-	ChecklistTitle string
+	ChecklistTitle string `json:"checklist_title"`
 
 	ChecklistItem
 }
@@ -1264,10 +1293,19 @@ type PlaybookRunService interface {
 	// CreatePlaybookRun always allocates the sequential identifier server-side.
 	CreatePlaybookRun(playbookRun *PlaybookRun, playbook *Playbook, userID string, public bool, source string, initialPropertyValues map[string]json.RawMessage) (*PlaybookRun, error)
 
-	// ResolveRunCreationParams validates template placeholders and resolves the run owner.
-	// Call before CreatePlaybookRun to surface template errors before a run number is consumed.
+	// ResolveRunCreationParams validates template placeholders and required property values and
+	// resolves the run owner. Call before CreatePlaybookRun to surface template errors and missing
+	// values before a run number is consumed.
 	ResolveRunCreationParams(playbookRun *PlaybookRun, pb *Playbook, initialValues map[string]json.RawMessage, source string) error
 
+	// StartRunFromChannelAction creates a run of playbookID reported by userID when a channel action
+	// fires in channelID, linked to post if not nil. propertyValues are the text values of playbook
+	// property fields, keyed by field ID; the ones that can't be converted are skipped.
+	StartRunFromChannelAction(playbookID, channelID, userID string, post *model.Post, propertyValues map[string]string) (*PlaybookRun, error)
+
+	// GetInProgressRunsForChannel returns the in-progress runs linked to channelID.
+	GetInProgressRunsForChannel(channelID string) ([]PlaybookRun, error)
+
 	// OpenCreatePlaybookRunDialog opens an interactive dialog to start a new playbook run.
 	OpenCreatePlaybookRunDialog(teamID, ownerID, triggerID, postID, clientID string, playbooks []Playbook) error
 
@@ -1293,6 +1331,7 @@ type PlaybookRunService interface {
 	OpenFinishPlaybookRunDialog(playbookRunID, userID, triggerID string) error
 
 	// FinishPlaybookRun changes a run's state to Finished. If run is already in Finished state, the call is a noop.
+	// Fields required on finish must have values first; see MissingRequiredPropertiesError.
 	FinishPlaybookRun(playbookRunID, userID string) error
 
 	// ToggleStatusUpdates  enables or disables status update for the run
@@ -1301,9 +1340,27 @@ type PlaybookRunService interface {
 	// GetPlaybookRun gets a playbook run by ID with property fields and values. Returns error if it could not be found.
 	GetPlaybookRun(playbookRunID string) (*PlaybookRun, error)
 
+	// PropagatePlaybookChecklists brings the checklists of the playbook's active runs up to date
+	// with its published version, without undoing progress or edits made in the runs. With
+	// dryRun, it only reports what would change.
+	PropagatePlaybookChecklists(playbookID, userID string, dryRun bool) (*ChecklistPropagationResult, error)
+
 	// SetRunPropertyValue sets a property value for a playbook run and sends websocket updates
 	SetRunPropertyValue(userID, playbookRunID, propertyFieldID string, value json.RawMessage) (*PropertyValue, error)
 
+	// GetPropertyValueHistory returns the history of the run's property values, oldest first,
+	// restricted to fieldID when it is not empty.
+	GetPropertyValueHistory(playbookRunID, fieldID string) ([]PropertyValueChange, error)
+
+	// GetPropertyValuesAsOf returns the values the run's properties had at asOf, in milliseconds.
+	GetPropertyValuesAsOf(playbookRunID string, asOf int64) ([]PropertyValue, error)
+
+	// GetRunChanges returns the changes of the run change feed matching options, oldest first.
+	GetRunChanges(options RunChangeOptions) ([]RunChange, error)
+
+	// GetLatestRunChangeCursor returns the cursor of the most recent change of the run change feed.
+	GetLatestRunChangeCursor() (int64, error)
+
 	// GetPlaybookRunMetadata gets ancillary metadata about a playbook run.
 	GetPlaybookRunMetadata(playbookRunID string, hasChannelAccess bool) (*Metadata, error)
 
@@ -1313,6 +1370,10 @@ type PlaybookRunService interface {
 	// GetOwners returns all the owners of playbook runs selected
 	GetOwners(requesterInfo RequesterInfo, options PlaybookRunFilterOptions) ([]OwnerInfo, error)
 
+	// GetSharedPropertyFieldStats counts the values of the team property field
+	// options.SharedFieldID across the playbook runs selected
+	GetSharedPropertyFieldStats(requesterInfo RequesterInfo, options PlaybookRunFilterOptions) (*SharedPropertyFieldStats, error)
+
 	// IsOwner returns true if the userID is the owner for playbookRunID.
 	IsOwner(playbookRunID string, userID string) bool
 
@@ -1334,6 +1395,12 @@ type PlaybookRunService interface {
 	// SetRoleAssignee sets a role-based assignee type ("owner" or "creator") for the specified checklist item.
 	SetRoleAssignee(playbookRunID, userID, assigneeType string, checklistNumber, itemNumber int) error
 
+	// SetRunRoleAssignee assigns a checklist item to whoever holds the given run role.
+	SetRunRoleAssignee(playbookRunID, userID, roleID string, checklistNumber, itemNumber int) error
+
+	// AssignRunRole gives a run role to assigneeID, or unassigns it when assigneeID is empty.
+	AssignRunRole(playbookRunID, userID, roleID, assigneeID string) error
+
 	// SetPropertyUserAssignee sets a checklist item's assignee to whoever the given User-type
 	// property field resolves to on this run.
 	SetPropertyUserAssignee(playbookRunID, userID string, checklistNumber, itemNumber int, propertyFieldID string) error
@@ -1383,6 +1450,36 @@ type PlaybookRunService interface {
 	// MoveChecklistItem moves a checklist item from one position to another.
 	MoveChecklistItem(playbookRunID, userID string, sourceChecklistIdx, sourceItemIdx, destChecklistIdx, destItemIdx int) error
 
+	// WithChecklistItem calls apply with the checklist and item numbers of the checklist item ref
+	// addresses, and a service through which the index based methods change it only if it is
+	// unchanged since: a *ChecklistItemConflictError is returned when the item was modified since
+	// ref.UpdateAt, or when the run is modified before the change is written.
+	WithChecklistItem(playbookRunID string, ref ChecklistItemRef, apply func(service PlaybookRunService, checklistNumber, itemNumber int) error) error
+
+	// ModifyCheckedStateByID modifies the state of the checklist item ref addresses.
+	ModifyCheckedStateByID(playbookRunID, userID, newState string, ref ChecklistItemRef) error
+
+	// ToggleCheckedStateByID checks or unchecks the checklist item ref addresses.
+	ToggleCheckedStateByID(playbookRunID, userID string, ref ChecklistItemRef) error
+
+	// SetAssigneeByID sets the assignee of the checklist item ref addresses.
+	SetAssigneeByID(playbookRunID, userID, assigneeID string, ref ChecklistItemRef) error
+
+	// SetDueDateByID sets the due date of the checklist item ref addresses.
+	SetDueDateByID(playbookRunID, userID string, duedate int64, ref ChecklistItemRef) error
+
+	// RemoveChecklistItemByID removes the checklist item ref addresses.
+	RemoveChecklistItemByID(playbookRunID, userID string, ref ChecklistItemRef) error
+
+	// MoveChecklistItemByID moves the checklist item ref addresses to position in the checklist
+	// with ID destChecklistID, or in its own checklist when destChecklistID is empty. A nil
+	// position moves it to the end.
+	MoveChecklistItemByID(playbookRunID, userID string, ref ChecklistItemRef, destChecklistID string, position *int) error
+
+	// ApplyChecklistItemOperations applies operations addressed by item ID to the checklist items
+	// of a run. With atomic, they are applied together in a single update of the run.
+	ApplyChecklistItemOperations(playbookRunID, userID string, ops []ChecklistItemOperation, atomic bool) ([]BulkOperationResult, error)
+
 	// GetChecklistItemAutocomplete returns the list of checklist items for playbookRuns to be used in autocomplete
 	GetChecklistItemAutocomplete(playbookRuns []PlaybookRun) ([]model.AutocompleteListItem, error)
 
@@ -1443,6 +1540,10 @@ type PlaybookRunService interface {
 	// and DMs the message to userID. Use force = true to DM even if there are no items.
 	DMTodoDigestToUser(userID string, force bool, includeRunsInProgress bool) error
 
+	// GetTodoDigestMessageItems returns the overdue runs, assigned tasks and runs in progress
+	// making up the todo digest of userID.
+	GetTodoDigestMessageItems(userID string) (*TodoDigestMessageItems, error)
+
 	// GetRunsWithAssignedTasks returns the list of runs that have tasks assigned to userID
 	GetRunsWithAssignedTasks(userID string) ([]AssignedRun, error)
 
@@ -1482,6 +1583,10 @@ type PlaybookRunService interface {
 	// GraphqlUpdate taking a setmap for graphql
 	GraphqlUpdate(id string, setmap map[string]interface{}) error
 
+	// GraphqlUpdateIfUnmodified is GraphqlUpdate, failing with ErrStaleVersion unless the run was
+	// last updated at updateAt. An updateAt of 0 updates the run unconditionally.
+	GraphqlUpdateIfUnmodified(id string, setmap map[string]interface{}, updateAt int64) error
+
 	// ToggleRetrospectiveEnabled enables or disables the retrospective for the run.
 	ToggleRetrospectiveEnabled(playbookRunID, userID string, enabled bool) error
 
@@ -1500,9 +1605,17 @@ type PlaybookRunStore interface {
 	// UpdatePlaybookRun updates a playbook run.
 	UpdatePlaybookRun(playbookRun *PlaybookRun) (*PlaybookRun, error)
 
+	// UpdatePlaybookRunIfUnmodified is UpdatePlaybookRun, failing with ErrStaleVersion unless the
+	// run's UpdateAt is updateAt. An updateAt of 0 updates the run unconditionally.
+	UpdatePlaybookRunIfUnmodified(playbookRun *PlaybookRun, updateAt int64) (*PlaybookRun, error)
+
 	// GraphqlUpdate taking a setmap for graphql
 	GraphqlUpdate(id string, setmap map[string]interface{}) error
 
+	// GraphqlUpdateIfUnmodified is GraphqlUpdate, failing with ErrStaleVersion unless the run's
+	// UpdateAt is updateAt. An updateAt of 0 updates the run unconditionally.
+	GraphqlUpdateIfUnmodified(id string, setmap map[string]interface{}, updateAt int64) error
+
 	// UpdateStatus updates the status of a playbook run.
 	UpdateStatus(statusPost *SQLStatusPost) error
 
@@ -1521,6 +1634,28 @@ type PlaybookRunStore interface {
 	// UpdateTimelineEvent updates an existing timeline event
 	UpdateTimelineEvent(event *TimelineEvent) error
 
+	// CreatePropertyValueChange appends a change to the history of a run's property values.
+	CreatePropertyValueChange(change PropertyValueChange) (PropertyValueChange, error)
+
+	// GetPropertyValueChanges returns the history of a run's property values, oldest first.
+	GetPropertyValueChanges(playbookRunID string, options PropertyValueChangeOptions) ([]PropertyValueChange, error)
+
+	// CreateRunChanges appends changes to the run change feed, assigning their cursors.
+	CreateRunChanges(changes []RunChange) error
+
+	// GetRunChanges returns the changes of the run change feed matching options, oldest first.
+	GetRunChanges(options RunChangeOptions) ([]RunChange, error)
+
+	// GetLatestRunChangeCursor returns the cursor of the most recent change of the feed, 0 when empty.
+	GetLatestRunChangeCursor() (int64, error)
+
+	// DeleteRunChangesBefore deletes the changes of the run change feed created before createAt.
+	DeleteRunChangesBefore(createAt int64) error
+
+	// GetSharedPropertyFieldStats counts the values of the team property field options.SharedFieldID
+	// across the runs matching options
+	GetSharedPropertyFieldStats(requesterInfo RequesterInfo, options PlaybookRunFilterOptions) (*SharedPropertyFieldStats, error)
+
 	// GetPlaybookRun gets a playbook run by ID.
 	GetPlaybookRun(playbookRunID string) (*PlaybookRun, error)
 
@@ -1702,6 +1837,27 @@ type PlaybookRunFilterOptions struct {
 	// OmitEnded determines whether to omit runs that have ended (EndAt > 0).
 	// If true, only active runs (EndAt = 0) are returned.
 	OmitEnded bool `url:"omit_ended,omitempty"`
+
+	// SharedFieldID filters to runs having the team property field with this ID, whichever
+	// playbook they were started from. Defaults to blank (no filter).
+	SharedFieldID string `url:"shared_field_id,omitempty"`
+
+	// SharedFieldValue further filters to runs whose value of SharedFieldID is this one, matching
+	// option names for select fields. Ignored if SharedFieldID is blank.
+	SharedFieldValue string `url:"shared_field_value,omitempty"`
+
+	// PropertyFilter filters playbook runs by their property values. Its comparisons reference
+	// playbook or team property fields and apply to the fields runs copied from them.
+	PropertyFilter *ConditionExprV1
+
+	// SortPropertyFieldID is the playbook or team property field to sort by when Sort is
+	// SortByProperty.
+	SortPropertyFieldID string `url:"sort_property_field_id,omitempty"`
+
+	// ResolvedPropertyFilter and ResolvedSortPropertyField are resolved by the service from
+	// PropertyFilter and SortPropertyFieldID for the store.
+	ResolvedPropertyFilter    *PropertyFilter
+	ResolvedSortPropertyField *PropertyField
 }
 
 // Clone duplicates the given options.
@@ -1736,6 +1892,10 @@ func (o PlaybookRunFilterOptions) Validate() (PlaybookRunFilterOptions, error) {
 	case SortByStatus:
 	case SortByLastStatusUpdateAt:
 	case SortByMetric0, SortByMetric1, SortByMetric2, SortByMetric3:
+	case SortByProperty:
+		if options.SortPropertyFieldID == "" {
+			return PlaybookRunFilterOptions{}, errors.New("bad parameter 'sort_property_field_id': required to sort by property")
+		}
 	case "": // default
 		options.Sort = SortByCreateAt
 	default:
@@ -1793,6 +1953,14 @@ func (o PlaybookRunFilterOptions) Validate() (PlaybookRunFilterOptions, error) {
 		return PlaybookRunFilterOptions{}, errors.New("bad parameter 'channel_id': must be 26 characters or blank")
 	}
 
+	if options.SharedFieldID != "" && !model.IsValidId(options.SharedFieldID) {
+		return PlaybookRunFilterOptions{}, errors.New("bad parameter 'shared_field_id': must be 26 characters or blank")
+	}
+
+	if options.SortPropertyFieldID != "" && !model.IsValidId(options.SortPropertyFieldID) {
+		return PlaybookRunFilterOptions{}, errors.New("bad parameter 'sort_property_field_id': must be 26 characters or blank")
+	}
+
 	for _, s := range options.Statuses {
 		if !validStatus(s) {
 			return PlaybookRunFilterOptions{}, errors.New("bad parameter in 'statuses': must be InProgress or Finished")
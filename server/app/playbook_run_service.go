@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -63,7 +64,8 @@ func (s *PlaybookRunServiceImpl) sendPlaybookRunObjectUpdatedWS(playbookRunID st
 	logger := logrus.WithField("playbook_run_id", playbookRunID)
 
 	// Determine if incremental updates are enabled
-	if !s.configService.IsIncrementalUpdatesEnabled() {
+	incrementalUpdates := s.configService.IsIncrementalUpdatesEnabled()
+	if !incrementalUpdates {
 		if currentRun != nil {
 			currentRun.ComputeTaskProgress()
 		}
@@ -73,7 +75,11 @@ func (s *PlaybookRunServiceImpl) sendPlaybookRunObjectUpdatedWS(playbookRunID st
 			PlaybookRun:       currentRun,
 		}
 		s.sendPlaybookRunUpdatedWS(playbookRunID, withRunWSOptions(&sendWSOptions))
-		return
+
+		// The change feed still needs the incremental update below
+		if !s.configService.IsRunChangeFeedEnabled() || previousRun == nil {
+			return
+		}
 	}
 
 	// Get the current state only if we don't already have it
@@ -92,11 +98,39 @@ func (s *PlaybookRunServiceImpl) sendPlaybookRunObjectUpdatedWS(playbookRunID st
 		previousRun.ComputeTaskProgress()
 	}
 
+	update, ok := newPlaybookRunUpdate(previousRun, currentRun)
+	if !ok {
+		// No changes detected, nothing to send
+		return
+	}
+
+	s.recordRunUpdate(currentRun, update)
+	if !incrementalUpdates {
+		return
+	}
+
+	var nonMembers []string
+	if len(additionalUserIDs) > 0 {
+		nonMembers = s.getNonMembersIDs(currentRun.ChannelID, additionalUserIDs)
+	}
+
+	// Send the incremental update
+	s.poster.PublishWebsocketEventToChannel(playbookRunUpdatedIncrementalWSEvent, update, currentRun.ChannelID)
+	if len(nonMembers) > 0 {
+		for _, nonMember := range nonMembers {
+			s.poster.PublishWebsocketEventToUser(playbookRunUpdatedIncrementalWSEvent, update, nonMember)
+		}
+	}
+
+}
+
+// newPlaybookRunUpdate computes the incremental update from previousRun to currentRun. It returns
+// false when nothing changed.
+func newPlaybookRunUpdate(previousRun, currentRun *PlaybookRun) (PlaybookRunUpdate, bool) {
 	// Pre-calculate changed fields for incremental updates
 	changedFields := DetectChangedFields(previousRun, currentRun)
 	if len(changedFields) == 0 {
-		// No changes detected, nothing to send
-		return
+		return PlaybookRunUpdate{}, false
 	}
 
 	// Extract checklist deletes from changed fields
@@ -124,28 +158,14 @@ func (s *PlaybookRunServiceImpl) sendPlaybookRunObjectUpdatedWS(playbookRunID st
 	}
 
 	// Prepare the update data
-	update := PlaybookRunUpdate{
+	return PlaybookRunUpdate{
 		ID:                   currentRun.ID,
 		PlaybookRunUpdatedAt: currentRun.UpdateAt,
 		ChangedFields:        changedFields,
 		ChecklistDeletes:     checklistDeletes,
 		TimelineEventDeletes: timelineEventDeletes,
 		StatusPostDeletes:    statusPostDeletes,
-	}
-
-	var nonMembers []string
-	if len(additionalUserIDs) > 0 {
-		nonMembers = s.getNonMembersIDs(currentRun.ChannelID, additionalUserIDs)
-	}
-
-	// Send the incremental update
-	s.poster.PublishWebsocketEventToChannel(playbookRunUpdatedIncrementalWSEvent, update, currentRun.ChannelID)
-	if len(nonMembers) > 0 {
-		for _, nonMember := range nonMembers {
-			s.poster.PublishWebsocketEventToUser(playbookRunUpdatedIncrementalWSEvent, update, nonMember)
-		}
-	}
-
+	}, true
 }
 
 // PlaybookRunServiceImpl holds the information needed by the PlaybookRunService's methods to complete their functions.
@@ -164,6 +184,10 @@ type PlaybookRunServiceImpl struct {
 	metricsService   *metrics.Metrics
 	propertyService  PropertyService
 	conditionService ConditionService
+
+	// lastRunChangePrune is when this server last pruned the run change feed, in milliseconds. It
+	// is shared with the copies of the service made by withChecklistItem.
+	lastRunChangePrune *atomic.Int64
 }
 
 var allNonSpaceNonWordRegex = regexp.MustCompile(`[^\w\s]`)
@@ -230,6 +254,8 @@ func NewPlaybookRunService(
 		metricsService:   metricsService,
 		propertyService:  propertyService,
 		conditionService: conditionService,
+
+		lastRunChangePrune: &atomic.Int64{},
 	}
 
 	service.permissions = NewPermissionsService(service.playbookService, service, service.pluginAPI, service.configService, service.licenseChecker)
@@ -239,6 +265,11 @@ func NewPlaybookRunService(
 
 // GetPlaybookRuns returns filtered playbook runs and the total count before paging.
 func (s *PlaybookRunServiceImpl) GetPlaybookRuns(requesterInfo RequesterInfo, options PlaybookRunFilterOptions) (*GetPlaybookRunsResults, error) {
+	options, err := s.resolvePropertyFilterOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
 	results, err := s.store.GetPlaybookRuns(requesterInfo, options)
 	if err != nil {
 		return nil, err
@@ -280,6 +311,62 @@ func (s *PlaybookRunServiceImpl) GetPlaybookRuns(requesterInfo RequesterInfo, op
 	return results, nil
 }
 
+// resolvePropertyFilterOptions resolves the property filter and the property to sort by against
+// the fields they reference, for the store to match the fields runs copied from them.
+func (s *PlaybookRunServiceImpl) resolvePropertyFilterOptions(options PlaybookRunFilterOptions) (PlaybookRunFilterOptions, error) {
+	if options.PropertyFilter == nil && options.Sort != SortByProperty {
+		return options, nil
+	}
+
+	if !s.licenseChecker.PlaybookAttributesAllowed() {
+		return options, errors.Wrap(ErrLicensedFeature, "filtering and sorting runs by property is not covered by current server license")
+	}
+
+	if options.PropertyFilter != nil {
+		fieldIDs, _ := options.PropertyFilter.ExtractPropertyIDs()
+		fields := make([]PropertyField, 0, len(fieldIDs))
+		for _, fieldID := range fieldIDs {
+			field, err := s.getPropertyFilterField(fieldID)
+			if err != nil {
+				return options, err
+			}
+			fields = append(fields, *field)
+		}
+
+		filter, err := ResolvePropertyFilter(*options.PropertyFilter, fields)
+		if err != nil {
+			return options, err
+		}
+		options.ResolvedPropertyFilter = filter
+	}
+
+	if options.Sort == SortByProperty {
+		field, err := s.getPropertyFilterField(options.SortPropertyFieldID)
+		if err != nil {
+			return options, err
+		}
+		if err := ValidatePropertySortField(*field); err != nil {
+			return options, err
+		}
+		options.ResolvedSortPropertyField = field
+	}
+
+	return options, nil
+}
+
+func (s *PlaybookRunServiceImpl) getPropertyFilterField(fieldID string) (*PropertyField, error) {
+	if !model.IsValidId(fieldID) {
+		return nil, errors.Wrapf(ErrMalformedCondition, "invalid property field ID '%s'", fieldID)
+	}
+
+	field, err := s.propertyService.GetPropertyField(fieldID)
+	if err != nil {
+		return nil, errors.Wrapf(ErrMalformedCondition, "unknown property field %s: %s", fieldID, err.Error())
+	}
+
+	return field, nil
+}
+
 func (s *PlaybookRunServiceImpl) buildPlaybookRunCreationMessage(playbookTitle, playbookID string, playbookRun *PlaybookRun, reporter *model.User) (string, error) {
 	return fmt.Sprintf(
 		"##### [%s](%s)\n@%s ran the [%s](%s) playbook.",
@@ -386,6 +473,9 @@ func normalizeAndValidateRunCreationParams(playbookRun *PlaybookRun, pb *Playboo
 	if pb.NewChannelOnly && playbookRun.ChannelID != "" {
 		return errors.Wrap(ErrMalformedPlaybookRun, "this playbook requires runs to create a new channel, but a channel_id was provided")
 	}
+	if pb.PublishedRevision != 0 && pb.Revision != pb.PublishedRevision {
+		return errors.Wrap(ErrMalformedPlaybookRun, "runs must be created from the published version of the playbook")
+	}
 	return nil
 }
 
@@ -450,6 +540,7 @@ func (s *PlaybookRunServiceImpl) CreatePlaybookRun(playbookRun *PlaybookRun, pb
 		if allocErr != nil {
 			return nil, allocErr
 		}
+		playbookRun.PlaybookRevision = pb.Revision
 	}
 
 	var err error
@@ -548,6 +639,7 @@ func (s *PlaybookRunServiceImpl) CreatePlaybookRun(playbookRun *PlaybookRun, pb
 
 	// Resolve role-based task assignments (Owner/Creator) before persisting
 	resolveRoleAssignments(playbookRun.Checklists, playbookRun.OwnerUserID, playbookRun.ReporterUserID)
+	resolveRunRoleAssignments(playbookRun.Checklists, playbookRun.RunRoles)
 
 	playbookRun, err = s.store.CreatePlaybookRun(playbookRun)
 	if err != nil {
@@ -557,7 +649,13 @@ func (s *PlaybookRunServiceImpl) CreatePlaybookRun(playbookRun *PlaybookRun, pb
 	}
 
 	if pb != nil && s.licenseChecker.PlaybookAttributesAllowed() {
-		propertyCopyResult, err := s.propertyService.CopyPlaybookPropertiesToRun(pb.ID, playbookRun.ID)
+		// Properties and conditions are copied from the same revision as the checklists, so that
+		// draft changes made after it don't reach the run.
+		var propertyCopyResult *PropertyCopyResult
+		properties, conditions, err := s.playbookService.GetPropertiesAndConditions(*pb)
+		if err == nil {
+			propertyCopyResult, err = s.propertyService.CopyPlaybookPropertiesToRun(pb.ID, playbookRun.ID, properties)
+		}
 		if err != nil {
 			logger.WithError(err).Warn("failed to copy playbook properties to run")
 			remapAssigneePropertyFieldIDs(playbookRun.Checklists, nil)
@@ -578,7 +676,7 @@ func (s *PlaybookRunServiceImpl) CreatePlaybookRun(playbookRun *PlaybookRun, pb
 			// Copy conditions from playbook to run using the field mappings if license allows
 			conditionsWereCopied := false
 			if s.licenseChecker.ConditionalPlaybooksAllowed() {
-				conditionMapping, err := s.conditionService.CopyPlaybookConditionsToRun(pb.ID, playbookRun.ID, propertyCopyResult)
+				conditionMapping, err := s.conditionService.CopyPlaybookConditionsToRun(pb.ID, playbookRun.ID, conditions, propertyCopyResult)
 				if err != nil {
 					logger.WithError(err).Warn("failed to copy playbook conditions to run")
 				} else {
@@ -596,12 +694,27 @@ func (s *PlaybookRunServiceImpl) CreatePlaybookRun(playbookRun *PlaybookRun, pb
 				}
 			}
 
+			valuesApplied := false
 			if len(initialPropertyValues) > 0 {
 				playbookRun, err = s.applyInitialPropertyValues(playbookRun, propertyCopyResult, initialPropertyValues, logger)
 				if err != nil {
 					return nil, errors.Wrap(err, "failed to apply initial property values")
 				}
+				for _, value := range playbookRun.PropertyValues {
+					s.recordPropertyValueChange(playbookRun.ID, value.FieldID, playbookRun.ReporterUserID, nil, value.Value)
+				}
+				valuesApplied = true
+			}
+
+			computedFieldIDs, err := s.updateComputedPropertyValues(playbookRun)
+			if err != nil {
+				logger.WithError(err).Warn("failed to compute initial property values")
+			}
+			if len(computedFieldIDs) > 0 {
+				valuesApplied = true
+			}
 
+			if valuesApplied {
 				// Re-evaluate conditions with the actual initial property values so the run
 				// starts in the correct visibility state rather than the default-value state.
 				if conditionsWereCopied {
@@ -621,6 +734,7 @@ func (s *PlaybookRunServiceImpl) CreatePlaybookRun(playbookRun *PlaybookRun, pb
 	}
 
 	s.metricsService.IncrementRunsCreatedCount(1)
+	s.recordRunCreated(playbookRun)
 
 	// Add result for audit
 	auditRec.AddEventResultState(*playbookRun)
@@ -986,7 +1100,7 @@ func (s *PlaybookRunServiceImpl) AddPostToTimeline(playbookRun *PlaybookRun, use
 	playbookRun.ComputeTaskProgress()
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRun.Clone()
 	}
 
@@ -1023,7 +1137,7 @@ func (s *PlaybookRunServiceImpl) RemoveTimelineEvent(playbookRunID, userID, even
 	model.AddEventParameterToAuditRec(auditRec, "eventID", eventID)
 	// Get the current playbook run state before changes if incremental updates are enabled
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		var err error
 		originalRun, err = s.GetPlaybookRun(playbookRunID)
 		if err != nil {
@@ -1175,7 +1289,7 @@ func (s *PlaybookRunServiceImpl) UpdateStatus(playbookRunID, userID string, opti
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -1234,6 +1348,7 @@ func (s *PlaybookRunServiceImpl) UpdateStatus(playbookRunID, userID string, opti
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
+	s.refreshStoredRunComputedPropertyValues(playbookRunID)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, nil)
 
 	if playbookRunToModify.StatusUpdateBroadcastWebhooksEnabled {
@@ -1320,6 +1435,7 @@ func (s *PlaybookRunServiceImpl) buildStatusUpdateMessage(playbookRun *PlaybookR
 }
 
 // FinishPlaybookRun changes a run's state to Finished. If run is already in Finished state, the call is a noop.
+// It returns a MissingRequiredPropertiesError while fields required on finish have no value.
 func (s *PlaybookRunServiceImpl) FinishPlaybookRun(playbookRunID, userID string) error {
 	auditRec := plugin.MakeAuditRecord("finishPlaybookRun", model.AuditStatusFail)
 	defer s.api.LogAuditRec(auditRec)
@@ -1346,8 +1462,12 @@ func (s *PlaybookRunServiceImpl) FinishPlaybookRun(playbookRunID, userID string)
 		return nil
 	}
 
+	if err = playbookRunToModify.CheckRequiredOnFinish(); err != nil {
+		return err
+	}
+
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -1387,6 +1507,9 @@ func (s *PlaybookRunServiceImpl) FinishPlaybookRun(playbookRunID, userID string)
 	// Also start the recurring reminder if enabled.
 	if s.licenseChecker.RetrospectiveAllowed() {
 		if playbookRunToModify.RetrospectiveEnabled && playbookRunToModify.RetrospectivePublishedAt == 0 {
+			if err = s.resolveRetrospectiveTemplate(playbookRunToModify); err != nil {
+				logger.WithError(err).Warn("failed to resolve retrospective template")
+			}
 			if err = s.postRetrospectiveReminder(playbookRunToModify, true); err != nil {
 				return errors.Wrap(err, "couldn't post retrospective reminder")
 			}
@@ -1418,6 +1541,7 @@ func (s *PlaybookRunServiceImpl) FinishPlaybookRun(playbookRunID, userID string)
 		playbookRunToModify = s.autoArchiveChannelOnFinish(playbookRunToModify, playbookRunID, userID, endAt, logger)
 	}
 
+	s.refreshStoredRunComputedPropertyValues(playbookRunID)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, nil)
 
 	if playbookRunToModify.StatusUpdateBroadcastWebhooksEnabled {
@@ -1460,7 +1584,7 @@ func (s *PlaybookRunServiceImpl) ToggleStatusUpdates(playbookRunID, userID strin
 	model.AddEventParameterToAuditRec(auditRec, "teamID", playbookRunToModify.TeamID)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -1580,7 +1704,7 @@ func (s *PlaybookRunServiceImpl) RestorePlaybookRun(playbookRunID, userID string
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToRestore.Clone()
 	}
 
@@ -1646,6 +1770,7 @@ func (s *PlaybookRunServiceImpl) RestorePlaybookRun(playbookRunID, userID string
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
+	s.refreshStoredRunComputedPropertyValues(playbookRunID)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, nil)
 
 	if playbookRunToRestore.StatusUpdateBroadcastWebhooksEnabled {
@@ -1781,6 +1906,13 @@ func updateChecklistAndItemTimestamp(checklist *Checklist, item *ChecklistItem,
 	checklist.UpdateAt = timestamp
 }
 
+// setChecklistItemDueDate sets the due date of an item of checklist, 0 clearing it, and updates
+// their timestamps.
+func setChecklistItemDueDate(checklist *Checklist, item *ChecklistItem, dueDate, timestamp int64) {
+	item.DueDate = dueDate
+	updateChecklistAndItemTimestamp(checklist, item, timestamp)
+}
+
 func (s *PlaybookRunServiceImpl) ToggleRetrospectiveEnabled(playbookRunID, userID string, enabled bool) error {
 	auditRec := plugin.MakeAuditRecord("togglePlaybookRunRetrospective", model.AuditStatusFail)
 	defer s.api.LogAuditRec(auditRec)
@@ -1817,7 +1949,7 @@ func (s *PlaybookRunServiceImpl) ToggleRetrospectiveEnabled(playbookRunID, userI
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -1879,6 +2011,12 @@ func (s *PlaybookRunServiceImpl) ToggleRetrospectiveEnabled(playbookRunID, userI
 
 // GraphqlUpdate updates fields based on a setmap.
 func (s *PlaybookRunServiceImpl) GraphqlUpdate(id string, setmap map[string]interface{}) error {
+	return s.GraphqlUpdateIfUnmodified(id, setmap, 0)
+}
+
+// GraphqlUpdateIfUnmodified updates fields based on a setmap, unless the run was modified since
+// updateAt.
+func (s *PlaybookRunServiceImpl) GraphqlUpdateIfUnmodified(id string, setmap map[string]interface{}, updateAt int64) error {
 	if len(setmap) == 0 {
 		return nil
 	}
@@ -1895,7 +2033,7 @@ func (s *PlaybookRunServiceImpl) GraphqlUpdate(id string, setmap map[string]inte
 	model.AddEventParameterToAuditRec(auditRec, "fieldsUpdated", strings.Join(fieldNames, ","))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		var err error
 		originalRun, err = s.GetPlaybookRun(id)
 		if err != nil {
@@ -1914,7 +2052,7 @@ func (s *PlaybookRunServiceImpl) GraphqlUpdate(id string, setmap map[string]inte
 
 	setmap["UpdateAt"] = now
 
-	if err := s.store.GraphqlUpdate(id, setmap); err != nil {
+	if err := s.store.GraphqlUpdateIfUnmodified(id, setmap, updateAt); err != nil {
 		err := errors.Wrapf(err, "failed to execute GraphQL update for playbook run (runID: %s) with fields [%s]", id, strings.Join(fieldNames, ","))
 		auditRec.AddErrorDesc(err.Error())
 		return err
@@ -1999,6 +2137,149 @@ func (s *PlaybookRunServiceImpl) GetPlaybookRun(playbookRunID string) (*Playbook
 	return playbookRun, nil
 }
 
+// PropagatePlaybookChecklists brings the checklists of the playbook's active runs up to date
+// with its published version.
+func (s *PlaybookRunServiceImpl) PropagatePlaybookChecklists(playbookID, userID string, dryRun bool) (*ChecklistPropagationResult, error) {
+	auditRec := plugin.MakeAuditRecord("propagatePlaybookChecklists", model.AuditStatusFail)
+	defer s.api.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", playbookID)
+	model.AddEventParameterToAuditRec(auditRec, "dryRun", dryRun)
+
+	target, err := s.playbookService.GetPublished(playbookID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get published version of playbook %s", playbookID)
+		auditRec.AddErrorDesc(err.Error())
+		return nil, err
+	}
+
+	var runs []PlaybookRun
+	for page := 0; ; page++ {
+		results, err := s.store.GetPlaybookRuns(RequesterInfo{UserID: userID, IsAdmin: true}, PlaybookRunFilterOptions{
+			PlaybookID: playbookID,
+			Statuses:   []string{StatusInProgress},
+			SkipExtras: true,
+			Page:       page,
+			PerPage:    PerPageDefault,
+		})
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get active runs of playbook %s", playbookID)
+			auditRec.AddErrorDesc(err.Error())
+			return nil, err
+		}
+		runs = append(runs, results.Items...)
+		if !results.HasMore {
+			break
+		}
+	}
+
+	result := &ChecklistPropagationResult{
+		PlaybookID: playbookID,
+		Revision:   target.Revision,
+		DryRun:     dryRun,
+		Runs:       []RunChecklistPropagation{},
+	}
+
+	// Runs are usually created from a handful of revisions, so each one is only loaded once.
+	baselines := map[int64][]Checklist{target.Revision: target.Checklists}
+	updatedRuns := 0
+	for _, run := range runs {
+		report := s.propagateChecklistsToRun(run.ID, target, baselines, dryRun)
+		if report.Error == "" && report.HasChanges() && !dryRun {
+			updatedRuns++
+		}
+		result.Runs = append(result.Runs, report)
+	}
+
+	auditRec.Success()
+	model.AddEventParameterToAuditRec(auditRec, "activeRuns", len(runs))
+	model.AddEventParameterToAuditRec(auditRec, "updatedRuns", updatedRuns)
+
+	return result, nil
+}
+
+// maxChecklistPropagationAttempts is how many times the checklists are merged into a run that
+// keeps being modified concurrently before it is skipped.
+const maxChecklistPropagationAttempts = 3
+
+// propagateChecklistsToRun merges the target playbook's checklists into a single run, reporting
+// failures in the returned report rather than aborting the propagation to the other runs. The run
+// is only written if unmodified since it was read; otherwise the merge is retried on its latest
+// version, and the run reported as skipped if it keeps changing.
+func (s *PlaybookRunServiceImpl) propagateChecklistsToRun(playbookRunID string, target Playbook, baselines map[int64][]Checklist, dryRun bool) RunChecklistPropagation {
+	for attempt := 1; ; attempt++ {
+		report, err := s.mergeChecklistsIntoRun(playbookRunID, target, baselines, dryRun)
+		if !errors.Is(err, ErrStaleVersion) {
+			return report
+		}
+		if attempt == maxChecklistPropagationAttempts {
+			logrus.WithError(err).WithFields(logrus.Fields{"playbook_run_id": playbookRunID, "playbook_id": target.ID}).Warn("run kept changing while propagating checklists, skipping it")
+			report.Error = "run was modified concurrently, skipped"
+			return report
+		}
+	}
+}
+
+// mergeChecklistsIntoRun makes a single attempt at propagateChecklistsToRun. Failures are
+// reported, except the run being modified since it was read, which is returned as ErrStaleVersion.
+func (s *PlaybookRunServiceImpl) mergeChecklistsIntoRun(playbookRunID string, target Playbook, baselines map[int64][]Checklist, dryRun bool) (RunChecklistPropagation, error) {
+	logger := logrus.WithFields(logrus.Fields{"playbook_run_id": playbookRunID, "playbook_id": target.ID})
+
+	run, err := s.store.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get run to propagate checklists to")
+		return RunChecklistPropagation{PlaybookRunID: playbookRunID, Error: "failed to get run"}, nil
+	}
+
+	report := RunChecklistPropagation{PlaybookRunID: run.ID, Name: run.Name, FromRevision: run.PlaybookRevision}
+	if run.PlaybookRevision == 0 {
+		report.Error = "run was created before playbook revisions were recorded"
+		return report, nil
+	}
+
+	base, ok := baselines[run.PlaybookRevision]
+	if !ok {
+		playbook, err := s.playbookService.GetAtRevision(target.ID, run.PlaybookRevision)
+		if err != nil {
+			logger.WithError(err).Warn("failed to get playbook revision the run was created from")
+			report.Error = "failed to get the playbook revision the run was created from"
+			return report, nil
+		}
+		base = playbook.Checklists
+		baselines[run.PlaybookRevision] = base
+	}
+
+	merged, merge := MergePlaybookChecklists(base, target.Checklists, run.Checklists, run.CreateAt)
+	report.Added, report.Updated, report.Skipped = merge.Added, merge.Updated, merge.Skipped
+	if dryRun || (!report.HasChanges() && run.PlaybookRevision == target.Revision) {
+		return report, nil
+	}
+
+	var originalRun *PlaybookRun
+	if s.shouldDiffRunUpdates() {
+		originalRun = run.Clone()
+	}
+
+	// The run is now up to date with the target revision, which the next propagation diffs from.
+	readAt := run.UpdateAt
+	run.Checklists = merged
+	run.PlaybookRevision = target.Revision
+	run, err = s.store.UpdatePlaybookRunIfUnmodified(run, readAt)
+	if errors.Is(err, ErrStaleVersion) {
+		return report, err
+	}
+	if err != nil {
+		logger.WithError(err).Warn("failed to update run with propagated checklists")
+		report.Error = "failed to update run"
+		return report, nil
+	}
+
+	s.sendPlaybookRunObjectUpdatedWS(run.ID, originalRun, run)
+
+	return report, nil
+}
+
 // GetPlaybookRunMetadata gets ancillary metadata about a playbook run.
 func (s *PlaybookRunServiceImpl) GetPlaybookRunMetadata(playbookRunID string, hasChannelAccess bool) (*Metadata, error) {
 	playbookRun, err := s.GetPlaybookRun(playbookRunID)
@@ -2076,6 +2357,152 @@ func (s *PlaybookRunServiceImpl) GetPlaybookRunsForChannelByUser(channelID strin
 	return result.Items, nil
 }
 
+// GetInProgressRunsForChannel returns the in-progress runs linked to channelID, with their
+// property fields and values.
+func (s *PlaybookRunServiceImpl) GetInProgressRunsForChannel(channelID string) ([]PlaybookRun, error) {
+	runIDs, err := s.store.GetPlaybookRunIDsForChannel(channelID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get runs of channel %s", channelID)
+	}
+
+	runs := make([]PlaybookRun, 0, len(runIDs))
+	for _, runID := range runIDs {
+		run, err := s.GetPlaybookRun(runID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get playbook run %s", runID)
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+// StartRunFromChannelAction creates a run of playbookID reported by userID when a channel action
+// fires in channelID, linked to post if not nil. The run is created in the channel's team and
+// follows the playbook's channel configuration; its owner is the playbook's default owner when
+// enabled and userID otherwise.
+func (s *PlaybookRunServiceImpl) StartRunFromChannelAction(playbookID, channelID, userID string, post *model.Post, propertyValues map[string]string) (*PlaybookRun, error) {
+	pb, err := s.playbookService.GetPublished(playbookID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get playbook")
+	}
+	if pb.DeleteAt != 0 {
+		return nil, errors.Wrap(ErrMalformedPlaybookRun, "playbook is archived, cannot create a new run using an archived playbook")
+	}
+
+	channel, err := s.pluginAPI.Channel.Get(channelID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get channel %s", channelID)
+	}
+	if channel.TeamId != pb.TeamID {
+		return nil, errors.Wrap(ErrMalformedPlaybookRun, "playbook belongs to another team than the channel")
+	}
+
+	playbookRun := &PlaybookRun{
+		TeamID:     pb.TeamID,
+		PlaybookID: pb.ID,
+		ChannelID:  pb.GetRunChannelID(),
+	}
+	if playbookRun.ChannelID == "" && pb.ChannelNameTemplate == "" {
+		playbookRun.Name = pb.Title
+	}
+	if post != nil {
+		playbookRun.PostID = post.Id
+	}
+	playbookRun.SetChecklistFromPlaybook(pb)
+	playbookRun.SetConfigurationFromPlaybook(pb, RunSourceChannelAction)
+	playbookRun.ReporterUserID = userID
+
+	initialValues := s.convertChannelActionPropertyValues(&pb, propertyValues)
+	if err = s.ResolveRunCreationParams(playbookRun, &pb, initialValues, RunSourceChannelAction); err != nil {
+		return nil, errors.Wrap(err, "failed to resolve run creation params")
+	}
+
+	return s.CreatePlaybookRun(playbookRun, &pb, userID, pb.CreatePublicPlaybookRun, RunSourceChannelAction, initialValues)
+}
+
+// convertChannelActionPropertyValues converts the text values extracted by a channel action into
+// values of the playbook's property fields. Values that don't fit their field are skipped, so that
+// a malformed alert still starts a run.
+func (s *PlaybookRunServiceImpl) convertChannelActionPropertyValues(pb *Playbook, values map[string]string) map[string]json.RawMessage {
+	if len(values) == 0 {
+		return nil
+	}
+
+	logger := logrus.WithField("playbook_id", pb.ID)
+	fields, _, err := s.playbookService.GetPropertiesAndConditions(*pb)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get property fields, ignoring the property values extracted by the channel action")
+		return nil
+	}
+
+	converted := make(map[string]json.RawMessage, len(values))
+	for i := range fields {
+		field := &fields[i]
+		text, ok := values[field.ID]
+		if !ok || field.IsComputed() {
+			continue
+		}
+		value, err := textToPropertyValue(field, strings.TrimSpace(text))
+		if err == nil {
+			value, err = s.propertyService.SanitizePropertyValue(field, value)
+		}
+		if err != nil {
+			logger.WithError(err).WithField("field_id", field.ID).Warn("skipping property value extracted by the channel action")
+			continue
+		}
+		converted[field.ID] = value
+	}
+	return converted
+}
+
+// textToPropertyValue returns the value of field written as text. Select options are given by
+// name, multiselect ones separated by commas.
+func textToPropertyValue(field *PropertyField, text string) (json.RawMessage, error) {
+	switch field.Type {
+	case model.PropertyFieldTypeSelect:
+		option := findOptionByName(field.Attrs.Options, text)
+		if option == nil {
+			return nil, errors.Errorf("field has no option named %q", text)
+		}
+		return json.Marshal(option.GetID())
+	case model.PropertyFieldTypeMultiselect:
+		optionIDs := []string{}
+		for _, name := range strings.Split(text, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			option := findOptionByName(field.Attrs.Options, name)
+			if option == nil {
+				return nil, errors.Errorf("field has no option named %q", name)
+			}
+			optionIDs = append(optionIDs, option.GetID())
+		}
+		return json.Marshal(optionIDs)
+	case model.PropertyFieldTypeUser, model.PropertyFieldTypeMultiuser:
+		return nil, errors.New("user fields can't be set from text")
+	default:
+		return json.Marshal(text)
+	}
+}
+
+// GetSharedPropertyFieldStats counts the values of the team property field options.SharedFieldID
+// across the playbook runs selected by options
+func (s *PlaybookRunServiceImpl) GetSharedPropertyFieldStats(requesterInfo RequesterInfo, options PlaybookRunFilterOptions) (*SharedPropertyFieldStats, error) {
+	if options.SharedFieldID == "" {
+		return nil, errors.New("shared field ID is required")
+	}
+
+	stats, err := s.store.GetSharedPropertyFieldStats(requesterInfo, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get shared property field stats from the store")
+	}
+
+	return stats, nil
+}
+
 // GetOwners returns all the owners of the playbook runs selected by options
 func (s *PlaybookRunServiceImpl) GetOwners(requesterInfo RequesterInfo, options PlaybookRunFilterOptions) ([]OwnerInfo, error) {
 	owners, err := s.store.GetOwners(requesterInfo, options)
@@ -2172,7 +2599,7 @@ func (s *PlaybookRunServiceImpl) ChangeOwner(playbookRunID, userID, ownerID stri
 
 	// Snapshot AFTER AddParticipants so the WS diff only includes the owner change.
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2277,11 +2704,6 @@ func (s *PlaybookRunServiceImpl) ModifyCheckedState(playbookRunID, userID, newSt
 	model.AddEventParameterToAuditRec(auditRec, "checklistNumber", checklistNumber)
 	model.AddEventParameterToAuditRec(auditRec, "itemNumber", itemNumber)
 
-	type Details struct {
-		Action string `json:"action,omitempty"`
-		Task   string `json:"task,omitempty"`
-	}
-
 	playbookRunToModify, err := s.checklistItemParamsVerify(playbookRunID, userID, checklistNumber, itemNumber)
 	if err != nil {
 		return err
@@ -2298,7 +2720,7 @@ func (s *PlaybookRunServiceImpl) ModifyCheckedState(playbookRunID, userID, newSt
 	model.AddEventParameterToAuditRec(auditRec, "currentState", itemToCheck.State)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2307,25 +2729,7 @@ func (s *PlaybookRunServiceImpl) ModifyCheckedState(playbookRunID, userID, newSt
 		return nil
 	}
 
-	details := Details{
-		Action: "check",
-		Task:   stripmd.Strip(itemToCheck.Title),
-	}
-
-	modifyMessage := fmt.Sprintf("checked off checklist item **%v**", stripmd.Strip(itemToCheck.Title))
-	if newState == ChecklistItemStateOpen {
-		details.Action = "uncheck"
-		modifyMessage = fmt.Sprintf("unchecked checklist item **%v**", stripmd.Strip(itemToCheck.Title))
-	}
-	if newState == ChecklistItemStateSkipped {
-		details.Action = "skip"
-		modifyMessage = fmt.Sprintf("skipped checklist item **%v**", stripmd.Strip(itemToCheck.Title))
-	}
-	if itemToCheck.State == ChecklistItemStateSkipped && newState == ChecklistItemStateOpen {
-		details.Action = "restore"
-		modifyMessage = fmt.Sprintf("restored checklist item **%v**", stripmd.Strip(itemToCheck.Title))
-	}
-
+	oldState := itemToCheck.State
 	itemToCheck.State = newState
 	timestamp := model.GetMillis()
 	itemToCheck.StateModified = timestamp
@@ -2337,35 +2741,69 @@ func (s *PlaybookRunServiceImpl) ModifyCheckedState(playbookRunID, userID, newSt
 		return errors.Wrapf(err, "failed to update playbook run, is now in inconsistent state")
 	}
 
-	detailsJSON, err := json.Marshal(details)
+	event, action, err := taskStateModifiedEvent(playbookRunID, userID, itemToCheck, oldState)
 	if err != nil {
-		return errors.Wrap(err, "failed to encode timeline event details")
-	}
-
-	event := &TimelineEvent{
-		PlaybookRunID: playbookRunID,
-		CreateAt:      itemToCheck.StateModified,
-		EventAt:       itemToCheck.StateModified,
-		EventType:     TaskStateModified,
-		Summary:       modifyMessage,
-		SubjectUserID: userID,
-		Details:       string(detailsJSON),
+		return err
 	}
 
 	if _, err = s.store.CreateTimelineEvent(event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, nil)
 
 	// Mark success and add result state for audit
 	auditRec.Success()
-	model.AddEventParameterToAuditRec(auditRec, "action", details.Action)
+	model.AddEventParameterToAuditRec(auditRec, "action", action)
 	model.AddEventParameterToAuditRec(auditRec, "finalState", newState)
 	auditRec.AddEventResultState(*playbookRunToModify)
 
 	return nil
 }
 
+// taskStateModifiedEvent returns the timeline event recording that userID changed the state of
+// item from oldState to its current state, and the action recorded in the event's details.
+func taskStateModifiedEvent(playbookRunID, userID string, item ChecklistItem, oldState string) (*TimelineEvent, string, error) {
+	type Details struct {
+		Action string `json:"action,omitempty"`
+		Task   string `json:"task,omitempty"`
+	}
+
+	details := Details{
+		Action: "check",
+		Task:   stripmd.Strip(item.Title),
+	}
+
+	modifyMessage := fmt.Sprintf("checked off checklist item **%v**", stripmd.Strip(item.Title))
+	if item.State == ChecklistItemStateOpen {
+		details.Action = "uncheck"
+		modifyMessage = fmt.Sprintf("unchecked checklist item **%v**", stripmd.Strip(item.Title))
+	}
+	if item.State == ChecklistItemStateSkipped {
+		details.Action = "skip"
+		modifyMessage = fmt.Sprintf("skipped checklist item **%v**", stripmd.Strip(item.Title))
+	}
+	if oldState == ChecklistItemStateSkipped && item.State == ChecklistItemStateOpen {
+		details.Action = "restore"
+		modifyMessage = fmt.Sprintf("restored checklist item **%v**", stripmd.Strip(item.Title))
+	}
+
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to encode timeline event details")
+	}
+
+	return &TimelineEvent{
+		PlaybookRunID: playbookRunID,
+		CreateAt:      item.StateModified,
+		EventAt:       item.StateModified,
+		EventType:     TaskStateModified,
+		Summary:       modifyMessage,
+		SubjectUserID: userID,
+		Details:       string(detailsJSON),
+	}, details.Action, nil
+}
+
 // ToggleCheckedState checks or unchecks the specified checklist item
 func (s *PlaybookRunServiceImpl) ToggleCheckedState(playbookRunID, userID string, checklistNumber, itemNumber int) error {
 	auditRec := plugin.MakeAuditRecord("toggleChecklistItemState", model.AuditStatusFail)
@@ -2429,7 +2867,7 @@ func (s *PlaybookRunServiceImpl) SetAssignee(playbookRunID, userID, assigneeID s
 	model.AddEventParameterToAuditRec(auditRec, "currentAssigneeID", itemToCheck.AssigneeID)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2441,24 +2879,14 @@ func (s *PlaybookRunServiceImpl) SetAssignee(playbookRunID, userID, assigneeID s
 		return nil
 	}
 
-	newAssigneeUserAtMention := noAssigneeName
-	if assigneeID != "" {
-		var newUser *model.User
-		newUser, err = s.pluginAPI.User.Get(assigneeID)
-		if err != nil {
-			return errors.Wrapf(err, "failed to resolve user %s", assigneeID)
-		}
-		newAssigneeUserAtMention = "@" + newUser.Username
+	newAssigneeUserAtMention, err := s.assigneeMention(assigneeID)
+	if err != nil {
+		return err
 	}
 
-	oldAssigneeUserAtMention := noAssigneeName
-	if itemToCheck.AssigneeID != "" {
-		var oldUser *model.User
-		oldUser, err = s.pluginAPI.User.Get(itemToCheck.AssigneeID)
-		if err != nil {
-			return errors.Wrapf(err, "failed to resolve user %s", itemToCheck.AssigneeID)
-		}
-		oldAssigneeUserAtMention = "@" + oldUser.Username
+	oldAssigneeUserAtMention, err := s.assigneeMention(itemToCheck.AssigneeID)
+	if err != nil {
+		return err
 	}
 
 	itemToCheck.AssigneeID = assigneeID
@@ -2471,29 +2899,9 @@ func (s *PlaybookRunServiceImpl) SetAssignee(playbookRunID, userID, assigneeID s
 		return errors.Wrapf(err, "failed to update playbook run; it is now in an inconsistent state")
 	}
 
-	var dmMsg string
-	if assigneeID != "" && assigneeID != userID {
-		if subjectUser, userErr := s.pluginAPI.User.Get(userID); userErr != nil {
-			s.pluginAPI.Log.Warn("failed to get user for assignee DM", "user_id", userID, "err", userErr.Error())
-		} else {
-			runURL := fmt.Sprintf("[%s](%s?from=dm_assignedtask)\n", playbookRunToModify.Name, GetRunDetailsRelativeURL(playbookRunID))
-			dmMsg = fmt.Sprintf("@%s assigned you the task **%s** (previously assigned to %s) for the run: %s   #taskassigned",
-				subjectUser.Username, stripmd.Strip(itemToCheck.Title), oldAssigneeUserAtMention, runURL)
-		}
-	}
-	s.addAssigneeParticipantAndDM(playbookRunID, userID, assigneeID, playbookRunToModify.ParticipantIDs, playbookRunToModify.OwnerUserID, dmMsg)
-
-	modifyMessage := fmt.Sprintf("changed assignee of checklist item **%s** from **%s** to **%s**",
-		stripmd.Strip(itemToCheck.Title), oldAssigneeUserAtMention, newAssigneeUserAtMention)
-	event := &TimelineEvent{
-		PlaybookRunID: playbookRunID,
-		CreateAt:      itemToCheck.AssigneeModified,
-		EventAt:       itemToCheck.AssigneeModified,
-		EventType:     AssigneeChanged,
-		Summary:       modifyMessage,
-		SubjectUserID: userID,
-	}
+	s.notifyAssignee(playbookRunToModify, playbookRunToModify.ParticipantIDs, userID, *itemToCheck, oldAssigneeUserAtMention)
 
+	event := assigneeChangedEvent(playbookRunID, userID, *itemToCheck, oldAssigneeUserAtMention, newAssigneeUserAtMention)
 	if _, err = s.store.CreateTimelineEvent(event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
@@ -2505,7 +2913,55 @@ func (s *PlaybookRunServiceImpl) SetAssignee(playbookRunID, userID, assigneeID s
 	model.AddEventParameterToAuditRec(auditRec, "assigneeModified", itemToCheck.AssigneeModified)
 	auditRec.AddEventResultState(*playbookRunToModify)
 
-	return nil
+	return nil
+}
+
+// assigneeMention returns how the assignee of a checklist item is shown in timeline events and
+// DMs: an @-mention of the user, or noAssigneeName when the item is unassigned.
+func (s *PlaybookRunServiceImpl) assigneeMention(assigneeID string) (string, error) {
+	if assigneeID == "" {
+		return noAssigneeName, nil
+	}
+
+	user, err := s.pluginAPI.User.Get(assigneeID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve user %s", assigneeID)
+	}
+
+	return "@" + user.Username, nil
+}
+
+// notifyAssignee adds the user item was just assigned to by userID as a participant of the run,
+// unless already in participantIDs, and sends them a DM unless they assigned the item to
+// themselves.
+func (s *PlaybookRunServiceImpl) notifyAssignee(playbookRun *PlaybookRun, participantIDs []string, userID string, item ChecklistItem, oldAssigneeUserAtMention string) {
+	var dmMsg string
+	if item.AssigneeID != "" && item.AssigneeID != userID {
+		if subjectUser, userErr := s.pluginAPI.User.Get(userID); userErr != nil {
+			s.pluginAPI.Log.Warn("failed to get user for assignee DM", "user_id", userID, "err", userErr.Error())
+		} else {
+			runURL := fmt.Sprintf("[%s](%s?from=dm_assignedtask)\n", playbookRun.Name, GetRunDetailsRelativeURL(playbookRun.ID))
+			dmMsg = fmt.Sprintf("@%s assigned you the task **%s** (previously assigned to %s) for the run: %s   #taskassigned",
+				subjectUser.Username, stripmd.Strip(item.Title), oldAssigneeUserAtMention, runURL)
+		}
+	}
+	s.addAssigneeParticipantAndDM(playbookRun.ID, userID, item.AssigneeID, participantIDs, playbookRun.OwnerUserID, dmMsg)
+}
+
+// assigneeChangedEvent returns the timeline event recording that userID changed the assignee of
+// item.
+func assigneeChangedEvent(playbookRunID, userID string, item ChecklistItem, oldAssigneeUserAtMention, newAssigneeUserAtMention string) *TimelineEvent {
+	modifyMessage := fmt.Sprintf("changed assignee of checklist item **%s** from **%s** to **%s**",
+		stripmd.Strip(item.Title), oldAssigneeUserAtMention, newAssigneeUserAtMention)
+
+	return &TimelineEvent{
+		PlaybookRunID: playbookRunID,
+		CreateAt:      item.AssigneeModified,
+		EventAt:       item.AssigneeModified,
+		EventType:     AssigneeChanged,
+		Summary:       modifyMessage,
+		SubjectUserID: userID,
+	}
 }
 
 // SetPropertyUserAssignee sets a checklist item's assignee to whoever the given User-type
@@ -2562,7 +3018,7 @@ func (s *PlaybookRunServiceImpl) SetPropertyUserAssignee(playbookRunID, userID s
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRun.Clone()
 	}
 
@@ -2647,13 +3103,13 @@ func (s *PlaybookRunServiceImpl) SetRoleAssignee(playbookRunID, userID, assignee
 		resolvedAssigneeID = playbookRunToModify.ReporterUserID
 	}
 
-	if itemToCheck.AssigneeType == assigneeType && itemToCheck.AssigneeID == resolvedAssigneeID && itemToCheck.AssigneePropertyFieldID == "" {
+	if itemToCheck.AssigneeType == assigneeType && itemToCheck.AssigneeID == resolvedAssigneeID && itemToCheck.AssigneePropertyFieldID == "" && itemToCheck.AssigneeRoleID == "" {
 		auditRec.Success()
 		return nil
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2661,6 +3117,7 @@ func (s *PlaybookRunServiceImpl) SetRoleAssignee(playbookRunID, userID, assignee
 	itemToCheck.AssigneeType = assigneeType
 	itemToCheck.AssigneeID = resolvedAssigneeID
 	itemToCheck.AssigneePropertyFieldID = ""
+	itemToCheck.AssigneeRoleID = ""
 	itemToCheck.AssigneeModified = timestamp
 	updateChecklistAndItemTimestamp(&playbookRunToModify.Checklists[checklistNumber], itemToCheck, timestamp)
 
@@ -2702,6 +3159,187 @@ func (s *PlaybookRunServiceImpl) SetRoleAssignee(playbookRunID, userID, assignee
 	return nil
 }
 
+// SetRunRoleAssignee assigns the specified checklist item to whoever holds the given run role.
+// The item follows the role when it is later given to someone else.
+func (s *PlaybookRunServiceImpl) SetRunRoleAssignee(playbookRunID, userID, roleID string, checklistNumber, itemNumber int) error {
+	auditRec := plugin.MakeAuditRecord("setChecklistItemRunRoleAssignee", model.AuditStatusFail)
+	defer s.api.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookRunID", playbookRunID)
+	model.AddEventParameterToAuditRec(auditRec, "roleID", roleID)
+	model.AddEventParameterToAuditRec(auditRec, "checklistNumber", checklistNumber)
+	model.AddEventParameterToAuditRec(auditRec, "itemNumber", itemNumber)
+
+	playbookRunToModify, err := s.checklistItemParamsVerify(playbookRunID, userID, checklistNumber, itemNumber)
+	if err != nil {
+		return err
+	}
+
+	role := playbookRunToModify.GetRunRole(roleID)
+	if role == nil {
+		return errors.Wrapf(ErrMalformedPlaybookRun, "run role %s not found on run", roleID)
+	}
+
+	itemToCheck := &playbookRunToModify.Checklists[checklistNumber].Items[itemNumber]
+	model.AddEventParameterToAuditRec(auditRec, "taskTitle", itemToCheck.Title)
+
+	if itemToCheck.AssigneeType == AssigneeTypeRunRole && itemToCheck.AssigneeRoleID == roleID && itemToCheck.AssigneeID == role.UserID {
+		auditRec.Success()
+		return nil
+	}
+
+	var originalRun *PlaybookRun
+	if s.shouldDiffRunUpdates() {
+		originalRun = playbookRunToModify.Clone()
+	}
+
+	timestamp := model.GetMillis()
+	itemToCheck.AssigneeType = AssigneeTypeRunRole
+	itemToCheck.AssigneeRoleID = roleID
+	itemToCheck.AssigneePropertyFieldID = ""
+	itemToCheck.AssigneeID = role.UserID
+	itemToCheck.AssigneeModified = timestamp
+	updateChecklistAndItemTimestamp(&playbookRunToModify.Checklists[checklistNumber], itemToCheck, timestamp)
+
+	playbookRunToModify, err = s.store.UpdatePlaybookRun(playbookRunToModify)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update playbook run; it is now in an inconsistent state")
+	}
+
+	var dmMsg string
+	if role.UserID != "" && role.UserID != userID {
+		if subjectUser, userErr := s.pluginAPI.User.Get(userID); userErr != nil {
+			s.pluginAPI.Log.Warn("failed to get user for run role assignee DM", "user_id", userID, "err", userErr.Error())
+		} else {
+			runURL := fmt.Sprintf("[%s](%s?from=dm_assignedtask)\n", playbookRunToModify.Name, GetRunDetailsRelativeURL(playbookRunID))
+			dmMsg = fmt.Sprintf("@%s assigned you the task **%s** (as %s) for the run: %s   #taskassigned",
+				subjectUser.Username, stripmd.Strip(itemToCheck.Title), role.Name, runURL)
+		}
+	}
+	s.addAssigneeParticipantAndDM(playbookRunID, userID, role.UserID, playbookRunToModify.ParticipantIDs, playbookRunToModify.OwnerUserID, dmMsg)
+
+	modifyMessage := fmt.Sprintf("set assignee of checklist item **%s** to %s", stripmd.Strip(itemToCheck.Title), role.Name)
+	event := &TimelineEvent{
+		PlaybookRunID: playbookRunID,
+		CreateAt:      timestamp,
+		EventAt:       timestamp,
+		EventType:     AssigneeChanged,
+		Summary:       modifyMessage,
+		SubjectUserID: userID,
+	}
+	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, nil)
+
+	auditRec.Success()
+	model.AddEventParameterToAuditRec(auditRec, "assigneeModified", itemToCheck.AssigneeModified)
+	auditRec.AddEventResultState(*playbookRunToModify)
+	return nil
+}
+
+// AssignRunRole gives the run role roleID to assigneeID, or leaves it unassigned when assigneeID
+// is empty. Checklist items assigned to the role are reassigned to its new holder.
+func (s *PlaybookRunServiceImpl) AssignRunRole(playbookRunID, userID, roleID, assigneeID string) error {
+	auditRec := plugin.MakeAuditRecord("assignRunRole", model.AuditStatusFail)
+	defer s.api.LogAuditRec(auditRec)
+
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookRunID", playbookRunID)
+	model.AddEventParameterToAuditRec(auditRec, "roleID", roleID)
+	model.AddEventParameterToAuditRec(auditRec, "assigneeID", assigneeID)
+
+	playbookRunToModify, err := s.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		return err
+	}
+	if err = EnsureRunIsActive(playbookRunToModify); err != nil {
+		return err
+	}
+
+	role := playbookRunToModify.GetRunRole(roleID)
+	if role == nil {
+		return errors.Wrapf(ErrNotFound, "run role %s not found on run %s", roleID, playbookRunID)
+	}
+	if role.UserID == assigneeID {
+		auditRec.Success()
+		return nil
+	}
+
+	var assignee *model.User
+	if assigneeID != "" {
+		assignee, err = s.pluginAPI.User.Get(assigneeID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve user %s", assigneeID)
+		}
+		if playbookRunToModify.TeamID != "" && !IsMemberOfTeam(assigneeID, playbookRunToModify.TeamID, s.pluginAPI) {
+			return errors.Wrapf(ErrMalformedPlaybookRun, "user %s is not a member of the run's team and cannot hold a run role", assignee.Username)
+		}
+		if err = s.AddParticipants(playbookRunID, []string{assigneeID}, userID, false, false); err != nil {
+			return errors.Wrap(err, "failed to add run role holder as a participant")
+		}
+		// Reload the run so the update below keeps the new participant.
+		if playbookRunToModify, err = s.GetPlaybookRun(playbookRunID); err != nil {
+			return err
+		}
+		role = playbookRunToModify.GetRunRole(roleID)
+		if role == nil {
+			return errors.Wrapf(ErrNotFound, "run role %s not found on run %s", roleID, playbookRunID)
+		}
+	}
+
+	var originalRun *PlaybookRun
+	if s.shouldDiffRunUpdates() {
+		originalRun = playbookRunToModify.Clone()
+	}
+
+	previousHolderID := role.UserID
+	role.UserID = assigneeID
+	resolveRunRoleAssignments(playbookRunToModify.Checklists, playbookRunToModify.RunRoles)
+
+	playbookRunToModify, err = s.store.UpdatePlaybookRun(playbookRunToModify)
+	if err != nil {
+		return errors.Wrap(err, "failed to update run roles")
+	}
+
+	summary := fmt.Sprintf("%s unassigned", role.Name)
+	if assignee != nil {
+		summary = fmt.Sprintf("%s assigned to @%s", role.Name, assignee.Username)
+	}
+	eventTime := model.GetMillis()
+	event := &TimelineEvent{
+		PlaybookRunID: playbookRunID,
+		CreateAt:      eventTime,
+		EventAt:       eventTime,
+		EventType:     RunRoleChanged,
+		Summary:       summary,
+		SubjectUserID: assigneeID,
+		CreatorUserID: userID,
+	}
+	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	if assigneeID != "" && assigneeID != userID {
+		if subjectUser, userErr := s.pluginAPI.User.Get(userID); userErr == nil {
+			msg := fmt.Sprintf("@%s made you **%s** for the run: [%s](%s)",
+				subjectUser.Username, role.Name, playbookRunToModify.Name, GetRunDetailsRelativeURL(playbookRunID))
+			if err = s.poster.DM(assigneeID, &model.Post{Message: msg}); err != nil {
+				logrus.WithError(err).WithField("playbook_run_id", playbookRunID).Warn("failed to send DM to new run role holder")
+			}
+		}
+	}
+
+	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, nil)
+
+	auditRec.Success()
+	model.AddEventParameterToAuditRec(auditRec, "previousHolderID", previousHolderID)
+	auditRec.AddEventResultState(*playbookRunToModify)
+	return nil
+}
+
 func (s *PlaybookRunServiceImpl) SetCommandToChecklistItem(playbookRunID, userID string, checklistNumber, itemNumber int, newCommand string) error {
 	playbookRunToModify, err := s.checklistItemParamsVerify(playbookRunID, userID, checklistNumber, itemNumber)
 	if err != nil {
@@ -2713,7 +3351,7 @@ func (s *PlaybookRunServiceImpl) SetCommandToChecklistItem(playbookRunID, userID
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2746,7 +3384,7 @@ func (s *PlaybookRunServiceImpl) SetTaskActionsToChecklistItem(playbookRunID, us
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2789,11 +3427,10 @@ func (s *PlaybookRunServiceImpl) SetDueDate(playbookRunID, userID string, duedat
 	model.AddEventParameterToAuditRec(auditRec, "currentDueDate", itemToCheck.DueDate)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
-	itemToCheck.DueDate = duedate
-	updateChecklistAndItemTimestamp(&playbookRunToModify.Checklists[checklistNumber], &itemToCheck, 0)
+	setChecklistItemDueDate(&playbookRunToModify.Checklists[checklistNumber], &itemToCheck, duedate, 0)
 	playbookRunToModify.Checklists[checklistNumber].Items[itemNumber] = itemToCheck
 
 	playbookRunToModify, err = s.store.UpdatePlaybookRun(playbookRunToModify)
@@ -2867,7 +3504,7 @@ func (s *PlaybookRunServiceImpl) RunChecklistItemSlashCommand(playbookRunID, use
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRun.Clone()
 	}
 
@@ -2915,7 +3552,7 @@ func (s *PlaybookRunServiceImpl) DuplicateChecklistItem(playbookRunID, userID st
 	updateChecklistAndItemTimestamp(&playbookRunToModify.Checklists[checklistNumber], &checklistItem, 0)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -2929,6 +3566,7 @@ func (s *PlaybookRunServiceImpl) DuplicateChecklistItem(playbookRunID, userID st
 		return errors.Wrapf(err, "failed to update playbook run")
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	return nil
@@ -2956,7 +3594,7 @@ func (s *PlaybookRunServiceImpl) AddChecklist(playbookRunID, userID string, chec
 	model.AddEventParameterToAuditRec(auditRec, "currentChecklistCount", len(playbookRunToModify.Checklists))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 	timestamp := model.GetMillis()
@@ -2971,6 +3609,7 @@ func (s *PlaybookRunServiceImpl) AddChecklist(playbookRunID, userID string, chec
 		return err
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	// Mark success and add result state for audit
@@ -2990,7 +3629,7 @@ func (s *PlaybookRunServiceImpl) DuplicateChecklist(playbookRunID, userID string
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3012,6 +3651,7 @@ func (s *PlaybookRunServiceImpl) DuplicateChecklist(playbookRunID, userID string
 		return errors.Wrapf(err, "failed to update playbook run")
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	return nil
@@ -3040,7 +3680,7 @@ func (s *PlaybookRunServiceImpl) RemoveChecklist(playbookRunID, userID string, c
 	model.AddEventParameterToAuditRec(auditRec, "currentChecklistCount", len(playbookRunToModify.Checklists))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3054,6 +3694,7 @@ func (s *PlaybookRunServiceImpl) RemoveChecklist(playbookRunID, userID string, c
 		return err
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	// Mark success and add result state for audit
@@ -3094,7 +3735,7 @@ func (s *PlaybookRunServiceImpl) RenameChecklist(playbookRunID, userID string, c
 	model.AddEventParameterToAuditRec(auditRec, "currentTitle", currentChecklist.Title)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3109,6 +3750,7 @@ func (s *PlaybookRunServiceImpl) RenameChecklist(playbookRunID, userID string, c
 		return err
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	// Mark success and add result state for audit
@@ -3143,7 +3785,7 @@ func (s *PlaybookRunServiceImpl) AddChecklistItem(playbookRunID, userID string,
 	model.AddEventParameterToAuditRec(auditRec, "currentItemCount", len(currentChecklist.Items))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3158,6 +3800,7 @@ func (s *PlaybookRunServiceImpl) AddChecklistItem(playbookRunID, userID string,
 		return err
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	// Mark success and add result state for audit
@@ -3193,7 +3836,7 @@ func (s *PlaybookRunServiceImpl) RemoveChecklistItem(playbookRunID, userID strin
 	model.AddEventParameterToAuditRec(auditRec, "currentItemCount", len(currentChecklist.Items))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 	playbookRunToModify.Checklists[checklistNumber].Items = append(
@@ -3211,6 +3854,7 @@ func (s *PlaybookRunServiceImpl) RemoveChecklistItem(playbookRunID, userID strin
 		return err
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	// Mark success and add result state for audit
@@ -3230,7 +3874,7 @@ func (s *PlaybookRunServiceImpl) SkipChecklist(playbookRunID, userID string, che
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3246,6 +3890,7 @@ func (s *PlaybookRunServiceImpl) SkipChecklist(playbookRunID, userID string, che
 		return errors.Wrapf(err, "failed to update playbook run")
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	return nil
@@ -3259,7 +3904,7 @@ func (s *PlaybookRunServiceImpl) RestoreChecklist(playbookRunID, userID string,
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3274,6 +3919,7 @@ func (s *PlaybookRunServiceImpl) RestoreChecklist(playbookRunID, userID string,
 		return errors.Wrapf(err, "failed to update playbook run")
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	return nil
@@ -3287,7 +3933,7 @@ func (s *PlaybookRunServiceImpl) SkipChecklistItem(playbookRunID, userID string,
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3301,6 +3947,7 @@ func (s *PlaybookRunServiceImpl) SkipChecklistItem(playbookRunID, userID string,
 		return errors.Wrapf(err, "failed to update playbook run")
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	return nil
@@ -3314,7 +3961,7 @@ func (s *PlaybookRunServiceImpl) RestoreChecklistItem(playbookRunID, userID stri
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3326,6 +3973,7 @@ func (s *PlaybookRunServiceImpl) RestoreChecklistItem(playbookRunID, userID stri
 		return errors.Wrapf(err, "failed to update playbook run")
 	}
 
+	s.refreshComputedPropertyValues(playbookRunToModify)
 	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
 
 	return nil
@@ -3352,7 +4000,7 @@ func (s *PlaybookRunServiceImpl) EditChecklistItem(playbookRunID, userID string,
 	model.AddEventParameterToAuditRec(auditRec, "currentCommand", item.Command)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3389,7 +4037,7 @@ func (s *PlaybookRunServiceImpl) MoveChecklist(playbookRunID, userID string, sou
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -3436,14 +4084,28 @@ func (s *PlaybookRunServiceImpl) MoveChecklistItem(playbookRunID, userID string,
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
-	timestamp := model.GetMillis()
+	moveChecklistItem(playbookRunToModify.Checklists, sourceChecklistIdx, sourceItemIdx, destChecklistIdx, destItemIdx, model.GetMillis())
+
+	playbookRunToModify, err = s.store.UpdatePlaybookRun(playbookRunToModify)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update playbook run")
+	}
+
+	s.refreshComputedPropertyValues(playbookRunToModify)
+	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
+
+	return nil
+}
 
+// moveChecklistItem moves an item between the checklists in place, marking the item and the
+// checklists it leaves and enters as updated at timestamp. The indices must be valid.
+func moveChecklistItem(checklists []Checklist, sourceChecklistIdx, sourceItemIdx, destChecklistIdx, destItemIdx int, timestamp int64) {
 	// Moved item
-	sourceChecklist := playbookRunToModify.Checklists[sourceChecklistIdx].Items
+	sourceChecklist := checklists[sourceChecklistIdx].Items
 	itemMoved := sourceChecklist[sourceItemIdx]
 	updateChecklistItemTimestamp(&itemMoved, timestamp)
 
@@ -3451,7 +4113,7 @@ func (s *PlaybookRunServiceImpl) MoveChecklistItem(playbookRunID, userID string,
 	sourceChecklist = append(sourceChecklist[:sourceItemIdx], sourceChecklist[sourceItemIdx+1:]...)
 
 	// Insert item in new location
-	destChecklist := playbookRunToModify.Checklists[destChecklistIdx].Items
+	destChecklist := checklists[destChecklistIdx].Items
 	if sourceChecklistIdx == destChecklistIdx {
 		destChecklist = sourceChecklist
 	}
@@ -3460,29 +4122,21 @@ func (s *PlaybookRunServiceImpl) MoveChecklistItem(playbookRunID, userID string,
 	copy(destChecklist[destItemIdx+1:], destChecklist[destItemIdx:])
 	destChecklist[destItemIdx] = itemMoved
 
-	// Update the playbookRunToModify checklists. If the source and destination indices
+	// Update the checklists. If the source and destination indices
 	// are the same, we only need to update the checklist to its final state (destChecklist)
 	if sourceChecklistIdx == destChecklistIdx {
-		playbookRunToModify.Checklists[sourceChecklistIdx].Items = destChecklist
-		playbookRunToModify.Checklists[sourceChecklistIdx].UpdateAt = timestamp
-		playbookRunToModify.Checklists[sourceChecklistIdx].ItemsOrder = playbookRunToModify.Checklists[sourceChecklistIdx].GetItemsOrder()
+		checklists[sourceChecklistIdx].Items = destChecklist
+		checklists[sourceChecklistIdx].UpdateAt = timestamp
+		checklists[sourceChecklistIdx].ItemsOrder = checklists[sourceChecklistIdx].GetItemsOrder()
 	} else {
-		playbookRunToModify.Checklists[sourceChecklistIdx].Items = sourceChecklist
-		playbookRunToModify.Checklists[destChecklistIdx].Items = destChecklist
-		playbookRunToModify.Checklists[sourceChecklistIdx].ItemsOrder = playbookRunToModify.Checklists[sourceChecklistIdx].GetItemsOrder()
-		playbookRunToModify.Checklists[destChecklistIdx].ItemsOrder = playbookRunToModify.Checklists[destChecklistIdx].GetItemsOrder()
-		playbookRunToModify.Checklists[sourceChecklistIdx].UpdateAt = timestamp
-		playbookRunToModify.Checklists[destChecklistIdx].UpdateAt = timestamp
-	}
-
-	playbookRunToModify, err = s.store.UpdatePlaybookRun(playbookRunToModify)
-	if err != nil {
-		return errors.Wrapf(err, "failed to update playbook run")
+		checklists[sourceChecklistIdx].Items = sourceChecklist
+		checklists[destChecklistIdx].Items = destChecklist
+		checklists[sourceChecklistIdx].ItemsOrder = checklists[sourceChecklistIdx].GetItemsOrder()
+		checklists[destChecklistIdx].ItemsOrder = checklists[destChecklistIdx].GetItemsOrder()
+		checklists[sourceChecklistIdx].UpdateAt = timestamp
+		checklists[destChecklistIdx].UpdateAt = timestamp
 	}
 
-	s.sendPlaybookRunObjectUpdatedWS(playbookRunID, originalRun, playbookRunToModify)
-
-	return nil
 }
 
 // GetChecklistAutocomplete returns the list of checklist items for playbookRuns to be used in autocomplete
@@ -3554,13 +4208,16 @@ func (s *PlaybookRunServiceImpl) GetRunsAutocomplete(playbookRuns []PlaybookRun)
 	return ret, nil
 }
 
+// TodoDigestMessageItems holds the items of a user's todo digest.
 type TodoDigestMessageItems struct {
-	overdueRuns    []RunLink
-	assignedRuns   []AssignedRun
-	inProgressRuns []RunLink
+	OverdueRuns    []RunLink     `json:"overdue_runs"`
+	AssignedRuns   []AssignedRun `json:"assigned_runs"`
+	InProgressRuns []RunLink     `json:"in_progress_runs"`
 }
 
-func (s *PlaybookRunServiceImpl) getTodoDigestMessageItems(userID string) (*TodoDigestMessageItems, error) {
+// GetTodoDigestMessageItems returns the overdue runs, assigned tasks and runs in progress
+// making up the todo digest of userID.
+func (s *PlaybookRunServiceImpl) GetTodoDigestMessageItems(userID string) (*TodoDigestMessageItems, error) {
 	runsOverdue, err := s.GetOverdueUpdateRuns(userID)
 	if err != nil {
 		return nil, err
@@ -3577,9 +4234,9 @@ func (s *PlaybookRunServiceImpl) getTodoDigestMessageItems(userID string) (*Todo
 	}
 
 	return &TodoDigestMessageItems{
-		overdueRuns:    runsOverdue,
-		assignedRuns:   runsAssigned,
-		inProgressRuns: runsInProgress,
+		OverdueRuns:    runsOverdue,
+		AssignedRuns:   runsAssigned,
+		InProgressRuns: runsInProgress,
 	}, nil
 
 }
@@ -3587,15 +4244,15 @@ func (s *PlaybookRunServiceImpl) getTodoDigestMessageItems(userID string) (*Todo
 // buildTodoDigestMessage
 // gathers the list of assigned tasks, participating runs, and overdue updates and builds a combined message with them
 func (s *PlaybookRunServiceImpl) buildTodoDigestMessage(userID string, force bool, shouldSendFullData bool) (*model.Post, error) {
-	digestMessageItems, err := s.getTodoDigestMessageItems(userID)
+	digestMessageItems, err := s.GetTodoDigestMessageItems(userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// if we have no items to send and we're not forced to, return early
-	if len(digestMessageItems.assignedRuns) == 0 &&
-		len(digestMessageItems.overdueRuns) == 0 &&
-		len(digestMessageItems.inProgressRuns) == 0 &&
+	if len(digestMessageItems.AssignedRuns) == 0 &&
+		len(digestMessageItems.OverdueRuns) == 0 &&
+		len(digestMessageItems.InProgressRuns) == 0 &&
 		!force {
 		return nil, nil
 	}
@@ -3605,7 +4262,7 @@ func (s *PlaybookRunServiceImpl) buildTodoDigestMessage(userID string, force boo
 		return nil, err
 	}
 
-	part1 := buildRunsOverdueMessage(digestMessageItems.overdueRuns, user.Locale)
+	part1 := buildRunsOverdueMessage(digestMessageItems.OverdueRuns, user.Locale)
 
 	timezone, err := timeutils.GetUserTimezone(user)
 	if err != nil {
@@ -3614,17 +4271,17 @@ func (s *PlaybookRunServiceImpl) buildTodoDigestMessage(userID string, force boo
 		}).Warn("failed to get user timezone")
 	}
 
-	part2 := buildAssignedTaskMessageSummary(digestMessageItems.assignedRuns, user.Locale, timezone, !force)
-	part3 := buildRunsInProgressMessage(digestMessageItems.inProgressRuns, user.Locale)
+	part2 := buildAssignedTaskMessageSummary(digestMessageItems.AssignedRuns, user.Locale, timezone, !force)
+	part3 := buildRunsInProgressMessage(digestMessageItems.InProgressRuns, user.Locale)
 
 	var message string
-	if shouldSendFullData || len(digestMessageItems.overdueRuns) > 0 {
+	if shouldSendFullData || len(digestMessageItems.OverdueRuns) > 0 {
 		message += part1
 	}
-	if shouldSendFullData || len(digestMessageItems.assignedRuns) > 0 {
+	if shouldSendFullData || len(digestMessageItems.AssignedRuns) > 0 {
 		message += part2
 	}
-	if shouldSendFullData || len(digestMessageItems.inProgressRuns) > 0 {
+	if shouldSendFullData || len(digestMessageItems.InProgressRuns) > 0 {
 		message += part3
 	}
 
@@ -4162,7 +4819,7 @@ func (s *PlaybookRunServiceImpl) UpdateRetrospective(playbookRunID, updaterID st
 	model.AddEventParameterToAuditRec(auditRec, "previousMetricsCount", len(playbookRunToModify.MetricsData))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -4211,7 +4868,7 @@ func (s *PlaybookRunServiceImpl) PublishRetrospective(playbookRunID, publisherID
 	model.AddEventParameterToAuditRec(auditRec, "wasAlreadyCanceled", playbookRunToPublish.RetrospectiveWasCanceled)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToPublish.Clone()
 	}
 
@@ -4338,7 +4995,7 @@ func (s *PlaybookRunServiceImpl) CancelRetrospective(playbookRunID, cancelerID s
 	model.AddEventParameterToAuditRec(auditRec, "currentRetrospectiveLength", len(playbookRunToCancel.Retrospective))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToCancel.Clone()
 	}
 
@@ -4443,7 +5100,7 @@ func (s *PlaybookRunServiceImpl) RequestUpdate(playbookRunID, requesterID string
 	model.AddEventParameterToAuditRec(auditRec, "channelID", playbookRun.ChannelID)
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRun.Clone()
 	}
 
@@ -4530,7 +5187,7 @@ func (s *PlaybookRunServiceImpl) RemoveParticipants(playbookRunID string, userID
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRun.Clone()
 	}
 
@@ -4647,7 +5304,7 @@ func (s *PlaybookRunServiceImpl) AddParticipants(playbookRunID string, userIDs [
 	model.AddEventParameterToAuditRec(auditRec, "currentParticipantCount", len(playbookRun.ParticipantIDs))
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRun.Clone()
 	}
 
@@ -5428,6 +6085,10 @@ func (s *PlaybookRunServiceImpl) SetRunPropertyValue(userID, playbookRunID, prop
 		return nil, errors.Wrap(ErrPropertyFieldNotOnRun, "property field does not belong to this run")
 	}
 
+	if propertyField.IsComputed() {
+		return nil, errors.Wrapf(ErrPropertyFieldReadOnly, "the value of %s is computed", propertyField.Name)
+	}
+
 	// Items set via SetPropertyUserAssignee store the caller's field ID, which may be the
 	// playbook-level parent ID rather than the run-level field ID. Compute the parent so
 	// resolvePropertyUserAssignmentsForField can find both kinds of items.
@@ -5467,12 +6128,13 @@ func (s *PlaybookRunServiceImpl) SetRunPropertyValue(userID, playbookRunID, prop
 	if propertyValue == nil {
 		return nil, errors.New("upsert property value returned nil without error")
 	}
+	s.recordPropertyValueChange(playbookRunID, propertyFieldID, userID, currentValue, propertyValue.Value)
 
 	// Capture the full pre-mutation run for the WS incremental diff. Must be a deep clone
 	// because resolvePropertyUserAssignmentsForField mutates run.Checklists in-place, and a
 	// shallow struct copy would share the same backing slice.
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = run.Clone()
 	}
 
@@ -5523,6 +6185,9 @@ func (s *PlaybookRunServiceImpl) SetRunPropertyValue(userID, playbookRunID, prop
 		if evaluationResult != nil && evaluationResult.AnythingAdded() {
 			s.PostPropertyChangeMessage(userID, run, propertyField, value, evaluationResult)
 		}
+
+		// Formulas and lookups may depend on the value that changed.
+		s.refreshComputedPropertyValues(run)
 	} else {
 		// Value unchanged — still resolve assignees in case new condition tasks were added.
 		// Snapshot before resolving so we can detect which items actually changed.
@@ -5629,6 +6294,9 @@ func (s *PlaybookRunServiceImpl) applyInitialPropertyValues(playbookRun *Playboo
 		if !ok {
 			return nil, errors.Errorf("run field %s (mapped from playbook field %s) not found in copied fields", runFieldID, playbookFieldID)
 		}
+		if runField.IsComputed() {
+			continue
+		}
 		if _, err := s.propertyService.UpsertRunPropertyValueWithField(playbookRun.ID, runField, translatedValue); err != nil {
 			return nil, errors.Wrapf(err, "failed to upsert initial property value for field %s", runFieldID)
 		}
@@ -5638,6 +6306,143 @@ func (s *PlaybookRunServiceImpl) applyInitialPropertyValues(playbookRun *Playboo
 	return playbookRun, nil
 }
 
+// updateComputedPropertyValues recomputes and stores the run's computed property values, merging
+// the ones that changed into run.PropertyValues. It returns the IDs of the fields that changed.
+func (s *PlaybookRunServiceImpl) updateComputedPropertyValues(run *PlaybookRun) ([]string, error) {
+	hasComputedFields := false
+	for i := range run.PropertyFields {
+		if run.PropertyFields[i].IsComputed() {
+			hasComputedFields = true
+			break
+		}
+	}
+	if !hasComputedFields {
+		return nil, nil
+	}
+
+	changedValues, err := s.propertyService.RecomputeRunPropertyValues(run)
+
+	fieldIDs := make([]string, 0, len(changedValues))
+	for _, changed := range changedValues {
+		fieldIDs = append(fieldIDs, changed.FieldID)
+
+		var oldValue json.RawMessage
+		found := false
+		for i := range run.PropertyValues {
+			if run.PropertyValues[i].FieldID == changed.FieldID {
+				oldValue = run.PropertyValues[i].Value
+				run.PropertyValues[i] = changed
+				found = true
+				break
+			}
+		}
+		if !found {
+			run.PropertyValues = append(run.PropertyValues, changed)
+		}
+
+		s.recordPropertyValueChange(run.ID, changed.FieldID, "", oldValue, changed.Value)
+	}
+
+	if err != nil {
+		return fieldIDs, errors.Wrap(err, "failed to recompute property values")
+	}
+	return fieldIDs, nil
+}
+
+// recordPropertyValueChange appends a write of a property value to the run's history. userID is
+// empty for values computed by the server. Failures are logged since the value itself is stored.
+func (s *PlaybookRunServiceImpl) recordPropertyValueChange(playbookRunID, fieldID, userID string, oldValue, newValue json.RawMessage) {
+	_, err := s.store.CreatePropertyValueChange(PropertyValueChange{
+		PlaybookRunID: playbookRunID,
+		FieldID:       fieldID,
+		OldValue:      normalizeHistoryValue(oldValue),
+		NewValue:      normalizeHistoryValue(newValue),
+		UserID:        userID,
+		CreateAt:      model.GetMillis(),
+	})
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"playbook_run_id": playbookRunID,
+			"field_id":        fieldID,
+		}).Warn("failed to record property value change")
+	}
+}
+
+// GetPropertyValueHistory returns the history of the run's property values, oldest first,
+// restricted to fieldID when it is not empty.
+func (s *PlaybookRunServiceImpl) GetPropertyValueHistory(playbookRunID, fieldID string) ([]PropertyValueChange, error) {
+	changes, err := s.store.GetPropertyValueChanges(playbookRunID, PropertyValueChangeOptions{FieldID: fieldID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property value history")
+	}
+	return changes, nil
+}
+
+// GetPropertyValuesAsOf returns the values the run's properties had at asOf, in milliseconds.
+func (s *PlaybookRunServiceImpl) GetPropertyValuesAsOf(playbookRunID string, asOf int64) ([]PropertyValue, error) {
+	current, err := s.propertyService.GetRunPropertyValues(playbookRunID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get run property values")
+	}
+
+	changes, err := s.store.GetPropertyValueChanges(playbookRunID, PropertyValueChangeOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property value history")
+	}
+
+	return PropertyValuesAsOf(current, changes, asOf), nil
+}
+
+// refreshComputedPropertyValues updates the run's computed property values after data they are
+// derived from changed, then evaluates the conditions on the fields that changed and persists the
+// run if that changed its checklists. Failures are logged rather than returned so that they do
+// not fail the change that triggered the refresh.
+func (s *PlaybookRunServiceImpl) refreshComputedPropertyValues(run *PlaybookRun) {
+	if !s.licenseChecker.PlaybookAttributesAllowed() {
+		return
+	}
+
+	logger := logrus.WithField("playbook_run_id", run.ID)
+
+	fieldIDs, err := s.updateComputedPropertyValues(run)
+	if err != nil {
+		logger.WithError(err).Warn("failed to update computed property values")
+	}
+
+	checklistsChanged := false
+	for _, fieldID := range fieldIDs {
+		result, err := s.conditionService.EvaluateConditionsOnValueChanged(run, fieldID)
+		if err != nil {
+			logger.WithError(err).WithField("field_id", fieldID).Warn("failed to evaluate conditions on computed property value")
+			continue
+		}
+		if result != nil && result.AnythingChanged() {
+			checklistsChanged = true
+		}
+	}
+
+	if checklistsChanged {
+		if _, err := s.store.UpdatePlaybookRun(run); err != nil {
+			logger.WithError(err).Warn("failed to persist conditions evaluated on computed property values")
+		}
+	}
+}
+
+// refreshStoredRunComputedPropertyValues reloads the run before refreshing its computed property
+// values, for changes written directly to the store that leave the run at hand outdated.
+func (s *PlaybookRunServiceImpl) refreshStoredRunComputedPropertyValues(playbookRunID string) {
+	if !s.licenseChecker.PlaybookAttributesAllowed() {
+		return
+	}
+
+	run, err := s.GetPlaybookRun(playbookRunID)
+	if err != nil {
+		logrus.WithError(err).WithField("playbook_run_id", playbookRunID).Warn("failed to load run to refresh computed property values")
+		return
+	}
+	s.refreshComputedPropertyValues(run)
+}
+
 // propertyValuesEqual compares two property values for equality based on the property field type
 func (s *PlaybookRunServiceImpl) propertyValuesEqual(field *PropertyField, oldValue, newValue json.RawMessage) bool {
 	switch field.Type {
@@ -5801,9 +6606,10 @@ func resolveOwnerRoleAssignments(checklists []Checklist, ownerID string) {
 // Returns true when the item was already in that state (caller may skip the store write);
 // returns false when role/property-field state must still be persisted, even if AssigneeID is unchanged.
 func applyAssigneeUpdate(item *ChecklistItem, assigneeID string) (noChangeNeeded bool) {
-	noChangeNeeded = assigneeID == item.AssigneeID && item.AssigneeType == AssigneeTypeSpecificUser && item.AssigneePropertyFieldID == ""
+	noChangeNeeded = assigneeID == item.AssigneeID && item.AssigneeType == AssigneeTypeSpecificUser && item.AssigneePropertyFieldID == "" && item.AssigneeRoleID == ""
 	item.AssigneeType = AssigneeTypeSpecificUser
 	item.AssigneePropertyFieldID = ""
+	item.AssigneeRoleID = ""
 	return noChangeNeeded
 }
 
@@ -5815,6 +6621,7 @@ func applyPropertyUserAssigneeUpdate(item *ChecklistItem, propertyFieldID string
 		resolvedUserID == item.AssigneeID
 	item.AssigneeType = AssigneeTypePropertyUser
 	item.AssigneePropertyFieldID = propertyFieldID
+	item.AssigneeRoleID = ""
 	item.AssigneeID = resolvedUserID
 	return noChangeNeeded
 }
@@ -5983,7 +6790,8 @@ func (s *PlaybookRunServiceImpl) formatPropertyValueForDisplay(propertyField *Pr
 	return str, false
 }
 
-// ResolveRunCreationParams validates template placeholders and resolves the run owner.
+// ResolveRunCreationParams validates template placeholders and required property values and
+// resolves the run owner. Missing required values are reported as a MissingRequiredPropertiesError.
 // Call before CreatePlaybookRun to surface template errors before a run number is consumed.
 // It does not allocate a sequential run number; that happens exclusively in CreatePlaybookRun.
 func (s *PlaybookRunServiceImpl) ResolveRunCreationParams(playbookRun *PlaybookRun, pb *Playbook, initialValues map[string]json.RawMessage, source string) error {
@@ -6005,6 +6813,15 @@ func (s *PlaybookRunServiceImpl) ResolveRunCreationParams(playbookRun *PlaybookR
 		return err
 	}
 
+	// Fields are only loaded for templates with placeholders, but required fields must be
+	// checked regardless of the template.
+	if attributesLicensed && fields == nil {
+		fields, err = s.propertyService.GetPropertyFields(pb.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to load property fields for required value validation")
+		}
+	}
+
 	s.resolveOwner(playbookRun, logger)
 
 	template, err := s.prepareTemplate(pb, source, fields, attributesLicensed, logger)
@@ -6013,6 +6830,10 @@ func (s *PlaybookRunServiceImpl) ResolveRunCreationParams(playbookRun *PlaybookR
 	}
 
 	sanitizedValues := s.sanitizePropertyValues(fields, initialValues, logger)
+	addComputedPropertyValues(playbookRun, fields, sanitizedValues)
+	if err := CheckRequiredPropertyValues(fields, sanitizedValues, PropertyAttrsRequiredOnCreate); err != nil {
+		return err
+	}
 	return s.dryRunValidateTemplate(pb, playbookRun, template, fields, sanitizedValues, nil, logger)
 }
 
@@ -6032,7 +6853,7 @@ func (s *PlaybookRunServiceImpl) resolveAndAllocate(playbookRun *PlaybookRun, pb
 	// RunNumberPrefix and ChannelNameTemplate come from the same consistent snapshot as the
 	// allocated SequentialID. An admin editing the playbook between preflight and submit would
 	// otherwise produce a run whose stamped name disagrees with the stored sequential ID.
-	latestPb, err := s.playbookService.Get(pb.ID)
+	latestPb, err := s.playbookService.GetPublished(pb.ID)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to re-read playbook before allocation")
 	}
@@ -6049,6 +6870,7 @@ func (s *PlaybookRunServiceImpl) resolveAndAllocate(playbookRun *PlaybookRun, pb
 	}
 
 	sanitizedValues := s.sanitizePropertyValues(fields, initialValues, logger)
+	addComputedPropertyValues(playbookRun, fields, sanitizedValues)
 
 	// Create one formatFunc instance shared between dry-run validation and real resolution
 	// to avoid duplicate pluginAPI.User.Get calls for user-type fields.
@@ -6164,10 +6986,10 @@ func (s *PlaybookRunServiceImpl) sanitizePropertyValues(fields []PropertyField,
 	sanitized := make(map[string]json.RawMessage, len(initialValues))
 	for fieldID, rawVal := range initialValues {
 		f, ok := fieldByID[fieldID]
-		if !ok {
+		if !ok || f.IsComputed() {
 			continue
 		}
-		val, err := s.propertyService.SanitizePropertyValue(f.Type, rawVal)
+		val, err := s.propertyService.SanitizePropertyValue(f, rawVal)
 		if err != nil {
 			logger.WithError(err).WithField("field_id", fieldID).Warn("skipping invalid property value during template resolution; will be validated in CreatePlaybookRun")
 			continue
@@ -6177,6 +6999,15 @@ func (s *PlaybookRunServiceImpl) sanitizePropertyValues(fields []PropertyField,
 	return sanitized
 }
 
+// addComputedPropertyValues adds the values of computed fields, as they would be when the run is
+// created, to values so that templates can reference them.
+func addComputedPropertyValues(playbookRun *PlaybookRun, fields []PropertyField, values map[string]json.RawMessage) {
+	computed := ComputePropertyValues(fields, values, playbookRun.Checklists, playbookRun.CreateAt, 0, model.GetMillis())
+	for fieldID, value := range computed {
+		values[fieldID] = value
+	}
+}
+
 // dryRunValidateTemplate validates that all fields referenced by the channel name template have
 // non-empty values. It uses a sentinel sequential ID to avoid consuming a real run number.
 // sanitizedValues must come from sanitizePropertyValues — callers are responsible for sanitizing once.
@@ -6188,7 +7019,7 @@ func (s *PlaybookRunServiceImpl) dryRunValidateTemplate(pb *Playbook, playbookRu
 	if formatFunc == nil {
 		formatFunc = s.makeRunNameFormatFunc()
 	}
-	systemTokens := s.buildSystemTokens(playbookRun, "")
+	systemTokens := s.buildSystemTokens(playbookRun, "", pb.Title, template)
 
 	const dryRunSeqSentinel int64 = 99999
 	dryRunTokens := make(map[string]string, len(systemTokens))
@@ -6222,7 +7053,7 @@ func (s *PlaybookRunServiceImpl) resolveRunName(playbookRun *PlaybookRun, pb *Pl
 	if formatFunc == nil {
 		formatFunc = s.makeRunNameFormatFunc()
 	}
-	systemTokens := s.buildSystemTokens(playbookRun, "")
+	systemTokens := s.buildSystemTokens(playbookRun, "", pb.Title, template)
 
 	var resolvedRunName, resolvedChannelName string
 	if template != "" {
@@ -6272,14 +7103,16 @@ func (s *PlaybookRunServiceImpl) resolveRunTemplate(template string, run *Playbo
 	return ResolveTemplate(template, ResolveOptions{
 		Fields:       fields,
 		Values:       values,
-		SystemTokens: s.buildSystemTokens(run, seqID),
+		SystemTokens: s.buildSystemTokens(run, seqID, "", template),
 		FormatFunc:   s.makeRunNameFormatFunc(),
 	})
 }
 
-// buildSystemTokens returns the SEQ/OWNER/CREATOR token map for template resolution.
-// Empty userID produces an empty token; user lookup failures fall back to the raw user ID.
-func (s *PlaybookRunServiceImpl) buildSystemTokens(run *PlaybookRun, seqID string) map[string]string {
+// buildSystemTokens returns the SEQ/OWNER/CREATOR/TEAM/PLAYBOOK/DATE token map for template resolution.
+// Empty userID produces an empty token; user lookup failures fall back to the raw user ID. The team
+// and playbook are only looked up when the template uses their tokens, and the playbook only when
+// playbookTitle is empty; failures leave the tokens empty.
+func (s *PlaybookRunServiceImpl) buildSystemTokens(run *PlaybookRun, seqID, playbookTitle, template string) map[string]string {
 	ownerName := ""
 	if run.OwnerUserID != "" {
 		ownerName = s.resolveUserDisplayName(run.OwnerUserID)
@@ -6288,10 +7121,36 @@ func (s *PlaybookRunServiceImpl) buildSystemTokens(run *PlaybookRun, seqID strin
 	if run.ReporterUserID != "" {
 		creatorName = s.resolveUserDisplayName(run.ReporterUserID)
 	}
+	teamName := ""
+	if run.TeamID != "" && templateUsesToken(template, "TEAM") {
+		if team, err := s.pluginAPI.Team.Get(run.TeamID); err == nil && team != nil {
+			teamName = team.DisplayName
+		}
+	}
+	if playbookTitle == "" && run.PlaybookID != "" && templateUsesToken(template, "PLAYBOOK") {
+		if playbook, err := s.playbookService.Get(run.PlaybookID); err == nil {
+			playbookTitle = playbook.Title
+		}
+	}
+	now := time.Now()
+	end := now
+	if run.EndAt > 0 {
+		end = time.UnixMilli(run.EndAt)
+	}
+	start := now
+	if run.CreateAt > 0 {
+		start = time.UnixMilli(run.CreateAt)
+	}
+	run.ComputeTaskProgress()
 	return map[string]string{
-		"SEQ":     seqID,
-		"OWNER":   ownerName,
-		"CREATOR": creatorName,
+		"SEQ":      seqID,
+		"OWNER":    ownerName,
+		"CREATOR":  creatorName,
+		"TEAM":     teamName,
+		"PLAYBOOK": playbookTitle,
+		"DATE":     now.UTC().Format(templateDateLayout),
+		"PROGRESS": fmt.Sprintf("%d/%d", run.TaskCompleted, run.TaskTotal),
+		"ELAPSED":  formatElapsed(end.Sub(start)),
 	}
 }
 
@@ -6400,7 +7259,7 @@ func translateOptionIDs(raw json.RawMessage, optionMappings map[string]string) (
 	return raw, nil
 }
 
-// resolveMessageForRun resolves {FieldName}/{SEQ}/{OWNER}/{CREATOR} placeholders in msg for the given run.
+// resolveMessageForRun resolves {FieldName} placeholders, system tokens and sections in msg for the given run.
 func (s *PlaybookRunServiceImpl) resolveMessageForRun(msg string, run *PlaybookRun) string {
 	if msg == "" || run == nil || !strings.Contains(msg, "{") {
 		return msg
@@ -6408,6 +7267,29 @@ func (s *PlaybookRunServiceImpl) resolveMessageForRun(msg string, run *PlaybookR
 	return s.resolveAttributePlaceholders(msg, run)
 }
 
+// resolveRetrospectiveTemplate renders the unpublished retrospective copied from the playbook
+// against the finished run, so the report starts from the run's actual values. playbookRun is
+// kept in sync so later updates of the caller's copy don't restore the template.
+func (s *PlaybookRunServiceImpl) resolveRetrospectiveTemplate(playbookRun *PlaybookRun) error {
+	run, err := s.GetPlaybookRun(playbookRun.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve playbook run")
+	}
+	if run.RetrospectivePublishedAt != 0 {
+		return nil
+	}
+	resolved := s.resolveMessageForRun(run.Retrospective, run)
+	if resolved == run.Retrospective {
+		return nil
+	}
+	run.Retrospective = resolved
+	if _, err = s.store.UpdatePlaybookRun(run); err != nil {
+		return errors.Wrap(err, "failed to update retrospective")
+	}
+	playbookRun.Retrospective = resolved
+	return nil
+}
+
 // resolveAttributePlaceholders replaces {FieldName} and system tokens in msg using the run's property values and metadata.
 // Unknown tokens are left as-is. Uses run.PropertyFields/PropertyValues populated by GetPlaybookRun.
 func (s *PlaybookRunServiceImpl) resolveAttributePlaceholders(msg string, run *PlaybookRun) string {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v4"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
@@ -63,6 +64,12 @@ func (s *playbookService) Create(playbook Playbook, userID string) (string, erro
 		return "", err
 	}
 
+	playbook.RunRoles = normalizePlaybookRunRoles(playbook.RunRoles)
+	if err := ValidatePlaybookRunRoles(playbook); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return "", errors.Wrap(ErrMalformedPlaybookRun, err.Error())
+	}
+
 	playbook.CreateAt = model.GetMillis()
 	playbook.UpdateAt = playbook.CreateAt
 
@@ -70,8 +77,17 @@ func (s *playbookService) Create(playbook Playbook, userID string) (string, erro
 		return "", err
 	}
 
+	if playbook.ID == "" {
+		playbook.ID = model.NewId()
+	}
+	revision, err := s.newRevision(playbook, userID)
+	if err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return "", err
+	}
+
 	// Perform the actual operation
-	newID, err := s.store.Create(playbook)
+	newID, err := s.store.CreateWithRevision(playbook, revision)
 	if err != nil {
 		auditRec.AddErrorDesc(err.Error())
 		return "", err
@@ -125,6 +141,9 @@ func (s *playbookService) Import(data PlaybookImportData, userID string) (string
 
 	propResult := s.createPropertiesFromExport(newPlaybookID, data.Properties)
 
+	// Remapping the conditions updates the playbook, recording a revision that includes the
+	// imported properties. Otherwise the revision recorded on creation lacks them.
+	revisionRecorded := false
 	if len(data.Conditions) > 0 && len(propResult.FieldMappings) > 0 {
 		propertyMappings := &PropertyCopyResult{
 			FieldMappings:  propResult.FieldMappings,
@@ -151,6 +170,13 @@ func (s *playbookService) Import(data PlaybookImportData, userID string) (string
 				auditRec.AddErrorDesc(err.Error())
 				return "", errors.Wrap(err, "failed to update playbook with remapped condition IDs")
 			}
+			revisionRecorded = true
+		}
+	}
+	if !revisionRecorded {
+		if err := s.RecordRevision(newPlaybookID, userID); err != nil {
+			auditRec.AddErrorDesc(err.Error())
+			return "", err
 		}
 	}
 
@@ -303,6 +329,10 @@ func (s *playbookService) GetPlaybooksForTeam(requesterInfo RequesterInfo, teamI
 }
 
 func (s *playbookService) Update(playbook Playbook, userID string) error {
+	return s.UpdateIfUnmodified(playbook, userID, 0)
+}
+
+func (s *playbookService) UpdateIfUnmodified(playbook Playbook, userID string, updateAt int64) error {
 	auditRec := s.auditor.MakeAuditRecord("updatePlaybook", model.AuditStatusFail)
 	defer s.auditor.LogAuditRec(auditRec)
 
@@ -320,14 +350,26 @@ func (s *playbookService) Update(playbook Playbook, userID string) error {
 		return err
 	}
 
+	playbook.RunRoles = normalizePlaybookRunRoles(playbook.RunRoles)
+	if err := ValidatePlaybookRunRoles(playbook); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return errors.Wrap(ErrMalformedPlaybookRun, err.Error())
+	}
+
 	playbook.UpdateAt = model.GetMillis()
 
 	if err := s.checkRunNumberPrefixUnique(playbook.TeamID, playbook.RunNumberPrefix, playbook.ID); err != nil {
 		return err
 	}
 
+	revision, err := s.newRevision(playbook, userID)
+	if err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+
 	// Perform the actual operation
-	if err := s.store.Update(playbook); err != nil {
+	if err := s.store.UpdateWithRevision(playbook, updateAt, revision); err != nil {
 		auditRec.AddErrorDesc(err.Error())
 		return err
 	}
@@ -480,6 +522,9 @@ func (s *playbookService) Duplicate(playbook Playbook, userID string) (string, e
 	newPlaybook.NextRunNumber = 0
 	newPlaybook.ChannelNameTemplate = ""
 
+	// The copy is a different playbook as far as bundle imports are concerned.
+	newPlaybook.ExportKey = ""
+
 	// On duplicating, make the current user the administrator.
 	newPlaybook.Members = []PlaybookMember{{
 		UserID: userID,
@@ -601,22 +646,40 @@ func licenseAndGuestCheck(s *playbookService, userID string, isMyInsights bool)
 	return true, nil
 }
 
-// CreatePropertyField creates a property field for a playbook and bumps the playbook's updated_at
-func (s *playbookService) CreatePropertyField(playbookID string, propertyField PropertyField) (*PropertyField, error) {
-	createdField, err := s.propertyService.CreatePropertyField(playbookID, propertyField)
-	if err != nil {
-		return nil, err
+// CreatePropertyField creates a property field for a playbook and bumps the playbook's updated_at.
+// Fields with a shared field ID are linked to that property field of the playbook's team.
+func (s *playbookService) CreatePropertyField(playbookID string, propertyField PropertyField, userID string) (*PropertyField, error) {
+	var createdField *PropertyField
+	if propertyField.IsShared() {
+		playbook, err := s.store.Get(playbookID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get playbook")
+		}
+
+		createdField, err = s.propertyService.LinkTeamPropertyField(playbookID, playbook.TeamID, propertyField)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		createdField, err = s.propertyService.CreatePropertyField(playbookID, propertyField)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.store.BumpPlaybookUpdatedAt(playbookID); err != nil {
 		return nil, errors.Wrap(err, "failed to bump playbook timestamp")
 	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		return nil, err
+	}
 
 	return createdField, nil
 }
 
 // UpdatePropertyField updates a property field for a playbook and bumps the playbook's updated_at
-func (s *playbookService) UpdatePropertyField(playbookID string, propertyField PropertyField) (*PropertyField, error) {
+func (s *playbookService) UpdatePropertyField(playbookID string, propertyField PropertyField, userID string) (*PropertyField, error) {
 	updatedField, err := s.propertyService.UpdatePropertyField(playbookID, propertyField)
 	if err != nil {
 		return nil, err
@@ -625,12 +688,15 @@ func (s *playbookService) UpdatePropertyField(playbookID string, propertyField P
 	if err := s.store.BumpPlaybookUpdatedAt(playbookID); err != nil {
 		return nil, errors.Wrap(err, "failed to bump playbook timestamp")
 	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		return nil, err
+	}
 
 	return updatedField, nil
 }
 
 // DeletePropertyField deletes a property field for a playbook and bumps the playbook's updated_at
-func (s *playbookService) DeletePropertyField(playbookID, propertyID string) error {
+func (s *playbookService) DeletePropertyField(playbookID, propertyID, userID string) error {
 	if err := s.propertyService.DeletePropertyField(playbookID, propertyID); err != nil {
 		return err
 	}
@@ -638,12 +704,15 @@ func (s *playbookService) DeletePropertyField(playbookID, propertyID string) err
 	if err := s.store.BumpPlaybookUpdatedAt(playbookID); err != nil {
 		return errors.Wrap(err, "failed to bump playbook timestamp")
 	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 // ReorderPropertyFields reorders property fields for a playbook and bumps the playbook's updated_at
-func (s *playbookService) ReorderPropertyFields(playbookID, fieldID string, targetPosition int) ([]PropertyField, error) {
+func (s *playbookService) ReorderPropertyFields(playbookID, fieldID string, targetPosition int, userID string) ([]PropertyField, error) {
 	reorderedFields, err := s.propertyService.ReorderPropertyFields(playbookID, fieldID, targetPosition)
 	if err != nil {
 		return nil, err
@@ -652,10 +721,97 @@ func (s *playbookService) ReorderPropertyFields(playbookID, fieldID string, targ
 	if err := s.store.BumpPlaybookUpdatedAt(playbookID); err != nil {
 		return nil, errors.Wrap(err, "failed to bump playbook timestamp")
 	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		return nil, err
+	}
 
 	return reorderedFields, nil
 }
 
+// CreateTeamPropertyField creates a property field shared by the playbooks of a team
+func (s *playbookService) CreateTeamPropertyField(teamID string, propertyField PropertyField) (*PropertyField, error) {
+	return s.propertyService.CreateTeamPropertyField(teamID, propertyField)
+}
+
+// UpdateTeamPropertyField updates a team property field and the playbook fields linked to it,
+// bumping the updated_at of their playbooks
+func (s *playbookService) UpdateTeamPropertyField(teamID string, propertyField PropertyField, userID string) (*PropertyField, error) {
+	playbookIDs, err := s.store.GetPlaybookIDsForTeam(teamID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get team playbooks")
+	}
+
+	updatedField, updatedPlaybookIDs, err := s.propertyService.UpdateTeamPropertyField(teamID, propertyField, playbookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bumpPlaybooksWithRevision(updatedPlaybookIDs, userID); err != nil {
+		return nil, err
+	}
+
+	return updatedField, nil
+}
+
+// DeleteTeamPropertyField deletes a team property field no playbook uses anymore
+func (s *playbookService) DeleteTeamPropertyField(teamID, propertyID string) error {
+	playbookIDs, err := s.store.GetPlaybookIDsForTeam(teamID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get team playbooks")
+	}
+
+	return s.propertyService.DeleteTeamPropertyField(teamID, propertyID, playbookIDs)
+}
+
+// MergeTeamPropertyFields links the same-named property fields of the team's playbooks to a team
+// property field, creating it from them if needed
+func (s *playbookService) MergeTeamPropertyFields(teamID, name, userID string) (*SharedPropertyMergeResult, error) {
+	playbookIDs, err := s.store.GetPlaybookIDsForTeam(teamID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get team playbooks")
+	}
+
+	result, err := s.propertyService.MergePropertyFieldsIntoTeamField(teamID, name, playbookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedPlaybookIDs := make([]string, 0, len(result.LinkedFields))
+	for _, field := range result.LinkedFields {
+		updatedPlaybookIDs = append(updatedPlaybookIDs, field.TargetID)
+	}
+	if err := s.bumpPlaybooksWithRevision(updatedPlaybookIDs, userID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// bumpPlaybooksWithRevision bumps the updated_at of playbooks changed as a side effect of another
+// change and records their revisions. Every playbook is attempted; the first failure is returned.
+func (s *playbookService) bumpPlaybooksWithRevision(playbookIDs []string, userID string) error {
+	var firstErr error
+	bumped := make(map[string]bool, len(playbookIDs))
+	for _, playbookID := range playbookIDs {
+		if bumped[playbookID] {
+			continue
+		}
+		bumped[playbookID] = true
+
+		err := s.store.BumpPlaybookUpdatedAt(playbookID)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to bump timestamp of playbook %s", playbookID)
+		} else {
+			err = s.RecordRevision(playbookID, userID)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
 // checkRunNumberPrefixUnique returns ErrDuplicateEntry if prefix is already used by another
 // active playbook in teamID. Pass excludeID = "" when creating a new playbook.
 func (s *playbookService) checkRunNumberPrefixUnique(teamID, prefix, excludeID string) error {
@@ -707,6 +863,10 @@ func (s *playbookService) UpdateChannelNameTemplate(playbookID, template, userID
 		auditRec.AddErrorDesc(err.Error())
 		return err
 	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
 
 	auditRec.Success()
 	return nil
@@ -740,7 +900,336 @@ func (s *playbookService) UpdateRunNumberPrefix(playbookID, prefix, userID strin
 	if err := s.store.UpdateRunNumberPrefix(playbookID, prefix); err != nil {
 		return err
 	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
 
 	auditRec.Success()
 	return nil
 }
+
+func (s *playbookService) GraphqlUpdate(id string, setmap map[string]interface{}, userID string) error {
+	if len(setmap) == 0 {
+		return nil
+	}
+
+	auditRec := s.auditor.MakeAuditRecord("updatePlaybookFields", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", id)
+
+	if err := s.store.GraphqlUpdate(id, setmap); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+	if err := s.RecordRevision(id, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+
+	auditRec.Success()
+	return nil
+}
+
+func (s *playbookService) AddMember(playbookID, memberID, userID string) error {
+	auditRec := s.auditor.MakeAuditRecord("addPlaybookMember", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", playbookID)
+	model.AddEventParameterToAuditRec(auditRec, "memberID", memberID)
+
+	playbook, err := s.store.Get(playbookID)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range playbook.Members {
+		if member.UserID == memberID {
+			auditRec.Success()
+			return nil
+		}
+	}
+
+	if err := s.store.AddPlaybookMember(playbookID, memberID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return errors.Wrap(err, "unable to add playbook member")
+	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+
+	auditRec.Success()
+	return nil
+}
+
+func (s *playbookService) RemoveMember(playbookID, memberID, userID string) error {
+	auditRec := s.auditor.MakeAuditRecord("removePlaybookMember", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", playbookID)
+	model.AddEventParameterToAuditRec(auditRec, "memberID", memberID)
+
+	if err := s.store.RemovePlaybookMember(playbookID, memberID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return errors.Wrap(err, "unable to remove playbook member")
+	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+
+	auditRec.Success()
+	return nil
+}
+
+func (s *playbookService) GetMetric(id string) (*PlaybookMetricConfig, error) {
+	return s.store.GetMetric(id)
+}
+
+func (s *playbookService) AddMetric(playbookID string, metric PlaybookMetricConfig, userID string) (*PlaybookMetricConfig, error) {
+	auditRec := s.auditor.MakeAuditRecord("addPlaybookMetric", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "playbookID", playbookID)
+
+	metric.ID = model.NewId()
+	metric.PlaybookID = playbookID
+	if err := s.store.AddMetric(playbookID, metric); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return nil, err
+	}
+	if err := s.RecordRevision(playbookID, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return nil, err
+	}
+
+	auditRec.Success()
+	return &metric, nil
+}
+
+func (s *playbookService) UpdateMetric(id string, patch PlaybookMetricPatch, userID string) (*PlaybookMetricConfig, error) {
+	auditRec := s.auditor.MakeAuditRecord("updatePlaybookMetric", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "metricID", id)
+
+	metric, err := s.store.GetMetric(id)
+	if err != nil {
+		return nil, err
+	}
+
+	setmap := map[string]interface{}{}
+	if patch.Title != nil {
+		setmap["Title"] = *patch.Title
+		metric.Title = *patch.Title
+	}
+	if patch.Description != nil {
+		setmap["Description"] = *patch.Description
+		metric.Description = *patch.Description
+	}
+	if patch.Target != nil {
+		setmap["Target"] = null.IntFrom(*patch.Target)
+		metric.Target = null.IntFrom(*patch.Target)
+	}
+
+	if len(setmap) > 0 {
+		if err := s.store.UpdateMetric(id, setmap); err != nil {
+			auditRec.AddErrorDesc(err.Error())
+			return nil, err
+		}
+		if err := s.RecordRevision(metric.PlaybookID, userID); err != nil {
+			auditRec.AddErrorDesc(err.Error())
+			return nil, err
+		}
+	}
+
+	auditRec.Success()
+	return metric, nil
+}
+
+func (s *playbookService) DeleteMetric(id, userID string) error {
+	auditRec := s.auditor.MakeAuditRecord("deletePlaybookMetric", model.AuditStatusFail)
+	defer s.auditor.LogAuditRec(auditRec)
+	model.AddEventParameterToAuditRec(auditRec, "userID", userID)
+	model.AddEventParameterToAuditRec(auditRec, "metricID", id)
+
+	metric, err := s.store.GetMetric(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteMetric(id); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+	if err := s.RecordRevision(metric.PlaybookID, userID); err != nil {
+		auditRec.AddErrorDesc(err.Error())
+		return err
+	}
+
+	auditRec.Success()
+	return nil
+}
+
+func (s *playbookService) GetPublished(id string) (Playbook, error) {
+	playbook, err := s.store.Get(id)
+	if err != nil {
+		return Playbook{}, err
+	}
+
+	if playbook.PublishedRevision == 0 || playbook.PublishedRevision == playbook.Revision {
+		return playbook, nil
+	}
+
+	return s.playbookAtRevision(playbook, playbook.PublishedRevision)
+}
+
+func (s *playbookService) GetAtRevision(id string, revision int64) (Playbook, error) {
+	playbook, err := s.store.Get(id)
+	if err != nil {
+		return Playbook{}, err
+	}
+
+	if revision == playbook.Revision {
+		return playbook, nil
+	}
+
+	return s.playbookAtRevision(playbook, revision)
+}
+
+// playbookAtRevision rebuilds the given playbook as it was at an earlier revision.
+func (s *playbookService) playbookAtRevision(playbook Playbook, revisionNumber int64) (Playbook, error) {
+	id := playbook.ID
+	revision, err := s.store.GetPlaybookRevision(id, revisionNumber)
+	if err != nil {
+		return Playbook{}, errors.Wrapf(err, "failed to get revision %d of playbook %s", revisionNumber, id)
+	}
+
+	entry, err := revision.Entry()
+	if err != nil {
+		return Playbook{}, err
+	}
+
+	// The exported content and run settings are taken from the revision, while membership, team
+	// and access settings stay current. Metrics deleted since then have no ID to record run data
+	// against.
+	atRevision := playbook.Clone()
+	applyExportedFields(&atRevision, entry.Playbook)
+	if entry.RunSettings != nil {
+		entry.RunSettings.ApplyTo(&atRevision)
+	}
+	atRevision.Checklists = entry.Checklists
+	atRevision.Metrics = []PlaybookMetricConfig{}
+	for _, metric := range matchMetricIDs(entry.Metrics, playbook.Metrics) {
+		if metric.ID != "" {
+			metric.PlaybookID = id
+			atRevision.Metrics = append(atRevision.Metrics, metric)
+		}
+	}
+	atRevision.Revision = revisionNumber
+
+	return atRevision, nil
+}
+
+func (s *playbookService) GetPropertiesAndConditions(playbook Playbook) ([]PropertyField, []Condition, error) {
+	if playbook.Revision == 0 {
+		properties, err := s.propertyService.GetPropertyFields(playbook.ID)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get property fields for playbook %s", playbook.ID)
+		}
+
+		conditions, err := s.GetPlaybookConditionsForExport(playbook.ID)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get conditions for playbook %s", playbook.ID)
+		}
+
+		return properties, conditions, nil
+	}
+
+	revision, err := s.store.GetPlaybookRevision(playbook.ID, playbook.Revision)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get revision %d of playbook %s", playbook.Revision, playbook.ID)
+	}
+
+	entry, err := revision.Entry()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	properties := make([]PropertyField, 0, len(entry.Properties))
+	for _, exported := range entry.Properties {
+		properties = append(properties, PropertyField{
+			PropertyField: model.PropertyField{
+				ID:         exported.ID,
+				Name:       exported.Name,
+				Type:       exported.Type,
+				TargetType: PropertyTargetTypePlaybook,
+				TargetID:   playbook.ID,
+			},
+			Attrs: exported.Attrs,
+		})
+	}
+
+	conditions := make([]Condition, 0, len(entry.Conditions))
+	for _, exported := range entry.Conditions {
+		conditions = append(conditions, Condition{
+			ID:            exported.ID,
+			PlaybookID:    playbook.ID,
+			ConditionExpr: exported.ConditionExpr,
+			Version:       exported.Version,
+		})
+	}
+
+	return properties, conditions, nil
+}
+
+func (s *playbookService) RecordRevision(playbookID, userID string) error {
+	playbook, err := s.store.Get(playbookID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get playbook %s", playbookID)
+	}
+
+	return s.createRevision(playbook, userID)
+}
+
+// createRevision stores a snapshot of the given playbook together with its current property
+// fields and conditions.
+func (s *playbookService) createRevision(playbook Playbook, userID string) error {
+	revision, err := s.newRevision(playbook, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.store.CreatePlaybookRevision(revision); err != nil {
+		return errors.Wrapf(err, "failed to record revision of playbook %s", playbook.ID)
+	}
+	return nil
+}
+
+// newRevision builds, without storing it, a revision of the given playbook snapshotting its
+// current property fields and conditions.
+func (s *playbookService) newRevision(playbook Playbook, userID string) (PlaybookRevision, error) {
+	properties, err := s.propertyService.GetPropertyFields(playbook.ID)
+	if err != nil {
+		return PlaybookRevision{}, errors.Wrapf(err, "failed to get property fields for playbook %s", playbook.ID)
+	}
+
+	conditions, err := s.GetPlaybookConditionsForExport(playbook.ID)
+	if err != nil {
+		return PlaybookRevision{}, errors.Wrapf(err, "failed to get conditions for playbook %s", playbook.ID)
+	}
+
+	snapshot, err := NewPlaybookRevisionSnapshot(playbook, properties, conditions)
+	if err != nil {
+		return PlaybookRevision{}, err
+	}
+
+	return PlaybookRevision{
+		PlaybookID: playbook.ID,
+		UserID:     userID,
+		CreateAt:   model.GetMillis(),
+		Snapshot:   snapshot,
+	}, nil
+}
@@ -40,6 +40,7 @@ func TestPlaybookService_CreatePropertyField(t *testing.T) {
 	)
 
 	playbookID := "playbook123"
+	userID := "user123"
 	propertyField := app.PropertyField{
 		PropertyField: model.PropertyField{
 			Name: "Test Field",
@@ -63,8 +64,9 @@ func TestPlaybookService_CreatePropertyField(t *testing.T) {
 		mockStore.EXPECT().
 			BumpPlaybookUpdatedAt(playbookID).
 			Return(nil)
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, playbookID)
 
-		result, err := service.CreatePropertyField(playbookID, propertyField)
+		result, err := service.CreatePropertyField(playbookID, propertyField, userID)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedField, result)
@@ -77,7 +79,7 @@ func TestPlaybookService_CreatePropertyField(t *testing.T) {
 			CreatePropertyField(playbookID, propertyField).
 			Return(nil, expectedError)
 
-		result, err := service.CreatePropertyField(playbookID, propertyField)
+		result, err := service.CreatePropertyField(playbookID, propertyField, userID)
 
 		require.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -102,7 +104,7 @@ func TestPlaybookService_CreatePropertyField(t *testing.T) {
 			BumpPlaybookUpdatedAt(playbookID).
 			Return(bumpError)
 
-		result, err := service.CreatePropertyField(playbookID, propertyField)
+		result, err := service.CreatePropertyField(playbookID, propertyField, userID)
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to bump playbook timestamp")
@@ -130,6 +132,7 @@ func TestPlaybookService_UpdatePropertyField(t *testing.T) {
 	)
 
 	playbookID := "playbook123"
+	userID := "user123"
 	propertyField := app.PropertyField{
 		PropertyField: model.PropertyField{
 			ID:   "prop123",
@@ -154,8 +157,9 @@ func TestPlaybookService_UpdatePropertyField(t *testing.T) {
 		mockStore.EXPECT().
 			BumpPlaybookUpdatedAt(playbookID).
 			Return(nil)
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, playbookID)
 
-		result, err := service.UpdatePropertyField(playbookID, propertyField)
+		result, err := service.UpdatePropertyField(playbookID, propertyField, userID)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedField, result)
@@ -168,7 +172,7 @@ func TestPlaybookService_UpdatePropertyField(t *testing.T) {
 			UpdatePropertyField(playbookID, propertyField).
 			Return(nil, expectedError)
 
-		result, err := service.UpdatePropertyField(playbookID, propertyField)
+		result, err := service.UpdatePropertyField(playbookID, propertyField, userID)
 
 		require.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -193,7 +197,7 @@ func TestPlaybookService_UpdatePropertyField(t *testing.T) {
 			BumpPlaybookUpdatedAt(playbookID).
 			Return(bumpError)
 
-		result, err := service.UpdatePropertyField(playbookID, propertyField)
+		result, err := service.UpdatePropertyField(playbookID, propertyField, userID)
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to bump playbook timestamp")
@@ -221,6 +225,7 @@ func TestPlaybookService_DeletePropertyField(t *testing.T) {
 	)
 
 	playbookID := "playbook123"
+	userID := "user123"
 	propertyID := "prop123"
 
 	t.Run("success - property field deleted and playbook updated", func(t *testing.T) {
@@ -231,8 +236,9 @@ func TestPlaybookService_DeletePropertyField(t *testing.T) {
 		mockStore.EXPECT().
 			BumpPlaybookUpdatedAt(playbookID).
 			Return(nil)
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, playbookID)
 
-		err := service.DeletePropertyField(playbookID, propertyID)
+		err := service.DeletePropertyField(playbookID, propertyID, userID)
 
 		require.NoError(t, err)
 	})
@@ -244,7 +250,7 @@ func TestPlaybookService_DeletePropertyField(t *testing.T) {
 			DeletePropertyField(playbookID, propertyID).
 			Return(expectedError)
 
-		err := service.DeletePropertyField(playbookID, propertyID)
+		err := service.DeletePropertyField(playbookID, propertyID, userID)
 
 		require.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -261,11 +267,30 @@ func TestPlaybookService_DeletePropertyField(t *testing.T) {
 			BumpPlaybookUpdatedAt(playbookID).
 			Return(bumpError)
 
-		err := service.DeletePropertyField(playbookID, propertyID)
+		err := service.DeletePropertyField(playbookID, propertyID, userID)
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to bump playbook timestamp")
 	})
+	t.Run("revision failure fails the deletion", func(t *testing.T) {
+		mockPropertyService.EXPECT().
+			DeletePropertyField(playbookID, propertyID).
+			Return(nil)
+
+		mockStore.EXPECT().
+			BumpPlaybookUpdatedAt(playbookID).
+			Return(nil)
+		mockStore.EXPECT().Get(playbookID).Return(app.Playbook{ID: playbookID}, nil)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, playbookID)
+		mockStore.EXPECT().
+			CreatePlaybookRevision(gomock.Any()).
+			Return(app.PlaybookRevision{}, errors.New("revision insert failed"))
+
+		err := service.DeletePropertyField(playbookID, propertyID, userID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "revision insert failed")
+	})
 }
 
 func TestPlaybookService_Duplicate(t *testing.T) {
@@ -320,8 +345,8 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				assert.Equal(t, "Copy of Original Playbook", pb.Title)
 				assert.Equal(t, teamID, pb.TeamID)
 				assert.Len(t, pb.Members, 1)
@@ -332,6 +357,7 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), teamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		mockPropertyService.EXPECT().
 			CopyPlaybookPropertiesToPlaybook(originalPlaybookID, gomock.Any()).
@@ -359,8 +385,9 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 
 		// Mock Update for saving condition IDs
 		mockStore.EXPECT().
-			Update(gomock.Any()).
+			UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).
 			Return(nil)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		resultID, err := service.Duplicate(originalPlaybook, userID)
 
@@ -370,13 +397,14 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 
 	t.Run("duplicates playbook even if property copying fails", func(t *testing.T) {
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				return model.NewId(), nil
 			})
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), teamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		mockPropertyService.EXPECT().
 			CopyPlaybookPropertiesToPlaybook(originalPlaybookID, gomock.Any()).
@@ -395,13 +423,14 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				return model.NewId(), nil
 			})
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), teamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		mockPropertyService.EXPECT().
 			CopyPlaybookPropertiesToPlaybook(originalPlaybookID, gomock.Any()).
@@ -420,8 +449,9 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 	t.Run("fails if playbook creation fails", func(t *testing.T) {
 		expectedError := errors.New("database error")
 
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 		mockStore.EXPECT().
-			Create(gomock.Any()).
+			CreateWithRevision(gomock.Any(), gomock.Any()).
 			Return("", expectedError)
 
 		resultID, err := service.Duplicate(originalPlaybook, userID)
@@ -433,13 +463,14 @@ func TestPlaybookService_Duplicate(t *testing.T) {
 
 	t.Run("does not copy conditions if property copying fails", func(t *testing.T) {
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				return model.NewId(), nil
 			})
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), teamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		mockPropertyService.EXPECT().
 			CopyPlaybookPropertiesToPlaybook(originalPlaybookID, gomock.Any()).
@@ -570,8 +601,8 @@ func TestPlaybookService_Import(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				assert.Empty(t, pb.Checklists[0].Items[0].ConditionID,
 					"ConditionID should be cleared before Create")
 				assert.Empty(t, pb.Checklists[0].Items[0].ConditionAction,
@@ -579,6 +610,7 @@ func TestPlaybookService_Import(t *testing.T) {
 				return newPlaybookID, nil
 			})
 		mockPoster.EXPECT().PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), gomock.Any())
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		createdField := &app.PropertyField{
 			PropertyField: model.PropertyField{ID: newFieldID, Name: "Status", Type: model.PropertyFieldTypeSelect},
@@ -635,14 +667,15 @@ func TestPlaybookService_Import(t *testing.T) {
 		mockStore.EXPECT().Get(newPlaybookID).Return(storedPlaybook, nil)
 
 		mockStore.EXPECT().
-			Update(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) error {
+			UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ int64, _ app.PlaybookRevision) error {
 				assert.Equal(t, newConditionID, pb.Checklists[0].Items[0].ConditionID,
 					"checklist item ConditionID should be remapped to new condition ID")
 				assert.Equal(t, app.ConditionActionHidden, pb.Checklists[0].Items[0].ConditionAction,
 					"checklist item ConditionAction should be restored after remap")
 				return nil
 			})
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		resultID, err := service.Import(app.PlaybookImportData{
 			Playbook:   playbook,
@@ -683,13 +716,14 @@ func TestPlaybookService_Import(t *testing.T) {
 		)
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				return newPlaybookID, nil
 			})
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), basePlaybook.TeamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		resultID, err := service.Import(app.PlaybookImportData{
 			Playbook:   basePlaybook,
@@ -731,13 +765,15 @@ func TestPlaybookService_Import(t *testing.T) {
 		)
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(pb app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(pb app.Playbook, _ app.PlaybookRevision) (string, error) {
 				return newPlaybookID, nil
 			})
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), basePlaybook.TeamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, newPlaybookID)
 
 		mockPropertyService.EXPECT().
 			CreatePropertyField(newPlaybookID, gomock.Any()).
@@ -783,11 +819,13 @@ func TestPlaybookService_Import(t *testing.T) {
 		)
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
+			CreateWithRevision(gomock.Any(), gomock.Any()).
 			Return(newPlaybookID, nil)
 
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), gomock.Any())
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, newPlaybookID)
 
 		mockPropertyService.EXPECT().
 			CreatePropertyField(newPlaybookID, gomock.Any()).
@@ -848,12 +886,13 @@ func TestPlaybookService_Import(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
-			DoAndReturn(func(created app.Playbook) (string, error) {
+			CreateWithRevision(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(created app.Playbook, _ app.PlaybookRevision) (string, error) {
 				require.Empty(t, created.Metrics[0].ID, "import must strip metric IDs so Create only inserts new metrics")
 				return newPlaybookID, nil
 			})
 		mockPoster.EXPECT().PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), basePlaybook.TeamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		resultID, err := service.Import(app.PlaybookImportData{Playbook: pb}, userID)
 		require.NoError(t, err)
@@ -866,6 +905,8 @@ func TestPlaybookService_ValidateNewChannelOnlyMode(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStore := mock_app.NewMockPlaybookStore(ctrl)
+	mockPropertyService := mock_app.NewMockPropertyService(ctrl)
+	mockConditionService := mock_app.NewMockConditionService(ctrl)
 	mockPoster := mock_bot.NewMockPoster(ctrl)
 	mockAuditor := mock_app.NewMockAuditor(ctrl)
 
@@ -884,8 +925,8 @@ func TestPlaybookService_ValidateNewChannelOnlyMode(t *testing.T) {
 		nil,
 		mockAuditor,
 		nil, // metrics
-		nil,
-		nil,
+		mockPropertyService,
+		mockConditionService,
 	)
 
 	userID := model.NewId()
@@ -914,10 +955,11 @@ func TestPlaybookService_ValidateNewChannelOnlyMode(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
+			CreateWithRevision(gomock.Any(), gomock.Any()).
 			Return(newPlaybookID, nil)
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), teamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		id, err := service.Create(playbook, userID)
 		require.NoError(t, err)
@@ -949,8 +991,9 @@ func TestPlaybookService_ValidateNewChannelOnlyMode(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Update(gomock.Any()).
+			UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).
 			Return(nil)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		err := service.Update(playbook, userID)
 		require.NoError(t, err)
@@ -979,10 +1022,11 @@ func TestPlaybookService_ValidateNewChannelOnlyMode(t *testing.T) {
 		}
 
 		mockStore.EXPECT().
-			Create(gomock.Any()).
+			CreateWithRevision(gomock.Any(), gomock.Any()).
 			Return(newPlaybookID, nil)
 		mockPoster.EXPECT().
 			PublishWebsocketEventToTeam(gomock.Any(), gomock.Any(), teamID)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, gomock.Any())
 
 		id, err := service.Import(app.PlaybookImportData{Playbook: playbook}, userID)
 		require.NoError(t, err)
@@ -5,6 +5,7 @@ package app
 
 import (
 	"encoding/json"
+	"math"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
@@ -16,6 +17,16 @@ const (
 	PropertyAttrsVisibility = "visibility"
 	PropertyAttrsParentID   = "parent_id"
 	PropertyAttrsValueType  = "value_type"
+	PropertyAttrsMin        = "min"
+	PropertyAttrsMax        = "max"
+	PropertyAttrsInteger    = "integer"
+
+	PropertyAttrsRequiredOnCreate = "required_on_create"
+	PropertyAttrsRequiredOnFinish = "required_on_finish"
+
+	PropertyAttrsComputed = "computed"
+
+	PropertyAttrsSharedFieldID = "shared_field_id"
 
 	// Visibility
 	PropertyFieldVisibilityHidden  = "hidden"
@@ -26,9 +37,17 @@ const (
 	PropertyOptionNameMaxLength  = 128
 	PropertyOptionColorMaxLength = 128
 
+	// Value types refine how the values of a field are validated and displayed. Number, URL and
+	// email apply to text fields, datetime to date fields.
+	PropertyValueTypeURL      = "url"
+	PropertyValueTypeEmail    = "email"
+	PropertyValueTypeNumber   = "number"
+	PropertyValueTypeDateTime = "datetime"
+
 	// Target types
 	PropertyTargetTypePlaybook = "playbook"
 	PropertyTargetTypeRun      = "run"
+	PropertyTargetTypeTeam     = "team"
 )
 
 type PropertyCopyResult struct {
@@ -43,6 +62,23 @@ type Attrs struct {
 	Options    model.PropertyOptions[*model.PluginPropertyOption] `json:"options"`
 	ParentID   string                                             `json:"parent_id"`
 	ValueType  string                                             `json:"value_type"`
+
+	// Min, Max and Integer constrain the values of number fields.
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Integer bool     `json:"integer,omitempty"`
+
+	// RequiredOnCreate and RequiredOnFinish make a value mandatory when a run is started or
+	// finished, respectively.
+	RequiredOnCreate bool `json:"required_on_create,omitempty"`
+	RequiredOnFinish bool `json:"required_on_finish,omitempty"`
+
+	// Computed makes the field read-only, its value being derived from other data of the run.
+	Computed *ComputedAttrs `json:"computed,omitempty"`
+
+	// SharedFieldID links a playbook field, and the run fields copied from it, to the team field
+	// that defines it.
+	SharedFieldID string `json:"shared_field_id,omitempty"`
 }
 
 func PropertySortOrder(p *model.PropertyField) int {
@@ -80,6 +116,28 @@ func (p *PropertyField) SupportsOptions() bool {
 	}
 }
 
+// IsNumber returns true for text fields holding numeric values.
+func (p *PropertyField) IsNumber() bool {
+	return p.Type == model.PropertyFieldTypeText && p.Attrs.ValueType == PropertyValueTypeNumber
+}
+
+// IsDateTime returns true for date fields that keep the time of day.
+func (p *PropertyField) IsDateTime() bool {
+	return p.Type == model.PropertyFieldTypeDate && p.Attrs.ValueType == PropertyValueTypeDateTime
+}
+
+// isValidValueType reports whether valueType applies to fields of the given type.
+func isValidValueType(fieldType model.PropertyFieldType, valueType string) bool {
+	switch fieldType {
+	case model.PropertyFieldTypeText:
+		return valueType == PropertyValueTypeURL || valueType == PropertyValueTypeEmail || valueType == PropertyValueTypeNumber
+	case model.PropertyFieldTypeDate:
+		return valueType == PropertyValueTypeDateTime
+	default:
+		return false
+	}
+}
+
 func (p *PropertyField) SanitizeAndValidate() error {
 	// first we clean unused attributes depending on the field type
 	if !p.SupportsOptions() {
@@ -126,10 +184,44 @@ func (p *PropertyField) SanitizeAndValidate() error {
 	}
 	p.Attrs.Visibility = visibility
 
-	if p.Attrs.ValueType != "" && p.Attrs.ValueType != "url" {
+	if p.Attrs.Computed != nil {
+		if p.Type != model.PropertyFieldTypeText {
+			return errors.New("computed fields must be text fields")
+		}
+		if err := p.Attrs.Computed.sanitizeAndValidate(); err != nil {
+			return err
+		}
+
+		// Computed values are always numbers and cannot be entered by users.
+		p.Attrs.ValueType = PropertyValueTypeNumber
+		p.Attrs.Min = nil
+		p.Attrs.Max = nil
+		p.Attrs.Integer = false
+		p.Attrs.RequiredOnCreate = false
+		p.Attrs.RequiredOnFinish = false
+		return nil
+	}
+
+	if p.Attrs.ValueType != "" && !isValidValueType(p.Type, p.Attrs.ValueType) {
 		p.Attrs.ValueType = ""
 	}
 
+	if !p.IsNumber() {
+		p.Attrs.Min = nil
+		p.Attrs.Max = nil
+		p.Attrs.Integer = false
+		return nil
+	}
+
+	for _, bound := range []*float64{p.Attrs.Min, p.Attrs.Max} {
+		if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+			return errors.New("number field bounds must be finite")
+		}
+	}
+	if p.Attrs.Min != nil && p.Attrs.Max != nil && *p.Attrs.Min > *p.Attrs.Max {
+		return errors.New("number field min must not be greater than max")
+	}
+
 	return nil
 }
 
@@ -143,6 +235,27 @@ func (p *PropertyField) ToMattermostPropertyField() *model.PropertyField {
 		PropertyAttrsParentID:               p.Attrs.ParentID,
 		PropertyAttrsValueType:              p.Attrs.ValueType,
 	}
+	if p.Attrs.Min != nil {
+		mmpf.Attrs[PropertyAttrsMin] = *p.Attrs.Min
+	}
+	if p.Attrs.Max != nil {
+		mmpf.Attrs[PropertyAttrsMax] = *p.Attrs.Max
+	}
+	if p.Attrs.Integer {
+		mmpf.Attrs[PropertyAttrsInteger] = true
+	}
+	if p.Attrs.RequiredOnCreate {
+		mmpf.Attrs[PropertyAttrsRequiredOnCreate] = true
+	}
+	if p.Attrs.RequiredOnFinish {
+		mmpf.Attrs[PropertyAttrsRequiredOnFinish] = true
+	}
+	if p.Attrs.Computed != nil {
+		mmpf.Attrs[PropertyAttrsComputed] = p.Attrs.Computed
+	}
+	if p.Attrs.SharedFieldID != "" {
+		mmpf.Attrs[PropertyAttrsSharedFieldID] = p.Attrs.SharedFieldID
+	}
 	return &mmpf
 }
 
@@ -189,6 +302,9 @@ type PropertyServiceReader interface {
 	// GetRunPropertyValuesSince gets all property values for a run since a given timestamp
 	// updatedSince: optional timestamp in milliseconds - only return values updated after this time (0 = all)
 	GetRunPropertyValuesSince(runID string, updatedSince int64) ([]PropertyValue, error)
+
+	// GetTeamPropertyFields gets all property fields shared by the playbooks of a team
+	GetTeamPropertyFields(teamID string) ([]PropertyField, error)
 }
 
 type PropertyService interface {
@@ -205,11 +321,22 @@ type PropertyService interface {
 	UpdatePropertyField(playbookID string, propertyField PropertyField) (*PropertyField, error)
 	DeletePropertyField(playbookID string, propertyID string) error
 	ReorderPropertyFields(playbookID, fieldID string, targetPosition int) ([]PropertyField, error)
-	CopyPlaybookPropertiesToRun(playbookID, runID string) (*PropertyCopyResult, error)
+	CopyPlaybookPropertiesToRun(playbookID, runID string, playbookProperties []PropertyField) (*PropertyCopyResult, error)
 	CopyPlaybookPropertiesToPlaybook(sourcePlaybookID, targetPlaybookID string) (*PropertyCopyResult, error)
 	UpsertRunPropertyValue(runID, propertyFieldID string, value json.RawMessage) (*PropertyValue, error)
 	UpsertRunPropertyValueWithField(runID string, field *PropertyField, value json.RawMessage) (*PropertyValue, error)
-	SanitizePropertyValue(fieldType model.PropertyFieldType, raw json.RawMessage) (json.RawMessage, error)
+	RecomputeRunPropertyValues(run *PlaybookRun) ([]PropertyValue, error)
+	SanitizePropertyValue(field *PropertyField, raw json.RawMessage) (json.RawMessage, error)
+	ValidatePropertyValue(field *PropertyField, raw json.RawMessage) (json.RawMessage, error)
+
+	// Team fields shared by the playbooks of a team. playbookIDs are the team's playbooks, whose
+	// fields linked to the team field are kept in sync with it.
+	CreateTeamPropertyField(teamID string, propertyField PropertyField) (*PropertyField, error)
+	GetTeamPropertyFields(teamID string) ([]PropertyField, error)
+	UpdateTeamPropertyField(teamID string, propertyField PropertyField, playbookIDs []string) (*PropertyField, []string, error)
+	DeleteTeamPropertyField(teamID, propertyID string, playbookIDs []string) error
+	LinkTeamPropertyField(playbookID, teamID string, propertyField PropertyField) (*PropertyField, error)
+	MergePropertyFieldsIntoTeamField(teamID, name string, playbookIDs []string) (*SharedPropertyMergeResult, error)
 
 	// Bulk methods for retrieving properties for multiple runs
 	GetRunsPropertyFields(runIDs []string) (map[string][]PropertyField, error)
@@ -242,6 +242,65 @@ func TestPropertyField_SanitizeAndValidate(t *testing.T) {
 		require.Equal(t, "", pf.Attrs.ValueType)
 	})
 
+	t.Run("keeps value types that match the field type", func(t *testing.T) {
+		for _, tc := range []struct {
+			fieldType model.PropertyFieldType
+			valueType string
+		}{
+			{model.PropertyFieldTypeText, PropertyValueTypeNumber},
+			{model.PropertyFieldTypeText, PropertyValueTypeEmail},
+			{model.PropertyFieldTypeDate, PropertyValueTypeDateTime},
+		} {
+			pf := &PropertyField{
+				PropertyField: model.PropertyField{Type: tc.fieldType},
+				Attrs:         Attrs{ValueType: tc.valueType},
+			}
+			require.NoError(t, pf.SanitizeAndValidate())
+			require.Equal(t, tc.valueType, pf.Attrs.ValueType)
+		}
+	})
+
+	t.Run("clears value types that do not match the field type", func(t *testing.T) {
+		pf := &PropertyField{
+			PropertyField: model.PropertyField{Type: model.PropertyFieldTypeDate},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+		}
+		require.NoError(t, pf.SanitizeAndValidate())
+		require.Equal(t, "", pf.Attrs.ValueType)
+	})
+
+	t.Run("keeps number constraints on number fields", func(t *testing.T) {
+		minValue, maxValue := 0.0, 100.0
+		pf := &PropertyField{
+			PropertyField: model.PropertyField{Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Min: &minValue, Max: &maxValue, Integer: true},
+		}
+		require.NoError(t, pf.SanitizeAndValidate())
+		require.Equal(t, 0.0, *pf.Attrs.Min)
+		require.Equal(t, 100.0, *pf.Attrs.Max)
+		require.True(t, pf.Attrs.Integer)
+	})
+
+	t.Run("clears number constraints on other fields", func(t *testing.T) {
+		minValue := 1.0
+		pf := &PropertyField{
+			PropertyField: model.PropertyField{Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{Min: &minValue, Integer: true},
+		}
+		require.NoError(t, pf.SanitizeAndValidate())
+		require.Nil(t, pf.Attrs.Min)
+		require.False(t, pf.Attrs.Integer)
+	})
+
+	t.Run("rejects min greater than max", func(t *testing.T) {
+		minValue, maxValue := 10.0, 1.0
+		pf := &PropertyField{
+			PropertyField: model.PropertyField{Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Min: &minValue, Max: &maxValue},
+		}
+		require.Error(t, pf.SanitizeAndValidate())
+	})
+
 	t.Run("converts invalid value_type to empty string", func(t *testing.T) {
 		pf := &PropertyField{
 			PropertyField: model.PropertyField{
@@ -310,6 +369,22 @@ func TestPropertyField_ToMattermostPropertyField(t *testing.T) {
 	require.Equal(t, "Option 2", options[1].GetName())
 }
 
+func TestPropertyField_ToMattermostPropertyField_RequiredFlags(t *testing.T) {
+	pf := &PropertyField{
+		PropertyField: model.PropertyField{Name: "Root cause", Type: model.PropertyFieldTypeText},
+		Attrs:         Attrs{RequiredOnFinish: true},
+	}
+
+	result := pf.ToMattermostPropertyField()
+	require.Equal(t, true, result.Attrs[PropertyAttrsRequiredOnFinish])
+	require.NotContains(t, result.Attrs, PropertyAttrsRequiredOnCreate)
+
+	roundTripped, err := NewPropertyFieldFromMattermostPropertyField(result)
+	require.NoError(t, err)
+	require.True(t, roundTripped.Attrs.RequiredOnFinish)
+	require.False(t, roundTripped.Attrs.RequiredOnCreate)
+}
+
 func TestNewPropertyFieldFromMattermostPropertyField(t *testing.T) {
 	optionID1 := model.NewId()
 	optionID2 := model.NewId()
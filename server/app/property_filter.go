@@ -0,0 +1,218 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+)
+
+// PropertyFilterOperator is the comparison made by a PropertyFilterComparison.
+type PropertyFilterOperator string
+
+const (
+	PropertyFilterIs          PropertyFilterOperator = "is"
+	PropertyFilterIsNot       PropertyFilterOperator = "isNot"
+	PropertyFilterGreaterThan PropertyFilterOperator = "gt"
+	PropertyFilterLessThan    PropertyFilterOperator = "lt"
+)
+
+// PropertyFilter is a property filter expression resolved against the definitions of the fields
+// it references, so the store can match it against the fields the runs copied from them.
+type PropertyFilter struct {
+	And        []PropertyFilter
+	Or         []PropertyFilter
+	Comparison *PropertyFilterComparison
+}
+
+// PropertyFilterComparison compares the value of a field of the runs. Field is the playbook or
+// team field the runs' fields were copied from.
+type PropertyFilterComparison struct {
+	Operator PropertyFilterOperator
+	Field    PropertyField
+
+	// Text is compared case-insensitively to the values of text fields.
+	Text string
+
+	// Number is compared to the values of number fields, and of date fields in milliseconds.
+	Number float64
+
+	// OptionNames are compared case-insensitively to the names of the options selected in runs,
+	// since runs get their own copy of the options of their playbook.
+	OptionNames []string
+}
+
+// ResolvePropertyFilter validates the filter expression against the fields it references and
+// resolves it for the store. Select and multiselect values may be given as option IDs or names.
+func ResolvePropertyFilter(expr ConditionExprV1, fields []PropertyField) (*PropertyFilter, error) {
+	fieldMap := make(map[string]PropertyField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.ID] = field
+	}
+
+	expr.Sanitize()
+	if err := resolveFilterOptionNames(&expr, fieldMap); err != nil {
+		return nil, err
+	}
+	if err := expr.Validate(fields); err != nil {
+		return nil, errors.Wrap(ErrMalformedCondition, err.Error())
+	}
+
+	return resolvePropertyFilter(expr, fieldMap)
+}
+
+// ValidatePropertySortField checks that runs can be sorted by the values of the field.
+func ValidatePropertySortField(field PropertyField) error {
+	if err := validateFilterField(field); err != nil {
+		return err
+	}
+
+	switch field.Type {
+	case model.PropertyFieldTypeText, model.PropertyFieldTypeSelect, model.PropertyFieldTypeMultiselect, model.PropertyFieldTypeDate:
+		return nil
+	default:
+		return errors.Wrapf(ErrMalformedCondition, "cannot sort by %s property field '%s'", field.Type, field.Name)
+	}
+}
+
+// validateFilterField checks that the field holds definitions runs copy: playbook and team fields.
+// Computed fields are left out, their values not being stored.
+func validateFilterField(field PropertyField) error {
+	if field.TargetType != PropertyTargetTypePlaybook && field.TargetType != PropertyTargetTypeTeam {
+		return errors.Wrapf(ErrMalformedCondition, "property field %s is not a playbook or team property field", field.ID)
+	}
+	if field.IsComputed() {
+		return errors.Wrapf(ErrMalformedCondition, "computed property field '%s' cannot be filtered or sorted on", field.Name)
+	}
+	return nil
+}
+
+func resolvePropertyFilter(expr ConditionExprV1, fieldMap map[string]PropertyField) (*PropertyFilter, error) {
+	filter := &PropertyFilter{}
+
+	switch {
+	case expr.And != nil:
+		for _, nested := range expr.And {
+			resolved, err := resolvePropertyFilter(nested, fieldMap)
+			if err != nil {
+				return nil, err
+			}
+			filter.And = append(filter.And, *resolved)
+		}
+	case expr.Or != nil:
+		for _, nested := range expr.Or {
+			resolved, err := resolvePropertyFilter(nested, fieldMap)
+			if err != nil {
+				return nil, err
+			}
+			filter.Or = append(filter.Or, *resolved)
+		}
+	case expr.Is != nil:
+		return resolveFilterComparison(PropertyFilterIs, expr.Is, fieldMap)
+	case expr.IsNot != nil:
+		return resolveFilterComparison(PropertyFilterIsNot, expr.IsNot, fieldMap)
+	case expr.GreaterThan != nil:
+		return resolveFilterComparison(PropertyFilterGreaterThan, expr.GreaterThan, fieldMap)
+	case expr.LessThan != nil:
+		return resolveFilterComparison(PropertyFilterLessThan, expr.LessThan, fieldMap)
+	}
+
+	return filter, nil
+}
+
+func resolveFilterComparison(operator PropertyFilterOperator, cc *ComparisonCondition, fieldMap map[string]PropertyField) (*PropertyFilter, error) {
+	field, ok := fieldMap[cc.FieldID]
+	if !ok {
+		return nil, errors.Wrapf(ErrMalformedCondition, "unknown property field %s", cc.FieldID)
+	}
+	if err := validateFilterField(field); err != nil {
+		return nil, err
+	}
+
+	comparison := &PropertyFilterComparison{Operator: operator, Field: field}
+
+	switch {
+	case operator == PropertyFilterGreaterThan || operator == PropertyFilterLessThan || field.IsNumber():
+		number, ok := orderedValue(field, cc.Value)
+		if !ok {
+			return nil, errors.Wrapf(ErrMalformedCondition, "invalid value for property field '%s'", field.Name)
+		}
+		comparison.Number = number
+
+	case field.Type == model.PropertyFieldTypeText:
+		if err := json.Unmarshal(cc.Value, &comparison.Text); err != nil {
+			return nil, errors.Wrapf(ErrMalformedCondition, "invalid value for property field '%s'", field.Name)
+		}
+
+	default:
+		var optionIDs []string
+		if err := json.Unmarshal(cc.Value, &optionIDs); err != nil {
+			return nil, errors.Wrapf(ErrMalformedCondition, "invalid value for property field '%s'", field.Name)
+		}
+		for _, option := range field.Attrs.Options {
+			for _, optionID := range optionIDs {
+				if option.GetID() == optionID {
+					comparison.OptionNames = append(comparison.OptionNames, option.GetName())
+					break
+				}
+			}
+		}
+	}
+
+	return &PropertyFilter{Comparison: comparison}, nil
+}
+
+// resolveFilterOptionNames replaces the option names used as select and multiselect values by
+// the IDs of these options, as conditions expect.
+func resolveFilterOptionNames(expr *ConditionExprV1, fieldMap map[string]PropertyField) error {
+	for i := range expr.And {
+		if err := resolveFilterOptionNames(&expr.And[i], fieldMap); err != nil {
+			return err
+		}
+	}
+	for i := range expr.Or {
+		if err := resolveFilterOptionNames(&expr.Or[i], fieldMap); err != nil {
+			return err
+		}
+	}
+
+	for _, cc := range []*ComparisonCondition{expr.Is, expr.IsNot} {
+		if cc == nil {
+			continue
+		}
+		field, ok := fieldMap[cc.FieldID]
+		if !ok || (field.Type != model.PropertyFieldTypeSelect && field.Type != model.PropertyFieldTypeMultiselect) {
+			continue
+		}
+
+		var values []string
+		if err := json.Unmarshal(cc.Value, &values); err != nil {
+			// Left for validation to report.
+			continue
+		}
+		for j, value := range values {
+			if option := findOptionByName(field.Attrs.Options, value); option != nil && !hasOptionID(field, value) {
+				values[j] = option.GetID()
+			}
+		}
+		resolved, err := json.Marshal(values)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode option IDs")
+		}
+		cc.Value = resolved
+	}
+
+	return nil
+}
+
+func hasOptionID(field PropertyField, optionID string) bool {
+	for _, option := range field.Attrs.Options {
+		if option.GetID() == optionID {
+			return true
+		}
+	}
+	return false
+}
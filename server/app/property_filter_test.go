@@ -0,0 +1,100 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePropertyFilter(t *testing.T) {
+	severity := PropertyField{
+		PropertyField: model.PropertyField{ID: "severity", Name: "Severity", Type: model.PropertyFieldTypeSelect, TargetType: PropertyTargetTypePlaybook},
+		Attrs:         Attrs{Options: sharedTestOptions("sev1", "SEV1", "sev2", "SEV2", "sev3", "SEV3")},
+	}
+	service := PropertyField{
+		PropertyField: model.PropertyField{ID: "service", Name: "Service", Type: model.PropertyFieldTypeText, TargetType: PropertyTargetTypeTeam},
+	}
+	impact := PropertyField{
+		PropertyField: model.PropertyField{ID: "impact", Name: "Users affected", Type: model.PropertyFieldTypeText, TargetType: PropertyTargetTypePlaybook},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+	}
+	score := PropertyField{
+		PropertyField: model.PropertyField{ID: "score", Name: "Score", Type: model.PropertyFieldTypeText, TargetType: PropertyTargetTypePlaybook},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Computed: &ComputedAttrs{Kind: ComputedKindFormula, Expression: "{Users affected} * 2"}},
+	}
+	runField := PropertyField{
+		PropertyField: model.PropertyField{ID: "run_field", Name: "Notes", Type: model.PropertyFieldTypeText, TargetType: PropertyTargetTypeRun},
+	}
+	fields := []PropertyField{severity, service, impact, score, runField}
+
+	comparison := func(fieldID, value string) *ComparisonCondition {
+		return &ComparisonCondition{FieldID: fieldID, Value: json.RawMessage(value)}
+	}
+
+	t.Run("option names and IDs", func(t *testing.T) {
+		filter, err := ResolvePropertyFilter(ConditionExprV1{And: []ConditionExprV1{
+			{Is: comparison("severity", `["SEV1", "sev2"]`)},
+			{Is: comparison("service", `"payments"`)},
+		}}, fields)
+		require.NoError(t, err)
+
+		require.Len(t, filter.And, 2)
+		require.NotNil(t, filter.And[0].Comparison)
+		assert.Equal(t, PropertyFilterIs, filter.And[0].Comparison.Operator)
+		assert.Equal(t, []string{"SEV1", "SEV2"}, filter.And[0].Comparison.OptionNames)
+		require.NotNil(t, filter.And[1].Comparison)
+		assert.Equal(t, "payments", filter.And[1].Comparison.Text)
+		assert.Equal(t, "service", filter.And[1].Comparison.Field.ID)
+	})
+
+	t.Run("number comparisons", func(t *testing.T) {
+		filter, err := ResolvePropertyFilter(ConditionExprV1{Or: []ConditionExprV1{
+			{GreaterThan: comparison("impact", `100`)},
+			{IsNot: comparison("impact", `5`)},
+		}}, fields)
+		require.NoError(t, err)
+
+		require.Len(t, filter.Or, 2)
+		assert.Equal(t, PropertyFilterGreaterThan, filter.Or[0].Comparison.Operator)
+		assert.Equal(t, float64(100), filter.Or[0].Comparison.Number)
+		assert.Equal(t, PropertyFilterIsNot, filter.Or[1].Comparison.Operator)
+		assert.Equal(t, float64(5), filter.Or[1].Comparison.Number)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := ResolvePropertyFilter(ConditionExprV1{Is: comparison("unknown", `"x"`)}, fields)
+		assert.ErrorIs(t, err, ErrMalformedCondition)
+	})
+
+	t.Run("computed field", func(t *testing.T) {
+		_, err := ResolvePropertyFilter(ConditionExprV1{GreaterThan: comparison("score", `10`)}, fields)
+		assert.ErrorIs(t, err, ErrMalformedCondition)
+	})
+
+	t.Run("run field", func(t *testing.T) {
+		_, err := ResolvePropertyFilter(ConditionExprV1{Is: comparison("run_field", `"x"`)}, fields)
+		assert.ErrorIs(t, err, ErrMalformedCondition)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		_, err := ResolvePropertyFilter(ConditionExprV1{LessThan: comparison("impact", `"many"`)}, fields)
+		assert.ErrorIs(t, err, ErrMalformedCondition)
+	})
+}
+
+func TestValidatePropertySortField(t *testing.T) {
+	field := func(fieldType model.PropertyFieldType, targetType string) PropertyField {
+		return PropertyField{PropertyField: model.PropertyField{ID: "field", Type: fieldType, TargetType: targetType}}
+	}
+
+	assert.NoError(t, ValidatePropertySortField(field(model.PropertyFieldTypeSelect, PropertyTargetTypePlaybook)))
+	assert.NoError(t, ValidatePropertySortField(field(model.PropertyFieldTypeDate, PropertyTargetTypeTeam)))
+	assert.ErrorIs(t, ValidatePropertySortField(field(model.PropertyFieldTypeUser, PropertyTargetTypePlaybook)), ErrMalformedCondition)
+	assert.ErrorIs(t, ValidatePropertySortField(field(model.PropertyFieldTypeText, PropertyTargetTypeRun)), ErrMalformedCondition)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// PropertyValueChange records a write of a run's property value. Changes are append-only and
+// form the history of the run's property values.
+type PropertyValueChange struct {
+	ID            string `json:"id"`
+	PlaybookRunID string `json:"playbook_run_id"`
+	FieldID       string `json:"field_id"`
+
+	// OldValue and NewValue hold the JSON value before and after the write; null when unset.
+	OldValue json.RawMessage `json:"old_value"`
+	NewValue json.RawMessage `json:"new_value"`
+
+	// UserID is the user who set the value, empty for values computed by the server.
+	UserID   string `json:"user_id"`
+	CreateAt int64  `json:"create_at"`
+}
+
+// PropertyValueChangeOptions filters the history of a run's property values.
+type PropertyValueChangeOptions struct {
+	// FieldID restricts the history to a single field. Empty returns every field.
+	FieldID string
+
+	// Until excludes changes made after this time, in milliseconds. 0 means no limit.
+	Until int64
+}
+
+// PropertyValuesAsOf reconstructs the values a run's properties had at the given time from their
+// history, ordered by CreateAt. Values of fields without any recorded change, such as values set
+// before the history was kept, are taken from current when they were last updated by then.
+func PropertyValuesAsOf(current []PropertyValue, changes []PropertyValueChange, asOf int64) []PropertyValue {
+	latest := make(map[string]PropertyValueChange)
+	hasHistory := make(map[string]bool)
+	for _, change := range changes {
+		hasHistory[change.FieldID] = true
+		if change.CreateAt <= asOf {
+			latest[change.FieldID] = change
+		}
+	}
+
+	values := make([]PropertyValue, 0, len(latest))
+	for _, change := range latest {
+		values = append(values, PropertyValue{
+			TargetID: change.PlaybookRunID,
+			FieldID:  change.FieldID,
+			Value:    change.NewValue,
+			UpdateAt: change.CreateAt,
+		})
+	}
+	for _, value := range current {
+		if !hasHistory[value.FieldID] && value.UpdateAt <= asOf {
+			values = append(values, value)
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].FieldID < values[j].FieldID
+	})
+	return values
+}
+
+// normalizeHistoryValue stores missing values as JSON null.
+func normalizeHistoryValue(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyValuesAsOf(t *testing.T) {
+	current := []PropertyValue{
+		{TargetID: "run", FieldID: "severity", Value: json.RawMessage(`"opt_low"`), UpdateAt: 300},
+		{TargetID: "run", FieldID: "legacy", Value: json.RawMessage(`"before history"`), UpdateAt: 50},
+		{TargetID: "run", FieldID: "late", Value: json.RawMessage(`"set late"`), UpdateAt: 500},
+	}
+	changes := []PropertyValueChange{
+		{PlaybookRunID: "run", FieldID: "severity", OldValue: json.RawMessage(`null`), NewValue: json.RawMessage(`"opt_high"`), CreateAt: 100},
+		{PlaybookRunID: "run", FieldID: "owner", OldValue: json.RawMessage(`null`), NewValue: json.RawMessage(`"alice"`), CreateAt: 150},
+		{PlaybookRunID: "run", FieldID: "severity", OldValue: json.RawMessage(`"opt_high"`), NewValue: json.RawMessage(`"opt_low"`), CreateAt: 300},
+	}
+
+	valuesByField := func(values []PropertyValue) map[string]string {
+		result := make(map[string]string, len(values))
+		for _, value := range values {
+			result[value.FieldID] = string(value.Value)
+		}
+		return result
+	}
+
+	t.Run("before any change", func(t *testing.T) {
+		values := PropertyValuesAsOf(current, changes, 10)
+		assert.Empty(t, values)
+	})
+
+	t.Run("between changes", func(t *testing.T) {
+		values := PropertyValuesAsOf(current, changes, 200)
+		assert.Equal(t, map[string]string{
+			"legacy":   `"before history"`,
+			"owner":    `"alice"`,
+			"severity": `"opt_high"`,
+		}, valuesByField(values))
+	})
+
+	t.Run("after every change", func(t *testing.T) {
+		values := PropertyValuesAsOf(current, changes, 1000)
+		require.Len(t, values, 4)
+		assert.Equal(t, []string{"late", "legacy", "owner", "severity"}, []string{values[0].FieldID, values[1].FieldID, values[2].FieldID, values[3].FieldID})
+		assert.Equal(t, `"opt_low"`, string(values[3].Value))
+		assert.Equal(t, int64(300), values[3].UpdateAt)
+	})
+}
@@ -4,8 +4,11 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -51,6 +54,8 @@ func (s *propertyService) CreatePropertyField(playbookID string, propertyField P
 		return nil, err
 	}
 
+	// Fields are linked to team fields through LinkTeamPropertyField only.
+	propertyField.Attrs.SharedFieldID = ""
 	if err := propertyField.SanitizeAndValidate(); err != nil {
 		return nil, errors.Wrap(err, "invalid property field")
 	}
@@ -172,6 +177,25 @@ func (s *propertyService) UpdatePropertyField(playbookID string, propertyField P
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get existing property field")
 	}
+
+	// Linked fields are defined by their team field: only their visibility and sort order belong
+	// to the playbook.
+	currentField, err := NewPropertyFieldFromMattermostPropertyField(existingField)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert existing property field")
+	}
+	if currentField.IsShared() {
+		if !sameSharedDefinition(*currentField, propertyField) {
+			return nil, errors.Wrapf(ErrPropertyFieldShared, "cannot change property field '%s': its name, type and options are managed by its team", currentField.Name)
+		}
+		visibility, sortOrder := propertyField.Attrs.Visibility, propertyField.Attrs.SortOrder
+		propertyField = *currentField
+		propertyField.Attrs.Visibility = visibility
+		propertyField.Attrs.SortOrder = sortOrder
+		if err := propertyField.SanitizeAndValidate(); err != nil {
+			return nil, errors.Wrap(err, "invalid property field")
+		}
+	}
 	// Check if the type is changing and validate it's allowed
 	if existingField.Type != propertyField.Type {
 		if err := s.validatePropertyFieldTypeChange(existingField, propertyField, playbookID); err != nil {
@@ -397,17 +421,15 @@ func (s *propertyService) getAllPropertyFieldsSince(targetType, targetID string,
 	return allFields, nil
 }
 
-func (s *propertyService) CopyPlaybookPropertiesToRun(playbookID, runID string) (*PropertyCopyResult, error) {
-	playbookProperties, err := s.getAllPropertyFields(PropertyTargetTypePlaybook, playbookID)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get playbook properties")
-	}
-
+// CopyPlaybookPropertiesToRun creates run copies of the given property fields of a playbook, which
+// may be those of a revision rather than its current ones.
+func (s *propertyService) CopyPlaybookPropertiesToRun(playbookID, runID string, playbookProperties []PropertyField) (*PropertyCopyResult, error) {
 	fieldMappings := make(map[string]string)
 	optionMappings := make(map[string]string)
 	var copiedFields []PropertyField
 
-	for _, playbookProperty := range playbookProperties {
+	for i := range playbookProperties {
+		playbookProperty := playbookProperties[i].ToMattermostPropertyField()
 		runProperty, err := s.copyPropertyFieldForRun(playbookProperty, runID)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to duplicate property field %s for run", playbookProperty.Name)
@@ -525,7 +547,7 @@ func (s *propertyService) copyPropertyFieldForPlaybook(sourceProperty *model.Pro
 	propertyField.TargetType = PropertyTargetTypePlaybook
 	propertyField.TargetID = targetPlaybookID
 
-	if propertyField.SupportsOptions() {
+	if propertyField.SupportsOptions() && !propertyField.IsShared() {
 		for i := range propertyField.Attrs.Options {
 			propertyField.Attrs.Options[i].SetID("")
 		}
@@ -553,7 +575,9 @@ func (s *propertyService) copyPropertyFieldForRun(playbookProperty *model.Proper
 	propertyField.TargetID = runID
 	propertyField.Attrs.ParentID = playbookProperty.ID
 
-	if propertyField.SupportsOptions() {
+	// Fields linked to a team field keep its option IDs, so runs of every playbook using it can be
+	// compared.
+	if propertyField.SupportsOptions() && !propertyField.IsShared() {
 		for i := range propertyField.Attrs.Options {
 			propertyField.Attrs.Options[i].SetID("")
 		}
@@ -694,6 +718,43 @@ func (s *propertyService) UpsertRunPropertyValueWithField(runID string, field *P
 	return (*PropertyValue)(upsertedValue), nil
 }
 
+// RecomputeRunPropertyValues evaluates the computed fields of the run and stores the values that
+// changed, returning them. The run must have been loaded with its property fields and values.
+func (s *propertyService) RecomputeRunPropertyValues(run *PlaybookRun) ([]PropertyValue, error) {
+	current := make(map[string]json.RawMessage, len(run.PropertyValues))
+	for _, value := range run.PropertyValues {
+		current[value.FieldID] = value.Value
+	}
+
+	computed := run.ComputePropertyValues(model.GetMillis())
+
+	var changed []PropertyValue
+	for i := range run.PropertyFields {
+		field := &run.PropertyFields[i]
+		value, ok := computed[field.ID]
+		if !ok || computedValueUnchanged(current[field.ID], value) {
+			continue
+		}
+
+		upserted, err := s.UpsertRunPropertyValueWithField(run.ID, field, value)
+		if err != nil {
+			return changed, errors.Wrapf(err, "failed to store computed value of property field %s", field.ID)
+		}
+		changed = append(changed, *upserted)
+	}
+
+	return changed, nil
+}
+
+// computedValueUnchanged compares a stored value with a freshly computed one, treating missing
+// values as null.
+func computedValueUnchanged(stored, computed json.RawMessage) bool {
+	if isEmptyPropertyValue(stored) || isEmptyPropertyValue(computed) {
+		return isEmptyPropertyValue(stored) && isEmptyPropertyValue(computed)
+	}
+	return bytes.Equal(bytes.TrimSpace(stored), bytes.TrimSpace(computed))
+}
+
 func (s *propertyService) sanitizeAndValidatePropertyValue(propertyField *model.PropertyField, value json.RawMessage, validateOptions bool) (json.RawMessage, error) {
 	if len(value) == 0 || string(value) == "null" {
 		return value, nil
@@ -701,6 +762,13 @@ func (s *propertyService) sanitizeAndValidatePropertyValue(propertyField *model.
 
 	switch propertyField.Type {
 	case model.PropertyFieldTypeText:
+		pf, err := NewPropertyFieldFromMattermostPropertyField(propertyField)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert property field")
+		}
+		if pf.IsNumber() {
+			return normalizeNumberValue(pf, value)
+		}
 		var stringValue string
 		if err := json.Unmarshal(value, &stringValue); err != nil {
 			return nil, errors.New("text field value must be a string")
@@ -709,6 +777,18 @@ func (s *propertyService) sanitizeAndValidatePropertyValue(propertyField *model.
 		if err != nil {
 			return nil, err
 		}
+		if sanitizedString != "" {
+			switch pf.Attrs.ValueType {
+			case PropertyValueTypeURL:
+				if err := validateURLValue(sanitizedString); err != nil {
+					return nil, err
+				}
+			case PropertyValueTypeEmail:
+				if !model.IsValidEmail(sanitizedString) {
+					return nil, errors.New("email field value must be a valid email address")
+				}
+			}
+		}
 		return json.Marshal(sanitizedString)
 	case model.PropertyFieldTypeSelect:
 		var stringValue string
@@ -760,9 +840,65 @@ func (s *propertyService) sanitizeAndValidatePropertyValue(propertyField *model.
 }
 
 // SanitizePropertyValue sanitizes without validating option membership (use for template pre-sanitization).
-func (s *propertyService) SanitizePropertyValue(fieldType model.PropertyFieldType, raw json.RawMessage) (json.RawMessage, error) {
-	field := &model.PropertyField{Type: fieldType}
-	return s.sanitizeAndValidatePropertyValue(field, raw, false)
+// Value type constraints such as number bounds and URL or email formats are still enforced.
+func (s *propertyService) SanitizePropertyValue(field *PropertyField, raw json.RawMessage) (json.RawMessage, error) {
+	if field == nil {
+		return nil, errors.Wrap(ErrInternalPrecondition, "field must not be nil")
+	}
+	return s.sanitizeAndValidatePropertyValue(field.ToMattermostPropertyField(), raw, false)
+}
+
+// ValidatePropertyValue sanitizes a value and validates it as a run value of field would be,
+// including option membership.
+func (s *propertyService) ValidatePropertyValue(field *PropertyField, raw json.RawMessage) (json.RawMessage, error) {
+	if field == nil {
+		return nil, errors.Wrap(ErrInternalPrecondition, "field must not be nil")
+	}
+	return s.sanitizeAndValidatePropertyValue(field.ToMattermostPropertyField(), raw, true)
+}
+
+// normalizeNumberValue accepts a JSON number or a numeric string, enforces the field's integer and
+// min/max constraints and stores the value as a JSON number. An empty string clears the value.
+func normalizeNumberValue(field *PropertyField, value json.RawMessage) (json.RawMessage, error) {
+	var number float64
+	if err := json.Unmarshal(value, &number); err != nil {
+		var stringValue string
+		if err := json.Unmarshal(value, &stringValue); err != nil {
+			return nil, errors.New("number field value must be a number")
+		}
+		stringValue = strings.TrimSpace(stringValue)
+		if stringValue == "" {
+			return json.RawMessage("null"), nil
+		}
+		number, err = strconv.ParseFloat(stringValue, 64)
+		if err != nil {
+			return nil, errors.New("number field value must be a number")
+		}
+	}
+
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return nil, errors.New("number field value must be finite")
+	}
+	if field.Attrs.Integer && number != math.Trunc(number) {
+		return nil, errors.New("number field value must be an integer")
+	}
+	if field.Attrs.Min != nil && number < *field.Attrs.Min {
+		return nil, errors.Errorf("number field value must be at least %s", strconv.FormatFloat(*field.Attrs.Min, 'f', -1, 64))
+	}
+	if field.Attrs.Max != nil && number > *field.Attrs.Max {
+		return nil, errors.Errorf("number field value must be at most %s", strconv.FormatFloat(*field.Attrs.Max, 'f', -1, 64))
+	}
+
+	return json.Marshal(number)
+}
+
+// validateURLValue requires an absolute http or https URL.
+func validateURLValue(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return errors.New("url field value must be an absolute http or https URL")
+	}
+	return nil
 }
 
 // normalizeDateValue normalizes RFC3339 strings, numeric-string millis, and JSON-number millis to RFC3339.
@@ -305,6 +305,26 @@ func TestPropertyService_sanitizeAndValidatePropertyValue(t *testing.T) {
 		Type: model.PropertyFieldTypeText,
 	}
 
+	numberPropertyField := &model.PropertyField{
+		Type: model.PropertyFieldTypeText,
+		Attrs: model.StringInterface{
+			PropertyAttrsValueType: PropertyValueTypeNumber,
+			PropertyAttrsMin:       0.0,
+			PropertyAttrsMax:       1000.0,
+			PropertyAttrsInteger:   true,
+		},
+	}
+
+	urlPropertyField := &model.PropertyField{
+		Type:  model.PropertyFieldTypeText,
+		Attrs: model.StringInterface{PropertyAttrsValueType: PropertyValueTypeURL},
+	}
+
+	emailPropertyField := &model.PropertyField{
+		Type:  model.PropertyFieldTypeText,
+		Attrs: model.StringInterface{PropertyAttrsValueType: PropertyValueTypeEmail},
+	}
+
 	tests := []struct {
 		name           string
 		propertyField  *model.PropertyField
@@ -408,6 +428,86 @@ func TestPropertyService_sanitizeAndValidatePropertyValue(t *testing.T) {
 			expectedOutput: json.RawMessage(`null`),
 			expectError:    false,
 		},
+		// Number field tests
+		{
+			name:           "number field stores numbers as numbers",
+			propertyField:  numberPropertyField,
+			input:          json.RawMessage(`42`),
+			expectedOutput: json.RawMessage(`42`),
+		},
+		{
+			name:           "number field converts numeric strings",
+			propertyField:  numberPropertyField,
+			input:          json.RawMessage(`" 17 "`),
+			expectedOutput: json.RawMessage(`17`),
+		},
+		{
+			name:           "number field clears on empty string",
+			propertyField:  numberPropertyField,
+			input:          json.RawMessage(`""`),
+			expectedOutput: json.RawMessage(`null`),
+		},
+		{
+			name:          "number field rejects text",
+			propertyField: numberPropertyField,
+			input:         json.RawMessage(`"many"`),
+			expectError:   true,
+		},
+		{
+			name:          "number field rejects fractions when integer",
+			propertyField: numberPropertyField,
+			input:         json.RawMessage(`1.5`),
+			expectError:   true,
+		},
+		{
+			name:          "number field rejects values below min",
+			propertyField: numberPropertyField,
+			input:         json.RawMessage(`-1`),
+			expectError:   true,
+		},
+		{
+			name:          "number field rejects values above max",
+			propertyField: numberPropertyField,
+			input:         json.RawMessage(`1001`),
+			expectError:   true,
+		},
+		// URL and email field tests
+		{
+			name:           "url field allows http URLs",
+			propertyField:  urlPropertyField,
+			input:          json.RawMessage(`" https://example.com/incident "`),
+			expectedOutput: json.RawMessage(`"https://example.com/incident"`),
+		},
+		{
+			name:          "url field rejects relative URLs",
+			propertyField: urlPropertyField,
+			input:         json.RawMessage(`"example.com"`),
+			expectError:   true,
+		},
+		{
+			name:          "url field rejects other schemes",
+			propertyField: urlPropertyField,
+			input:         json.RawMessage(`"javascript://alert(1)"`),
+			expectError:   true,
+		},
+		{
+			name:           "url field allows clearing",
+			propertyField:  urlPropertyField,
+			input:          json.RawMessage(`""`),
+			expectedOutput: json.RawMessage(`""`),
+		},
+		{
+			name:           "email field allows email addresses",
+			propertyField:  emailPropertyField,
+			input:          json.RawMessage(`"oncall@example.com"`),
+			expectedOutput: json.RawMessage(`"oncall@example.com"`),
+		},
+		{
+			name:          "email field rejects invalid addresses",
+			propertyField: emailPropertyField,
+			input:         json.RawMessage(`"not an email"`),
+			expectError:   true,
+		},
 		// Empty value tests
 		{
 			name:           "text field allows empty RawMessage",
@@ -457,14 +557,24 @@ func TestSanitizePropertyValue_SkipsOptionValidation(t *testing.T) {
 	unknownMultiselectIDs := json.RawMessage(`["does-not-exist-1","does-not-exist-2"]`)
 
 	t.Run("select with unknown option ID passes when validateOptions=false", func(t *testing.T) {
-		_, err := s.SanitizePropertyValue(model.PropertyFieldTypeSelect, unknownSelectID)
+		_, err := s.SanitizePropertyValue(&PropertyField{PropertyField: model.PropertyField{Type: model.PropertyFieldTypeSelect}}, unknownSelectID)
 		assert.NoError(t, err)
 	})
 
 	t.Run("multiselect with unknown option IDs passes when validateOptions=false", func(t *testing.T) {
-		_, err := s.SanitizePropertyValue(model.PropertyFieldTypeMultiselect, unknownMultiselectIDs)
+		_, err := s.SanitizePropertyValue(&PropertyField{PropertyField: model.PropertyField{Type: model.PropertyFieldTypeMultiselect}}, unknownMultiselectIDs)
 		assert.NoError(t, err)
 	})
+
+	t.Run("number constraints are still enforced", func(t *testing.T) {
+		maxValue := 10.0
+		field := &PropertyField{
+			PropertyField: model.PropertyField{Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Max: &maxValue},
+		}
+		_, err := s.SanitizePropertyValue(field, json.RawMessage(`11`))
+		assert.Error(t, err)
+	})
 }
 
 func TestPropertyService_TestPropertySortOrder(t *testing.T) {
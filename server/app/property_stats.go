@@ -0,0 +1,85 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"math"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// NumberPropertyStats summarizes the values a number property of a playbook took across its runs.
+type NumberPropertyStats struct {
+	// FieldID is the ID of the playbook property field.
+	FieldID string `json:"field_id"`
+	Name    string `json:"name"`
+
+	// Count is the number of runs with a value set. The other aggregates are zero when it is zero.
+	Count   int     `json:"count"`
+	Sum     float64 `json:"sum"`
+	Average float64 `json:"average"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// ComputeNumberPropertyStats aggregates the values of the playbook's number fields over the given
+// runs. Run fields are matched to playbook fields through their parent ID, and values that cannot
+// be read as numbers are skipped. Stats are returned in the order of playbookFields.
+func ComputeNumberPropertyStats(playbookFields []PropertyField, runFields map[string][]PropertyField, runValues map[string][]PropertyValue) []NumberPropertyStats {
+	stats := make([]NumberPropertyStats, 0, len(playbookFields))
+	statsByFieldID := make(map[string]int, len(playbookFields))
+	for _, field := range playbookFields {
+		if !field.IsNumber() {
+			continue
+		}
+		statsByFieldID[field.ID] = len(stats)
+		stats = append(stats, NumberPropertyStats{FieldID: field.ID, Name: field.Name})
+	}
+
+	// Values are read as numbers even if the run copied the field before it became a number field.
+	numberField := PropertyField{
+		PropertyField: model.PropertyField{Type: model.PropertyFieldTypeText},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+	}
+
+	for runID, fields := range runFields {
+		fieldsByID := make(map[string]PropertyField, len(fields))
+		for _, field := range fields {
+			fieldsByID[field.ID] = field
+		}
+
+		for _, value := range runValues[runID] {
+			field, ok := fieldsByID[value.FieldID]
+			if !ok {
+				continue
+			}
+			idx, ok := statsByFieldID[field.Attrs.ParentID]
+			if !ok {
+				continue
+			}
+			number, ok := orderedValue(numberField, value.Value)
+			if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+				continue
+			}
+
+			s := &stats[idx]
+			if s.Count == 0 || number < s.Min {
+				s.Min = number
+			}
+			if s.Count == 0 || number > s.Max {
+				s.Max = number
+			}
+			s.Count++
+			s.Sum += number
+		}
+	}
+
+	for i := range stats {
+		if stats[i].Count > 0 {
+			stats[i].Average = stats[i].Sum / float64(stats[i].Count)
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeNumberPropertyStats(t *testing.T) {
+	playbookFields := []PropertyField{
+		{
+			PropertyField: model.PropertyField{ID: "pb-customers", Name: "Customers affected", Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+		},
+		{
+			PropertyField: model.PropertyField{ID: "pb-summary", Name: "Summary", Type: model.PropertyFieldTypeText},
+		},
+		{
+			PropertyField: model.PropertyField{ID: "pb-cost", Name: "Cost", Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ValueType: PropertyValueTypeNumber},
+		},
+	}
+
+	runField := func(id, parentID string) PropertyField {
+		return PropertyField{
+			PropertyField: model.PropertyField{ID: id, Type: model.PropertyFieldTypeText},
+			Attrs:         Attrs{ParentID: parentID, ValueType: PropertyValueTypeNumber},
+		}
+	}
+
+	runFields := map[string][]PropertyField{
+		"run1": {runField("r1-customers", "pb-customers"), runField("r1-summary", "pb-summary")},
+		"run2": {runField("r2-customers", "pb-customers")},
+		"run3": {runField("r3-customers", "pb-customers")},
+	}
+	runValues := map[string][]PropertyValue{
+		"run1": {
+			{FieldID: "r1-customers", Value: json.RawMessage(`10`)},
+			{FieldID: "r1-summary", Value: json.RawMessage(`"ignored"`)},
+		},
+		"run2": {{FieldID: "r2-customers", Value: json.RawMessage(`"30"`)}},
+		"run3": {{FieldID: "r3-customers", Value: json.RawMessage(`"unknown"`)}},
+	}
+
+	stats := ComputeNumberPropertyStats(playbookFields, runFields, runValues)
+
+	require.Equal(t, []NumberPropertyStats{
+		{FieldID: "pb-customers", Name: "Customers affected", Count: 2, Sum: 40, Average: 20, Min: 10, Max: 30},
+		{FieldID: "pb-cost", Name: "Cost"},
+	}, stats)
+}
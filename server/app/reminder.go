@@ -83,7 +83,7 @@ func (s *PlaybookRunServiceImpl) handleStatusUpdateReminder(playbookRunID string
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
@@ -215,7 +215,7 @@ func (s *PlaybookRunServiceImpl) SetNewReminder(playbookRunID string, newReminde
 	}
 
 	var originalRun *PlaybookRun
-	if s.configService.IsIncrementalUpdatesEnabled() {
+	if s.shouldDiffRunUpdates() {
 		originalRun = playbookRunToModify.Clone()
 	}
 
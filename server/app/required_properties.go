@@ -0,0 +1,112 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MissingPropertyField identifies a required property field that has no value.
+type MissingPropertyField struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// MissingRequiredPropertiesError is returned when a run is created or finished while fields
+// marked as required for that step have no value. It wraps ErrMalformedPlaybookRun.
+type MissingRequiredPropertiesError struct {
+	// Requirement is the attribute that made the fields mandatory: PropertyAttrsRequiredOnCreate
+	// or PropertyAttrsRequiredOnFinish.
+	Requirement string
+	Fields      []MissingPropertyField
+}
+
+func (e *MissingRequiredPropertiesError) Error() string {
+	return e.PublicMessage()
+}
+
+func (e *MissingRequiredPropertiesError) Unwrap() error {
+	return ErrMalformedPlaybookRun
+}
+
+// PublicMessage describes the missing fields in a form suitable for showing to users.
+func (e *MissingRequiredPropertiesError) PublicMessage() string {
+	names := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		names = append(names, field.Name)
+	}
+
+	step := "starting"
+	if e.Requirement == PropertyAttrsRequiredOnFinish {
+		step = "finishing"
+	}
+	return fmt.Sprintf("The following properties must be filled in before %s the run: %s", step, strings.Join(names, ", "))
+}
+
+// IsRequired reports whether the field must have a value for the given requirement,
+// PropertyAttrsRequiredOnCreate or PropertyAttrsRequiredOnFinish.
+func (p *PropertyField) IsRequired(requirement string) bool {
+	switch requirement {
+	case PropertyAttrsRequiredOnCreate:
+		return p.Attrs.RequiredOnCreate
+	case PropertyAttrsRequiredOnFinish:
+		return p.Attrs.RequiredOnFinish
+	default:
+		return false
+	}
+}
+
+// CheckRequiredPropertyValues returns a MissingRequiredPropertiesError listing the fields that are
+// required for the given requirement but have no value, or nil when every required field is set.
+// values is keyed by field ID.
+func CheckRequiredPropertyValues(fields []PropertyField, values map[string]json.RawMessage, requirement string) error {
+	var missing []MissingPropertyField
+	for _, field := range fields {
+		if field.DeleteAt != 0 || !field.IsRequired(requirement) {
+			continue
+		}
+		if isEmptyPropertyValue(values[field.ID]) {
+			missing = append(missing, MissingPropertyField{ID: field.ID, Name: field.Name})
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return &MissingRequiredPropertiesError{Requirement: requirement, Fields: missing}
+}
+
+// CheckRequiredOnFinish checks the run's property values against the fields required to finish it.
+// The run must have been loaded with its property fields and values.
+func (r *PlaybookRun) CheckRequiredOnFinish() error {
+	values := make(map[string]json.RawMessage, len(r.PropertyValues))
+	for _, value := range r.PropertyValues {
+		values[value.FieldID] = value.Value
+	}
+	return CheckRequiredPropertyValues(r.PropertyFields, values, PropertyAttrsRequiredOnFinish)
+}
+
+// isEmptyPropertyValue reports whether raw holds no meaningful value: nothing, null, a blank
+// string or an empty array.
+func isEmptyPropertyValue(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return true
+	}
+
+	var str string
+	if err := json.Unmarshal(trimmed, &str); err == nil {
+		return strings.TrimSpace(str) == ""
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(trimmed, &list); err == nil {
+		return len(list) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsEmptyPropertyValue(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{``, true},
+		{`null`, true},
+		{`""`, true},
+		{`"   "`, true},
+		{`[]`, true},
+		{`"value"`, false},
+		{`["option"]`, false},
+		{`0`, false},
+		{`1700000000000`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isEmptyPropertyValue(json.RawMessage(tc.value)))
+		})
+	}
+}
+
+func TestCheckRequiredPropertyValues(t *testing.T) {
+	fields := []PropertyField{
+		{PropertyField: model.PropertyField{ID: "severity", Name: "Severity"}, Attrs: Attrs{RequiredOnCreate: true}},
+		{PropertyField: model.PropertyField{ID: "root_cause", Name: "Root cause category"}, Attrs: Attrs{RequiredOnFinish: true}},
+		{PropertyField: model.PropertyField{ID: "impact", Name: "Customer impact"}, Attrs: Attrs{RequiredOnCreate: true, RequiredOnFinish: true}},
+		{PropertyField: model.PropertyField{ID: "deleted", Name: "Deleted", DeleteAt: 1}, Attrs: Attrs{RequiredOnCreate: true}},
+		{PropertyField: model.PropertyField{ID: "notes", Name: "Notes"}},
+	}
+
+	t.Run("reports missing fields for the requirement only", func(t *testing.T) {
+		err := CheckRequiredPropertyValues(fields, map[string]json.RawMessage{
+			"severity": json.RawMessage(`"high"`),
+			"impact":   json.RawMessage(`""`),
+		}, PropertyAttrsRequiredOnCreate)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrMalformedPlaybookRun))
+
+		var missingErr *MissingRequiredPropertiesError
+		require.True(t, errors.As(err, &missingErr))
+		assert.Equal(t, PropertyAttrsRequiredOnCreate, missingErr.Requirement)
+		assert.Equal(t, []MissingPropertyField{{ID: "impact", Name: "Customer impact"}}, missingErr.Fields)
+		assert.Equal(t, "The following properties must be filled in before starting the run: Customer impact", missingErr.PublicMessage())
+	})
+
+	t.Run("all required fields set", func(t *testing.T) {
+		err := CheckRequiredPropertyValues(fields, map[string]json.RawMessage{
+			"root_cause": json.RawMessage(`"option-id"`),
+			"impact":     json.RawMessage(`"Checkout unavailable"`),
+		}, PropertyAttrsRequiredOnFinish)
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown requirement requires nothing", func(t *testing.T) {
+		require.NoError(t, CheckRequiredPropertyValues(fields, nil, "required_on_lunch"))
+	})
+}
+
+func TestPlaybookRun_CheckRequiredOnFinish(t *testing.T) {
+	run := &PlaybookRun{
+		PropertyFields: []PropertyField{
+			{PropertyField: model.PropertyField{ID: "root_cause", Name: "Root cause category"}, Attrs: Attrs{RequiredOnFinish: true}},
+			{PropertyField: model.PropertyField{ID: "impact", Name: "Customer impact"}, Attrs: Attrs{RequiredOnFinish: true}},
+		},
+		PropertyValues: []PropertyValue{
+			{FieldID: "root_cause", Value: json.RawMessage(`"option-id"`)},
+		},
+	}
+
+	err := run.CheckRequiredOnFinish()
+	var missingErr *MissingRequiredPropertiesError
+	require.True(t, errors.As(err, &missingErr))
+	assert.Equal(t, []MissingPropertyField{{ID: "impact", Name: "Customer impact"}}, missingErr.Fields)
+	assert.Contains(t, missingErr.PublicMessage(), "before finishing the run")
+
+	run.PropertyValues = append(run.PropertyValues, PropertyValue{FieldID: "impact", Value: json.RawMessage(`"None"`)})
+	require.NoError(t, run.CheckRequiredOnFinish())
+}
@@ -29,6 +29,9 @@ type allocPlaybookServiceStub struct {
 }
 
 func (s *allocPlaybookServiceStub) Get(string) (Playbook, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) GetPublished(string) (Playbook, error) {
 	s.getCalled++
 	return s.getResult, s.getErr
 }
@@ -54,7 +57,10 @@ func (s *allocPlaybookServiceStub) GetActivePlaybooks() ([]Playbook, error) { pa
 func (s *allocPlaybookServiceStub) GetPlaybooksForTeam(RequesterInfo, string, PlaybookFilterOptions) (GetPlaybooksResults, error) {
 	panic("not called")
 }
-func (s *allocPlaybookServiceStub) Update(Playbook, string) error           { panic("not called") }
+func (s *allocPlaybookServiceStub) Update(Playbook, string) error { panic("not called") }
+func (s *allocPlaybookServiceStub) UpdateIfUnmodified(Playbook, string, int64) error {
+	panic("not called")
+}
 func (s *allocPlaybookServiceStub) Archive(Playbook, string) error          { panic("not called") }
 func (s *allocPlaybookServiceStub) Restore(Playbook, string) error          { panic("not called") }
 func (s *allocPlaybookServiceStub) AutoFollow(string, string) error         { panic("not called") }
@@ -69,14 +75,28 @@ func (s *allocPlaybookServiceStub) GetTopPlaybooksForTeam(string, string, *Insig
 func (s *allocPlaybookServiceStub) GetTopPlaybooksForUser(string, string, *InsightsOpts) (*PlaybooksInsightsList, error) {
 	panic("not called")
 }
-func (s *allocPlaybookServiceStub) CreatePropertyField(string, PropertyField) (*PropertyField, error) {
+func (s *allocPlaybookServiceStub) CreatePropertyField(string, PropertyField, string) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) UpdatePropertyField(string, PropertyField, string) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) DeletePropertyField(string, string, string) error {
 	panic("not called")
 }
-func (s *allocPlaybookServiceStub) UpdatePropertyField(string, PropertyField) (*PropertyField, error) {
+func (s *allocPlaybookServiceStub) ReorderPropertyFields(string, string, int, string) ([]PropertyField, error) {
 	panic("not called")
 }
-func (s *allocPlaybookServiceStub) DeletePropertyField(string, string) error { panic("not called") }
-func (s *allocPlaybookServiceStub) ReorderPropertyFields(string, string, int) ([]PropertyField, error) {
+func (s *allocPlaybookServiceStub) CreateTeamPropertyField(string, PropertyField) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) UpdateTeamPropertyField(string, PropertyField, string) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) DeleteTeamPropertyField(string, string) error {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) MergeTeamPropertyFields(string, string, string) (*SharedPropertyMergeResult, error) {
 	panic("not called")
 }
 func (s *allocPlaybookServiceStub) UpdateChannelNameTemplate(string, string, string) error {
@@ -85,6 +105,30 @@ func (s *allocPlaybookServiceStub) UpdateChannelNameTemplate(string, string, str
 func (s *allocPlaybookServiceStub) UpdateRunNumberPrefix(string, string, string) error {
 	panic("not called")
 }
+func (s *allocPlaybookServiceStub) RecordRevision(string, string) error { panic("not called") }
+func (s *allocPlaybookServiceStub) GetAtRevision(string, int64) (Playbook, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) GetPropertiesAndConditions(Playbook) ([]PropertyField, []Condition, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) GraphqlUpdate(string, map[string]interface{}, string) error {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) AddMember(string, string, string) error { panic("not called") }
+func (s *allocPlaybookServiceStub) RemoveMember(string, string, string) error {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) GetMetric(string) (*PlaybookMetricConfig, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) AddMetric(string, PlaybookMetricConfig, string) (*PlaybookMetricConfig, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) UpdateMetric(string, PlaybookMetricPatch, string) (*PlaybookMetricConfig, error) {
+	panic("not called")
+}
+func (s *allocPlaybookServiceStub) DeleteMetric(string, string) error { panic("not called") }
 
 // allocPropertyServiceStub is a minimal PropertyService stub: returns fixed fields and
 // passes through SanitizePropertyValue unchanged. Methods resolveAndAllocate never calls panic.
@@ -95,9 +139,12 @@ type allocPropertyServiceStub struct {
 func (s *allocPropertyServiceStub) GetPropertyFields(string) ([]PropertyField, error) {
 	return s.fields, nil
 }
-func (s *allocPropertyServiceStub) SanitizePropertyValue(_ model.PropertyFieldType, raw json.RawMessage) (json.RawMessage, error) {
+func (s *allocPropertyServiceStub) SanitizePropertyValue(_ *PropertyField, raw json.RawMessage) (json.RawMessage, error) {
 	return raw, nil
 }
+func (s *allocPropertyServiceStub) ValidatePropertyValue(*PropertyField, json.RawMessage) (json.RawMessage, error) {
+	panic("not called")
+}
 func (s *allocPropertyServiceStub) CreatePropertyField(string, PropertyField) (*PropertyField, error) {
 	panic("not called")
 }
@@ -133,7 +180,7 @@ func (s *allocPropertyServiceStub) DeletePropertyField(string, string) error { p
 func (s *allocPropertyServiceStub) ReorderPropertyFields(string, string, int) ([]PropertyField, error) {
 	panic("not called")
 }
-func (s *allocPropertyServiceStub) CopyPlaybookPropertiesToRun(string, string) (*PropertyCopyResult, error) {
+func (s *allocPropertyServiceStub) CopyPlaybookPropertiesToRun(string, string, []PropertyField) (*PropertyCopyResult, error) {
 	panic("not called")
 }
 func (s *allocPropertyServiceStub) CopyPlaybookPropertiesToPlaybook(string, string) (*PropertyCopyResult, error) {
@@ -145,6 +192,27 @@ func (s *allocPropertyServiceStub) UpsertRunPropertyValue(string, string, json.R
 func (s *allocPropertyServiceStub) UpsertRunPropertyValueWithField(string, *PropertyField, json.RawMessage) (*PropertyValue, error) {
 	panic("not called")
 }
+func (s *allocPropertyServiceStub) RecomputeRunPropertyValues(*PlaybookRun) ([]PropertyValue, error) {
+	panic("not called")
+}
+func (s *allocPropertyServiceStub) CreateTeamPropertyField(string, PropertyField) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPropertyServiceStub) GetTeamPropertyFields(string) ([]PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPropertyServiceStub) UpdateTeamPropertyField(string, PropertyField, []string) (*PropertyField, []string, error) {
+	panic("not called")
+}
+func (s *allocPropertyServiceStub) DeleteTeamPropertyField(string, string, []string) error {
+	panic("not called")
+}
+func (s *allocPropertyServiceStub) LinkTeamPropertyField(string, string, PropertyField) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *allocPropertyServiceStub) MergePropertyFieldsIntoTeamField(string, string, []string) (*SharedPropertyMergeResult, error) {
+	panic("not called")
+}
 func (s *allocPropertyServiceStub) GetRunsPropertyFields([]string) (map[string][]PropertyField, error) {
 	panic("not called")
 }
@@ -288,3 +356,34 @@ func TestResolveAndAllocate_DryRunFailureSkipsAllocation(t *testing.T) {
 	assert.Equal(t, int64(0), run.RunNumber)
 	assert.Equal(t, "", run.SequentialID)
 }
+
+// TestResolveAndAllocate_ComputedFieldInTemplate verifies that computed fields resolve in the
+// channel name template from the run being created, ignoring values supplied by the caller.
+func TestResolveAndAllocate_ComputedFieldInTemplate(t *testing.T) {
+	zoneField := PropertyField{
+		PropertyField: model.PropertyField{ID: "fld_zone", Name: "Zone", Type: model.PropertyFieldTypeText},
+	}
+	openField := PropertyField{
+		PropertyField: model.PropertyField{ID: "fld_open", Name: "Open tasks", Type: model.PropertyFieldTypeText},
+		Attrs:         Attrs{ValueType: PropertyValueTypeNumber, Computed: &ComputedAttrs{Kind: ComputedKindOpenItemsCount}},
+	}
+	pb := Playbook{ID: "pb_1", ChannelNameTemplate: "{Zone} {Open tasks}"}
+	pbStub := &allocPlaybookServiceStub{getResult: pb, incrementResult: 1}
+	svc := &PlaybookRunServiceImpl{
+		playbookService: pbStub,
+		propertyService: &allocPropertyServiceStub{fields: []PropertyField{zoneField, openField}},
+		licenseChecker:  &allocLicenseCheckerWithAttributes{},
+	}
+
+	run := &PlaybookRun{
+		PlaybookID: pb.ID,
+		Checklists: []Checklist{{Items: []ChecklistItem{{State: ChecklistItemStateOpen}, {State: ChecklistItemStateOpen}, {State: ChecklistItemStateClosed}}}},
+	}
+	channelName, err := svc.resolveAndAllocate(run, &pb, map[string]json.RawMessage{
+		"fld_zone": json.RawMessage(`"eu"`),
+		"fld_open": json.RawMessage(`99`),
+	}, RunSourcePost)
+
+	require.NoError(t, err)
+	assert.Equal(t, "eu 2", channelName)
+}
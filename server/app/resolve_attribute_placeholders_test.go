@@ -5,6 +5,7 @@ package app
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
@@ -35,6 +36,49 @@ func TestResolveAttributePlaceholders_SEQStripping(t *testing.T) {
 	})
 }
 
+func TestResolveAttributePlaceholders_ContextTokens(t *testing.T) {
+	t.Run("TEAM resolved to the team display name", func(t *testing.T) {
+		mockAPI := &plugintest.API{}
+		mockAPI.On("GetTeam", "team1").Return(&model.Team{Id: "team1", DisplayName: "Payments"}, nil)
+		t.Cleanup(func() { mockAPI.AssertExpectations(t) })
+		svc := &PlaybookRunServiceImpl{pluginAPI: pluginapi.NewClient(mockAPI, nil)}
+		run := &PlaybookRun{TeamID: "team1", SequentialID: "INC-00001"}
+		result := svc.resolveAttributePlaceholders("{TEAM|upper} - {SEQ|lower}", run)
+		assert.Equal(t, "PAYMENTS - inc-00001", result)
+	})
+
+	t.Run("team not looked up when TEAM is unused", func(t *testing.T) {
+		svc := &PlaybookRunServiceImpl{}
+		run := &PlaybookRun{TeamID: "team1", SequentialID: "INC-00001"}
+		result := svc.resolveAttributePlaceholders("{#if OWNER}owned{else}unowned{/if} {SEQ}", run)
+		assert.Equal(t, "unowned INC-00001", result)
+	})
+
+	t.Run("legacy run strips SEQ with filters", func(t *testing.T) {
+		svc := &PlaybookRunServiceImpl{}
+		run := &PlaybookRun{}
+		result := svc.resolveAttributePlaceholders("{SEQ|upper} - Incident Report", run)
+		assert.Equal(t, "Incident Report", result)
+	})
+
+	t.Run("PROGRESS and ELAPSED resolved from the run", func(t *testing.T) {
+		svc := &PlaybookRunServiceImpl{}
+		run := &PlaybookRun{
+			SequentialID: "INC-00001",
+			CreateAt:     1000,
+			EndAt:        1000 + (26*time.Hour + 5*time.Minute).Milliseconds(),
+			Checklists: []Checklist{{Items: []ChecklistItem{
+				{State: ChecklistItemStateClosed},
+				{State: ChecklistItemStateSkipped},
+				{State: ChecklistItemStateOpen},
+				{State: ChecklistItemStateOpen, ConditionAction: ConditionActionHidden},
+			}}},
+		}
+		result := svc.resolveAttributePlaceholders("Tasks: {PROGRESS}, elapsed: {ELAPSED}", run)
+		assert.Equal(t, "Tasks: 2/3, elapsed: 1d 2h", result)
+	})
+}
+
 func newResolveUserTestService(t *testing.T, user *model.User, showFullName *bool) *PlaybookRunServiceImpl {
 	t.Helper()
 	mockAPI := &plugintest.API{}
@@ -70,7 +70,7 @@ func (s *stubUpsertPropertyService) DeletePropertyField(_, _ string) error { pan
 func (s *stubUpsertPropertyService) ReorderPropertyFields(_, _ string, _ int) ([]PropertyField, error) {
 	panic("not called")
 }
-func (s *stubUpsertPropertyService) CopyPlaybookPropertiesToRun(_, _ string) (*PropertyCopyResult, error) {
+func (s *stubUpsertPropertyService) CopyPlaybookPropertiesToRun(_, _ string, _ []PropertyField) (*PropertyCopyResult, error) {
 	panic("not called")
 }
 func (s *stubUpsertPropertyService) CopyPlaybookPropertiesToPlaybook(_, _ string) (*PropertyCopyResult, error) {
@@ -79,7 +79,13 @@ func (s *stubUpsertPropertyService) CopyPlaybookPropertiesToPlaybook(_, _ string
 func (s *stubUpsertPropertyService) UpsertRunPropertyValue(_, _ string, _ json.RawMessage) (*PropertyValue, error) {
 	panic("not called")
 }
-func (s *stubUpsertPropertyService) SanitizePropertyValue(_ mm_model.PropertyFieldType, _ json.RawMessage) (json.RawMessage, error) {
+func (s *stubUpsertPropertyService) RecomputeRunPropertyValues(_ *PlaybookRun) ([]PropertyValue, error) {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) SanitizePropertyValue(_ *PropertyField, _ json.RawMessage) (json.RawMessage, error) {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) ValidatePropertyValue(_ *PropertyField, _ json.RawMessage) (json.RawMessage, error) {
 	panic("not called")
 }
 func (s *stubUpsertPropertyService) GetRunsPropertyFields(_ []string) (map[string][]PropertyField, error) {
@@ -88,6 +94,24 @@ func (s *stubUpsertPropertyService) GetRunsPropertyFields(_ []string) (map[strin
 func (s *stubUpsertPropertyService) GetRunsPropertyValues(_ []string) (map[string][]PropertyValue, error) {
 	panic("not called")
 }
+func (s *stubUpsertPropertyService) CreateTeamPropertyField(_ string, _ PropertyField) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) GetTeamPropertyFields(_ string) ([]PropertyField, error) {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) UpdateTeamPropertyField(_ string, _ PropertyField, _ []string) (*PropertyField, []string, error) {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) DeleteTeamPropertyField(_, _ string, _ []string) error {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) LinkTeamPropertyField(_, _ string, _ PropertyField) (*PropertyField, error) {
+	panic("not called")
+}
+func (s *stubUpsertPropertyService) MergePropertyFieldsIntoTeamField(_, _ string, _ []string) (*SharedPropertyMergeResult, error) {
+	panic("not called")
+}
 
 // stubUpsertPropertyServiceCapture is like stubUpsertPropertyService but also records the last value passed to UpsertRunPropertyValueWithField.
 type stubUpsertPropertyServiceCapture struct {
@@ -303,3 +327,34 @@ func TestResolveRunCreationParams_DoesNotAllocateRunNumber(t *testing.T) {
 	assert.Equal(t, int64(0), run.RunNumber)
 	assert.Equal(t, "", run.SequentialID)
 }
+
+func TestResolveRunCreationParams_RequiredOnCreate(t *testing.T) {
+	pbID := mm_model.NewId()
+	fields := []PropertyField{
+		{PropertyField: mm_model.PropertyField{ID: "severity", Name: "Severity", Type: mm_model.PropertyFieldTypeText}, Attrs: Attrs{RequiredOnCreate: true}},
+		{PropertyField: mm_model.PropertyField{ID: "root_cause", Name: "Root cause", Type: mm_model.PropertyFieldTypeText}, Attrs: Attrs{RequiredOnFinish: true}},
+	}
+	svc := &PlaybookRunServiceImpl{
+		licenseChecker:  &allocLicenseCheckerWithAttributes{},
+		propertyService: &allocPropertyServiceStub{fields: fields},
+	}
+	pb := &Playbook{ID: pbID}
+
+	t.Run("missing value is rejected with the field", func(t *testing.T) {
+		run := &PlaybookRun{PlaybookID: pbID, OwnerUserID: "owner"}
+		err := svc.ResolveRunCreationParams(run, pb, map[string]json.RawMessage{"severity": json.RawMessage(`" "`)}, RunSourcePost)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrMalformedPlaybookRun))
+		var missingErr *MissingRequiredPropertiesError
+		require.True(t, errors.As(err, &missingErr))
+		assert.Equal(t, []MissingPropertyField{{ID: "severity", Name: "Severity"}}, missingErr.Fields)
+	})
+
+	t.Run("fields required on finish are not needed to start", func(t *testing.T) {
+		run := &PlaybookRun{PlaybookID: pbID, OwnerUserID: "owner"}
+		err := svc.ResolveRunCreationParams(run, pb, map[string]json.RawMessage{"severity": json.RawMessage(`"high"`)}, RunSourcePost)
+
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// Types of the changes recorded in the run change feed.
+const (
+	RunChangeCreated       = "run_created"
+	RunChangeUpdated       = "run_updated"
+	RunChangeFinished      = "run_finished"
+	RunChangeChecklistItem = "checklist_item_changed"
+	RunChangeProperty      = "property_changed"
+)
+
+const (
+	// RunChangeRetention is how long changes are kept in the run change feed.
+	RunChangeRetention = 7 * 24 * time.Hour
+
+	// runChangePruneInterval is how often a server prunes changes older than RunChangeRetention.
+	runChangePruneInterval = time.Hour
+
+	// runChangeSettleDelay holds back the most recent changes of the feed. Cursors are allocated
+	// when a change is inserted, so a change committed after a concurrent one may get a lower
+	// cursor; waiting for concurrent inserts to commit keeps subscribers from skipping it.
+	runChangeSettleDelay = time.Second
+)
+
+// RunChange is an entry of the run change feed. Cursor increases with every change and lets
+// subscribers resume the feed after the last change they received.
+type RunChange struct {
+	Cursor        int64  `json:"cursor"`
+	Type          string `json:"type"`
+	PlaybookRunID string `json:"playbook_run_id"`
+	TeamID        string `json:"team_id"`
+	ChannelID     string `json:"channel_id"`
+
+	// Payload depends on Type: the run for run_created, a PlaybookRunUpdate for run_updated and
+	// run_finished, a ChecklistItemChange or a PropertyChange for the other types.
+	Payload json.RawMessage `json:"payload"`
+
+	CreateAt int64 `json:"create_at"`
+}
+
+// RunChangeOptions filters the run change feed.
+type RunChangeOptions struct {
+	// After returns only the changes with a greater cursor.
+	After int64
+
+	// TeamID restricts the feed to the runs of a team. Empty returns every team.
+	TeamID string
+
+	// PlaybookRunID restricts the feed to a single run. Empty returns every run.
+	PlaybookRunID string
+
+	// Until excludes changes made after this time, in milliseconds. 0 means no limit.
+	Until int64
+
+	// Limit is the maximum number of changes returned. 0 means no limit.
+	Limit int
+}
+
+// ChecklistItemChange is the payload of a checklist_item_changed change, listing the changes to
+// the items of one checklist.
+type ChecklistItemChange struct {
+	PlaybookRunID string                `json:"playbook_run_id"`
+	ChecklistID   string                `json:"checklist_id"`
+	ItemUpdates   []ChecklistItemUpdate `json:"item_updates,omitempty"`
+	ItemInserts   []ChecklistItem       `json:"item_inserts,omitempty"`
+	ItemDeletes   []string              `json:"item_deletes,omitempty"`
+	ItemsOrder    []string              `json:"items_order,omitempty"`
+}
+
+// PropertyChange is the payload of a property_changed change, holding the run's property fields
+// and values after the change.
+type PropertyChange struct {
+	PlaybookRunID  string          `json:"playbook_run_id"`
+	PropertyFields []PropertyField `json:"property_fields,omitempty"`
+	PropertyValues []PropertyValue `json:"property_values,omitempty"`
+}
+
+// NewRunChanges builds the entries of the change feed for an update of a run: a run_updated
+// change with the update, followed by the changes derived from it.
+func NewRunChanges(run *PlaybookRun, update PlaybookRunUpdate) ([]RunChange, error) {
+	now := model.GetMillis()
+	var changes []RunChange
+	add := func(changeType string, payload any) error {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s change", changeType)
+		}
+		changes = append(changes, RunChange{
+			Type:          changeType,
+			PlaybookRunID: run.ID,
+			TeamID:        run.TeamID,
+			ChannelID:     run.ChannelID,
+			Payload:       data,
+			CreateAt:      now,
+		})
+		return nil
+	}
+
+	if err := add(RunChangeUpdated, update); err != nil {
+		return nil, err
+	}
+
+	if status, ok := update.ChangedFields["current_status"].(string); ok && status == StatusFinished {
+		if err := add(RunChangeFinished, update); err != nil {
+			return nil, err
+		}
+	}
+
+	if checklistUpdates, ok := update.ChangedFields["checklists"].([]ChecklistUpdate); ok {
+		for _, checklistUpdate := range checklistUpdates {
+			if len(checklistUpdate.ItemUpdates) == 0 && len(checklistUpdate.ItemInserts) == 0 &&
+				len(checklistUpdate.ItemDeletes) == 0 && len(checklistUpdate.ItemsOrder) == 0 {
+				continue
+			}
+			if err := add(RunChangeChecklistItem, ChecklistItemChange{
+				PlaybookRunID: run.ID,
+				ChecklistID:   checklistUpdate.ID,
+				ItemUpdates:   checklistUpdate.ItemUpdates,
+				ItemInserts:   checklistUpdate.ItemInserts,
+				ItemDeletes:   checklistUpdate.ItemDeletes,
+				ItemsOrder:    checklistUpdate.ItemsOrder,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, fieldsChanged := update.ChangedFields["property_fields"]
+	_, valuesChanged := update.ChangedFields["property_values"]
+	if fieldsChanged || valuesChanged {
+		if err := add(RunChangeProperty, PropertyChange{
+			PlaybookRunID:  run.ID,
+			PropertyFields: run.PropertyFields,
+			PropertyValues: run.PropertyValues,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// shouldDiffRunUpdates returns true when run updates need the state of the run before the
+// update, to send incremental websocket updates or to record the update in the change feed.
+func (s *PlaybookRunServiceImpl) shouldDiffRunUpdates() bool {
+	return s.configService.IsIncrementalUpdatesEnabled() || s.configService.IsRunChangeFeedEnabled()
+}
+
+// recordRunCreated records the creation of a run in the change feed, when enabled.
+func (s *PlaybookRunServiceImpl) recordRunCreated(run *PlaybookRun) {
+	if !s.configService.IsRunChangeFeedEnabled() {
+		return
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		logrus.WithError(err).WithField("playbook_run_id", run.ID).Error("failed to marshal created run for the change feed")
+		return
+	}
+
+	s.storeRunChanges([]RunChange{{
+		Type:          RunChangeCreated,
+		PlaybookRunID: run.ID,
+		TeamID:        run.TeamID,
+		ChannelID:     run.ChannelID,
+		Payload:       data,
+		CreateAt:      model.GetMillis(),
+	}})
+}
+
+// recordRunUpdate records an update of a run in the change feed, when enabled.
+func (s *PlaybookRunServiceImpl) recordRunUpdate(run *PlaybookRun, update PlaybookRunUpdate) {
+	if !s.configService.IsRunChangeFeedEnabled() {
+		return
+	}
+
+	changes, err := NewRunChanges(run, update)
+	if err != nil {
+		logrus.WithError(err).WithField("playbook_run_id", run.ID).Error("failed to build run changes for the change feed")
+		return
+	}
+
+	s.storeRunChanges(changes)
+}
+
+// storeRunChanges appends changes to the feed and, at most once per runChangePruneInterval,
+// prunes the changes that are past retention. Failures are logged: the feed is best effort and
+// must not fail the update it records.
+func (s *PlaybookRunServiceImpl) storeRunChanges(changes []RunChange) {
+	if err := s.store.CreateRunChanges(changes); err != nil {
+		logrus.WithError(err).Error("failed to record run changes")
+		return
+	}
+
+	now := time.Now()
+	lastPrune := s.lastRunChangePrune.Load()
+	if now.Sub(time.UnixMilli(lastPrune)) < runChangePruneInterval || !s.lastRunChangePrune.CompareAndSwap(lastPrune, now.UnixMilli()) {
+		return
+	}
+	if err := s.store.DeleteRunChangesBefore(now.Add(-RunChangeRetention).UnixMilli()); err != nil {
+		logrus.WithError(err).Warn("failed to prune run change feed")
+	}
+}
+
+// GetRunChanges returns the changes of the feed matching options, oldest first. The most recent
+// changes are held back until concurrent inserts have settled.
+func (s *PlaybookRunServiceImpl) GetRunChanges(options RunChangeOptions) ([]RunChange, error) {
+	until := model.GetMillis() - runChangeSettleDelay.Milliseconds()
+	if options.Until == 0 || options.Until > until {
+		options.Until = until
+	}
+
+	return s.store.GetRunChanges(options)
+}
+
+// GetLatestRunChangeCursor returns the cursor of the most recent change of the feed, 0 when empty.
+func (s *PlaybookRunServiceImpl) GetLatestRunChangeCursor() (int64, error) {
+	return s.store.GetLatestRunChangeCursor()
+}
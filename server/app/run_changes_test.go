@@ -0,0 +1,96 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRunChanges(t *testing.T) {
+	previous := &PlaybookRun{
+		ID:            "run_id",
+		TeamID:        "team_id",
+		ChannelID:     "channel_id",
+		CurrentStatus: StatusInProgress,
+		Checklists: []Checklist{{
+			ID:    "checklist_id",
+			Title: "Triage",
+			Items: []ChecklistItem{{ID: "item_id", Title: "Page on-call", State: ChecklistItemStateOpen}},
+		}},
+	}
+
+	t.Run("scalar update", func(t *testing.T) {
+		current := previous.Clone()
+		current.Name = "Renamed"
+
+		update, ok := newPlaybookRunUpdate(previous, current)
+		require.True(t, ok)
+		changes, err := NewRunChanges(current, update)
+		require.NoError(t, err)
+
+		require.Len(t, changes, 1)
+		assert.Equal(t, RunChangeUpdated, changes[0].Type)
+		assert.Equal(t, "run_id", changes[0].PlaybookRunID)
+		assert.Equal(t, "team_id", changes[0].TeamID)
+		assert.Equal(t, "channel_id", changes[0].ChannelID)
+		assert.NotZero(t, changes[0].CreateAt)
+
+		var payload PlaybookRunUpdate
+		require.NoError(t, json.Unmarshal(changes[0].Payload, &payload))
+		assert.Equal(t, "run_id", payload.ID)
+		assert.Equal(t, map[string]interface{}{"name": "Renamed"}, payload.ChangedFields)
+	})
+
+	t.Run("finish with a checklist item change", func(t *testing.T) {
+		current := previous.Clone()
+		current.CurrentStatus = StatusFinished
+		current.EndAt = 1000
+		current.Checklists[0].Items[0].State = ChecklistItemStateClosed
+
+		update, ok := newPlaybookRunUpdate(previous, current)
+		require.True(t, ok)
+		changes, err := NewRunChanges(current, update)
+		require.NoError(t, err)
+
+		require.Len(t, changes, 3)
+		assert.Equal(t, RunChangeUpdated, changes[0].Type)
+		assert.Equal(t, RunChangeFinished, changes[1].Type)
+		assert.Equal(t, RunChangeChecklistItem, changes[2].Type)
+
+		var itemChange ChecklistItemChange
+		require.NoError(t, json.Unmarshal(changes[2].Payload, &itemChange))
+		assert.Equal(t, "run_id", itemChange.PlaybookRunID)
+		assert.Equal(t, "checklist_id", itemChange.ChecklistID)
+		require.Len(t, itemChange.ItemUpdates, 1)
+		assert.Equal(t, "item_id", itemChange.ItemUpdates[0].ID)
+		assert.Equal(t, ChecklistItemStateClosed, itemChange.ItemUpdates[0].Fields["state"])
+	})
+
+	t.Run("property value change", func(t *testing.T) {
+		current := previous.Clone()
+		current.PropertyValues = []PropertyValue{{FieldID: "field_id", Value: json.RawMessage(`"sev1"`)}}
+
+		update, ok := newPlaybookRunUpdate(previous, current)
+		require.True(t, ok)
+		changes, err := NewRunChanges(current, update)
+		require.NoError(t, err)
+
+		require.Len(t, changes, 2)
+		assert.Equal(t, RunChangeProperty, changes[1].Type)
+
+		var propertyChange PropertyChange
+		require.NoError(t, json.Unmarshal(changes[1].Payload, &propertyChange))
+		require.Len(t, propertyChange.PropertyValues, 1)
+		assert.Equal(t, "field_id", propertyChange.PropertyValues[0].FieldID)
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		_, ok := newPlaybookRunUpdate(previous, previous.Clone())
+		assert.False(t, ok)
+	})
+}
@@ -18,7 +18,7 @@ import (
 	"github.com/mattermost/mattermost-plugin-playbooks/server/metrics"
 )
 
-func makePlaybookService(ctrl *gomock.Controller) (app.PlaybookService, *mock_app.MockPlaybookStore, *mock_app.MockPropertyService) {
+func makePlaybookService(ctrl *gomock.Controller) (app.PlaybookService, *mock_app.MockPlaybookStore, *mock_app.MockPropertyService, *mock_app.MockConditionService) {
 	mockStore := mock_app.NewMockPlaybookStore(ctrl)
 	mockPoster := mock_bot.NewMockPoster(ctrl)
 	mockPropertyService := mock_app.NewMockPropertyService(ctrl)
@@ -36,11 +36,11 @@ func makePlaybookService(ctrl *gomock.Controller) (app.PlaybookService, *mock_ap
 		mockPropertyService,
 		mockConditionService,
 	)
-	return svc, mockStore, mockPropertyService
+	return svc, mockStore, mockPropertyService, mockConditionService
 }
 
 func TestPlaybookService_UpdateRunNumberPrefixMutable(t *testing.T) {
-	makeService := func(ctrl *gomock.Controller) (app.PlaybookService, *mock_app.MockPlaybookStore, *mock_bot.MockPoster) {
+	makeService := func(ctrl *gomock.Controller) (app.PlaybookService, *mock_app.MockPlaybookStore, *mock_app.MockPropertyService, *mock_app.MockConditionService) {
 		mockStore := mock_app.NewMockPlaybookStore(ctrl)
 		mockPoster := mock_bot.NewMockPoster(ctrl)
 		mockPropertyService := mock_app.NewMockPropertyService(ctrl)
@@ -58,7 +58,7 @@ func TestPlaybookService_UpdateRunNumberPrefixMutable(t *testing.T) {
 			mockPropertyService,
 			mockConditionService,
 		)
-		return svc, mockStore, mockPoster
+		return svc, mockStore, mockPropertyService, mockConditionService
 	}
 
 	basePlaybook := app.Playbook{
@@ -72,13 +72,14 @@ func TestPlaybookService_UpdateRunNumberPrefixMutable(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore, _ := makeService(ctrl)
+		svc, mockStore, mockPropertyService, mockConditionService := makeService(ctrl)
 
 		updated := basePlaybook
 		updated.RunNumberPrefix = "CHANGED"
 
 		mockStore.EXPECT().IsRunNumberPrefixUsed("team1", "CHANGED", "pb1").Return(false, nil)
-		mockStore.EXPECT().Update(gomock.Any()).Return(nil)
+		mockStore.EXPECT().UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).Return(nil)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, "pb1")
 
 		err := svc.Update(updated, "user1")
 		require.NoError(t, err)
@@ -86,7 +87,7 @@ func TestPlaybookService_UpdateRunNumberPrefixMutable(t *testing.T) {
 }
 
 func TestRunNumberPrefixUniqueness(t *testing.T) {
-	makeService := func(ctrl *gomock.Controller) (app.PlaybookService, *mock_app.MockPlaybookStore) {
+	makeService := func(ctrl *gomock.Controller) (app.PlaybookService, *mock_app.MockPlaybookStore, *mock_app.MockPropertyService, *mock_app.MockConditionService) {
 		mockStore := mock_app.NewMockPlaybookStore(ctrl)
 		mockPoster := mock_bot.NewMockPoster(ctrl)
 		mockPropertyService := mock_app.NewMockPropertyService(ctrl)
@@ -104,14 +105,14 @@ func TestRunNumberPrefixUniqueness(t *testing.T) {
 			mockPropertyService,
 			mockConditionService,
 		)
-		return svc, mockStore
+		return svc, mockStore, mockPropertyService, mockConditionService
 	}
 
 	t.Run("duplicate prefix on same team is rejected with ErrDuplicateEntry", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore := makeService(ctrl)
+		svc, mockStore, _, _ := makeService(ctrl)
 
 		playbookB := app.Playbook{
 			ID:              "pb2",
@@ -131,7 +132,7 @@ func TestRunNumberPrefixUniqueness(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore := makeService(ctrl)
+		svc, mockStore, mockPropertyService, mockConditionService := makeService(ctrl)
 
 		playbookC := app.Playbook{
 			ID:              "pb3",
@@ -141,7 +142,8 @@ func TestRunNumberPrefixUniqueness(t *testing.T) {
 		}
 
 		mockStore.EXPECT().IsRunNumberPrefixUsed("team2", "INC", "pb3").Return(false, nil)
-		mockStore.EXPECT().Update(gomock.Any()).Return(nil)
+		mockStore.EXPECT().UpdateWithRevision(gomock.Any(), int64(0), gomock.Any()).Return(nil)
+		expectRevisionSnapshot(mockStore, mockPropertyService, mockConditionService, "pb3")
 
 		err := svc.Update(playbookC, "user1")
 		require.NoError(t, err)
@@ -259,10 +261,11 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore, mockPropertyService := makePlaybookService(ctrl)
+		svc, mockStore, mockPropertyService, mockConditionService := makePlaybookService(ctrl)
 
 		mockPropertyService.EXPECT().GetPropertyFields("pb1").Return(nil, nil)
 		mockStore.EXPECT().UpdateChannelNameTemplate("pb1", "Incident - Run").Return(nil)
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, "pb1")
 
 		err := svc.UpdateChannelNameTemplate("pb1", "Incident - Run", "user1")
 		require.NoError(t, err)
@@ -272,11 +275,12 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore, mockPropertyService := makePlaybookService(ctrl)
+		svc, mockStore, mockPropertyService, mockConditionService := makePlaybookService(ctrl)
 
 		fields := []app.PropertyField{{PropertyField: model.PropertyField{ID: "f1", Name: "Zone"}}}
 		mockPropertyService.EXPECT().GetPropertyFields("pb1").Return(fields, nil)
 		mockStore.EXPECT().UpdateChannelNameTemplate("pb1", "{Zone} - Incident").Return(nil)
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, "pb1")
 
 		err := svc.UpdateChannelNameTemplate("pb1", "{Zone} - Incident", "user1")
 		require.NoError(t, err)
@@ -286,7 +290,7 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, _, mockPropertyService := makePlaybookService(ctrl)
+		svc, _, mockPropertyService, _ := makePlaybookService(ctrl)
 
 		mockPropertyService.EXPECT().GetPropertyFields("pb1").Return(nil, nil)
 
@@ -299,7 +303,7 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, _, _ := makePlaybookService(ctrl)
+		svc, _, _, _ := makePlaybookService(ctrl)
 
 		longTemplate := string(make([]byte, app.MaxChannelNameTemplateLength+1))
 		err := svc.UpdateChannelNameTemplate("pb1", longTemplate, "user1")
@@ -311,10 +315,11 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore, mockPropertyService := makePlaybookService(ctrl)
+		svc, mockStore, mockPropertyService, mockConditionService := makePlaybookService(ctrl)
 
 		mockPropertyService.EXPECT().GetPropertyFields("pb1").Return(nil, nil)
 		mockStore.EXPECT().UpdateChannelNameTemplate("pb1", "").Return(nil)
+		expectRevisionRecorded(mockStore, mockPropertyService, mockConditionService, "pb1")
 
 		err := svc.UpdateChannelNameTemplate("pb1", "", "user1")
 		require.NoError(t, err)
@@ -324,7 +329,7 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, _, mockPropertyService := makePlaybookService(ctrl)
+		svc, _, mockPropertyService, _ := makePlaybookService(ctrl)
 
 		mockPropertyService.EXPECT().GetPropertyFields("pb1").Return(nil, errors.New("db error"))
 
@@ -336,7 +341,7 @@ func TestPlaybookService_UpdateChannelNameTemplate(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		svc, mockStore, mockPropertyService := makePlaybookService(ctrl)
+		svc, mockStore, mockPropertyService, _ := makePlaybookService(ctrl)
 
 		mockPropertyService.EXPECT().GetPropertyFields("pb1").Return(nil, nil)
 		mockStore.EXPECT().UpdateChannelNameTemplate("pb1", "Incident").Return(errors.New("update failed"))
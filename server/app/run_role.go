@@ -0,0 +1,197 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// Permissions a run role can grant. Once any role of a run grants a permission, only the run
+// owner, system admins and the holders of such a role may use it; permissions no role grants
+// stay open to every participant.
+const (
+	RunRolePermissionPostStatusUpdates = "post_status_updates"
+	RunRolePermissionEditChecklists    = "edit_checklists"
+	RunRolePermissionFinishRun         = "finish_run"
+	RunRolePermissionManageProperties  = "manage_properties"
+)
+
+const (
+	MaxRunRolesPerPlaybook = 20
+	MaxRunRoleNameLength   = 64
+)
+
+// RunRole is a role such as Incident Commander or Scribe. Roles are defined on a playbook,
+// copied to each of its runs, and assigned to a user per run.
+type RunRole struct {
+	// ID identifies the role within its playbook and the runs created from it. Checklist items
+	// assigned to the role reference it, so it is kept on export.
+	ID          string   `json:"id" export:"id"`
+	Name        string   `json:"name" export:"name"`
+	Permissions []string `json:"permissions" export:"permissions"`
+
+	// UserID is the user holding the role in a run. It is always empty on playbooks.
+	UserID string `json:"user_id" export:"-"`
+}
+
+// IsValidRunRolePermission returns true for the permissions a run role can grant.
+func IsValidRunRolePermission(permission string) bool {
+	return permission == RunRolePermissionPostStatusUpdates ||
+		permission == RunRolePermissionEditChecklists ||
+		permission == RunRolePermissionFinishRun ||
+		permission == RunRolePermissionManageProperties
+}
+
+// ValidatePlaybookRunRoles checks that the run roles of a playbook have unique, non-empty names
+// and only grant known permissions, and that checklist items assigned to a role reference one of them.
+func ValidatePlaybookRunRoles(playbook Playbook) error {
+	roles := playbook.RunRoles
+	if len(roles) > MaxRunRolesPerPlaybook {
+		return errors.Errorf("playbook cannot have more than %d run roles", MaxRunRolesPerPlaybook)
+	}
+
+	names := make(map[string]bool, len(roles))
+	ids := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		name := strings.TrimSpace(role.Name)
+		if name == "" {
+			return errors.New("run role name must not be empty")
+		}
+		if utf8.RuneCountInString(name) > MaxRunRoleNameLength {
+			return errors.Errorf("run role name must be at most %d characters", MaxRunRoleNameLength)
+		}
+		if names[strings.ToLower(name)] {
+			return errors.Errorf("duplicate run role name %q", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		if role.ID != "" {
+			if ids[role.ID] {
+				return errors.Errorf("duplicate run role id %q", role.ID)
+			}
+			ids[role.ID] = true
+		}
+
+		for _, permission := range role.Permissions {
+			if !IsValidRunRolePermission(permission) {
+				return errors.Errorf("invalid permission %q for run role %q", permission, name)
+			}
+		}
+	}
+
+	for _, checklist := range playbook.Checklists {
+		for _, item := range checklist.Items {
+			if item.AssigneeType == AssigneeTypeRunRole && !ids[item.AssigneeRoleID] {
+				return errors.Errorf("checklist item %q is assigned to unknown run role %q", item.Title, item.AssigneeRoleID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizePlaybookRunRoles trims role names, drops duplicate permissions, assigns IDs to new
+// roles and clears holders, which only exist on runs.
+func normalizePlaybookRunRoles(roles []RunRole) []RunRole {
+	normalized := make([]RunRole, 0, len(roles))
+	for _, role := range roles {
+		role.Name = strings.TrimSpace(role.Name)
+		if role.ID == "" {
+			role.ID = model.NewId()
+		}
+		role.UserID = ""
+
+		permissions := make([]string, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+		role.Permissions = permissions
+
+		normalized = append(normalized, role)
+	}
+	return normalized
+}
+
+// cloneRunRoles returns a deep copy of the given roles.
+func cloneRunRoles(roles []RunRole) []RunRole {
+	if roles == nil {
+		return nil
+	}
+	cloned := make([]RunRole, 0, len(roles))
+	for _, role := range roles {
+		role.Permissions = append([]string(nil), role.Permissions...)
+		cloned = append(cloned, role)
+	}
+	return cloned
+}
+
+// GetRunRole returns the role of the run with the given ID, or nil if there is none.
+func (r *PlaybookRun) GetRunRole(roleID string) *RunRole {
+	for i := range r.RunRoles {
+		if r.RunRoles[i].ID == roleID {
+			return &r.RunRoles[i]
+		}
+	}
+	return nil
+}
+
+// RunRolesRestrict returns true if any role of the run grants the given permission, in which
+// case only the owner, system admins and the holders of those roles may use it.
+func (r *PlaybookRun) RunRolesRestrict(permission string) bool {
+	for _, role := range r.RunRoles {
+		if slices.Contains(role.Permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRunRolePermission returns true if userID holds a role of the run granting the given permission.
+func (r *PlaybookRun) HasRunRolePermission(userID, permission string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, role := range r.RunRoles {
+		if role.UserID == userID && slices.Contains(role.Permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRunRoleAssignments points items assigned to a run role at the user currently holding it.
+// Returns true if any item changed.
+func resolveRunRoleAssignments(checklists []Checklist, roles []RunRole) bool {
+	holders := make(map[string]string, len(roles))
+	for _, role := range roles {
+		holders[role.ID] = role.UserID
+	}
+
+	anyChanged := false
+	now := model.GetMillis()
+	for ci := range checklists {
+		for ii := range checklists[ci].Items {
+			item := &checklists[ci].Items[ii]
+			if item.AssigneeType != AssigneeTypeRunRole {
+				continue
+			}
+			holder := holders[item.AssigneeRoleID]
+			if item.AssigneeID != holder {
+				item.AssigneeID = holder
+				item.AssigneeModified = now
+				updateChecklistAndItemTimestamp(&checklists[ci], item, now)
+				anyChanged = true
+			}
+		}
+	}
+	return anyChanged
+}
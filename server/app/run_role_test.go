@@ -0,0 +1,132 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePlaybookRunRoles(t *testing.T) {
+	t.Run("no roles is valid", func(t *testing.T) {
+		require.NoError(t, ValidatePlaybookRunRoles(Playbook{}))
+	})
+
+	t.Run("valid roles and assignment", func(t *testing.T) {
+		playbook := Playbook{
+			RunRoles: []RunRole{
+				{ID: "commander", Name: "Incident Commander", Permissions: []string{RunRolePermissionFinishRun}},
+				{ID: "scribe", Name: "Scribe", Permissions: []string{RunRolePermissionPostStatusUpdates}},
+			},
+			Checklists: []Checklist{{Items: []ChecklistItem{
+				{Title: "Write notes", AssigneeType: AssigneeTypeRunRole, AssigneeRoleID: "scribe"},
+			}}},
+		}
+		require.NoError(t, ValidatePlaybookRunRoles(playbook))
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		err := ValidatePlaybookRunRoles(Playbook{RunRoles: []RunRole{{ID: "a", Name: "  "}}})
+		require.Error(t, err)
+	})
+
+	t.Run("name too long", func(t *testing.T) {
+		err := ValidatePlaybookRunRoles(Playbook{RunRoles: []RunRole{{ID: "a", Name: strings.Repeat("x", MaxRunRoleNameLength+1)}}})
+		require.Error(t, err)
+	})
+
+	t.Run("duplicate names differing in case", func(t *testing.T) {
+		err := ValidatePlaybookRunRoles(Playbook{RunRoles: []RunRole{{ID: "a", Name: "Scribe"}, {ID: "b", Name: "scribe"}}})
+		require.Error(t, err)
+	})
+
+	t.Run("duplicate ids", func(t *testing.T) {
+		err := ValidatePlaybookRunRoles(Playbook{RunRoles: []RunRole{{ID: "a", Name: "One"}, {ID: "a", Name: "Two"}}})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown permission", func(t *testing.T) {
+		err := ValidatePlaybookRunRoles(Playbook{RunRoles: []RunRole{{ID: "a", Name: "One", Permissions: []string{"delete_everything"}}}})
+		require.Error(t, err)
+	})
+
+	t.Run("too many roles", func(t *testing.T) {
+		roles := make([]RunRole, MaxRunRolesPerPlaybook+1)
+		for i := range roles {
+			roles[i] = RunRole{Name: strings.Repeat("r", i+1)}
+		}
+		require.Error(t, ValidatePlaybookRunRoles(Playbook{RunRoles: roles}))
+	})
+
+	t.Run("item assigned to unknown role", func(t *testing.T) {
+		playbook := Playbook{
+			RunRoles: []RunRole{{ID: "scribe", Name: "Scribe"}},
+			Checklists: []Checklist{{Items: []ChecklistItem{
+				{Title: "Write notes", AssigneeType: AssigneeTypeRunRole, AssigneeRoleID: "missing"},
+			}}},
+		}
+		require.Error(t, ValidatePlaybookRunRoles(playbook))
+	})
+}
+
+func TestNormalizePlaybookRunRoles(t *testing.T) {
+	roles := normalizePlaybookRunRoles([]RunRole{
+		{ID: "keep", Name: " Scribe ", UserID: "user", Permissions: []string{RunRolePermissionEditChecklists, RunRolePermissionEditChecklists}},
+		{Name: "Lead"},
+	})
+
+	require.Len(t, roles, 2)
+	assert.Equal(t, "keep", roles[0].ID)
+	assert.Equal(t, "Scribe", roles[0].Name)
+	assert.Empty(t, roles[0].UserID)
+	assert.Equal(t, []string{RunRolePermissionEditChecklists}, roles[0].Permissions)
+	assert.NotEmpty(t, roles[1].ID)
+}
+
+func TestResolveRunRoleAssignments(t *testing.T) {
+	checklists := []Checklist{{Items: []ChecklistItem{
+		{ID: "a", AssigneeType: AssigneeTypeRunRole, AssigneeRoleID: "scribe"},
+		{ID: "b", AssigneeType: AssigneeTypeRunRole, AssigneeRoleID: "lead"},
+		{ID: "c", AssigneeID: "someone"},
+	}}}
+	roles := []RunRole{
+		{ID: "scribe", Name: "Scribe", UserID: "scribe-user"},
+		{ID: "lead", Name: "Lead"},
+	}
+
+	require.True(t, resolveRunRoleAssignments(checklists, roles))
+	assert.Equal(t, "scribe-user", checklists[0].Items[0].AssigneeID)
+	assert.NotZero(t, checklists[0].Items[0].AssigneeModified)
+	assert.Empty(t, checklists[0].Items[1].AssigneeID)
+	assert.Equal(t, "someone", checklists[0].Items[2].AssigneeID)
+
+	// Resolving again without changes is a no-op.
+	require.False(t, resolveRunRoleAssignments(checklists, roles))
+
+	// Unassigning the role clears its items.
+	roles[0].UserID = ""
+	require.True(t, resolveRunRoleAssignments(checklists, roles))
+	assert.Empty(t, checklists[0].Items[0].AssigneeID)
+}
+
+func TestRunRolePermissionHelpers(t *testing.T) {
+	run := &PlaybookRun{RunRoles: []RunRole{
+		{ID: "scribe", Name: "Scribe", UserID: "scribe-user", Permissions: []string{RunRolePermissionPostStatusUpdates}},
+		{ID: "lead", Name: "Lead", Permissions: []string{RunRolePermissionFinishRun}},
+	}}
+
+	assert.True(t, run.RunRolesRestrict(RunRolePermissionPostStatusUpdates))
+	assert.True(t, run.RunRolesRestrict(RunRolePermissionFinishRun))
+	assert.False(t, run.RunRolesRestrict(RunRolePermissionEditChecklists))
+
+	assert.True(t, run.HasRunRolePermission("scribe-user", RunRolePermissionPostStatusUpdates))
+	assert.False(t, run.HasRunRolePermission("scribe-user", RunRolePermissionFinishRun))
+	assert.False(t, run.HasRunRolePermission("", RunRolePermissionFinishRun), "unassigned roles grant nothing")
+
+	require.NotNil(t, run.GetRunRole("lead"))
+	assert.Nil(t, run.GetRunRole("missing"))
+}
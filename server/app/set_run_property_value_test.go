@@ -114,9 +114,15 @@ func (s *stubRunStoreGetOnly) CreatePlaybookRun(_ *PlaybookRun) (*PlaybookRun, e
 func (s *stubRunStoreGetOnly) UpdatePlaybookRun(_ *PlaybookRun) (*PlaybookRun, error) {
 	panic("not implemented")
 }
+func (s *stubRunStoreGetOnly) UpdatePlaybookRunIfUnmodified(_ *PlaybookRun, _ int64) (*PlaybookRun, error) {
+	panic("not implemented")
+}
 func (s *stubRunStoreGetOnly) GraphqlUpdate(_ string, _ map[string]interface{}) error {
 	panic("not implemented")
 }
+func (s *stubRunStoreGetOnly) GraphqlUpdateIfUnmodified(_ string, _ map[string]interface{}, _ int64) error {
+	panic("not implemented")
+}
 func (s *stubRunStoreGetOnly) UpdateStatus(_ *SQLStatusPost) error { panic("not implemented") }
 func (s *stubRunStoreGetOnly) FinishPlaybookRun(_ string, _ int64) error {
 	panic("not implemented")
@@ -131,6 +137,21 @@ func (s *stubRunStoreGetOnly) CreateTimelineEvent(_ *TimelineEvent) (*TimelineEv
 	panic("not implemented")
 }
 func (s *stubRunStoreGetOnly) UpdateTimelineEvent(_ *TimelineEvent) error { panic("not implemented") }
+func (s *stubRunStoreGetOnly) CreatePropertyValueChange(_ PropertyValueChange) (PropertyValueChange, error) {
+	panic("not implemented")
+}
+func (s *stubRunStoreGetOnly) GetPropertyValueChanges(_ string, _ PropertyValueChangeOptions) ([]PropertyValueChange, error) {
+	panic("not implemented")
+}
+func (s *stubRunStoreGetOnly) CreateRunChanges(_ []RunChange) error { panic("not implemented") }
+func (s *stubRunStoreGetOnly) GetRunChanges(_ RunChangeOptions) ([]RunChange, error) {
+	panic("not implemented")
+}
+func (s *stubRunStoreGetOnly) GetLatestRunChangeCursor() (int64, error) { panic("not implemented") }
+func (s *stubRunStoreGetOnly) DeleteRunChangesBefore(_ int64) error     { panic("not implemented") }
+func (s *stubRunStoreGetOnly) GetSharedPropertyFieldStats(RequesterInfo, PlaybookRunFilterOptions) (*SharedPropertyFieldStats, error) {
+	panic("not implemented")
+}
 func (s *stubRunStoreGetOnly) GetPlaybookRunIDsForChannel(_ string) ([]string, error) {
 	panic("not implemented")
 }
@@ -0,0 +1,590 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxPropertiesPerTeam is the maximum number of property fields a team can share between its
+// playbooks.
+const MaxPropertiesPerTeam = 50
+
+// SharedPropertyValueCount is the number of runs having a value of a team property field.
+type SharedPropertyValueCount struct {
+	// Value is the option name for select fields, the value itself otherwise.
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SharedPropertyFieldStats summarizes the values of a team property field across the runs of
+// every playbook using it.
+type SharedPropertyFieldStats struct {
+	FieldID string `json:"field_id"`
+
+	// TotalRuns is the number of runs having the field, whether set or not.
+	TotalRuns int                        `json:"total_runs"`
+	Values    []SharedPropertyValueCount `json:"values"`
+}
+
+// SharedPropertyMergeSkip explains why a playbook field could not be linked to the team field.
+type SharedPropertyMergeSkip struct {
+	PlaybookID string `json:"playbook_id"`
+	FieldID    string `json:"field_id"`
+	Reason     string `json:"reason"`
+}
+
+// SharedPropertyMergeResult is the outcome of merging same-named playbook fields into a team
+// field.
+type SharedPropertyMergeResult struct {
+	Field        PropertyField             `json:"field"`
+	LinkedFields []PropertyField           `json:"linked_fields"`
+	Skipped      []SharedPropertyMergeSkip `json:"skipped"`
+}
+
+// IsShared returns true if the field is linked to a team property field.
+func (p *PropertyField) IsShared() bool {
+	return p.Attrs.SharedFieldID != ""
+}
+
+// linkPropertyField returns playbookField redefined by teamField. The playbook field keeps its
+// identity, visibility and sort order; options keep the IDs of the team field so values can be
+// compared across playbooks.
+func linkPropertyField(teamField, playbookField PropertyField) PropertyField {
+	linked := playbookField
+	linked.Name = teamField.Name
+	linked.Type = teamField.Type
+
+	attrs := teamField.Attrs
+	attrs.Options = cloneOptions(teamField.Attrs.Options)
+	attrs.Visibility = playbookField.Attrs.Visibility
+	attrs.SortOrder = playbookField.Attrs.SortOrder
+	attrs.ParentID = ""
+	attrs.SharedFieldID = teamField.ID
+	linked.Attrs = attrs
+
+	return linked
+}
+
+// sameSharedDefinition returns true if the update leaves the parts of a linked field that are
+// managed by its team untouched. Option IDs are not compared, the existing ones being kept.
+func sameSharedDefinition(existing, updated PropertyField) bool {
+	if existing.Name != updated.Name || existing.Type != updated.Type {
+		return false
+	}
+	if len(existing.Attrs.Options) != len(updated.Attrs.Options) {
+		return false
+	}
+	for i, option := range existing.Attrs.Options {
+		other := updated.Attrs.Options[i]
+		if option.GetName() != other.GetName() || option.GetValue("color") != other.GetValue("color") {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneOptions(options model.PropertyOptions[*model.PluginPropertyOption]) model.PropertyOptions[*model.PluginPropertyOption] {
+	if options == nil {
+		return nil
+	}
+
+	cloned := make(model.PropertyOptions[*model.PluginPropertyOption], 0, len(options))
+	for _, option := range options {
+		clone := model.NewPluginPropertyOption(option.GetID(), option.GetName())
+		if color := option.GetValue("color"); color != "" {
+			clone.SetValue("color", color)
+		}
+		cloned = append(cloned, clone)
+	}
+	return cloned
+}
+
+// mergeOptionsByName appends to options those of additional whose name is not used yet, keeping
+// their IDs. Names are compared case-insensitively. Returns the merged options and whether any
+// option was added.
+func mergeOptionsByName(options, additional model.PropertyOptions[*model.PluginPropertyOption]) (model.PropertyOptions[*model.PluginPropertyOption], bool) {
+	merged := cloneOptions(options)
+	added := false
+	for _, option := range additional {
+		if findOptionByName(merged, option.GetName()) != nil {
+			continue
+		}
+		clone := cloneOptions(model.PropertyOptions[*model.PluginPropertyOption]{option})
+		merged = append(merged, clone...)
+		added = true
+	}
+	return merged, added
+}
+
+func findOptionByName(options model.PropertyOptions[*model.PluginPropertyOption], name string) *model.PluginPropertyOption {
+	for _, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option.GetName()), strings.TrimSpace(name)) {
+			return option
+		}
+	}
+	return nil
+}
+
+// mostCommonFieldType returns the type shared by most fields, the first one seen on ties.
+func mostCommonFieldType(fields []PropertyField) model.PropertyFieldType {
+	counts := make(map[model.PropertyFieldType]int)
+	var best model.PropertyFieldType
+	for _, field := range fields {
+		counts[field.Type]++
+		if counts[field.Type] > counts[best] {
+			best = field.Type
+		}
+	}
+	return best
+}
+
+func (s *propertyService) CreateTeamPropertyField(teamID string, propertyField PropertyField) (*PropertyField, error) {
+	if err := validateReservedFieldName(propertyField.Name); err != nil {
+		return nil, err
+	}
+
+	propertyField.Attrs.ParentID = ""
+	propertyField.Attrs.SharedFieldID = ""
+	if err := propertyField.SanitizeAndValidate(); err != nil {
+		return nil, errors.Wrap(err, "invalid property field")
+	}
+	if propertyField.IsComputed() {
+		return nil, errors.New("invalid property field: computed fields cannot be shared")
+	}
+
+	currentCount, err := s.api.Property.CountPropertyFieldsForTarget(s.groupID, PropertyTargetTypeTeam, teamID, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count property fields for team")
+	}
+	if currentCount >= MaxPropertiesPerTeam {
+		return nil, errors.Errorf("cannot create property field: team already has the maximum allowed number of shared properties (%d)", MaxPropertiesPerTeam)
+	}
+
+	mmPropertyField := propertyField.ToMattermostPropertyField()
+	mmPropertyField.GroupID = s.groupID
+	mmPropertyField.TargetType = PropertyTargetTypeTeam
+	mmPropertyField.TargetID = teamID
+
+	createdField, err := s.api.Property.CreatePropertyField(mmPropertyField)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create team property field")
+	}
+
+	resultField, err := NewPropertyFieldFromMattermostPropertyField(createdField)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert created property field")
+	}
+
+	return resultField, nil
+}
+
+func (s *propertyService) GetTeamPropertyFields(teamID string) ([]PropertyField, error) {
+	mmPropertyFields, err := s.getAllPropertyFields(PropertyTargetTypeTeam, teamID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get team property fields")
+	}
+
+	propertyFields := make([]PropertyField, 0, len(mmPropertyFields))
+	for _, mmField := range mmPropertyFields {
+		propertyField, err := NewPropertyFieldFromMattermostPropertyField(mmField)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert property field")
+		}
+		propertyFields = append(propertyFields, *propertyField)
+	}
+
+	return propertyFields, nil
+}
+
+// getTeamPropertyField gets a team property field, returning ErrNotFound if it belongs to
+// something else.
+func (s *propertyService) getTeamPropertyField(teamID, propertyID string) (*PropertyField, error) {
+	field, err := s.GetPropertyField(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.TargetType != PropertyTargetTypeTeam || field.TargetID != teamID || field.DeleteAt != 0 {
+		return nil, errors.Wrapf(ErrNotFound, "property field %s does not belong to team %s", propertyID, teamID)
+	}
+
+	return field, nil
+}
+
+func (s *propertyService) UpdateTeamPropertyField(teamID string, propertyField PropertyField, playbookIDs []string) (*PropertyField, []string, error) {
+	if err := validateReservedFieldName(propertyField.Name); err != nil {
+		return nil, nil, err
+	}
+
+	propertyField.Attrs.ParentID = ""
+	propertyField.Attrs.SharedFieldID = ""
+	if err := propertyField.SanitizeAndValidate(); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid property field")
+	}
+	if propertyField.IsComputed() {
+		return nil, nil, errors.New("invalid property field: computed fields cannot be shared")
+	}
+
+	existingField, err := s.getTeamPropertyField(teamID, propertyField.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	linkedFields, err := s.getLinkedPropertyFields(propertyField.ID, playbookIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The linked fields share the options of the team field, so the conditions of every playbook
+	// using it must allow the change.
+	removedOptionIDs := s.findRemovedOptions(existingField.Attrs.Options, propertyField.Attrs.Options)
+	for _, linkedField := range linkedFields {
+		if existingField.Type != propertyField.Type {
+			if err := s.validatePropertyFieldTypeChange(existingField.ToMattermostPropertyField(), linkPropertyField(propertyField, linkedField), linkedField.TargetID); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if len(removedOptionIDs) == 0 {
+			continue
+		}
+		optionsInUse, err := s.conditionStore.CountConditionsUsingPropertyOptions(linkedField.TargetID, removedOptionIDs)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to check if property options are in use")
+		}
+		if len(optionsInUse) > 0 {
+			optionNames := s.getOptionNames(existingField.Attrs.Options, optionsInUse)
+			return nil, nil, errors.Wrapf(ErrPropertyOptionsInUse, "cannot remove property options: %s. Please remove or update the conditions of playbook %s before removing these options", optionNames, linkedField.TargetID)
+		}
+	}
+
+	mmPropertyField := propertyField.ToMattermostPropertyField()
+	mmPropertyField.GroupID = existingField.GroupID
+	mmPropertyField.TargetType = existingField.TargetType
+	mmPropertyField.TargetID = existingField.TargetID
+	mmPropertyField.CreateAt = existingField.CreateAt
+	mmPropertyField.UpdateAt = existingField.UpdateAt
+	mmPropertyField.DeleteAt = existingField.DeleteAt
+
+	updatedMMField, err := s.api.Property.UpdatePropertyField(s.groupID, mmPropertyField)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to update team property field")
+	}
+
+	updatedField, err := NewPropertyFieldFromMattermostPropertyField(updatedMMField)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to convert updated property field")
+	}
+
+	if len(linkedFields) == 0 {
+		return updatedField, []string{}, nil
+	}
+
+	fieldsToUpdate := make([]*model.PropertyField, 0, len(linkedFields))
+	updatedPlaybookIDs := make([]string, 0, len(linkedFields))
+	for _, linkedField := range linkedFields {
+		updatedLinkedField := linkPropertyField(*updatedField, linkedField)
+		fieldsToUpdate = append(fieldsToUpdate, updatedLinkedField.ToMattermostPropertyField())
+		updatedPlaybookIDs = append(updatedPlaybookIDs, linkedField.TargetID)
+	}
+
+	if _, err := s.api.Property.UpdatePropertyFields(s.groupID, fieldsToUpdate); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to update linked playbook property fields")
+	}
+
+	return updatedField, updatedPlaybookIDs, nil
+}
+
+func (s *propertyService) DeleteTeamPropertyField(teamID, propertyID string, playbookIDs []string) error {
+	field, err := s.getTeamPropertyField(teamID, propertyID)
+	if err != nil {
+		return err
+	}
+
+	linkedFields, err := s.getLinkedPropertyFields(propertyID, playbookIDs)
+	if err != nil {
+		return err
+	}
+	if len(linkedFields) > 0 {
+		return errors.Wrapf(ErrPropertyFieldInUse, "cannot delete property field '%s': it is used by %d playbook(s). Please remove it from these playbooks before deleting it", field.Name, len(linkedFields))
+	}
+
+	if err := s.api.Property.DeletePropertyField(s.groupID, propertyID); err != nil {
+		return errors.Wrap(err, "failed to delete team property field")
+	}
+
+	return nil
+}
+
+// LinkTeamPropertyField adds the team field propertyField.Attrs.SharedFieldID to a playbook. The
+// visibility and sort order of propertyField are kept, the rest is defined by the team field.
+func (s *propertyService) LinkTeamPropertyField(playbookID, teamID string, propertyField PropertyField) (*PropertyField, error) {
+	teamField, err := s.getTeamPropertyField(teamID, propertyField.Attrs.SharedFieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingFields, err := s.GetPropertyFields(playbookID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property fields")
+	}
+	for _, existingField := range existingFields {
+		if existingField.Attrs.SharedFieldID == teamField.ID {
+			return nil, errors.Wrapf(ErrDuplicateEntry, "playbook already uses the shared property field '%s'", teamField.Name)
+		}
+	}
+
+	if err := s.validatePropertyLimit(playbookID); err != nil {
+		return nil, err
+	}
+
+	linkedField := linkPropertyField(*teamField, PropertyField{
+		PropertyField: model.PropertyField{
+			GroupID:    s.groupID,
+			TargetType: PropertyTargetTypePlaybook,
+			TargetID:   playbookID,
+		},
+		Attrs: Attrs{
+			Visibility: propertyField.Attrs.Visibility,
+			SortOrder:  propertyField.Attrs.SortOrder,
+		},
+	})
+	if err := linkedField.SanitizeAndValidate(); err != nil {
+		return nil, errors.Wrap(err, "invalid property field")
+	}
+
+	createdField, err := s.api.Property.CreatePropertyField(linkedField.ToMattermostPropertyField())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create linked property field")
+	}
+
+	resultField, err := NewPropertyFieldFromMattermostPropertyField(createdField)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert created property field")
+	}
+
+	return resultField, nil
+}
+
+// MergePropertyFieldsIntoTeamField links the fields named name of the given playbooks to the
+// team field of that name, creating it from them if needed. Options are matched by name, and
+// the team field gains the options it is missing. Fields whose options would change while being
+// referenced by conditions are skipped, as are fields of another type.
+func (s *propertyService) MergePropertyFieldsIntoTeamField(teamID, name string, playbookIDs []string) (*SharedPropertyMergeResult, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	playbookFields, err := s.getPlaybooksPropertyFields(playbookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []PropertyField
+	for _, field := range playbookFields {
+		if strings.TrimSpace(field.Name) == name && !field.IsShared() && !field.IsComputed() {
+			candidates = append(candidates, field)
+		}
+	}
+
+	teamFields, err := s.GetTeamPropertyFields(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var teamField *PropertyField
+	for i := range teamFields {
+		if teamFields[i].Name == name {
+			teamField = &teamFields[i]
+			break
+		}
+	}
+
+	if teamField == nil {
+		if len(candidates) == 0 {
+			return nil, errors.Wrapf(ErrNotFound, "no playbook property field named '%s'", name)
+		}
+
+		fieldType := mostCommonFieldType(candidates)
+		definition := PropertyField{}
+		for _, candidate := range candidates {
+			if candidate.Type != fieldType {
+				continue
+			}
+			if definition.Type == "" {
+				definition = linkPropertyField(candidate, PropertyField{})
+				definition.Name = name
+				definition.Attrs.SharedFieldID = ""
+				definition.Attrs.Visibility = ""
+				definition.Attrs.SortOrder = float64(len(teamFields))
+				continue
+			}
+			definition.Attrs.Options, _ = mergeOptionsByName(definition.Attrs.Options, candidate.Attrs.Options)
+		}
+
+		teamField, err = s.CreateTeamPropertyField(teamID, definition)
+		if err != nil {
+			return nil, err
+		}
+	} else if teamField.SupportsOptions() {
+		options := teamField.Attrs.Options
+		added := false
+		for _, candidate := range candidates {
+			if candidate.Type != teamField.Type {
+				continue
+			}
+			var addedHere bool
+			options, addedHere = mergeOptionsByName(options, candidate.Attrs.Options)
+			added = added || addedHere
+		}
+
+		if added {
+			updated := *teamField
+			updated.Attrs.Options = options
+			teamField, _, err = s.UpdateTeamPropertyField(teamID, updated, playbookIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := &SharedPropertyMergeResult{
+		Field:        *teamField,
+		LinkedFields: []PropertyField{},
+		Skipped:      []SharedPropertyMergeSkip{},
+	}
+
+	alreadyLinked := make(map[string]bool)
+	for _, field := range playbookFields {
+		if field.Attrs.SharedFieldID == teamField.ID {
+			alreadyLinked[field.TargetID] = true
+		}
+	}
+
+	for _, candidate := range candidates {
+		if alreadyLinked[candidate.TargetID] {
+			result.Skipped = append(result.Skipped, SharedPropertyMergeSkip{
+				PlaybookID: candidate.TargetID,
+				FieldID:    candidate.ID,
+				Reason:     "the playbook already uses the shared field",
+			})
+			continue
+		}
+		if candidate.Type != teamField.Type {
+			result.Skipped = append(result.Skipped, SharedPropertyMergeSkip{
+				PlaybookID: candidate.TargetID,
+				FieldID:    candidate.ID,
+				Reason:     fmt.Sprintf("field type '%s' differs from the shared field type '%s'", candidate.Type, teamField.Type),
+			})
+			continue
+		}
+
+		var changedOptionIDs []string
+		for _, option := range candidate.Attrs.Options {
+			teamOption := findOptionByName(teamField.Attrs.Options, option.GetName())
+			if teamOption == nil || teamOption.GetID() != option.GetID() {
+				changedOptionIDs = append(changedOptionIDs, option.GetID())
+			}
+		}
+		if len(changedOptionIDs) > 0 {
+			optionsInUse, err := s.conditionStore.CountConditionsUsingPropertyOptions(candidate.TargetID, changedOptionIDs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to check if property options are in use")
+			}
+			if len(optionsInUse) > 0 {
+				result.Skipped = append(result.Skipped, SharedPropertyMergeSkip{
+					PlaybookID: candidate.TargetID,
+					FieldID:    candidate.ID,
+					Reason:     fmt.Sprintf("options %s are referenced by conditions", s.getOptionNames(candidate.Attrs.Options, optionsInUse)),
+				})
+				continue
+			}
+		}
+
+		linkedField := linkPropertyField(*teamField, candidate)
+		updatedMMField, err := s.api.Property.UpdatePropertyField(s.groupID, linkedField.ToMattermostPropertyField())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to link property field %s of playbook %s", candidate.ID, candidate.TargetID)
+		}
+
+		updatedField, err := NewPropertyFieldFromMattermostPropertyField(updatedMMField)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert linked property field")
+		}
+		result.LinkedFields = append(result.LinkedFields, *updatedField)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"team_id":        teamID,
+		"field_id":       teamField.ID,
+		"fields_linked":  len(result.LinkedFields),
+		"fields_skipped": len(result.Skipped),
+	}).Info("merged playbook properties into team property")
+
+	return result, nil
+}
+
+// getLinkedPropertyFields returns the fields of the given playbooks linked to a team field.
+func (s *propertyService) getLinkedPropertyFields(sharedFieldID string, playbookIDs []string) ([]PropertyField, error) {
+	playbookFields, err := s.getPlaybooksPropertyFields(playbookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var linkedFields []PropertyField
+	for _, field := range playbookFields {
+		if field.Attrs.SharedFieldID == sharedFieldID {
+			linkedFields = append(linkedFields, field)
+		}
+	}
+
+	return linkedFields, nil
+}
+
+// getPlaybooksPropertyFields retrieves the property fields of multiple playbooks in a paginated way
+func (s *propertyService) getPlaybooksPropertyFields(playbookIDs []string) ([]PropertyField, error) {
+	if len(playbookIDs) == 0 {
+		return []PropertyField{}, nil
+	}
+
+	opts := model.PropertyFieldSearchOpts{
+		GroupID:    s.groupID,
+		TargetType: PropertyTargetTypePlaybook,
+		TargetIDs:  playbookIDs,
+		PerPage:    PropertyBulkSearchPerPage,
+	}
+
+	var result []PropertyField
+	for {
+		fields, err := s.api.Property.SearchPropertyFields(s.groupID, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search property fields")
+		}
+
+		for _, mmField := range fields {
+			pf, err := NewPropertyFieldFromMattermostPropertyField(mmField)
+			if err != nil {
+				logrus.WithError(err).Warn("Failed to convert property field")
+				continue
+			}
+			result = append(result, *pf)
+		}
+
+		if len(fields) < PropertyBulkSearchPerPage {
+			break
+		}
+
+		opts.Cursor.PropertyFieldID = fields[len(fields)-1].ID
+		opts.Cursor.CreateAt = fields[len(fields)-1].CreateAt
+	}
+
+	return result, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sharedTestOptions(idsAndNames ...string) model.PropertyOptions[*model.PluginPropertyOption] {
+	options := make(model.PropertyOptions[*model.PluginPropertyOption], 0, len(idsAndNames)/2)
+	for i := 0; i+1 < len(idsAndNames); i += 2 {
+		options = append(options, model.NewPluginPropertyOption(idsAndNames[i], idsAndNames[i+1]))
+	}
+	return options
+}
+
+func TestLinkPropertyField(t *testing.T) {
+	teamField := PropertyField{
+		PropertyField: model.PropertyField{ID: "team_field", Name: "Severity", Type: model.PropertyFieldTypeSelect},
+		Attrs: Attrs{
+			Visibility: PropertyFieldVisibilityDefault,
+			SortOrder:  7,
+			Options:    sharedTestOptions("team_sev1", "SEV1", "team_sev2", "SEV2"),
+		},
+	}
+	playbookField := PropertyField{
+		PropertyField: model.PropertyField{ID: "playbook_field", TargetID: "playbook", Name: "severity", Type: model.PropertyFieldTypeText},
+		Attrs: Attrs{
+			Visibility: PropertyFieldVisibilityHidden,
+			SortOrder:  2,
+			ParentID:   "old_parent",
+		},
+	}
+
+	linked := linkPropertyField(teamField, playbookField)
+
+	assert.Equal(t, "playbook_field", linked.ID)
+	assert.Equal(t, "playbook", linked.TargetID)
+	assert.Equal(t, "Severity", linked.Name)
+	assert.Equal(t, model.PropertyFieldTypeSelect, linked.Type)
+	assert.Equal(t, PropertyFieldVisibilityHidden, linked.Attrs.Visibility)
+	assert.Equal(t, float64(2), linked.Attrs.SortOrder)
+	assert.Empty(t, linked.Attrs.ParentID)
+	assert.Equal(t, "team_field", linked.Attrs.SharedFieldID)
+	assert.True(t, linked.IsShared())
+
+	require.Len(t, linked.Attrs.Options, 2)
+	assert.Equal(t, "team_sev1", linked.Attrs.Options[0].GetID())
+	assert.Equal(t, "SEV2", linked.Attrs.Options[1].GetName())
+
+	// The options are copies: changing them leaves the team field untouched.
+	linked.Attrs.Options[0].SetValue("name", "changed")
+	assert.Equal(t, "SEV1", teamField.Attrs.Options[0].GetName())
+}
+
+func TestSameSharedDefinition(t *testing.T) {
+	existing := PropertyField{
+		PropertyField: model.PropertyField{Name: "Severity", Type: model.PropertyFieldTypeSelect},
+		Attrs:         Attrs{Options: sharedTestOptions("sev1", "SEV1", "sev2", "SEV2")},
+	}
+
+	t.Run("different option IDs", func(t *testing.T) {
+		updated := existing
+		updated.Attrs.Options = sharedTestOptions("", "SEV1", "other", "SEV2")
+		updated.Attrs.SortOrder = 4
+		assert.True(t, sameSharedDefinition(existing, updated))
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		updated := existing
+		updated.Name = "Priority"
+		assert.False(t, sameSharedDefinition(existing, updated))
+	})
+
+	t.Run("type changed", func(t *testing.T) {
+		updated := existing
+		updated.Type = model.PropertyFieldTypeMultiselect
+		assert.False(t, sameSharedDefinition(existing, updated))
+	})
+
+	t.Run("option added", func(t *testing.T) {
+		updated := existing
+		updated.Attrs.Options = sharedTestOptions("sev1", "SEV1", "sev2", "SEV2", "sev3", "SEV3")
+		assert.False(t, sameSharedDefinition(existing, updated))
+	})
+
+	t.Run("option color changed", func(t *testing.T) {
+		updated := existing
+		updated.Attrs.Options = sharedTestOptions("sev1", "SEV1", "sev2", "SEV2")
+		updated.Attrs.Options[1].SetValue("color", "red")
+		assert.False(t, sameSharedDefinition(existing, updated))
+	})
+}
+
+func TestMergeOptionsByName(t *testing.T) {
+	options := sharedTestOptions("sev1", "SEV1", "sev2", "SEV2")
+
+	merged, added := mergeOptionsByName(options, sharedTestOptions("x", "sev1", "sev3", "SEV3"))
+	assert.True(t, added)
+	require.Len(t, merged, 3)
+	assert.Equal(t, "sev1", merged[0].GetID())
+	assert.Equal(t, "sev3", merged[2].GetID())
+	assert.Len(t, options, 2)
+
+	merged, added = mergeOptionsByName(options, sharedTestOptions("y", " sev2 "))
+	assert.False(t, added)
+	assert.Len(t, merged, 2)
+}
+
+func TestMostCommonFieldType(t *testing.T) {
+	field := func(fieldType model.PropertyFieldType) PropertyField {
+		return PropertyField{PropertyField: model.PropertyField{Type: fieldType}}
+	}
+
+	assert.Equal(t, model.PropertyFieldTypeSelect, mostCommonFieldType([]PropertyField{
+		field(model.PropertyFieldTypeText),
+		field(model.PropertyFieldTypeSelect),
+		field(model.PropertyFieldTypeSelect),
+	}))
+
+	// Ties go to the first type seen.
+	assert.Equal(t, model.PropertyFieldTypeText, mostCommonFieldType([]PropertyField{
+		field(model.PropertyFieldTypeText),
+		field(model.PropertyFieldTypeSelect),
+	}))
+}
@@ -54,6 +54,9 @@ const (
 	SortByMetric1 SortField = "metric1"
 	SortByMetric2 SortField = "metric2"
 	SortByMetric3 SortField = "metric3"
+
+	// SortByProperty sorts by the value of a property field.
+	SortByProperty SortField = "property"
 )
 
 // SortDirection is the type used to specify the ascending or descending order of returned results.
@@ -1,17 +1,17 @@
 // Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.txt for license information.
 
-// Template engine for resolving channel/run name placeholders.
+// Template engine for resolving channel/run name placeholders, filters and conditional sections.
 // KEEP IN SYNC with webapp/src/utils/template_utils.ts (same token syntax, system tokens, formatting rules).
 
 package app
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -21,9 +21,9 @@ import (
 
 var placeholderRegex = regexp.MustCompile(`\{([^}]+)\}`)
 
-// seqTokenRegex matches {SEQ} case-insensitively with optional surrounding whitespace,
+// seqTokenRegex matches {SEQ} case-insensitively with optional surrounding whitespace and filters,
 // consistent with the whitespace-trimming behaviour of ResolveTemplate.
-var seqTokenRegex = regexp.MustCompile(`(?i)\{\s*SEQ\s*\}`)
+var seqTokenRegex = regexp.MustCompile(`(?i)\{\s*SEQ\s*(\|[^}]*)?\}`)
 
 // epochMsMin is the minimum epoch-millisecond value (Sep 2001) treated as a date.
 // KEEP IN SYNC with EPOCH_MS_MIN in webapp/src/utils/template_utils.ts.
@@ -34,17 +34,6 @@ var (
 	collapseMultiSpaceRe         = regexp.MustCompile(`\s{2,}`)
 )
 
-var fieldRegexCache sync.Map // map[string]*regexp.Regexp
-
-func fieldRegex(fieldName string) *regexp.Regexp {
-	if v, ok := fieldRegexCache.Load(fieldName); ok {
-		return v.(*regexp.Regexp)
-	}
-	re := regexp.MustCompile(`(?i)\{\s*` + regexp.QuoteMeta(fieldName) + `\s*\}`)
-	fieldRegexCache.Store(fieldName, re)
-	return re
-}
-
 // FormatFunc formats a property field's raw JSON value as a string.
 // Returns the formatted string and whether the value is empty; replaces default behavior when set in ResolveOptions.
 type FormatFunc func(field *PropertyField, raw json.RawMessage) (string, bool)
@@ -53,7 +42,7 @@ type FormatFunc func(field *PropertyField, raw json.RawMessage) (string, bool)
 type ResolveOptions struct {
 	Fields       []PropertyField
 	Values       map[string]json.RawMessage
-	SystemTokens map[string]string // Pre-resolved built-in tokens (SEQ, OWNER, CREATOR); take precedence over field names.
+	SystemTokens map[string]string // Pre-resolved built-in tokens (SEQ, OWNER, CREATOR, TEAM, PLAYBOOK, DATE, PROGRESS, ELAPSED).
 	FormatFunc   FormatFunc        // Nil uses DefaultFormatPropertyValue.
 }
 
@@ -63,8 +52,19 @@ type ResolveOptions struct {
 // PROPERTY_USER: reserved assignee type name.
 var systemTokens = []string{"SEQ", "OWNER", "CREATOR", "PROPERTY_USER"}
 
-// isSystemToken checks if a name matches a built-in system token (case-insensitive).
+// contextTokens are built-in tokens introduced after fields could already be named like them, so
+// they are not reserved: a field of the same name takes precedence over them.
+// TEAM: team display name; PLAYBOOK: playbook title; DATE: date of resolution (YYYY-MM-DD, UTC).
+// PROGRESS: completed/total checklist items (e.g. 3/10); ELAPSED: run duration so far (e.g. 2d 3h).
+var contextTokens = []string{"TEAM", "PLAYBOOK", "DATE", "PROGRESS", "ELAPSED"}
+
+// isSystemToken checks if a name matches a built-in token (case-insensitive).
 func isSystemToken(name string) bool {
+	return isReservedToken(name) || isContextToken(name)
+}
+
+// isReservedToken checks if a name matches a built-in token reserved as a field name (case-insensitive).
+func isReservedToken(name string) bool {
 	for _, tok := range systemTokens {
 		if strings.EqualFold(name, tok) {
 			return true
@@ -73,122 +73,588 @@ func isSystemToken(name string) bool {
 	return false
 }
 
+func isContextToken(name string) bool {
+	for _, tok := range contextTokens {
+		if strings.EqualFold(name, tok) {
+			return true
+		}
+	}
+	return false
+}
+
 // validateReservedFieldName rejects field names that would conflict with built-in
 // template placeholders or assignee types. Reserved: SEQ, OWNER, CREATOR, PROPERTY_USER (case-insensitive).
 func validateReservedFieldName(name string) error {
 	name = strings.TrimSpace(name)
-	if isSystemToken(name) {
+	if isReservedToken(name) {
 		return errors.Wrapf(ErrReservedPropertyFieldName, "field name %q is reserved", name)
 	}
 	return nil
 }
 
-// ResolveTemplate substitutes {TokenName} placeholders in a template string.
-// System tokens (opts.SystemTokens) are resolved first; unknown tokens are returned in unresolved.
-func ResolveTemplate(template string, opts ResolveOptions) (string, []string) {
-	if template == "" {
-		return "", nil
+// Template syntax, on top of plain {Name} placeholders:
+//
+//	{Name|upper}, {Name|lower}, {Name|trim}  change the case or trim the value
+//	{Name|default:"text"}                    used when the value is empty
+//	{Name|date:"2006-01-02"}                 formats a date with a Go layout
+//	{#if Name}...{else}...{/if}              kept when the value is not empty
+//	{#if !Name}...{/if}                      kept when the value is empty
+//	{#if Name == SEV1}...{/if}               kept when the value, or one of its values, is SEV1
+//	{#if Name != "SEV 1"}...{/if}            the negation of ==
+//
+// Filters are chained left to right. Comparisons ignore case; a missing value equals nothing.
+// Malformed sections are kept as written and reported as unresolved.
+
+type templateNodeKind int
+
+const (
+	templateNodeText templateNodeKind = iota
+	templateNodePlaceholder
+	templateNodeSection
+	templateNodeInvalid
+)
+
+// templateNode is a parsed piece of a template: literal text, a placeholder, a conditional section
+// or a malformed tag kept as written.
+type templateNode struct {
+	kind templateNodeKind
+	raw  string // the text, or the tag as written
+
+	// name is the field or token of placeholders and sections.
+	name string
+
+	// filtersRaw is the part of a placeholder after its name, such as "|upper".
+	filtersRaw string
+	filters    []templateFilter
+
+	cond      templateCondition
+	then      []templateNode
+	otherwise []templateNode
+	elseRaw   string
+	endRaw    string
+	unclosed  bool
+}
+
+type templateFilter struct {
+	name string
+	arg  string
+}
+
+type templateCondition struct {
+	negate   bool   // {#if !Name}
+	op       string // "", "==" or "!="
+	value    string
+	valueRaw string // the value as written, quotes included
+}
+
+// templateValue is the value of a placeholder or condition. Empty text means no value.
+type templateValue struct {
+	text    string
+	items   []string // the separate values of multi-valued fields
+	time    time.Time
+	hasTime bool
+}
+
+// templateFilterArgs maps each filter to whether it requires an argument.
+var templateFilterArgs = map[string]bool{
+	"upper":   false,
+	"lower":   false,
+	"trim":    false,
+	"default": true,
+	"date":    false,
+}
+
+// templateDateLayout is the layout of the date filter without argument, and of DATE.
+const templateDateLayout = "2006-01-02"
+
+// formatElapsed renders a duration for the ELAPSED token with its two most significant units,
+// e.g. "2d 3h", "3h 20m" or "5m".
+func formatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	minutes := int64(d / time.Minute)
+	days, hours, mins := minutes/(24*60), (minutes/60)%24, minutes%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	default:
+		return fmt.Sprintf("%dm", mins)
+	}
+}
+
+// parseTemplate splits a template into nodes, nesting the content of conditional sections.
+func parseTemplate(tmpl string) []templateNode {
+	type frame struct {
+		section templateNode
+		inElse  bool
+	}
+	var root []templateNode
+	var stack []*frame
+
+	appendNode := func(node templateNode) {
+		if len(stack) == 0 {
+			root = append(root, node)
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.inElse {
+			top.section.otherwise = append(top.section.otherwise, node)
+		} else {
+			top.section.then = append(top.section.then, node)
+		}
+	}
+
+	last := 0
+	for _, loc := range placeholderRegex.FindAllStringSubmatchIndex(tmpl, -1) {
+		if loc[0] > last {
+			appendNode(templateNode{kind: templateNodeText, raw: tmpl[last:loc[0]]})
+		}
+		last = loc[1]
+
+		raw := tmpl[loc[0]:loc[1]]
+		inner := strings.TrimSpace(tmpl[loc[2]:loc[3]])
+		lower := strings.ToLower(inner)
+
+		switch {
+		case lower == "#if" || strings.HasPrefix(lower, "#if "):
+			name, cond, ok := parseTemplateCondition(inner[len("#if"):])
+			if !ok {
+				appendNode(templateNode{kind: templateNodeInvalid, raw: raw, name: inner})
+				continue
+			}
+			stack = append(stack, &frame{section: templateNode{kind: templateNodeSection, raw: raw, name: name, cond: cond}})
+
+		case lower == "else" && len(stack) > 0 && !stack[len(stack)-1].inElse:
+			stack[len(stack)-1].inElse = true
+			stack[len(stack)-1].section.elseRaw = raw
+
+		case lower == "/if":
+			if len(stack) == 0 {
+				appendNode(templateNode{kind: templateNodeInvalid, raw: raw, name: inner})
+				continue
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			top.section.endRaw = raw
+			appendNode(top.section)
+
+		default:
+			appendNode(parseTemplatePlaceholder(raw, inner))
+		}
+	}
+	if last < len(tmpl) {
+		appendNode(templateNode{kind: templateNodeText, raw: tmpl[last:]})
+	}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		top.section.unclosed = true
+		appendNode(top.section)
+	}
+
+	return root
+}
+
+// parseTemplatePlaceholder parses {Name|filter|filter:"arg"}. Placeholders whose filters cannot be
+// parsed are read as a plain name, as before filters existed.
+func parseTemplatePlaceholder(raw, inner string) templateNode {
+	node := templateNode{kind: templateNodePlaceholder, raw: raw, name: inner}
+
+	parts := splitTemplateFilters(inner)
+	if len(parts) == 1 {
+		return node
+	}
+
+	filters := make([]templateFilter, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		filter, ok := parseTemplateFilter(part)
+		if !ok {
+			return node
+		}
+		filters = append(filters, filter)
+	}
+
+	node.name = strings.TrimSpace(parts[0])
+	node.filtersRaw = strings.TrimSpace(inner[len(parts[0]):])
+	node.filters = filters
+	return node
+}
+
+// splitTemplateFilters splits a placeholder on the pipes outside double quotes.
+func splitTemplateFilters(inner string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range inner {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == '|' && !quoted:
+			parts = append(parts, inner[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, inner[start:])
+}
+
+func parseTemplateFilter(part string) (templateFilter, bool) {
+	name, arg, hasArg := strings.Cut(strings.TrimSpace(part), ":")
+	filter := templateFilter{name: strings.ToLower(strings.TrimSpace(name))}
+
+	requiresArg, ok := templateFilterArgs[filter.name]
+	if !ok {
+		return templateFilter{}, false
+	}
+	if hasArg {
+		filter.arg = unquoteTemplateValue(strings.TrimSpace(arg))
+	}
+	if requiresArg && !hasArg {
+		return templateFilter{}, false
+	}
+	return filter, true
+}
+
+// parseTemplateCondition parses the condition of a section: Name, !Name, Name == value or
+// Name != value.
+func parseTemplateCondition(expr string) (string, templateCondition, bool) {
+	expr = strings.TrimSpace(expr)
+	var cond templateCondition
+
+	if strings.HasPrefix(expr, "!") && !strings.Contains(expr, "=") {
+		cond.negate = true
+		expr = strings.TrimSpace(expr[1:])
+	} else if i := strings.IndexAny(expr, "=!"); i >= 0 {
+		rest := expr[i:]
+		switch {
+		case strings.HasPrefix(rest, "=="):
+			cond.op = "=="
+		case strings.HasPrefix(rest, "!="):
+			cond.op = "!="
+		default:
+			return "", templateCondition{}, false
+		}
+		cond.valueRaw = strings.TrimSpace(rest[2:])
+		cond.value = unquoteTemplateValue(cond.valueRaw)
+		expr = strings.TrimSpace(expr[:i])
+	}
+
+	if expr == "" {
+		return "", templateCondition{}, false
+	}
+	return expr, cond, true
+}
+
+func unquoteTemplateValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// matches reports whether the section of the condition is kept for the value.
+func (c templateCondition) matches(value templateValue) bool {
+	switch c.op {
+	case "==":
+		return value.equals(c.value)
+	case "!=":
+		return !value.equals(c.value)
+	}
+	return (value.text != "") != c.negate
+}
+
+func (v templateValue) equals(expected string) bool {
+	if v.text == "" {
+		return false
+	}
+	expected = strings.TrimSpace(expected)
+	if strings.EqualFold(strings.TrimSpace(v.text), expected) {
+		return true
+	}
+	for _, item := range v.items {
+		if strings.EqualFold(strings.TrimSpace(item), expected) {
+			return true
+		}
 	}
+	return false
+}
+
+// source returns the section head as written, for re-serializing templates.
+func (n templateNode) source(then, otherwise string) string {
+	if n.elseRaw != "" {
+		otherwise = n.elseRaw + otherwise
+	}
+	return n.raw + then + otherwise + n.endRaw
+}
 
-	formatFn := opts.FormatFunc
-	if formatFn == nil {
-		formatFn = DefaultFormatPropertyValue
+// templateResolver resolves parsed templates, collecting unresolved names.
+type templateResolver struct {
+	opts        ResolveOptions
+	formatFn    FormatFunc
+	fieldByName map[string]int
+	sysTokens   map[string]string
+	unresolved  []string
+}
+
+func newTemplateResolver(opts ResolveOptions) *templateResolver {
+	r := &templateResolver{opts: opts, formatFn: opts.FormatFunc}
+	if r.formatFn == nil {
+		r.formatFn = DefaultFormatPropertyValue
 	}
 
 	// Pre-build a case-insensitive index for O(1) field lookups.
-	fieldByName := make(map[string]int, len(opts.Fields))
+	r.fieldByName = make(map[string]int, len(opts.Fields))
 	for i := range opts.Fields {
-		fieldByName[strings.ToLower(opts.Fields[i].Name)] = i
+		r.fieldByName[strings.ToLower(opts.Fields[i].Name)] = i
 	}
 
 	// Pre-build a case-insensitive index for system tokens.
-	var sysTokens map[string]string
 	if len(opts.SystemTokens) > 0 {
-		sysTokens = make(map[string]string, len(opts.SystemTokens))
+		r.sysTokens = make(map[string]string, len(opts.SystemTokens))
 		for k, v := range opts.SystemTokens {
-			sysTokens[strings.ToLower(k)] = v
+			r.sysTokens[strings.ToLower(k)] = v
 		}
 	}
+	return r
+}
 
-	var unresolved []string
+// lookup returns the value of a token or field, and false if the name is neither.
+func (r *templateResolver) lookup(name string) (templateValue, bool) {
+	nameLower := strings.ToLower(name)
+	idx, isField := r.fieldByName[nameLower]
 
-	result := placeholderRegex.ReplaceAllStringFunc(template, func(match string) string {
-		// match is always "{inner}" with at least one character inside the braces
-		// (enforced by the regex \{([^}]+)\}), so extract content directly.
-		name := strings.TrimSpace(match[1 : len(match)-1])
-		nameLower := strings.ToLower(name)
+	// System tokens take precedence (case-insensitive lookup), except context tokens which
+	// yield to fields of the same name.
+	if val, ok := r.sysTokens[nameLower]; ok && (!isField || !isContextToken(name)) {
+		return templateValue{text: val}, true
+	}
+	if !isField {
+		return templateValue{}, false
+	}
 
-		// System tokens take precedence (case-insensitive lookup).
-		if val, ok := sysTokens[nameLower]; ok {
-			if val == "" {
-				unresolved = append(unresolved, name)
-				return match
+	field := &r.opts.Fields[idx]
+	raw, hasVal := r.opts.Values[field.ID]
+	if !hasVal {
+		return templateValue{}, true
+	}
+	formatted, empty := r.formatFn(field, raw)
+	if empty {
+		return templateValue{}, true
+	}
+
+	value := templateValue{text: formatted}
+	switch field.Type {
+	case model.PropertyFieldTypeDate:
+		if ms, ok := orderedValue(*field, raw); ok {
+			value.time = time.UnixMilli(int64(ms)).UTC()
+			value.hasTime = true
+		}
+	case model.PropertyFieldTypeMultiselect, model.PropertyFieldTypeMultiuser:
+		var elements []string
+		if json.Unmarshal(raw, &elements) == nil {
+			for _, element := range elements {
+				single, err := json.Marshal([]string{element})
+				if err != nil {
+					continue
+				}
+				if item, empty := r.formatFn(field, single); !empty {
+					value.items = append(value.items, item)
+				}
 			}
-			return val
 		}
+	}
+	return value, true
+}
+
+func (r *templateResolver) render(nodes []templateNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.kind {
+		case templateNodeText:
+			b.WriteString(node.raw)
 
-		// Look up field by name (case-insensitive)
-		if idx, ok := fieldByName[nameLower]; ok {
-			if opts.Values == nil {
-				unresolved = append(unresolved, name)
-				return match
+		case templateNodeInvalid:
+			r.unresolved = append(r.unresolved, node.name)
+			b.WriteString(node.raw)
+
+		case templateNodePlaceholder:
+			b.WriteString(r.renderPlaceholder(node))
+
+		case templateNodeSection:
+			if node.unclosed {
+				r.unresolved = append(r.unresolved, strings.TrimSpace(node.raw[1:len(node.raw)-1]))
+				b.WriteString(node.source(r.render(node.then), r.render(node.otherwise)))
+				continue
 			}
-			raw, hasVal := opts.Values[opts.Fields[idx].ID]
-			if !hasVal {
-				unresolved = append(unresolved, name)
-				return match
+			value, known := r.lookup(node.name)
+			if !known {
+				r.unresolved = append(r.unresolved, node.name)
 			}
-			formatted, empty := formatFn(&opts.Fields[idx], raw)
-			if empty {
-				unresolved = append(unresolved, name)
-				return match
+			if node.cond.matches(value) {
+				b.WriteString(r.render(node.then))
+			} else {
+				b.WriteString(r.render(node.otherwise))
 			}
-			return formatted
 		}
+	}
+	return b.String()
+}
 
-		unresolved = append(unresolved, name)
-		return match
-	})
+func (r *templateResolver) renderPlaceholder(node templateNode) string {
+	value, known := r.lookup(node.name)
+	if !known {
+		r.unresolved = append(r.unresolved, node.name)
+		return node.raw
+	}
 
-	return result, unresolved
+	text := value.text
+	for _, filter := range node.filters {
+		text = applyTemplateFilter(filter, text, value)
+	}
+	if text == "" {
+		r.unresolved = append(r.unresolved, node.name)
+		return node.raw
+	}
+	return text
 }
 
-// ValidateTemplate checks if all {FieldName} placeholders reference known fields or system tokens.
-// Returns unrecognized placeholder names; SEQ/OWNER/CREATOR are always valid.
-func ValidateTemplate(template string, opts ResolveOptions) []string {
-	_, unresolved := ResolveTemplate(template, ResolveOptions{Fields: opts.Fields})
+func applyTemplateFilter(filter templateFilter, text string, value templateValue) string {
+	switch filter.name {
+	case "upper":
+		return strings.ToUpper(text)
+	case "lower":
+		return strings.ToLower(text)
+	case "trim":
+		return strings.TrimSpace(text)
+	case "default":
+		if text == "" {
+			return filter.arg
+		}
+	case "date":
+		if text == "" {
+			return text
+		}
+		layout := filter.arg
+		if layout == "" {
+			layout = templateDateLayout
+		}
+		if value.hasTime {
+			return value.time.Format(layout)
+		}
+		if t, ok := parseTemplateDate(text); ok {
+			return t.Format(layout)
+		}
+	}
+	return text
+}
+
+// parseTemplateDate reads the dates of system tokens and of formatted values.
+func parseTemplateDate(text string) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04 MST", templateDateLayout} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+	if ms, err := strconv.ParseInt(text, 10, 64); err == nil && ms > epochMsMin {
+		return time.UnixMilli(ms).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// walkTemplate calls fn for every node, including those of both branches of sections.
+func walkTemplate(nodes []templateNode, fn func(node templateNode)) {
+	for _, node := range nodes {
+		fn(node)
+		if node.kind == templateNodeSection {
+			walkTemplate(node.then, fn)
+			walkTemplate(node.otherwise, fn)
+		}
+	}
+}
+
+// ResolveTemplate substitutes {TokenName} placeholders in a template string, applying filters and
+// conditional sections. System tokens (opts.SystemTokens) are resolved first; unknown tokens,
+// empty values and malformed sections are returned in unresolved. Placeholders in sections left
+// out are not resolved.
+func ResolveTemplate(template string, opts ResolveOptions) (string, []string) {
+	if template == "" {
+		return "", nil
+	}
+
+	r := newTemplateResolver(opts)
+	result := r.render(parseTemplate(template))
+	return result, r.unresolved
+}
 
+// ValidateTemplate checks if all placeholders and conditions, in every section, reference known
+// fields or system tokens. Returns unrecognized names and malformed section tags; system tokens
+// are always valid.
+func ValidateTemplate(template string, opts ResolveOptions) []string {
 	knownNames := make(map[string]struct{}, len(opts.Fields))
 	for _, f := range opts.Fields {
 		knownNames[strings.ToLower(f.Name)] = struct{}{}
 	}
 
 	var unknown []string
-	for _, name := range unresolved {
+	check := func(name string) {
 		if isSystemToken(name) {
-			continue
+			return
 		}
 		if _, ok := knownNames[strings.ToLower(name)]; !ok {
 			unknown = append(unknown, name)
 		}
 	}
+
+	walkTemplate(parseTemplate(template), func(node templateNode) {
+		switch node.kind {
+		case templateNodeInvalid:
+			unknown = append(unknown, node.name)
+		case templateNodePlaceholder:
+			check(node.name)
+		case templateNodeSection:
+			if node.unclosed {
+				unknown = append(unknown, strings.TrimSpace(node.raw[1:len(node.raw)-1]))
+				return
+			}
+			check(node.name)
+		}
+	})
 	return unknown
 }
 
+// templateUsesToken reports whether a placeholder or condition of the template references the
+// token (case-insensitive).
+func templateUsesToken(tmpl, token string) bool {
+	if !strings.Contains(tmpl, "{") {
+		return false
+	}
+	used := false
+	walkTemplate(parseTemplate(tmpl), func(node templateNode) {
+		if (node.kind == templateNodePlaceholder || node.kind == templateNodeSection) && strings.EqualFold(node.name, token) {
+			used = true
+		}
+	})
+	return used
+}
+
 // TemplateUsesSeqToken reports whether a template contains a {SEQ} placeholder
 // (case-insensitive, matching the resolution behaviour of ResolveTemplate).
 func TemplateUsesSeqToken(tmpl string) bool {
-	return seqTokenRegex.MatchString(tmpl)
+	return templateUsesToken(tmpl, "SEQ")
 }
 
 // StripFieldFromTemplate removes all occurrences of {fieldName} from a template string
-// and cleans up orphaned separators and whitespace.
+// and cleans up orphaned separators and whitespace. Sections conditioned on the field are replaced
+// by the content kept when it has no value.
 func StripFieldFromTemplate(tmpl, fieldName string) string {
 	if tmpl == "" {
 		return ""
 	}
-	re := fieldRegex(fieldName)
-	result := re.ReplaceAllString(tmpl, "")
+	result := stripTemplateField(parseTemplate(tmpl), fieldName)
 	result = collapseOrphanedSeparatorsRe.ReplaceAllString(result, " - ")
 	result = collapseMultiSpaceRe.ReplaceAllString(result, " ")
 	result = strings.TrimSpace(result)
@@ -200,15 +666,62 @@ func StripFieldFromTemplate(tmpl, fieldName string) string {
 	return result
 }
 
-// ReplaceFieldInTemplate replaces all occurrences of {oldName} with {newName} in a template string (case-insensitive).
+func stripTemplateField(nodes []templateNode, fieldName string) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch {
+		case node.kind == templateNodePlaceholder && strings.EqualFold(node.name, fieldName):
+		case node.kind == templateNodeSection && !node.unclosed && strings.EqualFold(node.name, fieldName):
+			if node.cond.matches(templateValue{}) {
+				b.WriteString(stripTemplateField(node.then, fieldName))
+			} else {
+				b.WriteString(stripTemplateField(node.otherwise, fieldName))
+			}
+		case node.kind == templateNodeSection:
+			b.WriteString(node.source(stripTemplateField(node.then, fieldName), stripTemplateField(node.otherwise, fieldName)))
+		default:
+			b.WriteString(node.raw)
+		}
+	}
+	return b.String()
+}
+
+// ReplaceFieldInTemplate replaces all occurrences of {oldName} with {newName} in a template string
+// (case-insensitive), in placeholders and conditions alike.
 func ReplaceFieldInTemplate(tmpl, oldName, newName string) string {
 	if tmpl == "" {
 		return ""
 	}
-	re := fieldRegex(oldName)
-	// Use ReplaceAllLiteralString so that $ and \ characters in newName are treated as literals,
-	// not as regex replacement references.
-	return re.ReplaceAllLiteralString(tmpl, "{"+newName+"}")
+	return replaceTemplateField(parseTemplate(tmpl), oldName, newName)
+}
+
+func replaceTemplateField(nodes []templateNode, oldName, newName string) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch {
+		case node.kind == templateNodePlaceholder && strings.EqualFold(node.name, oldName):
+			b.WriteString("{" + newName + node.filtersRaw + "}")
+		case node.kind == templateNodeSection:
+			if !node.unclosed && strings.EqualFold(node.name, oldName) {
+				node.raw = "{#if " + node.cond.source(newName) + "}"
+			}
+			b.WriteString(node.source(replaceTemplateField(node.then, oldName, newName), replaceTemplateField(node.otherwise, oldName, newName)))
+		default:
+			b.WriteString(node.raw)
+		}
+	}
+	return b.String()
+}
+
+// source returns the condition as written for the given name.
+func (c templateCondition) source(name string) string {
+	switch {
+	case c.negate:
+		return "!" + name
+	case c.op != "":
+		return name + " " + c.op + " " + c.valueRaw
+	}
+	return name
 }
 
 // DefaultFormatPropertyValue formats a property field's raw JSON value as a human-readable string.
@@ -223,6 +736,12 @@ func DefaultFormatPropertyValue(field *PropertyField, raw json.RawMessage) (stri
 
 	switch field.Type {
 	case model.PropertyFieldTypeText:
+		if field.IsNumber() {
+			var numValue float64
+			if err := json.Unmarshal(raw, &numValue); err == nil {
+				return strconv.FormatFloat(numValue, 'f', -1, 64), false
+			}
+		}
 		var stringValue string
 		if err := json.Unmarshal(raw, &stringValue); err != nil {
 			return string(raw), false
@@ -275,24 +794,28 @@ func DefaultFormatPropertyValue(field *PropertyField, raw json.RawMessage) (stri
 		return strings.Join(labels, ", "), false
 
 	case model.PropertyFieldTypeDate:
+		layout := "2006-01-02"
+		if field.IsDateTime() {
+			layout = "2006-01-02 15:04 MST"
+		}
 		// First try unmarshaling as a number (JSON numeric date stored as millis)
 		var numValue float64
 		if json.Unmarshal(raw, &numValue) == nil && numValue > epochMsMin {
-			return time.UnixMilli(int64(numValue)).UTC().Format("2006-01-02"), false
+			return time.UnixMilli(int64(numValue)).UTC().Format(layout), false
 		}
 		var stringValue string
 		if err := json.Unmarshal(raw, &stringValue); err != nil {
 			return string(raw), false
 		}
 		if t, err := time.Parse(time.RFC3339, stringValue); err == nil {
-			return t.Format("2006-01-02"), false
+			return t.Format(layout), false
 		}
 		if t, err := time.Parse("2006-01-02", stringValue); err == nil {
-			return t.Format("2006-01-02"), false
+			return t.Format(layout), false
 		}
 		// Numeric-string millisecond timestamp
 		if ms, err := strconv.ParseInt(stringValue, 10, 64); err == nil && ms > epochMsMin {
-			return time.UnixMilli(ms).UTC().Format("2006-01-02"), false
+			return time.UnixMilli(ms).UTC().Format(layout), false
 		}
 		return stringValue, false
 
@@ -6,6 +6,7 @@ package app
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/stretchr/testify/require"
@@ -402,6 +403,199 @@ func TestReplaceFieldInTemplate(t *testing.T) {
 	})
 }
 
+func TestFormatElapsed(t *testing.T) {
+	require.Equal(t, "0m", formatElapsed(0))
+	require.Equal(t, "0m", formatElapsed(-time.Hour))
+	require.Equal(t, "5m", formatElapsed(5*time.Minute+30*time.Second))
+	require.Equal(t, "3h 20m", formatElapsed(3*time.Hour+20*time.Minute))
+	require.Equal(t, "2d 3h", formatElapsed(51*time.Hour+59*time.Minute))
+}
+
+// ---------------------------------------------------------------------------
+// Filters and conditional sections
+// ---------------------------------------------------------------------------
+
+func TestResolveTemplateFilters(t *testing.T) {
+	region := textField("fid1", "Region")
+	opened := dateField("fid2", "Opened")
+	opts := func(values map[string]json.RawMessage) ResolveOptions {
+		return ResolveOptions{
+			Fields:       []PropertyField{region, opened},
+			Values:       values,
+			SystemTokens: map[string]string{"OWNER": "Alice Smith", "DATE": "2025-03-04"},
+		}
+	}
+
+	t.Run("upper and lower", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("{Owner|upper} / {owner | lower}", opts(nil))
+		require.Equal(t, "ALICE SMITH / alice smith", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("default used for missing value", func(t *testing.T) {
+		result, unresolved := ResolveTemplate(`{Region|default:"global"}`, opts(nil))
+		require.Equal(t, "global", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("default ignored when value is set", func(t *testing.T) {
+		result, unresolved := ResolveTemplate(`{Region|default:"global"|upper}`, opts(map[string]json.RawMessage{"fid1": mustJSON("emea")}))
+		require.Equal(t, "EMEA", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("date formats date fields", func(t *testing.T) {
+		result, unresolved := ResolveTemplate(`{Opened|date:"Jan 2, 2006"}`, opts(map[string]json.RawMessage{"fid2": mustJSON("2025-01-15T10:30:00Z")}))
+		require.Equal(t, "Jan 15, 2025", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("date formats the DATE token", func(t *testing.T) {
+		result, unresolved := ResolveTemplate(`{DATE|date:"02/01/2006"}`, opts(nil))
+		require.Equal(t, "04/03/2025", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("filters on a missing value leave it unresolved", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("{Region|upper}", opts(nil))
+		require.Equal(t, "{Region|upper}", result)
+		require.Equal(t, []string{"Region"}, unresolved)
+	})
+
+	t.Run("unknown filter is read as a plain name", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("{Region|shout}", opts(nil))
+		require.Equal(t, "{Region|shout}", result)
+		require.Equal(t, []string{"Region|shout"}, unresolved)
+	})
+
+	t.Run("field named like a context token takes precedence", func(t *testing.T) {
+		team := textField("fid3", "Team")
+		result, unresolved := ResolveTemplate("{TEAM}", ResolveOptions{
+			Fields:       []PropertyField{team},
+			Values:       map[string]json.RawMessage{"fid3": mustJSON("Payments")},
+			SystemTokens: map[string]string{"TEAM": "Engineering"},
+		})
+		require.Equal(t, "Payments", result)
+		require.Nil(t, unresolved)
+	})
+}
+
+func TestResolveTemplateSections(t *testing.T) {
+	severity := selectField("fid1", "Severity",
+		model.NewPluginPropertyOption("opt-1", "SEV1"),
+		model.NewPluginPropertyOption("opt-2", "SEV 2"),
+	)
+	services := multiselectField("fid2", "Services",
+		model.NewPluginPropertyOption("opt-3", "Payments"),
+		model.NewPluginPropertyOption("opt-4", "Auth"),
+	)
+	opts := func(values map[string]json.RawMessage) ResolveOptions {
+		return ResolveOptions{Fields: []PropertyField{severity, services}, Values: values}
+	}
+
+	t.Run("equality on select option name", func(t *testing.T) {
+		tmpl := "{#if Severity == SEV1}URGENT {/if}{Severity}"
+		result, unresolved := ResolveTemplate(tmpl, opts(map[string]json.RawMessage{"fid1": mustJSON("opt-1")}))
+		require.Equal(t, "URGENT SEV1", result)
+		require.Nil(t, unresolved)
+
+		result, unresolved = ResolveTemplate(tmpl, opts(map[string]json.RawMessage{"fid1": mustJSON("opt-2")}))
+		require.Equal(t, "SEV 2", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("quoted values and inequality", func(t *testing.T) {
+		result, _ := ResolveTemplate(`{#if Severity != "sev 2"}high{else}low{/if}`, opts(map[string]json.RawMessage{"fid1": mustJSON("opt-2")}))
+		require.Equal(t, "low", result)
+	})
+
+	t.Run("equality matches one of several values", func(t *testing.T) {
+		result, _ := ResolveTemplate("{#if Services == auth}auth impacted{/if}", opts(map[string]json.RawMessage{"fid2": mustJSON([]string{"opt-3", "opt-4"})}))
+		require.Equal(t, "auth impacted", result)
+	})
+
+	t.Run("missing values are not unresolved in conditions", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("{#if Severity}{Severity}{else}untriaged{/if}{#if !Services} - no services{/if}", opts(nil))
+		require.Equal(t, "untriaged - no services", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("nested sections", func(t *testing.T) {
+		tmpl := "{#if Severity}{#if Services}{Severity} on {Services}{else}{Severity}{/if}{/if}"
+		result, unresolved := ResolveTemplate(tmpl, opts(map[string]json.RawMessage{
+			"fid1": mustJSON("opt-1"),
+			"fid2": mustJSON([]string{"opt-3"}),
+		}))
+		require.Equal(t, "SEV1 on Payments", result)
+		require.Nil(t, unresolved)
+	})
+
+	t.Run("unknown field in condition is unresolved", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("{#if Region}x{/if}y", opts(nil))
+		require.Equal(t, "y", result)
+		require.Equal(t, []string{"Region"}, unresolved)
+	})
+
+	t.Run("unclosed section is kept as written", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("{#if Severity}x", opts(nil))
+		require.Equal(t, "{#if Severity}x", result)
+		require.Equal(t, []string{"#if Severity"}, unresolved)
+	})
+
+	t.Run("stray end tag is kept as written", func(t *testing.T) {
+		result, unresolved := ResolveTemplate("x{/if}", opts(nil))
+		require.Equal(t, "x{/if}", result)
+		require.Equal(t, []string{"/if"}, unresolved)
+	})
+}
+
+func TestValidateTemplateSections(t *testing.T) {
+	fields := []PropertyField{textField("fid1", "Region")}
+
+	t.Run("names in every branch are checked", func(t *testing.T) {
+		unknown := ValidateTemplate("{#if Region == emea}{Foo}{else}{Bar|upper}{/if}{#if Baz}{/if}", ResolveOptions{Fields: fields})
+		require.Equal(t, []string{"Foo", "Bar", "Baz"}, unknown)
+	})
+
+	t.Run("filters and context tokens are valid", func(t *testing.T) {
+		require.Nil(t, ValidateTemplate(`{TEAM|upper} {PLAYBOOK} {DATE|date:"Jan 2"} {Region|default:"global"}`, ResolveOptions{Fields: fields}))
+	})
+
+	t.Run("malformed sections are returned", func(t *testing.T) {
+		require.Equal(t, []string{"#if Region"}, ValidateTemplate("{#if Region}", ResolveOptions{Fields: fields}))
+		require.Equal(t, []string{"#if", "/if"}, ValidateTemplate("{#if}{/if}", ResolveOptions{Fields: fields}))
+	})
+
+	t.Run("context tokens are not reserved field names", func(t *testing.T) {
+		require.NoError(t, validateReservedFieldName("Date"))
+		require.Error(t, validateReservedFieldName("Owner"))
+	})
+}
+
+func TestTemplateSectionsRewrite(t *testing.T) {
+	t.Run("SEQ with filters is detected", func(t *testing.T) {
+		require.True(t, TemplateUsesSeqToken("{SEQ|lower} - x"))
+		require.True(t, TemplateUsesSeqToken("{#if SEQ}{SEQ}{/if}"))
+	})
+
+	t.Run("strip keeps the branch of a missing value", func(t *testing.T) {
+		require.Equal(t, "INC - untriaged", StripFieldFromTemplate("INC - {#if Severity}{Severity|upper}{else}untriaged{/if}", "Severity"))
+		require.Equal(t, "INC", StripFieldFromTemplate("INC{#if Severity == SEV1} - urgent{/if}", "Severity"))
+	})
+
+	t.Run("strip inside sections on other fields", func(t *testing.T) {
+		require.Equal(t, "{#if Region}{Region}{/if}", StripFieldFromTemplate("{#if Region}{Region}{Severity}{/if}", "Severity"))
+	})
+
+	t.Run("replace keeps filters and conditions", func(t *testing.T) {
+		require.Equal(t,
+			`{#if Sev == "SEV 1"}{Sev|upper}{else}{Region}{/if}`,
+			ReplaceFieldInTemplate(`{#if Severity == "SEV 1"}{severity|upper}{else}{Region}{/if}`, "Severity", "Sev"),
+		)
+		require.Equal(t, "{#if !Sev}x{/if}", ReplaceFieldInTemplate("{#if  !Severity }x{/if}", "Severity", "Sev"))
+	})
+}
+
 // ---------------------------------------------------------------------------
 // DefaultFormatPropertyValue
 // ---------------------------------------------------------------------------
@@ -494,8 +688,37 @@ func TestDefaultFormatPropertyValue(t *testing.T) {
 		require.False(t, empty)
 	})
 
+	// --- number ---
+
+	t.Run("number field formats without trailing zeros", func(t *testing.T) {
+		f := textField("fid1", "Customers")
+		f.Attrs.ValueType = PropertyValueTypeNumber
+		s, empty := DefaultFormatPropertyValue(&f, json.RawMessage(`1250`))
+		require.Equal(t, "1250", s)
+		require.False(t, empty)
+
+		s, _ = DefaultFormatPropertyValue(&f, json.RawMessage(`2.5`))
+		require.Equal(t, "2.5", s)
+	})
+
+	t.Run("number field falls back to legacy string values", func(t *testing.T) {
+		f := textField("fid1", "Customers")
+		f.Attrs.ValueType = PropertyValueTypeNumber
+		s, empty := DefaultFormatPropertyValue(&f, mustJSON("about 20"))
+		require.Equal(t, "about 20", s)
+		require.False(t, empty)
+	})
+
 	// --- date ---
 
+	t.Run("datetime field includes the time of day", func(t *testing.T) {
+		f := dateField("fid1", "Detected")
+		f.Attrs.ValueType = PropertyValueTypeDateTime
+		s, empty := DefaultFormatPropertyValue(&f, mustJSON("2024-03-15T14:30:00Z"))
+		require.Equal(t, "2024-03-15 14:30 UTC", s)
+		require.False(t, empty)
+	})
+
 	t.Run("date field with RFC3339 string formats as YYYY-MM-DD", func(t *testing.T) {
 		f := dateField("fid1", "Due")
 		s, empty := DefaultFormatPropertyValue(&f, mustJSON("2024-03-15T00:00:00Z"))
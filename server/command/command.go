@@ -27,9 +27,9 @@ const helpText = "###### Mattermost Playbooks Plugin - Slash Command Help\n" +
 	"* `/playbook run` - Run a playbook\n" +
 	"* `/playbook finish` - Finish the playbook run in this channel. \n" +
 	"* `/playbook update` - Provide a status update. \n" +
-	"* `/playbook check [checklist #] [item #]` - check/uncheck the checklist item. \n" +
+	"* `/playbook check [checklist #] [item #]` - check/uncheck the checklist item. The item ID can be given instead of the numbers. \n" +
 	"* `/playbook checkadd [checklist #] [item text]` - add a checklist item. \n" +
-	"* `/playbook checkremove [checklist #] [item #]` - remove a checklist item. \n" +
+	"* `/playbook checkremove [checklist #] [item #]` - remove a checklist item. The item ID can be given instead of the numbers. \n" +
 	"* `/playbook owner [@username]` - Show or change the current owner. \n" +
 	"* `/playbook info` - Show a summary of the current playbook run. \n" +
 	"* `/playbook timeline` - Show the timeline for the current playbook run. \n" +
@@ -348,6 +348,17 @@ func (r *Runner) actionCheck(args []string) {
 		return
 	}
 
+	if len(args) == 1 && model.IsValidId(args[0]) {
+		playbookRun, ok := r.checklistItemRunByID(playbookRuns, args[0])
+		if !ok {
+			return
+		}
+		if err = r.playbookRunService.ToggleCheckedStateByID(playbookRun.ID, r.args.UserId, app.ChecklistItemRef{ItemID: args[0]}); err != nil {
+			r.warnUserAndLogErrorf("Error checking/unchecking item: %v", err)
+		}
+		return
+	}
+
 	multipleRuns := len(playbookRuns) > 1
 
 	if !multipleRuns && len(args) != 2 {
@@ -391,6 +402,10 @@ func (r *Runner) actionCheck(args []string) {
 		r.postCommandResponse("Become a participant to interact with this run.")
 		return
 	}
+	if err = r.permissions.RunEditChecklists(r.args.UserId, playbookRuns[run].ID); err != nil {
+		r.postCommandResponse("You need a run role allowed to edit checklists to interact with this run's checklists.")
+		return
+	}
 
 	err = r.playbookRunService.ToggleCheckedState(playbookRuns[run].ID, r.args.UserId, checklist, item)
 	if err != nil {
@@ -446,6 +461,10 @@ func (r *Runner) actionAddChecklistItem(args []string) {
 		r.postCommandResponse("Become a participant to interact with this run.")
 		return
 	}
+	if err = r.permissions.RunEditChecklists(r.args.UserId, playbookRuns[run].ID); err != nil {
+		r.postCommandResponse("You need a run role allowed to edit checklists to interact with this run's checklists.")
+		return
+	}
 
 	// If we didn't get the item's text, then use the interactive dialog
 	if len(args) == index {
@@ -476,6 +495,17 @@ func (r *Runner) actionRemoveChecklistItem(args []string) {
 		return
 	}
 
+	if len(args) == 1 && model.IsValidId(args[0]) {
+		playbookRun, ok := r.checklistItemRunByID(playbookRuns, args[0])
+		if !ok {
+			return
+		}
+		if err = r.playbookRunService.RemoveChecklistItemByID(playbookRun.ID, r.args.UserId, app.ChecklistItemRef{ItemID: args[0]}); err != nil {
+			r.warnUserAndLogErrorf("Error removing item: %v", err)
+		}
+		return
+	}
+
 	multipleRuns := len(playbookRuns) > 1
 
 	if !multipleRuns && len(args) != 2 {
@@ -519,6 +549,10 @@ func (r *Runner) actionRemoveChecklistItem(args []string) {
 		r.postCommandResponse("Become a participant to interact with this run.")
 		return
 	}
+	if err = r.permissions.RunEditChecklists(r.args.UserId, playbookRuns[run].ID); err != nil {
+		r.postCommandResponse("You need a run role allowed to edit checklists to interact with this run's checklists.")
+		return
+	}
 
 	err = r.playbookRunService.RemoveChecklistItem(playbookRuns[run].ID, r.args.UserId, checklist, item)
 	if err != nil {
@@ -526,6 +560,37 @@ func (r *Runner) actionRemoveChecklistItem(args []string) {
 	}
 }
 
+// checklistItemRunByID returns the run of the channel holding the checklist item with the given ID,
+// once the user is checked to be allowed to edit its checklists. It responds to the user and returns
+// false otherwise.
+func (r *Runner) checklistItemRunByID(playbookRuns []app.PlaybookRun, itemID string) (*app.PlaybookRun, bool) {
+	var playbookRun *app.PlaybookRun
+	for i := range playbookRuns {
+		for _, checklist := range playbookRuns[i].Checklists {
+			for _, item := range checklist.Items {
+				if item.ID == itemID {
+					playbookRun = &playbookRuns[i]
+				}
+			}
+		}
+	}
+	if playbookRun == nil {
+		r.postCommandResponse("No checklist item with this ID in the runs of this channel.")
+		return nil, false
+	}
+
+	if err := r.permissions.RunManageProperties(r.args.UserId, playbookRun.ID); err != nil {
+		r.postCommandResponse("Become a participant to interact with this run.")
+		return nil, false
+	}
+	if err := r.permissions.RunEditChecklists(r.args.UserId, playbookRun.ID); err != nil {
+		r.postCommandResponse("You need a run role allowed to edit checklists to interact with this run's checklists.")
+		return nil, false
+	}
+
+	return playbookRun, true
+}
+
 func (r *Runner) actionOwner(args []string) {
 	playbookRuns, err := r.playbookRunService.GetPlaybookRunsForChannelByUser(r.args.ChannelId, r.args.UserId)
 	if err != nil {
@@ -827,6 +892,10 @@ func (r *Runner) actionUpdate(args []string) {
 		r.warnUserAndLogErrorf("Error retrieving playbook run: %v", err)
 		return
 	}
+	if err = r.permissions.RunUpdateStatus(r.args.UserId, playbookRuns[run].ID); err != nil {
+		r.postCommandResponse("You need a run role allowed to post status updates to update this run.")
+		return
+	}
 
 	err = r.playbookRunService.OpenUpdateStatusDialog(playbookRuns[run].ID, r.args.UserId, r.args.TriggerId)
 	switch {
@@ -1407,7 +1476,7 @@ func (r *Runner) actionTestCreate(params []string) {
 		r.postCommandResponse("The first parameter, <playbook_id>, must be a valid ID.")
 		return
 	}
-	playbook, err := r.playbookService.Get(playbookID)
+	playbook, err := r.playbookService.GetPublished(playbookID)
 	if err != nil {
 		r.postCommandResponse(fmt.Sprintf("The playbook with ID '%s' does not exist.", playbookID))
 		return
@@ -1938,7 +2007,7 @@ func (r *Runner) generateTestData(numActivePlaybookRuns, numEndedPlaybookRuns in
 	} else {
 		playbooks = make([]app.Playbook, 0, len(filteredItems))
 		for _, thePlaybook := range filteredItems {
-			wholePlaybook, err := r.playbookService.Get(thePlaybook.ID)
+			wholePlaybook, err := r.playbookService.GetPublished(thePlaybook.ID)
 			if err != nil {
 				r.warnUserAndLogErrorf("Error getting playbook: %v", err)
 				return
@@ -44,6 +44,10 @@ type Service interface {
 	// This allows the server to send only changed fields in WebSocket events instead of full objects.
 	IsIncrementalUpdatesEnabled() bool
 
+	// IsRunChangeFeedEnabled returns true when run changes are recorded in the change feed
+	// streamed to external subscribers.
+	IsRunChangeFeedEnabled() bool
+
 	// IsExperimentalFeaturesEnabled returns true when experimental features are enabled.
 	IsExperimentalFeaturesEnabled() bool
 }
@@ -28,6 +28,10 @@ type Configuration struct {
 	// This is set to false by default for backward compatibility.
 	EnableIncrementalUpdates bool `json:"enableincrementalupdates"`
 
+	// EnableRunChangeFeed controls whether run changes are recorded in the change feed served
+	// to external subscribers. Like incremental updates, it requires diffing every run update.
+	EnableRunChangeFeed bool `json:"enablerunchangefeed"`
+
 	// EnableExperimentalFeatures controls whether experimental features are enabled in the plugin.
 	// These features may have in-progress UI, bugs, and other issues.
 	EnableExperimentalFeatures bool `json:"enableexperimentalfeatures"`
@@ -54,6 +58,7 @@ func (c *Configuration) serialize() map[string]any {
 	ret["teamsTabAppTenantIDs"] = c.TeamsTabAppTenantIDs
 	ret["TeamsTabAppBotUserID"] = c.TeamsTabAppBotUserID
 	ret["enableincrementalupdates"] = c.EnableIncrementalUpdates
+	ret["EnableRunChangeFeed"] = c.EnableRunChangeFeed
 	ret["EnableExperimentalFeatures"] = c.EnableExperimentalFeatures
 	ret["ExposeMCPExternal"] = c.ExposeMCPExternal
 	return ret
@@ -293,6 +293,11 @@ func (c *ServiceImpl) IsIncrementalUpdatesEnabled() bool {
 	return c.GetConfiguration().EnableIncrementalUpdates
 }
 
+// IsRunChangeFeedEnabled returns true when run changes are recorded in the change feed.
+func (c *ServiceImpl) IsRunChangeFeedEnabled() bool {
+	return c.GetConfiguration().EnableRunChangeFeed
+}
+
 // IsExperimentalFeaturesEnabled returns true when experimental features are enabled.
 func (c *ServiceImpl) IsExperimentalFeaturesEnabled() bool {
 	return c.GetConfiguration().EnableExperimentalFeatures
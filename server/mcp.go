@@ -178,6 +178,7 @@ func newPlaybooksMCPServer(api mcphelper.PluginAPI, handler http.Handler, expose
 		return nil, fmt.Errorf("failed to create tool provider: %w", err)
 	}
 	provider.ProvideMCPHelperTools(server)
+	provider.ProvideMCPHelperResources(server)
 	return server, nil
 }
 
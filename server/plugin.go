@@ -200,6 +200,7 @@ func (p *Plugin) OnActivate() error {
 		p.propertyService,
 		p.conditionService,
 	)
+	p.channelActionService.SetRunService(p.playbookRunService)
 
 	if err = scheduler.SetCallback(p.playbookRunService.HandleReminder); err != nil {
 		logrus.WithError(err).Error("JobOnceScheduler could not add the playbookRunService's HandleReminder")
@@ -221,6 +222,7 @@ func (p *Plugin) OnActivate() error {
 	mutex.Unlock()
 
 	p.permissions = app.NewPermissionsService(p.playbookService, p.playbookRunService, pluginAPIClient, p.config, p.licenseChecker)
+	p.channelActionService.SetPermissions(p.permissions)
 
 	api.NewGraphQLHandler(
 		p.handler.APIRouter,
@@ -238,6 +240,7 @@ func (p *Plugin) OnActivate() error {
 	api.NewPlaybookHandler(
 		p.handler.APIRouter,
 		p.playbookService,
+		app.NewPlaybookBundleService(playbookStore, p.playbookService, p.propertyService, p.conditionService, p.channelActionService, auditorService),
 		p.propertyService,
 		pluginAPIClient,
 		p.config,
@@ -255,13 +258,20 @@ func (p *Plugin) OnActivate() error {
 		p.bot,
 		p.config,
 	)
-	api.NewStatsHandler(p.handler.APIRouter, pluginAPIClient, statsStore, p.playbookService, p.permissions, p.licenseChecker)
+	api.NewStatsHandler(p.handler.APIRouter, pluginAPIClient, statsStore, p.playbookService, p.propertyService, p.permissions, p.licenseChecker)
 	api.NewBotHandler(p.handler.APIRouter, pluginAPIClient, p.bot, p.config, p.playbookRunService, p.userInfoStore)
 	api.NewSignalHandler(p.handler.APIRouter, pluginAPIClient, p.playbookRunService, p.playbookService, keywordsThreadIgnorer, p.bot)
 	api.NewSettingsHandler(p.handler.APIRouter, pluginAPIClient, p.config)
-	api.NewActionsHandler(p.handler.APIRouter, p.channelActionService, p.pluginAPI, p.permissions)
+	api.NewActionsHandler(p.handler.APIRouter, p.channelActionService, p.playbookService, p.propertyService, p.pluginAPI, p.permissions)
 	api.NewCategoryHandler(p.handler.APIRouter, pluginAPIClient, p.categoryService, p.playbookService, p.playbookRunService, p.permissions)
 	api.NewConditionHandler(p.handler.APIRouter, p.conditionService, p.playbookService, p.playbookRunService, p.propertyService, p.permissions, pluginAPIClient)
+	api.NewPlaybookRevisionHandler(
+		p.handler.APIRouter,
+		app.NewPlaybookRevisionService(playbookStore, p.playbookService, p.propertyService, p.conditionService, auditorService),
+		p.playbookService,
+		p.playbookRunService,
+		p.permissions,
+	)
 	api.NewTabAppHandler(
 		p.handler,
 		p.playbookRunService,
@@ -344,6 +354,18 @@ func (p *Plugin) UserHasJoinedChannel(c *plugin.Context, channelMember *model.Ch
 	p.channelActionService.UserHasJoinedChannel(channelMember.UserId, channelMember.ChannelId, actorID)
 }
 
+func (p *Plugin) UserHasLeftChannel(c *plugin.Context, channelMember *model.ChannelMember, actor *model.User) {
+	actorID := ""
+	if actor != nil && actor.Id != channelMember.UserId {
+		actorID = actor.Id
+	}
+	p.channelActionService.UserHasLeftChannel(channelMember.UserId, channelMember.ChannelId, actorID)
+}
+
+func (p *Plugin) ReactionHasBeenAdded(c *plugin.Context, reaction *model.Reaction) {
+	p.channelActionService.ReactionHasBeenAdded(reaction)
+}
+
 func (p *Plugin) MessageHasBeenPosted(c *plugin.Context, post *model.Post) {
 	p.channelActionService.MessageHasBeenPosted(post)
 	p.playbookRunService.MessageHasBeenPosted(post)
@@ -163,6 +163,42 @@ func (c *channelActionStore) GetChannelActions(channelID string, options app.Get
 				Payload:                            categorizeChannelPayload,
 			}
 
+			actions = append(actions, action)
+		case app.ActionTypeStartRun:
+			var startRunPayload app.StartRunPayload
+			if err := json.Unmarshal(sqlAction.Payload, &startRunPayload); err != nil {
+				return nil, errors.Wrapf(err, fmt.Sprintf("unable to unmarshal payload for action with ID %q and type %q", sqlAction.ID, sqlAction.ActionType), channelID)
+			}
+
+			action := app.GenericChannelAction{
+				GenericChannelActionWithoutPayload: sqlAction.GenericChannelActionWithoutPayload,
+				Payload:                            startRunPayload,
+			}
+
+			actions = append(actions, action)
+		case app.ActionTypeAddToRunTimeline:
+			var addToRunTimelinePayload app.AddToRunTimelinePayload
+			if err := json.Unmarshal(sqlAction.Payload, &addToRunTimelinePayload); err != nil {
+				return nil, errors.Wrapf(err, fmt.Sprintf("unable to unmarshal payload for action with ID %q and type %q", sqlAction.ID, sqlAction.ActionType), channelID)
+			}
+
+			action := app.GenericChannelAction{
+				GenericChannelActionWithoutPayload: sqlAction.GenericChannelActionWithoutPayload,
+				Payload:                            addToRunTimelinePayload,
+			}
+
+			actions = append(actions, action)
+		case app.ActionTypeSetRunProperty:
+			var setRunPropertyPayload app.SetRunPropertyPayload
+			if err := json.Unmarshal(sqlAction.Payload, &setRunPropertyPayload); err != nil {
+				return nil, errors.Wrapf(err, fmt.Sprintf("unable to unmarshal payload for action with ID %q and type %q", sqlAction.ID, sqlAction.ActionType), channelID)
+			}
+
+			action := app.GenericChannelAction{
+				GenericChannelActionWithoutPayload: sqlAction.GenericChannelActionWithoutPayload,
+				Payload:                            setRunPropertyPayload,
+			}
+
 			actions = append(actions, action)
 		}
 	}
@@ -1799,4 +1799,131 @@ var migrations = []Migration{
 			return nil
 		},
 	},
+	{
+		fromVersion: semver.MustParse("0.68.0"),
+		toVersion:   semver.MustParse("0.69.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if err := addColumnToPGTable(e, "IR_Playbook", "ExportKey", "VARCHAR(64) NOT NULL DEFAULT ''"); err != nil {
+				return errors.Wrapf(err, "failed adding column ExportKey to IR_Playbook")
+			}
+
+			if _, err := e.Exec(createPGIndex("IR_Playbook_TeamID_ExportKey", "IR_Playbook", "TeamID, ExportKey")); err != nil {
+				return errors.Wrapf(err, "failed creating index IR_Playbook_TeamID_ExportKey")
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.69.0"),
+		toVersion:   semver.MustParse("0.70.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if _, err := e.Exec(`
+				CREATE TABLE IF NOT EXISTS IR_PlaybookRevision (
+					ID VARCHAR(26) PRIMARY KEY,
+					PlaybookID VARCHAR(26) NOT NULL REFERENCES IR_Playbook(ID),
+					Revision BIGINT NOT NULL,
+					UserID VARCHAR(26) NOT NULL DEFAULT '',
+					CreateAt BIGINT NOT NULL,
+					Snapshot JSON NOT NULL
+				)
+			`); err != nil {
+				return errors.Wrapf(err, "failed creating table IR_PlaybookRevision")
+			}
+
+			if _, err := e.Exec(createUniquePGIndex("IR_PlaybookRevision_PlaybookID_Revision", "IR_PlaybookRevision", "PlaybookID, Revision")); err != nil {
+				return errors.Wrapf(err, "failed creating index IR_PlaybookRevision_PlaybookID_Revision")
+			}
+
+			if err := addColumnToPGTable(e, "IR_Playbook", "Revision", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+				return errors.Wrapf(err, "failed adding column Revision to IR_Playbook")
+			}
+			if err := addColumnToPGTable(e, "IR_Incident", "PlaybookRevision", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+				return errors.Wrapf(err, "failed adding column PlaybookRevision to IR_Incident")
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.70.0"),
+		toVersion:   semver.MustParse("0.71.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if err := addColumnToPGTable(e, "IR_Playbook", "PublishedRevision", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+				return errors.Wrapf(err, "failed adding column PublishedRevision to IR_Playbook")
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.71.0"),
+		toVersion:   semver.MustParse("0.72.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if err := addColumnToPGTable(e, "IR_Playbook", "RunRolesJSON", "JSON NOT NULL DEFAULT '[]'"); err != nil {
+				return errors.Wrapf(err, "failed adding column RunRolesJSON to IR_Playbook")
+			}
+			if err := addColumnToPGTable(e, "IR_Incident", "RunRolesJSON", "JSON NOT NULL DEFAULT '[]'"); err != nil {
+				return errors.Wrapf(err, "failed adding column RunRolesJSON to IR_Incident")
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.72.0"),
+		toVersion:   semver.MustParse("0.73.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if _, err := e.Exec(`
+				CREATE TABLE IF NOT EXISTS IR_PropertyValueChange (
+					ID VARCHAR(26) PRIMARY KEY,
+					PlaybookRunID VARCHAR(26) NOT NULL,
+					FieldID VARCHAR(26) NOT NULL,
+					OldValue JSON NOT NULL,
+					NewValue JSON NOT NULL,
+					UserID VARCHAR(26) NOT NULL DEFAULT '',
+					CreateAt BIGINT NOT NULL
+				)
+			`); err != nil {
+				return errors.Wrapf(err, "failed creating table IR_PropertyValueChange")
+			}
+
+			if _, err := e.Exec(createPGIndex("IR_PropertyValueChange_PlaybookRunID_CreateAt", "IR_PropertyValueChange", "PlaybookRunID, CreateAt")); err != nil {
+				return errors.Wrapf(err, "failed creating index IR_PropertyValueChange_PlaybookRunID_CreateAt")
+			}
+			if _, err := e.Exec(createPGIndex("IR_PropertyValueChange_PlaybookRunID_FieldID_CreateAt", "IR_PropertyValueChange", "PlaybookRunID, FieldID, CreateAt")); err != nil {
+				return errors.Wrapf(err, "failed creating index IR_PropertyValueChange_PlaybookRunID_FieldID_CreateAt")
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.73.0"),
+		toVersion:   semver.MustParse("0.74.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if _, err := e.Exec(`
+				CREATE TABLE IF NOT EXISTS IR_RunChange (
+					Seq BIGSERIAL PRIMARY KEY,
+					Type VARCHAR(64) NOT NULL,
+					PlaybookRunID VARCHAR(26) NOT NULL,
+					TeamID VARCHAR(26) NOT NULL DEFAULT '',
+					ChannelID VARCHAR(26) NOT NULL DEFAULT '',
+					Payload JSON NOT NULL,
+					CreateAt BIGINT NOT NULL
+				)
+			`); err != nil {
+				return errors.Wrapf(err, "failed creating table IR_RunChange")
+			}
+
+			if _, err := e.Exec(createPGIndex("IR_RunChange_CreateAt", "IR_RunChange", "CreateAt")); err != nil {
+				return errors.Wrapf(err, "failed creating index IR_RunChange_CreateAt")
+			}
+			if _, err := e.Exec(createPGIndex("IR_RunChange_PlaybookRunID_Seq", "IR_RunChange", "PlaybookRunID, Seq")); err != nil {
+				return errors.Wrapf(err, "failed creating index IR_RunChange_PlaybookRunID_Seq")
+			}
+
+			return nil
+		},
+	},
 }
@@ -24,6 +24,7 @@ const pgUniqueViolation = "23505"
 type sqlPlaybook struct {
 	app.Playbook
 	ChecklistsJSON                        json.RawMessage
+	RunRolesJSON                          json.RawMessage
 	ConcatenatedInvitedUserIDs            string
 	ConcatenatedInvitedGroupIDs           string
 	ConcatenatedSignalAnyKeywords         string
@@ -168,11 +169,15 @@ func NewPlaybookStore(pluginAPI PluginAPIClient, sqlStore *SQLStore) app.Playboo
 			"p.ChannelMode",
 			"p.RunNumberPrefix",
 			"p.NextRunNumber",
+			"p.ExportKey",
+			"p.Revision",
+			"p.PublishedRevision",
 			"p.AdminOnlyEdit",
 			"p.OwnerGroupOnlyActions",
 			"p.NewChannelOnly",
 			"p.AutoArchiveChannel",
 			"p.ChecklistsJSON",
+			"p.RunRolesJSON",
 			"COALESCE(p.CategoryName, '') CategoryName",
 			"p.RunSummaryTemplateEnabled",
 			"COALESCE(p.RunSummaryTemplate, '') RunSummaryTemplate",
@@ -221,6 +226,15 @@ func NewPlaybookStore(pluginAPI PluginAPIClient, sqlStore *SQLStore) app.Playboo
 
 // Create creates a new playbook
 func (p *playbookStore) Create(playbook app.Playbook) (id string, err error) {
+	return p.create(playbook, nil)
+}
+
+// CreateWithRevision creates a playbook and stores its first revision in the same transaction.
+func (p *playbookStore) CreateWithRevision(playbook app.Playbook, revision app.PlaybookRevision) (string, error) {
+	return p.create(playbook, &revision)
+}
+
+func (p *playbookStore) create(playbook app.Playbook, revision *app.PlaybookRevision) (id string, err error) {
 	if playbook.ID == "" {
 		playbook.ID = model.NewId()
 	}
@@ -249,6 +263,7 @@ func (p *playbookStore) Create(playbook app.Playbook) (id string, err error) {
 			"UpdateAt":                                rawPlaybook.UpdateAt,
 			"DeleteAt":                                rawPlaybook.DeleteAt,
 			"ChecklistsJSON":                          rawPlaybook.ChecklistsJSON,
+			"RunRolesJSON":                            rawPlaybook.RunRolesJSON,
 			"NumStages":                               len(rawPlaybook.Checklists),
 			"NumSteps":                                getSteps(rawPlaybook.Playbook),
 			"ReminderMessageTemplate":                 rawPlaybook.ReminderMessageTemplate,
@@ -283,6 +298,7 @@ func (p *playbookStore) Create(playbook app.Playbook) (id string, err error) {
 			"ChannelMode":                             rawPlaybook.ChannelMode,
 			"RunNumberPrefix":                         rawPlaybook.RunNumberPrefix,
 			// NextRunNumber omitted: DB default (1) is always correct for new playbooks.
+			"ExportKey":             rawPlaybook.ExportKey,
 			"AdminOnlyEdit":         rawPlaybook.AdminOnlyEdit,
 			"OwnerGroupOnlyActions": rawPlaybook.OwnerGroupOnlyActions,
 			"NewChannelOnly":        rawPlaybook.NewChannelOnly,
@@ -303,6 +319,13 @@ func (p *playbookStore) Create(playbook app.Playbook) (id string, err error) {
 		return "", errors.Wrap(err, "failed to replace playbook metrics configs")
 	}
 
+	if revision != nil {
+		revision.PlaybookID = rawPlaybook.ID
+		if _, err = p.insertPlaybookRevision(tx, *revision); err != nil {
+			return "", err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return "", errors.Wrap(err, "could not commit transaction")
 	}
@@ -619,6 +642,21 @@ func (p *playbookStore) GetTimeLastUpdated(onlyPlaybooksWithKeywordsEnabled bool
 	return updateAt[0], nil
 }
 
+// GetPlaybookIDsForTeam retrieves the IDs of all playbooks of a team, archived ones included
+func (p *playbookStore) GetPlaybookIDsForTeam(teamID string) ([]string, error) {
+	queryForResults := p.store.builder.
+		Select("ID").
+		From("IR_Playbook").
+		Where(sq.Eq{"TeamID": teamID})
+
+	var playbookIDs []string
+	err := p.store.selectBuilder(p.store.db, &playbookIDs, queryForResults)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get playbookIDs for team - %v", teamID)
+	}
+	return playbookIDs, nil
+}
+
 // GetPlaybookIDsForUser retrieves playbooks user can access
 // Notice that method is not checking weather or not user is member of a team
 func (p *playbookStore) GetPlaybookIDsForUser(userID string, teamID string) ([]string, error) {
@@ -661,9 +699,16 @@ func (p *playbookStore) GraphqlUpdate(id string, setmap map[string]interface{})
 		}
 	}
 
+	// Every change to a playbook moves UpdateAt, which its ETag is built from.
+	updated := make(map[string]interface{}, len(setmap)+1)
+	for column, value := range setmap {
+		updated[column] = value
+	}
+	updated["UpdateAt"] = model.GetMillis()
+
 	_, err := p.store.execBuilder(p.store.db, sq.
 		Update("IR_Playbook").
-		SetMap(setmap).
+		SetMap(updated).
 		Where(sq.Eq{"ID": id}))
 
 	if err != nil {
@@ -674,7 +719,23 @@ func (p *playbookStore) GraphqlUpdate(id string, setmap map[string]interface{})
 }
 
 // Update updates a playbook
-func (p *playbookStore) Update(playbook app.Playbook) (err error) {
+func (p *playbookStore) Update(playbook app.Playbook) error {
+	return p.UpdateIfUnmodified(playbook, 0)
+}
+
+// UpdateIfUnmodified updates a playbook, failing with app.ErrStaleVersion unless its UpdateAt
+// is updateAt. An updateAt of 0 updates the playbook unconditionally.
+func (p *playbookStore) UpdateIfUnmodified(playbook app.Playbook, updateAt int64) error {
+	return p.update(playbook, updateAt, nil)
+}
+
+// UpdateWithRevision updates a playbook like UpdateIfUnmodified and stores the given revision of
+// it in the same transaction, so neither is saved without the other.
+func (p *playbookStore) UpdateWithRevision(playbook app.Playbook, updateAt int64, revision app.PlaybookRevision) error {
+	return p.update(playbook, updateAt, &revision)
+}
+
+func (p *playbookStore) update(playbook app.Playbook, updateAt int64, revision *app.PlaybookRevision) (err error) {
 	if playbook.ID == "" {
 		return errors.New("id should not be empty")
 	}
@@ -690,7 +751,12 @@ func (p *playbookStore) Update(playbook app.Playbook) (err error) {
 	}
 	defer p.store.finalizeTransaction(tx)
 
-	_, err = p.store.execBuilder(tx, sq.
+	where := sq.And{sq.Eq{"ID": rawPlaybook.ID}}
+	if updateAt != 0 {
+		where = append(where, sq.Eq{"UpdateAt": updateAt})
+	}
+
+	result, err := p.store.execBuilder(tx, sq.
 		Update("IR_Playbook").
 		SetMap(map[string]interface{}{
 			"Title":                                   rawPlaybook.Title,
@@ -701,6 +767,7 @@ func (p *playbookStore) Update(playbook app.Playbook) (err error) {
 			"UpdateAt":                                rawPlaybook.UpdateAt,
 			"DeleteAt":                                rawPlaybook.DeleteAt,
 			"ChecklistsJSON":                          rawPlaybook.ChecklistsJSON,
+			"RunRolesJSON":                            rawPlaybook.RunRolesJSON,
 			"NumStages":                               len(rawPlaybook.Checklists),
 			"NumSteps":                                getSteps(rawPlaybook.Playbook),
 			"ReminderMessageTemplate":                 rawPlaybook.ReminderMessageTemplate,
@@ -739,7 +806,7 @@ func (p *playbookStore) Update(playbook app.Playbook) (err error) {
 			"NewChannelOnly":                          rawPlaybook.NewChannelOnly,
 			"AutoArchiveChannel":                      rawPlaybook.AutoArchiveChannel,
 		}).
-		Where(sq.Eq{"ID": rawPlaybook.ID}))
+		Where(where))
 
 	if err != nil {
 		if pe, ok := errors.Cause(err).(*pq.Error); ok && pe.Code == pgUniqueViolation {
@@ -748,6 +815,16 @@ func (p *playbookStore) Update(playbook app.Playbook) (err error) {
 		return errors.Wrapf(err, "failed to update playbook with id '%s'", rawPlaybook.ID)
 	}
 
+	if updateAt != 0 {
+		affected, affectedErr := result.RowsAffected()
+		if affectedErr != nil {
+			return errors.Wrapf(affectedErr, "failed to read rows affected for playbook '%s'", rawPlaybook.ID)
+		}
+		if affected == 0 {
+			return errors.Wrapf(app.ErrStaleVersion, "playbook with id '%s'", rawPlaybook.ID)
+		}
+	}
+
 	if err = p.replacePlaybookMembers(tx, rawPlaybook.Playbook); err != nil {
 		return errors.Wrapf(err, "failed to replace playbook members for playbook with id '%s'", rawPlaybook.ID)
 	}
@@ -756,6 +833,13 @@ func (p *playbookStore) Update(playbook app.Playbook) (err error) {
 		return errors.Wrapf(err, "failed to replace playbook metrics configs for playbook with id '%s'", rawPlaybook.ID)
 	}
 
+	if revision != nil {
+		revision.PlaybookID = rawPlaybook.ID
+		if _, err = p.insertPlaybookRevision(tx, *revision); err != nil {
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return errors.Wrap(err, "could not commit transaction")
 	}
@@ -769,9 +853,11 @@ func (p *playbookStore) Archive(id string) error {
 		return errors.New("ID cannot be empty")
 	}
 
+	now := model.GetMillis()
 	_, err := p.store.execBuilder(p.store.db, sq.
 		Update("IR_Playbook").
-		Set("DeleteAt", model.GetMillis()).
+		Set("DeleteAt", now).
+		Set("UpdateAt", now).
 		Where(sq.Eq{"ID": id}))
 
 	if err != nil {
@@ -790,6 +876,7 @@ func (p *playbookStore) Restore(id string) error {
 	_, err := p.store.execBuilder(p.store.db, sq.
 		Update("IR_Playbook").
 		Set("DeleteAt", 0).
+		Set("UpdateAt", model.GetMillis()).
 		Where(sq.Eq{"ID": id}))
 
 	if err != nil {
@@ -876,6 +963,38 @@ func (p *playbookStore) IsRunNumberPrefixUsed(teamID, prefix, excludePlaybookID
 	return count > 0, nil
 }
 
+// GetPlaybookIDsByExportKeys returns the IDs of the active playbooks in teamID matching the given
+// export keys, keyed by export key.
+func (p *playbookStore) GetPlaybookIDsByExportKeys(teamID string, exportKeys []string) (map[string]string, error) {
+	result := make(map[string]string, len(exportKeys))
+	if len(exportKeys) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		ID        string
+		ExportKey string
+	}
+	query := p.store.builder.
+		Select("ID", "ExportKey").
+		From("IR_Playbook").
+		Where(sq.Eq{"TeamID": teamID, "DeleteAt": 0}).
+		Where(sq.Eq{"ExportKey": exportKeys}).
+		OrderBy("CreateAt ASC")
+	if err := p.store.selectBuilder(p.store.db, &rows, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get playbooks by export keys")
+	}
+
+	for _, row := range rows {
+		// Keep the oldest playbook if a key was somehow reused.
+		if _, ok := result[row.ExportKey]; !ok {
+			result[row.ExportKey] = row.ID
+		}
+	}
+
+	return result, nil
+}
+
 // Get number of active playbooks.
 func (p *playbookStore) GetPlaybooksActiveTotal() (int64, error) {
 	var count int64
@@ -913,16 +1032,11 @@ func (p *playbookStore) AddPlaybookMember(id string, memberID string) error {
 		return errors.New("ids should not be empty")
 	}
 
-	_, err := p.store.execBuilder(p.store.db, sq.
+	return p.changePlaybook(sq.Eq{"ID": id}, sq.
 		Insert("IR_PlaybookMember").
 		Columns("PlaybookID", "MemberID", "Roles").
-		Values(id, memberID, app.PlaybookRoleMember))
-
-	if err != nil {
-		return errors.Wrapf(err, "failed to update playbook with id '%s'", id)
-	}
-
-	return nil
+		Values(id, memberID, app.PlaybookRoleMember),
+		fmt.Sprintf("failed to update playbook with id '%s'", id))
 }
 
 func (p *playbookStore) RemovePlaybookMember(id string, memberID string) error {
@@ -930,13 +1044,35 @@ func (p *playbookStore) RemovePlaybookMember(id string, memberID string) error {
 		return errors.New("ids should not be empty")
 	}
 
-	_, err := p.store.execBuilder(p.store.db, sq.
+	return p.changePlaybook(sq.Eq{"ID": id}, sq.
 		Delete("IR_PlaybookMember").
 		Where(sq.Eq{"PlaybookID": id}).
-		Where(sq.Eq{"MemberID": memberID}))
+		Where(sq.Eq{"MemberID": memberID}),
+		fmt.Sprintf("failed to update playbook with id '%s'", id))
+}
 
+// changePlaybook runs change, a write to a table holding part of a playbook, and moves the
+// UpdateAt of the playbooks matching where in the same transaction, so that their ETags change.
+func (p *playbookStore) changePlaybook(where sq.Sqlizer, change builder, errMessage string) error {
+	tx, err := p.store.db.Beginx()
 	if err != nil {
-		return errors.Wrapf(err, "failed to update playbook with id '%s'", id)
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	defer p.store.finalizeTransaction(tx)
+
+	if _, err = p.store.execBuilder(tx, change); err != nil {
+		return errors.Wrap(err, errMessage)
+	}
+
+	if _, err = p.store.execBuilder(tx, sq.
+		Update("IR_Playbook").
+		Set("UpdateAt", model.GetMillis()).
+		Where(where)); err != nil {
+		return errors.Wrap(err, errMessage)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "could not commit transaction")
 	}
 
 	return nil
@@ -1057,18 +1193,25 @@ func (p *playbookStore) GetMetric(id string) (*app.PlaybookMetricConfig, error)
 			"c.Target",
 		).
 		From("IR_MetricConfig c").
-		Where(sq.Eq{"c.ID": id})
+		Where(sq.Eq{"c.ID": id}).
+		Where(sq.Eq{"c.DeleteAt": 0})
 
 	var metric app.PlaybookMetricConfig
 	err := p.store.getBuilder(p.store.db, &metric, metricSelect)
-	if err != nil {
-		return nil, err
+	if err == sql.ErrNoRows {
+		return nil, errors.Wrapf(app.ErrNotFound, "metric does not exist for id %q", id)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get metric by id %q", id)
 	}
 
 	return &metric, nil
 }
 
 func (p *playbookStore) AddMetric(playbookID string, config app.PlaybookMetricConfig) error {
+	if config.ID == "" {
+		return errors.New("metric id should not be empty")
+	}
+
 	numExistingMetrics, err := p.GetNumMetrics(playbookID)
 	if err != nil {
 		return err
@@ -1078,16 +1221,16 @@ func (p *playbookStore) AddMetric(playbookID string, config app.PlaybookMetricCo
 		return errors.Errorf("playbook cannot have more than %d key metrics", app.MaxMetricsPerPlaybook)
 	}
 
-	_, err = p.store.execBuilder(p.store.db, sq.
+	return p.changePlaybook(sq.Eq{"ID": playbookID}, sq.
 		Insert("IR_MetricConfig").
 		Columns("ID", "PlaybookID", "Title", "Description", "Type", "Target", "Ordering").
-		Values(model.NewId(), playbookID, config.Title, config.Description, config.Type, config.Target, numExistingMetrics))
-
-	if err != nil {
-		return errors.Wrapf(err, "failed to add metric")
-	}
+		Values(config.ID, playbookID, config.Title, config.Description, config.Type, config.Target, numExistingMetrics),
+		"failed to add metric")
+}
 
-	return nil
+// metricPlaybook matches the playbook of the metric id.
+func metricPlaybook(id string) sq.Sqlizer {
+	return sq.Expr("ID = (SELECT PlaybookID FROM IR_MetricConfig WHERE ID = ?)", id)
 }
 
 func (p *playbookStore) DeleteMetric(id string) error {
@@ -1095,16 +1238,11 @@ func (p *playbookStore) DeleteMetric(id string) error {
 		return errors.New("id should not be empty")
 	}
 
-	_, err := p.store.execBuilder(p.store.db, sq.
+	return p.changePlaybook(metricPlaybook(id), sq.
 		Update("IR_MetricConfig").
 		Set("DeleteAt", model.GetMillis()).
-		Where(sq.Eq{"ID": id}))
-
-	if err != nil {
-		return errors.Wrapf(err, "failed to delete metric with id %q", id)
-	}
-
-	return nil
+		Where(sq.Eq{"ID": id}),
+		fmt.Sprintf("failed to delete metric with id %q", id))
 }
 
 func (p *playbookStore) UpdateMetric(id string, setmap map[string]interface{}) error {
@@ -1112,16 +1250,11 @@ func (p *playbookStore) UpdateMetric(id string, setmap map[string]interface{}) e
 		return errors.New("id should not be empty")
 	}
 
-	_, err := p.store.execBuilder(p.store.db, sq.
+	return p.changePlaybook(metricPlaybook(id), sq.
 		Update("IR_MetricConfig").
 		SetMap(setmap).
-		Where(sq.Eq{"ID": id}))
-
-	if err != nil {
-		return errors.Wrapf(err, "failed to update metric with id %q", id)
-	}
-
-	return nil
+		Where(sq.Eq{"ID": id}),
+		fmt.Sprintf("failed to update metric with id %q", id))
 }
 
 func generatePlaybookSchemeRoles(member playbookMember, playbook *app.Playbook) []string {
@@ -1197,9 +1330,15 @@ func toSQLPlaybook(playbook app.Playbook) (*sqlPlaybook, error) {
 		return nil, errors.Errorf("checklist json for playbook id '%s' is too long (max %d)", playbook.ID, maxJSONLength)
 	}
 
+	runRolesJSON, err := runRolesToJSON(playbook.RunRoles)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal run roles json for playbook id: '%s'", playbook.ID)
+	}
+
 	return &sqlPlaybook{
 		Playbook:                              playbook,
 		ChecklistsJSON:                        checklistsJSON,
+		RunRolesJSON:                          runRolesJSON,
 		ConcatenatedInvitedUserIDs:            strings.Join(playbook.InvitedUserIDs, ","),
 		ConcatenatedInvitedGroupIDs:           strings.Join(playbook.InvitedGroupIDs, ","),
 		ConcatenatedSignalAnyKeywords:         strings.Join(playbook.SignalAnyKeywords, ","),
@@ -1217,6 +1356,13 @@ func toPlaybook(rawPlaybook sqlPlaybook) (app.Playbook, error) {
 		}
 	}
 
+	p.RunRoles = nil
+	if len(rawPlaybook.RunRolesJSON) > 0 {
+		if err := json.Unmarshal(rawPlaybook.RunRolesJSON, &p.RunRoles); err != nil {
+			return app.Playbook{}, errors.Wrapf(err, "failed to unmarshal run roles json for playbook id: '%s'", p.ID)
+		}
+	}
+
 	p.InvitedUserIDs = []string(nil)
 	if rawPlaybook.ConcatenatedInvitedUserIDs != "" {
 		p.InvitedUserIDs = strings.Split(rawPlaybook.ConcatenatedInvitedUserIDs, ",")
@@ -0,0 +1,156 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"math"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// CreatePlaybookRevision stores a new revision of a playbook.
+func (p *playbookStore) CreatePlaybookRevision(revision app.PlaybookRevision) (app.PlaybookRevision, error) {
+	if revision.PlaybookID == "" {
+		return app.PlaybookRevision{}, errors.New("playbookID cannot be empty")
+	}
+
+	tx, err := p.store.db.Beginx()
+	if err != nil {
+		return app.PlaybookRevision{}, errors.Wrap(err, "could not begin transaction")
+	}
+	defer p.store.finalizeTransaction(tx)
+
+	if revision, err = p.insertPlaybookRevision(tx, revision); err != nil {
+		return app.PlaybookRevision{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return app.PlaybookRevision{}, errors.Wrap(err, "could not commit transaction")
+	}
+
+	return revision, nil
+}
+
+// insertPlaybookRevision numbers and inserts a revision within tx. Bumping IR_Playbook.Revision in
+// the same transaction serializes concurrent revisions of a playbook.
+func (p *playbookStore) insertPlaybookRevision(tx *sqlx.Tx, revision app.PlaybookRevision) (app.PlaybookRevision, error) {
+	// Raw SQL: squirrel cannot express RETURNING.
+	if err := tx.Get(
+		&revision.Revision,
+		`UPDATE IR_Playbook SET Revision = Revision + 1 WHERE ID = $1 RETURNING Revision`,
+		revision.PlaybookID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return app.PlaybookRevision{}, errors.Wrapf(app.ErrNotFound, "playbook '%s' not found", revision.PlaybookID)
+		}
+		return app.PlaybookRevision{}, errors.Wrapf(err, "failed to bump revision of playbook %s", revision.PlaybookID)
+	}
+
+	revision.ID = model.NewId()
+	_, err := p.store.execBuilder(tx, sq.
+		Insert("IR_PlaybookRevision").
+		SetMap(map[string]interface{}{
+			"ID":         revision.ID,
+			"PlaybookID": revision.PlaybookID,
+			"Revision":   revision.Revision,
+			"UserID":     revision.UserID,
+			"CreateAt":   revision.CreateAt,
+			"Snapshot":   revision.Snapshot,
+		}))
+	if err != nil {
+		return app.PlaybookRevision{}, errors.Wrapf(err, "failed to store revision of playbook %s", revision.PlaybookID)
+	}
+
+	return revision, nil
+}
+
+// GetPlaybookRevisions returns the revisions of a playbook, newest first, without their snapshots.
+func (p *playbookStore) GetPlaybookRevisions(playbookID string, page, perPage int) (app.GetPlaybookRevisionsResults, error) {
+	if page < 0 {
+		page = 0
+	}
+	if perPage < 0 {
+		perPage = 0
+	}
+
+	var total int
+	err := p.store.getBuilder(p.store.db, &total, p.store.builder.
+		Select("COUNT(*)").
+		From("IR_PlaybookRevision").
+		Where(sq.Eq{"PlaybookID": playbookID}))
+	if err != nil {
+		return app.GetPlaybookRevisionsResults{}, errors.Wrapf(err, "failed to count revisions of playbook %s", playbookID)
+	}
+
+	var revisions []app.PlaybookRevision
+	err = p.store.selectBuilder(p.store.db, &revisions, p.store.builder.
+		Select("ID", "PlaybookID", "Revision", "UserID", "CreateAt").
+		From("IR_PlaybookRevision").
+		Where(sq.Eq{"PlaybookID": playbookID}).
+		OrderBy("Revision DESC").
+		Offset(uint64(page*perPage)).
+		Limit(uint64(perPage)))
+	if err != nil && err != sql.ErrNoRows {
+		return app.GetPlaybookRevisionsResults{}, errors.Wrapf(err, "failed to get revisions of playbook %s", playbookID)
+	}
+
+	pageCount := 0
+	if perPage > 0 {
+		pageCount = int(math.Ceil(float64(total) / float64(perPage)))
+	}
+
+	return app.GetPlaybookRevisionsResults{
+		TotalCount: total,
+		PageCount:  pageCount,
+		HasMore:    page+1 < pageCount,
+		Items:      revisions,
+	}, nil
+}
+
+// GetPlaybookRevision returns a single revision of a playbook.
+func (p *playbookStore) GetPlaybookRevision(playbookID string, revision int64) (app.PlaybookRevision, error) {
+	var playbookRevision app.PlaybookRevision
+	err := p.store.getBuilder(p.store.db, &playbookRevision, p.store.builder.
+		Select("ID", "PlaybookID", "Revision", "UserID", "CreateAt", "Snapshot").
+		From("IR_PlaybookRevision").
+		Where(sq.Eq{"PlaybookID": playbookID}).
+		Where(sq.Eq{"Revision": revision}))
+	if err == sql.ErrNoRows {
+		return app.PlaybookRevision{}, errors.Wrapf(app.ErrNotFound, "revision %d does not exist for playbook '%s'", revision, playbookID)
+	} else if err != nil {
+		return app.PlaybookRevision{}, errors.Wrapf(err, "failed to get revision %d of playbook '%s'", revision, playbookID)
+	}
+
+	return playbookRevision, nil
+}
+
+// SetPublishedRevision marks a revision of a playbook as the published one.
+func (p *playbookStore) SetPublishedRevision(playbookID string, revision int64) error {
+	result, err := p.store.execBuilder(p.store.db, sq.
+		Update("IR_Playbook").
+		Set("PublishedRevision", revision).
+		Where(sq.Eq{"ID": playbookID}).
+		Where(sq.LtOrEq{"PublishedRevision": revision}).
+		Where(sq.GtOrEq{"Revision": revision}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish revision %d of playbook '%s'", revision, playbookID)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read rows affected for playbook '%s'", playbookID)
+	}
+	if affected == 0 {
+		return errors.Wrapf(app.ErrNotFound, "playbook '%s' not found or revision %d cannot be published", playbookID, revision)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+func TestPlaybookRevisions(t *testing.T) {
+	db := setupTestDB(t)
+	playbookStore := setupPlaybookStore(t, db)
+
+	playbookID, err := playbookStore.Create(NewPBBuilder().WithTitle("pb").WithChecklists([]int{1}).ToPlaybook())
+	require.NoError(t, err)
+
+	userID := model.NewId()
+	for i := 1; i <= 3; i++ {
+		created, err := playbookStore.CreatePlaybookRevision(app.PlaybookRevision{
+			PlaybookID: playbookID,
+			UserID:     userID,
+			CreateAt:   int64(i),
+			Snapshot:   json.RawMessage(`{"title":"pb"}`),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.ID)
+		require.Equal(t, int64(i), created.Revision)
+	}
+
+	t.Run("playbook tracks its latest revision", func(t *testing.T) {
+		playbook, err := playbookStore.Get(playbookID)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), playbook.Revision)
+	})
+
+	t.Run("list newest first without snapshots", func(t *testing.T) {
+		results, err := playbookStore.GetPlaybookRevisions(playbookID, 0, 2)
+		require.NoError(t, err)
+		require.Equal(t, 3, results.TotalCount)
+		require.Equal(t, 2, results.PageCount)
+		require.True(t, results.HasMore)
+		require.Len(t, results.Items, 2)
+		require.Equal(t, int64(3), results.Items[0].Revision)
+		require.Equal(t, int64(2), results.Items[1].Revision)
+		require.Empty(t, results.Items[0].Snapshot)
+
+		results, err = playbookStore.GetPlaybookRevisions(playbookID, 1, 2)
+		require.NoError(t, err)
+		require.False(t, results.HasMore)
+		require.Len(t, results.Items, 1)
+		require.Equal(t, int64(1), results.Items[0].Revision)
+	})
+
+	t.Run("get single revision with snapshot", func(t *testing.T) {
+		revision, err := playbookStore.GetPlaybookRevision(playbookID, 2)
+		require.NoError(t, err)
+		require.Equal(t, userID, revision.UserID)
+		require.JSONEq(t, `{"title":"pb"}`, string(revision.Snapshot))
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		_, err := playbookStore.GetPlaybookRevision(playbookID, 42)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("publish a revision", func(t *testing.T) {
+		require.NoError(t, playbookStore.SetPublishedRevision(playbookID, 2))
+
+		playbook, err := playbookStore.Get(playbookID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), playbook.PublishedRevision)
+
+		// Publishing cannot go back to an older revision or ahead of the latest one.
+		require.ErrorIs(t, playbookStore.SetPublishedRevision(playbookID, 1), app.ErrNotFound)
+		require.ErrorIs(t, playbookStore.SetPublishedRevision(playbookID, 4), app.ErrNotFound)
+	})
+
+	t.Run("unknown playbook", func(t *testing.T) {
+		_, err := playbookStore.CreatePlaybookRevision(app.PlaybookRevision{
+			PlaybookID: model.NewId(),
+			Snapshot:   json.RawMessage(`{}`),
+		})
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("create and update with a revision", func(t *testing.T) {
+		newID, err := playbookStore.CreateWithRevision(
+			NewPBBuilder().WithTitle("with revision").ToPlaybook(),
+			app.PlaybookRevision{UserID: userID, CreateAt: 1, Snapshot: json.RawMessage(`{"title":"with revision"}`)},
+		)
+		require.NoError(t, err)
+
+		playbook, err := playbookStore.Get(newID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), playbook.Revision)
+
+		playbook.Title = "renamed"
+		err = playbookStore.UpdateWithRevision(playbook, playbook.UpdateAt,
+			app.PlaybookRevision{UserID: userID, CreateAt: 2, Snapshot: json.RawMessage(`{"title":"renamed"}`)})
+		require.NoError(t, err)
+
+		revision, err := playbookStore.GetPlaybookRevision(newID, 2)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"title":"renamed"}`, string(revision.Snapshot))
+	})
+
+	t.Run("stale update stores no revision", func(t *testing.T) {
+		playbook, err := playbookStore.Get(playbookID)
+		require.NoError(t, err)
+
+		playbook.Title = "stale"
+		err = playbookStore.UpdateWithRevision(playbook, playbook.UpdateAt-1,
+			app.PlaybookRevision{UserID: userID, CreateAt: 5, Snapshot: json.RawMessage(`{"title":"stale"}`)})
+		require.ErrorIs(t, err, app.ErrStaleVersion)
+
+		playbook, err = playbookStore.Get(playbookID)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), playbook.Revision)
+		require.Equal(t, "pb", playbook.Title)
+	})
+}
@@ -71,6 +71,7 @@ func GetOrderByClause(column, direction string) string {
 type sqlPlaybookRun struct {
 	app.PlaybookRun
 	ChecklistsJSON                        json.RawMessage
+	RunRolesJSON                          json.RawMessage
 	ConcatenatedInvitedUserIDs            string
 	ConcatenatedInvitedGroupIDs           string
 	ConcatenatedParticipantIDs            string
@@ -128,7 +129,7 @@ func applyPlaybookRunFilterOptionsSort(builder sq.SelectBuilder, options app.Pla
 	case "":
 		// Default to a stable sort if none explicitly provided.
 		sort = "ID"
-	case app.SortByMetric0, app.SortByMetric1, app.SortByMetric2, app.SortByMetric3:
+	case app.SortByMetric0, app.SortByMetric1, app.SortByMetric2, app.SortByMetric3, app.SortByProperty:
 		// Will handle below
 	default:
 		return sq.SelectBuilder{}, errors.Errorf("unsupported sort parameter '%s'", options.Sort)
@@ -196,6 +197,20 @@ func applyPlaybookRunFilterOptionsSort(builder sq.SelectBuilder, options app.Pla
 		// Since we're sorting by metric, we need to create the correct metric column to sort by
 		builder = builder.Column(sq.Alias(metricQuery, "Metric")).
 			OrderByClause(GetOrderByClause("Metric", direction))
+	case app.SortByProperty:
+		if options.ResolvedSortPropertyField == nil {
+			return sq.SelectBuilder{}, errors.New("sorting by property requires a resolved property field")
+		}
+
+		sortExpr, err := propertySortExpr(*options.ResolvedSortPropertyField)
+		if err != nil {
+			return sq.SelectBuilder{}, err
+		}
+
+		// Runs without a value come last in both directions, ties keeping a stable order.
+		builder = builder.
+			OrderByClause(sq.Expr("? "+direction+" NULLS LAST", sortExpr)).
+			OrderByClause(GetOrderByClause("ID", direction))
 	default:
 		builder = builder.OrderByClause(GetOrderByClause(sort, direction))
 	}
@@ -231,7 +246,7 @@ func NewPlaybookRunStore(pluginAPI PluginAPIClient, sqlStore *SQLStore) app.Play
 			"RetrospectiveWasCanceled", "ConcatenatedWebhookOnStatusUpdateURLs", "StatusUpdateBroadcastChannelsEnabled", "StatusUpdateBroadcastWebhooksEnabled",
 			"CreateChannelMemberOnNewParticipant", "RemoveChannelMemberOnRemovedParticipant",
 			"COALESCE(CategoryName, '') CategoryName", "SummaryModifiedAt", "i.RunType AS Type",
-			"i.RunNumber", "i.SequentialID",
+			"i.RunNumber", "i.SequentialID", "i.PlaybookRevision", "i.RunRolesJSON",
 			"i.ChannelCreatedByRun", "i.AutoArchivedChannel", "i.AutoArchiveChannel").
 		Column(participantsCol).
 		From("IR_Incident AS i")
@@ -393,6 +408,21 @@ func (s *playbookRunStore) GetPlaybookRuns(requesterInfo app.RequesterInfo, opti
 		queryForTotal = queryForTotal.Where(sq.Eq{"i.ChannelId": options.ChannelID})
 	}
 
+	if options.SharedFieldID != "" {
+		sharedFieldClause := s.sharedPropertyFilterExpr(options.SharedFieldID, options.SharedFieldValue)
+		queryForResults = queryForResults.Where(sharedFieldClause)
+		queryForTotal = queryForTotal.Where(sharedFieldClause)
+	}
+
+	if options.ResolvedPropertyFilter != nil {
+		propertyFilterClause, err := propertyFilterExpr(*options.ResolvedPropertyFilter)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build property filter")
+		}
+		queryForResults = queryForResults.Where(propertyFilterClause)
+		queryForTotal = queryForTotal.Where(propertyFilterClause)
+	}
+
 	queryForResults = queryActiveBetweenTimes(queryForResults, options.ActiveGTE, options.ActiveLT)
 	queryForTotal = queryActiveBetweenTimes(queryForTotal, options.ActiveGTE, options.ActiveLT)
 
@@ -516,6 +546,7 @@ func (s *playbookRunStore) CreatePlaybookRun(playbookRun *app.PlaybookRun) (*app
 			"PostID":                                  rawPlaybookRun.PostID,
 			"PlaybookID":                              rawPlaybookRun.PlaybookID,
 			"ChecklistsJSON":                          rawPlaybookRun.ChecklistsJSON,
+			"RunRolesJSON":                            rawPlaybookRun.RunRolesJSON,
 			"ReminderPostID":                          rawPlaybookRun.ReminderPostID,
 			"PreviousReminder":                        rawPlaybookRun.PreviousReminder,
 			"ReminderMessageTemplate":                 rawPlaybookRun.ReminderMessageTemplate,
@@ -543,6 +574,7 @@ func (s *playbookRunStore) CreatePlaybookRun(playbookRun *app.PlaybookRun) (*app
 			"RunType":                                 rawPlaybookRun.Type,
 			"RunNumber":                               rawPlaybookRun.RunNumber,
 			"SequentialID":                            rawPlaybookRun.SequentialID,
+			"PlaybookRevision":                        rawPlaybookRun.PlaybookRevision,
 			"ChannelCreatedByRun":                     rawPlaybookRun.ChannelCreatedByRun,
 			"AutoArchivedChannel":                     rawPlaybookRun.AutoArchivedChannel,
 			"AutoArchiveChannel":                      rawPlaybookRun.AutoArchiveChannel,
@@ -562,6 +594,12 @@ func (s *playbookRunStore) CreatePlaybookRun(playbookRun *app.PlaybookRun) (*app
 
 // UpdatePlaybookRun updates a playbook run.
 func (s *playbookRunStore) UpdatePlaybookRun(playbookRun *app.PlaybookRun) (*app.PlaybookRun, error) {
+	return s.UpdatePlaybookRunIfUnmodified(playbookRun, 0)
+}
+
+// UpdatePlaybookRunIfUnmodified updates a playbook run, failing with app.ErrStaleVersion unless
+// its UpdateAt is updateAt. An updateAt of 0 updates the run unconditionally.
+func (s *playbookRunStore) UpdatePlaybookRunIfUnmodified(playbookRun *app.PlaybookRun, updateAt int64) (*app.PlaybookRun, error) {
 	if playbookRun == nil {
 		return nil, errors.New("playbook run is nil")
 	}
@@ -585,8 +623,13 @@ func (s *playbookRunStore) UpdatePlaybookRun(playbookRun *app.PlaybookRun) (*app
 	}
 	defer s.store.finalizeTransaction(tx)
 
+	where := sq.And{sq.Eq{"ID": rawPlaybookRun.ID}}
+	if updateAt != 0 {
+		where = append(where, sq.Eq{"UpdateAt": updateAt})
+	}
+
 	// When adding a PlaybookRun column #3: add to this SetMap (if it is a column that can be updated)
-	_, err = s.store.execBuilder(tx, sq.
+	result, err := s.store.execBuilder(tx, sq.
 		Update("IR_Incident").
 		SetMap(map[string]interface{}{
 			"Name":                                    rawPlaybookRun.Name,
@@ -595,6 +638,7 @@ func (s *playbookRunStore) UpdatePlaybookRun(playbookRun *app.PlaybookRun) (*app
 			"CommanderUserID":                         rawPlaybookRun.OwnerUserID,
 			"LastStatusUpdateAt":                      rawPlaybookRun.LastStatusUpdateAt,
 			"ChecklistsJSON":                          rawPlaybookRun.ChecklistsJSON,
+			"RunRolesJSON":                            rawPlaybookRun.RunRolesJSON,
 			"ReminderPostID":                          rawPlaybookRun.ReminderPostID,
 			"PreviousReminder":                        rawPlaybookRun.PreviousReminder,
 			"ConcatenatedInvitedUserIDs":              rawPlaybookRun.ConcatenatedInvitedUserIDs,
@@ -616,15 +660,26 @@ func (s *playbookRunStore) UpdatePlaybookRun(playbookRun *app.PlaybookRun) (*app
 			"RemoveChannelMemberOnRemovedParticipant": rawPlaybookRun.RemoveChannelMemberOnRemovedParticipant,
 			"RunType":             rawPlaybookRun.Type,
 			"AutoArchivedChannel": rawPlaybookRun.AutoArchivedChannel,
+			"PlaybookRevision":    rawPlaybookRun.PlaybookRevision,
 			// ChannelCreatedByRun and AutoArchiveChannel are intentionally omitted — set once at creation and immutable.
 			"UpdateAt": rawPlaybookRun.UpdateAt,
 		}).
-		Where(sq.Eq{"ID": rawPlaybookRun.ID}))
+		Where(where))
 
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to update playbook run with id '%s'", rawPlaybookRun.ID)
 	}
 
+	if updateAt != 0 {
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read rows affected for playbook run '%s'", rawPlaybookRun.ID)
+		}
+		if affected == 0 {
+			return nil, errors.Wrapf(app.ErrStaleVersion, "playbook run with id '%s'", rawPlaybookRun.ID)
+		}
+	}
+
 	if err = s.updateRunMetrics(tx, rawPlaybookRun.PlaybookRun); err != nil {
 		return nil, errors.Wrapf(err, "failed to update playbook run metrics for run with id '%s'", rawPlaybookRun.PlaybookRun.ID)
 	}
@@ -998,7 +1053,7 @@ func (s *playbookRunStore) NukeDB() (err error) {
 	}
 	defer s.store.finalizeTransaction(tx)
 
-	if _, err := tx.Exec("DROP TABLE IF EXISTS IR_Condition, IR_Metric, IR_MetricConfig, IR_PlaybookMember, IR_Run_Participants, IR_PlaybookAutoFollow, IR_StatusPosts, IR_TimelineEvent, IR_Incident, IR_Playbook, IR_System"); err != nil {
+	if _, err := tx.Exec("DROP TABLE IF EXISTS IR_RunChange, IR_PropertyValueChange, IR_Condition, IR_Metric, IR_MetricConfig, IR_PlaybookMember, IR_Run_Participants, IR_PlaybookAutoFollow, IR_StatusPosts, IR_TimelineEvent, IR_Incident, IR_Playbook, IR_System"); err != nil {
 		return errors.Wrap(err, "could not delete all IR tables")
 	}
 
@@ -1213,6 +1268,13 @@ func (s *playbookRunStore) toPlaybookRun(rawPlaybookRun sqlPlaybookRun) (*app.Pl
 		return nil, errors.Wrapf(err, "failed to unmarshal checklists json for playbook run id: %s", rawPlaybookRun.ID)
 	}
 
+	playbookRun.RunRoles = nil
+	if len(rawPlaybookRun.RunRolesJSON) > 0 {
+		if err := json.Unmarshal(rawPlaybookRun.RunRolesJSON, &playbookRun.RunRoles); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal run roles json for playbook run id: %s", rawPlaybookRun.ID)
+		}
+	}
+
 	playbookRun.InvitedUserIDs = []string(nil)
 	if rawPlaybookRun.ConcatenatedInvitedUserIDs != "" {
 		playbookRun.InvitedUserIDs = strings.Split(rawPlaybookRun.ConcatenatedInvitedUserIDs, ",")
@@ -1648,19 +1710,40 @@ func (s *playbookRunStore) GetPlaybookRunIDsForUser(userID string) ([]string, er
 }
 
 func (s *playbookRunStore) GraphqlUpdate(id string, setmap map[string]interface{}) error {
+	return s.GraphqlUpdateIfUnmodified(id, setmap, 0)
+}
+
+// GraphqlUpdateIfUnmodified updates the fields in setmap, failing with app.ErrStaleVersion
+// unless the run's UpdateAt is updateAt. An updateAt of 0 updates the run unconditionally.
+func (s *playbookRunStore) GraphqlUpdateIfUnmodified(id string, setmap map[string]interface{}, updateAt int64) error {
 	if id == "" {
 		return errors.New("id should not be empty")
 	}
 
-	_, err := s.store.execBuilder(s.store.db, sq.
+	where := sq.And{sq.Eq{"ID": id}}
+	if updateAt != 0 {
+		where = append(where, sq.Eq{"UpdateAt": updateAt})
+	}
+
+	result, err := s.store.execBuilder(s.store.db, sq.
 		Update("IR_Incident").
 		SetMap(setmap).
-		Where(sq.Eq{"ID": id}))
+		Where(where))
 
 	if err != nil {
 		return errors.Wrapf(err, "failed to update playbook run with id '%s'", id)
 	}
 
+	if updateAt != 0 {
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read rows affected for playbook run '%s'", id)
+		}
+		if affected == 0 {
+			return errors.Wrapf(app.ErrStaleVersion, "playbook run with id '%s'", id)
+		}
+	}
+
 	return nil
 }
 
@@ -1674,9 +1757,15 @@ func toSQLPlaybookRun(playbookRun app.PlaybookRun) (*sqlPlaybookRun, error) {
 		return nil, errors.Errorf("checklist json for playbook run id '%s' is too long (max %d)", playbookRun.ID, maxJSONLength)
 	}
 
+	runRolesJSON, err := runRolesToJSON(playbookRun.RunRoles)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal run roles json for playbook run id '%s'", playbookRun.ID)
+	}
+
 	return &sqlPlaybookRun{
 		PlaybookRun:                           playbookRun,
 		ChecklistsJSON:                        checklistsJSON,
+		RunRolesJSON:                          runRolesJSON,
 		ConcatenatedInvitedUserIDs:            strings.Join(playbookRun.InvitedUserIDs, ","),
 		ConcatenatedInvitedGroupIDs:           strings.Join(playbookRun.InvitedGroupIDs, ","),
 		ConcatenatedBroadcastChannelIDs:       strings.Join(playbookRun.BroadcastChannelIDs, ","),
@@ -1720,6 +1809,19 @@ func checklistsToJSON(checklists []app.Checklist) (json.RawMessage, error) {
 	return checklistsJSON, nil
 }
 
+// runRolesToJSON marshals run roles, storing an empty list rather than null.
+func runRolesToJSON(roles []app.RunRole) (json.RawMessage, error) {
+	if roles == nil {
+		roles = []app.RunRole{}
+	}
+	runRolesJSON, err := json.Marshal(roles)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal run roles json")
+	}
+
+	return runRolesJSON, nil
+}
+
 func addStatusPostsToPlaybookRuns(statusIDs playbookRunStatusPosts, playbookRuns []app.PlaybookRun) {
 	iToPosts := make(map[string][]app.StatusPost)
 	for _, p := range statusIDs {
@@ -1716,6 +1716,81 @@ func TestActivitySince(t *testing.T) {
 	})
 }
 
+func TestGraphqlUpdateIfUnmodified(t *testing.T) {
+	db := setupTestDB(t)
+	playbookRunStore := setupPlaybookRunStore(t, db)
+	store := setupSQLStore(t, db)
+	setupChannelsTable(t, db)
+
+	playbookRun, err := playbookRunStore.CreatePlaybookRun(NewBuilder(t).WithCreateAt(1000).ToPlaybookRun())
+	require.NoError(t, err)
+	createPlaybookRunChannel(t, store, playbookRun)
+
+	t.Run("stale version", func(t *testing.T) {
+		err := playbookRunStore.GraphqlUpdateIfUnmodified(playbookRun.ID, map[string]interface{}{"Name": "stale", "UpdateAt": int64(3000)}, 999)
+		require.ErrorIs(t, err, app.ErrStaleVersion)
+
+		actual, err := playbookRunStore.GetPlaybookRun(playbookRun.ID)
+		require.NoError(t, err)
+		require.Equal(t, playbookRun.Name, actual.Name)
+		require.Equal(t, int64(1000), actual.UpdateAt)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		err := playbookRunStore.GraphqlUpdateIfUnmodified(playbookRun.ID, map[string]interface{}{"Name": "current", "UpdateAt": int64(2000)}, 1000)
+		require.NoError(t, err)
+
+		actual, err := playbookRunStore.GetPlaybookRun(playbookRun.ID)
+		require.NoError(t, err)
+		require.Equal(t, "current", actual.Name)
+		require.Equal(t, int64(2000), actual.UpdateAt)
+	})
+
+	t.Run("unconditional", func(t *testing.T) {
+		err := playbookRunStore.GraphqlUpdateIfUnmodified(playbookRun.ID, map[string]interface{}{"Name": "unconditional", "UpdateAt": int64(3000)}, 0)
+		require.NoError(t, err)
+
+		actual, err := playbookRunStore.GetPlaybookRun(playbookRun.ID)
+		require.NoError(t, err)
+		require.Equal(t, "unconditional", actual.Name)
+	})
+}
+
+func TestUpdatePlaybookRunIfUnmodified(t *testing.T) {
+	db := setupTestDB(t)
+	playbookRunStore := setupPlaybookRunStore(t, db)
+	store := setupSQLStore(t, db)
+	setupChannelsTable(t, db)
+
+	playbookRun, err := playbookRunStore.CreatePlaybookRun(NewBuilder(t).WithCreateAt(1000).ToPlaybookRun())
+	require.NoError(t, err)
+	createPlaybookRunChannel(t, store, playbookRun)
+
+	t.Run("stale version", func(t *testing.T) {
+		stale := playbookRun.Clone()
+		stale.Name = "stale"
+		_, err := playbookRunStore.UpdatePlaybookRunIfUnmodified(stale, 999)
+		require.ErrorIs(t, err, app.ErrStaleVersion)
+
+		actual, err := playbookRunStore.GetPlaybookRun(playbookRun.ID)
+		require.NoError(t, err)
+		require.Equal(t, playbookRun.Name, actual.Name)
+		require.Equal(t, int64(1000), actual.UpdateAt)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		current := playbookRun.Clone()
+		current.Name = "current"
+		updated, err := playbookRunStore.UpdatePlaybookRunIfUnmodified(current, 1000)
+		require.NoError(t, err)
+
+		actual, err := playbookRunStore.GetPlaybookRun(playbookRun.ID)
+		require.NoError(t, err)
+		require.Equal(t, "current", actual.Name)
+		require.Equal(t, updated.UpdateAt, actual.UpdateAt)
+	})
+}
+
 // PlaybookRunBuilder is a utility to build playbook runs with a default base.
 // Use it as:
 // NewBuilder.WithName("name").WithXYZ(xyz)....ToPlaybookRun()
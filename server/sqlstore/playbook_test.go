@@ -1272,6 +1272,46 @@ func TestUpdatePlaybook_DoesNotModifyOtherPlaybookMetrics(t *testing.T) {
 		"victim metric must not change when a different playbook update references its metric ID")
 }
 
+func TestUpdatePlaybookIfUnmodified(t *testing.T) {
+	db := setupTestDB(t)
+	playbookStore := setupPlaybookStore(t, db)
+
+	id, err := playbookStore.Create(NewPBBuilder().
+		WithTitle("playbook").
+		WithTeamID(model.NewId()).
+		WithUpdateAt(1000).
+		ToPlaybook())
+	require.NoError(t, err)
+	playbook, err := playbookStore.Get(id)
+	require.NoError(t, err)
+
+	t.Run("stale version", func(t *testing.T) {
+		stale := playbook
+		stale.Title = "stale"
+		stale.UpdateAt = 3000
+		err := playbookStore.UpdateIfUnmodified(stale, 999)
+		require.ErrorIs(t, err, app.ErrStaleVersion)
+
+		actual, err := playbookStore.Get(id)
+		require.NoError(t, err)
+		require.Equal(t, "playbook", actual.Title)
+		require.Equal(t, int64(1000), actual.UpdateAt)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		current := playbook
+		current.Title = "current"
+		current.UpdateAt = 2000
+		err := playbookStore.UpdateIfUnmodified(current, 1000)
+		require.NoError(t, err)
+
+		actual, err := playbookStore.Get(id)
+		require.NoError(t, err)
+		require.Equal(t, "current", actual.Title)
+		require.Equal(t, int64(2000), actual.UpdateAt)
+	})
+}
+
 func TestDeletePlaybook(t *testing.T) {
 	team1id := model.NewId()
 
@@ -2116,6 +2156,47 @@ func TestBumpPlaybookUpdatedAt(t *testing.T) {
 	require.Greater(t, updatedPlaybook.UpdateAt, int64(1))
 }
 
+func TestPlaybookWritesBumpUpdatedAt(t *testing.T) {
+	db := setupTestDB(t)
+	playbookStore := setupPlaybookStore(t, db)
+
+	id, err := playbookStore.Create(NewPBBuilder().WithTitle("Test Playbook").WithTeamID(model.NewId()).ToPlaybook())
+	require.NoError(t, err)
+
+	metricID := model.NewId()
+	memberID := model.NewId()
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"graphql update", func() error { return playbookStore.GraphqlUpdate(id, map[string]interface{}{"Title": "Renamed"}) }},
+		{"add metric", func() error {
+			return playbookStore.AddMetric(id, app.PlaybookMetricConfig{ID: metricID, Title: "Time to resolve", Type: app.MetricTypeDuration})
+		}},
+		{"update metric", func() error {
+			return playbookStore.UpdateMetric(metricID, map[string]interface{}{"Title": "Time to recover"})
+		}},
+		{"delete metric", func() error { return playbookStore.DeleteMetric(metricID) }},
+		{"add member", func() error { return playbookStore.AddPlaybookMember(id, memberID) }},
+		{"remove member", func() error { return playbookStore.RemovePlaybookMember(id, memberID) }},
+		{"archive", func() error { return playbookStore.Archive(id) }},
+		{"restore", func() error { return playbookStore.Restore(id) }},
+	}
+
+	for _, tc := range writes {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := db.Exec("UPDATE IR_Playbook SET UpdateAt = 1 WHERE ID = $1", id)
+			require.NoError(t, err)
+
+			require.NoError(t, tc.write())
+
+			playbook, err := playbookStore.Get(id)
+			require.NoError(t, err)
+			require.Greater(t, playbook.UpdateAt, int64(1))
+		})
+	}
+}
+
 func TestIncrementRunNumber(t *testing.T) {
 	db := setupTestDB(t)
 	playbookStore := setupPlaybookStore(t, db)
@@ -0,0 +1,205 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"strconv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// propertyNumberValue is the property value aliased v as a number, accepting the numeric strings
+// stored before a field became a number field. NULL if it is not a number.
+const propertyNumberValue = `(CASE
+	WHEN jsonb_typeof(v.Value) = 'number' THEN (v.Value #>> '{}')::double precision
+	WHEN jsonb_typeof(v.Value) = 'string' AND btrim(v.Value #>> '{}') ~ '^[-+]{0,1}[0-9]*[.]{0,1}[0-9]+([eE][-+]{0,1}[0-9]+){0,1}$'
+		THEN btrim(v.Value #>> '{}')::double precision
+END)`
+
+// propertyTimestampPattern matches the RFC 3339 timestamps Postgres can cast to timestamptz, except
+// for days past the end of their month. It has no question marks, which squirrel would take for
+// placeholders.
+const propertyTimestampPattern = `^[1-9][0-9]{3}-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])` +
+	`T([01][0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9]([.][0-9]+){0,1}){0,1}` +
+	`(Z|[-+](0[0-9]|1[0-4])(:{0,1}[0-5][0-9]){0,1}){0,1}$`
+
+// propertyDateValue is the property value aliased v as milliseconds since the epoch. NULL if it is
+// not a date. Strings are only cast once known to be valid timestamps, as a single malformed value
+// would otherwise fail the whole query; the nested CASE checks the day exists in its month.
+const propertyDateValue = `(CASE
+	WHEN jsonb_typeof(v.Value) = 'number' THEN (v.Value #>> '{}')::double precision
+	WHEN jsonb_typeof(v.Value) = 'string' AND (v.Value #>> '{}') ~ '` + propertyTimestampPattern + `'
+		THEN (CASE
+			WHEN substring(v.Value #>> '{}' FROM 9 FOR 2)::int <= EXTRACT(DAY FROM make_date(
+				substring(v.Value #>> '{}' FROM 1 FOR 4)::int,
+				substring(v.Value #>> '{}' FROM 6 FOR 2)::int,
+				1
+			) + interval '1 month' - interval '1 day')
+				THEN EXTRACT(EPOCH FROM (v.Value #>> '{}')::timestamptz) * 1000
+		END)
+END)`
+
+// propertyRunFieldsExpr matches the run property fields, aliased f, copied from the playbook or
+// team property field.
+func propertyRunFieldsExpr(field app.PropertyField) sq.Sqlizer {
+	if field.TargetType == app.PropertyTargetTypeTeam {
+		return sharedPropertyRunFieldsExpr(field.ID)
+	}
+
+	return sq.Expr(`f.TargetType = ? AND f.DeleteAt = 0 AND f.Attrs->>'parent_id' = ?`, app.PropertyTargetTypeRun, field.ID)
+}
+
+// propertyValueExistsExpr matches the runs, aliased i, with a value aliased v of a field copied
+// from field for which predicate holds.
+func propertyValueExistsExpr(field app.PropertyField, predicate string, args ...interface{}) sq.Sqlizer {
+	return sq.Expr(`EXISTS(
+		SELECT 1
+		FROM PropertyFields AS f
+		JOIN PropertyValues AS v ON v.FieldID = f.ID AND v.TargetID = i.ID AND v.DeleteAt = 0
+		WHERE f.TargetID = i.ID
+		AND ?
+		AND `+predicate+`
+	)`, append([]interface{}{propertyRunFieldsExpr(field)}, args...)...)
+}
+
+// propertyFilterExpr matches the runs, aliased i, satisfying the property filter.
+func propertyFilterExpr(filter app.PropertyFilter) (sq.Sqlizer, error) {
+	switch {
+	case filter.And != nil:
+		and := sq.And{}
+		for _, nested := range filter.And {
+			expr, err := propertyFilterExpr(nested)
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, expr)
+		}
+		return and, nil
+
+	case filter.Or != nil:
+		or := sq.Or{}
+		for _, nested := range filter.Or {
+			expr, err := propertyFilterExpr(nested)
+			if err != nil {
+				return nil, err
+			}
+			or = append(or, expr)
+		}
+		return or, nil
+
+	case filter.Comparison != nil:
+		return propertyComparisonExpr(*filter.Comparison)
+	}
+
+	return nil, errors.New("empty property filter")
+}
+
+// propertyComparisonExpr mirrors the evaluation of conditions: runs without the field or a value
+// never match is, gt and lt comparisons, and always match isNot ones.
+func propertyComparisonExpr(comparison app.PropertyFilterComparison) (sq.Sqlizer, error) {
+	field := comparison.Field
+
+	var valueExpr string
+	switch {
+	case field.IsNumber():
+		valueExpr = propertyNumberValue
+	case field.Type == model.PropertyFieldTypeDate:
+		valueExpr = propertyDateValue
+	}
+
+	switch comparison.Operator {
+	case app.PropertyFilterIsNot:
+		isComparison := comparison
+		isComparison.Operator = app.PropertyFilterIs
+		isExpr, err := propertyComparisonExpr(isComparison)
+		if err != nil {
+			return nil, err
+		}
+		return sq.Expr("NOT (?)", isExpr), nil
+
+	case app.PropertyFilterGreaterThan, app.PropertyFilterLessThan:
+		if valueExpr == "" {
+			return nil, errors.Errorf("cannot order values of %s property field %s", field.Type, field.ID)
+		}
+		operator := ">"
+		if comparison.Operator == app.PropertyFilterLessThan {
+			operator = "<"
+		}
+		return propertyValueExistsExpr(field, valueExpr+" "+operator+" ?", comparison.Number), nil
+
+	case app.PropertyFilterIs:
+		switch {
+		case field.IsNumber():
+			return propertyValueExistsExpr(field, valueExpr+" = ?", comparison.Number), nil
+
+		case field.Type == model.PropertyFieldTypeText:
+			if comparison.Text == "" {
+				// Matches runs having the field but no text for it, as conditions treat a missing
+				// value as empty.
+				return sq.And{
+					sq.Expr(`EXISTS(SELECT 1 FROM PropertyFields AS f WHERE f.TargetID = i.ID AND ?)`, propertyRunFieldsExpr(field)),
+					sq.Expr("NOT (?)", propertyValueExistsExpr(field, `COALESCE(v.Value #>> '{}', '') <> ''`)),
+				}, nil
+			}
+			return propertyValueExistsExpr(field, `LOWER(v.Value #>> '{}') = LOWER(?)`, comparison.Text), nil
+
+		case field.Type == model.PropertyFieldTypeSelect, field.Type == model.PropertyFieldTypeMultiselect:
+			if len(comparison.OptionNames) == 0 {
+				return sq.Expr("FALSE"), nil
+			}
+			names := make([]interface{}, 0, len(comparison.OptionNames))
+			for _, name := range comparison.OptionNames {
+				names = append(names, strings.ToLower(name))
+			}
+			return propertyValueExistsExpr(field,
+				`EXISTS(SELECT 1 FROM `+sharedPropertyValueElements+` WHERE LOWER(`+sharedPropertyDisplayValue+`) IN (`+sq.Placeholders(len(names))+`))`,
+				names...,
+			), nil
+		}
+	}
+
+	return nil, errors.Errorf("unsupported %s comparison on %s property field %s", comparison.Operator, field.Type, field.ID)
+}
+
+// propertySortExpr is the value of the runs, aliased i, for a field copied from field, as a key
+// to sort them by: numbers and dates by value, text case-insensitively and options by their
+// position in field. NULL for runs without the field or a value.
+func propertySortExpr(field app.PropertyField) (sq.Sqlizer, error) {
+	var keyExpr string
+	var keyArgs []interface{}
+
+	switch {
+	case field.IsNumber():
+		keyExpr = propertyNumberValue
+	case field.Type == model.PropertyFieldTypeDate:
+		keyExpr = propertyDateValue
+	case field.Type == model.PropertyFieldTypeText:
+		keyExpr = `LOWER(v.Value #>> '{}')`
+	case field.Type == model.PropertyFieldTypeSelect, field.Type == model.PropertyFieldTypeMultiselect:
+		if len(field.Attrs.Options) == 0 {
+			return sq.Expr("NULL"), nil
+		}
+		var positions strings.Builder
+		for i, option := range field.Attrs.Options {
+			positions.WriteString(" WHEN ? THEN " + strconv.Itoa(i))
+			keyArgs = append(keyArgs, strings.ToLower(option.GetName()))
+		}
+		keyExpr = `(SELECT MIN(CASE LOWER(` + sharedPropertyDisplayValue + `)` + positions.String() + ` END) FROM ` + sharedPropertyValueElements + `)`
+	default:
+		return nil, errors.Errorf("cannot sort by %s property field %s", field.Type, field.ID)
+	}
+
+	return sq.Expr(`(
+		SELECT MIN(`+keyExpr+`)
+		FROM PropertyFields AS f
+		JOIN PropertyValues AS v ON v.FieldID = f.ID AND v.TargetID = i.ID AND v.DeleteAt = 0
+		WHERE f.TargetID = i.ID
+		AND ?
+	)`, append(keyArgs, propertyRunFieldsExpr(field))...), nil
+}
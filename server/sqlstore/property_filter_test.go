@@ -0,0 +1,53 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyDateValue(t *testing.T) {
+	db := setupTestDB(t)
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected sql.NullFloat64
+	}{
+		{"milliseconds", `1700000000000`, sql.NullFloat64{Float64: 1700000000000, Valid: true}},
+		{"UTC timestamp", `"2024-01-02T03:04:05Z"`, sql.NullFloat64{Float64: 1704164645000, Valid: true}},
+		{"timestamp with offset and fraction", `"2024-01-02T05:04:05.5+02:00"`, sql.NullFloat64{Float64: 1704164645500, Valid: true}},
+		{"leap day", `"2024-02-29T00:00:00Z"`, sql.NullFloat64{Float64: 1709164800000, Valid: true}},
+		{"date without time", `"2024-01-02"`, sql.NullFloat64{}},
+		{"timestamp followed by text", `"2024-01-02Tnoon"`, sql.NullFloat64{}},
+		{"month out of range", `"2024-13-02T03:04:05Z"`, sql.NullFloat64{}},
+		{"day past the end of the month", `"2023-02-29T03:04:05Z"`, sql.NullFloat64{}},
+		{"hour out of range", `"2024-01-02T25:04:05Z"`, sql.NullFloat64{}},
+		{"offset out of range", `"2024-01-02T03:04:05+99:00"`, sql.NullFloat64{}},
+		{"text", `"soon"`, sql.NullFloat64{}},
+		{"boolean", `true`, sql.NullFloat64{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var actual sql.NullFloat64
+			err := db.Get(&actual, `SELECT `+propertyDateValue+` FROM (SELECT $1::jsonb AS Value) AS v`, testCase.value)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+
+	t.Run("malformed value does not fail the query", func(t *testing.T) {
+		var count int
+		err := db.Get(&count, `
+			SELECT COUNT(*)
+			FROM (VALUES ('"2024-01-02T03:04:05Z"'::jsonb), ('"2023-02-30T03:04:05Z"'::jsonb), ('"2024-01-02Tlater"'::jsonb)) AS v(Value)
+			WHERE `+propertyDateValue+` > 0`)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+}
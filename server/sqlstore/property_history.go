@@ -0,0 +1,74 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// CreatePropertyValueChange appends a change to the history of a run's property values.
+func (s *playbookRunStore) CreatePropertyValueChange(change app.PropertyValueChange) (app.PropertyValueChange, error) {
+	if change.PlaybookRunID == "" {
+		return app.PropertyValueChange{}, errors.New("needs playbook run ID")
+	}
+	if change.FieldID == "" {
+		return app.PropertyValueChange{}, errors.New("needs field ID")
+	}
+	if change.CreateAt == 0 {
+		change.CreateAt = model.GetMillis()
+	}
+	if len(change.OldValue) == 0 {
+		change.OldValue = []byte("null")
+	}
+	if len(change.NewValue) == 0 {
+		change.NewValue = []byte("null")
+	}
+	change.ID = model.NewId()
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Insert("IR_PropertyValueChange").
+		SetMap(map[string]interface{}{
+			"ID":            change.ID,
+			"PlaybookRunID": change.PlaybookRunID,
+			"FieldID":       change.FieldID,
+			"OldValue":      change.OldValue,
+			"NewValue":      change.NewValue,
+			"UserID":        change.UserID,
+			"CreateAt":      change.CreateAt,
+		}))
+	if err != nil {
+		return app.PropertyValueChange{}, errors.Wrapf(err, "failed to store property value change for run %s", change.PlaybookRunID)
+	}
+
+	return change, nil
+}
+
+// GetPropertyValueChanges returns the history of a run's property values, oldest first.
+func (s *playbookRunStore) GetPropertyValueChanges(playbookRunID string, options app.PropertyValueChangeOptions) ([]app.PropertyValueChange, error) {
+	query := s.queryBuilder.
+		Select("ID", "PlaybookRunID", "FieldID", "OldValue", "NewValue", "UserID", "CreateAt").
+		From("IR_PropertyValueChange").
+		Where(sq.Eq{"PlaybookRunID": playbookRunID}).
+		OrderBy("CreateAt ASC", "ID ASC")
+	if options.FieldID != "" {
+		query = query.Where(sq.Eq{"FieldID": options.FieldID})
+	}
+	if options.Until > 0 {
+		query = query.Where(sq.LtOrEq{"CreateAt": options.Until})
+	}
+
+	changes := []app.PropertyValueChange{}
+	if err := s.store.selectBuilder(s.store.db, &changes, query); err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get property value changes for run %s", playbookRunID)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+func TestPlaybookRunStore_PropertyValueChanges(t *testing.T) {
+	db := setupTestDB(t)
+	runStore := setupPlaybookRunStore(t, db)
+
+	runID := model.NewId()
+	severityID := model.NewId()
+	impactID := model.NewId()
+	userID := model.NewId()
+
+	for _, change := range []app.PropertyValueChange{
+		{PlaybookRunID: runID, FieldID: severityID, NewValue: json.RawMessage(`"sev3"`), UserID: userID, CreateAt: 100},
+		{PlaybookRunID: runID, FieldID: impactID, NewValue: json.RawMessage(`"low"`), UserID: userID, CreateAt: 150},
+		{PlaybookRunID: runID, FieldID: severityID, OldValue: json.RawMessage(`"sev3"`), NewValue: json.RawMessage(`"sev1"`), UserID: userID, CreateAt: 200},
+		{PlaybookRunID: model.NewId(), FieldID: severityID, NewValue: json.RawMessage(`"sev2"`), CreateAt: 120},
+	} {
+		created, err := runStore.CreatePropertyValueChange(change)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.ID)
+	}
+
+	t.Run("whole run, oldest first", func(t *testing.T) {
+		changes, err := runStore.GetPropertyValueChanges(runID, app.PropertyValueChangeOptions{})
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		require.Equal(t, int64(100), changes[0].CreateAt)
+		require.JSONEq(t, `null`, string(changes[0].OldValue))
+		require.Equal(t, impactID, changes[1].FieldID)
+		require.JSONEq(t, `"sev3"`, string(changes[2].OldValue))
+		require.JSONEq(t, `"sev1"`, string(changes[2].NewValue))
+		require.Equal(t, userID, changes[2].UserID)
+	})
+
+	t.Run("single field until a time", func(t *testing.T) {
+		changes, err := runStore.GetPropertyValueChanges(runID, app.PropertyValueChangeOptions{FieldID: severityID, Until: 199})
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.JSONEq(t, `"sev3"`, string(changes[0].NewValue))
+	})
+
+	t.Run("run without history", func(t *testing.T) {
+		changes, err := runStore.GetPropertyValueChanges(model.NewId(), app.PropertyValueChangeOptions{})
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("requires run and field", func(t *testing.T) {
+		_, err := runStore.CreatePropertyValueChange(app.PropertyValueChange{FieldID: severityID})
+		require.Error(t, err)
+		_, err = runStore.CreatePropertyValueChange(app.PropertyValueChange{PlaybookRunID: runID})
+		require.Error(t, err)
+	})
+}
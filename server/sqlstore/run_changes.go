@@ -0,0 +1,101 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// CreateRunChanges appends changes to the run change feed. Their cursors are assigned by the
+// database, in insertion order.
+func (s *playbookRunStore) CreateRunChanges(changes []app.RunChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	insert := sq.
+		Insert("IR_RunChange").
+		Columns("Type", "PlaybookRunID", "TeamID", "ChannelID", "Payload", "CreateAt")
+	for _, change := range changes {
+		if change.PlaybookRunID == "" {
+			return errors.New("needs playbook run ID")
+		}
+		if change.Type == "" {
+			return errors.New("needs change type")
+		}
+		if change.CreateAt == 0 {
+			change.CreateAt = model.GetMillis()
+		}
+		if len(change.Payload) == 0 {
+			change.Payload = []byte("null")
+		}
+		insert = insert.Values(change.Type, change.PlaybookRunID, change.TeamID, change.ChannelID, change.Payload, change.CreateAt)
+	}
+
+	if _, err := s.store.execBuilder(s.store.db, insert); err != nil {
+		return errors.Wrapf(err, "failed to store changes of run %s", changes[0].PlaybookRunID)
+	}
+
+	return nil
+}
+
+// GetRunChanges returns the changes of the run change feed matching options, oldest first.
+func (s *playbookRunStore) GetRunChanges(options app.RunChangeOptions) ([]app.RunChange, error) {
+	query := s.queryBuilder.
+		Select("Seq AS Cursor", "Type", "PlaybookRunID", "TeamID", "ChannelID", "Payload", "CreateAt").
+		From("IR_RunChange").
+		Where(sq.Gt{"Seq": options.After}).
+		OrderBy("Seq ASC")
+	if options.TeamID != "" {
+		query = query.Where(sq.Eq{"TeamID": options.TeamID})
+	}
+	if options.PlaybookRunID != "" {
+		query = query.Where(sq.Eq{"PlaybookRunID": options.PlaybookRunID})
+	}
+	if options.Until > 0 {
+		query = query.Where(sq.LtOrEq{"CreateAt": options.Until})
+	}
+	if options.Limit > 0 {
+		query = query.Limit(uint64(options.Limit))
+	}
+
+	changes := []app.RunChange{}
+	if err := s.store.selectBuilder(s.store.db, &changes, query); err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrap(err, "failed to get run changes")
+	}
+
+	return changes, nil
+}
+
+// GetLatestRunChangeCursor returns the cursor of the most recent change of the feed, 0 when empty.
+func (s *playbookRunStore) GetLatestRunChangeCursor() (int64, error) {
+	var cursor int64
+	query := s.queryBuilder.
+		Select("COALESCE(MAX(Seq), 0)").
+		From("IR_RunChange")
+	if err := s.store.getBuilder(s.store.db, &cursor, query); err != nil {
+		return 0, errors.Wrap(err, "failed to get latest run change cursor")
+	}
+
+	return cursor, nil
+}
+
+// DeleteRunChangesBefore deletes the changes of the run change feed created before createAt.
+func (s *playbookRunStore) DeleteRunChangesBefore(createAt int64) error {
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Delete("IR_RunChange").
+		Where(sq.Lt{"CreateAt": createAt}))
+	if err != nil {
+		return errors.Wrap(err, "failed to delete run changes")
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+func TestPlaybookRunStore_RunChanges(t *testing.T) {
+	db := setupTestDB(t)
+	runStore := setupPlaybookRunStore(t, db)
+
+	cursor, err := runStore.GetLatestRunChangeCursor()
+	require.NoError(t, err)
+	require.Zero(t, cursor)
+
+	teamID := model.NewId()
+	runID := model.NewId()
+	otherRunID := model.NewId()
+
+	require.NoError(t, runStore.CreateRunChanges([]app.RunChange{
+		{Type: app.RunChangeCreated, PlaybookRunID: runID, TeamID: teamID, Payload: json.RawMessage(`{"id":"run"}`), CreateAt: 100},
+		{Type: app.RunChangeCreated, PlaybookRunID: otherRunID, TeamID: model.NewId(), CreateAt: 150},
+	}))
+	require.NoError(t, runStore.CreateRunChanges([]app.RunChange{
+		{Type: app.RunChangeUpdated, PlaybookRunID: runID, TeamID: teamID, CreateAt: 200},
+		{Type: app.RunChangeFinished, PlaybookRunID: runID, TeamID: teamID, CreateAt: 200},
+	}))
+	require.NoError(t, runStore.CreateRunChanges(nil))
+
+	t.Run("whole feed, oldest first", func(t *testing.T) {
+		changes, err := runStore.GetRunChanges(app.RunChangeOptions{})
+		require.NoError(t, err)
+		require.Len(t, changes, 4)
+		require.Equal(t, app.RunChangeCreated, changes[0].Type)
+		require.JSONEq(t, `{"id":"run"}`, string(changes[0].Payload))
+		require.JSONEq(t, `null`, string(changes[1].Payload))
+		require.Equal(t, app.RunChangeFinished, changes[3].Type)
+		for i := 1; i < len(changes); i++ {
+			require.Greater(t, changes[i].Cursor, changes[i-1].Cursor)
+		}
+
+		latest, err := runStore.GetLatestRunChangeCursor()
+		require.NoError(t, err)
+		require.Equal(t, changes[3].Cursor, latest)
+	})
+
+	t.Run("resume after a cursor", func(t *testing.T) {
+		all, err := runStore.GetRunChanges(app.RunChangeOptions{})
+		require.NoError(t, err)
+
+		changes, err := runStore.GetRunChanges(app.RunChangeOptions{After: all[1].Cursor, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, all[2], changes[0])
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		changes, err := runStore.GetRunChanges(app.RunChangeOptions{TeamID: teamID})
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+
+		changes, err = runStore.GetRunChanges(app.RunChangeOptions{PlaybookRunID: otherRunID})
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+
+		changes, err = runStore.GetRunChanges(app.RunChangeOptions{Until: 150})
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+	})
+
+	t.Run("delete before", func(t *testing.T) {
+		require.NoError(t, runStore.DeleteRunChangesBefore(200))
+
+		changes, err := runStore.GetRunChanges(app.RunChangeOptions{})
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, app.RunChangeUpdated, changes[0].Type)
+	})
+
+	t.Run("requires run and type", func(t *testing.T) {
+		require.Error(t, runStore.CreateRunChanges([]app.RunChange{{Type: app.RunChangeUpdated}}))
+		require.Error(t, runStore.CreateRunChanges([]app.RunChange{{PlaybookRunID: runID}}))
+	})
+}
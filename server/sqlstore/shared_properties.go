@@ -0,0 +1,121 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-playbooks/server/app"
+)
+
+// sharedPropertyValueElements expands the property value aliased v into its elements, aliased
+// e(element): the items of multiselect values, the value itself otherwise.
+const sharedPropertyValueElements = `jsonb_array_elements_text(
+	CASE WHEN jsonb_typeof(v.Value) = 'array' THEN v.Value ELSE jsonb_build_array(v.Value) END
+) AS e(element)`
+
+// sharedPropertyDisplayValue is the name of the option of the field aliased f matching the value
+// element e.element, or the element itself when the field has no such option. Runs copy the
+// options of their playbook field, so option names are what runs of different playbooks share.
+const sharedPropertyDisplayValue = `COALESCE((
+	SELECT o->>'name'
+	FROM jsonb_array_elements(
+		CASE WHEN jsonb_typeof(f.Attrs->'options') = 'array' THEN f.Attrs->'options' ELSE '[]'::jsonb END
+	) AS o
+	WHERE o->>'id' = e.element
+	LIMIT 1
+), e.element)`
+
+// sharedPropertyRunFieldsExpr matches the run property fields, aliased f, of the team property
+// field sharedFieldID. Besides the fields linked to it, these are the fields of runs started
+// before their playbook field was merged into the team field, found through that playbook field.
+func sharedPropertyRunFieldsExpr(sharedFieldID string) sq.Sqlizer {
+	return sq.Expr(`f.TargetType = ? AND f.DeleteAt = 0 AND (
+		f.Attrs->>'shared_field_id' = ?
+		OR f.Attrs->>'parent_id' IN (
+			SELECT pf.ID
+			FROM PropertyFields AS pf
+			WHERE pf.TargetType = ?
+			AND pf.Attrs->>'shared_field_id' = ?
+		)
+	)`, app.PropertyTargetTypeRun, sharedFieldID, app.PropertyTargetTypePlaybook, sharedFieldID)
+}
+
+// sharedPropertyFilterExpr matches the runs, aliased i, having the team property field
+// sharedFieldID and, if value is not empty, that value for it.
+func (s *playbookRunStore) sharedPropertyFilterExpr(sharedFieldID, value string) sq.Sqlizer {
+	query := s.queryBuilder.
+		Select("1").
+		Prefix("EXISTS(").
+		From("PropertyFields AS f").
+		Where("f.TargetID = i.ID").
+		Where(sharedPropertyRunFieldsExpr(sharedFieldID)).
+		Suffix(")")
+
+	if value != "" {
+		query = query.
+			Join("PropertyValues AS v ON v.FieldID = f.ID AND v.TargetID = i.ID AND v.DeleteAt = 0").
+			Where(sq.Expr(`EXISTS(SELECT 1 FROM `+sharedPropertyValueElements+` WHERE `+sharedPropertyDisplayValue+` = ?)`, value))
+	}
+
+	return query
+}
+
+// GetSharedPropertyFieldStats counts the values of the team property field options.SharedFieldID
+// across the runs matching options.
+func (s *playbookRunStore) GetSharedPropertyFieldStats(requesterInfo app.RequesterInfo, options app.PlaybookRunFilterOptions) (*app.SharedPropertyFieldStats, error) {
+	permissionsExpr := s.buildPermissionsExpr(requesterInfo)
+	teamLimitExpr := buildTeamLimitExpr(requesterInfo, options.TeamID, tableAliasIncident)
+
+	queryForTotal := s.queryBuilder.
+		Select("COUNT(DISTINCT i.ID)").
+		From("IR_Incident AS i").
+		Join("PropertyFields AS f ON f.TargetID = i.ID").
+		Where(sharedPropertyRunFieldsExpr(options.SharedFieldID)).
+		Where(permissionsExpr).
+		Where(teamLimitExpr)
+
+	queryForValues := s.queryBuilder.
+		Select(sharedPropertyDisplayValue+" AS Value", "COUNT(DISTINCT i.ID) AS Count").
+		From("IR_Incident AS i").
+		Join("PropertyFields AS f ON f.TargetID = i.ID").
+		Join("PropertyValues AS v ON v.FieldID = f.ID AND v.TargetID = i.ID AND v.DeleteAt = 0").
+		JoinClause("CROSS JOIN LATERAL "+sharedPropertyValueElements).
+		Where(sharedPropertyRunFieldsExpr(options.SharedFieldID)).
+		Where("e.element IS NOT NULL AND e.element <> ''").
+		Where(permissionsExpr).
+		Where(teamLimitExpr).
+		// Positional, since Value is also a column of PropertyValues.
+		GroupBy("1").
+		OrderBy("2 DESC", "1 ASC")
+
+	if len(options.Statuses) != 0 {
+		queryForTotal = queryForTotal.Where(sq.Eq{"i.CurrentStatus": options.Statuses})
+		queryForValues = queryForValues.Where(sq.Eq{"i.CurrentStatus": options.Statuses})
+	}
+
+	if options.PlaybookID != "" {
+		queryForTotal = queryForTotal.Where(sq.Eq{"i.PlaybookID": options.PlaybookID})
+		queryForValues = queryForValues.Where(sq.Eq{"i.PlaybookID": options.PlaybookID})
+	}
+
+	queryForTotal = queryStartedBetweenTimes(queryForTotal, options.StartedGTE, options.StartedLT)
+	queryForValues = queryStartedBetweenTimes(queryForValues, options.StartedGTE, options.StartedLT)
+
+	stats := &app.SharedPropertyFieldStats{
+		FieldID: options.SharedFieldID,
+		Values:  []app.SharedPropertyValueCount{},
+	}
+
+	if err := s.store.getBuilder(s.store.db, &stats.TotalRuns, queryForTotal); err != nil {
+		return nil, errors.Wrap(err, "failed to count runs with the shared property field")
+	}
+
+	if err := s.store.selectBuilder(s.store.db, &stats.Values, queryForValues); err != nil {
+		return nil, errors.Wrap(err, "failed to count shared property field values")
+	}
+
+	return stats, nil
+}
@@ -164,6 +164,22 @@ func (s *StatsStore) MovingWindowQueryActive(query sq.SelectBuilder, numDays int
 	return results, nil
 }
 
+// LastXRunIDs returns the IDs of the last X runs matching the filters, most recent first.
+func (s *StatsStore) LastXRunIDs(x int, filters *StatsFilters) ([]string, error) {
+	q := s.store.builder.
+		Select("i.ID").
+		From("IR_Incident as i").
+		OrderBy("i.CreateAt DESC").
+		Limit(uint64(x))
+	q = applyFilters(q, filters)
+
+	var ids []string
+	if err := s.store.selectBuilder(s.store.db, &ids, q); err != nil {
+		return nil, errors.Wrap(err, "failed to query last x run ids")
+	}
+	return ids, nil
+}
+
 // RunsStartedPerWeekLastXWeeks returns the number of runs started each week for the last X weeks.
 // Returns data in order of oldest week to most recent week.
 func (s *StatsStore) RunsStartedPerWeekLastXWeeks(x int, filters *StatsFilters) ([]int, [][]int64) {
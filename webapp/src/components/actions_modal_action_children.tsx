@@ -3,6 +3,7 @@
 
 import React from 'react';
 import {useIntl} from 'react-intl';
+import styled from 'styled-components';
 
 import {usePlaybook, usePlaybooksCrud} from 'src/hooks';
 
@@ -10,6 +11,7 @@ import MarkdownTextbox from 'src/components/markdown_textbox';
 import {StyledSelect} from 'src/components/backstage/styles';
 import CategorySelector from 'src/components/backstage/category_selector';
 import ClearIndicator from 'src/components/backstage/playbook_edit/automation/clear_indicator';
+import {Toggle} from 'src/components/backstage/playbook_edit/automation/toggle';
 
 interface WelcomeProps {
     message: string;
@@ -81,6 +83,67 @@ export const RunPlaybookChildren = ({playbookId, onUpdate, editable}: RunPlayboo
     );
 };
 
+interface AutoStartProps {
+    autoStart: boolean;
+    cooldownSeconds: number;
+    onUpdate: (autoStart: boolean, cooldownSeconds: number) => void;
+    editable: boolean;
+}
+
+export const AutoStartChildren = ({autoStart, cooldownSeconds, onUpdate, editable}: AutoStartProps) => {
+    const {formatMessage} = useIntl();
+
+    return (
+        <AutoStartContainer>
+            <Toggle
+                isChecked={autoStart}
+                disabled={!editable}
+                onChange={() => onUpdate(!autoStart, cooldownSeconds)}
+            >
+                {formatMessage({defaultMessage: 'Start the run right away instead of prompting'})}
+            </Toggle>
+            {autoStart &&
+                <CooldownLabel>
+                    {formatMessage({defaultMessage: 'Ignore the keywords for'})}
+                    <CooldownInput
+                        type='number'
+                        min={0}
+                        value={Math.round(cooldownSeconds / 60)}
+                        disabled={!editable}
+                        onChange={(e) => onUpdate(autoStart, Math.max(0, Math.round(Number(e.target.value))) * 60)}
+                    />
+                    {formatMessage({defaultMessage: 'minutes after starting a run'})}
+                </CooldownLabel>
+            }
+        </AutoStartContainer>
+    );
+};
+
+const AutoStartContainer = styled.div`
+    display: flex;
+    flex-direction: column;
+    gap: 8px;
+    margin-top: 12px;
+`;
+
+const CooldownLabel = styled.label`
+    display: flex;
+    align-items: center;
+    gap: 8px;
+    margin: 0;
+    font-weight: normal;
+`;
+
+const CooldownInput = styled.input`
+    width: 64px;
+    height: 32px;
+    padding: 0 8px;
+    border: 1px solid rgba(var(--center-channel-color-rgb), 0.16);
+    border-radius: 4px;
+    background: var(--center-channel-bg);
+    color: var(--center-channel-color);
+`;
+
 interface CategorizeChannelProps {
     categoryName: string;
     onUpdate: (newCategoryName: string) => void;
@@ -19,6 +19,11 @@ const TOKEN_DESCRIPTIONS: Record<string, {id: string; defaultMessage: string}> =
     SEQ: {id: 'template_token.seq', defaultMessage: 'Sequential ID'},
     OWNER: {id: 'template_token.owner', defaultMessage: 'Run owner'},
     CREATOR: {id: 'template_token.creator', defaultMessage: 'Run creator'},
+    TEAM: {id: 'template_token.team', defaultMessage: 'Team name'},
+    PLAYBOOK: {id: 'template_token.playbook', defaultMessage: 'Playbook name'},
+    DATE: {id: 'template_token.date', defaultMessage: 'Current date'},
+    PROGRESS: {id: 'template_token.progress', defaultMessage: 'Completed tasks out of total'},
+    ELAPSED: {id: 'template_token.elapsed', defaultMessage: 'Time since the run started'},
 };
 
 type TokenOption = {
@@ -133,9 +138,10 @@ export const TemplateInput = ({enabled, placeholderText, input, onChange, onBlur
         const ownerFallback = formatMessage({defaultMessage: "Owner's name"});
         const creatorFallback = formatMessage({defaultMessage: "Creator's name"});
         return buildTemplatePreview(input, [], {}, {prefix, ownerFallback, creatorFallback})
-            .replace(/\{([^}]+)\}/g, (match, inner: string) =>
-                (fieldNamesUpperSet.has(inner.trim().toUpperCase()) ? `[${inner.trim()}]` : match),
-            );
+            .replace(/\{([^}]+)\}/g, (match, inner: string) => {
+                const name = inner.split('|')[0].trim();
+                return fieldNamesUpperSet.has(name.toUpperCase()) ? `[${name}]` : match;
+            });
     }, [input, fieldNamesUpperSet, prefix, formatMessage]);
 
     const closeSuggestions = useCallback(() => {
@@ -92,6 +92,11 @@ const PlaybookProperties = ({playbookID, canEdit = true}: Props) => {
                 sort_order: updatedProperty.attrs.sort_order,
                 options: updatedProperty.attrs.options || undefined,
                 value_type: updatedProperty.attrs.value_type,
+                min: updatedProperty.attrs.min,
+                max: updatedProperty.attrs.max,
+                integer: updatedProperty.attrs.integer,
+                required_on_create: updatedProperty.attrs.required_on_create,
+                required_on_finish: updatedProperty.attrs.required_on_finish,
             },
         };
 
@@ -291,6 +296,12 @@ const PlaybookProperties = ({playbookID, canEdit = true}: Props) => {
                         onEditType={(field) => {
                             setEditingTypeId(field.id);
                         }}
+                        onToggleRequired={(field, requirement) => {
+                            updateProperty({
+                                ...field,
+                                attrs: {...field.attrs, [requirement]: !field.attrs[requirement]},
+                            });
+                        }}
                         onDelete={(field) => setDeletingProperty(field)}
                         onDuplicate={async (field) => {
                             const duplicatedPropertyField: PropertyFieldInput = {
@@ -306,6 +317,11 @@ const PlaybookProperties = ({playbookID, canEdit = true}: Props) => {
                                         color: opt.color,
                                     })) || undefined,
                                     value_type: field.attrs.value_type,
+                                    min: field.attrs.min,
+                                    max: field.attrs.max,
+                                    integer: field.attrs.integer,
+                                    required_on_create: field.attrs.required_on_create,
+                                    required_on_finish: field.attrs.required_on_finish,
                                 },
                             };
 
@@ -6,20 +6,26 @@ import {FormattedMessage} from 'react-intl';
 import styled from 'styled-components';
 
 import {
+    CheckIcon,
     ContentCopyIcon,
     DotsHorizontalIcon,
+    FlagCheckeredIcon,
     FormatListBulletedIcon,
     PencilOutlineIcon,
+    PlayOutlineIcon,
     TrashCanOutlineIcon,
 } from '@mattermost/compass-icons/components';
 
 import DotMenu, {DropdownMenu, DropdownMenuItem} from 'src/components/dot_menu';
 import type {PropertyField} from 'src/types/properties';
 
+export type PropertyRequirement = 'required_on_create' | 'required_on_finish';
+
 type Props = {
     field: PropertyField;
     onRename?: (field: PropertyField) => void;
     onEditType?: (field: PropertyField) => void;
+    onToggleRequired?: (field: PropertyField, requirement: PropertyRequirement) => void;
     onDelete?: (field: PropertyField) => void;
     onDuplicate?: (field: PropertyField) => void;
 };
@@ -28,6 +34,7 @@ const PropertyDotMenu = ({
     field,
     onRename,
     onEditType,
+    onToggleRequired,
     onDelete,
     onDuplicate,
 }: Props) => {
@@ -39,6 +46,10 @@ const PropertyDotMenu = ({
         onEditType?.(field);
     };
 
+    const handleToggleRequired = (requirement: PropertyRequirement) => {
+        onToggleRequired?.(field, requirement);
+    };
+
     const handleDeleteClick = () => {
         onDelete?.(field);
     };
@@ -75,6 +86,28 @@ const PropertyDotMenu = ({
                         </MenuItemLeft>
                     </MenuItemContent>
                 </DropdownMenuItem>
+                <DropdownMenuItem onClick={() => handleToggleRequired('required_on_create')}>
+                    <MenuItemContent>
+                        <MenuItemLeft>
+                            <PlayOutlineIcon size={18}/>
+                            <FormattedMessage
+                                defaultMessage='Required to start a run'
+                            />
+                        </MenuItemLeft>
+                        {field.attrs.required_on_create && <CheckIcon size={18}/>}
+                    </MenuItemContent>
+                </DropdownMenuItem>
+                <DropdownMenuItem onClick={() => handleToggleRequired('required_on_finish')}>
+                    <MenuItemContent>
+                        <MenuItemLeft>
+                            <FlagCheckeredIcon size={18}/>
+                            <FormattedMessage
+                                defaultMessage='Required to finish a run'
+                            />
+                        </MenuItemLeft>
+                        {field.attrs.required_on_finish && <CheckIcon size={18}/>}
+                    </MenuItemContent>
+                </DropdownMenuItem>
                 <DropdownMenuItem onClick={handleDuplicate}>
                     <MenuItemContent>
                         <MenuItemLeft>
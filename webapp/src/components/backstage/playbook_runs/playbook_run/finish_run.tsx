@@ -82,8 +82,10 @@ export const useOnFinishRun = (playbookRun: PlaybookRun | null, location: string
         const onConfirm = async () => {
             const result = await finishRun(run.id);
             if (result?.error) {
+                // A 400 names the properties that must be filled in before finishing.
+                const requiredPropertiesMessage = result.error.status_code === 400 ? result.error.message : '';
                 toaster.add({
-                    content: formatMessage({defaultMessage: 'It wasn\'t possible to finish the run.'}),
+                    content: requiredPropertiesMessage || formatMessage({defaultMessage: 'It wasn\'t possible to finish the run.'}),
                     toastStyle: ToastStyle.Failure,
                 });
                 return;
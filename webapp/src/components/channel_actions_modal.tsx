@@ -30,7 +30,12 @@ import {
 } from 'src/types/channel_actions';
 
 import ActionsModal, {ActionsContainer, TriggersContainer} from 'src/components/actions_modal';
-import {CategorizeChannelChildren, RunPlaybookChildren, WelcomeActionChildren} from 'src/components/actions_modal_action_children';
+import {
+    AutoStartChildren,
+    CategorizeChannelChildren,
+    RunPlaybookChildren,
+    WelcomeActionChildren,
+} from 'src/components/actions_modal_action_children';
 import {useHasChannelPermission} from 'src/hooks/permissions';
 
 interface ActionState<T extends PayloadType> {
@@ -86,7 +91,7 @@ const ChannelActionsModal = () => {
 
     const [welcomeMsg, setWelcomeMsg, welcomeMsgInit, welcomeMsgReset, welcomeMsgOverwrite] = useActionState(welcomeMsgEmptyState);
     const [categorization, setCategorization, categorizationInit, categorizationReset, categorizationOverwrite] = useActionState(categorizationEmptyState);
-    const [prompt, setPrompt, promptInit, promptReset, promptOverwrite] = useActionState(promptEmptyState);
+    const [prompt, setPrompt, promptInit, promptReset, promptOverwrite] = useActionState<PromptRunPlaybookFromKeywordsPayload>(promptEmptyState);
 
     const editable = channel?.type === 'O' ? publicChannelPermission : privateChannelPermission;
 
@@ -223,6 +228,12 @@ const ChannelActionsModal = () => {
                                 onUpdate={(newId) => setPrompt({...prompt, payload: {...prompt.payload, playbook_id: newId}})}
                                 editable={editable}
                             />
+                            <AutoStartChildren
+                                autoStart={Boolean(prompt.payload.auto_start)}
+                                cooldownSeconds={prompt.payload.cooldown_seconds ?? 0}
+                                onUpdate={(autoStart, cooldownSeconds) => setPrompt({...prompt, payload: {...prompt.payload, auto_start: autoStart, cooldown_seconds: cooldownSeconds}})}
+                                editable={editable}
+                            />
                         </Action>
                     </ActionsContainer>
                 </Trigger>
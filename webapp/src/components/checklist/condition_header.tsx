@@ -12,7 +12,7 @@ import {WithTooltip} from '@mattermost/shared/components/tooltip';
 import {useAppSelector} from 'src/hooks/redux';
 
 import {ConditionExprV1} from 'src/types/conditions';
-import {PropertyField} from 'src/types/properties';
+import {PropertyField, PropertyValueType} from 'src/types/properties';
 import {getCondition} from 'src/selectors';
 import {useProxyState} from 'src/hooks';
 import {useConfirmModal} from 'src/components/widgets/confirmation_modal';
@@ -260,7 +260,9 @@ const ConditionHeader = ({
         const selectedOperatorOption = operatorOptions.find((opt) => opt.value === cond.operator);
 
         // For select fields, get single value; for multiselect, get multiple values
-        const selectedValueOptions = (isSelectField || isMultiSelectField) && Array.isArray(cond.value) ? valueOptions.filter((opt) => cond.value.includes(opt.value)) : undefined;
+        const selectedValues = Array.isArray(cond.value) ? cond.value : [];
+        const selectedValueOptions = (isSelectField || isMultiSelectField) && Array.isArray(cond.value) ? valueOptions.filter((opt) => selectedValues.includes(opt.value)) : undefined;
+        const isNumberField = selectedProperty?.type === 'text' && selectedProperty.attrs.value_type === PropertyValueType.Number;
 
         return (
             <ConditionRow key={index}>
@@ -324,8 +326,13 @@ const ConditionHeader = ({
                     />
                 ) : (
                     <StyledInput
-                        value={cond.value as string}
-                        onChange={(e) => updateCondition(index, {value: e.target.value})}
+                        type={isNumberField ? 'number' : 'text'}
+                        value={String(cond.value)}
+                        onChange={(e) => {
+                            const number = Number(e.target.value);
+                            const isNumber = isNumberField && e.target.value.trim() !== '' && Number.isFinite(number);
+                            updateCondition(index, {value: isNumber ? number : e.target.value});
+                        }}
                         placeholder={formatMessage({defaultMessage: 'Enter value...'})}
                     />
                 )}
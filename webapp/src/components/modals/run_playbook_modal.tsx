@@ -208,14 +208,16 @@ export const RunPlaybookModal = ({
         return names;
     }, [playbook?.channel_name_template]);
 
-    const templateFields = useMemo(() => {
-        if (!playbookAttributes || playbookAttributes.length === 0 || templateFieldNames.size === 0) {
+    // Fields that must be filled in before starting the run: those referenced by the name
+    // template and those marked as required on create. Computed fields are filled in by the server.
+    const requiredFields = useMemo(() => {
+        if (!playbookAttributes || playbookAttributes.length === 0) {
             return [];
         }
         const seen = new Set<string>();
         return playbookAttributes.filter((f) => {
             const lower = f.name.toLowerCase();
-            if (templateFieldNames.has(lower) && !seen.has(lower)) {
+            if ((templateFieldNames.has(lower) || f.attrs?.required_on_create) && !f.attrs?.computed && !seen.has(lower)) {
                 seen.add(lower);
                 return true;
             }
@@ -273,7 +275,7 @@ export const RunPlaybookModal = ({
 
     const namePreviewTooLong = hasTemplate && [...namePreview].length > RUN_NAME_MAX_LENGTH;
 
-    const requiredFieldsFilled = useMemo(() => templateFields.every((field) => {
+    const requiredFieldsFilled = useMemo(() => requiredFields.every((field) => {
         const val = propertyValues[field.id];
         if (val === undefined || val === null || val === '') {
             return false;
@@ -282,7 +284,7 @@ export const RunPlaybookModal = ({
             return false;
         }
         return true;
-    }), [templateFields, propertyValues]);
+    }), [requiredFields, propertyValues]);
 
     const isFormValid = !attributesLoading && nameValid && requiredFieldsFilled && unmatchedTemplateNames.length === 0 && (createNewChannel || channelId !== '');
 
@@ -382,12 +384,18 @@ export const RunPlaybookModal = ({
                 const statsData = buildStatsData(isNewChannel, isLinkedChannel);
                 onRunCreated(newPlaybookRun.id, newPlaybookRun.channel_id, statsData);
                 onHide?.();
-            }).catch(() => {
+            }).catch((error) => {
                 if (!isMountedRef.current) {
                     resetSubmitting();
                     return;
                 }
                 resetSubmitting();
+
+                // Missing required properties are reported with a message naming the fields.
+                if (error?.status_code === 400 && error?.message) {
+                    setSubmitError(error.message);
+                    return;
+                }
                 setSubmitError(formatMessage({defaultMessage: 'An error occurred while creating the run.'}));
             });
     }, [playbook, selectedPlaybookId, isFormValid, propertyValues, userId, runName, runSummary, channelId, createPublicRun, channelMode, playbookId, onHide, onRunCreated, formatMessage, resetSubmitting, buildStatsData]);
@@ -475,9 +483,9 @@ export const RunPlaybookModal = ({
                             {formatMessage({defaultMessage: 'The resolved run name exceeds the {maxLength}-character limit. Edit the playbook template to use fewer or shorter fields.'}, {maxLength: RUN_NAME_MAX_LENGTH})}
                         </ErrorMessage>
                     )}
-                    {templateFields.length > 0 && (
+                    {requiredFields.length > 0 && (
                         <PropertyFieldsSection
-                            fields={templateFields}
+                            fields={requiredFields}
                             values={propertyValues}
                             onSetValues={setPropertyValues}
                             onUserKnown={handleUserKnown}
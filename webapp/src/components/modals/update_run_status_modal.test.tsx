@@ -110,4 +110,15 @@ describe('computeStatusMessagePreview', () => {
         expect(computeStatusMessagePreview('By {owner}', makeRun(), userMap)).toBe('By Alice');
         expect(computeStatusMessagePreview('By {Owner}', makeRun(), userMap)).toBe('By Alice');
     });
+
+    it('resolves checklist progress and elapsed time from the run', () => {
+        const run = makeRun({task_completed: 3, task_total: 10, create_at: 1000, end_at: 1000 + (((3 * 60) + 20) * 60 * 1000)});
+        expect(computeStatusMessagePreview('{PROGRESS} tasks done after {ELAPSED}', run, userMap))
+            .toBe('3/10 tasks done after 3h 20m');
+    });
+
+    it('resolves team and playbook names when provided', () => {
+        expect(computeStatusMessagePreview('{PLAYBOOK} for {TEAM}', makeRun(), userMap, {teamName: 'Payments', playbookTitle: 'Incident response'}))
+            .toBe('Incident response for Payments');
+    });
 });
@@ -15,6 +15,7 @@ import {DateTime, Duration} from 'luxon';
 
 import {getCurrentUserId} from 'mattermost-redux/selectors/entities/users';
 import {getChannel} from 'mattermost-redux/selectors/entities/channels';
+import {getTeam} from 'mattermost-redux/selectors/entities/teams';
 
 import {ApolloProvider, useQuery} from '@apollo/client';
 
@@ -56,7 +57,7 @@ import {useFinishRunConfirmationMessage} from 'src/components/backstage/playbook
 import {getPlaybooksGraphQLClient} from 'src/graphql_client';
 import {getFragmentData, graphql} from 'src/graphql/generated';
 import {DefaultMessageFragment, ReminderTimerFragment} from 'src/graphql/generated/graphql';
-import {resolveTemplatePreview} from 'src/utils/template_utils';
+import {formatElapsed, resolveTemplatePreview} from 'src/utils/template_utils';
 import {PropertyField} from 'src/types/properties';
 import {PlaybookRun} from 'src/types/playbook_run';
 
@@ -98,7 +99,13 @@ const runStatusModalQueryDocument = graphql(/* GraphQL */`
     }
 `);
 
-function buildRunTemplateContext(run: PlaybookRun, userMap: Record<string, string>) {
+// Run context not carried by the run itself, resolved by the caller when available.
+interface RunTemplateExtras {
+    teamName?: string;
+    playbookTitle?: string;
+}
+
+function buildRunTemplateContext(run: PlaybookRun, userMap: Record<string, string>, extras: RunTemplateExtras = {}) {
     const fields = (run.property_fields ?? []) as PropertyField[];
     const values: Record<string, unknown> = {};
     for (const pv of run.property_values ?? []) {
@@ -106,13 +113,22 @@ function buildRunTemplateContext(run: PlaybookRun, userMap: Record<string, strin
             values[pv.field_id] = pv.value;
         }
     }
-    return {
-        fields,
-        values,
-        seqValue: run.sequential_id || (run.run_number == null ? '' : String(run.run_number)),
-        ownerName: (run.owner_user_id && userMap[run.owner_user_id]) || run.owner_user_id || '',
-        creatorName: (run.reporter_user_id && userMap[run.reporter_user_id]) || run.reporter_user_id || '',
+    const tokens: Record<string, string> = {
+        SEQ: run.sequential_id || (run.run_number == null ? '' : String(run.run_number)),
+        OWNER: (run.owner_user_id && userMap[run.owner_user_id]) || run.owner_user_id || '',
+        CREATOR: (run.reporter_user_id && userMap[run.reporter_user_id]) || run.reporter_user_id || '',
+        PROGRESS: `${run.task_completed ?? 0}/${run.task_total ?? 0}`,
     };
+    if (run.create_at) {
+        tokens.ELAPSED = formatElapsed((run.end_at || Date.now()) - run.create_at);
+    }
+    if (extras.teamName) {
+        tokens.TEAM = extras.teamName;
+    }
+    if (extras.playbookTitle) {
+        tokens.PLAYBOOK = extras.playbookTitle;
+    }
+    return {fields, values, tokens};
 }
 
 // Returns the resolved message, or '' when resolution produces no change (meaning no
@@ -121,13 +137,10 @@ export function computeStatusMessagePreview(
     message: string,
     run: PlaybookRun,
     userMap: Record<string, string>,
+    extras: RunTemplateExtras = {},
 ): string {
-    const ctx = buildRunTemplateContext(run, userMap);
-    const resolved = resolveTemplatePreview(message, ctx.fields, ctx.values, {
-        SEQ: ctx.seqValue,
-        OWNER: ctx.ownerName,
-        CREATOR: ctx.creatorName,
-    }, userMap);
+    const ctx = buildRunTemplateContext(run, userMap, extras);
+    const resolved = resolveTemplatePreview(message, ctx.fields, ctx.values, ctx.tokens, userMap);
 
     // Only show preview when resolution actually changed something —
     // if the message has {foo} that matches no known token or field,
@@ -135,26 +148,23 @@ export function computeStatusMessagePreview(
     return resolved === message ? '' : resolved;
 }
 
-function useStatusMessagePreview(playbookRunId: string, message: string | undefined): string {
+function useStatusMessagePreview(playbookRunId: string, message: string | undefined, playbookTitle?: string): string {
     const [run] = useRun(playbookRunId);
     const userMap = useUserDisplayNameMap();
+    const teamName = useAppSelector((state) => (run?.team_id ? getTeam(state, run.team_id)?.display_name : undefined));
 
     const derived = useMemo(() => {
         if (!run) {
             return null;
         }
-        return buildRunTemplateContext(run, userMap);
-    }, [run, userMap]);
+        return buildRunTemplateContext(run, userMap, {teamName, playbookTitle});
+    }, [run, userMap, teamName, playbookTitle]);
 
     return useMemo(() => {
         if (!message || !derived) {
             return '';
         }
-        const resolved = resolveTemplatePreview(message, derived.fields, derived.values, {
-            SEQ: derived.seqValue,
-            OWNER: derived.ownerName,
-            CREATOR: derived.creatorName,
-        }, userMap);
+        const resolved = resolveTemplatePreview(message, derived.fields, derived.values, derived.tokens, userMap);
         return resolved === message ? '' : resolved;
     }, [message, derived, userMap]);
 }
@@ -243,7 +253,7 @@ export const UpdateRunStatusModal = ({
         }) : '';
     };
 
-    const messagePreview = useStatusMessagePreview(playbookRunId, message);
+    const messagePreview = useStatusMessagePreview(playbookRunId, message, playbook?.title);
 
     const pendingChanges = !(providedMessage === message || message === defaultMessage || message === '');
 
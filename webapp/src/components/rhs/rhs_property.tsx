@@ -16,6 +16,7 @@ import SelectProperty from './properties/property_select';
 import MultiselectProperty from './properties/property_multiselect';
 import UserProperty, {MultiuserProperty} from './properties/property_user';
 import DateProperty from './properties/property_date';
+import EmptyState from './properties/empty_state';
 
 interface Props {
     field: PropertyField;
@@ -44,6 +45,14 @@ const RHSProperty = (props: Props) => {
     }, [setRunPropertyValue, props.runID, props.field.id, addToast, formatMessage]);
 
     const renderPropertyComponent = () => {
+        if (props.field.attrs.computed) {
+            const computedValue = props.value?.value;
+            if (computedValue === undefined || computedValue === null || computedValue === '') {
+                return <EmptyState/>;
+            }
+            return <ComputedValue>{String(computedValue)}</ComputedValue>;
+        }
+
         const commonProps = {
             field: props.field,
             value: props.value,
@@ -114,6 +123,12 @@ const PropertyRow = styled.div`
     margin-bottom: 12px;
 `;
 
+const ComputedValue = styled.span`
+    color: var(--center-channel-color);
+    font-size: 14px;
+    line-height: 20px;
+`;
+
 const PropertyLabel = styled.div`
     color: var(--center-channel-color);
     font-size: 12px;
@@ -15,27 +15,70 @@ export enum ChannelActionType {
     WelcomeMessage = 'send_welcome_message',
     PromptRunPlaybook = 'prompt_run_playbook',
     CategorizeChannel = 'categorize_channel',
+    StartRun = 'start_run',
+    AddToRunTimeline = 'add_to_run_timeline',
+    SetRunProperty = 'set_run_property',
 }
 
 export enum ChannelTriggerType {
     NewMemberJoins = 'new_member_joins',
     KeywordsPosted = 'keywords',
+    MemberLeaves = 'member_leaves',
+    ChannelHeaderChanged = 'channel_header_changed',
+    FileAttachment = 'file_attachment_posted',
+    MessageFromUser = 'message_from_user',
+    ReactionAdded = 'reaction_added',
 }
 
 export type PayloadType =
     | WelcomeMessageActionPayload
     | PromptRunPlaybookFromKeywordsPayload
-    | CategorizeChannelPayload;
+    | CategorizeChannelPayload
+    | StartRunPayload
+    | AddToRunTimelinePayload
+    | SetRunPropertyPayload;
 
 export interface WelcomeMessageActionPayload {
     message: string;
 }
 
-export interface PromptRunPlaybookFromKeywordsPayload {
+export interface PromptRunPlaybookFromKeywordsPayload extends RunAutoStartOptions {
     keywords: string[];
     playbook_id: string;
+    auto_start?: boolean;
 }
 
 export interface CategorizeChannelPayload {
     category_name: string;
 }
+
+// Narrows down when the run actions fire: keywords for keywords, user_ids for
+// message_from_user and emoji_names for reaction_added.
+export interface ChannelActionTriggerFilter {
+    keywords?: string[];
+    user_ids?: string[];
+    emoji_names?: string[];
+}
+
+export interface PropertyExtractor {
+    field_id: string;
+    pattern: string;
+}
+
+export interface RunAutoStartOptions {
+    cooldown_seconds?: number;
+    property_extractors?: PropertyExtractor[];
+}
+
+export interface StartRunPayload extends ChannelActionTriggerFilter, RunAutoStartOptions {
+    playbook_id: string;
+}
+
+export interface AddToRunTimelinePayload extends ChannelActionTriggerFilter {
+    summary: string;
+}
+
+export interface SetRunPropertyPayload extends ChannelActionTriggerFilter {
+    field_id: string;
+    value: unknown;
+}
@@ -486,5 +486,36 @@ describe('conditions', () => {
                 expect(result).toBe(false);
             });
         });
+
+        describe('number and date field conditions', () => {
+            const numberField: PropertyField = {
+                ...createTextField('customers'),
+                attrs: {...createTextField('customers').attrs, value_type: 'number'},
+            };
+            const dateField: PropertyField = {...createTextField('detected'), type: 'date'};
+            const numberValue = {...createTextValue('customers', ''), value: 150 as unknown as string};
+            const dateValue = createTextValue('detected', '2024-03-15T12:00:00Z');
+
+            it('should compare numbers with gt and lt', () => {
+                expect(executeCondition({gt: {field_id: 'customers', value: 100}}, [numberField], [numberValue])).toBe(true);
+                expect(executeCondition({gt: {field_id: 'customers', value: 150}}, [numberField], [numberValue])).toBe(false);
+                expect(executeCondition({lt: {field_id: 'customers', value: 1000}}, [numberField], [numberValue])).toBe(true);
+            });
+
+            it('should compare numbers numerically with is', () => {
+                expect(executeCondition({is: {field_id: 'customers', value: 150}}, [numberField], [numberValue])).toBe(true);
+                expect(executeCondition({isNot: {field_id: 'customers', value: 151}}, [numberField], [numberValue])).toBe(true);
+            });
+
+            it('should compare dates with gt and lt', () => {
+                expect(executeCondition({gt: {field_id: 'detected', value: '2024-03-01'}}, [dateField], [dateValue])).toBe(true);
+                expect(executeCondition({lt: {field_id: 'detected', value: 1710000000000}}, [dateField], [dateValue])).toBe(false);
+            });
+
+            it('should not match missing values', () => {
+                expect(executeCondition({gt: {field_id: 'customers', value: 1}}, [numberField], [])).toBe(false);
+                expect(executeCondition({lt: {field_id: 'customers', value: 1}}, [numberField], [])).toBe(false);
+            });
+        });
     });
 });
@@ -23,6 +23,10 @@ export interface ConditionExprV1 {
     // Comparison operators (mutually exclusive with logical operators)
     is?: ComparisonCondition;
     isNot?: ComparisonCondition;
+
+    // Ordered comparisons for number and date fields
+    gt?: ComparisonCondition;
+    lt?: ComparisonCondition;
 }
 
 export interface ComparisonCondition {
@@ -32,7 +36,8 @@ export interface ComparisonCondition {
     // - string for text fields (case-insensitive matching)
     // - string[] for select fields ("any of" logic)
     // - string[] for multiselect fields ("any of" logic)
-    value: string | string[];
+    // - number for number fields, and for date fields in gt/lt (milliseconds or a date string)
+    value: string | string[] | number;
 }
 
 // Execute function that matches the Go Evaluate method
@@ -98,14 +103,90 @@ const evaluate = (
         return isNot(field, value, condition.isNot.value);
     }
 
+    if (condition.gt) {
+        const field = fieldMap.get(condition.gt.field_id);
+        if (!field) {
+            return false;
+        }
+
+        return compare(field, valueMap.get(condition.gt.field_id), condition.gt.value) === 1;
+    }
+
+    if (condition.lt) {
+        const field = fieldMap.get(condition.lt.field_id);
+        if (!field) {
+            return false;
+        }
+
+        return compare(field, valueMap.get(condition.lt.field_id), condition.lt.value) === -1;
+    }
+
     return true;
 };
 
+// compareUnordered is returned by compare when either side cannot be ordered; it never matches.
+const compareUnordered = 2;
+
+// compare matches the Go compare function: it orders the value of a number or date field
+// against the condition value, returning -1, 0 or 1.
+const compare = (
+    propertyField: PropertyField,
+    propertyValue: PropertyValue | undefined,
+    conditionValue: ComparisonCondition['value']
+): number => {
+    const value = orderedValue(propertyField, propertyValue?.value);
+    const target = orderedValue(propertyField, conditionValue);
+    if (value === null || target === null) {
+        return compareUnordered;
+    }
+    if (value < target) {
+        return -1;
+    }
+    if (value > target) {
+        return 1;
+    }
+    return 0;
+};
+
+const isNumberField = (propertyField: PropertyField): boolean => {
+    return propertyField.type === 'text' && propertyField.attrs.value_type === 'number';
+};
+
+// orderedValue converts number values, and date values to milliseconds since the epoch.
+const orderedValue = (propertyField: PropertyField, raw: unknown): number | null => {
+    if (raw === null || raw === undefined || raw === '') {
+        return null;
+    }
+
+    if (propertyField.type === 'date') {
+        if (typeof raw === 'number') {
+            return raw;
+        }
+        if (typeof raw !== 'string') {
+            return null;
+        }
+        const numeric = Number(raw);
+        const millis = Number.isNaN(numeric) ? Date.parse(raw) : numeric;
+        return Number.isNaN(millis) ? null : millis;
+    }
+
+    if (!isNumberField(propertyField)) {
+        return null;
+    }
+
+    const number = typeof raw === 'number' ? raw : Number(typeof raw === 'string' ? raw.trim() : NaN);
+    return Number.isFinite(number) ? number : null;
+};
+
 const is = (
     propertyField: PropertyField,
     propertyValue: PropertyValue,
-    conditionValue: string | string[]
+    conditionValue: ComparisonCondition['value']
 ): boolean => {
+    if (isNumberField(propertyField)) {
+        return compare(propertyField, propertyValue, conditionValue) === 0;
+    }
+
     if (!propertyValue.value) {
         return false;
     }
@@ -142,7 +223,7 @@ const is = (
 const isNot = (
     propertyField: PropertyField,
     propertyValue: PropertyValue,
-    conditionValue: string | string[]
+    conditionValue: ComparisonCondition['value']
 ): boolean => {
     return !is(propertyField, propertyValue, conditionValue);
 };
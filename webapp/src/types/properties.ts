@@ -17,10 +17,26 @@ export type PropertyField = PropertyFieldBase & {
         sort_order: number;
         options: PropertyFieldOption[] | null;
         parent_id?: string;
+        shared_field_id?: string;
         value_type?: string;
+        min?: number;
+        max?: number;
+        integer?: boolean;
+        required_on_create?: boolean;
+        required_on_finish?: boolean;
+        computed?: PropertyFieldComputed;
     };
 };
 
+// Computed fields are read-only number fields whose value the server derives from other data.
+export type PropertyFieldComputed = {
+    kind: 'formula' | 'duration_since_creation' | 'open_items_count' | 'lookup';
+    expression?: string;
+    checklist?: string;
+    source_field?: string;
+    lookup?: Record<string, number>;
+};
+
 export type PropertyValue = PropertyValueBase<string | string[]> & {
     field_id: string;
 }
@@ -45,9 +61,24 @@ export type PropertyFieldAttrsInput = {
     sort_order?: number;
     options?: PropertyOptionInput[];
     parent_id?: string;
+    shared_field_id?: string;
     value_type?: string;
+    min?: number;
+    max?: number;
+    integer?: boolean;
+    required_on_create?: boolean;
+    required_on_finish?: boolean;
+    computed?: PropertyFieldComputed;
 };
 
+// Value types refine text fields (number, url, email) and date fields (datetime).
+export enum PropertyValueType {
+    DateTime = 'datetime',
+    Email = 'email',
+    Number = 'number',
+    URL = 'url',
+}
+
 export type PropertyFieldInput = {
     name: string;
     type: PropertyField['type'];
@@ -1,29 +1,41 @@
 // Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.txt for license information.
 
-import {ConditionExprV1} from 'src/types/conditions';
+import {ComparisonCondition, ConditionExprV1} from 'src/types/conditions';
 import {PropertyField} from 'src/types/properties';
 
+type ConditionOperator = 'is' | 'isNot' | 'gt' | 'lt';
+type ExtractedCondition = {operator: ConditionOperator; fieldId: string; value: ComparisonCondition['value']};
+
+const extractComparison = (expr: ConditionExprV1): ExtractedCondition | null => {
+    for (const operator of ['is', 'isNot', 'gt', 'lt'] as const) {
+        const comparison = expr[operator];
+        if (comparison) {
+            return {operator, fieldId: comparison.field_id, value: comparison.value};
+        }
+    }
+    return null;
+};
+
 // Helper to extract conditions from expr (handles both simple and compound conditions)
-export const extractConditions = (expr: ConditionExprV1): Array<{operator: 'is' | 'isNot'; fieldId: string; value: string | string[]}> => {
-    const conditions: Array<{operator: 'is' | 'isNot'; fieldId: string; value: string | string[]}> = [];
+export const extractConditions = (expr: ConditionExprV1): ExtractedCondition[] => {
+    const conditions: ExtractedCondition[] = [];
 
     // Check if it's a compound condition (and/or)
     const nestedExprs = expr.and || expr.or || [];
     if (nestedExprs.length > 0) {
         nestedExprs.forEach((nested) => {
-            if (nested.is) {
-                conditions.push({operator: 'is', fieldId: nested.is.field_id, value: nested.is.value});
-            } else if (nested.isNot) {
-                conditions.push({operator: 'isNot', fieldId: nested.isNot.field_id, value: nested.isNot.value});
+            const comparison = extractComparison(nested);
+            if (comparison) {
+                conditions.push(comparison);
             }
         });
-    } else if (expr.is) {
-        // Simple condition
-        conditions.push({operator: 'is', fieldId: expr.is.field_id, value: expr.is.value});
-    } else if (expr.isNot) {
+    } else {
         // Simple condition
-        conditions.push({operator: 'isNot', fieldId: expr.isNot.field_id, value: expr.isNot.value});
+        const comparison = extractComparison(expr);
+        if (comparison) {
+            conditions.push(comparison);
+        }
     }
 
     return conditions;
@@ -31,7 +43,7 @@ export const extractConditions = (expr: ConditionExprV1): Array<{operator: 'is'
 
 // Format a single condition for display
 export const formatCondition = (
-    cond: {operator: 'is' | 'isNot'; fieldId: string; value: string | string[]},
+    cond: ExtractedCondition,
     propertyFields: PropertyField[],
     operatorIs: string = 'is',
     operatorIsNot: string = 'is not'
@@ -41,7 +53,9 @@ export const formatCondition = (
 
     // Use different operator labels for multiselect fields
     let operator: string;
-    if (field?.type === 'multiselect') {
+    if (cond.operator === 'gt' || cond.operator === 'lt') {
+        operator = cond.operator === 'gt' ? '>' : '<';
+    } else if (field?.type === 'multiselect') {
         operator = cond.operator === 'is' ? 'contains' : 'does not contain';
     } else {
         operator = cond.operator === 'is' ? operatorIs : operatorIsNot;
@@ -55,14 +69,19 @@ export const formatCondition = (
             const valueArray = Array.isArray(cond.value) ? cond.value : [cond.value];
             valueNames = valueArray.map((valueId) => {
                 const option = field.attrs.options?.find((opt) => opt.id === valueId);
-                return option?.name || valueId;
+                return option?.name || String(valueId);
             });
         }
         break;
+    case 'date':
+        valueNames = [typeof cond.value === 'number' ? new Date(cond.value).toISOString().slice(0, 10) : String(cond.value)];
+        break;
     case 'text':
         {
             const textValue = Array.isArray(cond.value) ? cond.value[0] || '' : cond.value;
-            if (textValue === '') {
+            if (typeof textValue === 'number') {
+                valueNames = [String(textValue)];
+            } else if (textValue === '') {
                 valueNames = ['empty'];
             } else {
                 valueNames = [`"${textValue}"`];
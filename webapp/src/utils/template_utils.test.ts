@@ -7,6 +7,7 @@ import {
     SYSTEM_TOKENS,
     buildTemplatePreview,
     extractTemplateFieldNames,
+    formatElapsed,
     formatSequentialID,
     resolveTemplatePreview,
 } from './template_utils';
@@ -19,12 +20,33 @@ describe('template_utils', () => {
             expect(SYSTEM_TOKENS.has('CREATOR')).toBe(true);
         });
 
+        it('should contain TEAM, PLAYBOOK, and DATE', () => {
+            expect(SYSTEM_TOKENS.has('TEAM')).toBe(true);
+            expect(SYSTEM_TOKENS.has('PLAYBOOK')).toBe(true);
+            expect(SYSTEM_TOKENS.has('DATE')).toBe(true);
+        });
+
+        it('should contain PROGRESS and ELAPSED', () => {
+            expect(SYSTEM_TOKENS.has('PROGRESS')).toBe(true);
+            expect(SYSTEM_TOKENS.has('ELAPSED')).toBe(true);
+        });
+
         it('should not contain unknown tokens', () => {
             expect(SYSTEM_TOKENS.has('UNKNOWN')).toBe(false);
             expect(SYSTEM_TOKENS.has('NAME')).toBe(false);
         });
     });
 
+    describe('formatElapsed', () => {
+        it('should keep the two most significant units', () => {
+            expect(formatElapsed(0)).toBe('0m');
+            expect(formatElapsed(-3600000)).toBe('0m');
+            expect(formatElapsed(((5 * 60) + 30) * 1000)).toBe('5m');
+            expect(formatElapsed(((3 * 60) + 20) * 60000)).toBe('3h 20m');
+            expect(formatElapsed(((51 * 60) + 59) * 60000)).toBe('2d 3h');
+        });
+    });
+
     describe('formatSequentialID', () => {
         it('should return empty string when runNumber is 0 and prefix is empty', () => {
             expect(formatSequentialID('', 0)).toBe('');
@@ -101,6 +123,11 @@ describe('template_utils', () => {
             const result = extractTemplateFieldNames('{SEQ}-{OWNER}-{CREATOR}');
             expect(result).toEqual([]);
         });
+
+        it('should extract field names from filters and conditions', () => {
+            const result = extractTemplateFieldNames('{#if Severity == SEV1}{Project|upper}{else}{TEAM}{/if}');
+            expect(result).toEqual(['Severity', 'Project']);
+        });
     });
 
     describe('resolveTemplatePreview', () => {
@@ -209,6 +236,58 @@ describe('template_utils', () => {
             const result = resolveTemplatePreview('{project}', fields, {'field-1': 'Acme'});
             expect(result).toBe('Acme');
         });
+
+        it('should apply upper and lower filters', () => {
+            const result = resolveTemplatePreview('{Project|upper}-{Region | lower}', fields, {'field-1': 'Acme', 'field-2': 'opt-us'});
+            expect(result).toBe('ACME-us');
+        });
+
+        it('should use the default filter for empty values', () => {
+            const result = resolveTemplatePreview('{Project|default:"global"}', fields, {});
+            expect(result).toBe('global');
+        });
+
+        it('should format dates with a Go layout', () => {
+            const result = resolveTemplatePreview('{DueDate|date:"Jan 2, 2006"}', fields, {'field-6': '2024-06-15T00:00:00.000Z'});
+            expect(result).toBe('Jun 15, 2024');
+        });
+
+        it('should read unknown filters as part of the name', () => {
+            const result = resolveTemplatePreview('{Project|shout}', fields, {'field-1': 'Acme'});
+            expect(result).toBe('{Project|shout}');
+        });
+
+        it('should keep the section matching a select option', () => {
+            const template = '{#if Region == us}domestic{else}international{/if}';
+            expect(resolveTemplatePreview(template, fields, {'field-2': 'opt-us'})).toBe('domestic');
+            expect(resolveTemplatePreview(template, fields, {'field-2': 'opt-eu'})).toBe('international');
+        });
+
+        it('should match one of several values', () => {
+            const result = resolveTemplatePreview('{#if Tags != "beta"}no beta{else}beta{/if}', fields, {'field-3': ['tag-a', 'tag-b']});
+            expect(result).toBe('beta');
+        });
+
+        it('should treat missing values as empty in conditions', () => {
+            const result = resolveTemplatePreview('{#if Project}{Project}{else}untitled{/if}{#if !Tags} (untagged){/if}', fields, {});
+            expect(result).toBe('untitled (untagged)');
+        });
+
+        it('should keep unclosed sections as written', () => {
+            const result = resolveTemplatePreview('{#if Project}x', fields, {'field-1': 'Acme'});
+            expect(result).toBe('{#if Project}x');
+        });
+
+        it('should resolve DATE to the current date', () => {
+            const result = resolveTemplatePreview('{DATE}', fields, {});
+            expect(result).toBe(new Date().toISOString().split('T')[0]);
+        });
+
+        it('should prefer a field named like TEAM over the token', () => {
+            const withTeam = [...fields, {id: 'field-7', name: 'Team', type: 'text', group_id: 'g1', attrs: baseAttrs}] as PropertyField[];
+            const result = resolveTemplatePreview('{TEAM}', withTeam, {'field-7': 'Payments'}, {TEAM: 'Engineering'});
+            expect(result).toBe('Payments');
+        });
     });
 
     describe('buildTemplatePreview', () => {
@@ -261,6 +340,11 @@ describe('template_utils', () => {
             expect(result).toBe('Acme');
         });
 
+        it('should resolve TEAM and PLAYBOOK when provided', () => {
+            const result = buildTemplatePreview('{TEAM} - {PLAYBOOK|upper}', fields, {}, {teamName: 'Eng', playbookTitle: 'Incident'});
+            expect(result).toBe('Eng - INCIDENT');
+        });
+
         it('should handle complex template with multiple tokens', () => {
             const userMap = {'user-1': 'Alice'};
             const result = buildTemplatePreview('{SEQ}-{Project}-{OWNER}', fields, {'field-1': 'Acme'}, {prefix: 'RUN', userMap, ownerUserId: 'user-1', nextRunNumber: 7});
@@ -1,21 +1,40 @@
 // Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.txt for license information.
 
-// KEEP IN SYNC with server/app/template_engine.go (same token syntax, system tokens, and per-type formatting rules).
+// KEEP IN SYNC with server/app/template_engine.go (same token syntax, filters, sections, system tokens, and per-type formatting rules).
 
 import {PropertyField, PropertyFieldType} from 'src/types/properties';
 
 // System token names recognized by the template engine (case-insensitive)
-export const SYSTEM_TOKENS = new Set(['SEQ', 'OWNER', 'CREATOR']);
+export const SYSTEM_TOKENS = new Set(['SEQ', 'OWNER', 'CREATOR', 'TEAM', 'PLAYBOOK', 'DATE', 'PROGRESS', 'ELAPSED']);
+
+// Tokens added after fields could already be named like them: a field of the same name takes precedence.
+const CONTEXT_TOKENS = new Set(['TEAM', 'PLAYBOOK', 'DATE', 'PROGRESS', 'ELAPSED']);
 
 const DEFAULT_OWNER_TOKEN = "Owner's name";
 const DEFAULT_CREATOR_TOKEN = "Creator's name";
+const DEFAULT_TEAM_TOKEN = 'Team name';
+const DEFAULT_PLAYBOOK_TOKEN = 'Playbook name';
+const DEFAULT_PROGRESS_TOKEN = 'X/Y';
+const DEFAULT_ELAPSED_TOKEN = 'Xh Ym';
 
 // Minimum millisecond epoch value used to distinguish epoch-ms timestamps from
 // compact YYYYMMDD integers (e.g. 20250101). Values below this threshold are
 // treated as non-epoch integers. 1e12 ms ≈ Sep 9, 2001.
 const EPOCH_MS_MIN = 1_000_000_000_000;
 
+// Layout of the date filter without argument, and of DATE (Go layout syntax).
+const DEFAULT_DATE_LAYOUT = '2006-01-02';
+
+// Filters accepted by placeholders, mapped to whether they require an argument.
+const TEMPLATE_FILTERS: Record<string, boolean> = {
+    upper: false,
+    lower: false,
+    trim: false,
+    default: true,
+    date: false,
+};
+
 /**
  * Mirrors server/app.FormatSequentialID.
  * Returns empty string for runNumber === 0 (standalone / pre-feature runs).
@@ -28,17 +47,169 @@ export function formatSequentialID(prefix: string, runNumber: number): string {
     return prefix ? `${prefix}-${padded}` : padded;
 }
 
-// Extract field names referenced in a template (excluding system tokens)
-export function extractTemplateFieldNames(template: string): string[] {
-    const names: string[] = [];
+type TemplateFilter = {name: string; arg: string};
+
+type TemplateCondition = {negate: boolean; op: '' | '==' | '!='; value: string};
+
+type TemplateNode =
+    | {kind: 'text'; raw: string}
+    | {kind: 'invalid'; raw: string; name: string}
+    | {kind: 'placeholder'; raw: string; name: string; filters: TemplateFilter[]}
+    | {kind: 'section'; raw: string; name: string; cond: TemplateCondition; then: TemplateNode[]; otherwise: TemplateNode[]; elseRaw: string; unclosed: boolean};
+
+type SectionNode = Extract<TemplateNode, {kind: 'section'}>;
+
+function unquote(value: string): string {
+    if (value.length >= 2 && value.startsWith('"') && value.endsWith('"')) {
+        return value.slice(1, -1);
+    }
+    return value;
+}
+
+// Splits a placeholder on the pipes outside double quotes.
+function splitFilters(inner: string): string[] {
+    const parts: string[] = [];
+    let quoted = false;
+    let start = 0;
+    for (let i = 0; i < inner.length; i++) {
+        if (inner[i] === '"') {
+            quoted = !quoted;
+        } else if (inner[i] === '|' && !quoted) {
+            parts.push(inner.slice(start, i));
+            start = i + 1;
+        }
+    }
+    parts.push(inner.slice(start));
+    return parts;
+}
+
+// Placeholders whose filters cannot be parsed are read as a plain name, as before filters existed.
+function parsePlaceholder(raw: string, inner: string): TemplateNode {
+    const parts = splitFilters(inner);
+    if (parts.length === 1) {
+        return {kind: 'placeholder', raw, name: inner, filters: []};
+    }
+    const filters: TemplateFilter[] = [];
+    for (const part of parts.slice(1)) {
+        const trimmed = part.trim();
+        const colon = trimmed.indexOf(':');
+        const name = (colon >= 0 ? trimmed.slice(0, colon) : trimmed).trim().toLowerCase();
+        if (!(name in TEMPLATE_FILTERS) || (TEMPLATE_FILTERS[name] && colon < 0)) {
+            return {kind: 'placeholder', raw, name: inner, filters: []};
+        }
+        filters.push({name, arg: colon >= 0 ? unquote(trimmed.slice(colon + 1).trim()) : ''});
+    }
+    return {kind: 'placeholder', raw, name: parts[0].trim(), filters};
+}
+
+// Parses the condition of a section: Name, !Name, Name == value or Name != value.
+function parseCondition(expr: string): {name: string; cond: TemplateCondition} | null {
+    let name = expr.trim();
+    const cond: TemplateCondition = {negate: false, op: '', value: ''};
+
+    if (name.startsWith('!') && !name.includes('=')) {
+        cond.negate = true;
+        name = name.slice(1).trim();
+    } else {
+        const i = name.search(/[=!]/);
+        if (i >= 0) {
+            const rest = name.slice(i);
+            if (rest.startsWith('==')) {
+                cond.op = '==';
+            } else if (rest.startsWith('!=')) {
+                cond.op = '!=';
+            } else {
+                return null;
+            }
+            cond.value = unquote(rest.slice(2).trim());
+            name = name.slice(0, i).trim();
+        }
+    }
+
+    return name ? {name, cond} : null;
+}
+
+// Splits a template into nodes, nesting the content of {#if}...{else}...{/if} sections.
+function parseTemplate(template: string): TemplateNode[] {
+    const root: TemplateNode[] = [];
+    const stack: {section: SectionNode; inElse: boolean}[] = [];
+
+    const appendNode = (node: TemplateNode) => {
+        const top = stack[stack.length - 1];
+        if (!top) {
+            root.push(node);
+        } else if (top.inElse) {
+            top.section.otherwise.push(node);
+        } else {
+            top.section.then.push(node);
+        }
+    };
+
     const re = /\{([^}]+)\}/g;
+    let last = 0;
     let match;
     while ((match = re.exec(template)) !== null) {
-        const name = match[1].trim();
-        if (!SYSTEM_TOKENS.has(name.toUpperCase())) {
-            names.push(name);
+        if (match.index > last) {
+            appendNode({kind: 'text', raw: template.slice(last, match.index)});
         }
+        last = match.index + match[0].length;
+
+        const raw = match[0];
+        const inner = match[1].trim();
+        const lower = inner.toLowerCase();
+        const top = stack[stack.length - 1];
+
+        if (lower === '#if' || lower.startsWith('#if ')) {
+            const parsed = parseCondition(inner.slice(3));
+            if (parsed) {
+                stack.push({section: {kind: 'section', raw, name: parsed.name, cond: parsed.cond, then: [], otherwise: [], elseRaw: '', unclosed: false}, inElse: false});
+            } else {
+                appendNode({kind: 'invalid', raw, name: inner});
+            }
+        } else if (lower === 'else' && top && !top.inElse) {
+            top.inElse = true;
+            top.section.elseRaw = raw;
+        } else if (lower === '/if') {
+            if (top) {
+                stack.pop();
+                appendNode(top.section);
+            } else {
+                appendNode({kind: 'invalid', raw, name: inner});
+            }
+        } else {
+            appendNode(parsePlaceholder(raw, inner));
+        }
+    }
+    if (last < template.length) {
+        appendNode({kind: 'text', raw: template.slice(last)});
+    }
+
+    for (let top = stack.pop(); top; top = stack.pop()) {
+        top.section.unclosed = true;
+        appendNode(top.section);
     }
+
+    return root;
+}
+
+function walkTemplate(nodes: TemplateNode[], fn: (node: TemplateNode) => void) {
+    for (const node of nodes) {
+        fn(node);
+        if (node.kind === 'section') {
+            walkTemplate(node.then, fn);
+            walkTemplate(node.otherwise, fn);
+        }
+    }
+}
+
+// Extract field names referenced in a template, by placeholders and conditions (excluding system tokens)
+export function extractTemplateFieldNames(template: string): string[] {
+    const names: string[] = [];
+    walkTemplate(parseTemplate(template), (node) => {
+        if ((node.kind === 'placeholder' || (node.kind === 'section' && !node.unclosed)) && !SYSTEM_TOKENS.has(node.name.toUpperCase())) {
+            names.push(node.name);
+        }
+    });
     return names;
 }
 
@@ -50,6 +221,8 @@ export type BuildTemplatePreviewOptions = {
     nextRunNumber?: number;
     ownerFallback?: string;
     creatorFallback?: string;
+    teamName?: string;
+    playbookTitle?: string;
 };
 
 // Build a template preview with standard system token substitution.
@@ -58,13 +231,14 @@ export type BuildTemplatePreviewOptions = {
 // OWNER and CREATOR resolve to the display name from userMap when ownerUserId/creatorUserId are
 // provided; otherwise they fall back to ownerFallback / creatorFallback (callers should pass
 // a localized string from formatMessage; defaults to English for non-React contexts).
+// TEAM and PLAYBOOK resolve to teamName / playbookTitle when provided.
 export function buildTemplatePreview(
     template: string,
     fields: PropertyField[],
     values: Record<string, unknown>,
     options: BuildTemplatePreviewOptions = {},
 ): string {
-    const {prefix, userMap, ownerUserId, creatorUserId, nextRunNumber, ownerFallback, creatorFallback} = options;
+    const {prefix, userMap, ownerUserId, creatorUserId, nextRunNumber, ownerFallback, creatorFallback, teamName, playbookTitle} = options;
     const ownerName = (ownerUserId && userMap?.[ownerUserId]) || ownerFallback || DEFAULT_OWNER_TOKEN;
     const creatorName = (creatorUserId && userMap?.[creatorUserId]) || creatorFallback || DEFAULT_CREATOR_TOKEN;
     let seqValue: string;
@@ -73,19 +247,209 @@ export function buildTemplatePreview(
     } else {
         seqValue = formatSequentialID(prefix ?? '', nextRunNumber);
     }
-    return resolveTemplatePreview(template, fields, values, {
+    const tokens: Record<string, string> = {
         SEQ: seqValue,
         OWNER: ownerName,
         CREATOR: creatorName,
-    }, userMap);
+    };
+    if (teamName) {
+        tokens.TEAM = teamName;
+    }
+    if (playbookTitle) {
+        tokens.PLAYBOOK = playbookTitle;
+    }
+    return resolveTemplatePreview(template, fields, values, tokens, userMap);
+}
+
+// A resolved placeholder or condition value. Empty text means no value; token marks system tokens,
+// which render even when empty.
+type TemplateValue = {text: string; items: string[]; date?: Date; token?: boolean};
+
+const NO_VALUE: TemplateValue = {text: '', items: []};
+
+function formatDateValue(val: string | number): {text: string; date?: Date} {
+    if (typeof val === 'number') {
+        if (val >= EPOCH_MS_MIN) {
+            const date = new Date(val);
+            return {text: date.toISOString().split('T')[0], date};
+        }
+        return {text: String(val)};
+    }
+
+    const epoch = parseInt(val, 10);
+
+    // Require > 1e12 to distinguish epoch-ms values from compact YYYYMMDD
+    // integers (e.g. parseInt('20250101') === 20250101 which is > 0 but
+    // represents 1970-01-01 as a timestamp, not 2025-01-01).
+    if (!isNaN(epoch) && epoch >= EPOCH_MS_MIN) {
+        const date = new Date(epoch);
+        return {text: date.toISOString().split('T')[0], date};
+    }
+
+    // Treat bare YYYY-MM-DD strings as UTC to avoid timezone-offset date shifts.
+    const isoDate = (/^\d{4}-\d{2}-\d{2}$/).test(val) ? val + 'T00:00:00Z' : val;
+    const d = new Date(isoDate);
+    if (!isNaN(d.getTime())) {
+        // Extract the date portion directly from the original string to avoid UTC conversion shifting the date
+        const dateMatch = val.match(/^(\d{4}-\d{2}-\d{2})/);
+        return {text: dateMatch ? dateMatch[1] : d.toISOString().split('T')[0], date: d};
+    }
+    return {text: val}; // unknown format — return raw value as-is
+}
+
+function formatFieldValue(field: PropertyField, val: unknown, userMap?: Record<string, string>): TemplateValue {
+    if (val === undefined || val === null || val === '') {
+        return NO_VALUE;
+    }
+    const optionName = (v: string) => field.attrs?.options?.find((o) => o.id === v)?.name ?? v;
+
+    if (field.type === PropertyFieldType.Select && typeof val === 'string' && field.attrs?.options) {
+        return {text: optionName(val), items: []};
+    }
+    if (field.type === PropertyFieldType.Multiselect && Array.isArray(val) && field.attrs?.options) {
+        const items = val.map((v: string) => optionName(v));
+        return {text: items.join(', '), items};
+    }
+    if (field.type === PropertyFieldType.User && typeof val === 'string' && val) {
+        return {text: userMap?.[val] ?? val, items: []};
+    }
+    if (field.type === PropertyFieldType.Multiuser && Array.isArray(val)) {
+        const items = val.map((v: string) => userMap?.[v] ?? v);
+        return {text: items.join(', '), items};
+    }
+    if (field.type === PropertyFieldType.Date && ((typeof val === 'string' && val) || typeof val === 'number')) {
+        return {...formatDateValue(val as string | number), items: []};
+    }
+    return {text: String(val), items: []};
+}
+
+// Go reference-time elements supported by the date filter, longest first.
+const GO_LAYOUT_ELEMENTS = ['January', 'Monday', '2006', 'Jan', 'Mon', 'MST', '01', '02', '_2', '06', '15', '03', '04', '05', 'PM', 'pm', '1', '2', '3', '4', '5'];
+const MONTH_NAMES = ['January', 'February', 'March', 'April', 'May', 'June', 'July', 'August', 'September', 'October', 'November', 'December'];
+const DAY_NAMES = ['Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
+
+// Formats a date in UTC with a Go layout, as the server does.
+export function formatGoLayout(date: Date, layout: string): string {
+    const pad = (n: number) => String(n).padStart(2, '0');
+    const hour12 = date.getUTCHours() % 12 || 12;
+    const element = (el: string): string => {
+        switch (el) {
+        case 'January': return MONTH_NAMES[date.getUTCMonth()];
+        case 'Jan': return MONTH_NAMES[date.getUTCMonth()].slice(0, 3);
+        case 'Monday': return DAY_NAMES[date.getUTCDay()];
+        case 'Mon': return DAY_NAMES[date.getUTCDay()].slice(0, 3);
+        case '2006': return String(date.getUTCFullYear());
+        case '06': return pad(date.getUTCFullYear() % 100);
+        case '01': return pad(date.getUTCMonth() + 1);
+        case '1': return String(date.getUTCMonth() + 1);
+        case '02': return pad(date.getUTCDate());
+        case '_2': return String(date.getUTCDate()).padStart(2, ' ');
+        case '2': return String(date.getUTCDate());
+        case '15': return pad(date.getUTCHours());
+        case '03': return pad(hour12);
+        case '3': return String(hour12);
+        case '04': return pad(date.getUTCMinutes());
+        case '4': return String(date.getUTCMinutes());
+        case '05': return pad(date.getUTCSeconds());
+        case '5': return String(date.getUTCSeconds());
+        case 'PM': return date.getUTCHours() < 12 ? 'AM' : 'PM';
+        case 'pm': return date.getUTCHours() < 12 ? 'am' : 'pm';
+        case 'MST': return 'UTC';
+        default: return el;
+        }
+    };
+
+    let result = '';
+    let i = 0;
+    while (i < layout.length) {
+        const el = GO_LAYOUT_ELEMENTS.find((e) => layout.startsWith(e, i));
+        if (el) {
+            result += element(el);
+            i += el.length;
+        } else {
+            result += layout[i];
+            i++;
+        }
+    }
+    return result;
+}
+
+function parseTemplateDate(text: string): Date | undefined {
+    const trimmed = text.trim();
+    if ((/^\d+$/).test(trimmed)) {
+        const ms = parseInt(trimmed, 10);
+        return ms > EPOCH_MS_MIN ? new Date(ms) : undefined;
+    }
+    const isoDate = (/^\d{4}-\d{2}-\d{2}$/).test(trimmed) ? trimmed + 'T00:00:00Z' : trimmed;
+    if (!(/^\d{4}-\d{2}-\d{2}/).test(isoDate)) {
+        return undefined;
+    }
+    const d = new Date(isoDate);
+    return isNaN(d.getTime()) ? undefined : d;
+}
+
+function applyFilter(filter: TemplateFilter, text: string, value: TemplateValue): string {
+    switch (filter.name) {
+    case 'upper':
+        return text.toUpperCase();
+    case 'lower':
+        return text.toLowerCase();
+    case 'trim':
+        return text.trim();
+    case 'default':
+        return text === '' ? filter.arg : text;
+    case 'date': {
+        if (text === '') {
+            return text;
+        }
+        const date = value.date ?? parseTemplateDate(text);
+        return date ? formatGoLayout(date, filter.arg || DEFAULT_DATE_LAYOUT) : text;
+    }
+    }
+    return text;
+}
+
+function conditionMatches(cond: TemplateCondition, value: TemplateValue): boolean {
+    const equals = () => {
+        if (value.text === '') {
+            return false;
+        }
+        const expected = cond.value.trim().toLowerCase();
+        return value.text.trim().toLowerCase() === expected || value.items.some((item) => item.trim().toLowerCase() === expected);
+    };
+    switch (cond.op) {
+    case '==':
+        return equals();
+    case '!=':
+        return !equals();
+    }
+    return (value.text !== '') !== cond.negate;
+}
+
+// Render a duration for the ELAPSED token with its two most significant units,
+// e.g. "2d 3h", "3h 20m" or "5m". Mirrors formatElapsed on the server.
+export function formatElapsed(durationMs: number): string {
+    const minutes = Math.floor(Math.max(durationMs, 0) / 60000);
+    const days = Math.floor(minutes / (24 * 60));
+    const hours = Math.floor(minutes / 60) % 24;
+    const mins = minutes % 60;
+    if (days > 0) {
+        return `${days}d ${hours}h`;
+    }
+    if (hours > 0) {
+        return `${hours}h ${mins}m`;
+    }
+    return `${mins}m`;
 }
 
 // Resolve a template string client-side for preview purposes.
-// System tokens: {SEQ} → "N", {OWNER} → ownerFallback, {CREATOR} → creatorFallback.
+// System tokens: {SEQ} → "N", {OWNER} → ownerFallback, {CREATOR} → creatorFallback,
+// {TEAM}/{PLAYBOOK}/{PROGRESS}/{ELAPSED} → placeholders, {DATE} → today (UTC).
 // Custom systemTokens parameter allows callers to provide display values (takes precedence).
 // ownerFallback / creatorFallback should be a localized string from formatMessage when called
 // from React; defaults to English for non-React contexts.
 // userMap maps user IDs to display names for resolving user/multiuser field values.
+// Filters and {#if} sections are applied as on the server; unresolved placeholders are left as-is.
 export function resolveTemplatePreview(
     template: string,
     fields: PropertyField[],
@@ -103,70 +467,52 @@ export function resolveTemplatePreview(
         SEQ: 'N',
         OWNER: DEFAULT_OWNER_TOKEN,
         CREATOR: DEFAULT_CREATOR_TOKEN,
+        TEAM: DEFAULT_TEAM_TOKEN,
+        PLAYBOOK: DEFAULT_PLAYBOOK_TOKEN,
+        DATE: new Date().toISOString().split('T')[0],
+        PROGRESS: DEFAULT_PROGRESS_TOKEN,
+        ELAPSED: DEFAULT_ELAPSED_TOKEN,
         ...normalizedSystemTokens,
     };
 
     const fieldByName = new Map(fields.map((f) => [f.name.toLowerCase(), f]));
 
-    return template.replace(/\{([^}]+)\}/g, (match, inner: string) => {
-        const name = inner.trim();
+    // Returns null for names that are neither a token nor a field.
+    const lookup = (name: string): TemplateValue | null => {
         const upper = name.toUpperCase();
-
-        // System tokens
-        if (upper in defaultSystemTokens) {
-            return defaultSystemTokens[upper];
-        }
-
         const field = fieldByName.get(name.toLowerCase());
-        if (!field) {
-            return match;
-        }
-        const val = values[field.id];
-        if (val === undefined || val === null || val === '') {
-            return match;
-        }
-        if (field.type === PropertyFieldType.Select && typeof val === 'string' && field.attrs?.options) {
-            const opt = field.attrs?.options.find((o) => o.id === val);
-            return opt?.name ?? val;
+        if (upper in defaultSystemTokens && !(field && CONTEXT_TOKENS.has(upper))) {
+            return {text: defaultSystemTokens[upper], items: [], token: true};
         }
-        if (field.type === PropertyFieldType.Multiselect && Array.isArray(val) && field.attrs?.options) {
-            return val.map((v: string) => {
-                const opt = field.attrs?.options?.find((o) => o.id === v);
-                return opt?.name ?? v;
-            }).join(', ');
-        }
-        if (field.type === PropertyFieldType.User && typeof val === 'string' && val) {
-            return userMap?.[val] ?? val;
-        }
-        if (field.type === PropertyFieldType.Multiuser && Array.isArray(val)) {
-            return val.map((v: string) => userMap?.[v] ?? v).join(', ');
+        if (!field) {
+            return null;
         }
-        if (field.type === PropertyFieldType.Date && typeof val === 'string' && val) {
-            const epoch = parseInt(val, 10);
-
-            // Require > 1e12 to distinguish epoch-ms values from compact YYYYMMDD
-            // integers (e.g. parseInt('20250101') === 20250101 which is > 0 but
-            // represents 1970-01-01 as a timestamp, not 2025-01-01).
-            if (!isNaN(epoch) && epoch >= EPOCH_MS_MIN) {
-                return new Date(epoch).toISOString().split('T')[0];
-            }
+        return formatFieldValue(field, values[field.id], userMap);
+    };
 
-            // Treat bare YYYY-MM-DD strings as UTC to avoid timezone-offset date shifts.
-            const isoDate = (/^\d{4}-\d{2}-\d{2}$/).test(val) ? val + 'T00:00:00Z' : val;
-            const d = new Date(isoDate);
-            if (!isNaN(d.getTime())) {
-                // Extract the date portion directly from the original string to avoid UTC conversion shifting the date
-                const dateMatch = val.match(/^(\d{4}-\d{2}-\d{2})/);
-                return dateMatch ? dateMatch[1] : d.toISOString().split('T')[0];
+    const render = (nodes: TemplateNode[]): string => nodes.map((node) => {
+        switch (node.kind) {
+        case 'text':
+        case 'invalid':
+            return node.raw;
+        case 'placeholder': {
+            const value = lookup(node.name);
+            if (!value) {
+                return node.raw;
             }
-            return val; // unknown format — return raw value as-is
+            const text = node.filters.reduce((acc, filter) => applyFilter(filter, acc, value), value.text);
+            return text === '' && !value.token ? node.raw : text;
         }
-        if (field.type === PropertyFieldType.Date && typeof val === 'number') {
-            if (val >= EPOCH_MS_MIN) {
-                return new Date(val).toISOString().split('T')[0];
+        case 'section': {
+            if (node.unclosed) {
+                return node.raw + render(node.then) + node.elseRaw + render(node.otherwise);
             }
-            return String(val);
+            const value = lookup(node.name) ?? NO_VALUE;
+            return conditionMatches(node.cond, value) ? render(node.then) : render(node.otherwise);
         }
-        return String(val);
-    });
+        }
+        return '';
+    }).join('');
+
+    return render(parseTemplate(template));
 }